@@ -300,6 +300,20 @@ bool MVCCIsValidSplitKey(DBSlice key);
 DBStatus MVCCFindSplitKey(DBIterator* iter, DBKey start, DBKey end, DBKey min_split,
                           int64_t target_size, DBString* split_key);
 
+// DBIgnoredSeqNumRange is a range of sequence numbers of a transaction
+// whose writes have been rolled back. Both ends of the range are
+// inclusive.
+typedef struct {
+  int32_t start_seqnum;
+  int32_t end_seqnum;
+} DBIgnoredSeqNumRange;
+
+typedef struct {
+  // ranges are sorted and non-overlapping.
+  DBIgnoredSeqNumRange* ranges;
+  int len;
+} DBIgnoredSeqNums;
+
 // DBTxn contains the fields from a roachpb.Transaction that are
 // necessary for MVCC Get and Scan operations. Note that passing a
 // serialized roachpb.Transaction appears to be a non-starter as an
@@ -313,6 +327,7 @@ typedef struct {
   uint32_t epoch;
   int32_t sequence;
   DBTimestamp max_timestamp;
+  DBIgnoredSeqNums ignored_seqnums;
 } DBTxn;
 
 typedef struct {
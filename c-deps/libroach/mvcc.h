@@ -61,6 +61,7 @@ template <bool reverse> class mvccScanner {
         txn_epoch_(txn.epoch),
         txn_sequence_(txn.sequence),
         txn_max_timestamp_(txn.max_timestamp),
+        txn_ignored_seqnums_(txn.ignored_seqnums),
         inconsistent_(inconsistent),
         tombstones_(tombstones),
         ignore_sequence_(ignore_sequence),
@@ -166,9 +167,25 @@ template <bool reverse> class mvccScanner {
     return results_;
   }
 
+  // isIgnoredSeq returns whether the given sequence number of our txn has
+  // been rolled back, in which case values written at it must not be read.
+  bool isIgnoredSeq(const int32_t seq) const {
+    for (int i = 0; i < txn_ignored_seqnums_.len; i++) {
+      const DBIgnoredSeqNumRange& r = txn_ignored_seqnums_.ranges[i];
+      if (seq < r.start_seqnum) {
+        // The ranges are sorted, so none of the remaining ones can match.
+        return false;
+      }
+      if (seq <= r.end_seqnum) {
+        return true;
+      }
+    }
+    return false;
+  }
+
   bool getFromIntentHistory() {
     cockroach::storage::engine::enginepb::MVCCMetadata_SequencedIntent readIntent;
-    readIntent.set_sequence(txn_sequence_);
+    readIntent.set_sequence(ignore_sequence_ ? INT32_MAX : txn_sequence_);
     // Look for the intent with the sequence number less than or equal to the
     // read sequence. To do so, search using upper_bound, which returns an
     // iterator pointing to the first element in the range [first, last) that is
@@ -180,18 +197,22 @@ template <bool reverse> class mvccScanner {
            const cockroach::storage::engine::enginepb::MVCCMetadata_SequencedIntent& b) -> bool {
           return a.sequence() < b.sequence();
         });
-    if (up == meta_.intent_history().begin()) {
-      // It is possible that no intent exists such that the sequence is less
-      // than the read sequence. In this case, we cannot read a value from the
-      // intent history.
-      return false;
-    }
-    const auto intent = *(up - 1);
-    rocksdb::Slice value = intent.value();
-    if (value.size() > 0 || tombstones_) {
-      kvs_->Put(cur_raw_key_, value);
+    // Skip over the values written at sequence numbers that were rolled back.
+    while (up != meta_.intent_history().begin()) {
+      const auto intent = *(--up);
+      if (isIgnoredSeq(intent.sequence())) {
+        continue;
+      }
+      rocksdb::Slice value = intent.value();
+      if (value.size() > 0 || tombstones_) {
+        kvs_->Put(cur_raw_key_, value);
+      }
+      return true;
     }
-    return true;
+    // It is possible that no intent exists such that the sequence is less
+    // than the read sequence. In this case, we cannot read a value from the
+    // intent history.
+    return false;
   }
 
   bool uncertaintyError(DBTimestamp ts) {
@@ -293,7 +314,8 @@ template <bool reverse> class mvccScanner {
     }
 
     if (txn_epoch_ == meta_.txn().epoch()) {
-      if ((ignore_sequence_) || (txn_sequence_ >= meta_.txn().sequence())) {
+      if (((ignore_sequence_) || (txn_sequence_ >= meta_.txn().sequence())) &&
+          !isIgnoredSeq(meta_.txn().sequence())) {
         // 8. We're reading our own txn's intent at an equal or higher sequence.
         // Note that we read at the intent timestamp, not at our read timestamp
         // as the intent timestamp may have been pushed forward by another
@@ -306,13 +328,16 @@ template <bool reverse> class mvccScanner {
         // may not be earlier versions of the intent (with lower sequence
         // numbers) that we should read. If there exists a value in the intent
         // history that has a sequence number equal to or less than the read
-        // sequence, read that value.
+        // sequence, read that value. We also end up here if the intent was
+        // written at a sequence number that has since been rolled back, in
+        // which case we read the latest value in the intent history that was
+        // not rolled back.
         const bool found = getFromIntentHistory();
         if (found) {
           return advanceKey();
         }
         // 10. If no value in the intent history has a sequence number equal to
-        // or less than the read, we must ignore the intents laid down by the
+        // or less than the read (and was not rolled back), we must ignore the intents laid down by the
         // transaction all together. We ignore the intent by insisting that the
         // timestamp we're reading at is a historical timestamp < the intent
         // timestamp.
@@ -646,6 +671,7 @@ template <bool reverse> class mvccScanner {
   const uint32_t txn_epoch_;
   const int32_t txn_sequence_;
   const DBTimestamp txn_max_timestamp_;
+  const DBIgnoredSeqNums txn_ignored_seqnums_;
   const bool inconsistent_;
   const bool tombstones_;
   const bool ignore_sequence_;
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -47,6 +48,12 @@ import (
 // systems, especially when working with long histories of archived data across
 // many schema changes (such as a data lake).
 //
+// The value of a CREATE CHANGEFEED ... AS SELECT changefeed only contains the
+// columns rendered by the SELECT, so its Avro record is derived from those
+// instead: there is one field per rendered column, named after the column and
+// with the type of its expression. The key is still the primary key of the
+// table.
+//
 // One downside of the above is that it's not possible to recover the original
 // SQL table schema from an Avro one. (This is also true for other reasons, such
 // as lossy mappings from sql types to avro types.) To partially address this,
@@ -125,7 +132,7 @@ type avroMetadata map[string]interface{}
 // avroEnvelopeOpts controls which fields in avroEnvelopeRecord are set.
 type avroEnvelopeOpts struct {
 	updatedField, resolvedField bool
-	afterField                  bool
+	beforeField, afterField     bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -222,6 +229,15 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 			micros := x.(time.Duration) / time.Microsecond
 			return tree.MakeDTime(timeofday.TimeOfDay(micros)), nil
 		}
+	case types.TimeTZFamily:
+		// Avro has no logical type for a time with a time zone offset.
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DTimeTZ).TimeTZ.String(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTimeTZ(nil, x.(string))
+		}
 	case types.TimestampFamily:
 		avroType = avroLogicalType{
 			SchemaType:  avroSchemaLong,
@@ -297,6 +313,30 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDJSON(x.(string))
 		}
+	case types.GeometryFamily:
+		avroType = avroSchemaBytes
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DGeometry).EWKB(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			g, err := geo.ParseGeometryFromEWKB(x.([]byte))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDGeometry(g), nil
+		}
+	case types.GeographyFamily:
+		avroType = avroSchemaBytes
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DGeography).EWKB(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			g, err := geo.ParseGeographyFromEWKB(x.([]byte))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDGeography(g), nil
+		}
 	default:
 		return nil, errors.Errorf(`column %s: type %s not yet supported with avro`,
 			colDesc.Name, colDesc.Type.SQLString())
@@ -377,6 +417,8 @@ func indexToAvroSchema(
 
 // tableToAvroSchema converts a column descriptor into its corresponding avro
 // record schema. The fields are kept in the same order as `tableDesc.Columns`.
+// For a CREATE CHANGEFEED ... AS SELECT changefeed, it is called with the
+// descriptor of the rendered columns built by changefeedProjection.
 func tableToAvroSchema(tableDesc *sqlbase.TableDescriptor) (*avroDataRecord, error) {
 	schema := &avroDataRecord{
 		avroRecord: avroRecord{
@@ -524,6 +566,16 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, afterField)
 	}
+	if opts.beforeField {
+		// A named type can only be defined once in an avro schema, so refer to
+		// the record defined by the after field by name.
+		beforeField := &avroSchemaField{
+			Name:       `before`,
+			SchemaType: []avroSchemaType{avroSchemaNull, after.Name},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, beforeField)
+	}
 
 	schemaJSON, err := json.Marshal(schema)
 	if err != nil {
@@ -539,7 +591,7 @@ func envelopeToAvroSchema(
 // BinaryFromRow encodes the given metadata and row data into avro's defined
 // binary format.
 func (r *avroEnvelopeRecord) BinaryFromRow(
-	buf []byte, meta avroMetadata, row sqlbase.EncDatumRow,
+	buf []byte, meta avroMetadata, beforeRow, afterRow sqlbase.EncDatumRow,
 ) ([]byte, error) {
 	native := map[string]interface{}{
 		`after`: nil,
@@ -568,16 +620,27 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 	}
 	// WIP verify that meta is now empty
 	if r.opts.afterField {
-		if row == nil {
+		if afterRow == nil {
 			native[`after`] = nil
 		} else {
-			afterNative, err := r.after.nativeFromRow(row)
+			afterNative, err := r.after.nativeFromRow(afterRow)
 			if err != nil {
 				return nil, err
 			}
 			native[`after`] = goavro.Union(avroUnionKey(&r.after.avroRecord), afterNative)
 		}
 	}
+	if r.opts.beforeField {
+		if beforeRow == nil {
+			native[`before`] = nil
+		} else {
+			beforeNative, err := r.after.nativeFromRow(beforeRow)
+			if err != nil {
+				return nil, err
+			}
+			native[`before`] = goavro.Union(avroUnionKey(&r.after.avroRecord), beforeNative)
+		}
+	}
 	return r.codec.BinaryFromNative(buf, native)
 }
 
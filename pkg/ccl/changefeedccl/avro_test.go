@@ -292,11 +292,14 @@ func TestAvroSchema(t *testing.T) {
 			`BYTES`:        `["null","bytes"]`,
 			`DATE`:         `["null",{"type":"int","logicalType":"date"}]`,
 			`FLOAT8`:       `["null","double"]`,
+			`GEOGRAPHY`:    `["null","bytes"]`,
+			`GEOMETRY`:     `["null","bytes"]`,
 			`INET`:         `["null","string"]`,
 			`INT8`:         `["null","long"]`,
 			`JSONB`:        `["null","string"]`,
 			`STRING`:       `["null","string"]`,
 			`TIME`:         `["null",{"type":"long","logicalType":"time-micros"}]`,
+			`TIMETZ`:       `["null","string"]`,
 			`TIMESTAMP`:    `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
 			`TIMESTAMPTZ`:  `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
 			`UUID`:         `["null","string"]`,
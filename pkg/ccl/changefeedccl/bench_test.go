@@ -207,7 +207,7 @@ func createBenchmarkChangefeed(
 		targets:  details.Targets,
 		m:        th,
 	}
-	rowsFn := kvsToRows(s.LeaseManager().(*sql.LeaseManager), details, buf.Get)
+	rowsFn := kvsToRows(s.LeaseManager().(*sql.LeaseManager), nil /* evalCtx */, details, buf.Get)
 	tickFn := emitEntries(
 		s.ClusterSettings(), details, spans, encoder, sink, rowsFn, TestingKnobs{}, metrics)
 
@@ -26,8 +26,15 @@ import (
 )
 
 type bufferEntry struct {
-	kv       roachpb.KeyValue
+	kv roachpb.KeyValue
+	// prevVal is the value of kv.Key before kv.Value was written. It is only
+	// set if the changefeed was created with the diff option and is empty if
+	// there was no previous value or the kv is from a full scan.
+	prevVal  roachpb.Value
 	resolved *jobspb.ResolvedSpan
+	// stopErr, if non-nil, is returned by the changefeed once every entry
+	// before it has been emitted.
+	stopErr error
 	// Timestamp of the schema that should be used to read this KV.
 	// If unset (zero-valued), the value's timestamp will be used instead.
 	schemaTimestamp hlc.Timestamp
@@ -48,9 +55,9 @@ func makeBuffer() *buffer {
 // AddKV inserts a changed kv into the buffer. Individual keys must be added in
 // increasing mvcc order.
 func (b *buffer) AddKV(
-	ctx context.Context, kv roachpb.KeyValue, schemaTimestamp hlc.Timestamp,
+	ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, schemaTimestamp hlc.Timestamp,
 ) error {
-	return b.addEntry(ctx, bufferEntry{kv: kv, schemaTimestamp: schemaTimestamp})
+	return b.addEntry(ctx, bufferEntry{kv: kv, prevVal: prevVal, schemaTimestamp: schemaTimestamp})
 }
 
 // AddResolved inserts a resolved timestamp notification in the buffer.
@@ -58,6 +65,12 @@ func (b *buffer) AddResolved(ctx context.Context, span roachpb.Span, ts hlc.Time
 	return b.addEntry(ctx, bufferEntry{resolved: &jobspb.ResolvedSpan{Span: span, Timestamp: ts}})
 }
 
+// AddStop inserts an error in the buffer, which stops the changefeed after the
+// entries already in the buffer are emitted.
+func (b *buffer) AddStop(ctx context.Context, err error) error {
+	return b.addEntry(ctx, bufferEntry{stopErr: err})
+}
+
 func (b *buffer) addEntry(ctx context.Context, e bufferEntry) error {
 	select {
 	case <-ctx.Done():
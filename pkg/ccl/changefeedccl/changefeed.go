@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -61,6 +62,10 @@ type emitEntry struct {
 	// timestamp will be emitted.
 	resolved *jobspb.ResolvedSpan
 
+	// stopErr, if non-nil, is returned once every entry before it has been
+	// flushed to the sink.
+	stopErr error
+
 	// bufferGetTimestamp is the time this entry came out of the buffer.
 	bufferGetTimestamp time.Time
 }
@@ -70,15 +75,16 @@ type emitEntry struct {
 // The returned closure is not threadsafe.
 func kvsToRows(
 	leaseMgr *sql.LeaseManager,
+	evalCtx *tree.EvalContext,
 	details jobspb.ChangefeedDetails,
 	inputFn func(context.Context) (bufferEntry, error),
 ) func(context.Context) ([]emitEntry, error) {
-	rfCache := newRowFetcherCache(leaseMgr)
+	rfCache := newRowFetcherCache(leaseMgr, evalCtx)
 
 	var kvs row.SpanKVFetcher
 	appendEmitEntryForKV := func(
-		ctx context.Context, output []emitEntry, kv roachpb.KeyValue, schemaTimestamp hlc.Timestamp,
-		bufferGetTimestamp time.Time,
+		ctx context.Context, output []emitEntry, kv roachpb.KeyValue, prevVal roachpb.Value,
+		schemaTimestamp hlc.Timestamp, bufferGetTimestamp time.Time,
 	) ([]emitEntry, error) {
 		// Reuse kvs to save allocations.
 		kvs.KVs = kvs.KVs[:0]
@@ -120,6 +126,25 @@ func kvsToRows(
 			r.row.updated = schemaTimestamp
 			output = append(output, r)
 		}
+
+		// A tombstone or a missing previous value both mean the row did not
+		// exist before this change.
+		if len(prevVal.RawBytes) == 0 || len(output) == 0 {
+			return output, nil
+		}
+		// The previous value is decoded with the same descriptor as the new
+		// one, so that both are rendered with the same columns.
+		kvs.KVs = append(kvs.KVs[:0], roachpb.KeyValue{Key: kv.Key, Value: prevVal})
+		if err := rf.StartScanFrom(ctx, &kvs); err != nil {
+			return nil, err
+		}
+		prevDatums, _, _, err := rf.NextRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if prevDatums != nil && !rf.RowIsDeleted() {
+			output[len(output)-1].row.prevDatums = append(sqlbase.EncDatumRow(nil), prevDatums...)
+		}
 		return output, nil
 	}
 
@@ -141,7 +166,7 @@ func kvsToRows(
 					schemaTimestamp = input.schemaTimestamp
 				}
 				output, err = appendEmitEntryForKV(
-					ctx, output, input.kv, schemaTimestamp, input.bufferGetTimestamp)
+					ctx, output, input.kv, input.prevVal, schemaTimestamp, input.bufferGetTimestamp)
 				if err != nil {
 					return nil, err
 				}
@@ -152,6 +177,12 @@ func kvsToRows(
 					bufferGetTimestamp: input.bufferGetTimestamp,
 				})
 			}
+			if input.stopErr != nil {
+				output = append(output, emitEntry{
+					stopErr:            input.stopErr,
+					bufferGetTimestamp: input.bufferGetTimestamp,
+				})
+			}
 			if output != nil {
 				return output, nil
 			}
@@ -210,8 +241,14 @@ func emitEntries(
 	var lastFlush time.Time
 	// TODO(dan): We could keep these in `watchedSF` to eliminate dups.
 	var resolvedSpans []jobspb.ResolvedSpan
+	// stopErr is returned by every call after the one that flushed the rows
+	// before it, so that those rows are handed out first.
+	var stopErr error
 
 	return func(ctx context.Context) ([]jobspb.ResolvedSpan, error) {
+		if stopErr != nil {
+			return nil, stopErr
+		}
 		inputs, err := inputFn(ctx)
 		if err != nil {
 			return nil, err
@@ -237,6 +274,9 @@ func emitEntries(
 				_ = watchedSF.Forward(input.resolved.Span, input.resolved.Timestamp)
 				resolvedSpans = append(resolvedSpans, *input.resolved)
 			}
+			if input.stopErr != nil {
+				stopErr = input.stopErr
+			}
 		}
 
 		// If the resolved timestamp frequency is specified, use it as a rough
@@ -264,7 +304,8 @@ func emitEntries(
 		} else {
 			timeBetweenFlushes = changefeedPollInterval.Get(&settings.SV) / 5
 		}
-		if len(resolvedSpans) == 0 || timeutil.Since(lastFlush) < timeBetweenFlushes {
+		if stopErr == nil &&
+			(len(resolvedSpans) == 0 || timeutil.Since(lastFlush) < timeBetweenFlushes) {
 			return nil, nil
 		}
 
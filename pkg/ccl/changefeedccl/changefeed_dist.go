@@ -13,6 +13,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
@@ -92,6 +93,19 @@ func distChangefeedFlow(
 	if err != nil {
 		return err
 	}
+	// The tables in watched databases are the ones that exist now, not as of
+	// the high-water mark. Tables created after it are then watched from the
+	// high-water mark on, which covers all of their rows. A table created or
+	// dropped while the flow runs restarts the changefeed to pick up the
+	// change.
+	var databaseSpans []roachpb.Span
+	details.Targets, databaseSpans, err = expandDatabaseTargets(
+		ctx, execCfg.DB, details.Targets, execCfg.Clock.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	trackedSpans = append(trackedSpans, databaseSpans...)
 
 	// Changefeed flows handle transactional consistency themselves.
 	var noTxn *client.Txn
@@ -52,6 +52,9 @@ type changeAggregator struct {
 
 	// encoder is the Encoder to use for key and value serialization.
 	encoder Encoder
+	// projection, if non-nil, projects and filters the changed rows of a
+	// CREATE CHANGEFEED ... AS SELECT changefeed before they are encoded.
+	projection *changefeedProjection
 	// sink is the Sink to write rows to. Resolved timestamps are never written
 	// by changeAggregator.
 	sink Sink
@@ -106,6 +109,13 @@ func newChangeAggregatorProcessor(
 	if ca.encoder, err = getEncoder(ca.spec.Feed.Opts); err != nil {
 		return nil, err
 	}
+	if ca.spec.Feed.Select != `` {
+		if ca.projection, err = newChangefeedProjection(
+			ca.spec.Feed.Select, flowCtx.NewEvalCtx(),
+		); err != nil {
+			return nil, err
+		}
+	}
 
 	return ca, nil
 }
@@ -124,7 +134,7 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	nodeID := ca.flowCtx.EvalCtx.NodeID
 	var err error
 	if ca.sink, err = getSink(
-		ca.spec.Feed.SinkURI, nodeID, ca.spec.Feed.Opts, ca.spec.Feed.Targets, ca.flowCtx.Settings,
+		ctx, ca.spec.Feed.SinkURI, nodeID, ca.spec.Feed.Opts, ca.spec.Feed.Targets, ca.flowCtx.Settings,
 	); err != nil {
 		err = MarkRetryableError(err)
 		// Early abort in the case that there is an error creating the sink.
@@ -178,7 +188,10 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 		ca.flowCtx.Settings, ca.flowCtx.ClientDB, ca.flowCtx.ClientDB.Clock(), ca.flowCtx.Gossip,
 		spans, ca.spec.Feed, initialHighWater, buf, leaseMgr, metrics, ca.pollerMemMon,
 	)
-	rowsFn := kvsToRows(leaseMgr, ca.spec.Feed, buf.Get)
+	rowsFn := kvsToRows(leaseMgr, ca.flowCtx.NewEvalCtx(), ca.spec.Feed, buf.Get)
+	if ca.projection != nil {
+		rowsFn = ca.projection.rowsFn(rowsFn)
+	}
 
 	ca.tickFn = emitEntries(
 		ca.flowCtx.Settings, ca.spec.Feed, spans, ca.encoder, ca.sink, rowsFn, knobs, metrics)
@@ -427,7 +440,7 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 	nodeID := cf.flowCtx.EvalCtx.NodeID
 	var err error
 	if cf.sink, err = getSink(
-		cf.spec.Feed.SinkURI, nodeID, cf.spec.Feed.Opts, cf.spec.Feed.Targets, cf.flowCtx.Settings,
+		ctx, cf.spec.Feed.SinkURI, nodeID, cf.spec.Feed.Opts, cf.spec.Feed.Targets, cf.flowCtx.Settings,
 	); err != nil {
 		err = MarkRetryableError(err)
 		cf.MoveToDraining(err)
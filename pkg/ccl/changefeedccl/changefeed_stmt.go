@@ -46,14 +46,21 @@ func init() {
 
 type envelopeType string
 type formatType string
+type schemaChangeEventClass string
+type schemaChangePolicy string
 
 const (
 	optConfluentSchemaRegistry = `confluent_schema_registry`
 	optCursor                  = `cursor`
+	optDiff                    = `diff`
 	optEnvelope                = `envelope`
 	optFormat                  = `format`
+	optInitialScan             = `initial_scan`
 	optKeyInValue              = `key_in_value`
+	optNoInitialScan           = `no_initial_scan`
 	optResolvedTimestamps      = `resolved`
+	optSchemaChangeEvents      = `schema_change_events`
+	optSchemaChangePolicy      = `schema_change_policy`
 	optUpdatedTimestamps       = `updated`
 
 	optEnvelopeKeyOnly       envelopeType = `key_only`
@@ -64,6 +71,18 @@ const (
 	optFormatJSON formatType = `json`
 	optFormatAvro formatType = `experimental_avro`
 
+	// optSchemaChangeEventClassDefault is the set of schema changes that
+	// rewrite the table's data: a column backfill finishing.
+	optSchemaChangeEventClassDefault schemaChangeEventClass = `default`
+	// optSchemaChangeEventClassColumnChange is any change to the set of
+	// columns emitted by the changefeed, including the ones without a
+	// backfill, such as adding a nullable column.
+	optSchemaChangeEventClassColumnChange schemaChangeEventClass = `column_changes`
+
+	optSchemaChangePolicyBackfill   schemaChangePolicy = `backfill`
+	optSchemaChangePolicyNoBackfill schemaChangePolicy = `nobackfill`
+	optSchemaChangePolicyStop       schemaChangePolicy = `stop`
+
 	sinkParamCACert           = `ca_cert`
 	sinkParamFileSize         = `file_size`
 	sinkParamSchemaTopic      = `schema_topic`
@@ -76,15 +95,26 @@ const (
 	sinkParamSASLHandshake    = `sasl_handshake`
 	sinkParamSASLUser         = `sasl_user`
 	sinkParamSASLPassword     = `sasl_password`
+	sinkParamBatchSize        = `batch_size`
+	sinkParamClientCert       = `client_cert`
+	sinkParamClientKey        = `client_key`
+	sinkParamFlushInterval    = `flush_interval`
+	sinkParamHeaderPrefix     = `header_`
+	sinkSchemeWebhookHTTPS    = `webhook-https`
 )
 
 var changefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	optConfluentSchemaRegistry: sql.KVStringOptRequireValue,
 	optCursor:                  sql.KVStringOptRequireValue,
+	optDiff:                    sql.KVStringOptRequireNoValue,
 	optEnvelope:                sql.KVStringOptRequireValue,
 	optFormat:                  sql.KVStringOptRequireValue,
+	optInitialScan:             sql.KVStringOptRequireNoValue,
 	optKeyInValue:              sql.KVStringOptRequireNoValue,
+	optNoInitialScan:           sql.KVStringOptRequireNoValue,
 	optResolvedTimestamps:      sql.KVStringOptAny,
+	optSchemaChangeEvents:      sql.KVStringOptRequireValue,
+	optSchemaChangePolicy:      sql.KVStringOptRequireValue,
 	optUpdatedTimestamps:       sql.KVStringOptRequireNoValue,
 }
 
@@ -160,6 +190,13 @@ func changefeedPlanHook(
 		if err != nil {
 			return err
 		}
+		if _, ok := opts[optDiff]; ok {
+			if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionChangefeedDiff) {
+				return errors.Errorf(`CHANGEFEED option %s requires all nodes to be upgraded to %s`,
+					optDiff, cluster.VersionByKey(cluster.VersionChangefeedDiff),
+				)
+			}
+		}
 
 		jobDescription, err := changefeedJobDescription(p, changefeedStmt, sinkURI, opts)
 		if err != nil {
@@ -178,15 +215,53 @@ func changefeedPlanHook(
 			}
 			statementTime = initialHighWater
 		}
+		// By default, a changefeed starts with a scan of the targets at the
+		// statement time, unless it has a cursor. A zero initial high-water
+		// triggers the scan.
+		_, initialScan := opts[optInitialScan]
+		_, noInitialScan := opts[optNoInitialScan]
+		if initialScan && noInitialScan {
+			return errors.Errorf(
+				`cannot specify both %s and %s`, optInitialScan, optNoInitialScan)
+		}
+		if initialScan {
+			initialHighWater = hlc.Timestamp{}
+		} else if noInitialScan {
+			initialHighWater = statementTime
+		}
+
+		// A CREATE CHANGEFEED ... AS SELECT statement targets the table it
+		// selects from.
+		targetList := changefeedStmt.Targets
+		if changefeedStmt.Select != nil {
+			if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionChangefeedSelect) {
+				return errors.Errorf(
+					`CREATE CHANGEFEED ... AS SELECT requires all nodes to be upgraded to %s`,
+					cluster.VersionByKey(cluster.VersionChangefeedSelect),
+				)
+			}
+			_, tn, err := validateChangefeedSelect(changefeedStmt.Select)
+			if err != nil {
+				return err
+			}
+			targetList = tree.TargetList{Tables: tree.TablePatterns{tn}}
+		}
 
-		// For now, disallow targeting a database or wildcard table selection.
-		// Getting it right as tables enter and leave the set over time is
-		// tricky.
-		if len(changefeedStmt.Targets.Databases) > 0 {
-			return errors.Errorf(`CHANGEFEED cannot target %s`,
-				tree.AsString(&changefeedStmt.Targets))
+		if len(targetList.Databases) > 0 {
+			if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionChangefeedDatabaseTargets) {
+				return errors.Errorf(`CHANGEFEED FOR DATABASE requires all nodes to be upgraded to %s`,
+					cluster.VersionByKey(cluster.VersionChangefeedDatabaseTargets),
+				)
+			}
+			// The changefeed restarts to watch the tables created in the
+			// database, which sinkless changefeeds can't do.
+			if unspecifiedSink {
+				return errors.Errorf(`CHANGEFEED FOR DATABASE requires a sink`)
+			}
 		}
-		for _, t := range changefeedStmt.Targets.Tables {
+		// For now, disallow wildcard table selection. Getting it right as tables
+		// enter and leave the set over time is tricky.
+		for _, t := range targetList.Tables {
 			p, err := t.NormalizeTablePattern()
 			if err != nil {
 				return err
@@ -197,14 +272,29 @@ func changefeedPlanHook(
 		}
 
 		// This grabs table descriptors once to get their ids.
-		targetDescs, _, err := backupccl.ResolveTargetsToDescriptors(
-			ctx, p, statementTime, changefeedStmt.Targets)
+		targetDescs, expandedDBs, err := backupccl.ResolveTargetsToDescriptors(
+			ctx, p, statementTime, targetList)
 		if err != nil {
 			return err
 		}
 		targets := make(jobspb.ChangefeedTargets, len(targetDescs))
+		for _, desc := range targetDescs {
+			if dbDesc := desc.GetDatabase(); dbDesc != nil {
+				for _, id := range expandedDBs {
+					if id == dbDesc.ID {
+						targets[dbDesc.ID] = jobspb.ChangefeedTarget{
+							StatementTimeName: dbDesc.Name,
+							Database:          true,
+						}
+					}
+				}
+			}
+		}
 		for _, desc := range targetDescs {
 			if tableDesc := desc.GetTable(); tableDesc != nil {
+				if watchedByDatabase(targets, tableDesc) {
+					continue
+				}
 				targets[tableDesc.ID] = jobspb.ChangefeedTarget{
 					StatementTimeName: tableDesc.Name,
 				}
@@ -213,6 +303,14 @@ func changefeedPlanHook(
 				}
 			}
 		}
+		// The tables in watched databases are added to the targets when each
+		// changefeed flow starts, but they're checked now so that errors are
+		// reported to the user instead of failing the job.
+		if _, _, err := expandDatabaseTargets(
+			ctx, p.ExecCfg().DB, targets, statementTime,
+		); err != nil {
+			return err
+		}
 
 		details := jobspb.ChangefeedDetails{
 			Targets:       targets,
@@ -220,6 +318,23 @@ func changefeedPlanHook(
 			SinkURI:       sinkURI,
 			StatementTime: statementTime,
 		}
+		if changefeedStmt.Select != nil {
+			// Check the expressions against the table now, so that mistakes are
+			// reported to the user instead of failing the job.
+			details.Select = tree.AsStringWithFlags(changefeedStmt.Select, tree.FmtParsable)
+			projection, err := newChangefeedProjection(
+				details.Select, &p.ExtendedEvalContext().EvalContext)
+			if err != nil {
+				return err
+			}
+			for _, desc := range targetDescs {
+				if tableDesc := desc.GetTable(); tableDesc != nil {
+					if _, err := projection.compile(tableDesc); err != nil {
+						return err
+					}
+				}
+			}
+		}
 		progress := jobspb.Progress{
 			Progress: &jobspb.Progress_HighWater{HighWater: &initialHighWater},
 			Details: &jobspb.Progress_Changefeed{
@@ -295,7 +410,9 @@ func changefeedPlanHook(
 		// which will be immediately closed, only to check for errors.
 		{
 			nodeID := p.ExtendedEvalContext().NodeID
-			canarySink, err := getSink(details.SinkURI, nodeID, details.Opts, details.Targets, settings)
+			canarySink, err := getSink(
+				ctx, details.SinkURI, nodeID, details.Opts, details.Targets, settings,
+			)
 			if err != nil {
 				return MaybeStripRetryableErrorMarker(err)
 			}
@@ -350,6 +467,7 @@ func changefeedJobDescription(
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
 		SinkURI: tree.NewDString(cleanedSinkURI),
+		Select:  changefeed.Select,
 	}
 	for k, v := range opts {
 		opt := tree.KVOption{Key: tree.Name(k)}
@@ -403,17 +521,91 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			`unknown %s: %s`, optFormat, details.Opts[optFormat])
 	}
 
+	switch schemaChangeEventClass(details.Opts[optSchemaChangeEvents]) {
+	case ``, optSchemaChangeEventClassDefault:
+		details.Opts[optSchemaChangeEvents] = string(optSchemaChangeEventClassDefault)
+	case optSchemaChangeEventClassColumnChange:
+		// No-op.
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optSchemaChangeEvents, details.Opts[optSchemaChangeEvents])
+	}
+
+	switch schemaChangePolicy(details.Opts[optSchemaChangePolicy]) {
+	case ``, optSchemaChangePolicyBackfill:
+		details.Opts[optSchemaChangePolicy] = string(optSchemaChangePolicyBackfill)
+	case optSchemaChangePolicyNoBackfill, optSchemaChangePolicyStop:
+		// No-op.
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optSchemaChangePolicy, details.Opts[optSchemaChangePolicy])
+	}
+
 	return details, nil
 }
 
 func validateChangefeedTable(
 	targets jobspb.ChangefeedTargets, tableDesc *sqlbase.TableDescriptor,
 ) error {
+	if watchedByDatabase(targets, tableDesc) {
+		return validateChangefeedDatabaseTable(targets, tableDesc)
+	}
 	t, ok := targets[tableDesc.ID]
 	if !ok {
 		return errors.Errorf(`unwatched table: %s`, tableDesc.Name)
 	}
+	if err := validateChangefeedTableSchema(tableDesc); err != nil {
+		return err
+	}
 
+	if tableDesc.State == sqlbase.TableDescriptor_DROP {
+		return errors.Errorf(`"%s" was dropped or truncated`, t.StatementTimeName)
+	}
+	if tableDesc.Name != t.StatementTimeName {
+		return errors.Errorf(`"%s" was renamed to "%s"`, t.StatementTimeName, tableDesc.Name)
+	}
+
+	// TODO(mrtracy): re-enable this when allow-backfill option is added.
+	// if tableDesc.HasColumnBackfillMutation() {
+	// 	return errors.Errorf(`CHANGEFEEDs cannot operate on tables being backfilled`)
+	// }
+
+	return nil
+}
+
+// validateChangefeedDatabaseTable is validateChangefeedTable for the tables in
+// a watched database. The targets of a changefeed flow hold the tables that
+// were public in the database when the flow started. Tables created or dropped
+// since then make the changefeed restart, so that the next flow watches the
+// tables in the database at that time. Renamed tables don't: their rows are
+// emitted under their current name.
+func validateChangefeedDatabaseTable(
+	targets jobspb.ChangefeedTargets, tableDesc *sqlbase.TableDescriptor,
+) error {
+	// Views and sequences are not watched, and tables aren't until they're
+	// public.
+	if !tableDesc.IsTable() || tableDesc.Adding() {
+		return nil
+	}
+	_, ok := targets[tableDesc.ID]
+	if tableDesc.Dropped() {
+		if !ok {
+			return nil
+		}
+		return MarkRetryableError(errors.Errorf(`"%s" was dropped or truncated`, tableDesc.Name))
+	}
+	if err := validateChangefeedTableSchema(tableDesc); err != nil {
+		return err
+	}
+	if !ok {
+		return MarkRetryableError(errors.Errorf(`table "%s" was added`, tableDesc.Name))
+	}
+	return nil
+}
+
+// validateChangefeedTableSchema checks that a changefeed can emit the rows of
+// a table.
+func validateChangefeedTableSchema(tableDesc *sqlbase.TableDescriptor) error {
 	// Technically, the only non-user table known not to work is system.jobs
 	// (which creates a cycle since the resolved timestamp high-water mark is
 	// saved in it), but there are subtle differences in the way many of them
@@ -436,22 +628,16 @@ func validateChangefeedTable(
 			`CHANGEFEEDs are currently supported on tables with exactly 1 column family: %s has %d`,
 			tableDesc.Name, len(tableDesc.Families))
 	}
-
-	if tableDesc.State == sqlbase.TableDescriptor_DROP {
-		return errors.Errorf(`"%s" was dropped or truncated`, t.StatementTimeName)
-	}
-	if tableDesc.Name != t.StatementTimeName {
-		return errors.Errorf(`"%s" was renamed to "%s"`, t.StatementTimeName, tableDesc.Name)
-	}
-
-	// TODO(mrtracy): re-enable this when allow-backfill option is added.
-	// if tableDesc.HasColumnBackfillMutation() {
-	// 	return errors.Errorf(`CHANGEFEEDs cannot operate on tables being backfilled`)
-	// }
-
 	return nil
 }
 
+// watchedByDatabase returns whether the table is in one of the databases
+// watched by the changefeed.
+func watchedByDatabase(targets jobspb.ChangefeedTargets, tableDesc *sqlbase.TableDescriptor) bool {
+	t, ok := targets[tableDesc.ParentID]
+	return ok && t.Database
+}
+
 type changefeedResumer struct {
 	job *jobs.Job
 }
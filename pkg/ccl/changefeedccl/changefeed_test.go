@@ -13,6 +13,7 @@ import (
 	gosql "database/sql"
 	"fmt"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -129,6 +130,101 @@ func TestChangefeedEnvelope(t *testing.T) {
 	t.Run(`poller`, pollerTest(sinklessTest, testFn))
 }
 
+func TestChangefeedDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (0, 'updated')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH diff`)
+		defer closeFeed(t, foo)
+
+		// 'initial' is skipped because only the latest value ('updated') is
+		// emitted by the initial scan, which has no before values.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "updated"}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "before": null}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}, "before": null}`,
+		})
+
+		// The before value of the first change to 0 after the initial scan was
+		// written before the changefeed started.
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (0, 'c'), (1, 'd')`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'e')`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "c"}, "before": {"a": 0, "b": "updated"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "d"}, "before": {"a": 1, "b": "a"}}`,
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": "d"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "e"}, "before": null}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`poller`, pollerTest(sinklessTest, testFn))
+}
+
+func TestChangefeedProjection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT, tenant STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'zero', 0, 'x'), (1, 'one', 10, 'y')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED AS `+
+			`SELECT a, upper(b) AS b, c + 1 AS d FROM foo WHERE tenant = 'x'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "ZERO", "d": 1}}`,
+		})
+
+		// Without the diff option, a row that no longer satisfies the filter is
+		// not emitted, but deletions always are.
+		sqlDB.Exec(t, `UPDATE foo SET tenant = 'y' WHERE a = 0`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'two', 20, 'x'), (3, 'three', 30, 'y')`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 3`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "TWO", "d": 21}}`,
+			`foo: [3]->{"after": null}`,
+		})
+
+		// With the diff option, the filter is also evaluated against the
+		// previous value of the row.
+		fooDiff := feed(t, f, `CREATE CHANGEFEED WITH diff AS `+
+			`SELECT f.a, f.b FROM foo AS f WHERE f.tenant = 'y'`)
+		defer closeFeed(t, fooDiff)
+		assertPayloads(t, fooDiff, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "zero"}, "before": null}`,
+			`foo: [1]->{"after": {"a": 1, "b": "one"}, "before": null}`,
+		})
+		sqlDB.Exec(t, `UPDATE foo SET tenant = 'y' WHERE a = 2`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'uno' WHERE a = 1`)
+		sqlDB.Exec(t, `UPDATE foo SET tenant = 'x' WHERE a = 0`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a IN (0, 2)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'four', 40, 'y')`)
+		assertPayloads(t, fooDiff, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "two"}, "before": null}`,
+			`foo: [1]->{"after": {"a": 1, "b": "uno"}, "before": {"a": 1, "b": "one"}}`,
+			`foo: [0]->{"after": null, "before": {"a": 0, "b": "zero"}}`,
+			`foo: [2]->{"after": null, "before": {"a": 2, "b": "two"}}`,
+			`foo: [4]->{"after": {"a": 4, "b": "four"}, "before": null}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`poller`, pollerTest(sinklessTest, testFn))
+}
+
 func TestChangefeedMultiTable(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -153,6 +249,53 @@ func TestChangefeedMultiTable(t *testing.T) {
 	t.Run(`poller`, pollerTest(sinklessTest, testFn))
 }
 
+func TestChangefeedDatabase(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		sqlDB.Exec(t, `CREATE VIEW foo_view AS SELECT a FROM foo`)
+		sqlDB.Exec(t, `CREATE DATABASE other`)
+		sqlDB.Exec(t, `CREATE TABLE other.baz (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO other.baz VALUES (1, 'a')`)
+
+		dbFeed := feed(t, f, `CREATE CHANGEFEED FOR DATABASE d`)
+		defer closeFeed(t, dbFeed)
+		assertPayloads(t, dbFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+
+		// Tables created after the changefeed started are watched too, the
+		// tables of other databases are not.
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (2, 'b')`)
+		sqlDB.Exec(t, `INSERT INTO other.baz VALUES (2, 'b')`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c')`)
+		assertPayloads(t, dbFeed, []string{
+			`bar: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		})
+
+		// Renamed tables are emitted under their new name and dropped ones
+		// stop being watched.
+		sqlDB.Exec(t, `ALTER TABLE bar RENAME TO bar2`)
+		sqlDB.Exec(t, `INSERT INTO bar2 VALUES (4, 'd')`)
+		sqlDB.Exec(t, `DROP VIEW foo_view`)
+		sqlDB.Exec(t, `DROP TABLE foo`)
+		sqlDB.Exec(t, `INSERT INTO bar2 VALUES (5, 'e')`)
+		assertPayloads(t, dbFeed, []string{
+			`bar2: [4]->{"after": {"a": 4, "b": "d"}}`,
+			`bar2: [5]->{"after": {"a": 5, "b": "e"}}`,
+		})
+	}
+
+	// Sinkless changefeeds can't watch databases.
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`poller`, pollerTest(enterpriseTest, testFn))
+}
+
 func TestChangefeedCursor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -212,6 +355,43 @@ func TestChangefeedCursor(t *testing.T) {
 	t.Run(`poller`, pollerTest(sinklessTest, testFn))
 }
 
+func TestChangefeedInitialScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'before')`)
+		var tsLogical string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsLogical)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'after')`)
+
+		// Without the initial scan, the first message is a resolved timestamp
+		// and the rows that existed before the changefeed are not emitted.
+		noScan := feed(t, f, `CREATE CHANGEFEED FOR foo WITH no_initial_scan, resolved`)
+		defer closeFeed(t, noScan)
+		expectResolvedTimestamp(t, noScan)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'later')`)
+		assertPayloads(t, noScan, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "later"}}`,
+		})
+
+		// With a cursor, the initial scan is done as of the cursor.
+		scanWithCursor := feed(t, f,
+			`CREATE CHANGEFEED FOR foo WITH initial_scan, cursor=$1`, tsLogical)
+		defer closeFeed(t, scanWithCursor)
+		assertPayloads(t, scanWithCursor, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "before"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "after"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "later"}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`poller`, pollerTest(sinklessTest, testFn))
+}
+
 func TestChangefeedTimestamps(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -676,6 +856,84 @@ func TestChangefeedSchemaChangeAllowBackfill(t *testing.T) {
 	t.Run(`poller`, pollerTest(sinklessTest, testFn))
 }
 
+func TestChangefeedSchemaChangePolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+
+		t.Run(`nobackfill`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE nobackfill (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO nobackfill VALUES (1)`)
+			noBackfill := feed(t, f, `CREATE CHANGEFEED FOR nobackfill `+
+				`WITH schema_change_policy='nobackfill'`)
+			defer closeFeed(t, noBackfill)
+			assertPayloads(t, noBackfill, []string{
+				`nobackfill: [1]->{"after": {"a": 1}}`,
+			})
+			sqlDB.Exec(t, `ALTER TABLE nobackfill ADD COLUMN b STRING DEFAULT 'd'`)
+			sqlDB.Exec(t, `INSERT INTO nobackfill VALUES (2)`)
+			// The table is not scanned again, so 1 is not emitted with the new
+			// column.
+			assertPayloads(t, noBackfill, []string{
+				// TODO(dan): Track duplicates more precisely in sinklessFeed/tableFeed.
+				// `nobackfill: [1]->{"after": {"a": 1}}`,
+				`nobackfill: [2]->{"after": {"a": 2, "b": "d"}}`,
+			})
+		})
+
+		t.Run(`stop`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE stop_policy (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO stop_policy VALUES (1)`)
+			stop := feed(t, f, `CREATE CHANGEFEED FOR stop_policy WITH schema_change_policy='stop'`)
+			defer closeFeed(t, stop)
+			assertPayloads(t, stop, []string{
+				`stop_policy: [1]->{"after": {"a": 1}}`,
+			})
+			sqlDB.Exec(t, `ALTER TABLE stop_policy ADD COLUMN b STRING DEFAULT 'd'`)
+			sqlDB.Exec(t, `INSERT INTO stop_policy VALUES (2)`)
+			_, err := stop.Next()
+			if !testutils.IsError(err, `schema change occurred at`) {
+				t.Fatalf(`expected "schema change occurred at" error got: %+v`, err)
+			}
+
+			// The error has the cursor to resume from, which doesn't emit
+			// anything twice or skip anything.
+			m := regexp.MustCompile(`cursor='([^']+)'`).FindStringSubmatch(err.Error())
+			if m == nil {
+				t.Fatalf(`expected a cursor in: %s`, err)
+			}
+			resumed := feed(t, f, `CREATE CHANGEFEED FOR stop_policy WITH cursor=$1, `+
+				`schema_change_policy='stop'`, m[1])
+			defer closeFeed(t, resumed)
+			assertPayloads(t, resumed, []string{
+				`stop_policy: [2]->{"after": {"a": 2, "b": "d"}}`,
+			})
+		})
+
+		t.Run(`column_changes`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE column_changes (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO column_changes VALUES (1)`)
+			columnChanges := feed(t, f, `CREATE CHANGEFEED FOR column_changes `+
+				`WITH schema_change_events='column_changes'`)
+			defer closeFeed(t, columnChanges)
+			assertPayloads(t, columnChanges, []string{
+				`column_changes: [1]->{"after": {"a": 1}}`,
+			})
+			// Adding a nullable column doesn't backfill, but it's a column change,
+			// so the table is scanned again.
+			sqlDB.Exec(t, `ALTER TABLE column_changes ADD COLUMN b STRING`)
+			assertPayloads(t, columnChanges, []string{
+				`column_changes: [1]->{"after": {"a": 1, "b": null}}`,
+			})
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`poller`, pollerTest(sinklessTest, testFn))
+}
+
 // Regression test for #34314
 func TestChangefeedAfterSchemaChangeBackfill(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -1303,6 +1561,22 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `unknown envelope: nope`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH envelope=nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `unknown schema_change_events: nope`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH schema_change_events=nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown schema_change_policy: nope`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH schema_change_policy=nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `cannot specify both initial_scan and no_initial_scan`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH initial_scan, no_initial_scan`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED FOR DATABASE requires a sink`,
+		`EXPERIMENTAL CHANGEFEED FOR DATABASE d`,
+	)
 	sqlDB.ExpectErr(
 		t, `negative durations are not accepted: resolved='-1s'`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH resolved='-1s'`,
@@ -1468,6 +1742,45 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `key_in_value is only usable with envelope=wrapped`,
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH key_in_value, envelope='row'`, `kafka://nope`,
 	)
+
+	// CREATE CHANGEFEED ... AS SELECT only supports scalar expressions over a
+	// single table.
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED ... AS SELECT must select from exactly one table`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT foo.a FROM foo, foo AS bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED ... AS SELECT does not support GROUP BY`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT a FROM foo GROUP BY a`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED ... AS SELECT does not support LIMIT`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT a FROM foo LIMIT 1`,
+	)
+	sqlDB.ExpectErr(
+		t, `aggregate functions are not allowed in CHANGEFEED ... AS SELECT`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT count(a) FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `impure functions are not allowed in CHANGEFEED ... AS SELECT`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT a, now() FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `subqueries are not allowed in WHERE`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT a FROM foo WHERE a IN (SELECT a FROM foo)`,
+	)
+	sqlDB.ExpectErr(
+		t, `argument of WHERE must be type bool, not type int`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT a FROM foo WHERE a`,
+	)
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT nope FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED ... AS SELECT renders column "a" more than once`,
+		`EXPERIMENTAL CHANGEFEED AS SELECT a, b AS a FROM foo`,
+	)
 }
 
 func TestChangefeedPermissions(t *testing.T) {
@@ -47,6 +47,29 @@ type encodeRow struct {
 	// tableDesc is a TableDescriptor for the table containing `datums`.
 	// It's valid for interpreting the row at `updated`.
 	tableDesc *sqlbase.TableDescriptor
+	// prevDatums is the old value of a changed table row, laid out according
+	// to `tableDesc` like `datums`. It is only set if the changefeed was
+	// created with the diff option, and is nil if the row did not exist before
+	// the change or was emitted by a full scan.
+	prevDatums sqlbase.EncDatumRow
+	// projection, if set, describes the columns rendered by the SELECT of a
+	// CREATE CHANGEFEED ... AS SELECT changefeed. The value is then encoded from
+	// `projectedDatums` and `prevProjectedDatums`, which are laid out according
+	// to its `Columns`, instead of `datums` and `prevDatums`. The key is still
+	// encoded from the primary key columns in `datums`.
+	projection                           *sqlbase.TableDescriptor
+	projectedDatums, prevProjectedDatums sqlbase.EncDatumRow
+}
+
+// valueColumns returns the descriptor that the value of the row is encoded
+// with, along with the new and old values of the row laid out according to it.
+func (r encodeRow) valueColumns() (
+	*sqlbase.TableDescriptor, sqlbase.EncDatumRow, sqlbase.EncDatumRow,
+) {
+	if r.projection != nil {
+		return r.projection, r.projectedDatums, r.prevProjectedDatums
+	}
+	return r.tableDesc, r.datums, r.prevDatums
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -84,7 +107,7 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
-	updatedField, wrapped, keyOnly, keyInValue bool
+	updatedField, beforeField, wrapped, keyOnly, keyInValue bool
 
 	alloc sqlbase.DatumAlloc
 	buf   bytes.Buffer
@@ -98,6 +121,11 @@ func makeJSONEncoder(opts map[string]string) (*jsonEncoder, error) {
 		wrapped: envelopeType(opts[optEnvelope]) == optEnvelopeWrapped,
 	}
 	_, e.updatedField = opts[optUpdatedTimestamps]
+	_, e.beforeField = opts[optDiff]
+	if e.beforeField && !e.wrapped {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			optDiff, optEnvelope, optEnvelopeWrapped)
+	}
 	_, e.keyInValue = opts[optKeyInValue]
 	if e.keyInValue && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
@@ -148,21 +176,12 @@ func (e *jsonEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		return nil, nil
 	}
 
+	valueDesc, datums, prevDatums := row.valueColumns()
 	var after map[string]interface{}
 	if !row.deleted {
-		columns := row.tableDesc.Columns
-		after = make(map[string]interface{}, len(columns))
-		for i := range columns {
-			col := &columns[i]
-			datum := row.datums[i]
-			if err := datum.EnsureDecoded(&col.Type, &e.alloc); err != nil {
-				return nil, err
-			}
-			var err error
-			after[col.Name], err = tree.AsJSON(datum.Datum)
-			if err != nil {
-				return nil, err
-			}
+		var err error
+		if after, err = e.encodeColumns(valueDesc, datums); err != nil {
+			return nil, err
 		}
 	}
 
@@ -173,6 +192,20 @@ func (e *jsonEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		} else {
 			jsonEntries = map[string]interface{}{`after`: nil}
 		}
+		if e.beforeField {
+			var before map[string]interface{}
+			if prevDatums != nil {
+				var err error
+				if before, err = e.encodeColumns(valueDesc, prevDatums); err != nil {
+					return nil, err
+				}
+			}
+			if before != nil {
+				jsonEntries[`before`] = before
+			} else {
+				jsonEntries[`before`] = nil
+			}
+		}
 		if e.keyInValue {
 			keyEntries, err := e.encodeKeyRaw(row)
 			if err != nil {
@@ -204,6 +237,28 @@ func (e *jsonEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 	return e.buf.Bytes(), nil
 }
 
+// encodeColumns returns a JSON object mapping every column name to its value in
+// the given row.
+func (e *jsonEncoder) encodeColumns(
+	tableDesc *sqlbase.TableDescriptor, datums sqlbase.EncDatumRow,
+) (map[string]interface{}, error) {
+	columns := tableDesc.Columns
+	jsonEntries := make(map[string]interface{}, len(columns))
+	for i := range columns {
+		col := &columns[i]
+		datum := datums[i]
+		if err := datum.EnsureDecoded(&col.Type, &e.alloc); err != nil {
+			return nil, err
+		}
+		var err error
+		jsonEntries[col.Name], err = tree.AsJSON(datum.Datum)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return jsonEntries, nil
+}
+
 // EncodeResolvedTimestamp implements the Encoder interface.
 func (e *jsonEncoder) EncodeResolvedTimestamp(_ string, resolved hlc.Timestamp) ([]byte, error) {
 	meta := map[string]interface{}{
@@ -224,8 +279,8 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(_ string, resolved hlc.Timestamp)
 // JSON format. Keys are the primary key columns in a record. Values are all
 // columns in a record.
 type confluentAvroEncoder struct {
-	registryURL           string
-	updatedField, keyOnly bool
+	registryURL                        string
+	updatedField, beforeField, keyOnly bool
 
 	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache    map[tableIDAndVersion]confluentRegisteredEnvelopeSchema
@@ -262,6 +317,11 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 			optEnvelope, opts[optEnvelope], optFormat, optFormatAvro)
 	}
 	_, e.updatedField = opts[optUpdatedTimestamps]
+	_, e.beforeField = opts[optDiff]
+	if e.beforeField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			optDiff, optEnvelope, optEnvelopeWrapped)
+	}
 
 	if _, ok := opts[optKeyInValue]; ok {
 		return nil, errors.Errorf(`%s is not supported with %s=%s`,
@@ -316,15 +376,18 @@ func (e *confluentAvroEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		return nil, nil
 	}
 
+	valueDesc, datums, prevDatums := row.valueColumns()
 	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
 	registered, ok := e.valueCache[cacheKey]
 	if !ok {
-		afterDataSchema, err := tableToAvroSchema(row.tableDesc)
+		afterDataSchema, err := tableToAvroSchema(valueDesc)
 		if err != nil {
 			return nil, err
 		}
 
-		opts := avroEnvelopeOpts{afterField: true, updatedField: e.updatedField}
+		opts := avroEnvelopeOpts{
+			afterField: true, beforeField: e.beforeField, updatedField: e.updatedField,
+		}
 		registered.schema, err = envelopeToAvroSchema(row.tableDesc.Name, opts, afterDataSchema)
 		if err != nil {
 			return nil, err
@@ -346,9 +409,12 @@ func (e *confluentAvroEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 			`updated`: row.updated,
 		}
 	}
-	var datums sqlbase.EncDatumRow
+	var beforeDatums, afterDatums sqlbase.EncDatumRow
+	if registered.schema.opts.beforeField {
+		beforeDatums = prevDatums
+	}
 	if !row.deleted {
-		datums = row.datums
+		afterDatums = datums
 	}
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
 	header := []byte{
@@ -356,7 +422,7 @@ func (e *confluentAvroEncoder) EncodeValue(row encodeRow) ([]byte, error) {
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
-	return registered.schema.BinaryFromRow(header, meta, datums)
+	return registered.schema.BinaryFromRow(header, meta, beforeDatums, afterDatums)
 }
 
 // EncodeResolvedTimestamp implements the Encoder interface.
@@ -394,7 +460,7 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
-	return registered.schema.BinaryFromRow(header, meta, nil /* row */)
+	return registered.schema.BinaryFromRow(header, meta, nil /* beforeRow */, nil /* afterRow */)
 }
 
 func (e *confluentAvroEncoder) register(schema *avroRecord, subject string) (int32, error) {
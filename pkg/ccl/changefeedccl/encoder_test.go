@@ -193,6 +193,102 @@ func TestEncoders(t *testing.T) {
 	}
 }
 
+func TestEncodersDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	makeRow := func(b string) sqlbase.EncDatumRow {
+		return sqlbase.EncDatumRow{
+			sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+			sqlbase.EncDatum{Datum: tree.NewDString(b)},
+		}
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	tests := []struct {
+		opts map[string]string
+		// Either err is set or all of insert, update, and delete are.
+		err    string
+		insert string
+		update string
+		delete string
+	}{
+		{
+			opts:   map[string]string{optFormat: string(optFormatJSON), optEnvelope: string(optEnvelopeWrapped)},
+			insert: `{"after": {"a": 1, "b": "new"}, "before": null}`,
+			update: `{"after": {"a": 1, "b": "new"}, "before": {"a": 1, "b": "old"}}`,
+			delete: `{"after": null, "before": {"a": 1, "b": "old"}}`,
+		},
+		{
+			opts: map[string]string{optFormat: string(optFormatJSON), optEnvelope: string(optEnvelopeRow)},
+			err:  `diff is only usable with envelope=wrapped`,
+		},
+		{
+			opts: map[string]string{
+				optFormat: string(optFormatAvro), optEnvelope: string(optEnvelopeWrapped),
+			},
+			insert: `{"after":{"foo":{"a":{"long":1},"b":{"string":"new"}}},"before":null}`,
+			update: `{"after":{"foo":{"a":{"long":1},"b":{"string":"new"}}},` +
+				`"before":{"foo":{"a":{"long":1},"b":{"string":"old"}}}}`,
+			delete: `{"after":null,"before":{"foo":{"a":{"long":1},"b":{"string":"old"}}}}`,
+		},
+		{
+			opts: map[string]string{
+				optFormat: string(optFormatAvro), optEnvelope: string(optEnvelopeKeyOnly),
+			},
+			err: `diff is only usable with envelope=wrapped`,
+		},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("format=%s,envelope=%s", test.opts[optFormat], test.opts[optEnvelope])
+		t.Run(name, func(t *testing.T) {
+			test.opts[optDiff] = ``
+			valueStringFn := func(v []byte) string { return string(v) }
+			if test.opts[optFormat] == string(optFormatAvro) {
+				reg := makeTestSchemaRegistry()
+				defer reg.Close()
+				test.opts[optConfluentSchemaRegistry] = reg.server.URL
+				valueStringFn = func(v []byte) string { return string(avroToJSON(t, reg, v)) }
+			}
+
+			e, err := getEncoder(test.opts)
+			if len(test.err) > 0 {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, r := range []struct {
+				row      encodeRow
+				expected string
+			}{
+				{
+					row:      encodeRow{datums: makeRow(`new`), updated: ts, tableDesc: tableDesc},
+					expected: test.insert,
+				},
+				{
+					row: encodeRow{
+						datums: makeRow(`new`), prevDatums: makeRow(`old`), updated: ts, tableDesc: tableDesc,
+					},
+					expected: test.update,
+				},
+				{
+					row: encodeRow{
+						datums: makeRow(`new`), prevDatums: makeRow(`old`), deleted: true, updated: ts,
+						tableDesc: tableDesc,
+					},
+					expected: test.delete,
+				},
+			} {
+				value, err := e.EncodeValue(r.row)
+				require.NoError(t, err)
+				require.Equal(t, r.expected, valueStringFn(value))
+			}
+		})
+	}
+}
+
 type testSchemaRegistry struct {
 	server *httptest.Server
 	mu     struct {
@@ -323,6 +419,37 @@ func TestAvroEncoder(t *testing.T) {
 	t.Run(`poller`, pollerTest(sinklessTest, testFn))
 }
 
+func TestAvroEncoderProjection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		reg := makeTestSchemaRegistry()
+		defer reg.Close()
+
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar', 10), (2, 'baz', 20)`)
+
+		// The value schema only has the rendered columns, while the key schema is
+		// still the primary key of the table.
+		foo := feed(t, f, `CREATE CHANGEFEED WITH format=$1, confluent_schema_registry=$2 AS `+
+			`SELECT a, c::FLOAT / 2 AS half FROM foo WHERE b != 'baz'`,
+			optFormatAvro, reg.server.URL)
+		defer closeFeed(t, foo)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"half":{"double":5}}}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'baz', 30), (4, 'qux', NULL)`)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":4}}->{"after":{"foo":{"a":{"long":4},"half":null}}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestAvroMigrateToUnsupportedColumn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -54,6 +54,16 @@ type poller struct {
 	leaseMgr  *sql.LeaseManager
 	metrics   *Metrics
 	mm        *mon.BytesMonitor
+	// withDiff is set if the changefeed was created with the diff option, in
+	// which case the previous value of every changed kv is added to the buffer
+	// along with it.
+	withDiff bool
+	// schemaChangeEvents and schemaChangePolicy are the schema_change_events
+	// and schema_change_policy options of the changefeed. They determine which
+	// table descriptor changes add a scanBoundary and what happens when the
+	// poller reaches it.
+	schemaChangeEvents schemaChangeEventClass
+	schemaChangePolicy schemaChangePolicy
 
 	mu struct {
 		syncutil.Mutex
@@ -63,8 +73,11 @@ type poller struct {
 		// should pause and output a scan of *all keys* of the watched spans at the
 		// given timestamp. There are currently two situations where this occurs:
 		// the initial scan of the table when starting a new Changefeed, and when
-		// a backfilling schema change is marked as completed. This collection must
-		// be kept in sorted order (by timestamp ascending).
+		// a schema change matching schemaChangeEvents is detected (by default,
+		// when a backfilling schema change is marked as completed). With the stop
+		// schemaChangePolicy, the poller returns an error instead of scanning at
+		// the boundaries of schema changes. This collection must be kept in
+		// sorted order (by timestamp ascending).
 		scanBoundaries []hlc.Timestamp
 		// previousTableVersion is a map from tableID to the most recent version
 		// of the table descriptor seen by the poller. This is needed to determine
@@ -100,6 +113,16 @@ func makePoller(
 		metrics:  metrics,
 		mm:       mm,
 	}
+	_, p.withDiff = details.Opts[optDiff]
+	// Jobs created before these options existed don't have them set.
+	p.schemaChangeEvents = schemaChangeEventClass(details.Opts[optSchemaChangeEvents])
+	if p.schemaChangeEvents == `` {
+		p.schemaChangeEvents = optSchemaChangeEventClassDefault
+	}
+	p.schemaChangePolicy = schemaChangePolicy(details.Opts[optSchemaChangePolicy])
+	if p.schemaChangePolicy == `` {
+		p.schemaChangePolicy = optSchemaChangePolicyBackfill
+	}
 	p.mu.previousTableVersion = make(map[sqlbase.ID]*sqlbase.TableDescriptor)
 	// If no highWater is specified, set the highwater to the statement time
 	// and add a scanBoundary at the statement time to trigger an immediate output
@@ -152,9 +175,11 @@ func (p *poller) Run(ctx context.Context) error {
 
 		// Determine if we are at a scanBoundary, and trigger a full scan if needed.
 		isFullScan := false
+		var stopErr error
 		p.mu.Lock()
 		if len(p.mu.scanBoundaries) > 0 {
 			if p.mu.scanBoundaries[0].Equal(lastHighwater) {
+				stopErr = p.checkStopBoundary(lastHighwater)
 				// Perform a full scan of the latest value of all keys as of the
 				// boundary timestamp and consume the boundary.
 				isFullScan = true
@@ -169,6 +194,9 @@ func (p *poller) Run(ctx context.Context) error {
 			}
 		}
 		p.mu.Unlock()
+		if stopErr != nil {
+			return p.stop(ctx, stopErr)
+		}
 
 		if !isFullScan {
 			log.VEventf(ctx, 1, `changefeed poll (%s,%s]: %s`,
@@ -231,6 +259,9 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 		}
 		p.mu.Unlock()
 		if scanTime != (hlc.Timestamp{}) {
+			if err := p.checkStopBoundary(scanTime); err != nil {
+				return p.stop(ctx, err)
+			}
 			if err := p.exportSpansParallel(
 				ctx, spans, scanTime, scanTime, true, /* fullScan */
 			); err != nil {
@@ -316,6 +347,30 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 			}
 		})
 		g.GoCtx(func(ctx context.Context) error {
+			// With the diff option, kvs are held back until the next resolved
+			// timestamp (or until enough of them have accumulated) so that their
+			// previous values are fetched in batches instead of one at a time.
+			var pending []bufferEntry
+			flushPending := func() error {
+				if len(pending) == 0 {
+					return nil
+				}
+				kvs := make([]roachpb.KeyValue, len(pending))
+				for i := range pending {
+					kvs[i] = pending[i].kv
+				}
+				prevVals := make([]roachpb.Value, len(pending))
+				if err := p.fetchPrevValues(ctx, kvs, prevVals, hlc.Timestamp{}); err != nil {
+					return err
+				}
+				for i := range pending {
+					if err := p.buf.AddKV(ctx, kvs[i], prevVals[i], pending[i].schemaTimestamp); err != nil {
+						return err
+					}
+				}
+				pending = pending[:0]
+				return nil
+			}
 			for {
 				e, err := memBuf.Get(ctx)
 				if err != nil {
@@ -336,10 +391,24 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 					if pastBoundary {
 						continue
 					}
-					if err := p.buf.AddKV(ctx, e.kv, e.schemaTimestamp); err != nil {
+					if p.withDiff {
+						// Hold on to the kv so that its previous value can be
+						// fetched along with those of the kvs around it.
+						pending = append(pending, e)
+						if len(pending) >= prevValueBatchSize {
+							if err := flushPending(); err != nil {
+								return err
+							}
+						}
+						continue
+					}
+					if err := p.buf.AddKV(ctx, e.kv, roachpb.Value{}, e.schemaTimestamp); err != nil {
 						return err
 					}
 				} else if e.resolved != nil {
+					if err := flushPending(); err != nil {
+						return err
+					}
 					resolvedTS := e.resolved.Timestamp
 					boundaryBreak := false
 					p.mu.Lock()
@@ -507,14 +576,16 @@ func (p *poller) exportSpan(
 	p.metrics.PollRequestNanosHist.RecordValue(exportDuration.Nanoseconds())
 
 	// When outputting a full scan, we want to use the schema at the scan
-	// timestamp, not the schema at the value timestamp.
+	// timestamp, not the schema at the value timestamp. Rows from a full scan
+	// are not changes, so they never have a previous value.
 	var schemaTimestamp hlc.Timestamp
 	if isFullScan {
 		schemaTimestamp = end
 	}
+	withDiff := p.withDiff && !isFullScan
 	stopwatchStart = timeutil.Now()
 	for _, file := range exported.(*roachpb.ExportResponse).Files {
-		if err := p.slurpSST(ctx, file.SST, schemaTimestamp); err != nil {
+		if err := p.slurpSST(ctx, file.SST, schemaTimestamp, start, withDiff); err != nil {
 			return err
 		}
 	}
@@ -555,28 +626,20 @@ func (p *poller) pollTableHistory(ctx context.Context) error {
 }
 
 // slurpSST iterates an encoded sst and inserts the contained kvs into the
-// buffer.
-func (p *poller) slurpSST(ctx context.Context, sst []byte, schemaTimestamp hlc.Timestamp) error {
-	var previousKey roachpb.Key
+// buffer. If withDiff is true, each kv is inserted along with the previous
+// value of its key; the sst must then hold every revision written after
+// start, as exported over (start, end].
+func (p *poller) slurpSST(
+	ctx context.Context, sst []byte, schemaTimestamp, start hlc.Timestamp, withDiff bool,
+) error {
 	var kvs []roachpb.KeyValue
-	slurpKVs := func() error {
-		sort.Sort(byValueTimestamp(kvs))
-		for _, kv := range kvs {
-			if err := p.buf.AddKV(ctx, kv, schemaTimestamp); err != nil {
-				return err
-			}
-		}
-		previousKey = previousKey[:0]
-		kvs = kvs[:0]
-		return nil
-	}
-
 	var scratch bufalloc.ByteAllocator
 	it, err := engine.NewMemSSTIterator(sst, false /* verify */)
 	if err != nil {
 		return err
 	}
 	defer it.Close()
+	keyStart := 0
 	for it.Seek(engine.NilKey); ; it.Next() {
 		if ok, err := it.Valid(); err != nil {
 			return err
@@ -592,22 +655,97 @@ func (p *poller) slurpSST(ctx context.Context, sst []byte, schemaTimestamp hlc.T
 
 		// The buffer currently requires that each key's mvcc revisions are
 		// added in increasing timestamp order. The sst is guaranteed to be in
-		// key order, but decresing timestamp order. So, buffer up kvs until the
-		// key changes, then sort by increasing timestamp before handing them
-		// all to AddKV.
-		if !previousKey.Equal(key) {
-			if err := slurpKVs(); err != nil {
-				return err
-			}
-			previousKey = key
+		// key order, but decresing timestamp order. So, sort each key's
+		// revisions by increasing timestamp once the key changes.
+		if len(kvs) > 0 && !kvs[len(kvs)-1].Key.Equal(key) {
+			sort.Sort(byValueTimestamp(kvs[keyStart:]))
+			keyStart = len(kvs)
 		}
 		kvs = append(kvs, roachpb.KeyValue{
 			Key:   key,
 			Value: roachpb.Value{RawBytes: value, Timestamp: unsafeKey.Timestamp},
 		})
 	}
+	sort.Sort(byValueTimestamp(kvs[keyStart:]))
+
+	var prevVals []roachpb.Value
+	if withDiff {
+		// The first revision of each key in the sst is the first one written
+		// after start, so its previous value is the key's value as of start.
+		prevVals = make([]roachpb.Value, len(kvs))
+		if err := p.fetchPrevValues(ctx, kvs, prevVals, start); err != nil {
+			return err
+		}
+	}
+	for i := range kvs {
+		var prevVal roachpb.Value
+		if withDiff {
+			prevVal = prevVals[i]
+		}
+		if err := p.buf.AddKV(ctx, kvs[i], prevVal, schemaTimestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prevValueBatchSize is the maximum number of rangefeed kvs whose previous
+// values are fetched together.
+const prevValueBatchSize = 1000
+
+// fetchPrevValues sets prevVals[i] to the value of kvs[i].Key immediately
+// before kvs[i] was written, or to an empty value if the key did not exist
+// then. Each key's revisions must be in increasing timestamp order in kvs.
+//
+// The previous value of a revision is taken from an earlier revision of the
+// same key in kvs when there is one. The rest are read with one batch per
+// read timestamp: asOf if it is set, which requires that kvs hold every
+// revision of their keys written after asOf, and otherwise just before each
+// revision's timestamp, so that the keys written by a transaction are read
+// together.
+func (p *poller) fetchPrevValues(
+	ctx context.Context, kvs []roachpb.KeyValue, prevVals []roachpb.Value, asOf hlc.Timestamp,
+) error {
+	latest := make(map[string]int, len(kvs))
+	toRead := make(map[hlc.Timestamp][]int)
+	for i := range kvs {
+		key := string(kvs[i].Key)
+		if j, ok := latest[key]; ok && kvs[j].Value.Timestamp.Less(kvs[i].Value.Timestamp) {
+			prevVals[i] = kvs[j].Value
+		} else {
+			readTS := asOf
+			if readTS.IsEmpty() {
+				readTS = kvs[i].Value.Timestamp.Prev()
+			}
+			toRead[readTS] = append(toRead[readTS], i)
+		}
+		latest[key] = i
+	}
 
-	return slurpKVs()
+	for readTS, idxs := range toRead {
+		readTS, idxs := readTS, idxs
+		if err := p.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			txn.SetFixedTimestamp(ctx, readTS)
+			b := txn.NewBatch()
+			for _, i := range idxs {
+				b.Get(kvs[i].Key)
+			}
+			if err := txn.Run(ctx, b); err != nil {
+				return err
+			}
+			for j, i := range idxs {
+				prevVals[i] = roachpb.Value{}
+				if value := b.Results[j].Rows[0].Value; value != nil {
+					prevVals[i] = *value
+				}
+			}
+			return nil
+		}); err != nil {
+			return pgerror.Wrapf(err, pgerror.CodeDataExceptionError,
+				`fetching previous values as of %s`, readTS)
+		}
+	}
+	return nil
 }
 
 type byValueTimestamp []roachpb.KeyValue
@@ -645,6 +783,16 @@ func clusterNodeCount(g *gossip.Gossip) int {
 }
 
 func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescriptor) error {
+	if _, ok := p.details.Targets[desc.ID]; !ok && desc.IsTable() &&
+		desc.State == sqlbase.TableDescriptor_PUBLIC && watchedByDatabase(p.details.Targets, desc) {
+		// A table added to a watched database restarts the changefeed, which
+		// then watches the tables that exist at the time of the restart. A
+		// table that has been dropped again by now wouldn't be watched, so
+		// restarting for it would only find it again.
+		if dropped, err := p.isTableDropped(ctx, desc.ID); err != nil || dropped {
+			return err
+		}
+	}
 	if err := validateChangefeedTable(p.details.Targets, desc); err != nil {
 		return err
 	}
@@ -654,7 +802,7 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 		if desc.ModificationTime.Less(lastVersion.ModificationTime) {
 			return nil
 		}
-		if lastVersion.HasColumnBackfillMutation() && !desc.HasColumnBackfillMutation() {
+		if p.isSchemaChangeEvent(lastVersion, desc) {
 			boundaryTime := desc.GetModificationTime()
 			// Only mutations that happened after the changefeed started are
 			// interesting here.
@@ -668,10 +816,14 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 						log.Safe(p.mu.highWater),
 					)
 				}
-				p.mu.scanBoundaries = append(p.mu.scanBoundaries, boundaryTime)
-				sort.Slice(p.mu.scanBoundaries, func(i, j int) bool {
-					return p.mu.scanBoundaries[i].Less(p.mu.scanBoundaries[j])
-				})
+				// With the nobackfill policy, the changefeed carries on emitting
+				// changes without rescanning the table.
+				if p.schemaChangePolicy != optSchemaChangePolicyNoBackfill {
+					p.mu.scanBoundaries = append(p.mu.scanBoundaries, boundaryTime)
+					sort.Slice(p.mu.scanBoundaries, func(i, j int) bool {
+						return p.mu.scanBoundaries[i].Less(p.mu.scanBoundaries[j])
+					})
+				}
 				// To avoid race conditions with the lease manager, at this point we force
 				// the manager to acquire the freshest descriptor of this table from the
 				// store. In normal operation, the lease manager returns the newest
@@ -689,6 +841,76 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 	return nil
 }
 
+// isSchemaChangeEvent returns whether going from the prev to the next version
+// of a table descriptor is a schema change of the class configured by the
+// schema_change_events option.
+func (p *poller) isSchemaChangeEvent(prev, next *sqlbase.TableDescriptor) bool {
+	switch p.schemaChangeEvents {
+	case optSchemaChangeEventClassColumnChange:
+		return !sameColumns(prev, next)
+	default:
+		return prev.HasColumnBackfillMutation() && !next.HasColumnBackfillMutation()
+	}
+}
+
+// sameColumns returns whether two versions of a table descriptor have the same
+// public columns.
+func sameColumns(a, b *sqlbase.TableDescriptor) bool {
+	if len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	for i := range a.Columns {
+		if a.Columns[i].ID != b.Columns[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// checkStopBoundary returns an error if the changefeed has to stop at the
+// given scan boundary because of the stop schema_change_policy. The initial
+// scan is never a reason to stop. Every change up to and including the
+// boundary is in the buffer by then, so a new changefeed with the boundary as
+// its cursor picks up exactly where this one stopped.
+func (p *poller) checkStopBoundary(boundary hlc.Timestamp) error {
+	if p.schemaChangePolicy != optSchemaChangePolicyStop ||
+		!p.details.StatementTime.Less(boundary) {
+		return nil
+	}
+	return errors.Errorf(
+		`schema change occurred at %[1]s: stopping because of %[2]s=%[3]s, `+
+			`create a new changefeed WITH %[4]s='%[1]s' to continue`,
+		boundary.AsOfSystemTime(), optSchemaChangePolicy, optSchemaChangePolicyStop, optCursor)
+}
+
+// stop adds err to the buffer, so that the changefeed fails with it once the
+// entries before it are emitted, and then waits for the changefeed to shut
+// down.
+func (p *poller) stop(ctx context.Context, err error) error {
+	if err := p.buf.AddStop(ctx, err); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// isTableDropped returns whether the table is dropped or gone as of now.
+func (p *poller) isTableDropped(ctx context.Context, id sqlbase.ID) (bool, error) {
+	var dropped bool
+	err := p.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, id)
+		if err == sqlbase.ErrDescriptorNotFound {
+			dropped = true
+			return nil
+		} else if err != nil {
+			return err
+		}
+		dropped = tableDesc.Dropped()
+		return nil
+	})
+	return dropped, err
+}
+
 func fetchSpansForTargets(
 	ctx context.Context, db *client.DB, targets jobspb.ChangefeedTargets, ts hlc.Timestamp,
 ) ([]roachpb.Span, error) {
@@ -696,8 +918,13 @@ func fetchSpansForTargets(
 	err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		spans = nil
 		txn.SetFixedTimestamp(ctx, ts)
-		// Note that all targets are currently guaranteed to be tables.
-		for tableID := range targets {
+		// Note that all targets are currently guaranteed to be tables or
+		// databases. The tables in the databases are added by
+		// expandDatabaseTargets.
+		for tableID, t := range targets {
+			if t.Database {
+				continue
+			}
 			tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, tableID)
 			if err != nil {
 				return err
@@ -708,3 +935,52 @@ func fetchSpansForTargets(
 	})
 	return spans, err
 }
+
+// expandDatabaseTargets returns a copy of the targets with the public tables
+// of the watched databases as of ts added, along with the spans of the added
+// tables. The targets are returned unchanged if no database is watched.
+func expandDatabaseTargets(
+	ctx context.Context, db *client.DB, targets jobspb.ChangefeedTargets, ts hlc.Timestamp,
+) (jobspb.ChangefeedTargets, []roachpb.Span, error) {
+	var watchesDatabase bool
+	for _, t := range targets {
+		watchesDatabase = watchesDatabase || t.Database
+	}
+	if !watchesDatabase {
+		return targets, nil, nil
+	}
+
+	var expanded jobspb.ChangefeedTargets
+	var spans []roachpb.Span
+	err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		expanded = make(jobspb.ChangefeedTargets, len(targets))
+		for id, t := range targets {
+			expanded[id] = t
+		}
+		spans = nil
+		txn.SetFixedTimestamp(ctx, ts)
+		descs, err := sql.GetAllDescriptors(ctx, txn)
+		if err != nil {
+			return err
+		}
+		for _, desc := range descs {
+			tableDesc, ok := desc.(*sqlbase.TableDescriptor)
+			if !ok || !watchedByDatabase(targets, tableDesc) {
+				continue
+			}
+			if !tableDesc.IsTable() || tableDesc.State != sqlbase.TableDescriptor_PUBLIC {
+				continue
+			}
+			expanded[tableDesc.ID] = jobspb.ChangefeedTarget{StatementTimeName: tableDesc.Name}
+			if err := validateChangefeedTable(expanded, tableDesc); err != nil {
+				return err
+			}
+			spans = append(spans, tableDesc.PrimaryIndexSpan())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return expanded, spans, nil
+}
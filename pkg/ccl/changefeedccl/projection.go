@@ -0,0 +1,406 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/pkg/errors"
+)
+
+// validateChangefeedSelect checks that the SELECT of a CREATE CHANGEFEED ... AS
+// SELECT statement is restricted to a projection and a filter over a single
+// table. It returns the SELECT clause and the name of the table.
+func validateChangefeedSelect(sel *tree.Select) (*tree.SelectClause, *tree.TableName, error) {
+	unsupported := func(what string) error {
+		return errors.Errorf(`CHANGEFEED ... AS SELECT does not support %s`, what)
+	}
+	if sel.With != nil {
+		return nil, nil, unsupported(`WITH`)
+	}
+	if sel.OrderBy != nil {
+		return nil, nil, unsupported(`ORDER BY`)
+	}
+	if sel.Limit != nil {
+		return nil, nil, unsupported(`LIMIT`)
+	}
+	if sel.Locking != nil {
+		return nil, nil, unsupported(`locking clauses`)
+	}
+	sc, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, nil, errors.Errorf(
+			`CHANGEFEED ... AS SELECT must be a simple SELECT: %s`, tree.AsString(sel))
+	}
+	switch {
+	case sc.Distinct || len(sc.DistinctOn) > 0:
+		return nil, nil, unsupported(`DISTINCT`)
+	case len(sc.GroupBy) > 0:
+		return nil, nil, unsupported(`GROUP BY`)
+	case sc.Having != nil:
+		return nil, nil, unsupported(`HAVING`)
+	case len(sc.Window) > 0:
+		return nil, nil, unsupported(`WINDOW`)
+	case sc.From == nil || len(sc.From.Tables) != 1:
+		return nil, nil, errors.New(`CHANGEFEED ... AS SELECT must select from exactly one table`)
+	case sc.From.AsOf.Expr != nil:
+		return nil, nil, unsupported(`AS OF SYSTEM TIME`)
+	}
+	ate, ok := sc.From.Tables[0].(*tree.AliasedTableExpr)
+	if !ok {
+		return nil, nil, errors.Errorf(
+			`CHANGEFEED ... AS SELECT must select from a table: %s`, tree.AsString(sc.From.Tables[0]))
+	}
+	tn, ok := ate.Expr.(*tree.TableName)
+	if !ok {
+		return nil, nil, errors.Errorf(
+			`CHANGEFEED ... AS SELECT must select from a table: %s`, tree.AsString(ate.Expr))
+	}
+	switch {
+	case ate.IndexFlags != nil:
+		return nil, nil, unsupported(`index hints`)
+	case ate.Ordinality:
+		return nil, nil, unsupported(`WITH ORDINALITY`)
+	case len(ate.As.Cols) > 0:
+		return nil, nil, unsupported(`column aliases`)
+	}
+	return sc, tn, nil
+}
+
+// changefeedProjection evaluates the SELECT of a CREATE CHANGEFEED ... AS
+// SELECT changefeed against changed rows. Rows that don't satisfy the WHERE
+// clause are dropped, and the value of the others is replaced by the columns
+// rendered by the SELECT. The key is left untouched, so it is still the primary
+// key of the table.
+//
+// The filter cannot be evaluated against a deleted row, because only its
+// primary key is known, so deletions are always emitted. If the changefeed was
+// created with the diff option, the filter is also evaluated against the
+// previous value of the row: deletions of rows that did not satisfy it are then
+// dropped, and an update that makes a row stop satisfying it is emitted as a
+// deletion.
+type changefeedProjection struct {
+	sc      *tree.SelectClause
+	evalCtx *tree.EvalContext
+
+	compiled map[tableIDAndVersion]*compiledProjection
+	alloc    sqlbase.DatumAlloc
+}
+
+// maxCompiledProjections is the number of table descriptor versions a
+// changefeedProjection keeps compiled.
+const maxCompiledProjections = 8
+
+// compiledProjection is a changefeedProjection resolved against one version of
+// the table descriptor.
+type compiledProjection struct {
+	// desc describes the rendered columns. It has the ID, version and name of
+	// the table, but its columns are the rendered ones.
+	desc   *sqlbase.TableDescriptor
+	exprs  []tree.TypedExpr
+	filter tree.TypedExpr
+	iv     projectionIVarContainer
+}
+
+// projectionIVarContainer is the IndexedVarContainer of the expressions of a
+// compiledProjection. The indexed vars are the columns of the table.
+type projectionIVarContainer struct {
+	cols []sqlbase.ColumnDescriptor
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &projectionIVarContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *projectionIVarContainer) IndexedVarEval(
+	idx int, _ *tree.EvalContext,
+) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *projectionIVarContainer) IndexedVarResolvedType(idx int) *types.T {
+	return &c.cols[idx].Type
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *projectionIVarContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx].Name)
+	return &n
+}
+
+// newChangefeedProjection parses the SELECT stored in the details of a
+// changefeed.
+func newChangefeedProjection(sel string, evalCtx *tree.EvalContext) (*changefeedProjection, error) {
+	stmt, err := parser.ParseOne(sel)
+	if err != nil {
+		return nil, err
+	}
+	parsed, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.Errorf(`expected a SELECT statement: %s`, sel)
+	}
+	sc, _, err := validateChangefeedSelect(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &changefeedProjection{
+		sc:       sc,
+		evalCtx:  evalCtx,
+		compiled: make(map[tableIDAndVersion]*compiledProjection),
+	}, nil
+}
+
+// compile resolves and type checks the expressions of the SELECT against the
+// columns of the given table descriptor.
+func (p *changefeedProjection) compile(
+	tableDesc *sqlbase.TableDescriptor,
+) (*compiledProjection, error) {
+	cacheKey := makeTableIDAndVersion(tableDesc.ID, tableDesc.Version)
+	if c, ok := p.compiled[cacheKey]; ok {
+		return c, nil
+	}
+
+	c := &compiledProjection{
+		desc: &sqlbase.TableDescriptor{
+			ID:      tableDesc.ID,
+			Version: tableDesc.Version,
+			Name:    tableDesc.Name,
+		},
+		iv: projectionIVarContainer{cols: tableDesc.Columns},
+	}
+	ate := p.sc.From.Tables[0].(*tree.AliasedTableExpr)
+	sourceName := ate.Expr.(*tree.TableName).TableName
+	if ate.As.Alias != "" {
+		sourceName = ate.As.Alias
+	}
+	sources := sqlbase.MakeMultiSourceInfo(sqlbase.NewSourceInfoForSingleTable(
+		tree.MakeUnqualifiedTableName(sourceName), sqlbase.ResultColumnsFromColDescs(tableDesc.Columns),
+	))
+	ivarHelper := tree.MakeIndexedVarHelper(&c.iv, len(tableDesc.Columns))
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = &c.iv
+	searchPath := sqlbase.DefaultSearchPath
+	if p.evalCtx.SessionData != nil {
+		searchPath = p.evalCtx.SessionData.SearchPath
+	}
+
+	typeCheck := func(
+		expr tree.Expr, desired *types.T, context string,
+	) (tree.TypedExpr, error) {
+		expr, _, _, err := sqlbase.ResolveNames(expr, sources, ivarHelper, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		// Only scalar expressions are allowed, and they must be deterministic
+		// because a row may be emitted more than once.
+		defer semaCtx.Properties.Restore(semaCtx.Properties)
+		semaCtx.Properties.Require(context,
+			tree.RejectSpecial|tree.RejectImpureFunctions|tree.RejectSubqueries)
+		if desired.Family() == types.BoolFamily {
+			return tree.TypeCheckAndRequire(expr, &semaCtx, desired, context)
+		}
+		return tree.TypeCheck(expr, &semaCtx, desired)
+	}
+
+	seen := make(map[string]struct{})
+	addColumn := func(name string, typ *types.T) error {
+		if _, ok := seen[name]; ok {
+			return errors.Errorf(`CHANGEFEED ... AS SELECT renders column %q more than once`, name)
+		}
+		seen[name] = struct{}{}
+		c.desc.Columns = append(c.desc.Columns, sqlbase.ColumnDescriptor{
+			Name:     name,
+			ID:       sqlbase.ColumnID(len(c.desc.Columns) + 1),
+			Type:     *typ,
+			Nullable: true,
+		})
+		return nil
+	}
+	for _, target := range p.sc.Exprs {
+		if star, err := isStarTarget(target, sourceName); err != nil {
+			return nil, err
+		} else if star {
+			// Hidden columns, like rowid, are omitted as they are by SELECT *.
+			for i := range tableDesc.Columns {
+				col := &tableDesc.Columns[i]
+				if col.Hidden {
+					continue
+				}
+				if err := addColumn(col.Name, &col.Type); err != nil {
+					return nil, err
+				}
+				c.exprs = append(c.exprs, ivarHelper.IndexedVar(i))
+			}
+			continue
+		}
+		name, err := tree.GetRenderColName(searchPath, target)
+		if err != nil {
+			return nil, err
+		}
+		typedExpr, err := typeCheck(target.Expr, types.Any, `CHANGEFEED ... AS SELECT`)
+		if err != nil {
+			return nil, err
+		}
+		if err := addColumn(name, typedExpr.ResolvedType()); err != nil {
+			return nil, err
+		}
+		c.exprs = append(c.exprs, typedExpr)
+	}
+	if p.sc.Where != nil {
+		var err error
+		if c.filter, err = typeCheck(p.sc.Where.Expr, types.Bool, `WHERE`); err != nil {
+			return nil, err
+		}
+	}
+
+	// The projection only targets one table, so the rows are almost always of
+	// its latest versions. Evict the oldest version to bound the cache.
+	if len(p.compiled) >= maxCompiledProjections {
+		oldest := ^tableIDAndVersion(0)
+		for k := range p.compiled {
+			if k < oldest {
+				oldest = k
+			}
+		}
+		delete(p.compiled, oldest)
+	}
+	p.compiled[cacheKey] = c
+	return c, nil
+}
+
+// isStarTarget returns whether the given SELECT target is `*` or `source.*`.
+func isStarTarget(target tree.SelectExpr, sourceName tree.Name) (bool, error) {
+	vBase, ok := target.Expr.(tree.VarName)
+	if !ok {
+		return false, nil
+	}
+	v, err := vBase.NormalizeVarName()
+	if err != nil {
+		return false, err
+	}
+	switch t := v.(type) {
+	case tree.UnqualifiedStar:
+	case *tree.AllColumnsSelector:
+		if t.TableName.NumParts != 1 || tree.Name(t.TableName.Parts[0]) != sourceName {
+			return false, errors.Errorf(`no data source matches pattern: %s`, tree.AsString(t))
+		}
+	default:
+		return false, nil
+	}
+	if target.As != "" {
+		return false, errors.Errorf(`%q cannot be aliased`, tree.AsString(v))
+	}
+	return true, nil
+}
+
+// eval returns the rendered columns of the given row of the table, or nil if
+// the row does not satisfy the WHERE clause.
+func (p *changefeedProjection) eval(
+	c *compiledProjection, row sqlbase.EncDatumRow,
+) (sqlbase.EncDatumRow, error) {
+	c.iv.row = c.iv.row[:0]
+	for i := range row {
+		if err := row[i].EnsureDecoded(&c.iv.cols[i].Type, &p.alloc); err != nil {
+			return nil, err
+		}
+		c.iv.row = append(c.iv.row, row[i].Datum)
+	}
+
+	p.evalCtx.PushIVarContainer(&c.iv)
+	defer p.evalCtx.PopIVarContainer()
+	if c.filter != nil {
+		d, err := c.filter.Eval(p.evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		if d != tree.DBoolTrue {
+			return nil, nil
+		}
+	}
+	projected := make(sqlbase.EncDatumRow, len(c.exprs))
+	for i, expr := range c.exprs {
+		d, err := expr.Eval(p.evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		projected[i] = sqlbase.DatumToEncDatum(&c.desc.Columns[i].Type, d)
+	}
+	return projected, nil
+}
+
+// projectRow renders the columns of a changed row. It returns false if the
+// row should not be emitted.
+func (p *changefeedProjection) projectRow(row *encodeRow) (bool, error) {
+	c, err := p.compile(row.tableDesc)
+	if err != nil {
+		return false, err
+	}
+	row.projection = c.desc
+
+	prevMatched := false
+	if row.prevDatums != nil {
+		if row.prevProjectedDatums, err = p.eval(c, row.prevDatums); err != nil {
+			return false, err
+		}
+		prevMatched = row.prevProjectedDatums != nil
+	}
+	if row.deleted {
+		return row.prevDatums == nil || prevMatched, nil
+	}
+	if row.projectedDatums, err = p.eval(c, row.datums); err != nil {
+		return false, err
+	}
+	if row.projectedDatums == nil {
+		// The row no longer satisfies the filter. If it did before, it is
+		// emitted as a deletion so that consumers stop tracking it.
+		row.deleted = prevMatched
+		return prevMatched, nil
+	}
+	return true, nil
+}
+
+// rowsFn wraps a closure returned by kvsToRows, projecting and filtering the
+// rows it returns.
+func (p *changefeedProjection) rowsFn(
+	inputFn func(context.Context) ([]emitEntry, error),
+) func(context.Context) ([]emitEntry, error) {
+	return func(ctx context.Context) ([]emitEntry, error) {
+		for {
+			inputs, err := inputFn(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Filter in place, the inputs are only valid until the next call.
+			output := inputs[:0]
+			for _, input := range inputs {
+				if input.row.datums != nil {
+					emit, err := p.projectRow(&input.row)
+					if err != nil {
+						return nil, err
+					}
+					if !emit {
+						if input.resolved == nil {
+							continue
+						}
+						input.row = encodeRow{}
+					}
+				}
+				output = append(output, input)
+			}
+			if len(output) > 0 {
+				return output, nil
+			}
+		}
+	}
+}
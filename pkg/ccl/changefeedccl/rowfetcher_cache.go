@@ -14,6 +14,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -28,14 +29,17 @@ import (
 type rowFetcherCache struct {
 	leaseMgr *sql.LeaseManager
 	fetchers map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher
+	// evalCtx is used to compute the virtual computed columns.
+	evalCtx *tree.EvalContext
 
 	a sqlbase.DatumAlloc
 }
 
-func newRowFetcherCache(leaseMgr *sql.LeaseManager) *rowFetcherCache {
+func newRowFetcherCache(leaseMgr *sql.LeaseManager, evalCtx *tree.EvalContext) *rowFetcherCache {
 	return &rowFetcherCache{
 		leaseMgr: leaseMgr,
 		fetchers: make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher),
+		evalCtx:  evalCtx,
 	}
 }
 
@@ -98,7 +102,7 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 
 	var rf row.Fetcher
 	if err := rf.Init(
-		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &c.a,
+		c.evalCtx, false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &c.a,
 		row.FetcherTableArgs{
 			Spans:            tableDesc.AllIndexSpans(),
 			Desc:             tableDesc,
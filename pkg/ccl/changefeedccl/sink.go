@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -65,6 +66,7 @@ type Sink interface {
 }
 
 func getSink(
+	ctx context.Context,
 	sinkURI string,
 	nodeID roachpb.NodeID,
 	opts map[string]string,
@@ -173,6 +175,68 @@ func getSink(
 		makeSink = func() (Sink, error) {
 			return makeCloudStorageSink(u.String(), nodeID, fileSize, settings, opts)
 		}
+	case u.Scheme == sinkSchemeWebhookHTTPS:
+		cfg := webhookSinkConfig{
+			batchSize:     defaultWebhookBatchSize,
+			flushInterval: defaultWebhookFlushInterval,
+			headers:       make(http.Header),
+		}
+		if batchSizeParam := q.Get(sinkParamBatchSize); batchSizeParam != `` {
+			if cfg.batchSize, err = strconv.Atoi(batchSizeParam); err != nil {
+				return nil, errors.Errorf(`param %s must be an integer: %s`, sinkParamBatchSize, err)
+			}
+			if cfg.batchSize <= 0 {
+				return nil, errors.Errorf(`param %s must be positive`, sinkParamBatchSize)
+			}
+		}
+		q.Del(sinkParamBatchSize)
+		if flushIntervalParam := q.Get(sinkParamFlushInterval); flushIntervalParam != `` {
+			if cfg.flushInterval, err = time.ParseDuration(flushIntervalParam); err != nil {
+				return nil, errors.Errorf(`param %s must be a duration: %s`, sinkParamFlushInterval, err)
+			}
+			if cfg.flushInterval < 0 {
+				return nil, errors.Errorf(`param %s must not be negative`, sinkParamFlushInterval)
+			}
+		}
+		q.Del(sinkParamFlushInterval)
+		for _, p := range []struct {
+			name string
+			dest *[]byte
+		}{
+			{sinkParamCACert, &cfg.caCert},
+			{sinkParamClientCert, &cfg.clientCert},
+			{sinkParamClientKey, &cfg.clientKey},
+		} {
+			if param := q.Get(p.name); param != `` {
+				if *p.dest, err = base64.StdEncoding.DecodeString(param); err != nil {
+					return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, p.name, err)
+				}
+			}
+			q.Del(p.name)
+		}
+		if (cfg.clientCert == nil) != (cfg.clientKey == nil) {
+			return nil, errors.Errorf(`%s and %s must be provided together`, sinkParamClientCert, sinkParamClientKey)
+		}
+		// Every parameter of the form header_<name>=<value> adds a header to the
+		// requests sent to the endpoint.
+		for param, values := range q {
+			if !strings.HasPrefix(param, sinkParamHeaderPrefix) {
+				continue
+			}
+			name := strings.TrimPrefix(param, sinkParamHeaderPrefix)
+			if name == `` {
+				return nil, errors.Errorf(`param %s must be followed by a header name`, sinkParamHeaderPrefix)
+			}
+			for _, v := range values {
+				cfg.headers.Add(name, v)
+			}
+			q.Del(param)
+		}
+		u.Scheme = `https`
+		u.RawQuery = ``
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(ctx, u.String(), cfg, opts, targets)
+		}
 	case u.Scheme == sinkSchemeExperimentalSQL:
 		// Swap the changefeed prefix for the sql connection one that sqlSink
 		// expects.
@@ -283,6 +347,7 @@ type kafkaSink struct {
 	cfg      kafkaSinkConfig
 	client   sarama.Client
 	producer sarama.AsyncProducer
+	targets  jobspb.ChangefeedTargets
 	topics   map[string]struct{}
 
 	lastMetadataRefresh time.Time
@@ -303,9 +368,12 @@ type kafkaSink struct {
 func makeKafkaSink(
 	cfg kafkaSinkConfig, bootstrapServers string, targets jobspb.ChangefeedTargets,
 ) (Sink, error) {
-	sink := &kafkaSink{cfg: cfg}
+	sink := &kafkaSink{cfg: cfg, targets: targets}
 	sink.topics = make(map[string]struct{})
 	for _, t := range targets {
+		if t.Database {
+			continue
+		}
 		sink.topics[cfg.kafkaTopicPrefix+SQLNameToKafkaName(t.StatementTimeName)] = struct{}{}
 	}
 
@@ -415,7 +483,11 @@ func (s *kafkaSink) EmitRow(
 ) error {
 	topic := s.cfg.kafkaTopicPrefix + SQLNameToKafkaName(table.Name)
 	if _, ok := s.topics[topic]; !ok {
-		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		// The tables in watched databases can be renamed.
+		if !watchedByDatabase(s.targets, table) {
+			return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		}
+		s.topics[topic] = struct{}{}
 	}
 
 	msg := &sarama.ProducerMessage{
@@ -609,6 +681,7 @@ type sqlSink struct {
 	db *gosql.DB
 
 	tableName string
+	targets   jobspb.ChangefeedTargets
 	topics    map[string]struct{}
 	hasher    hash.Hash32
 
@@ -634,10 +707,14 @@ func makeSQLSink(uri, tableName string, targets jobspb.ChangefeedTargets) (*sqlS
 	s := &sqlSink{
 		db:        db,
 		tableName: tableName,
+		targets:   targets,
 		topics:    make(map[string]struct{}),
 		hasher:    fnv.New32a(),
 	}
 	for _, t := range targets {
+		if t.Database {
+			continue
+		}
 		s.topics[t.StatementTimeName] = struct{}{}
 	}
 	return s, nil
@@ -649,7 +726,11 @@ func (s *sqlSink) EmitRow(
 ) error {
 	topic := table.Name
 	if _, ok := s.topics[topic]; !ok {
-		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		// The tables in watched databases can be renamed.
+		if !watchedByDatabase(s.targets, table) {
+			return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		}
+		s.topics[topic] = struct{}{}
 	}
 
 	// Hashing logic copied from sarama.HashPartitioner.
@@ -0,0 +1,397 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWebhookBatchSize     = 100
+	defaultWebhookFlushInterval = time.Second
+	// webhookSinkRequestTimeout bounds each attempt at delivering a batch, so
+	// that an unresponsive endpoint is retried instead of blocking forever.
+	webhookSinkRequestTimeout = 30 * time.Second
+	// webhookSinkMaxErrorBody is how much of the body of an error response is
+	// included in the returned error.
+	webhookSinkMaxErrorBody = 1 << 10
+)
+
+type webhookSinkConfig struct {
+	batchSize     int
+	flushInterval time.Duration
+	caCert        []byte
+	clientCert    []byte
+	clientKey     []byte
+	headers       http.Header
+	retryOpts     retry.Options
+}
+
+// webhookMessage is one entry in the body of a request sent by webhookSink.
+// Rows have a topic, a key and (unless the envelope is key_only) a value, while
+// resolved timestamps only have the resolved field.
+type webhookMessage struct {
+	Topic    string          `json:"topic,omitempty"`
+	Key      json.RawMessage `json:"key,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Resolved json.RawMessage `json:"resolved,omitempty"`
+}
+
+// webhookPayload is the body of a request sent by webhookSink.
+type webhookPayload struct {
+	Payload []webhookMessage `json:"payload"`
+	Length  int              `json:"length"`
+}
+
+// webhookSink emits to an HTTPS endpoint. Rows are buffered and sent as a JSON
+// POST body, in batches of at most batchSize messages, whenever a batch is
+// full, whenever flushInterval elapses, and on every Flush. Resolved timestamps
+// are sent immediately along with any buffered rows, so the endpoint has
+// received every row before the resolved timestamp that covers it. Requests
+// that fail with a 5xx status or a network error are retried with backoff.
+//
+// Requests are sent one at a time, in the order in which the messages were
+// emitted, and without holding the lock on the buffer, so rows can be buffered
+// while a request is in flight. Flush only returns once the endpoint
+// acknowledged every buffered message with a 2xx status, so the changefeed
+// never checkpoints past an undelivered row. If a request fails for good, the
+// buffer is dropped and the error is returned; the changefeed then restarts
+// from its last checkpoint and emits the dropped messages again. As with the
+// other sinks, a message may be delivered more than once.
+type webhookSink struct {
+	cfg     webhookSinkConfig
+	url     string
+	client  *http.Client
+	targets jobspb.ChangefeedTargets
+	topics  map[string]struct{}
+
+	stopWorkerCh chan struct{}
+	cancelWorker context.CancelFunc
+	worker       sync.WaitGroup
+
+	// sendMu serializes the requests, so that the messages are delivered in
+	// order. It is acquired before mu, which is only held to take the messages
+	// out of the buffer.
+	sendMu syncutil.Mutex
+
+	mu struct {
+		syncutil.Mutex
+		batch    []webhookMessage
+		scratch  bufalloc.ByteAllocator
+		flushErr error
+	}
+}
+
+func makeWebhookSink(
+	ctx context.Context,
+	url string,
+	cfg webhookSinkConfig,
+	opts map[string]string,
+	targets jobspb.ChangefeedTargets,
+) (Sink, error) {
+	// The keys and values are embedded as is in the JSON body.
+	switch formatType(opts[optFormat]) {
+	case optFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			optFormat, opts[optFormat])
+	}
+
+	tlsConfig := &tls.Config{}
+	if cfg.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`param %s does not contain a valid certificate`, sinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil {
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid %s or %s`, sinkParamClientCert, sinkParamClientKey)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.retryOpts == (retry.Options{}) {
+		cfg.retryOpts = retry.Options{
+			InitialBackoff: 500 * time.Millisecond,
+			MaxBackoff:     30 * time.Second,
+			Multiplier:     2,
+			MaxRetries:     10,
+		}
+	}
+
+	s := &webhookSink{
+		cfg: cfg,
+		url: url,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+			Timeout: webhookSinkRequestTimeout,
+		},
+		targets: targets,
+		topics:  make(map[string]struct{}),
+	}
+	for _, t := range targets {
+		if t.Database {
+			continue
+		}
+		s.topics[t.StatementTimeName] = struct{}{}
+	}
+	s.start(ctx)
+	return s, nil
+}
+
+// start starts the worker which periodically sends the buffered messages. It
+// stops when ctx is canceled or the sink is closed.
+func (s *webhookSink) start(ctx context.Context) {
+	ctx, s.cancelWorker = context.WithCancel(ctx)
+	s.stopWorkerCh = make(chan struct{})
+	if s.cfg.flushInterval > 0 {
+		s.worker.Add(1)
+		go s.workerLoop(ctx)
+	}
+}
+
+// workerLoop sends the buffered messages every flushInterval. Errors are
+// returned by the next call to EmitRow, EmitResolvedTimestamp or Flush.
+func (s *webhookSink) workerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.sendMu.Lock()
+		s.mu.Lock()
+		failed := s.mu.flushErr != nil
+		s.mu.Unlock()
+		if !failed {
+			if err := s.sendBatches(ctx, true /* all */); err != nil {
+				s.mu.Lock()
+				s.mu.flushErr = err
+				s.mu.Unlock()
+			}
+		}
+		s.sendMu.Unlock()
+	}
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, _ hlc.Timestamp,
+) error {
+	topic := table.Name
+	if _, ok := s.topics[topic]; !ok {
+		// The tables in watched databases can be renamed.
+		if !watchedByDatabase(s.targets, table) {
+			return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		}
+		s.topics[topic] = struct{}{}
+	}
+
+	s.mu.Lock()
+	if err := s.takeFlushErrLocked(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	var m webhookMessage
+	m.Topic = topic
+	s.mu.scratch, m.Key = s.mu.scratch.Copy(key, 0 /* extraCap */)
+	if value != nil {
+		s.mu.scratch, m.Value = s.mu.scratch.Copy(value, 0 /* extraCap */)
+	}
+	s.mu.batch = append(s.mu.batch, m)
+	full := len(s.mu.batch) >= s.cfg.batchSize
+	s.mu.Unlock()
+	if !full {
+		return nil
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.sendBatches(ctx, false /* all */)
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	// Unlike kafka partitions, all messages go to the same endpoint, so the
+	// resolved timestamp is only sent once.
+	var noTopic string
+	payload, err := encoder.EncodeResolvedTimestamp(noTopic, resolved)
+	if err != nil {
+		return err
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.mu.Lock()
+	if err := s.takeFlushErrLocked(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	var m webhookMessage
+	s.mu.scratch, m.Resolved = s.mu.scratch.Copy(payload, 0 /* extraCap */)
+	s.mu.batch = append(s.mu.batch, m)
+	s.mu.Unlock()
+	return s.sendBatches(ctx, true /* all */)
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.mu.Lock()
+	err := s.takeFlushErrLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.sendBatches(ctx, true /* all */)
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	s.cancelWorker()
+	close(s.stopWorkerCh)
+	s.worker.Wait()
+	s.client.Transport.(*http.Transport).CloseIdleConnections()
+	return nil
+}
+
+func (s *webhookSink) takeFlushErrLocked() error {
+	err := s.mu.flushErr
+	s.mu.flushErr = nil
+	return err
+}
+
+// sendBatches sends the buffered messages in batches of at most batchSize
+// messages. If all is false, only full batches are sent. Each batch is taken
+// out of the buffer under s.mu, which is released while it is sent. s.sendMu
+// must be held.
+func (s *webhookSink) sendBatches(ctx context.Context, all bool) error {
+	for {
+		body, err := s.takeBatch(all)
+		if err != nil || body == nil {
+			return err
+		}
+		if err := s.post(ctx, body); err != nil {
+			// The changefeed restarts from its last checkpoint after a sink
+			// error, so the buffered messages will be emitted again.
+			s.mu.Lock()
+			s.mu.batch = nil
+			s.mu.scratch = bufalloc.ByteAllocator{}
+			s.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// takeBatch removes the next batch of messages from the buffer and returns the
+// body of the request that delivers it, or nil if there is no batch to send.
+// If all is false, only a full batch is taken.
+func (s *webhookSink) takeBatch(all bool) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.mu.batch)
+	if n > s.cfg.batchSize {
+		n = s.cfg.batchSize
+	}
+	if n == 0 || (n < s.cfg.batchSize && !all) {
+		if n == 0 {
+			// Start from scratch, so the memory of the delivered messages can be
+			// reclaimed.
+			s.mu.batch = nil
+			s.mu.scratch = bufalloc.ByteAllocator{}
+		}
+		return nil, nil
+	}
+	// The body is a copy of the messages, which can then be dropped from the
+	// buffer.
+	body, err := json.Marshal(webhookPayload{Payload: s.mu.batch[:n], Length: n})
+	if err != nil {
+		return nil, err
+	}
+	s.mu.batch = s.mu.batch[n:]
+	return body, nil
+}
+
+// post sends a request to the endpoint, retrying it with backoff if it fails
+// in a way that may be transient.
+func (s *webhookSink) post(ctx context.Context, body []byte) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, s.cfg.retryOpts); r.Next(); {
+		var retryable bool
+		if retryable, err = s.postOnce(ctx, body); err == nil || !retryable {
+			return err
+		}
+		log.Warningf(ctx, "retrying webhook sink request: %v", err)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.Wrap(err, `webhook sink request failed after retries`)
+}
+
+// postOnce sends a request to the endpoint, and returns whether the error (if
+// any) is worth retrying.
+func (s *webhookSink) postOnce(ctx context.Context, body []byte) (retryable bool, _ error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	for name, values := range s.cfg.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set(`Content-Type`, `application/json`)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// Read the body to allow the connection to be reused.
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return false, nil
+	}
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webhookSinkMaxErrorBody))
+	err = errors.Errorf(`webhook sink: %s: %s`, resp.Status, bytes.TrimSpace(respBody))
+	return resp.StatusCode >= 500, err
+}
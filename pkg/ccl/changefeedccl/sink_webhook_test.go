@@ -0,0 +1,384 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/securitytest"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookEndpoint is an HTTPS server recording the payloads sent by a
+// webhookSink. Requests are answered with the queued status codes, then with
+// 200 once the queue is empty. While unblock is set, requests wait for it to be
+// closed before being answered.
+type webhookEndpoint struct {
+	srv *httptest.Server
+
+	mu struct {
+		syncutil.Mutex
+		unblock  chan struct{}
+		statuses []int
+		payloads []webhookPayload
+		headers  []http.Header
+	}
+}
+
+func makeWebhookEndpoint(t *testing.T, clientCAs *x509.CertPool) *webhookEndpoint {
+	e := &webhookEndpoint{}
+	e.srv = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.mu.Lock()
+		unblock := e.mu.unblock
+		e.mu.Unlock()
+		if unblock != nil {
+			<-unblock
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		var p webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf(`decoding payload: %+v`, err)
+		}
+		e.mu.headers = append(e.mu.headers, r.Header)
+		status := http.StatusOK
+		if len(e.mu.statuses) > 0 {
+			status, e.mu.statuses = e.mu.statuses[0], e.mu.statuses[1:]
+		}
+		if status == http.StatusOK {
+			e.mu.payloads = append(e.mu.payloads, p)
+		}
+		http.Error(w, http.StatusText(status), status)
+	}))
+	if clientCAs != nil {
+		e.srv.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+	}
+	e.srv.StartTLS()
+	return e
+}
+
+func (e *webhookEndpoint) caCert() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: e.srv.Certificate().Raw})
+}
+
+func (e *webhookEndpoint) queueStatuses(statuses ...int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mu.statuses = append(e.mu.statuses, statuses...)
+}
+
+// received returns a summary of the acknowledged payloads, one string per
+// request.
+func (e *webhookEndpoint) received() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var received []string
+	for _, p := range e.mu.payloads {
+		var s string
+		for _, m := range p.Payload {
+			if m.Resolved != nil {
+				s += fmt.Sprintf(`[resolved %s]`, m.Resolved)
+			} else {
+				s += fmt.Sprintf(`[%s %s %s]`, m.Topic, m.Key, m.Value)
+			}
+		}
+		if len(p.Payload) != p.Length {
+			s += fmt.Sprintf(`[length %d]`, p.Length)
+		}
+		received = append(received, s)
+	}
+	return received
+}
+
+func (e *webhookEndpoint) requests() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.mu.headers)
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	table := func(name string) *sqlbase.TableDescriptor {
+		return &sqlbase.TableDescriptor{Name: name}
+	}
+	ctx := context.Background()
+	opts := map[string]string{optFormat: string(optFormatJSON)}
+	targets := jobspb.ChangefeedTargets{1: jobspb.ChangefeedTarget{StatementTimeName: `t`}}
+	encoder, err := makeJSONEncoder(opts)
+	require.NoError(t, err)
+
+	e := makeWebhookEndpoint(t, nil /* clientCAs */)
+	defer e.srv.Close()
+
+	makeSink := func(cfg webhookSinkConfig) Sink {
+		cfg.caCert = e.caCert()
+		cfg.retryOpts = retry.Options{
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			MaxRetries:     2,
+		}
+		s, err := makeWebhookSink(ctx, e.srv.URL, cfg, opts, targets)
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run(`batches`, func(t *testing.T) {
+		s := makeSink(webhookSinkConfig{batchSize: 2})
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// No rows.
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, 0, e.requests())
+
+		for i := 1; i <= 5; i++ {
+			key, value := fmt.Sprintf(`[%d]`, i), fmt.Sprintf(`{"a": %d}`, i)
+			require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(key), []byte(value), zeroTS))
+		}
+		require.Equal(t, []string{
+			`[t [1] {"a":1}][t [2] {"a":2}]`,
+			`[t [3] {"a":3}][t [4] {"a":4}]`,
+		}, e.received())
+
+		require.NoError(t, s.Flush(ctx))
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[6]`), nil, zeroTS))
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 7}))
+		require.Equal(t, []string{
+			`[t [1] {"a":1}][t [2] {"a":2}]`,
+			`[t [3] {"a":3}][t [4] {"a":4}]`,
+			`[t [5] {"a":5}]`,
+			`[t [6] ][resolved {"__crdb__":{"resolved":"7.0000000000"}}]`,
+		}, e.received())
+
+		err := s.EmitRow(ctx, table(`u`), []byte(`[1]`), nil, zeroTS)
+		require.EqualError(t, err, `cannot emit to undeclared topic: u`)
+	})
+
+	t.Run(`flush interval`, func(t *testing.T) {
+		s := makeSink(webhookSinkConfig{batchSize: 100, flushInterval: time.Millisecond})
+		defer func() { require.NoError(t, s.Close()) }()
+
+		before := len(e.received())
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[8]`), nil, zeroTS))
+		testutils.SucceedsSoon(t, func() error {
+			if received := e.received(); len(received) != before+1 {
+				return errors.Errorf(`expected %d payloads got %d`, before+1, len(received))
+			}
+			return nil
+		})
+		require.Equal(t, `[t [8] ]`, e.received()[before])
+	})
+
+	t.Run(`emit during request`, func(t *testing.T) {
+		s := makeSink(webhookSinkConfig{batchSize: 100, flushInterval: time.Millisecond})
+		defer func() { require.NoError(t, s.Close()) }()
+		w := s.(*webhookSink)
+
+		unblock := make(chan struct{})
+		e.mu.Lock()
+		e.mu.unblock = unblock
+		e.mu.Unlock()
+
+		before := len(e.received())
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[14]`), nil, zeroTS))
+		// Wait for the worker to take the row out of the buffer and send it.
+		testutils.SucceedsSoon(t, func() error {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if len(w.mu.batch) != 0 {
+				return errors.New(`row not sent yet`)
+			}
+			return nil
+		})
+		// Rows are buffered while the request is in flight.
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[15]`), nil, zeroTS))
+
+		e.mu.Lock()
+		e.mu.unblock = nil
+		e.mu.Unlock()
+		close(unblock)
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{`[t [14] ]`, `[t [15] ]`}, e.received()[before:])
+	})
+
+	t.Run(`retries`, func(t *testing.T) {
+		s := makeSink(webhookSinkConfig{batchSize: 100})
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// Server errors are retried.
+		before := len(e.received())
+		e.queueStatuses(http.StatusServiceUnavailable, http.StatusInternalServerError)
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[9]`), nil, zeroTS))
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{`[t [9] ]`}, e.received()[before:])
+
+		// Until they aren't.
+		e.queueStatuses(http.StatusInternalServerError, http.StatusInternalServerError,
+			http.StatusInternalServerError)
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[10]`), nil, zeroTS))
+		if err := s.Flush(ctx); !testutils.IsError(err, `failed after retries: .*500 Internal Server Error`) {
+			t.Fatalf(`expected "500 Internal Server Error" error got: %+v`, err)
+		}
+
+		// Other errors are not retried.
+		e.queueStatuses(http.StatusBadRequest)
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[11]`), nil, zeroTS))
+		requests := e.requests()
+		if err := s.Flush(ctx); !testutils.IsError(err, `400 Bad Request`) {
+			t.Fatalf(`expected "400 Bad Request" error got: %+v`, err)
+		}
+		require.Equal(t, requests+1, e.requests())
+
+		// Check simple success again after error.
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[12]`), nil, zeroTS))
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{`[t [9] ]`, `[t [12] ]`}, e.received()[before:])
+	})
+
+	t.Run(`headers`, func(t *testing.T) {
+		headers := make(http.Header)
+		headers.Add(`Authorization`, `Bearer foo`)
+		s := makeSink(webhookSinkConfig{batchSize: 100, headers: headers})
+		defer func() { require.NoError(t, s.Close()) }()
+
+		requests := e.requests()
+		require.NoError(t, s.EmitRow(ctx, table(`t`), []byte(`[13]`), nil, zeroTS))
+		require.NoError(t, s.Flush(ctx))
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		require.Equal(t, `Bearer foo`, e.mu.headers[requests].Get(`Authorization`))
+		require.Equal(t, `application/json`, e.mu.headers[requests].Get(`Content-Type`))
+	})
+}
+
+func TestWebhookSinkClientCert(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	readCert := func(name string) []byte {
+		b, err := securitytest.EmbeddedAssets.ReadFile(filepath.Join(security.EmbeddedCertsDir, name))
+		require.NoError(t, err)
+		return b
+	}
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(readCert(security.EmbeddedCACert)))
+	e := makeWebhookEndpoint(t, clientCAs)
+	defer e.srv.Close()
+
+	ctx := context.Background()
+	opts := map[string]string{optFormat: string(optFormatJSON)}
+	targets := jobspb.ChangefeedTargets{1: jobspb.ChangefeedTarget{StatementTimeName: `t`}}
+	table := &sqlbase.TableDescriptor{Name: `t`}
+
+	sinkURI := func(withClientCert bool) string {
+		u, err := url.Parse(e.srv.URL)
+		require.NoError(t, err)
+		u.Scheme = sinkSchemeWebhookHTTPS
+		q := u.Query()
+		q.Set(sinkParamCACert, base64.StdEncoding.EncodeToString(e.caCert()))
+		if withClientCert {
+			q.Set(sinkParamClientCert, base64.StdEncoding.EncodeToString(readCert(security.EmbeddedRootCert)))
+			q.Set(sinkParamClientKey, base64.StdEncoding.EncodeToString(readCert(security.EmbeddedRootKey)))
+		}
+		q.Set(sinkParamBatchSize, `1`)
+		q.Set(sinkParamFlushInterval, `0s`)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	// Without a client certificate, the handshake fails.
+	s, err := getSink(ctx, sinkURI(false), 0 /* nodeID */, opts, targets, nil /* settings */)
+	require.NoError(t, err)
+	s.(*webhookSink).cfg.retryOpts.MaxRetries = 1
+	if err := s.EmitRow(ctx, table, []byte(`[1]`), nil, zeroTS); !testutils.IsError(
+		err, `bad certificate|certificate required|EOF|connection reset`,
+	) {
+		t.Fatalf(`expected "bad certificate" error got: %+v`, err)
+	}
+	require.NoError(t, s.Close())
+
+	s, err = getSink(ctx, sinkURI(true), 0 /* nodeID */, opts, targets, nil /* settings */)
+	require.NoError(t, err)
+	require.NoError(t, s.EmitRow(ctx, table, []byte(`[2]`), nil, zeroTS))
+	require.NoError(t, s.Close())
+	require.Equal(t, []string{`[t [2] ]`}, e.received())
+}
+
+func TestWebhookSinkParams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	opts := map[string]string{optFormat: string(optFormatJSON)}
+	targets := jobspb.ChangefeedTargets{1: jobspb.ChangefeedTarget{StatementTimeName: `t`}}
+
+	tests := []struct {
+		params string
+		err    string
+	}{
+		{`batch_size=0`, `param batch_size must be positive`},
+		{`batch_size=a`, `param batch_size must be an integer`},
+		{`flush_interval=a`, `param flush_interval must be a duration`},
+		{`flush_interval=-1s`, `param flush_interval must not be negative`},
+		{`ca_cert=!`, `param ca_cert must be base 64 encoded`},
+		{`ca_cert=Zm9v`, `param ca_cert does not contain a valid certificate`},
+		{`client_cert=Zm9v`, `client_cert and client_key must be provided together`},
+		{`client_cert=Zm9v&client_key=Zm9v`, `invalid client_cert or client_key`},
+		{`header_=a`, `param header_ must be followed by a header name`},
+		{`foo=bar`, `unknown sink query parameter: foo`},
+	}
+	for _, test := range tests {
+		t.Run(test.params, func(t *testing.T) {
+			sinkURI := `webhook-https://localhost/?` + test.params
+			_, err := getSink(ctx, sinkURI, 0 /* nodeID */, opts, targets, nil /* settings */)
+			if !testutils.IsError(err, test.err) {
+				t.Fatalf(`expected %q error got: %+v`, test.err, err)
+			}
+		})
+	}
+
+	s, err := getSink(
+		ctx, `webhook-https://localhost:8080/path?header_X-Foo=bar&batch_size=10&flush_interval=5s`,
+		0 /* nodeID */, opts, targets, nil, /* settings */
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+	w := s.(*webhookSink)
+	require.Equal(t, `https://localhost:8080/path`, w.url)
+	require.Equal(t, 10, w.cfg.batchSize)
+	require.Equal(t, 5*time.Second, w.cfg.flushInterval)
+	require.Equal(t, `bar`, w.cfg.headers.Get(`X-Foo`))
+
+	opts = map[string]string{optFormat: string(optFormatAvro)}
+	_, err = getSink(ctx, `webhook-https://localhost/`, 0 /* nodeID */, opts, targets, nil /* settings */)
+	require.EqualError(t, err, `this sink is incompatible with format=experimental_avro`)
+}
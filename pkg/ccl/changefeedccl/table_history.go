@@ -248,6 +248,13 @@ func fetchTableDescriptorVersions(
 			`fetching changes for %s`, span)
 	}
 
+	// The descriptors of tables in watched databases are needed too, including
+	// the ones of tables that are not targets yet.
+	var watchesDatabase bool
+	for _, t := range targets {
+		watchesDatabase = watchesDatabase || t.Database
+	}
+
 	var tableDescs []*sqlbase.TableDescriptor
 	for _, file := range res.(*roachpb.ExportResponse).Files {
 		if err := func() error {
@@ -271,12 +278,18 @@ func fetchTableDescriptorVersions(
 					return err
 				}
 				origName, ok := targets[sqlbase.ID(tableID)]
-				if !ok {
+				if !ok && !watchesDatabase {
 					// Uninteresting table.
 					continue
 				}
 				unsafeValue := it.UnsafeValue()
 				if unsafeValue == nil {
+					if watchesDatabase {
+						// A dropped table is seen in the DROP state before its
+						// descriptor is deleted, so the drop was already
+						// handled.
+						continue
+					}
 					return errors.Errorf(`"%v" was dropped or truncated`, origName)
 				}
 				value := roachpb.Value{RawBytes: unsafeValue}
@@ -285,6 +298,10 @@ func fetchTableDescriptorVersions(
 					return err
 				}
 				if tableDesc := desc.GetTable(); tableDesc != nil {
+					if !ok && !watchedByDatabase(targets, tableDesc) {
+						// Uninteresting table.
+						continue
+					}
 					tableDescs = append(tableDescs, tableDesc)
 				}
 			}
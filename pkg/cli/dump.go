@@ -633,6 +633,16 @@ func dumpTableData(w io.Writer, conn *sqlConn, clusterTS string, bmd basicMetada
 						if err != nil {
 							return err
 						}
+					case types.GeometryFamily:
+						d, err = tree.ParseDGeometry(string(t))
+						if err != nil {
+							return err
+						}
+					case types.GeographyFamily:
+						d, err = tree.ParseDGeography(string(t))
+						if err != nil {
+							return err
+						}
 					case types.ArrayFamily:
 						// We can only observe ARRAY types by their [] suffix.
 						d, err = tree.ParseDArrayFromString(
@@ -664,6 +674,9 @@ func dumpTableData(w io.Writer, conn *sqlConn, clusterTS string, bmd basicMetada
 					case types.TimeFamily:
 						// pq awkwardly represents TIME as a time.Time with date 0000-01-01.
 						d = tree.MakeDTime(timeofday.FromTime(t))
+					case types.TimeTZFamily:
+						// Likewise for TIMETZ, which also carries the offset.
+						d = tree.MakeDTimeTZFromTime(t)
 					case types.TimestampFamily:
 						d = tree.MakeDTimestamp(t, time.Nanosecond)
 					case types.TimestampTZFamily:
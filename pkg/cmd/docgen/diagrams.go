@@ -590,7 +590,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:   "delete_stmt",
-		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_name_expr_opt_alias_idx", "table_name_expr_with_index", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause"},
+		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_name_expr_opt_alias_idx", "table_name_expr_with_index", "opt_using_clause", "from_list", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause"},
 		replace: map[string]string{
 			"relation_expr": "table_name",
 		},
@@ -749,16 +749,16 @@ var specs = []stmtSpec{
 			"'TO' ( ( name ) ( ( ',' name ) )*": "'TO' ( ( user_name ) ( ( ',' user_name ) )*",
 			"| 'GRANT' ( ( ( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' ) ) ( ( ',' ( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' ) ) )* ) 'TO' ( ( user_name ) ( ( ',' user_name ) )* )": "",
 			"'WITH' 'ADMIN' 'OPTION'": "",
-			"targets":                 "( ( 'TABLE' | ) table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* )",
+			"grant_targets":           "( ( 'TABLE' | ) table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* | 'SCHEMA' schema_name ( ( ',' schema_name ) )* )",
 		},
-		unlink:  []string{"table_name", "database_name", "user_name"},
+		unlink:  []string{"table_name", "database_name", "schema_name", "user_name"},
 		nosplit: true,
 	},
 	{
 		name: "grant_roles",
 		stmt: "grant_stmt",
 		replace: map[string]string{
-			"'GRANT' privileges 'ON' targets 'TO' name_list":                "",
+			"'GRANT' privileges 'ON' grant_targets 'TO' name_list":          "",
 			"'GRANT' privilege_list 'TO' name_list 'WITH' 'ADMIN' 'OPTION'": "'GRANT' ( role_name ) ( ( ',' role_name ) )* 'TO' ( user_name ) ( ( ',' user_name ) )* 'WITH' 'ADMIN' 'OPTION'",
 			"| 'GRANT' privilege_list 'TO' name_list":                       "'GRANT' ( role_name ) ( ( ',' role_name ) )* 'TO' ( user_name ) ( ( ',' user_name ) )*",
 		},
@@ -926,20 +926,20 @@ var specs = []stmtSpec{
 		inline: []string{"privileges", "privilege_list", "privilege", "name_list"},
 		replace: map[string]string{
 			"( name | 'CREATE' | 'GRANT' | 'SELECT' )": "( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' )",
-			"targets":                             "( ( 'TABLE' | ) table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* )",
+			"grant_targets":                       "( ( 'TABLE' | ) table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* | 'SCHEMA' schema_name ( ( ',' schema_name ) )* )",
 			"'FROM' ( ( name ) ( ( ',' name ) )*": "'FROM' ( ( user_name ) ( ( ',' user_name ) )*",
 			"| 'REVOKE' ( ( ( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' ) ) ( ( ',' ( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' ) ) )* ) 'FROM' ( ( user_name ) ( ( ',' user_name ) )* )":  "",
 			"| 'REVOKE'  ( ( ( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' ) ) ( ( ',' ( 'CREATE' | 'GRANT' | 'SELECT' | 'DROP' | 'INSERT' | 'DELETE' | 'UPDATE' ) ) )* ) 'FROM' ( ( user_name ) ( ( ',' user_name ) )* )": "",
 			"'ADMIN' 'OPTION' 'FOR'": "",
 		},
-		unlink:  []string{"table_name", "database_name", "user_name"},
+		unlink:  []string{"table_name", "database_name", "schema_name", "user_name"},
 		nosplit: true,
 	},
 	{
 		name: "revoke_roles",
 		stmt: "revoke_stmt",
 		replace: map[string]string{
-			"'REVOKE' privileges 'ON' targets 'FROM' name_list":               "",
+			"'REVOKE' privileges 'ON' grant_targets 'FROM' name_list":         "",
 			"'REVOKE' 'ADMIN' 'OPTION' 'FOR' privilege_list 'FROM' name_list": "'REVOKE' 'ADMIN' 'OPTION' 'FOR' ( role_name ) ( ( ',' role_name ) )* 'FROM' ( user_name ) ( ( ',' user_name ) )*",
 			"| 'REVOKE' privilege_list 'FROM' name_list":                      "'REVOKE' ( role_name ) ( ( ',' role_name ) )* 'FROM' ( user_name ) ( ( ',' user_name ) )*",
 		},
@@ -978,7 +978,7 @@ var specs = []stmtSpec{
 		replace: map[string]string{
 			"( simple_select |":    "(",
 			"| select_with_parens": "",
-			"select_clause sort_clause | select_clause ( sort_clause |  ) ( limit_clause offset_clause | offset_clause limit_clause | limit_clause | offset_clause )":                                                                                                                                           "select_clause ( sort_clause | ) ( limit_clause | ) ( offset_clause | )",
+			"select_clause sort_clause | select_clause ( sort_clause |  ) for_locking_clause opt_select_limit | select_clause ( sort_clause |  ) ( limit_clause offset_clause | offset_clause limit_clause | limit_clause | offset_clause ) opt_for_locking_clause":                                             "select_clause ( sort_clause | ) ( limit_clause | ) ( offset_clause | ) ( for_locking_clause | )",
 			"| ( 'WITH' ( ( common_table_expr ) ( ( ',' common_table_expr ) )* ) ) select_clause sort_clause | ( 'WITH' ( ( common_table_expr ) ( ( ',' common_table_expr ) )* ) ) select_clause ( sort_clause |  ) ( limit_clause offset_clause | offset_clause limit_clause | limit_clause | offset_clause )": "( sort_clause | ) ( limit_clause | ) ( offset_clause | )",
 		},
 		unlink:  []string{"index_name"},
@@ -1250,6 +1250,8 @@ var specs = []stmtSpec{
 			"expr_list",
 			"expr_tuple1_ambiguous",
 			"tuple1_ambiguous_values",
+			"update_from_clause",
+			"from_list",
 			"opt_where_clause",
 			"where_clause",
 			"opt_sort_clause",
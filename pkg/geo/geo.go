@@ -0,0 +1,343 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+// Package geo contains the spatial objects backing the GEOMETRY and
+// GEOGRAPHY SQL types, along with their text and binary representations
+// (WKT, WKB and GeoJSON), the spatial predicates and measurements exposed
+// as ST_* builtins, and the cell decomposition used by spatial inverted
+// indexes.
+//
+// Only two-dimensional objects are supported. Geometries are planar and
+// their measurements are in the units of their coordinates. Geographies
+// use longitude/latitude coordinates in degrees on a sphere and their
+// measurements are in meters.
+package geo
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultGeographySRID is the SRID of geographies which do not specify one,
+// corresponding to WGS 84 longitude/latitude coordinates.
+const DefaultGeographySRID = 4326
+
+// ShapeType identifies the kind of a Shape. The values match the geometry
+// type codes used by WKB.
+type ShapeType uint32
+
+// The supported shape types.
+const (
+	PointType              ShapeType = 1
+	LineStringType         ShapeType = 2
+	PolygonType            ShapeType = 3
+	MultiPointType         ShapeType = 4
+	MultiLineStringType    ShapeType = 5
+	MultiPolygonType       ShapeType = 6
+	GeometryCollectionType ShapeType = 7
+)
+
+var shapeTypeNames = [...]string{
+	PointType:              "Point",
+	LineStringType:         "LineString",
+	PolygonType:            "Polygon",
+	MultiPointType:         "MultiPoint",
+	MultiLineStringType:    "MultiLineString",
+	MultiPolygonType:       "MultiPolygon",
+	GeometryCollectionType: "GeometryCollection",
+}
+
+// String returns the name of the shape type as used by GeoJSON, e.g.
+// "LineString".
+func (t ShapeType) String() string {
+	if t == 0 || int(t) >= len(shapeTypeNames) {
+		return "Unknown"
+	}
+	return shapeTypeNames[t]
+}
+
+// Coord is a position in two dimensions. For geographies, X is the
+// longitude and Y the latitude, both in degrees.
+type Coord struct {
+	X, Y float64
+}
+
+// Shape is a spatial object without any spatial reference system.
+type Shape interface {
+	// Type returns the kind of the shape.
+	Type() ShapeType
+	// IsEmpty returns whether the shape contains no points at all.
+	IsEmpty() bool
+}
+
+// Point is a single position, or the empty point.
+type Point struct {
+	Coord
+	Empty bool
+}
+
+// LineString is a sequence of at least two positions joined by straight
+// segments (planar) or great circle arcs (spherical). A LineString with no
+// positions is empty.
+type LineString []Coord
+
+// Polygon is a sequence of closed rings. The first ring is the exterior
+// boundary and the remaining rings are holes. A Polygon without rings is
+// empty.
+type Polygon []LineString
+
+// MultiPoint is a collection of points.
+type MultiPoint []Coord
+
+// MultiLineString is a collection of line strings.
+type MultiLineString []LineString
+
+// MultiPolygon is a collection of polygons.
+type MultiPolygon []Polygon
+
+// GeometryCollection is a heterogeneous collection of shapes.
+type GeometryCollection []Shape
+
+// Type implements the Shape interface.
+func (Point) Type() ShapeType { return PointType }
+
+// Type implements the Shape interface.
+func (LineString) Type() ShapeType { return LineStringType }
+
+// Type implements the Shape interface.
+func (Polygon) Type() ShapeType { return PolygonType }
+
+// Type implements the Shape interface.
+func (MultiPoint) Type() ShapeType { return MultiPointType }
+
+// Type implements the Shape interface.
+func (MultiLineString) Type() ShapeType { return MultiLineStringType }
+
+// Type implements the Shape interface.
+func (MultiPolygon) Type() ShapeType { return MultiPolygonType }
+
+// Type implements the Shape interface.
+func (GeometryCollection) Type() ShapeType { return GeometryCollectionType }
+
+// IsEmpty implements the Shape interface.
+func (p Point) IsEmpty() bool { return p.Empty }
+
+// IsEmpty implements the Shape interface.
+func (l LineString) IsEmpty() bool { return len(l) == 0 }
+
+// IsEmpty implements the Shape interface.
+func (p Polygon) IsEmpty() bool { return len(p) == 0 }
+
+// IsEmpty implements the Shape interface.
+func (m MultiPoint) IsEmpty() bool { return len(m) == 0 }
+
+// IsEmpty implements the Shape interface.
+func (m MultiLineString) IsEmpty() bool {
+	for _, l := range m {
+		if !l.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty implements the Shape interface.
+func (m MultiPolygon) IsEmpty() bool {
+	for _, p := range m {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty implements the Shape interface.
+func (c GeometryCollection) IsEmpty() bool {
+	for _, s := range c {
+		if !s.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// forEachCoord calls fn on every position of the shape.
+func forEachCoord(s Shape, fn func(Coord)) {
+	switch t := s.(type) {
+	case Point:
+		if !t.Empty {
+			fn(t.Coord)
+		}
+	case LineString:
+		for _, c := range t {
+			fn(c)
+		}
+	case Polygon:
+		for _, r := range t {
+			forEachCoord(r, fn)
+		}
+	case MultiPoint:
+		for _, c := range t {
+			fn(c)
+		}
+	case MultiLineString:
+		for _, l := range t {
+			forEachCoord(l, fn)
+		}
+	case MultiPolygon:
+		for _, p := range t {
+			forEachCoord(p, fn)
+		}
+	case GeometryCollection:
+		for _, c := range t {
+			forEachCoord(c, fn)
+		}
+	}
+}
+
+// Geometry is a planar spatial object.
+type Geometry struct {
+	// SRID identifies the spatial reference system of the coordinates. Zero
+	// means unknown.
+	SRID  int32
+	Shape Shape
+}
+
+// Geography is a spatial object on the surface of the earth, which is
+// modeled as a sphere. Its coordinates are longitudes and latitudes in
+// degrees.
+type Geography struct {
+	// SRID identifies the spatial reference system of the coordinates. It is
+	// always a longitude/latitude system, DefaultGeographySRID unless
+	// specified otherwise.
+	SRID  int32
+	Shape Shape
+}
+
+// IsEmpty returns whether the geometry contains no points at all.
+func (g Geometry) IsEmpty() bool { return g.Shape.IsEmpty() }
+
+// IsEmpty returns whether the geography contains no points at all.
+func (g Geography) IsEmpty() bool { return g.Shape.IsEmpty() }
+
+// NumPoints returns the number of positions of the geometry.
+func (g Geometry) NumPoints() int { return numPoints(g.Shape) }
+
+// NumPoints returns the number of positions of the geography.
+func (g Geography) NumPoints() int { return numPoints(g.Shape) }
+
+func numPoints(s Shape) int {
+	n := 0
+	forEachCoord(s, func(Coord) { n++ })
+	return n
+}
+
+// AsGeography converts the geometry to a geography, interpreting its
+// coordinates as longitudes and latitudes.
+func (g Geometry) AsGeography() (Geography, error) {
+	srid := g.SRID
+	if srid == 0 {
+		srid = DefaultGeographySRID
+	}
+	return NewGeography(srid, g.Shape)
+}
+
+// AsGeometry converts the geography to a planar geometry with the same
+// coordinates.
+func (g Geography) AsGeometry() Geometry {
+	return Geometry{SRID: g.SRID, Shape: g.Shape}
+}
+
+// NewGeography returns a geography after validating that the SRID is a
+// longitude/latitude system and that the coordinates are in range. An SRID
+// of zero is replaced with DefaultGeographySRID.
+func NewGeography(srid int32, s Shape) (Geography, error) {
+	if srid == 0 {
+		srid = DefaultGeographySRID
+	}
+	if srid != DefaultGeographySRID {
+		return Geography{}, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"SRID %d is not a supported longitude/latitude coordinate system for GEOGRAPHY", srid)
+	}
+	var err error
+	forEachCoord(s, func(c Coord) {
+		if err == nil && (c.X < -180 || c.X > 180 || c.Y < -90 || c.Y > 90) {
+			err = pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+				"coordinate (%s %s) is out of range [-180 -90, 180 90] for GEOGRAPHY",
+				formatFloat(c.X), formatFloat(c.Y))
+		}
+	})
+	if err != nil {
+		return Geography{}, err
+	}
+	return Geography{SRID: srid, Shape: s}, nil
+}
+
+// ParseGeometry parses a geometry from any of its text representations:
+// WKT, EWKT (WKT prefixed with "SRID=<srid>;"), hex-encoded WKB or EWKB, or
+// GeoJSON.
+func ParseGeometry(s string) (Geometry, error) {
+	srid, shape, err := parseShape(s)
+	if err != nil {
+		return Geometry{}, err
+	}
+	return Geometry{SRID: srid, Shape: shape}, nil
+}
+
+// ParseGeography parses a geography from the same representations accepted
+// by ParseGeometry.
+func ParseGeography(s string) (Geography, error) {
+	srid, shape, err := parseShape(s)
+	if err != nil {
+		return Geography{}, err
+	}
+	return NewGeography(srid, shape)
+}
+
+// parseShape detects the representation of s and parses it.
+func parseShape(s string) (int32, Shape, error) {
+	t := strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(t, "{"):
+		shape, err := parseGeoJSON([]byte(t))
+		if err != nil {
+			return 0, nil, err
+		}
+		return DefaultGeographySRID, shape, nil
+	case isHexWKB(t):
+		return parseHexEWKB(t)
+	default:
+		return parseEWKT(t)
+	}
+}
+
+// Compare orders two geometries by their EWKB representation.
+func (g Geometry) Compare(o Geometry) int {
+	return bytes.Compare(g.EWKB(), o.EWKB())
+}
+
+// Compare orders two geographies by their EWKB representation.
+func (g Geography) Compare(o Geography) int {
+	return bytes.Compare(g.EWKB(), o.EWKB())
+}
+
+// checkSRIDs returns an error if two objects with the given SRIDs cannot be
+// combined.
+func checkSRIDs(a, b int32) error {
+	if a != b {
+		return pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"operation on mixed SRIDs: %d != %d", a, b)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGeometryRoundTrip(t *testing.T) {
+	testCases := []struct {
+		input string
+		ewkt  string
+	}{
+		{`POINT(1 2)`, `POINT(1 2)`},
+		{`point ( -1.5  2e3 )`, `POINT(-1.5 2000)`},
+		{`SRID=4326;POINT(1 2)`, `SRID=4326;POINT(1 2)`},
+		{`POINT EMPTY`, `POINT EMPTY`},
+		{`LINESTRING(0 0, 1 1, 2 0)`, `LINESTRING(0 0,1 1,2 0)`},
+		{`LINESTRING EMPTY`, `LINESTRING EMPTY`},
+		{`POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))`, `POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))`},
+		{`MULTIPOINT(0 0, 1 1)`, `MULTIPOINT(0 0,1 1)`},
+		{`MULTIPOINT((0 0), (1 1))`, `MULTIPOINT(0 0,1 1)`},
+		{`MULTILINESTRING((0 0,1 1),EMPTY)`, `MULTILINESTRING((0 0,1 1),EMPTY)`},
+		{`MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))`, `MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))`},
+		{`GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(0 0,1 1))`, `GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(0 0,1 1))`},
+		{`GEOMETRYCOLLECTION EMPTY`, `GEOMETRYCOLLECTION EMPTY`},
+		// Hex EWKB.
+		{`0101000020E6100000000000000000F03F0000000000000040`, `SRID=4326;POINT(1 2)`},
+		{`000000000140000000000000004010000000000000`, `POINT(2 4)`},
+		// GeoJSON.
+		{`{"type":"Point","coordinates":[1,2]}`, `SRID=4326;POINT(1 2)`},
+		{`{"type":"GeometryCollection","geometries":[{"type":"LineString","coordinates":[[0,0],[1,1]]}]}`,
+			`SRID=4326;GEOMETRYCOLLECTION(LINESTRING(0 0,1 1))`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			g, err := ParseGeometry(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := g.EWKT(); s != tc.ewkt {
+				t.Fatalf("expected %s, got %s", tc.ewkt, s)
+			}
+			// Round trip through the other representations.
+			for _, repr := range []string{
+				g.EWKT(), strings.ToUpper(hex.EncodeToString(g.EWKB())),
+			} {
+				g2, err := ParseGeometry(repr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(g, g2) {
+					t.Fatalf("%s: expected %#v, got %#v", repr, g, g2)
+				}
+			}
+			g2, err := ParseGeometryFromGeoJSON([]byte(g.GeoJSON(DefaultGeoJSONDecimalDigits)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g2.WKT() != g.WKT() {
+				t.Fatalf("expected %s, got %s", g.WKT(), g2.WKT())
+			}
+		})
+	}
+}
+
+func TestParseGeometryError(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   string
+	}{
+		{`POINT(1)`, `invalid number`},
+		{`POINT(1 2 3)`, `only two dimensional`},
+		{`POINT Z (1 2 3)`, `only two dimensional`},
+		{`LINESTRING(0 0)`, `at least two points`},
+		{`POLYGON((0 0,1 0,1 1,0 1))`, `must be closed`},
+		{`POLYGON((0 0,1 0,0 0))`, `at least four points`},
+		{`CIRCLE(1 2)`, `unknown shape type CIRCLE`},
+		{`POINT(1 2) x`, `unexpected`},
+		{`SRID=x;POINT(1 2)`, `invalid SRID`},
+		{`0101000000000000000000F03F`, `unexpected end of input`},
+		{`0101000080000000000000F03F00000000000000400000000000000040`, `only two dimensional`},
+		{`{"type":"Circle","coordinates":[1,2]}`, `unknown geometry type`},
+		{`{"type":"Point"}`, `missing coordinates`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := ParseGeometry(tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestParseGeography(t *testing.T) {
+	g, err := ParseGeography(`POINT(-73.98 40.75)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.SRID != DefaultGeographySRID {
+		t.Fatalf("expected SRID %d, got %d", DefaultGeographySRID, g.SRID)
+	}
+	if _, err := ParseGeography(`POINT(200 0)`); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Fatalf("expected out of range error, got %v", err)
+	}
+	if _, err := ParseGeography(`SRID=3857;POINT(1 2)`); err == nil || !strings.Contains(err.Error(), "SRID 3857") {
+		t.Fatalf("expected SRID error, got %v", err)
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	testCases := []struct {
+		wkt     string
+		digits  int
+		geojson string
+	}{
+		{`POINT(1.123456789012 2)`, 9, `{"type":"Point","coordinates":[1.123456789,2]}`},
+		{`POINT(1.123456789012 2)`, 2, `{"type":"Point","coordinates":[1.12,2]}`},
+		{`POINT EMPTY`, 9, `{"type":"Point","coordinates":[]}`},
+		{`POLYGON((0 0,1 0,1 1,0 0))`, 9, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`},
+		{`MULTIPOLYGON(((0 0,1 0,1 1,0 0)))`, 9, `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]]]}`},
+		{`GEOMETRYCOLLECTION(POINT(1 2))`, 9, `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}]}`},
+	}
+	for _, tc := range testCases {
+		g, err := ParseGeometry(tc.wkt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := g.GeoJSON(tc.digits); s != tc.geojson {
+			t.Errorf("%s: expected %s, got %s", tc.wkt, tc.geojson, s)
+		}
+	}
+}
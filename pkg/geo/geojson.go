@@ -0,0 +1,275 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultGeoJSONDecimalDigits is the default number of decimal digits
+// retained for coordinates in GeoJSON output.
+const DefaultGeoJSONDecimalDigits = 9
+
+// GeoJSON returns the GeoJSON representation of the geometry, with
+// coordinates rounded to the given number of decimal digits.
+func (g Geometry) GeoJSON(decimalDigits int) string { return formatGeoJSON(g.Shape, decimalDigits) }
+
+// GeoJSON returns the GeoJSON representation of the geography, with
+// coordinates rounded to the given number of decimal digits.
+func (g Geography) GeoJSON(decimalDigits int) string { return formatGeoJSON(g.Shape, decimalDigits) }
+
+// ParseGeometryFromGeoJSON decodes a geometry from a GeoJSON geometry
+// object. The SRID is always DefaultGeographySRID, as mandated by RFC 7946.
+func ParseGeometryFromGeoJSON(b []byte) (Geometry, error) {
+	shape, err := parseGeoJSON(b)
+	if err != nil {
+		return Geometry{}, err
+	}
+	return Geometry{SRID: DefaultGeographySRID, Shape: shape}, nil
+}
+
+func formatGeoJSON(s Shape, decimalDigits int) string {
+	var buf strings.Builder
+	writeGeoJSON(&buf, s, decimalDigits)
+	return buf.String()
+}
+
+func writeGeoJSON(buf *strings.Builder, s Shape, digits int) {
+	buf.WriteString(`{"type":"`)
+	buf.WriteString(s.Type().String())
+	if c, ok := s.(GeometryCollection); ok {
+		buf.WriteString(`","geometries":[`)
+		for i, e := range c {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeGeoJSON(buf, e, digits)
+		}
+		buf.WriteString("]}")
+		return
+	}
+	buf.WriteString(`","coordinates":`)
+	switch t := s.(type) {
+	case Point:
+		if t.Empty {
+			buf.WriteString("[]")
+		} else {
+			writeGeoJSONCoord(buf, t.Coord, digits)
+		}
+	case LineString:
+		writeGeoJSONCoords(buf, t, digits)
+	case Polygon:
+		writeGeoJSONRings(buf, t, digits)
+	case MultiPoint:
+		writeGeoJSONCoords(buf, t, digits)
+	case MultiLineString:
+		writeGeoJSONRings(buf, t, digits)
+	case MultiPolygon:
+		buf.WriteByte('[')
+		for i, p := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeGeoJSONRings(buf, p, digits)
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+}
+
+func writeGeoJSONRings(buf *strings.Builder, rings []LineString, digits int) {
+	buf.WriteByte('[')
+	for i, r := range rings {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeGeoJSONCoords(buf, r, digits)
+	}
+	buf.WriteByte(']')
+}
+
+func writeGeoJSONCoords(buf *strings.Builder, coords []Coord, digits int) {
+	buf.WriteByte('[')
+	for i, c := range coords {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeGeoJSONCoord(buf, c, digits)
+	}
+	buf.WriteByte(']')
+}
+
+func writeGeoJSONCoord(buf *strings.Builder, c Coord, digits int) {
+	buf.WriteByte('[')
+	buf.WriteString(formatFloat(roundDigits(c.X, digits)))
+	buf.WriteByte(',')
+	buf.WriteString(formatFloat(roundDigits(c.Y, digits)))
+	buf.WriteByte(']')
+}
+
+// roundDigits rounds f to the given number of decimal digits.
+func roundDigits(f float64, digits int) float64 {
+	if digits < 0 || digits > 15 {
+		return f
+	}
+	scale := math.Pow10(digits)
+	r := math.Round(f*scale) / scale
+	if math.IsInf(r, 0) || math.IsNaN(r) {
+		return f
+	}
+	return r
+}
+
+// geoJSONObject is the subset of a GeoJSON geometry object that we decode.
+type geoJSONObject struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Geometries  []json.RawMessage `json:"geometries"`
+}
+
+func geoJSONError(format string, args ...interface{}) error {
+	return pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+		"invalid GeoJSON: "+format, args...)
+}
+
+// parseGeoJSON decodes a GeoJSON geometry object.
+func parseGeoJSON(b []byte) (Shape, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, geoJSONError("%v", err)
+	}
+	if obj.Type == "GeometryCollection" {
+		var gc GeometryCollection
+		for _, g := range obj.Geometries {
+			s, err := parseGeoJSON(g)
+			if err != nil {
+				return nil, err
+			}
+			gc = append(gc, s)
+		}
+		return gc, nil
+	}
+	if obj.Coordinates == nil {
+		return nil, geoJSONError("missing coordinates")
+	}
+	switch obj.Type {
+	case "Point":
+		var c []float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, geoJSONError("%v", err)
+		}
+		if len(c) == 0 {
+			return Point{Empty: true}, nil
+		}
+		coord, err := geoJSONCoord(c)
+		return Point{Coord: coord}, err
+	case "LineString":
+		var cs [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &cs); err != nil {
+			return nil, geoJSONError("%v", err)
+		}
+		return geoJSONLineString(cs)
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &rings); err != nil {
+			return nil, geoJSONError("%v", err)
+		}
+		return geoJSONPolygon(rings)
+	case "MultiPoint":
+		var cs [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &cs); err != nil {
+			return nil, geoJSONError("%v", err)
+		}
+		coords, err := geoJSONCoords(cs)
+		return MultiPoint(coords), err
+	case "MultiLineString":
+		var ls [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &ls); err != nil {
+			return nil, geoJSONError("%v", err)
+		}
+		var ml MultiLineString
+		for _, cs := range ls {
+			l, err := geoJSONLineString(cs)
+			if err != nil {
+				return nil, err
+			}
+			ml = append(ml, l)
+		}
+		return ml, nil
+	case "MultiPolygon":
+		var ps [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &ps); err != nil {
+			return nil, geoJSONError("%v", err)
+		}
+		var mp MultiPolygon
+		for _, rings := range ps {
+			p, err := geoJSONPolygon(rings)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, p)
+		}
+		return mp, nil
+	default:
+		return nil, geoJSONError("unknown geometry type %q", obj.Type)
+	}
+}
+
+func geoJSONCoord(c []float64) (Coord, error) {
+	if len(c) != 2 {
+		return Coord{}, geoJSONError("only two dimensional positions are supported")
+	}
+	return Coord{X: c[0], Y: c[1]}, nil
+}
+
+func geoJSONCoords(cs [][]float64) ([]Coord, error) {
+	var coords []Coord
+	for _, c := range cs {
+		coord, err := geoJSONCoord(c)
+		if err != nil {
+			return nil, err
+		}
+		coords = append(coords, coord)
+	}
+	return coords, nil
+}
+
+func geoJSONLineString(cs [][]float64) (LineString, error) {
+	coords, err := geoJSONCoords(cs)
+	if err != nil {
+		return nil, err
+	}
+	if len(coords) == 1 {
+		return nil, geoJSONError("LineString requires at least two positions")
+	}
+	return LineString(coords), nil
+}
+
+func geoJSONPolygon(rings [][][]float64) (Polygon, error) {
+	var poly Polygon
+	for _, r := range rings {
+		coords, err := geoJSONCoords(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRing(coords); err != nil {
+			return nil, err
+		}
+		poly = append(poly, coords)
+	}
+	return poly, nil
+}
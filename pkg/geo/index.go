@@ -0,0 +1,288 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Spatial inverted indexes divide a bounded region of the plane into a
+// quadtree of cells. Each object is indexed under a single cell: the
+// smallest cell that contains its bounding box. Objects whose bounding box
+// is not within the region are indexed under the root cell.
+//
+// Cells are identified by a CellID, which numbers the cells such that the
+// IDs of the descendants of a cell form a contiguous range around the ID of
+// the cell itself. The objects that may intersect a query region are then
+// found by scanning, for each cell of a covering of the region, the range of
+// its descendants plus the IDs of each of its ancestors.
+//
+// The bounds of the region and the numbering of the cells are part of the
+// on-disk format of spatial indexes and must never change.
+
+// BoundingBox is an axis-aligned rectangle.
+type BoundingBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// geometryIndexBounds is the region covered by the cells of geometry
+// indexes.
+var geometryIndexBounds = BoundingBox{MinX: -1 << 25, MinY: -1 << 25, MaxX: 1 << 25, MaxY: 1 << 25}
+
+// geographyIndexBounds is the region covered by the cells of geography
+// indexes, in degrees of longitude and latitude.
+var geographyIndexBounds = BoundingBox{MinX: -180, MinY: -90, MaxX: 180, MaxY: 90}
+
+// MaxCellLevel is the level of the smallest cells.
+const MaxCellLevel = 30
+
+// CellID identifies a cell of the spatial index quadtree. The root cell is
+// at level 0 and each cell at level l < MaxCellLevel has four children at
+// level l+1.
+//
+// A cell at level l is encoded as the 2l bits of its path from the root,
+// followed by a 1 bit and 2(MaxCellLevel-l) zero bits.
+type CellID uint64
+
+// RootCell is the cell covering the whole indexed region.
+const RootCell = CellID(1) << (2 * MaxCellLevel)
+
+// lsb returns the lowest set bit of the cell ID, which marks its level.
+func (c CellID) lsb() uint64 { return uint64(c) & -uint64(c) }
+
+// Level returns the level of the cell.
+func (c CellID) Level() int {
+	return MaxCellLevel - bits.TrailingZeros64(uint64(c))/2
+}
+
+// RangeMin returns the smallest ID of the descendants of the cell.
+func (c CellID) RangeMin() CellID { return CellID(uint64(c) - c.lsb() + 1) }
+
+// RangeMax returns the largest ID of the descendants of the cell.
+func (c CellID) RangeMax() CellID { return CellID(uint64(c) + c.lsb() - 1) }
+
+// Parent returns the parent of the cell, which must not be the root.
+func (c CellID) Parent() CellID {
+	lsb := c.lsb() << 2
+	return CellID(uint64(c)&-lsb | lsb)
+}
+
+// Ancestors returns the strict ancestors of the cell, from its parent up to
+// the root.
+func (c CellID) Ancestors() []CellID {
+	res := make([]CellID, 0, c.Level())
+	for c.Level() > 0 {
+		c = c.Parent()
+		res = append(res, c)
+	}
+	return res
+}
+
+// cellFromPos returns the cell at the given level containing the position
+// (i, j) of the grid of smallest cells.
+func cellFromPos(level int, i, j uint32) CellID {
+	shift := uint(MaxCellLevel - level)
+	i, j = i>>shift, j>>shift
+	var path uint64
+	for b := level - 1; b >= 0; b-- {
+		path = path<<2 | uint64((i>>uint(b))&1)<<1 | uint64((j>>uint(b))&1)
+	}
+	return CellID(path<<(2*shift+1) | 1<<(2*shift))
+}
+
+// gridPos maps a coordinate to its position in the grid of smallest cells,
+// clamping it to the bounds.
+func gridPos(v, min, max float64) uint32 {
+	const n = 1 << MaxCellLevel
+	f := math.Floor((v - min) / (max - min) * n)
+	if !(f >= 0) {
+		return 0
+	}
+	if f >= n {
+		return n - 1
+	}
+	return uint32(f)
+}
+
+func (b BoundingBox) within(o BoundingBox) bool {
+	return b.MinX >= o.MinX && b.MaxX <= o.MaxX && b.MinY >= o.MinY && b.MaxY <= o.MaxY
+}
+
+// cellForBox returns the smallest cell containing the box, or the root cell
+// if the box is not within the bounds.
+func cellForBox(bounds, box BoundingBox) CellID {
+	if !box.within(bounds) {
+		return RootCell
+	}
+	i0, i1 := gridPos(box.MinX, bounds.MinX, bounds.MaxX), gridPos(box.MaxX, bounds.MinX, bounds.MaxX)
+	j0, j1 := gridPos(box.MinY, bounds.MinY, bounds.MaxY), gridPos(box.MaxY, bounds.MinY, bounds.MaxY)
+	// The level is the length of the common prefix of the positions.
+	level := MaxCellLevel - bits.Len32((i0^i1)|(j0^j1))
+	return cellFromPos(level, i0, j0)
+}
+
+// coveringForBox returns up to four cells which together cover the part of
+// the box within the bounds.
+func coveringForBox(bounds, box BoundingBox) []CellID {
+	i0, i1 := gridPos(box.MinX, bounds.MinX, bounds.MaxX), gridPos(box.MaxX, bounds.MinX, bounds.MaxX)
+	j0, j1 := gridPos(box.MinY, bounds.MinY, bounds.MaxY), gridPos(box.MaxY, bounds.MinY, bounds.MaxY)
+	// Find the deepest level at which the box spans at most two cells in each
+	// dimension.
+	shift := uint(0)
+	for (i1>>shift)-(i0>>shift) > 1 || (j1>>shift)-(j0>>shift) > 1 {
+		shift++
+	}
+	level := MaxCellLevel - int(shift)
+	var res []CellID
+	for i := i0 >> shift; i <= i1>>shift; i++ {
+		for j := j0 >> shift; j <= j1>>shift; j++ {
+			res = append(res, cellFromPos(level, i<<shift, j<<shift))
+		}
+	}
+	return res
+}
+
+// boundingBox returns the bounding box of the positions of the shape, or
+// false if it is empty.
+func boundingBox(s Shape) (BoundingBox, bool) {
+	box := BoundingBox{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	forEachCoord(s, func(c Coord) {
+		box.MinX, box.MaxX = math.Min(box.MinX, c.X), math.Max(box.MaxX, c.X)
+		box.MinY, box.MaxY = math.Min(box.MinY, c.Y), math.Max(box.MaxY, c.Y)
+	})
+	return box, box.MinX <= box.MaxX
+}
+
+// BoundingBox returns the bounding box of the geometry, or false if it is
+// empty.
+func (g Geometry) BoundingBox() (BoundingBox, bool) { return boundingBox(g.Shape) }
+
+// BoundingBox returns a longitude/latitude box containing the geography, or
+// false if it is empty. Arcs bulge towards the poles, so the box extends
+// beyond the positions of the shape where needed. Shapes crossing the
+// antimeridian or containing a pole span all longitudes.
+func (g Geography) BoundingBox() (BoundingBox, bool) {
+	box, ok := boundingBox(g.Shape)
+	if !ok {
+		return box, false
+	}
+	c := decompose(g.Shape)
+	fullLon := false
+	c.forEachSegment(func(a, b Coord) bool {
+		if math.Abs(a.X-b.X) > 180 {
+			fullLon = true
+		}
+		va, vb := toVec3(a), toVec3(b)
+		n := va.cross(vb)
+		if n.norm() < sphereEpsilon {
+			return false
+		}
+		n = n.normalize()
+		// The point of the great circle closest to the north pole.
+		top := vec3{0, 0, 1}.sub(n.scale(n[2]))
+		if top.norm() < sphereEpsilon {
+			return false
+		}
+		top = top.normalize()
+		if onArc(top, va, vb) {
+			box.MaxY = math.Max(box.MaxY, math.Asin(top[2])*180/math.Pi)
+		}
+		if bottom := top.scale(-1); onArc(bottom, va, vb) {
+			box.MinY = math.Min(box.MinY, math.Asin(bottom[2])*180/math.Pi)
+		}
+		return false
+	})
+	for _, p := range c.polys {
+		sp, err := makeSphericalPolygon(p)
+		if err != nil {
+			// Polygons this large span the whole globe.
+			return geographyIndexBounds, true
+		}
+		if sp.locate(vec3{0, 0, 1}) != exterior {
+			box.MaxY, fullLon = 90, true
+		}
+		if sp.locate(vec3{0, 0, -1}) != exterior {
+			box.MinY, fullLon = -90, true
+		}
+	}
+	if fullLon {
+		box.MinX, box.MaxX = -180, 180
+	}
+	box.MinY, box.MaxY = math.Max(box.MinY, -90), math.Min(box.MaxY, 90)
+	return box, true
+}
+
+// IndexCell returns the cell under which the geometry is indexed, or false
+// if it is empty and so cannot match any spatial predicate.
+func (g Geometry) IndexCell() (CellID, bool) {
+	box, ok := g.BoundingBox()
+	if !ok {
+		return 0, false
+	}
+	return cellForBox(geometryIndexBounds, box), true
+}
+
+// IndexCell returns the cell under which the geography is indexed, or false
+// if it is empty and so cannot match any spatial predicate.
+func (g Geography) IndexCell() (CellID, bool) {
+	box, ok := g.BoundingBox()
+	if !ok {
+		return 0, false
+	}
+	return cellForBox(geographyIndexBounds, box), true
+}
+
+// IndexCovering returns cells covering all the points within the given
+// distance of the geometry, or nil if it is empty.
+func (g Geometry) IndexCovering(distance float64) []CellID {
+	box, ok := g.BoundingBox()
+	if !ok {
+		return nil
+	}
+	distance = math.Max(distance, 0)
+	box.MinX, box.MinY = box.MinX-distance, box.MinY-distance
+	box.MaxX, box.MaxY = box.MaxX+distance, box.MaxY+distance
+	return coveringForBox(geometryIndexBounds, box)
+}
+
+// IndexCovering returns cells covering all the points within the given
+// distance in meters of the geography, or nil if it is empty.
+func (g Geography) IndexCovering(distance float64) []CellID {
+	box, ok := g.BoundingBox()
+	if !ok {
+		return nil
+	}
+	if distance > 0 {
+		delta := distance / EarthRadiusMeters
+		if delta >= math.Pi {
+			return coveringForBox(geographyIndexBounds, geographyIndexBounds)
+		}
+		deltaDeg := delta * 180 / math.Pi
+		// The longitude difference of points within delta of a point at
+		// latitude lat is at most asin(sin(delta)/cos(lat)).
+		maxLat := math.Max(math.Abs(box.MinY), math.Abs(box.MaxY))
+		box.MinY, box.MaxY = math.Max(box.MinY-deltaDeg, -90), math.Min(box.MaxY+deltaDeg, 90)
+		sinLon := math.Sin(delta) / math.Cos(maxLat*math.Pi/180)
+		if sinLon >= 1 || maxLat+deltaDeg >= 90 {
+			box.MinX, box.MaxX = -180, 180
+		} else {
+			lonDeg := math.Asin(sinLon) * 180 / math.Pi
+			box.MinX, box.MaxX = box.MinX-lonDeg, box.MaxX+lonDeg
+			if box.MinX < -180 || box.MaxX > 180 {
+				box.MinX, box.MaxX = -180, 180
+			}
+		}
+	}
+	return coveringForBox(geographyIndexBounds, box)
+}
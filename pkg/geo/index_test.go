@@ -0,0 +1,144 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCellID(t *testing.T) {
+	if l := RootCell.Level(); l != 0 {
+		t.Fatalf("expected root level 0, got %d", l)
+	}
+	if RootCell.RangeMin() != 1 || RootCell.RangeMax() != 2*RootCell-1 {
+		t.Fatalf("unexpected root range [%d, %d]", RootCell.RangeMin(), RootCell.RangeMax())
+	}
+	c := cellFromPos(MaxCellLevel, 12345, 67890)
+	if l := c.Level(); l != MaxCellLevel {
+		t.Fatalf("expected level %d, got %d", MaxCellLevel, l)
+	}
+	if c.RangeMin() != c || c.RangeMax() != c {
+		t.Fatalf("leaf cell should be its own range")
+	}
+	ancestors := c.Ancestors()
+	if len(ancestors) != MaxCellLevel || ancestors[len(ancestors)-1] != RootCell {
+		t.Fatalf("unexpected ancestors %v", ancestors)
+	}
+	for i, a := range ancestors {
+		if a.Level() != MaxCellLevel-1-i {
+			t.Fatalf("ancestor %d has level %d", i, a.Level())
+		}
+		if c < a.RangeMin() || c > a.RangeMax() {
+			t.Fatalf("cell %d is not in the range of its ancestor %d", c, a)
+		}
+		if a != cellFromPos(a.Level(), 12345, 67890) {
+			t.Fatalf("ancestor %d does not match the cell at its level", i)
+		}
+	}
+}
+
+// TestIndexCovering checks that the cell of any geometry intersecting a
+// query is found by scanning the covering of the query.
+func TestIndexCovering(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	matches := func(cell CellID, covering []CellID) bool {
+		for _, c := range covering {
+			if cell >= c.RangeMin() && cell <= c.RangeMax() {
+				return true
+			}
+			for _, a := range c.Ancestors() {
+				if cell == a {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	randBox := func(scale float64) Geometry {
+		x, y := (rng.Float64()-0.5)*scale, (rng.Float64()-0.5)*scale
+		w, h := rng.Float64()*scale/100, rng.Float64()*scale/100
+		return Geometry{Shape: Polygon{{{X: x, Y: y}, {X: x + w, Y: y}, {X: x + w, Y: y + h}, {X: x, Y: y}}}}
+	}
+	for i := 0; i < 10000; i++ {
+		scale := []float64{1, 1000, 1 << 27}[i%3]
+		a, b := randBox(scale), randBox(scale)
+		intersects, err := GeometryIntersects(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cell, ok := a.IndexCell()
+		if !ok {
+			t.Fatal("expected an index cell")
+		}
+		if intersects && !matches(cell, b.IndexCovering(0)) {
+			t.Fatalf("%s intersects %s but is not found by the covering", a.WKT(), b.WKT())
+		}
+		d, err := GeometryDistance(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !matches(cell, b.IndexCovering(d)) {
+			t.Fatalf("%s is within %g of %s but is not found by the covering", a.WKT(), d, b.WKT())
+		}
+	}
+}
+
+func TestGeographyIndexCovering(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	randPoint := func() Geography {
+		return Geography{SRID: DefaultGeographySRID, Shape: Point{Coord: Coord{
+			X: (rng.Float64() - 0.5) * 360, Y: (rng.Float64() - 0.5) * 180,
+		}}}
+	}
+	for i := 0; i < 10000; i++ {
+		a, b := randPoint(), randPoint()
+		d, err := GeographyDistance(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cell, _ := a.IndexCell()
+		found := false
+		for _, c := range b.IndexCovering(d * 1.0001) {
+			if cell >= c.RangeMin() && cell <= c.RangeMax() {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("%s is within %g of %s but is not found by the covering", a.WKT(), d, b.WKT())
+		}
+	}
+}
+
+func TestGeographyBoundingBox(t *testing.T) {
+	testCases := []struct {
+		wkt string
+		box BoundingBox
+	}{
+		{`POINT(1 2)`, BoundingBox{1, 2, 1, 2}},
+		{`LINESTRING(170 0,-170 0)`, BoundingBox{-180, 0, 180, 0}},
+		{`POLYGON((-10 80,80 80,170 80,-100 80,-10 80))`, BoundingBox{-180, 80, 180, 90}},
+	}
+	for _, tc := range testCases {
+		g := mustParseGeography(t, tc.wkt)
+		box, ok := g.BoundingBox()
+		if !ok || box != tc.box {
+			t.Errorf("%s: expected %v, got %v", tc.wkt, tc.box, box)
+		}
+	}
+	// Arcs along a parallel bulge towards the pole.
+	g := mustParseGeography(t, `LINESTRING(0 10,20 10)`)
+	if box, _ := g.BoundingBox(); !(box.MaxY > 10.15 && box.MinY == 10) {
+		t.Errorf("unexpected box %v", box)
+	}
+}
@@ -0,0 +1,512 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// location is the position of a point relative to a shape.
+type location int
+
+const (
+	exterior location = iota
+	boundary
+	interior
+)
+
+// components is a shape flattened into its points, line strings and
+// polygons. Empty elements are dropped.
+type components struct {
+	points []Coord
+	lines  []LineString
+	polys  []Polygon
+}
+
+func decompose(s Shape) components {
+	var c components
+	c.add(s)
+	return c
+}
+
+func (c *components) add(s Shape) {
+	switch t := s.(type) {
+	case Point:
+		if !t.Empty {
+			c.points = append(c.points, t.Coord)
+		}
+	case LineString:
+		if !t.IsEmpty() {
+			c.lines = append(c.lines, t)
+		}
+	case Polygon:
+		if !t.IsEmpty() {
+			c.polys = append(c.polys, t)
+		}
+	case MultiPoint:
+		c.points = append(c.points, t...)
+	case MultiLineString:
+		for _, l := range t {
+			c.add(l)
+		}
+	case MultiPolygon:
+		for _, p := range t {
+			c.add(p)
+		}
+	case GeometryCollection:
+		for _, e := range t {
+			c.add(e)
+		}
+	}
+}
+
+func (c *components) isEmpty() bool {
+	return len(c.points) == 0 && len(c.lines) == 0 && len(c.polys) == 0
+}
+
+// forEachSegment calls fn on every segment of the line strings and polygon
+// rings, stopping early if fn returns true.
+func (c *components) forEachSegment(fn func(a, b Coord) bool) bool {
+	for _, l := range c.lines {
+		for i := 1; i < len(l); i++ {
+			if fn(l[i-1], l[i]) {
+				return true
+			}
+		}
+	}
+	for _, p := range c.polys {
+		for _, r := range p {
+			for i := 1; i < len(r); i++ {
+				if fn(r[i-1], r[i]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// orient returns a positive value if c is to the left of the directed line
+// through a and b, a negative value if it is to the right, and zero if the
+// three points are collinear.
+func orient(a, b, c Coord) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// inSegmentBox returns whether p lies within the bounding box of segment ab.
+func inSegmentBox(p, a, b Coord) bool {
+	return math.Min(a.X, b.X) <= p.X && p.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= p.Y && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// onSegment returns whether p lies on the closed segment ab.
+func onSegment(p, a, b Coord) bool {
+	return orient(a, b, p) == 0 && inSegmentBox(p, a, b)
+}
+
+// segmentsIntersect returns whether the closed segments ab and cd share a
+// point.
+func segmentsIntersect(a, b, c, d Coord) bool {
+	o1, o2 := orient(a, b, c), orient(a, b, d)
+	o3, o4 := orient(c, d, a), orient(c, d, b)
+	if ((o1 > 0 && o2 < 0) || (o1 < 0 && o2 > 0)) && ((o3 > 0 && o4 < 0) || (o3 < 0 && o4 > 0)) {
+		return true
+	}
+	return (o1 == 0 && inSegmentBox(c, a, b)) || (o2 == 0 && inSegmentBox(d, a, b)) ||
+		(o3 == 0 && inSegmentBox(a, c, d)) || (o4 == 0 && inSegmentBox(b, c, d))
+}
+
+func pointDistance(a, b Coord) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// pointSegmentDistance returns the distance from p to the segment ab.
+func pointSegmentDistance(p, a, b Coord) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return pointDistance(p, a)
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / l2
+	t = math.Max(0, math.Min(1, t))
+	return pointDistance(p, Coord{X: a.X + t*dx, Y: a.Y + t*dy})
+}
+
+// segmentDistance returns the distance between the segments ab and cd.
+func segmentDistance(a, b, c, d Coord) float64 {
+	if segmentsIntersect(a, b, c, d) {
+		return 0
+	}
+	return math.Min(
+		math.Min(pointSegmentDistance(a, c, d), pointSegmentDistance(b, c, d)),
+		math.Min(pointSegmentDistance(c, a, b), pointSegmentDistance(d, a, b)),
+	)
+}
+
+// locateInRing returns the location of p relative to the area enclosed by
+// the closed ring.
+func locateInRing(p Coord, ring []Coord) location {
+	inside := false
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if onSegment(p, a, b) {
+			return boundary
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return interior
+	}
+	return exterior
+}
+
+// locateInPolygon returns the location of p relative to the polygon.
+func locateInPolygon(p Coord, poly Polygon) location {
+	loc := locateInRing(p, poly[0])
+	if loc != interior {
+		return loc
+	}
+	for _, hole := range poly[1:] {
+		switch locateInRing(p, hole) {
+		case interior:
+			return exterior
+		case boundary:
+			return boundary
+		}
+	}
+	return interior
+}
+
+// locate returns the location of p relative to the union of the components.
+func (c *components) locate(p Coord) location {
+	loc := exterior
+	for _, q := range c.points {
+		if p == q {
+			return interior
+		}
+	}
+	for _, l := range c.lines {
+		for i := 1; i < len(l); i++ {
+			if onSegment(p, l[i-1], l[i]) {
+				// The endpoints of an open line string are its boundary.
+				if l[0] != l[len(l)-1] && (p == l[0] || p == l[len(l)-1]) {
+					loc = boundary
+					break
+				}
+				return interior
+			}
+		}
+	}
+	for _, poly := range c.polys {
+		switch locateInPolygon(p, poly) {
+		case interior:
+			return interior
+		case boundary:
+			loc = boundary
+		}
+	}
+	return loc
+}
+
+// intersects returns whether the components share at least one point.
+func (c *components) intersects(o *components) bool {
+	for _, p := range c.points {
+		if o.locate(p) != exterior {
+			return true
+		}
+	}
+	for _, p := range o.points {
+		if c.locate(p) != exterior {
+			return true
+		}
+	}
+	// If no segments cross, each line string and polygon is either entirely
+	// inside or entirely outside of each polygon of the other side, which is
+	// determined by locating any of its vertices.
+	crosses := c.forEachSegment(func(a, b Coord) bool {
+		return o.forEachSegment(func(x, y Coord) bool {
+			return segmentsIntersect(a, b, x, y)
+		})
+	})
+	if crosses {
+		return true
+	}
+	vertexInPolys := func(v Coord, polys []Polygon) bool {
+		for _, poly := range polys {
+			if locateInPolygon(v, poly) != exterior {
+				return true
+			}
+		}
+		return false
+	}
+	for _, l := range c.lines {
+		if vertexInPolys(l[0], o.polys) {
+			return true
+		}
+	}
+	for _, p := range c.polys {
+		if vertexInPolys(p[0][0], o.polys) {
+			return true
+		}
+	}
+	for _, l := range o.lines {
+		if vertexInPolys(l[0], c.polys) {
+			return true
+		}
+	}
+	for _, p := range o.polys {
+		if vertexInPolys(p[0][0], c.polys) {
+			return true
+		}
+	}
+	return false
+}
+
+// distance returns the minimum distance between the components, which must
+// not be empty.
+func (c *components) distance(o *components) float64 {
+	if c.intersects(o) {
+		return 0
+	}
+	d := math.Inf(1)
+	for _, p := range c.points {
+		for _, q := range o.points {
+			d = math.Min(d, pointDistance(p, q))
+		}
+		o.forEachSegment(func(a, b Coord) bool {
+			d = math.Min(d, pointSegmentDistance(p, a, b))
+			return false
+		})
+	}
+	for _, q := range o.points {
+		c.forEachSegment(func(a, b Coord) bool {
+			d = math.Min(d, pointSegmentDistance(q, a, b))
+			return false
+		})
+	}
+	c.forEachSegment(func(a, b Coord) bool {
+		o.forEachSegment(func(x, y Coord) bool {
+			d = math.Min(d, segmentDistance(a, b, x, y))
+			return false
+		})
+		return false
+	})
+	return d
+}
+
+// splitSegment returns the points at which the segment ab should be split so
+// that no piece crosses the boundary of the components: the intersections
+// with their segments and the endpoints of their segments lying on ab. The
+// result is ordered from a to b and includes a and b.
+func (c *components) splitSegment(a, b Coord) []Coord {
+	type split struct {
+		t float64
+		p Coord
+	}
+	splits := []split{{0, a}, {1, b}}
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l2 := dx*dx + dy*dy
+	param := func(p Coord) float64 {
+		if l2 == 0 {
+			return 0
+		}
+		return ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / l2
+	}
+	c.forEachSegment(func(x, y Coord) bool {
+		if !segmentsIntersect(a, b, x, y) {
+			return false
+		}
+		ox, oy := orient(a, b, x), orient(a, b, y)
+		if ox == 0 || oy == 0 {
+			// Collinear or touching: the endpoints lying on ab are the splits.
+			if onSegment(x, a, b) {
+				splits = append(splits, split{param(x), x})
+			}
+			if onSegment(y, a, b) {
+				splits = append(splits, split{param(y), y})
+			}
+			return false
+		}
+		// Proper crossing.
+		t := ox / (ox - oy)
+		p := Coord{X: x.X + t*(y.X-x.X), Y: x.Y + t*(y.Y-x.Y)}
+		splits = append(splits, split{param(p), p})
+		return false
+	})
+	sort.Slice(splits, func(i, j int) bool { return splits[i].t < splits[j].t })
+	res := make([]Coord, len(splits))
+	for i := range splits {
+		res[i] = splits[i].p
+	}
+	return res
+}
+
+// coverSegment checks the segment ab against the components. It returns
+// false if part of the segment lies in their exterior, and reports via
+// anyInterior whether part of it lies in their interior.
+func (c *components) coverSegment(a, b Coord, anyInterior *bool) bool {
+	splits := c.splitSegment(a, b)
+	check := func(p Coord) bool {
+		switch c.locate(p) {
+		case exterior:
+			return false
+		case interior:
+			*anyInterior = true
+		}
+		return true
+	}
+	// The split points computed from crossings lie on the boundary by
+	// construction, so only the endpoints and the midpoints of the pieces
+	// need to be located.
+	if !check(a) || !check(b) {
+		return false
+	}
+	for i := 1; i < len(splits); i++ {
+		p, q := splits[i-1], splits[i]
+		if !check(Coord{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2}) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains returns whether no point of o lies in the exterior of c and at
+// least one point of the interior of o lies in the interior of c.
+func (c *components) contains(o *components) bool {
+	if c.isEmpty() || o.isEmpty() {
+		return false
+	}
+	anyInterior := false
+	for _, p := range o.points {
+		switch c.locate(p) {
+		case exterior:
+			return false
+		case interior:
+			anyInterior = true
+		}
+	}
+	// Line strings and polygon boundaries must be covered.
+	covered := !o.forEachSegment(func(a, b Coord) bool {
+		return !c.coverSegment(a, b, &anyInterior)
+	})
+	if !covered {
+		return false
+	}
+	if len(o.polys) > 0 {
+		// An area can only be contained in an area.
+		if len(c.polys) == 0 {
+			return false
+		}
+		// The boundaries of o's polygons are covered, but the holes of c
+		// could still lie inside of them. Since those boundaries do not enter
+		// the holes, a hole lies either inside or outside of each polygon.
+		for _, hole := range c.holes() {
+			p := ringInteriorPoint(hole)
+			for _, poly := range o.polys {
+				if locateInPolygon(p, poly) == interior {
+					return false
+				}
+			}
+		}
+		// The interior of a polygon covered by c lies in the interior of c.
+		anyInterior = true
+	}
+	return anyInterior
+}
+
+// holes returns the holes of all the polygons.
+func (c *components) holes() []LineString {
+	var res []LineString
+	for _, p := range c.polys {
+		res = append(res, p[1:]...)
+	}
+	return res
+}
+
+// ringInteriorPoint returns a point strictly inside the area enclosed by the
+// ring. It intersects the ring with a horizontal line passing between the
+// two lowest vertices and returns the midpoint of the first two crossings.
+func ringInteriorPoint(ring []Coord) Coord {
+	y0, y1 := math.Inf(1), math.Inf(1)
+	for _, c := range ring {
+		if c.Y < y0 {
+			y0, y1 = c.Y, y0
+		} else if c.Y > y0 && c.Y < y1 {
+			y1 = c.Y
+		}
+	}
+	y := (y0 + y1) / 2
+	var xs []float64
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if (a.Y > y) != (b.Y > y) {
+			xs = append(xs, a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+		}
+	}
+	if len(xs) < 2 {
+		// Degenerate ring without area.
+		return ring[0]
+	}
+	sort.Float64s(xs)
+	return Coord{X: (xs[0] + xs[1]) / 2, Y: y}
+}
+
+// GeometryDistance returns the minimum planar distance between two
+// geometries. The distance involving an empty geometry is infinite.
+func GeometryDistance(a, b Geometry) (float64, error) {
+	if err := checkSRIDs(a.SRID, b.SRID); err != nil {
+		return 0, err
+	}
+	ca, cb := decompose(a.Shape), decompose(b.Shape)
+	if ca.isEmpty() || cb.isEmpty() {
+		return math.Inf(1), nil
+	}
+	return ca.distance(&cb), nil
+}
+
+// GeometryIntersects returns whether two geometries share at least one
+// point.
+func GeometryIntersects(a, b Geometry) (bool, error) {
+	if err := checkSRIDs(a.SRID, b.SRID); err != nil {
+		return false, err
+	}
+	ca, cb := decompose(a.Shape), decompose(b.Shape)
+	return ca.intersects(&cb), nil
+}
+
+// GeometryContains returns whether a contains b: no point of b lies in the
+// exterior of a, and at least one point of the interior of b lies in the
+// interior of a.
+func GeometryContains(a, b Geometry) (bool, error) {
+	if err := checkSRIDs(a.SRID, b.SRID); err != nil {
+		return false, err
+	}
+	ca, cb := decompose(a.Shape), decompose(b.Shape)
+	return ca.contains(&cb), nil
+}
+
+// GeometryDWithin returns whether two geometries are within the given
+// distance of each other.
+func GeometryDWithin(a, b Geometry, distance float64) (bool, error) {
+	d, err := GeometryDistance(a, b)
+	if err != nil {
+		return false, err
+	}
+	return d <= distance, nil
+}
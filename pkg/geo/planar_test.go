@@ -0,0 +1,116 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func mustParseGeometry(t *testing.T, s string) Geometry {
+	t.Helper()
+	g, err := ParseGeometry(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+const (
+	square = `POLYGON((0 0,10 0,10 10,0 10,0 0))`
+	donut  = `POLYGON((0 0,10 0,10 10,0 10,0 0),(4 4,6 4,6 6,4 6,4 4))`
+)
+
+func TestGeometryPredicates(t *testing.T) {
+	testCases := []struct {
+		a, b       string
+		intersects bool
+		contains   bool
+		distance   float64
+	}{
+		{`POINT(1 1)`, `POINT(1 1)`, true, true, 0},
+		{`POINT(0 0)`, `POINT(3 4)`, false, false, 5},
+		{square, `POINT(5 5)`, true, true, 0},
+		{square, `POINT(0 5)`, true, false, 0},
+		{square, `POINT(13 14)`, false, false, 5},
+		{donut, `POINT(5 5)`, false, false, 1},
+		{donut, `POINT(2 2)`, true, true, 0},
+		{square, `LINESTRING(1 1,9 9)`, true, true, 0},
+		{square, `LINESTRING(0 0,10 0)`, true, false, 0},
+		{square, `LINESTRING(0 0,5 5)`, true, true, 0},
+		{square, `LINESTRING(5 5,15 5)`, true, false, 0},
+		{donut, `LINESTRING(1 1,9 9)`, true, false, 0},
+		{square, `LINESTRING(11 0,11 10)`, false, false, 1},
+		{square, square, true, true, 0},
+		{square, `POLYGON((1 1,2 1,2 2,1 1))`, true, true, 0},
+		{donut, `POLYGON((1 1,9 1,9 9,1 9,1 1))`, true, false, 0},
+		{donut, `POLYGON((4 4,6 4,6 6,4 6,4 4))`, true, false, 0},
+		{square, `POLYGON((5 5,15 5,15 15,5 15,5 5))`, true, false, 0},
+		{`POLYGON((1 1,2 1,2 2,1 1))`, square, true, false, 0},
+		{square, `POLYGON((20 0,30 0,30 10,20 0))`, false, false, 10},
+		{`LINESTRING(0 0,10 0)`, `POINT(5 0)`, true, true, 0},
+		{`LINESTRING(0 0,10 0)`, `POINT(0 0)`, true, false, 0},
+		{`LINESTRING(0 0,10 0)`, `LINESTRING(2 0,8 0)`, true, true, 0},
+		{`LINESTRING(0 0,5 0,10 0)`, `LINESTRING(2 0,8 0)`, true, true, 0},
+		{`LINESTRING(0 0,10 0)`, `LINESTRING(5 -1,5 1)`, true, false, 0},
+		{`LINESTRING(0 0,10 0)`, `POLYGON((0 0,1 0,1 1,0 0))`, true, false, 0},
+		{`MULTIPOINT(0 0,5 5)`, `POINT(5 5)`, true, true, 0},
+		{`MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))`, `POINT(5.5 5.2)`, true, true, 0},
+		{`GEOMETRYCOLLECTION(POINT(0 0),POLYGON((5 5,6 5,6 6,5 5)))`, `MULTIPOINT(0 0,5.5 5.2)`, true, true, 0},
+		{square, `POINT EMPTY`, false, false, math.Inf(1)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			a, b := mustParseGeometry(t, tc.a), mustParseGeometry(t, tc.b)
+			intersects, err := GeometryIntersects(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if intersects != tc.intersects {
+				t.Errorf("expected intersects %t, got %t", tc.intersects, intersects)
+			}
+			if rev, _ := GeometryIntersects(b, a); rev != intersects {
+				t.Errorf("intersects is not symmetric")
+			}
+			contains, err := GeometryContains(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contains != tc.contains {
+				t.Errorf("expected contains %t, got %t", tc.contains, contains)
+			}
+			distance, err := GeometryDistance(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if distance != tc.distance {
+				t.Errorf("expected distance %g, got %g", tc.distance, distance)
+			}
+			within, err := GeometryDWithin(a, b, math.Min(tc.distance, math.MaxFloat64))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := !math.IsInf(tc.distance, 1); within != expected {
+				t.Errorf("expected dwithin %t, got %t", expected, within)
+			}
+		})
+	}
+}
+
+func TestGeometryMixedSRIDs(t *testing.T) {
+	a, b := mustParseGeometry(t, `SRID=4326;POINT(0 0)`), mustParseGeometry(t, `POINT(0 0)`)
+	if _, err := GeometryIntersects(a, b); err == nil || !strings.Contains(err.Error(), "mixed SRIDs") {
+		t.Fatalf("expected mixed SRIDs error, got %v", err)
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// EarthRadiusMeters is the mean radius of the earth, which is modeled as a
+// sphere for geography measurements.
+const EarthRadiusMeters = 6371008.8
+
+// sphereEpsilon is the angular tolerance, in radians, below which points on
+// the sphere are considered to coincide. It corresponds to well under a
+// millimeter on the surface of the earth.
+const sphereEpsilon = 1e-12
+
+// vec3 is a point on the unit sphere.
+type vec3 [3]float64
+
+func toVec3(c Coord) vec3 {
+	lon, lat := c.X*math.Pi/180, c.Y*math.Pi/180
+	return vec3{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+func (v vec3) dot(o vec3) float64 { return v[0]*o[0] + v[1]*o[1] + v[2]*o[2] }
+
+func (v vec3) cross(o vec3) vec3 {
+	return vec3{v[1]*o[2] - v[2]*o[1], v[2]*o[0] - v[0]*o[2], v[0]*o[1] - v[1]*o[0]}
+}
+
+func (v vec3) norm() float64 { return math.Sqrt(v.dot(v)) }
+
+func (v vec3) scale(f float64) vec3 { return vec3{v[0] * f, v[1] * f, v[2] * f} }
+
+func (v vec3) sub(o vec3) vec3 { return vec3{v[0] - o[0], v[1] - o[1], v[2] - o[2]} }
+
+func (v vec3) add(o vec3) vec3 { return vec3{v[0] + o[0], v[1] + o[1], v[2] + o[2]} }
+
+func (v vec3) normalize() vec3 {
+	n := v.norm()
+	if n == 0 {
+		return v
+	}
+	return v.scale(1 / n)
+}
+
+// angle returns the angle between two unit vectors in radians.
+func angle(a, b vec3) float64 {
+	return math.Atan2(a.cross(b).norm(), a.dot(b))
+}
+
+// onArc returns whether p lies on the minor great circle arc from a to b.
+func onArc(p, a, b vec3) bool {
+	return math.Abs(angle(a, p)+angle(p, b)-angle(a, b)) < sphereEpsilon
+}
+
+// pointArcAngle returns the angular distance from p to the minor arc ab.
+func pointArcAngle(p, a, b vec3) float64 {
+	n := a.cross(b)
+	if n.norm() < sphereEpsilon {
+		return angle(p, a)
+	}
+	n = n.normalize()
+	// Project p onto the plane of the great circle through a and b.
+	q := p.sub(n.scale(p.dot(n)))
+	if q.norm() > sphereEpsilon {
+		q = q.normalize()
+		if onArc(q, a, b) {
+			return angle(p, q)
+		}
+	}
+	return math.Min(angle(p, a), angle(p, b))
+}
+
+// arcsIntersect returns whether the minor arcs ab and cd share a point.
+func arcsIntersect(a, b, c, d vec3) bool {
+	n1, n2 := a.cross(b), c.cross(d)
+	if n1.norm() < sphereEpsilon || n2.norm() < sphereEpsilon {
+		// At least one degenerate arc.
+		return pointArcAngle(a, c, d) < sphereEpsilon || pointArcAngle(c, a, b) < sphereEpsilon
+	}
+	x := n1.normalize().cross(n2.normalize())
+	if x.norm() < sphereEpsilon {
+		// Both arcs lie on the same great circle.
+		return onArc(c, a, b) || onArc(d, a, b) || onArc(a, c, d) || onArc(b, c, d)
+	}
+	x = x.normalize()
+	for _, p := range [2]vec3{x, x.scale(-1)} {
+		if onArc(p, a, b) && onArc(p, c, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// arcAngle returns the angular distance between the minor arcs ab and cd.
+func arcAngle(a, b, c, d vec3) float64 {
+	if arcsIntersect(a, b, c, d) {
+		return 0
+	}
+	return math.Min(
+		math.Min(pointArcAngle(a, c, d), pointArcAngle(b, c, d)),
+		math.Min(pointArcAngle(c, a, b), pointArcAngle(d, a, b)),
+	)
+}
+
+var errPolygonTooLarge = pgerror.New(pgerror.CodeFeatureNotSupportedError,
+	"GEOGRAPHY polygons covering a hemisphere or more are not supported")
+
+// sphericalPolygon is a polygon on the sphere together with its gnomonic
+// projection, which maps great circle arcs to straight lines and so allows
+// points to be located using planar algorithms. The projection is centered
+// on the polygon and only covers the hemisphere around its center, so it is
+// only possible for polygons contained in an open hemisphere.
+type sphericalPolygon struct {
+	rings     [][]vec3
+	center    vec3
+	u, w      vec3
+	projected Polygon
+}
+
+func makeSphericalPolygon(p Polygon) (sphericalPolygon, error) {
+	var sp sphericalPolygon
+	sp.rings = make([][]vec3, len(p))
+	for i, r := range p {
+		sp.rings[i] = make([]vec3, len(r))
+		for j, c := range r {
+			sp.rings[i][j] = toVec3(c)
+		}
+	}
+	var sum vec3
+	ext := sp.rings[0]
+	for _, v := range ext[:len(ext)-1] {
+		sum = sum.add(v)
+	}
+	if sum.norm() < sphereEpsilon {
+		return sphericalPolygon{}, errPolygonTooLarge
+	}
+	sp.center = sum.normalize()
+	// Pick a basis of the plane tangent to the sphere at the center.
+	axis := vec3{0, 0, 1}
+	if math.Abs(sp.center[2]) > 0.9 {
+		axis = vec3{1, 0, 0}
+	}
+	sp.u = axis.cross(sp.center).normalize()
+	sp.w = sp.center.cross(sp.u)
+	sp.projected = make(Polygon, len(sp.rings))
+	for i, r := range sp.rings {
+		sp.projected[i] = make(LineString, len(r))
+		for j, v := range r {
+			c, ok := sp.project(v)
+			if !ok {
+				return sphericalPolygon{}, errPolygonTooLarge
+			}
+			sp.projected[i][j] = c
+		}
+	}
+	return sp, nil
+}
+
+// project returns the gnomonic projection of v, or false if v is not in the
+// open hemisphere centered on the polygon.
+func (sp *sphericalPolygon) project(v vec3) (Coord, bool) {
+	d := v.dot(sp.center)
+	if d < sphereEpsilon {
+		return Coord{}, false
+	}
+	v = v.scale(1 / d)
+	return Coord{X: v.dot(sp.u), Y: v.dot(sp.w)}, true
+}
+
+// locate returns the location of v relative to the polygon.
+func (sp *sphericalPolygon) locate(v vec3) location {
+	for _, r := range sp.rings {
+		for i := 1; i < len(r); i++ {
+			if pointArcAngle(v, r[i-1], r[i]) < sphereEpsilon {
+				return boundary
+			}
+		}
+	}
+	c, ok := sp.project(v)
+	if !ok {
+		return exterior
+	}
+	return locateInPolygon(c, sp.projected)
+}
+
+// sphericalComponents is a geography flattened into its points, line strings
+// and polygons on the unit sphere.
+type sphericalComponents struct {
+	points []vec3
+	lines  [][]vec3
+	polys  []sphericalPolygon
+}
+
+func decomposeSpherical(s Shape) (sphericalComponents, error) {
+	c := decompose(s)
+	var res sphericalComponents
+	for _, p := range c.points {
+		res.points = append(res.points, toVec3(p))
+	}
+	for _, l := range c.lines {
+		vs := make([]vec3, len(l))
+		for i, p := range l {
+			vs[i] = toVec3(p)
+		}
+		res.lines = append(res.lines, vs)
+	}
+	for _, p := range c.polys {
+		sp, err := makeSphericalPolygon(p)
+		if err != nil {
+			return sphericalComponents{}, err
+		}
+		res.polys = append(res.polys, sp)
+	}
+	return res, nil
+}
+
+func (c *sphericalComponents) isEmpty() bool {
+	return len(c.points) == 0 && len(c.lines) == 0 && len(c.polys) == 0
+}
+
+// forEachArc calls fn on every arc of the line strings and polygon rings,
+// stopping early if fn returns true.
+func (c *sphericalComponents) forEachArc(fn func(a, b vec3) bool) bool {
+	for _, l := range c.lines {
+		for i := 1; i < len(l); i++ {
+			if fn(l[i-1], l[i]) {
+				return true
+			}
+		}
+	}
+	for _, p := range c.polys {
+		for _, r := range p.rings {
+			for i := 1; i < len(r); i++ {
+				if fn(r[i-1], r[i]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// covers returns whether v lies on the components.
+func (c *sphericalComponents) covers(v vec3) bool {
+	for _, p := range c.points {
+		if angle(p, v) < sphereEpsilon {
+			return true
+		}
+	}
+	for _, l := range c.lines {
+		for i := 1; i < len(l); i++ {
+			if pointArcAngle(v, l[i-1], l[i]) < sphereEpsilon {
+				return true
+			}
+		}
+	}
+	for i := range c.polys {
+		if c.polys[i].locate(v) != exterior {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *sphericalComponents) intersects(o *sphericalComponents) bool {
+	for _, p := range c.points {
+		if o.covers(p) {
+			return true
+		}
+	}
+	for _, p := range o.points {
+		if c.covers(p) {
+			return true
+		}
+	}
+	crosses := c.forEachArc(func(a, b vec3) bool {
+		return o.forEachArc(func(x, y vec3) bool {
+			return arcsIntersect(a, b, x, y)
+		})
+	})
+	if crosses {
+		return true
+	}
+	// Without crossings, each line string and polygon is either entirely
+	// inside or entirely outside of each polygon of the other side.
+	vertexInPolys := func(v vec3, polys []sphericalPolygon) bool {
+		for i := range polys {
+			if polys[i].locate(v) != exterior {
+				return true
+			}
+		}
+		return false
+	}
+	for _, l := range c.lines {
+		if vertexInPolys(l[0], o.polys) {
+			return true
+		}
+	}
+	for _, p := range c.polys {
+		if vertexInPolys(p.rings[0][0], o.polys) {
+			return true
+		}
+	}
+	for _, l := range o.lines {
+		if vertexInPolys(l[0], c.polys) {
+			return true
+		}
+	}
+	for _, p := range o.polys {
+		if vertexInPolys(p.rings[0][0], c.polys) {
+			return true
+		}
+	}
+	return false
+}
+
+// angle returns the minimum angular distance between the components.
+func (c *sphericalComponents) angle(o *sphericalComponents) float64 {
+	if c.intersects(o) {
+		return 0
+	}
+	d := math.Inf(1)
+	for _, p := range c.points {
+		for _, q := range o.points {
+			d = math.Min(d, angle(p, q))
+		}
+		o.forEachArc(func(a, b vec3) bool {
+			d = math.Min(d, pointArcAngle(p, a, b))
+			return false
+		})
+	}
+	for _, q := range o.points {
+		c.forEachArc(func(a, b vec3) bool {
+			d = math.Min(d, pointArcAngle(q, a, b))
+			return false
+		})
+	}
+	c.forEachArc(func(a, b vec3) bool {
+		o.forEachArc(func(x, y vec3) bool {
+			d = math.Min(d, arcAngle(a, b, x, y))
+			return false
+		})
+		return false
+	})
+	return d
+}
+
+// GeographyDistance returns the minimum distance in meters between two
+// geographies along the surface of the earth. The distance involving an
+// empty geography is infinite.
+func GeographyDistance(a, b Geography) (float64, error) {
+	if err := checkSRIDs(a.SRID, b.SRID); err != nil {
+		return 0, err
+	}
+	ca, err := decomposeSpherical(a.Shape)
+	if err != nil {
+		return 0, err
+	}
+	cb, err := decomposeSpherical(b.Shape)
+	if err != nil {
+		return 0, err
+	}
+	if ca.isEmpty() || cb.isEmpty() {
+		return math.Inf(1), nil
+	}
+	return ca.angle(&cb) * EarthRadiusMeters, nil
+}
+
+// GeographyIntersects returns whether two geographies share at least one
+// point.
+func GeographyIntersects(a, b Geography) (bool, error) {
+	if err := checkSRIDs(a.SRID, b.SRID); err != nil {
+		return false, err
+	}
+	ca, err := decomposeSpherical(a.Shape)
+	if err != nil {
+		return false, err
+	}
+	cb, err := decomposeSpherical(b.Shape)
+	if err != nil {
+		return false, err
+	}
+	return ca.intersects(&cb), nil
+}
+
+// GeographyDWithin returns whether two geographies are within the given
+// distance in meters of each other.
+func GeographyDWithin(a, b Geography, distance float64) (bool, error) {
+	d, err := GeographyDistance(a, b)
+	if err != nil {
+		return false, err
+	}
+	return d <= distance, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func mustParseGeography(t *testing.T, s string) Geography {
+	t.Helper()
+	g, err := ParseGeography(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGeographyPredicates(t *testing.T) {
+	// One degree of arc on the surface of the earth, in meters.
+	deg := EarthRadiusMeters * math.Pi / 180
+	rad := math.Pi / 180
+	// The latitude reached by the arc from (0 10) to (20 10) at longitude 10.
+	bulge := math.Atan(math.Tan(10*rad)/math.Cos(10*rad)) / rad
+	testCases := []struct {
+		a, b       string
+		intersects bool
+		distance   float64
+	}{
+		{`POINT(0 0)`, `POINT(0 0)`, true, 0},
+		{`POINT(0 0)`, `POINT(0 1)`, false, deg},
+		{`POINT(179.5 0)`, `POINT(-179.5 0)`, false, deg},
+		{`POINT(0 89.5)`, `POINT(180 89.5)`, false, deg},
+		// The arc from (0 10) to (20 10) bulges north of latitude 10.
+		{`LINESTRING(0 10,20 10)`, `POINT(10 10)`, false, (bulge - 10) * deg},
+		{`LINESTRING(0 0,10 0)`, `POINT(5 0)`, true, 0},
+		{`LINESTRING(0 -1,0 1)`, `LINESTRING(-1 0,1 0)`, true, 0},
+		{`LINESTRING(179 -1,-179 1)`, `LINESTRING(179 1,-179 -1)`, true, 0},
+		{`POLYGON((0 0,10 0,10 10,0 10,0 0))`, `POINT(5 5)`, true, 0},
+		{`POLYGON((0 0,10 0,10 10,0 10,0 0))`, `POINT(5 -1)`, false, deg},
+		{`POLYGON((0 0,10 0,10 10,0 10,0 0),(4 4,6 4,6 6,4 6,4 4))`, `POINT(5 5)`, false, 0.9961 * deg},
+		{`POLYGON((0 0,10 0,10 10,0 10,0 0))`, `POLYGON((2 2,3 2,3 3,2 2))`, true, 0},
+		{`POLYGON((-10 80,80 80,170 80,-100 80,-10 80))`, `POINT(0 90)`, true, 0},
+		{`POLYGON((0 0,10 0,10 10,0 10,0 0))`, `LINESTRING(-5 5,15 5)`, true, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			a, b := mustParseGeography(t, tc.a), mustParseGeography(t, tc.b)
+			intersects, err := GeographyIntersects(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if intersects != tc.intersects {
+				t.Errorf("expected intersects %t, got %t", tc.intersects, intersects)
+			}
+			distance, err := GeographyDistance(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.distance == 0 {
+				if distance != 0 {
+					t.Errorf("expected distance 0, got %g", distance)
+				}
+			} else if math.Abs(distance-tc.distance) > 1e-3*tc.distance {
+				t.Errorf("expected distance %g, got %g", tc.distance, distance)
+			}
+			if tc.intersects {
+				return
+			}
+			within, err := GeographyDWithin(a, b, distance*1.001)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !within {
+				t.Errorf("expected to be within %g", distance)
+			}
+			within, err = GeographyDWithin(a, b, distance*0.999)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if within {
+				t.Errorf("expected not to be within %g", distance*0.999)
+			}
+		})
+	}
+}
+
+func TestGeographyPolygonTooLarge(t *testing.T) {
+	a := mustParseGeography(t, `POLYGON((0 0,120 0,-120 0,0 0))`)
+	b := mustParseGeography(t, `POINT(0 0)`)
+	if _, err := GeographyIntersects(a, b); err != errPolygonTooLarge {
+		t.Fatalf("expected %v, got %v", errPolygonTooLarge, err)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Flags used by EWKB in the geometry type word.
+const (
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+	ewkbSRIDFlag = 0x20000000
+)
+
+// wkbNDR is the WKB byte order marker for little endian encoding, which is
+// the encoding we produce.
+const wkbNDR = 1
+
+// WKB returns the Well-Known Binary representation of the geometry.
+func (g Geometry) WKB() []byte { return appendWKB(nil, 0, g.Shape) }
+
+// EWKB returns the extended Well-Known Binary representation of the
+// geometry, which includes the SRID unless it is zero. It is the
+// representation used to store geometries.
+func (g Geometry) EWKB() []byte { return appendWKB(nil, g.SRID, g.Shape) }
+
+// WKB returns the Well-Known Binary representation of the geography.
+func (g Geography) WKB() []byte { return appendWKB(nil, 0, g.Shape) }
+
+// EWKB returns the extended Well-Known Binary representation of the
+// geography.
+func (g Geography) EWKB() []byte { return appendWKB(nil, g.SRID, g.Shape) }
+
+// ParseGeometryFromEWKB decodes a geometry from (E)WKB.
+func ParseGeometryFromEWKB(b []byte) (Geometry, error) {
+	srid, shape, err := parseEWKB(b)
+	if err != nil {
+		return Geometry{}, err
+	}
+	return Geometry{SRID: srid, Shape: shape}, nil
+}
+
+// ParseGeographyFromEWKB decodes a geography from (E)WKB.
+func ParseGeographyFromEWKB(b []byte) (Geography, error) {
+	srid, shape, err := parseEWKB(b)
+	if err != nil {
+		return Geography{}, err
+	}
+	return NewGeography(srid, shape)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendCoord(b []byte, c Coord) []byte {
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], math.Float64bits(c.X))
+	binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(c.Y))
+	return append(b, buf[:]...)
+}
+
+func appendCoords(b []byte, coords []Coord) []byte {
+	b = appendUint32(b, uint32(len(coords)))
+	for _, c := range coords {
+		b = appendCoord(b, c)
+	}
+	return b
+}
+
+// appendWKB appends the WKB encoding of s, including the SRID if non-zero.
+func appendWKB(b []byte, srid int32, s Shape) []byte {
+	b = append(b, wkbNDR)
+	typ := uint32(s.Type())
+	if srid != 0 {
+		b = appendUint32(b, typ|ewkbSRIDFlag)
+		b = appendUint32(b, uint32(srid))
+	} else {
+		b = appendUint32(b, typ)
+	}
+	switch t := s.(type) {
+	case Point:
+		c := t.Coord
+		if t.Empty {
+			c = Coord{X: math.NaN(), Y: math.NaN()}
+		}
+		b = appendCoord(b, c)
+	case LineString:
+		b = appendCoords(b, t)
+	case Polygon:
+		b = appendUint32(b, uint32(len(t)))
+		for _, r := range t {
+			b = appendCoords(b, r)
+		}
+	case MultiPoint:
+		b = appendUint32(b, uint32(len(t)))
+		for _, c := range t {
+			b = appendWKB(b, 0, Point{Coord: c})
+		}
+	case MultiLineString:
+		b = appendUint32(b, uint32(len(t)))
+		for _, l := range t {
+			b = appendWKB(b, 0, l)
+		}
+	case MultiPolygon:
+		b = appendUint32(b, uint32(len(t)))
+		for _, p := range t {
+			b = appendWKB(b, 0, p)
+		}
+	case GeometryCollection:
+		b = appendUint32(b, uint32(len(t)))
+		for _, c := range t {
+			b = appendWKB(b, 0, c)
+		}
+	}
+	return b
+}
+
+// isHexWKB returns whether s looks like hex-encoded WKB: an even number of
+// hex digits starting with a byte order marker.
+func isHexWKB(s string) bool {
+	if len(s) < 10 || len(s)%2 != 0 || (s[:2] != "00" && s[:2] != "01") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+func parseHexEWKB(s string) (int32, Shape, error) {
+	b, err := hex.DecodeString(strings.ToLower(s))
+	if err != nil {
+		return 0, nil, pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid hex WKB: %v", err)
+	}
+	return parseEWKB(b)
+}
+
+func parseEWKB(b []byte) (int32, Shape, error) {
+	r := wkbReader{buf: b}
+	srid, shape, err := r.shape(true /* topLevel */)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(r.buf) != 0 {
+		return 0, nil, pgerror.New(pgerror.CodeInvalidTextRepresentationError,
+			"invalid WKB: trailing bytes")
+	}
+	return srid, shape, nil
+}
+
+// wkbReader decodes WKB and EWKB in either byte order.
+type wkbReader struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+var errWKBTruncated = pgerror.New(pgerror.CodeInvalidTextRepresentationError,
+	"invalid WKB: unexpected end of input")
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.buf) < 4 {
+		return 0, errWKBTruncated
+	}
+	v := r.order.Uint32(r.buf)
+	r.buf = r.buf[4:]
+	return v, nil
+}
+
+func (r *wkbReader) coord() (Coord, error) {
+	if len(r.buf) < 16 {
+		return Coord{}, errWKBTruncated
+	}
+	c := Coord{
+		X: math.Float64frombits(r.order.Uint64(r.buf)),
+		Y: math.Float64frombits(r.order.Uint64(r.buf[8:])),
+	}
+	r.buf = r.buf[16:]
+	return c, nil
+}
+
+// count reads a length prefix, bounding it by the remaining input so that
+// corrupt input cannot cause huge allocations.
+func (r *wkbReader) count(minElemSize int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if int64(n)*int64(minElemSize) > int64(len(r.buf)) {
+		return 0, errWKBTruncated
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) coords() ([]Coord, error) {
+	n, err := r.count(16)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	coords := make([]Coord, n)
+	for i := range coords {
+		if coords[i], err = r.coord(); err != nil {
+			return nil, err
+		}
+		if math.IsNaN(coords[i].X) || math.IsNaN(coords[i].Y) {
+			return nil, pgerror.New(pgerror.CodeInvalidTextRepresentationError,
+				"invalid WKB: NaN coordinate")
+		}
+	}
+	return coords, nil
+}
+
+func (r *wkbReader) polygon() (Polygon, error) {
+	n, err := r.count(4)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	poly := make(Polygon, n)
+	for i := range poly {
+		if poly[i], err = r.coords(); err != nil {
+			return nil, err
+		}
+		if err := checkRing(poly[i]); err != nil {
+			return nil, err
+		}
+	}
+	return poly, nil
+}
+
+// shape decodes a shape along with its header. Only the top level shape may
+// carry an SRID.
+func (r *wkbReader) shape(topLevel bool) (int32, Shape, error) {
+	if len(r.buf) < 1 {
+		return 0, nil, errWKBTruncated
+	}
+	switch r.buf[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return 0, nil, pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid WKB: unknown byte order %d", r.buf[0])
+	}
+	r.buf = r.buf[1:]
+	typ, err := r.uint32()
+	if err != nil {
+		return 0, nil, err
+	}
+	if typ&(ewkbZFlag|ewkbMFlag) != 0 || (typ&0xffff) >= 1000 {
+		return 0, nil, pgerror.New(pgerror.CodeInvalidParameterValueError,
+			"only two dimensional shapes are supported")
+	}
+	var srid int32
+	if typ&ewkbSRIDFlag != 0 {
+		if !topLevel {
+			return 0, nil, pgerror.New(pgerror.CodeInvalidTextRepresentationError,
+				"invalid WKB: SRID on nested shape")
+		}
+		v, err := r.uint32()
+		if err != nil {
+			return 0, nil, err
+		}
+		srid = int32(v)
+	}
+	var shape Shape
+	switch ShapeType(typ &^ ewkbSRIDFlag) {
+	case PointType:
+		c, err := r.coord()
+		if err != nil {
+			return 0, nil, err
+		}
+		if math.IsNaN(c.X) && math.IsNaN(c.Y) {
+			shape = Point{Empty: true}
+		} else if math.IsNaN(c.X) || math.IsNaN(c.Y) {
+			return 0, nil, pgerror.New(pgerror.CodeInvalidTextRepresentationError,
+				"invalid WKB: NaN coordinate")
+		} else {
+			shape = Point{Coord: c}
+		}
+	case LineStringType:
+		coords, err := r.coords()
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(coords) == 1 {
+			return 0, nil, pgerror.New(pgerror.CodeInvalidParameterValueError,
+				"LINESTRING requires at least two points")
+		}
+		shape = LineString(coords)
+	case PolygonType:
+		poly, err := r.polygon()
+		if err != nil {
+			return 0, nil, err
+		}
+		shape = poly
+	case MultiPointType, MultiLineStringType, MultiPolygonType, GeometryCollectionType:
+		coll := ShapeType(typ &^ ewkbSRIDFlag)
+		n, err := r.count(5)
+		if err != nil {
+			return 0, nil, err
+		}
+		var elems []Shape
+		for i := 0; i < n; i++ {
+			_, elem, err := r.shape(false /* topLevel */)
+			if err != nil {
+				return 0, nil, err
+			}
+			elems = append(elems, elem)
+		}
+		if shape, err = makeCollection(coll, elems); err != nil {
+			return 0, nil, err
+		}
+	default:
+		return 0, nil, pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid WKB: unknown shape type %d", typ)
+	}
+	return srid, shape, nil
+}
+
+// makeCollection builds a collection of the given type from its elements,
+// checking that they have the expected type.
+func makeCollection(typ ShapeType, elems []Shape) (Shape, error) {
+	wrongType := func(s Shape) error {
+		return pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"%s cannot contain a %s", typ, s.Type())
+	}
+	switch typ {
+	case MultiPointType:
+		var res MultiPoint
+		for _, e := range elems {
+			p, ok := e.(Point)
+			if !ok {
+				return nil, wrongType(e)
+			}
+			if p.Empty {
+				return nil, pgerror.New(pgerror.CodeInvalidParameterValueError,
+					"MultiPoint cannot contain empty points")
+			}
+			res = append(res, p.Coord)
+		}
+		return res, nil
+	case MultiLineStringType:
+		var res MultiLineString
+		for _, e := range elems {
+			l, ok := e.(LineString)
+			if !ok {
+				return nil, wrongType(e)
+			}
+			res = append(res, l)
+		}
+		return res, nil
+	case MultiPolygonType:
+		var res MultiPolygon
+		for _, e := range elems {
+			p, ok := e.(Polygon)
+			if !ok {
+				return nil, wrongType(e)
+			}
+			res = append(res, p)
+		}
+		return res, nil
+	default:
+		return GeometryCollection(elems), nil
+	}
+}
@@ -0,0 +1,467 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// WKT returns the Well-Known Text representation of the geometry.
+func (g Geometry) WKT() string { return formatWKT(g.Shape) }
+
+// EWKT returns the extended Well-Known Text representation of the geometry,
+// which is prefixed with the SRID unless it is zero.
+func (g Geometry) EWKT() string { return formatEWKT(g.SRID, g.Shape) }
+
+// WKT returns the Well-Known Text representation of the geography.
+func (g Geography) WKT() string { return formatWKT(g.Shape) }
+
+// EWKT returns the extended Well-Known Text representation of the
+// geography.
+func (g Geography) EWKT() string { return formatEWKT(g.SRID, g.Shape) }
+
+// ParseGeometryFromEWKT parses a geometry from WKT or EWKT.
+func ParseGeometryFromEWKT(s string) (Geometry, error) {
+	srid, shape, err := parseEWKT(strings.TrimSpace(s))
+	if err != nil {
+		return Geometry{}, err
+	}
+	return Geometry{SRID: srid, Shape: shape}, nil
+}
+
+// ParseGeographyFromEWKT parses a geography from WKT or EWKT.
+func ParseGeographyFromEWKT(s string) (Geography, error) {
+	srid, shape, err := parseEWKT(strings.TrimSpace(s))
+	if err != nil {
+		return Geography{}, err
+	}
+	return NewGeography(srid, shape)
+}
+
+func formatEWKT(srid int32, s Shape) string {
+	if srid == 0 {
+		return formatWKT(s)
+	}
+	var buf strings.Builder
+	buf.WriteString("SRID=")
+	buf.WriteString(strconv.Itoa(int(srid)))
+	buf.WriteByte(';')
+	writeWKT(&buf, s)
+	return buf.String()
+}
+
+func formatWKT(s Shape) string {
+	var buf strings.Builder
+	writeWKT(&buf, s)
+	return buf.String()
+}
+
+// formatFloat formats a coordinate using the fewest digits that represent it
+// exactly.
+func formatFloat(f float64) string {
+	if f == 0 {
+		// Avoid printing negative zero.
+		return "0"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func writeWKT(buf *strings.Builder, s Shape) {
+	buf.WriteString(strings.ToUpper(s.Type().String()))
+	if s.IsEmpty() {
+		buf.WriteString(" EMPTY")
+		return
+	}
+	writeWKTBody(buf, s)
+}
+
+// writeWKTBody writes the parenthesized contents of a non-empty shape.
+func writeWKTBody(buf *strings.Builder, s Shape) {
+	switch t := s.(type) {
+	case Point:
+		buf.WriteByte('(')
+		writeWKTCoord(buf, t.Coord)
+		buf.WriteByte(')')
+	case LineString:
+		writeWKTCoords(buf, t)
+	case Polygon:
+		buf.WriteByte('(')
+		for i, r := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeWKTCoords(buf, r)
+		}
+		buf.WriteByte(')')
+	case MultiPoint:
+		writeWKTCoords(buf, t)
+	case MultiLineString:
+		buf.WriteByte('(')
+		for i, l := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if l.IsEmpty() {
+				buf.WriteString("EMPTY")
+			} else {
+				writeWKTCoords(buf, l)
+			}
+		}
+		buf.WriteByte(')')
+	case MultiPolygon:
+		buf.WriteByte('(')
+		for i, p := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if p.IsEmpty() {
+				buf.WriteString("EMPTY")
+			} else {
+				writeWKTBody(buf, p)
+			}
+		}
+		buf.WriteByte(')')
+	case GeometryCollection:
+		buf.WriteByte('(')
+		for i, c := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeWKT(buf, c)
+		}
+		buf.WriteByte(')')
+	}
+}
+
+func writeWKTCoords(buf *strings.Builder, coords []Coord) {
+	buf.WriteByte('(')
+	for i, c := range coords {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeWKTCoord(buf, c)
+	}
+	buf.WriteByte(')')
+}
+
+func writeWKTCoord(buf *strings.Builder, c Coord) {
+	buf.WriteString(formatFloat(c.X))
+	buf.WriteByte(' ')
+	buf.WriteString(formatFloat(c.Y))
+}
+
+// wktParser is a recursive descent parser for (E)WKT.
+type wktParser struct {
+	input string
+	pos   int
+}
+
+// parseEWKT parses WKT optionally prefixed with "SRID=<srid>;".
+func parseEWKT(s string) (int32, Shape, error) {
+	var srid int32
+	if len(s) >= 5 && strings.EqualFold(s[:5], "SRID=") {
+		semi := strings.IndexByte(s, ';')
+		if semi < 0 {
+			return 0, nil, pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+				"invalid EWKT %q: missing ';' after SRID", s)
+		}
+		v, err := strconv.ParseInt(strings.TrimSpace(s[5:semi]), 10, 32)
+		if err != nil {
+			return 0, nil, pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+				"invalid EWKT %q: invalid SRID", s)
+		}
+		srid = int32(v)
+		s = s[semi+1:]
+	}
+	shape, err := parseWKT(s)
+	return srid, shape, err
+}
+
+// parseWKT parses a Well-Known Text representation of a shape.
+func parseWKT(s string) (Shape, error) {
+	p := wktParser{input: s}
+	shape, err := p.parseShape()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return shape, nil
+}
+
+func (p *wktParser) errorf(format string, args ...interface{}) error {
+	return pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+		"invalid WKT: "+format, args...)
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// word consumes and returns the next alphabetic token, upper-cased.
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.input[start:p.pos])
+}
+
+// peekEmpty consumes the EMPTY keyword if it is next.
+func (p *wktParser) peekEmpty() bool {
+	save := p.pos
+	if p.word() == "EMPTY" {
+		return true
+	}
+	p.pos = save
+	return false
+}
+
+func (p *wktParser) peek(c byte) bool {
+	p.skipSpace()
+	return p.pos < len(p.input) && p.input[p.pos] == c
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.peek(c) {
+		if p.pos >= len(p.input) {
+			return p.errorf("expected %q at end of input", c)
+		}
+		return p.errorf("expected %q at position %d", c, p.pos+1)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if (c >= '0' && c <= '9') || c == '.' || c == '-' || c == '+' || c == 'e' || c == 'E' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, p.errorf("invalid number at position %d", start+1)
+	}
+	return f, nil
+}
+
+func (p *wktParser) coord() (Coord, error) {
+	x, err := p.number()
+	if err != nil {
+		return Coord{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return Coord{}, err
+	}
+	if p.peek('-') || p.peek('.') || (p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9') {
+		return Coord{}, p.errorf("only two dimensional coordinates are supported")
+	}
+	return Coord{X: x, Y: y}, nil
+}
+
+// coords parses a parenthesized list of coordinates.
+func (p *wktParser) coords() ([]Coord, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var res []Coord
+	for {
+		c, err := p.coord()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+		if !p.peek(',') {
+			break
+		}
+		p.pos++
+	}
+	return res, p.expect(')')
+}
+
+// list parses a parenthesized, comma separated list of elements.
+func (p *wktParser) list(elem func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		if !p.peek(',') {
+			break
+		}
+		p.pos++
+	}
+	return p.expect(')')
+}
+
+func (p *wktParser) lineString() (LineString, error) {
+	if p.peekEmpty() {
+		return nil, nil
+	}
+	coords, err := p.coords()
+	if err != nil {
+		return nil, err
+	}
+	if len(coords) < 2 {
+		return nil, p.errorf("LINESTRING requires at least two points")
+	}
+	return LineString(coords), nil
+}
+
+func (p *wktParser) polygon() (Polygon, error) {
+	if p.peekEmpty() {
+		return nil, nil
+	}
+	var poly Polygon
+	err := p.list(func() error {
+		ring, err := p.coords()
+		if err != nil {
+			return err
+		}
+		if err := checkRing(ring); err != nil {
+			return err
+		}
+		poly = append(poly, ring)
+		return nil
+	})
+	return poly, err
+}
+
+func (p *wktParser) parseShape() (Shape, error) {
+	typ := p.word()
+	// Reject three and four dimensional shapes up front.
+	switch dim := p.word(); dim {
+	case "":
+	case "EMPTY":
+		p.pos -= len(dim)
+	default:
+		return nil, p.errorf("only two dimensional shapes are supported")
+	}
+	switch typ {
+	case "POINT":
+		if p.peekEmpty() {
+			return Point{Empty: true}, nil
+		}
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		c, err := p.coord()
+		if err != nil {
+			return nil, err
+		}
+		return Point{Coord: c}, p.expect(')')
+	case "LINESTRING":
+		return p.lineString()
+	case "POLYGON":
+		return p.polygon()
+	case "MULTIPOINT":
+		if p.peekEmpty() {
+			return MultiPoint(nil), nil
+		}
+		var mp MultiPoint
+		err := p.list(func() error {
+			var c Coord
+			var err error
+			if p.peek('(') {
+				p.pos++
+				if c, err = p.coord(); err != nil {
+					return err
+				}
+				err = p.expect(')')
+			} else {
+				c, err = p.coord()
+			}
+			mp = append(mp, c)
+			return err
+		})
+		return mp, err
+	case "MULTILINESTRING":
+		if p.peekEmpty() {
+			return MultiLineString(nil), nil
+		}
+		var ml MultiLineString
+		err := p.list(func() error {
+			l, err := p.lineString()
+			ml = append(ml, l)
+			return err
+		})
+		return ml, err
+	case "MULTIPOLYGON":
+		if p.peekEmpty() {
+			return MultiPolygon(nil), nil
+		}
+		var mp MultiPolygon
+		err := p.list(func() error {
+			poly, err := p.polygon()
+			mp = append(mp, poly)
+			return err
+		})
+		return mp, err
+	case "GEOMETRYCOLLECTION":
+		if p.peekEmpty() {
+			return GeometryCollection(nil), nil
+		}
+		var gc GeometryCollection
+		err := p.list(func() error {
+			s, err := p.parseShape()
+			gc = append(gc, s)
+			return err
+		})
+		return gc, err
+	case "":
+		return nil, p.errorf("expected shape type")
+	default:
+		return nil, p.errorf("unknown shape type %s", typ)
+	}
+}
+
+// checkRing returns an error if the coordinates do not form a valid polygon
+// ring.
+func checkRing(ring []Coord) error {
+	if len(ring) < 4 {
+		return pgerror.New(pgerror.CodeInvalidParameterValueError,
+			"polygon rings require at least four points")
+	}
+	if ring[0] != ring[len(ring)-1] {
+		return pgerror.New(pgerror.CodeInvalidParameterValueError,
+			"polygon rings must be closed")
+	}
+	return nil
+}
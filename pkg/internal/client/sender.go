@@ -207,8 +207,31 @@ type TxnSender interface {
 	// However, this is used by DistSQL for sending the transaction over the wire
 	// when it creates flows.
 	SerializeTxn() *roachpb.Transaction
+
+	// CreateSavepoint establishes a savepoint at the current position in the
+	// transaction. The returned token can be passed to RollbackToSavepoint to
+	// undo all the writes performed after the savepoint was created, and must
+	// eventually be passed to ReleaseSavepoint.
+	CreateSavepoint(context.Context) (SavepointToken, error)
+
+	// RollbackToSavepoint undoes all the writes performed by the transaction
+	// since the given savepoint was created. The transaction remains open and
+	// the savepoint remains valid, so it can be rolled back to again.
+	//
+	// Rolling back to a savepoint created in a previous epoch of the
+	// transaction is not possible and results in an error.
+	RollbackToSavepoint(context.Context, SavepointToken) error
+
+	// ReleaseSavepoint destroys the given savepoint. The writes performed since
+	// the savepoint was created are kept.
+	ReleaseSavepoint(context.Context, SavepointToken) error
 }
 
+// SavepointToken represents a savepoint established by
+// TxnSender.CreateSavepoint. It is opaque to the client and can only be
+// interpreted by the TxnSender that created it.
+type SavepointToken interface{}
+
 // TxnStatusOpt represents options for TxnSender.GetMeta().
 type TxnStatusOpt int
 
@@ -368,6 +391,21 @@ func (m *MockTransactionalSender) UpdateStateOnRemoteRetryableErr(
 // DisablePipelining is part of the client.TxnSender interface.
 func (m *MockTransactionalSender) DisablePipelining() error { return nil }
 
+// CreateSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) CreateSavepoint(context.Context) (SavepointToken, error) {
+	panic("unimplemented")
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) RollbackToSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
+// ReleaseSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) ReleaseSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
 // MockTxnSenderFactory is a TxnSenderFactory producing MockTxnSenders.
 type MockTxnSenderFactory struct {
 	senderFunc func(context.Context, *roachpb.Transaction, roachpb.BatchRequest) (
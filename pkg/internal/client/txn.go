@@ -293,6 +293,31 @@ func (txn *Txn) DisablePipelining() error {
 	return txn.mu.sender.DisablePipelining()
 }
 
+// CreateSavepoint establishes a savepoint at the current position in the
+// transaction. See TxnSender.CreateSavepoint.
+func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.CreateSavepoint(ctx)
+}
+
+// RollbackToSavepoint undoes the writes performed by the transaction since the
+// given savepoint was created, leaving the transaction open. See
+// TxnSender.RollbackToSavepoint.
+func (txn *Txn) RollbackToSavepoint(ctx context.Context, s SavepointToken) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.RollbackToSavepoint(ctx, s)
+}
+
+// ReleaseSavepoint destroys the given savepoint, keeping the writes performed
+// since it was created. See TxnSender.ReleaseSavepoint.
+func (txn *Txn) ReleaseSavepoint(ctx context.Context, s SavepointToken) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.ReleaseSavepoint(ctx, s)
+}
+
 // NewBatch creates and returns a new empty batch object for use with the Txn.
 func (txn *Txn) NewBatch() *Batch {
 	return &Batch{txn: txn}
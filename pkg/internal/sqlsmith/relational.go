@@ -829,8 +829,9 @@ func (s *scope) makeOrderBy(refs colRefs) tree.OrderBy {
 	var ob tree.OrderBy
 	for s.coin() {
 		ref := refs[s.schema.rnd.Intn(len(refs))]
-		// We don't support order by jsonb columns.
-		if ref.typ.Family() == types.JsonFamily {
+		// We don't support order by jsonb or spatial columns.
+		switch ref.typ.Family() {
+		case types.JsonFamily, types.GeometryFamily, types.GeographyFamily:
 			continue
 		}
 		ob = append(ob, &tree.Order{
@@ -134,6 +134,10 @@ type TxnCoordSender struct {
 
 		// onFinishFn is a closure invoked when state changes to done or aborted.
 		onFinishFn func(error)
+
+		// activeSavepoints is the number of savepoints that have been created
+		// and not yet released. See txn_coord_sender_savepoints.go.
+		activeSavepoints int
 	}
 
 	// A pointer member to the creating factory provides access to
@@ -1092,6 +1096,20 @@ func (tc *TxnCoordSender) updateStateLocked(
 
 	// This is the non-retriable error case.
 	if errTxn := pErr.GetTxn(); errTxn != nil {
+		if tc.mu.activeSavepoints > 0 && isSavepointRecoverableErr(ba, pErr) {
+			// The client can undo the effects of the failed batch by rolling
+			// back to a savepoint, so we don't move to the txnError state.
+			tc.mu.txn.Update(errTxn)
+			return pErr
+		}
+		if isWaitPolicyConflictErr(ba, pErr) {
+			// The batch asked to be told about conflicting intents rather than
+			// wait on them. At most it locked some keys without changing their
+			// values, and those locks are tracked like any other intent, so the
+			// transaction can carry on.
+			tc.mu.txn.Update(errTxn)
+			return pErr
+		}
 		tc.mu.txnState = txnError
 		tc.mu.storedErr = roachpb.NewError(&roachpb.TxnAlreadyEncounteredErrorError{
 			PrevError: pErr.String(),
@@ -1103,6 +1121,22 @@ func (tc *TxnCoordSender) updateStateLocked(
 	return pErr
 }
 
+// isWaitPolicyConflictErr returns true if the given error is a conflict
+// reported for a batch that was sent with the ERROR wait policy and that
+// contains only reads and Lock requests.
+func isWaitPolicyConflictErr(ba roachpb.BatchRequest, pErr *roachpb.Error) bool {
+	if ba.WaitPolicy != roachpb.WaitPolicy_ERROR {
+		return false
+	}
+	for _, ru := range ba.Requests {
+		if req := ru.GetInner(); !roachpb.IsReadOnly(req) && req.Method() != roachpb.Lock {
+			return false
+		}
+	}
+	_, ok := pErr.GetDetail().(*roachpb.WriteIntentError)
+	return ok
+}
+
 // setTxnAnchorKey sets the key at which to anchor the transaction record. The
 // transaction anchor key defaults to the first key written in a transaction.
 func (tc *TxnCoordSender) setTxnAnchorKeyLocked(key roachpb.Key) error {
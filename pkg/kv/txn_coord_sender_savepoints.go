@@ -0,0 +1,146 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package kv
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// savepoint is the TxnCoordSender's implementation of client.SavepointToken.
+//
+// A savepoint captures the sequence number of the last write performed by the
+// transaction before the savepoint was created. Rolling back to the savepoint
+// marks the range of sequence numbers allocated since then as ignored in the
+// transaction proto. The ignored ranges travel with every subsequent request
+// and end up in the transaction record. The MVCC layer keeps the history of a
+// transaction's writes to a key in the intent's IntentHistory, so reads by the
+// transaction skip over the values written at ignored sequence numbers, and
+// intent resolution restores the latest value that was not rolled back.
+type savepoint struct {
+	txnID    uuid.UUID
+	epoch    enginepb.TxnEpoch
+	seqNum   enginepb.TxnSeq
+	released bool
+}
+
+// CreateSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (client.SavepointToken, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.typ != client.RootTxn {
+		return nil, errors.Errorf("cannot create savepoint in non-root txn")
+	}
+	if !tc.st.Version.IsActive(cluster.VersionSavepoints) {
+		return nil, errors.Errorf("savepoints require all nodes to be upgraded to %s",
+			cluster.VersionByKey(cluster.VersionSavepoints))
+	}
+	if pErr := tc.maybeRejectClientLocked(ctx, nil /* ba */); pErr != nil {
+		return nil, pErr.GoError()
+	}
+
+	tc.mu.activeSavepoints++
+	return &savepoint{
+		txnID:  tc.mu.txn.ID,
+		epoch:  tc.mu.txn.Epoch,
+		seqNum: tc.interceptorAlloc.txnSeqNumAllocator.seqGen,
+	}, nil
+}
+
+// ReleaseSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) ReleaseSavepoint(ctx context.Context, s client.SavepointToken) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	sp := s.(*savepoint)
+	if sp.released {
+		return errors.Errorf("savepoint already released")
+	}
+	sp.released = true
+	if sp.txnID != tc.mu.txn.ID {
+		// The savepoint belongs to a previous incarnation of the transaction,
+		// which was aborted.
+		return nil
+	}
+	tc.mu.activeSavepoints--
+	return nil
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s client.SavepointToken) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	sp := s.(*savepoint)
+	if err := tc.checkSavepointLocked(ctx, sp); err != nil {
+		return err
+	}
+
+	seqGen := tc.interceptorAlloc.txnSeqNumAllocator.seqGen
+	if seqGen == sp.seqNum {
+		// Nothing was written since the savepoint was created.
+		return nil
+	}
+	log.VEventf(ctx, 2, "rolling back to savepoint at seq %d; ignoring seqs (%d, %d]",
+		sp.seqNum, sp.seqNum, seqGen)
+	tc.mu.txn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{
+		Start: sp.seqNum + 1, End: seqGen,
+	})
+	return nil
+}
+
+// checkSavepointLocked verifies that the transaction can be rolled back to the
+// provided savepoint.
+func (tc *TxnCoordSender) checkSavepointLocked(ctx context.Context, sp *savepoint) error {
+	if sp.released {
+		return errors.Errorf("cannot rollback to released savepoint")
+	}
+	if pErr := tc.maybeRejectClientLocked(ctx, nil /* ba */); pErr != nil {
+		return pErr.GoError()
+	}
+	if sp.txnID != tc.mu.txn.ID || sp.epoch != tc.mu.txn.Epoch {
+		return roachpb.NewTransactionStatusError(
+			"cannot rollback to savepoint established before the transaction restarted")
+	}
+	return nil
+}
+
+// isSavepointRecoverableErr returns true if the given error, returned for a
+// batch sent while savepoints are active, leaves the transaction in a state
+// that rolling back to a savepoint can recover from. This is the case for
+// errors that are generated by the evaluation of an individual request and do
+// not reflect a problem with the transaction as a whole. This includes
+// conflicts with other transactions' intents: SQL uses them to report lock
+// timeouts and NOWAIT conflicts, and unique violations are reported through
+// ConditionFailedErrors.
+func isSavepointRecoverableErr(ba roachpb.BatchRequest, pErr *roachpb.Error) bool {
+	if _, ok := ba.GetArg(roachpb.EndTransaction); ok {
+		return false
+	}
+	switch pErr.GetDetail().(type) {
+	case *roachpb.ConditionFailedError,
+		*roachpb.WriteIntentError,
+		*roachpb.IntegerOverflowError:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestTxnCoordSenderSavepoints verifies that rolling back to a savepoint undoes
+// the point and ranged writes performed since the savepoint was created while
+// keeping the transaction open.
+func TestTxnCoordSenderSavepoints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	s := createTestDB(t)
+	defer s.Stop()
+
+	if err := s.DB.Put(ctx, "a", "committed-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DB.Put(ctx, "c", "committed-c"); err != nil {
+		t.Fatal(err)
+	}
+
+	txn := client.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */, client.RootTxn)
+	if err := txn.Put(ctx, "b", "before"); err != nil {
+		t.Fatal(err)
+	}
+	sp1, err := txn.CreateSavepoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Put(ctx, "a", "sp1-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Put(ctx, "b", "sp1-b"); err != nil {
+		t.Fatal(err)
+	}
+	sp2, err := txn.CreateSavepoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Put(ctx, "d", "sp2-d"); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.DelRange(ctx, "a", "c\x00"); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(exp map[string]string) {
+		t.Helper()
+		kvs, err := txn.Scan(ctx, "a", "z", 0 /* maxRows */)
+		if err != nil {
+			t.Fatal(err)
+		}
+		act := make(map[string]string, len(kvs))
+		for _, kv := range kvs {
+			act[string(kv.Key)] = string(kv.ValueBytes())
+		}
+		if len(act) != len(exp) {
+			t.Fatalf("expected %v, found %v", exp, act)
+		}
+		for k, v := range exp {
+			if act[k] != v {
+				t.Fatalf("expected %v, found %v", exp, act)
+			}
+		}
+	}
+	expect(map[string]string{"d": "sp2-d"})
+
+	if err := txn.RollbackToSavepoint(ctx, sp2); err != nil {
+		t.Fatal(err)
+	}
+	expect(map[string]string{"a": "sp1-a", "b": "sp1-b", "c": "committed-c"})
+
+	// Rolling back to the outer savepoint undoes the writes performed before
+	// the inner one was created.
+	if err := txn.ReleaseSavepoint(ctx, sp2); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.RollbackToSavepoint(ctx, sp1); err != nil {
+		t.Fatal(err)
+	}
+	expect(map[string]string{"a": "committed-a", "b": "before", "c": "committed-c"})
+
+	// A savepoint can be rolled back to multiple times.
+	if err := txn.Put(ctx, "e", "sp1-e"); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.RollbackToSavepoint(ctx, sp1); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.ReleaseSavepoint(ctx, sp1); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.RollbackToSavepoint(ctx, sp1); !testutils.IsError(err, "released savepoint") {
+		t.Fatalf("expected error rolling back to released savepoint, got %v", err)
+	}
+	if err := txn.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	kvs, err := s.DB.Scan(ctx, "a", "z", 0 /* maxRows */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 3 || string(kvs[1].ValueBytes()) != "before" {
+		t.Fatalf("unexpected committed values: %v", kvs)
+	}
+}
+
+// TestTxnCoordSenderSavepointConditionFailed verifies that a ConditionFailedError
+// encountered while a savepoint is active does not poison the transaction and
+// can be recovered from by rolling back to the savepoint.
+func TestTxnCoordSenderSavepointConditionFailed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	s := createTestDB(t)
+	defer s.Stop()
+
+	txn := client.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */, client.RootTxn)
+	if err := txn.Put(ctx, "a", "1"); err != nil {
+		t.Fatal(err)
+	}
+	sp, err := txn.CreateSavepoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := txn.NewBatch()
+	b.Put("b", "2")
+	b.CPut("a", "3", "wrong")
+	err = txn.Run(ctx, b)
+	if _, ok := err.(*roachpb.ConditionFailedError); !ok {
+		t.Fatalf("expected ConditionFailedError, got %v", err)
+	}
+	if err := txn.RollbackToSavepoint(ctx, sp); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.ReleaseSavepoint(ctx, sp); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if kv, err := s.DB.Get(ctx, "b"); err != nil {
+		t.Fatal(err)
+	} else if kv.Exists() {
+		t.Fatalf("expected b to have been rolled back, found %v", kv)
+	}
+	if kv, err := s.DB.Get(ctx, "a"); err != nil {
+		t.Fatal(err)
+	} else if string(kv.ValueBytes()) != "1" {
+		t.Fatalf("expected a=1, found %v", kv)
+	}
+}
@@ -577,6 +577,9 @@ func (*SubsumeRequest) Method() Method { return Subsume }
 // Method implements the Request interface.
 func (*RangeStatsRequest) Method() Method { return RangeStats }
 
+// Method implements the Request interface.
+func (*LockRequest) Method() Method { return Lock }
+
 // ShallowCopy implements the Request interface.
 func (gr *GetRequest) ShallowCopy() Request {
 	shallowCopy := *gr
@@ -835,6 +838,12 @@ func (r *RangeStatsRequest) ShallowCopy() Request {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Request interface.
+func (r *LockRequest) ShallowCopy() Request {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
 // NewGet returns a Request initialized to get the value at key.
 func NewGet(key Key) Request {
 	return &GetRequest{
@@ -916,6 +925,16 @@ func NewInitPut(key Key, value Value, failOnTombstones bool) Request {
 	}
 }
 
+// NewLock returns a Request initialized to lock the key for the transaction
+// without changing its value.
+func NewLock(key Key) Request {
+	return &LockRequest{
+		RequestHeader: RequestHeader{
+			Key: key,
+		},
+	}
+}
+
 // NewDelete returns a Request initialized to delete the value at key.
 func NewDelete(key Key) Request {
 	return &DeleteRequest{
@@ -996,6 +1015,12 @@ func (*IncrementRequest) flags() int {
 func (*DeleteRequest) flags() int {
 	return isWrite | isTxn | isTxnWrite | consultsTSCache | canBackpressure
 }
+
+// Lock rewrites the latest value of the key as an intent, so it is a write
+// like Put as far as the timestamp cache and intent tracking are concerned.
+func (*LockRequest) flags() int {
+	return isWrite | isTxn | isTxnWrite | consultsTSCache | canBackpressure
+}
 func (drr *DeleteRangeRequest) flags() int {
 	// DeleteRangeRequest has different properties if the "inline" flag is set.
 	// This flag indicates that the request is deleting inline MVCC values,
@@ -166,6 +166,8 @@ func (ru RequestUnion) GetInner() Request {
 		return t.Subsume
 	case *RequestUnion_RangeStats:
 		return t.RangeStats
+	case *RequestUnion_Lock:
+		return t.Lock
 	default:
 		return nil
 	}
@@ -258,6 +260,8 @@ func (ru ResponseUnion) GetInner() Response {
 		return t.Subsume
 	case *ResponseUnion_RangeStats:
 		return t.RangeStats
+	case *ResponseUnion_Lock:
+		return t.Lock
 	default:
 		return nil
 	}
@@ -424,6 +428,8 @@ func (ru *RequestUnion) SetInner(r Request) bool {
 		union = &RequestUnion_Subsume{t}
 	case *RangeStatsRequest:
 		union = &RequestUnion_RangeStats{t}
+	case *LockRequest:
+		union = &RequestUnion_Lock{t}
 	default:
 		return false
 	}
@@ -519,6 +525,8 @@ func (ru *ResponseUnion) SetInner(r Response) bool {
 		union = &ResponseUnion_Subsume{t}
 	case *RangeStatsResponse:
 		union = &ResponseUnion_RangeStats{t}
+	case *LockResponse:
+		union = &ResponseUnion_Lock{t}
 	default:
 		return false
 	}
@@ -526,7 +534,7 @@ func (ru *ResponseUnion) SetInner(r Response) bool {
 	return true
 }
 
-type reqCounts [43]int32
+type reqCounts [44]int32
 
 // getReqCounts returns the number of times each
 // request type appears in the batch.
@@ -620,6 +628,8 @@ func (ba *BatchRequest) getReqCounts() reqCounts {
 			counts[41]++
 		case *RequestUnion_RangeStats:
 			counts[42]++
+		case *RequestUnion_Lock:
+			counts[43]++
 		default:
 			panic(fmt.Sprintf("unsupported request: %+v", ru))
 		}
@@ -671,6 +681,7 @@ var requestNames = []string{
 	"RefreshRng",
 	"Subsume",
 	"RngStats",
+	"Lock",
 }
 
 // Summary prints a short summary of the requests in a batch.
@@ -874,6 +885,10 @@ type rangeStatsResponseAlloc struct {
 	union ResponseUnion_RangeStats
 	resp  RangeStatsResponse
 }
+type lockResponseAlloc struct {
+	union ResponseUnion_Lock
+	resp  LockResponse
+}
 
 // CreateReply creates replies for each of the contained requests, wrapped in a
 // BatchResponse. The response objects are batch allocated to minimize
@@ -927,6 +942,7 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 	var buf40 []refreshRangeResponseAlloc
 	var buf41 []subsumeResponseAlloc
 	var buf42 []rangeStatsResponseAlloc
+	var buf43 []lockResponseAlloc
 
 	for i, r := range ba.Requests {
 		switch r.GetValue().(type) {
@@ -1231,6 +1247,13 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 			buf42[0].union.RangeStats = &buf42[0].resp
 			br.Responses[i].Value = &buf42[0].union
 			buf42 = buf42[1:]
+		case *RequestUnion_Lock:
+			if buf43 == nil {
+				buf43 = make([]lockResponseAlloc, counts[43])
+			}
+			buf43[0].union.Lock = &buf43[0].resp
+			br.Responses[i].Value = &buf43[0].union
+			buf43 = buf43[1:]
 		default:
 			panic(fmt.Sprintf("unsupported request: %+v", r))
 		}
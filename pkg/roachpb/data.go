@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
@@ -439,6 +440,14 @@ func (v *Value) SetTime(t time.Time) {
 	v.setTag(ValueType_TIME)
 }
 
+// SetTimeTZ encodes the specified time with time zone value into the bytes
+// field of the receiver, sets the tag and clears the checksum.
+func (v *Value) SetTimeTZ(t timetz.TimeTZ) {
+	v.ensureRawBytes(headerSize + encoding.EncodedTimeTZMaxLen)
+	v.RawBytes = encoding.EncodeTimeTZAscending(v.RawBytes[:headerSize], t)
+	v.setTag(ValueType_TIMETZ)
+}
+
 // SetDuration encodes the specified duration value into the bytes field of the
 // receiver, sets the tag and clears the checksum.
 func (v *Value) SetDuration(t duration.Duration) error {
@@ -563,6 +572,16 @@ func (v Value) GetTime() (time.Time, error) {
 	return t, err
 }
 
+// GetTimeTZ decodes a time with time zone value from the bytes field of the
+// receiver. If the tag is not TIMETZ an error will be returned.
+func (v Value) GetTimeTZ() (timetz.TimeTZ, error) {
+	if tag := v.GetTag(); tag != ValueType_TIMETZ {
+		return timetz.TimeTZ{}, fmt.Errorf("value type is not %s: %s", ValueType_TIMETZ, tag)
+	}
+	_, t, err := encoding.DecodeTimeTZAscending(v.dataBytes())
+	return t, err
+}
+
 // GetDuration decodes a duration value from the bytes field of the receiver. If
 // the tag is not DURATION an error will be returned.
 func (v Value) GetDuration() (duration.Duration, error) {
@@ -719,6 +738,10 @@ func (v Value) PrettyPrint() string {
 		var t time.Time
 		t, err = v.GetTime()
 		buf.WriteString(t.UTC().Format(time.RFC3339Nano))
+	case ValueType_TIMETZ:
+		var t timetz.TimeTZ
+		t, err = v.GetTimeTZ()
+		buf.WriteString(t.String())
 	case ValueType_DECIMAL:
 		var d apd.Decimal
 		d, err = v.GetDecimal()
@@ -915,6 +938,23 @@ func MakePriority(userPriority UserPriority) enginepb.TxnPriority {
 	return enginepb.TxnPriority(val)
 }
 
+// AddIgnoredSeqNumRange adds the given range to the transaction's list of
+// ignored sequence number ranges. Ranges already in the list that are contained
+// in the new range are removed. The list is replaced rather than modified in
+// place, as it may be shared with other copies of the transaction.
+func (t *Transaction) AddIgnoredSeqNumRange(newRange enginepb.IgnoredSeqNumRange) {
+	// The new range is expected to start after the end of all the ranges in
+	// the list that it does not contain, as sequence numbers are allocated in
+	// increasing order and the new range always extends up to the latest one.
+	idx := sort.Search(len(t.IgnoredSeqNums), func(i int) bool {
+		return t.IgnoredSeqNums[i].Start >= newRange.Start
+	})
+	cpy := make([]enginepb.IgnoredSeqNumRange, idx+1)
+	copy(cpy, t.IgnoredSeqNums[:idx])
+	cpy[idx] = newRange
+	t.IgnoredSeqNums = cpy
+}
+
 // Restart reconfigures a transaction for restart. The epoch is
 // incremented for an in-place restart. The timestamp of the
 // transaction on restart is set to the maximum of the transaction's
@@ -936,6 +976,9 @@ func (t *Transaction) Restart(
 	t.UpgradePriority(upgradePriority)
 	t.WriteTooOld = false
 	t.Sequence = 0
+	// Sequence numbers start over in the new epoch, so the ignored sequence
+	// numbers of the previous epoch no longer apply.
+	t.IgnoredSeqNums = nil
 	// Reset Writing. Since we're using a new epoch, we don't care about the abort
 	// cache.
 	t.DeprecatedWriting = false
@@ -998,6 +1041,9 @@ func (t *Transaction) Update(o *Transaction) {
 
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	} else if t.Epoch == o.Epoch && len(o.IgnoredSeqNums) > 0 {
+		t.IgnoredSeqNums = o.IgnoredSeqNums
 	}
 
 	t.Timestamp.Forward(o.Timestamp)
@@ -1140,6 +1186,7 @@ func (t *Transaction) AsRecord() TransactionRecord {
 	tr.OrigTimestamp = t.OrigTimestamp
 	tr.IntentSpans = t.IntentSpans
 	tr.InFlightWrites = t.InFlightWrites
+	tr.IgnoredSeqNums = t.IgnoredSeqNums
 	return tr
 }
 
@@ -1154,6 +1201,7 @@ func (tr *TransactionRecord) AsTransaction() Transaction {
 	t.OrigTimestamp = tr.OrigTimestamp
 	t.IntentSpans = tr.IntentSpans
 	t.InFlightWrites = tr.InFlightWrites
+	t.IgnoredSeqNums = tr.IgnoredSeqNums
 	return t
 }
 
@@ -1481,15 +1529,21 @@ func (l *Lease) Equal(that interface{}) bool {
 func AsIntents(spans []Span, txn *Transaction) []Intent {
 	ret := make([]Intent, len(spans))
 	for i := range spans {
-		ret[i] = Intent{
-			Span:   spans[i],
-			Txn:    txn.TxnMeta,
-			Status: txn.Status,
-		}
+		ret[i] = MakeIntent(txn, spans[i])
 	}
 	return ret
 }
 
+// MakeIntent makes an intent from the given span and txn.
+func MakeIntent(txn *Transaction, span Span) Intent {
+	return Intent{
+		Span:           span,
+		Txn:            txn.TxnMeta,
+		Status:         txn.Status,
+		IgnoredSeqNums: txn.IgnoredSeqNums,
+	}
+}
+
 // EqualValue compares for equality.
 func (s Span) EqualValue(o Span) bool {
 	return s.Key.Equal(o.Key) && s.EndKey.Equal(o.EndKey)
@@ -493,6 +493,7 @@ var nonZeroTxn = Transaction{
 	InFlightWrites:           []SequencedWrite{{Key: []byte("c"), Sequence: 1}},
 	EpochZeroTimestamp:       makeTS(1, 1),
 	OrigTimestampWasObserved: true,
+	IgnoredSeqNums:           []enginepb.IgnoredSeqNumRange{{Start: 888, End: 999}},
 }
 
 func TestTransactionUpdate(t *testing.T) {
@@ -586,6 +587,7 @@ func TestTransactionClone(t *testing.T) {
 	// listed below. If this test fails, please update the list below and/or
 	// Transaction.Clone().
 	expFields := []string{
+		"IgnoredSeqNums",
 		"InFlightWrites",
 		"InFlightWrites.Key",
 		"IntentSpans",
@@ -638,6 +640,9 @@ func TestTransactionRecordRoundtrips(t *testing.T) {
 	if !reflect.DeepEqual(txnRecord.IntentSpans, txn.IntentSpans) {
 		t.Fatalf("txnRecord.IntentSpans = %v, txn.IntentSpans = %v", txnRecord.IntentSpans, txn.IntentSpans)
 	}
+	if !reflect.DeepEqual(txnRecord.IgnoredSeqNums, txn.IgnoredSeqNums) {
+		t.Fatalf("txnRecord.IgnoredSeqNums = %v, txn.IgnoredSeqNums = %v", txnRecord.IgnoredSeqNums, txn.IgnoredSeqNums)
+	}
 
 	// Verify that converting through a Transaction message and back
 	// to a TransactionRecord is a lossless round trip.
@@ -156,4 +156,7 @@ const (
 	Subsume
 	// RangeStats returns the MVCC statistics for a range.
 	RangeStats
+	// Lock acquires an exclusive lock on a key for a transaction without
+	// changing its value.
+	Lock
 )
@@ -51,11 +51,12 @@ func _() {
 	_ = x[RefreshRange-40]
 	_ = x[Subsume-41]
 	_ = x[RangeStats-42]
+	_ = x[Lock-43]
 }
 
-const _Method_name = "GetPutConditionalPutIncrementDeleteDeleteRangeClearRangeScanReverseScanBeginTransactionEndTransactionAdminSplitAdminUnsplitAdminMergeAdminTransferLeaseAdminChangeReplicasAdminRelocateRangeHeartbeatTxnGCPushTxnRecoverTxnQueryTxnQueryIntentResolveIntentResolveIntentRangeMergeTruncateLogRequestLeaseTransferLeaseLeaseInfoComputeChecksumCheckConsistencyInitPutWriteBatchExportImportAdminScatterAddSSTableRecomputeStatsRefreshRefreshRangeSubsumeRangeStats"
+const _Method_name = "GetPutConditionalPutIncrementDeleteDeleteRangeClearRangeScanReverseScanBeginTransactionEndTransactionAdminSplitAdminUnsplitAdminMergeAdminTransferLeaseAdminChangeReplicasAdminRelocateRangeHeartbeatTxnGCPushTxnRecoverTxnQueryTxnQueryIntentResolveIntentResolveIntentRangeMergeTruncateLogRequestLeaseTransferLeaseLeaseInfoComputeChecksumCheckConsistencyInitPutWriteBatchExportImportAdminScatterAddSSTableRecomputeStatsRefreshRefreshRangeSubsumeRangeStatsLock"
 
-var _Method_index = [...]uint16{0, 3, 6, 20, 29, 35, 46, 56, 60, 71, 87, 101, 111, 123, 133, 151, 170, 188, 200, 202, 209, 219, 227, 238, 251, 269, 274, 285, 297, 310, 319, 334, 350, 357, 367, 373, 379, 391, 401, 415, 422, 434, 441, 451}
+var _Method_index = [...]uint16{0, 3, 6, 20, 29, 35, 46, 56, 60, 71, 87, 101, 111, 123, 133, 151, 170, 188, 200, 202, 209, 219, 227, 238, 251, 269, 274, 285, 297, 310, 319, 334, 350, 357, 367, 373, 379, 391, 401, 415, 422, 434, 441, 451, 455}
 
 func (i Method) String() string {
 	if i < 0 || i >= Method(len(_Method_index)-1) {
@@ -1583,6 +1583,15 @@ func (s *Server) Start(ctx context.Context) error {
 		s.distSQLServer.ServerConfig.SessionBoundInternalExecutorFactory,
 	).Start(s.stopper)
 
+	// Start the background cleanup of temporary objects left behind by
+	// sessions that did not end gracefully.
+	sql.NewTemporaryObjectCleaner(
+		s.st,
+		s.db,
+		s.execCfg.InternalExecutor,
+		s.distSQLServer.ServerConfig.SessionBoundInternalExecutorFactory,
+	).Start(ctx, s.stopper)
+
 	s.distSQLServer.Start()
 	s.pgServer.Start(ctx, s.stopper)
 
@@ -70,6 +70,25 @@ const (
 	VersionQueryTxnTimestamp
 	VersionStickyBit
 	VersionParallelCommits
+	VersionChangefeedDatabaseTargets
+	VersionRowLevelLocking
+	VersionEnums
+	VersionUserDefinedSchemas
+	VersionSpatialTypes
+	VersionTimeTZType
+	VersionUserDefinedFunctions
+	VersionDomains
+	VersionMultiDimensionalArrays
+	VersionSavepoints
+	VersionMaterializedViews
+	VersionPartialIndexes
+	VersionTemporaryTables
+	VersionTriggers
+	VersionDeferrableConstraints
+	VersionVirtualColumns
+	VersionArrayInvertedIndexes
+	VersionChangefeedDiff
+	VersionChangefeedSelect
 
 	// Add new versions here (step one of two).
 
@@ -477,6 +496,117 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionParallelCommits,
 		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 4},
 	},
+	{
+		// VersionChangefeedDatabaseTargets is CREATE CHANGEFEED FOR DATABASE.
+		Key:     VersionChangefeedDatabaseTargets,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 5},
+	},
+	{
+		// VersionRowLevelLocking is the Lock request and the wait_policy header
+		// field, used by SELECT ... FOR UPDATE, NOWAIT and SKIP LOCKED.
+		Key:     VersionRowLevelLocking,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 6},
+	},
+	{
+		// VersionEnums is user-defined ENUM types (CREATE TYPE ... AS ENUM),
+		// which are stored in TypeDescriptors.
+		Key:     VersionEnums,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 7},
+	},
+	{
+		// VersionUserDefinedSchemas is CREATE SCHEMA, which stores user-defined
+		// schemas in SchemaDescriptors.
+		Key:     VersionUserDefinedSchemas,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 8},
+	},
+	{
+		// VersionSpatialTypes is columns of the GEOMETRY and GEOGRAPHY types.
+		Key:     VersionSpatialTypes,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 9},
+	},
+	{
+		// VersionTimeTZType is columns of the TIMETZ type.
+		Key:     VersionTimeTZType,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 10},
+	},
+	{
+		// VersionUserDefinedFunctions is CREATE FUNCTION, which stores
+		// user-defined functions in FunctionDescriptors.
+		Key:     VersionUserDefinedFunctions,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 11},
+	},
+	{
+		// VersionDomains is CREATE DOMAIN, which stores domains in TypeDescriptors.
+		Key:     VersionDomains,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 12},
+	},
+	{
+		// VersionMultiDimensionalArrays is columns of multi-dimensional array
+		// types, whose values are encoded with one length per dimension.
+		Key:     VersionMultiDimensionalArrays,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 13},
+	},
+	{
+		// VersionSavepoints is regular (non-restart) savepoints, whose rollbacks
+		// are recorded in the IgnoredSeqNums of transactions and of intent
+		// resolution requests.
+		Key:     VersionSavepoints,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 14},
+	},
+	{
+		// VersionMaterializedViews is CREATE MATERIALIZED VIEW, which marks the
+		// view's TableDescriptor with is_materialized_view.
+		Key:     VersionMaterializedViews,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 15},
+	},
+	{
+		// VersionPartialIndexes is partial indexes (CREATE INDEX ... WHERE), whose
+		// predicate is stored in the IndexDescriptor.
+		Key:     VersionPartialIndexes,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 16},
+	},
+	{
+		// VersionTemporaryTables is CREATE TEMPORARY TABLE, which stores the table
+		// in a per-session temporary schema that is dropped when the session ends.
+		Key:     VersionTemporaryTables,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 17},
+	},
+	{
+		// VersionTriggers is CREATE TRIGGER, which stores triggers in the table's
+		// TableDescriptor.
+		Key:     VersionTriggers,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 18},
+	},
+	{
+		// VersionDeferrableConstraints is DEFERRABLE and INITIALLY constraints, whose
+		// deferrability is stored in the ForeignKeyReference and IndexDescriptor.
+		Key:     VersionDeferrableConstraints,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 19},
+	},
+	{
+		// VersionVirtualColumns is virtual computed columns (AS (...) VIRTUAL), which
+		// are marked as such in the ColumnDescriptor and not stored.
+		Key:     VersionVirtualColumns,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 20},
+	},
+	{
+		// VersionArrayInvertedIndexes is inverted indexes on ARRAY columns, whose
+		// entries are the key-encoded array elements.
+		Key:     VersionArrayInvertedIndexes,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 21},
+	},
+	{
+		// VersionChangefeedDiff is the diff option of CREATE CHANGEFEED, which emits
+		// the previous value of each row with its new value.
+		Key:     VersionChangefeedDiff,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 22},
+	},
+	{
+		// VersionChangefeedSelect is CREATE CHANGEFEED ... AS SELECT, which stores the
+		// projection in the ChangefeedDetails.
+		Key:     VersionChangefeedSelect,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 23},
+	},
 
 	// Add new versions here (step two of two).
 
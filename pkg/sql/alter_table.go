@@ -33,9 +33,12 @@ import (
 type alterTableNode struct {
 	n         *tree.AlterTable
 	tableDesc *MutableTableDescriptor
+	// cmds are the commands of n, in which each added column declared with a
+	// domain is followed by the CHECK constraints of the domain.
+	cmds tree.AlterTableCmds
 	// statsData is populated with data for "alter table inject statistics"
 	// commands - the JSON stats expressions.
-	// It is parallel with n.Cmds (for the inject stats commands).
+	// It is parallel with cmds (for the inject stats commands).
 	statsData map[int]tree.TypedExpr
 }
 
@@ -61,10 +64,29 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 
 	n.HoistAddColumnConstraints()
 
+	// Resolve the types of the added columns, so that the constraints of the
+	// domains they are declared with can be added along with them.
+	var cmds tree.AlterTableCmds
+	for _, cmd := range n.Cmds {
+		cmds = append(cmds, cmd)
+		if t, ok := cmd.(*tree.AlterTableAddColumn); ok {
+			checks, err := p.resolveColumnType(ctx, t.ColumnDef)
+			if err != nil {
+				return nil, err
+			}
+			for _, check := range checks {
+				cmds = append(cmds, &tree.AlterTableAddConstraint{
+					ConstraintDef:      check,
+					ValidationBehavior: tree.ValidationDefault,
+				})
+			}
+		}
+	}
+
 	// See if there's any "inject statistics" in the query and type check the
 	// expressions.
 	statsData := make(map[int]tree.TypedExpr)
-	for i, cmd := range n.Cmds {
+	for i, cmd := range cmds {
 		injectStats, ok := cmd.(*tree.AlterTableInjectStats)
 		if !ok {
 			continue
@@ -84,6 +106,7 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 	return &alterTableNode{
 		n:         n,
 		tableDesc: tableDesc,
+		cmds:      cmds,
 		statsData: statsData,
 	}, nil
 }
@@ -94,10 +117,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 	// the list.
 	descriptorChanged := false
 	origNumMutations := len(n.tableDesc.Mutations)
+	origTypeIDs := typeIDsReferencedByTable(n.tableDesc.TableDesc())
 	var droppedViews []string
 	tn := params.p.ResolvedName(n.n.Table)
 
-	for i, cmd := range n.n.Cmds {
+	for i, cmd := range n.cmds {
 		switch t := cmd.(type) {
 		case *tree.AlterTableAddColumn:
 			d := t.ColumnDef
@@ -117,6 +141,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			d = newDef
 
+			if d.Type, err = tree.ResolveType(d.Type, params.p); err != nil {
+				return err
+			}
+
+			if err := checkVirtualColumnSupported(params.p.ExecCfg().Settings, d); err != nil {
+				return err
+			}
 			col, idx, expr, err := sqlbase.MakeColumnDefDescs(d, &params.p.semaCtx)
 			if err != nil {
 				return err
@@ -193,11 +224,22 @@ func (n *alterTableNode) startExec(params runParams) error {
 					return pgerror.Newf(pgerror.CodeSyntaxError,
 						"multiple primary keys for table %q are not allowed", n.tableDesc.Name)
 				}
+				if err := checkDeferrableSupported(
+					params.p.ExecCfg().Settings, d.Deferrable,
+				); err != nil {
+					return err
+				}
 				idx := sqlbase.IndexDescriptor{
 					Name:             string(d.Name),
 					Unique:           true,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
+				if d.Deferrable != tree.NotDeferrable {
+					// As in CREATE TABLE, the index is not unique. The values of the
+					// existing rows are checked for duplicates once it is backfilled.
+					idx.Unique = false
+					idx.UniqueDeferrability = sqlbase.ConstraintDeferrabilityValue[d.Deferrable]
+				}
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
@@ -210,6 +252,15 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					idx.Partitioning = partitioning
 				}
+				if d.Predicate != nil {
+					var err error
+					if idx.Predicate, err = MakePartialIndexPredicate(
+						params.ctx, params.p.ExecCfg().Settings, n.tableDesc, &idx, d.Predicate,
+						&params.p.semaCtx, *tn,
+					); err != nil {
+						return err
+					}
+				}
 				_, dropped, err := n.tableDesc.FindIndexByName(string(d.Name))
 				if err == nil {
 					if dropped {
@@ -231,6 +282,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 				n.tableDesc.AddCheckValidationMutation(ck)
 
 			case *tree.ForeignKeyConstraintTableDef:
+				if err := checkDeferrableSupported(
+					params.p.ExecCfg().Settings, d.Deferrable,
+				); err != nil {
+					return err
+				}
 				for _, colName := range d.FromCols {
 					col, err := n.tableDesc.FindActiveColumnByName(string(colName))
 					if err != nil {
@@ -395,6 +451,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 						containsThisColumn = true
 					}
 				}
+				// A partial index also depends on the columns referenced by
+				// its predicate.
+				if used, err := idx.PredicateUsesColumn(n.tableDesc.TableDesc(), col.ID); err != nil {
+					return err
+				} else if used {
+					containsThisColumn = true
+				}
 
 				// Perform the DROP.
 				if containsThisColumn {
@@ -679,6 +742,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	if err := params.p.updateTypeBackReferences(
+		params.ctx, origTypeIDs, typeIDsReferencedByTable(n.tableDesc.TableDesc()), n.tableDesc.ID,
+	); err != nil {
+		return err
+	}
+
 	// Record this table alteration in the event log. This is an auditable log
 	// event and is recorded in the same transaction as the table descriptor
 	// update.
@@ -731,7 +800,23 @@ func applyColumnMutation(
 ) error {
 	switch t := mut.(type) {
 	case *tree.AlterTableAlterColumnType:
-		typ := t.ToType
+		typ, err := tree.ResolveType(t.ToType, params.p)
+		if err != nil {
+			return err
+		}
+		// Changing the type of a column from or to a domain is not supported:
+		// the CHECK constraints inherited from a domain are not distinguished
+		// from the other CHECK constraints of the table, and the values of the
+		// column are not validated against the constraints of a new domain.
+		if col.DomainID != sqlbase.InvalidID || typ.DomainID() != 0 {
+			from := "from"
+			if col.DomainID == sqlbase.InvalidID {
+				from = "to"
+			}
+			return pgerror.Unimplementedf("alter column type domain",
+				"changing the type of column %q %s a domain is not supported", col.Name, from).SetHintf(
+				"Add a new column of the desired type, copy the values with UPDATE and drop the old column.")
+		}
 
 		// Special handling for STRING COLLATE xy to verify that we recognize the language.
 		if t.Collation != "" {
@@ -742,8 +827,7 @@ func applyColumnMutation(
 			}
 		}
 
-		err := sqlbase.ValidateColumnDefType(typ)
-		if err != nil {
+		if err := sqlbase.ValidateColumnDefType(typ); err != nil {
 			return err
 		}
 
@@ -814,6 +898,10 @@ func applyColumnMutation(
 			return pgerror.Newf(pgerror.CodeInvalidColumnDefinitionError,
 				"column %q is not a computed column", col.Name)
 		}
+		if col.Virtual {
+			return pgerror.Newf(pgerror.CodeInvalidColumnDefinitionError,
+				"column %q is a virtual computed column, whose values are not stored", col.Name)
+		}
 		col.ComputeExpr = nil
 	}
 	return nil
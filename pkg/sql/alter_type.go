@@ -0,0 +1,162 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/enum"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type alterTypeNode struct {
+	n        *tree.AlterType
+	tn       *tree.TableName
+	typeDesc *sqlbase.TypeDescriptor
+}
+
+// AlterType applies a schema change on a user-defined type.
+// Privileges: CREATE on type.
+//   notes: postgres requires owner of the type.
+func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, error) {
+	tn, typeDesc, err := p.resolveTypeDescriptor(ctx, n.Type, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, typeDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if typeDesc.Domain != nil {
+		return nil, pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+			"%q is not an enum", tn.Table())
+	}
+	return &alterTypeNode{n: n, tn: tn, typeDesc: typeDesc}, nil
+}
+
+func (n *alterTypeNode) startExec(params runParams) error {
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterTypeAddValue:
+		added, err := params.p.addEnumValue(params.ctx, n.typeDesc, t)
+		if err != nil || !added {
+			return err
+		}
+	default:
+		return pgerror.AssertionFailedf("unknown alter type cmd: %T", t)
+	}
+
+	// Record this type alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the type descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogAlterType,
+		int32(n.typeDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.tn.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*alterTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*alterTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*alterTypeNode) Close(context.Context)        {}
+
+// addEnumValue adds a member to an enum type. The member is added in the
+// READ_ONLY state and becomes writable once a typeSchemaChanger has ensured
+// that every node knows about it. It returns false if the member already
+// exists and IF NOT EXISTS was specified.
+func (p *planner) addEnumValue(
+	ctx context.Context, typeDesc *sqlbase.TypeDescriptor, cmd *tree.AlterTypeAddValue,
+) (bool, error) {
+	if err := checkEnumLabel(cmd.NewVal); err != nil {
+		return false, err
+	}
+	findLabel := func(label tree.EnumValue) int {
+		for i := range typeDesc.EnumMembers {
+			if typeDesc.EnumMembers[i].LogicalRepresentation == string(label) {
+				return i
+			}
+		}
+		return -1
+	}
+	if findLabel(cmd.NewVal) != -1 {
+		if cmd.IfNotExists {
+			return false, nil
+		}
+		return false, pgerror.Newf(pgerror.CodeDuplicateObjectError,
+			"enum label %q already exists", cmd.NewVal)
+	}
+
+	// pos is the index at which the new member is inserted.
+	pos := len(typeDesc.EnumMembers)
+	if cmd.Placement != nil {
+		existing := findLabel(cmd.Placement.ExistingVal)
+		if existing == -1 {
+			return false, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+				"%q is not an existing enum label", cmd.Placement.ExistingVal)
+		}
+		pos = existing
+		if !cmd.Placement.Before {
+			pos++
+		}
+	}
+	var prev, next []byte
+	if pos > 0 {
+		prev = typeDesc.EnumMembers[pos-1].PhysicalRepresentation
+	}
+	if pos < len(typeDesc.EnumMembers) {
+		next = typeDesc.EnumMembers[pos].PhysicalRepresentation
+	}
+	member := sqlbase.TypeDescriptor_EnumMember{
+		PhysicalRepresentation: enum.GenByteStringBetween(prev, next),
+		LogicalRepresentation:  string(cmd.NewVal),
+		Capability:             sqlbase.TypeDescriptor_EnumMember_READ_ONLY,
+	}
+	typeDesc.EnumMembers = append(typeDesc.EnumMembers, sqlbase.TypeDescriptor_EnumMember{})
+	copy(typeDesc.EnumMembers[pos+1:], typeDesc.EnumMembers[pos:])
+	typeDesc.EnumMembers[pos] = member
+
+	if err := p.writeTypeDesc(ctx, typeDesc); err != nil {
+		return false, err
+	}
+
+	// Propagate the new version of the type to the tables that use it, so that
+	// nodes learn about the new member as they pick up the new versions of the
+	// tables.
+	typ := typeDesc.MakeTypesT()
+	for _, id := range typeDesc.ReferencingDescriptorIDs {
+		tableDesc, err := p.Tables().getMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return false, err
+		}
+		if !setReferencingColumnTypes(tableDesc, typ) {
+			continue
+		}
+		if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
+			return false, err
+		}
+	}
+
+	p.extendedEvalCtx.SchemaChangers.queueTypeSchemaChanger(typeSchemaChanger{
+		typeID:  typeDesc.ID,
+		execCfg: p.ExecCfg(),
+	})
+	return true, nil
+}
@@ -289,7 +289,12 @@ func (a *applyJoinNode) Next(params runParams) (bool, error) {
 func (a *applyJoinNode) runRightSidePlan(params runParams, plan *planTop) error {
 	a.run.curRightRow = 0
 	a.run.rightRows.Clear(params.ctx)
-	rowResultWriter := NewRowResultWriter(a.run.rightRows)
+	return runPlanInsidePlan(params, plan, NewRowResultWriter(a.run.rightRows))
+}
+
+// runPlanInsidePlan is used to run a plan and pass the results to a
+// rowResultWriter, as part of the execution of an "outer" plan.
+func runPlanInsidePlan(params runParams, plan *planTop, rowResultWriter rowResultWriter) error {
 	recv := MakeDistSQLReceiver(
 		params.ctx, rowResultWriter, tree.Rows,
 		params.extendedEvalCtx.ExecCfg.RangeDescriptorCache,
@@ -332,8 +337,7 @@ func (a *applyJoinNode) runRightSidePlan(params runParams, plan *planTop) error
 	if recv.commErr != nil {
 		return recv.commErr
 	}
-	return rowResultWriter.err
-
+	return rowResultWriter.Err()
 }
 
 func (a *applyJoinNode) Values() tree.Datums {
@@ -16,6 +16,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
@@ -452,8 +453,11 @@ func (sc *SchemaChanger) truncateIndexes(
 				if err != nil {
 					return err
 				}
+				// The eval context is used to compute the virtual computed columns
+				// of the dropped index.
+				evalCtx := createSchemaChangeEvalCtx(ctx, txn.OrigTimestamp(), &SessionTracing{}, sc.ieFactory)
 				td := tableDeleter{rd: rd, alloc: alloc}
-				if err := td.init(txn, nil /* *tree.EvalContext */); err != nil {
+				if err := td.init(txn, &evalCtx.EvalContext); err != nil {
 					return err
 				}
 				if !sc.canClearRangeForDrop(&desc) {
@@ -1009,9 +1013,15 @@ func (sc *SchemaChanger) validateForwardIndexes(
 				ie.impl.tcModifier = nil
 			}()
 
+			// A partial index only contains entries for the rows that satisfy
+			// its predicate, so only those rows are counted.
+			var where string
+			if idx.IsPartial() {
+				where = " WHERE " + idx.Predicate
+			}
 			row, err := newEvalCtx.InternalExecutor.QueryRow(ctx, "verify-idx-count", txn,
-				fmt.Sprintf(`SELECT count(1) FROM [%d AS t]@[%d] AS OF SYSTEM TIME %s`,
-					tableDesc.ID, idx.ID, readAsOf.AsOfSystemTime()))
+				fmt.Sprintf(`SELECT count(1) FROM [%d AS t]@[%d] AS OF SYSTEM TIME %s%s`,
+					tableDesc.ID, idx.ID, readAsOf.AsOfSystemTime(), where))
 			if err != nil {
 				return err
 			}
@@ -1020,6 +1030,31 @@ func (sc *SchemaChanger) validateForwardIndexes(
 			log.Infof(ctx, "validation: index %s/%s row count = %d, took %s",
 				tableDesc.Name, idx.Name, idxLen, timeutil.Since(start))
 
+			if idx.UniqueDeferrability != sqlbase.ConstraintDeferrability_NotDeferrable {
+				if err := validateDeferrableUnique(
+					ctx, newEvalCtx.InternalExecutor, txn, tableDesc, idx, readAsOf,
+				); err != nil {
+					return err
+				}
+			}
+
+			if idx.IsPartial() {
+				cnt, err := newEvalCtx.InternalExecutor.QueryRow(ctx, "verify-partial-idx-count", txn,
+					fmt.Sprintf(`SELECT count(1) FROM [%d AS t] AS OF SYSTEM TIME %s%s`,
+						tableDesc.ID, readAsOf.AsOfSystemTime(), where))
+				if err != nil {
+					return err
+				}
+				if expected := int64(tree.MustBeDInt(cnt[0])); idxLen != expected {
+					return pgerror.Newf(
+						pgerror.CodeUniqueViolationError,
+						"%d entries, expected %d violates unique constraint %q",
+						idxLen, expected, idx.Name,
+					)
+				}
+				return nil
+			}
+
 			select {
 			case <-tableCountReady:
 				if idxLen != tableRowCount {
@@ -1060,6 +1095,44 @@ func (sc *SchemaChanger) validateForwardIndexes(
 	return grp.Wait()
 }
 
+// validateDeferrableUnique verifies that the rows of the table do not hold
+// duplicate values for the index of a deferrable UNIQUE constraint. Such an
+// index is not unique, so the backfill does not detect duplicates.
+func validateDeferrableUnique(
+	ctx context.Context,
+	ie tree.SessionBoundInternalExecutor,
+	txn *client.Txn,
+	tableDesc *TableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	readAsOf hlc.Timestamp,
+) error {
+	cols := make([]string, len(idx.ColumnNames))
+	conds := make([]string, len(idx.ColumnNames), len(idx.ColumnNames)+1)
+	for i, name := range idx.ColumnNames {
+		cols[i] = tree.NameString(name)
+		conds[i] = cols[i] + " IS NOT NULL"
+	}
+	if idx.IsPartial() {
+		conds = append(conds, "("+idx.Predicate+")")
+	}
+	colList := strings.Join(cols, ", ")
+	row, err := ie.QueryRow(ctx, "verify-deferrable-unique", txn,
+		fmt.Sprintf(`SELECT %s FROM [%d AS t]@[%d] AS OF SYSTEM TIME %s WHERE %s `+
+			`GROUP BY %s HAVING count(*) > 1 LIMIT 1`,
+			colList, tableDesc.ID, idx.ID, readAsOf.AsOfSystemTime(), strings.Join(conds, " AND "),
+			colList))
+	if err != nil || row == nil {
+		return err
+	}
+	valStrs := make([]string, len(row))
+	for i, d := range row {
+		valStrs[i] = d.String()
+	}
+	return pgerror.Newf(pgerror.CodeUniqueViolationError,
+		"duplicate key value (%s)=(%s) violates unique constraint %q",
+		strings.Join(idx.ColumnNames, ","), strings.Join(valStrs, ","), idx.Name)
+}
+
 func (sc *SchemaChanger) backfillIndexes(
 	ctx context.Context,
 	evalCtx *extendedEvalContext,
@@ -1155,7 +1228,7 @@ func runSchemaChangesInTxn(
 				doneColumnBackfill = true
 
 			case *sqlbase.DescriptorMutation_Index:
-				if err := indexBackfillInTxn(ctx, txn, immutDesc, traceKV); err != nil {
+				if err := indexBackfillInTxn(ctx, txn, evalCtx, immutDesc, traceKV); err != nil {
 					return err
 				}
 
@@ -1193,7 +1266,7 @@ func runSchemaChangesInTxn(
 				doneColumnBackfill = true
 
 			case *sqlbase.DescriptorMutation_Index:
-				if err := indexTruncateInTxn(ctx, txn, execCfg, immutDesc, traceKV); err != nil {
+				if err := indexTruncateInTxn(ctx, txn, execCfg, evalCtx, immutDesc, traceKV); err != nil {
 					return err
 				}
 
@@ -1391,10 +1464,14 @@ func columnBackfillInTxn(
 }
 
 func indexBackfillInTxn(
-	ctx context.Context, txn *client.Txn, tableDesc *sqlbase.ImmutableTableDescriptor, traceKV bool,
+	ctx context.Context,
+	txn *client.Txn,
+	evalCtx *tree.EvalContext,
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	traceKV bool,
 ) error {
 	var backfiller backfill.IndexBackfiller
-	if err := backfiller.Init(tableDesc); err != nil {
+	if err := backfiller.Init(evalCtx, tableDesc); err != nil {
 		return err
 	}
 	sp := tableDesc.PrimaryIndexSpan()
@@ -1413,6 +1490,7 @@ func indexTruncateInTxn(
 	ctx context.Context,
 	txn *client.Txn,
 	execCfg *ExecutorConfig,
+	evalCtx *tree.EvalContext,
 	tableDesc *sqlbase.ImmutableTableDescriptor,
 	traceKV bool,
 ) error {
@@ -1427,7 +1505,7 @@ func indexTruncateInTxn(
 			return err
 		}
 		td := tableDeleter{rd: rd, alloc: alloc}
-		if err := td.init(txn, nil /* *tree.EvalContext */); err != nil {
+		if err := td.init(txn, evalCtx); err != nil {
 			return err
 		}
 		sp, err = td.deleteIndex(
@@ -123,6 +123,7 @@ func (cb *ColumnBackfiller) Init(
 		ValNeededForCol: valNeededForCol,
 	}
 	return cb.fetcher.Init(
+		cb.evalCtx,
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &cb.alloc, tableArgs,
 	)
 }
@@ -294,6 +295,10 @@ type IndexBackfiller struct {
 
 	types   []types.T
 	rowVals tree.Datums
+
+	// predicates are the predicates of the partial indexes among the added
+	// indexes.
+	predicates sqlbase.PartialIndexPredicates
 }
 
 // ContainsInvertedIndex returns true if backfilling an inverted index.
@@ -307,7 +312,9 @@ func (ib *IndexBackfiller) ContainsInvertedIndex() bool {
 }
 
 // Init initializes an IndexBackfiller.
-func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
+func (ib *IndexBackfiller) Init(
+	evalCtx *tree.EvalContext, desc *sqlbase.ImmutableTableDescriptor,
+) error {
 	numCols := len(desc.Columns)
 	cols := desc.Columns
 	if len(desc.Mutations) > 0 {
@@ -331,15 +338,29 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 		if IndexMutationFilter(m) {
 			idx := m.GetIndex()
 			ib.added = append(ib.added, *idx)
+			predicateColIDs, err := idx.PredicateColumnIDs(desc.TableDesc())
+			if err != nil {
+				return err
+			}
 			for i := range cols {
 				id := cols[i].ID
 				if idx.ContainsColumnID(id) {
 					valNeededForCol.Add(i)
 				}
+				for _, predicateColID := range predicateColIDs {
+					if id == predicateColID {
+						valNeededForCol.Add(i)
+					}
+				}
 			}
 		}
 	}
 
+	var err error
+	if ib.predicates, err = sqlbase.MakePartialIndexPredicates(desc, ib.added, evalCtx); err != nil {
+		return err
+	}
+
 	ib.types = make([]types.T, len(cols))
 	for i := range cols {
 		ib.types[i] = cols[i].Type
@@ -358,6 +379,7 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 		ValNeededForCol: valNeededForCol,
 	}
 	return ib.fetcher.Init(
+		evalCtx,
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &ib.alloc, tableArgs,
 	)
 }
@@ -418,6 +440,26 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 			ib.rowVals, buffer); err != nil {
 			return nil, nil, err
 		}
+		if !ib.predicates.Empty() {
+			// Only the rows satisfying the predicate of a partial index have an
+			// entry in it. Partial indexes cannot be inverted, so the first
+			// len(ib.added) entries belong to the respective added indexes.
+			for j := range ib.added {
+				holds, err := ib.predicates.Holds(j, ib.colIdxMap, ib.rowVals)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !holds {
+					buffer[j] = sqlbase.IndexEntry{}
+				}
+			}
+			for j := range buffer {
+				if buffer[j].Key != nil {
+					entries = append(entries, buffer[j])
+				}
+			}
+			continue
+		}
 		entries = append(entries, buffer...)
 	}
 	return entries, ib.fetcher.Key(), nil
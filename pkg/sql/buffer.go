@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 // bufferNode consumes its input one row at a time, stores it in the buffer,
@@ -31,6 +32,14 @@ type bufferNode struct {
 	// processors, but this node is local.
 	bufferedRows       *rowcontainer.RowContainer
 	passThruNextRowIdx int
+
+	// workingRows is set instead of bufferedRows when the node refers to the
+	// working table of a recursive CTE, which is filled by the recursive CTE
+	// processor rather than by running plan.
+	workingRows *rowcontainer.DiskBackedRowContainer
+
+	// label is a string used to describe the node in an EXPLAIN output.
+	label string
 }
 
 func (n *bufferNode) startExec(params runParams) error {
@@ -76,20 +85,64 @@ type scanBufferNode struct {
 	buffer *bufferNode
 
 	nextRowIdx int
+
+	// iter iterates over the working table of a recursive CTE, if the buffer
+	// refers to one; row holds the decoded current row.
+	iter  rowcontainer.RowIterator
+	row   tree.Datums
+	types []types.T
+	alloc sqlbase.DatumAlloc
+
+	// label is a string used to describe the node in an EXPLAIN output.
+	label string
 }
 
-func (n *scanBufferNode) startExec(runParams) error {
+func (n *scanBufferNode) startExec(params runParams) error {
+	if n.buffer.workingRows != nil {
+		cols := planColumns(n.buffer.plan)
+		n.types = make([]types.T, len(cols))
+		for i := range cols {
+			n.types[i] = *cols[i].Typ
+		}
+		n.row = make(tree.Datums, len(cols))
+		n.iter = n.buffer.workingRows.NewIterator(params.ctx)
+		n.iter.Rewind()
+	}
 	return nil
 }
 
 func (n *scanBufferNode) Next(runParams) (bool, error) {
+	if n.iter != nil {
+		if ok, err := n.iter.Valid(); err != nil || !ok {
+			return false, err
+		}
+		encRow, err := n.iter.Row()
+		if err != nil {
+			return false, err
+		}
+		for i := range encRow {
+			if err := encRow[i].EnsureDecoded(&n.types[i], &n.alloc); err != nil {
+				return false, err
+			}
+			n.row[i] = encRow[i].Datum
+		}
+		n.iter.Next()
+		return true, nil
+	}
 	n.nextRowIdx++
 	return n.nextRowIdx <= n.buffer.bufferedRows.Len(), nil
 }
 
 func (n *scanBufferNode) Values() tree.Datums {
+	if n.iter != nil {
+		return n.row
+	}
 	return n.buffer.bufferedRows.At(n.nextRowIdx - 1)
 }
 
 func (n *scanBufferNode) Close(context.Context) {
+	if n.iter != nil {
+		n.iter.Close()
+		n.iter = nil
+	}
 }
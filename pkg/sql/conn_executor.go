@@ -642,6 +642,10 @@ func (s *Server) newConnExecutorWithTxn(
 		txn,
 		ex.transitionCtx)
 
+	// The transaction is committed by the parent executor, which cannot run
+	// the checks deferred by this executor.
+	ex.extraTxnState.constraints.boundTxn = true
+
 	// Modify the TableCollection to match the parent executor's TableCollection.
 	// This allows the InternalExecutor to see schema changes made by the
 	// parent executor.
@@ -750,6 +754,20 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		log.Warningf(ctx, "error while cleaning up connExecutor: %s", err)
 	}
 
+	// Drop the temporary objects created by the session. Only the session that
+	// owns the temporary schema does this: internal executors bound to the
+	// session share its search path, but have a session ID of their own.
+	if tempSchemaName := ex.sessionData.SearchPath.GetTemporarySchemaName(); closeType != panicClose &&
+		tempSchemaName != "" && tempSchemaName == temporarySchemaName(ex.sessionID) {
+		ie := NewSessionBoundInternalExecutor(
+			ctx, ex.sessionData, ex.server, ex.memMetrics, ex.server.cfg.Settings,
+		)
+		cleanupCtx := ex.server.cfg.AmbientCtx.AnnotateCtx(context.Background())
+		if err := cleanupSessionTempObjects(cleanupCtx, ex.server.cfg.DB, ie, tempSchemaName); err != nil {
+			log.Warningf(ctx, "error while cleaning up temporary objects: %s", err)
+		}
+	}
+
 	if closeType != panicClose {
 		// Close all statements and prepared portals.
 		ex.extraTxnState.prepStmtsNamespace.resetTo(ctx, prepStmtNamespace{})
@@ -840,6 +858,11 @@ type connExecutor struct {
 		// is done if the statement was executed in an implicit txn).
 		schemaChangers schemaChangerCollection
 
+		// constraints queues the checks of the deferrable constraints, which
+		// are run at the end of each statement or when the transaction
+		// commits.
+		constraints deferredConstraints
+
 		// autoRetryCounter keeps track of the which iteration of a transaction
 		// auto-retry we're currently in. It's 0 whenever the transaction state is not
 		// stateOpen.
@@ -1019,6 +1042,7 @@ func (ex *connExecutor) resetExtraTxnState(
 	ctx context.Context, dbCacheHolder *databaseCacheHolder,
 ) error {
 	ex.extraTxnState.schemaChangers.reset()
+	ex.extraTxnState.constraints.reset()
 
 	ex.extraTxnState.tables.releaseTables(ctx)
 
@@ -1515,8 +1539,12 @@ func (ex *connExecutor) setTxnRewindPos(ctx context.Context, pos CmdPos) {
 // retried when performing automatic retries. This means that the results of the
 // statement do not change with retries.
 func (ex *connExecutor) stmtDoesntNeedRetry(stmt tree.Statement) bool {
+	if s, ok := stmt.(*tree.Savepoint); ok {
+		// Regular savepoints need to be re-established when retrying.
+		return ex.isRestartSavepoint(s.Name)
+	}
 	wrap := Statement{Statement: parser.Statement{AST: stmt}}
-	return isSavepoint(wrap) || isSetTransaction(wrap)
+	return isSetTransaction(wrap)
 }
 
 func stateToTxnStatusIndicator(s fsm.State) TransactionStatusIndicator {
@@ -1658,6 +1686,11 @@ func (ex *connExecutor) getRewindTxnCapability() (rewindCapability, bool) {
 		cl.Close()
 		return rewindCapability{}, false
 	}
+	// Retrying the transaction would invalidate the active savepoints.
+	if len(ex.state.savepoints) > 0 {
+		cl.Close()
+		return rewindCapability{}, false
+	}
 	return rewindCapability{
 		cl:        cl,
 		buf:       ex.stmtBuf,
@@ -1798,6 +1831,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			ReCache:          ex.server.reCache,
 			InternalExecutor: ie,
 			DB:               ex.server.cfg.DB,
+			Constraints:      &ex.extraTxnState.constraints,
 		},
 		SessionMutator:  ex.dataMutator,
 		VirtualSchemas:  ex.server.cfg.VirtualSchemas,
@@ -1809,6 +1843,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		DistSQLPlanner:  ex.server.cfg.DistSQLPlanner,
 		TxnModesSetter:  ex,
 		SchemaChangers:  &ex.extraTxnState.schemaChangers,
+		Constraints:     &ex.extraTxnState.constraints,
 		schemaAccessors: scInterface,
 	}
 }
@@ -1823,6 +1858,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 func (ex *connExecutor) resetEvalCtx(
 	evalCtx *extendedEvalContext, txn *client.Txn, stmtTS time.Time,
 ) {
+	evalCtx.SessionID = ex.sessionID
 	evalCtx.TxnState = ex.getTransactionState()
 	evalCtx.TxnReadOnly = ex.state.readOnly
 	evalCtx.TxnImplicit = ex.implicitTxn()
@@ -1886,6 +1922,8 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx = tree.MakeSemaContext()
 	p.semaCtx.Location = &ex.sessionData.DataConversion.Location
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 	p.semaCtx.AsOfTimestamp = nil
 	p.semaCtx.Annotations = tree.MakeAnnotations(numAnnotations)
 
@@ -2157,9 +2195,9 @@ type StatementCounters struct {
 	TxnCommitCount   telemetry.CounterWithMetric
 	TxnRollbackCount telemetry.CounterWithMetric
 
-	// Savepoint operations. SavepointCount is for real SQL savepoints
-	// (which we don't yet support; this is just a placeholder for
-	// telemetry); the RestartSavepoint variants are for the
+	// Savepoint operations. SavepointCount is for regular SQL savepoints
+	// (RELEASE and ROLLBACK TO of regular savepoints are counted as
+	// miscellaneous statements); the RestartSavepoint variants are for the
 	// cockroach-specific client-side retry protocol.
 	SavepointCount                  telemetry.CounterWithMetric
 	RestartSavepointCount           telemetry.CounterWithMetric
@@ -2257,15 +2295,23 @@ func (sc *StatementCounters) incrementCount(ex *connExecutor, stmt tree.Statemen
 	case *tree.RollbackTransaction:
 		sc.TxnRollbackCount.Inc()
 	case *tree.Savepoint:
-		if err := ex.validateSavepointName(t.Name); err == nil {
+		if ex.isRestartSavepoint(t.Name) {
 			sc.RestartSavepointCount.Inc()
 		} else {
 			sc.SavepointCount.Inc()
 		}
 	case *tree.ReleaseSavepoint:
-		sc.ReleaseRestartSavepointCount.Inc()
+		if ex.isRestartSavepoint(t.Savepoint) {
+			sc.ReleaseRestartSavepointCount.Inc()
+		} else {
+			sc.MiscCount.Inc()
+		}
 	case *tree.RollbackToSavepoint:
-		sc.RollbackToRestartSavepointCount.Inc()
+		if ex.isRestartSavepoint(t.Savepoint) {
+			sc.RollbackToRestartSavepointCount.Inc()
+		} else {
+			sc.MiscCount.Inc()
+		}
 	default:
 		if tree.CanModifySchema(stmt) {
 			sc.DdlCount.Inc()
@@ -136,6 +136,8 @@ func (ex *connExecutor) execStmtInOpenState(
 	defer func() {
 		if retErr == nil && !payloadHasError(retPayload) {
 			ex.incrementExecutedStmtCounter(stmt)
+		} else {
+			ex.extraTxnState.constraints.discardStatementChecks()
 		}
 	}()
 	os := ex.machine.CurState().(stateOpen)
@@ -231,6 +233,10 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.ReleaseSavepoint:
+		if !ex.isRestartSavepoint(s.Savepoint) {
+			ev, payload := ex.execReleaseSavepointInOpenState(ctx, s)
+			return ev, payload, nil
+		}
 		if err := ex.validateSavepointName(s.Savepoint); err != nil {
 			return makeErrEvent(err)
 		}
@@ -249,6 +255,10 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.Savepoint:
+		if !ex.isRestartSavepoint(s.Name) {
+			ev, payload := ex.execSavepointInOpenState(ctx, s)
+			return ev, payload, nil
+		}
 		// Ensure that the user isn't trying to run BEGIN; SAVEPOINT; SAVEPOINT;
 		if ex.state.activeSavepointName != "" {
 			err := pgerror.UnimplementedWithIssueDetail(10735, "nested", "SAVEPOINT may not be nested")
@@ -265,7 +275,7 @@ func (ex *connExecutor) execStmtInOpenState(
 		// See also:
 		// https://github.com/cockroachdb/cockroach/issues/15012
 		meta := ex.state.mu.txn.GetTxnCoordMeta(ctx)
-		if meta.CommandCount > 0 {
+		if meta.CommandCount > 0 || len(ex.state.savepoints) > 0 {
 			err := pgerror.Newf(pgerror.CodeSyntaxError,
 				"SAVEPOINT %s needs to be the first statement in a "+
 					"transaction", RestartSavepointName)
@@ -277,6 +287,10 @@ func (ex *connExecutor) execStmtInOpenState(
 		return eventRetryIntentSet{}, nil /* payload */, nil
 
 	case *tree.RollbackToSavepoint:
+		if !ex.isRestartSavepoint(s.Savepoint) {
+			ev, payload := ex.execRollbackToSavepoint(ctx, s)
+			return ev, payload, nil
+		}
 		if err := ex.validateSavepointName(s.Savepoint); err != nil {
 			return makeErrEvent(err)
 		}
@@ -284,6 +298,8 @@ func (ex *connExecutor) execStmtInOpenState(
 			return makeErrEvent(errSavepointNotUsed)
 		}
 		ex.state.activeSavepointName = ""
+		// Restarting the transaction invalidates all the other savepoints.
+		ex.clearSavepoints(ctx)
 
 		res.ResetStmtType((*tree.Savepoint)(nil))
 		return eventTxnRestart{}, nil /* payload */, nil
@@ -572,6 +588,10 @@ func (ex *connExecutor) commitSQLTransaction(
 		isRelease = true
 	}
 
+	if err := ex.extraTxnState.constraints.runDeferred(ctx, ex.state.mu.txn); err != nil {
+		return ex.makeErrEvent(err, stmt)
+	}
+
 	if err := ex.checkTableTwoVersionInvariant(ctx); err != nil {
 		return ex.makeErrEvent(err, stmt)
 	}
@@ -846,6 +866,12 @@ func canFallbackFromOpt(err error, optMode sessiondata.OptimizerMode, stmt *Stat
 	if _, ok := stmt.AST.(*tree.CannedOptPlan); ok {
 		return false
 	}
+
+	// Row-level locking clauses are only supported by the optimizer, so
+	// return its error rather than falling back.
+	if sel, ok := stmt.AST.(*tree.Select); ok && sel.Locking != nil {
+		return false
+	}
 	return true
 }
 
@@ -1036,6 +1062,19 @@ func (ex *connExecutor) execStmtInAbortedState(
 		default:
 			panic("unreachable")
 		}
+		if !ex.isRestartSavepoint(spName) {
+			// Regular savepoints. Only ROLLBACK TO SAVEPOINT is allowed here, and
+			// only if the transaction hasn't been restarted.
+			customMsg := ""
+			if inRestartWait {
+				customMsg = "Expected \"ROLLBACK TO SAVEPOINT COCKROACH_RESTART\""
+			} else if isRollback {
+				return ex.execRollbackToSavepoint(ctx, s.(*tree.RollbackToSavepoint))
+			}
+			return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{
+				err: sqlbase.NewTransactionAbortedError(customMsg),
+			}
+		}
 		// If the user issued a SAVEPOINT in the abort state, validate
 		// as though there were no active savepoint.
 		if !isRollback {
@@ -1066,6 +1105,8 @@ func (ex *connExecutor) execStmtInAbortedState(
 
 		res.ResetStmtType((*tree.RollbackTransaction)(nil))
 
+		// Restarting the transaction invalidates all the regular savepoints.
+		ex.clearSavepoints(ctx)
 		if inRestartWait {
 			return eventTxnRestart{}, nil
 		}
@@ -1328,11 +1369,8 @@ func payloadHasError(payload fsm.EventPayload) bool {
 	return hasErr
 }
 
-// validateSavepointName validates that it is that the provided ident
-// matches the active savepoint name, begins with RestartSavepointName,
-// or that force_savepoint_restart==true. We accept everything with the
-// desired prefix because at least the C++ libpqxx appends sequence
-// numbers to the savepoint name specified by the user.
+// validateSavepointName validates that the provided restart savepoint name
+// (see isRestartSavepoint) matches the active restart savepoint name, if any.
 func (ex *connExecutor) validateSavepointName(savepoint tree.Name) error {
 	if ex.state.activeSavepointName != "" {
 		if savepoint == ex.state.activeSavepointName {
@@ -1341,11 +1379,5 @@ func (ex *connExecutor) validateSavepointName(savepoint tree.Name) error {
 		return pgerror.Newf(pgerror.CodeInvalidSavepointSpecificationError,
 			`SAVEPOINT %q is in use`, tree.ErrString(&ex.state.activeSavepointName))
 	}
-	if !ex.sessionData.ForceSavepointRestart && !strings.HasPrefix(string(savepoint), RestartSavepointName) {
-		return pgerror.UnimplementedWithIssueHint(10735,
-			"SAVEPOINT not supported except for "+RestartSavepointName,
-			"Retryable transactions with arbitrary SAVEPOINT names can be enabled "+
-				"with SET force_savepoint_restart=true")
-	}
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/lib/pq/oid"
@@ -339,6 +340,15 @@ func (ex *connExecutor) execBind(
 			if arg == nil {
 				// nil indicates a NULL argument value.
 				qargs[k] = tree.DNull
+			} else if typ, ok := ps.ValueType(k); ok && typ.Family() == types.EnumFamily {
+				// Enum values are sent as their labels in both the text and the
+				// binary formats. The OID of a user-defined type is not enough to
+				// decode it, so use the placeholder's type instead.
+				d, err := tree.MakeDEnumFromLogicalRepresentation(typ, string(arg))
+				if err != nil {
+					return retErr(err)
+				}
+				qargs[k] = d
 			} else {
 				d, err := pgwirebase.DecodeOidDatum(ptCtx, t, qArgFormatCodes[i], arg)
 				if err != nil {
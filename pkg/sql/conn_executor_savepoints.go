@@ -0,0 +1,180 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// This file contains the handling of regular (as opposed to the
+// cockroach_restart one) savepoints. Regular savepoints can be nested, and
+// ROLLBACK TO SAVEPOINT undoes the writes performed since the savepoint was
+// established while keeping the transaction open. This is implemented by the
+// KV transaction; see client.TxnSender.RollbackToSavepoint.
+//
+// When a statement fails while there are active savepoints, the transaction
+// moves to the Aborted state as usual, but the KV transaction is not rolled
+// back; a subsequent ROLLBACK TO SAVEPOINT moves the transaction back to the
+// Open state.
+
+// savepoint is a savepoint established through a SAVEPOINT statement.
+type savepoint struct {
+	name  tree.Name
+	token client.SavepointToken
+	// constraintsMark identifies the deferred constraint checks queued
+	// before the savepoint was established.
+	constraintsMark int
+}
+
+// savepointStack is the stack of active savepoints, ordered from the oldest to
+// the most recently established one.
+type savepointStack []savepoint
+
+// find returns the index of the most recently established savepoint with the
+// given name, or -1 if there is none.
+func (s savepointStack) find(name tree.Name) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// isRestartSavepoint returns true if the given savepoint name refers to the
+// special restart savepoint (see RestartSavepointName). We accept everything
+// with the desired prefix because at least the C++ libpqxx appends sequence
+// numbers to the savepoint name specified by the user.
+func (ex *connExecutor) isRestartSavepoint(savepoint tree.Name) bool {
+	return ex.sessionData.ForceSavepointRestart ||
+		strings.HasPrefix(string(savepoint), RestartSavepointName)
+}
+
+// execSavepointInOpenState establishes a regular savepoint.
+func (ex *connExecutor) execSavepointInOpenState(
+	ctx context.Context, s *tree.Savepoint,
+) (fsm.Event, fsm.EventPayload) {
+	if ex.implicitTxn() {
+		return ex.makeErrEvent(errNoTransactionInProgress, s)
+	}
+	// Nodes running older versions drop the ignored sequence numbers when
+	// resolving intents, which would commit the writes rolled back to a
+	// savepoint.
+	if !ex.server.cfg.Settings.Version.IsActive(cluster.VersionSavepoints) {
+		return ex.makeErrEvent(pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`SAVEPOINT requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionSavepoints),
+		), s)
+	}
+	token, err := ex.state.mu.txn.CreateSavepoint(ctx)
+	if err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+	ex.state.savepoints = append(ex.state.savepoints, savepoint{
+		name:            s.Name,
+		token:           token,
+		constraintsMark: ex.extraTxnState.constraints.savepointMark(),
+	})
+	return nil, nil
+}
+
+// execReleaseSavepointInOpenState destroys a regular savepoint along with all
+// the savepoints established after it. The writes performed since it was
+// established are kept.
+func (ex *connExecutor) execReleaseSavepointInOpenState(
+	ctx context.Context, s *tree.ReleaseSavepoint,
+) (fsm.Event, fsm.EventPayload) {
+	idx := ex.state.savepoints.find(s.Savepoint)
+	if idx == -1 {
+		return ex.makeErrEvent(errSavepointDoesNotExist(s.Savepoint), s)
+	}
+	if err := ex.popSavepoints(ctx, idx); err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+	return nil, nil
+}
+
+// execRollbackToSavepoint undoes the writes performed since a regular
+// savepoint was established and destroys all the savepoints established after
+// it. The savepoint itself remains active. It can be executed both in the
+// Open and in the Aborted states; in the latter case, the transaction moves
+// back to the Open state.
+func (ex *connExecutor) execRollbackToSavepoint(
+	ctx context.Context, s *tree.RollbackToSavepoint,
+) (fsm.Event, fsm.EventPayload) {
+	_, inOpen := ex.machine.CurState().(stateOpen)
+	makeErrEvent := func(err error) (fsm.Event, fsm.EventPayload) {
+		if inOpen {
+			return ex.makeErrEvent(err, s)
+		}
+		return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}
+	}
+
+	idx := ex.state.savepoints.find(s.Savepoint)
+	if idx == -1 {
+		return makeErrEvent(errSavepointDoesNotExist(s.Savepoint))
+	}
+	// Rolling back the KV writes of a schema change would leave the
+	// transaction's cached descriptors and staged schema changers behind.
+	if ex.extraTxnState.tables.hasUncommittedTables() ||
+		len(ex.extraTxnState.tables.uncommittedDatabases) > 0 {
+		return makeErrEvent(pgerror.UnimplementedWithIssue(10735,
+			"ROLLBACK TO SAVEPOINT in a transaction that performed schema changes"))
+	}
+	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, ex.state.savepoints[idx].token); err != nil {
+		return makeErrEvent(err)
+	}
+	// The rolled back writes no longer need their constraints checked.
+	ex.extraTxnState.constraints.rollbackToSavepoint(ex.state.savepoints[idx].constraintsMark)
+	if err := ex.popSavepoints(ctx, idx+1); err != nil {
+		return makeErrEvent(err)
+	}
+	if inOpen {
+		return nil, nil
+	}
+	return eventSavepointRollback{}, nil
+}
+
+// popSavepoints releases the savepoints at positions idx and above.
+func (ex *connExecutor) popSavepoints(ctx context.Context, idx int) error {
+	for i := len(ex.state.savepoints) - 1; i >= idx; i-- {
+		if err := ex.state.mu.txn.ReleaseSavepoint(ctx, ex.state.savepoints[i].token); err != nil {
+			return err
+		}
+		ex.state.savepoints = ex.state.savepoints[:i]
+	}
+	return nil
+}
+
+// clearSavepoints discards all the regular savepoints. It is used when the
+// transaction is restarted through the cockroach_restart savepoint, which
+// invalidates them.
+func (ex *connExecutor) clearSavepoints(ctx context.Context) {
+	if err := ex.popSavepoints(ctx, 0); err != nil {
+		log.Warningf(ctx, "error releasing savepoints: %s", err)
+		ex.state.savepoints = nil
+	}
+}
+
+func errSavepointDoesNotExist(name tree.Name) error {
+	return pgerror.Newf(pgerror.CodeInvalidSavepointSpecificationError,
+		"savepoint %s does not exist", tree.ErrString(&name))
+}
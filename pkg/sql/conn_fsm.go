@@ -209,18 +209,24 @@ var _ payloadWithError = eventRetriableErrPayload{}
 // cockroach_restart. It moves the state to CommitWait.
 type eventTxnReleased struct{}
 
+// eventSavepointRollback is generated after a successful ROLLBACK TO SAVEPOINT
+// for a regular savepoint (i.e. not cockroach_restart) in the Aborted state. It
+// moves the state back to Open.
+type eventSavepointRollback struct{}
+
 // payloadWithError is a common interface for the payloads that wrap an error.
 type payloadWithError interface {
 	errorCause() error
 }
 
-func (eventRetryIntentSet) Event()  {}
-func (eventTxnStart) Event()        {}
-func (eventTxnFinish) Event()       {}
-func (eventTxnRestart) Event()      {}
-func (eventNonRetriableErr) Event() {}
-func (eventRetriableErr) Event()    {}
-func (eventTxnReleased) Event()     {}
+func (eventRetryIntentSet) Event()    {}
+func (eventTxnStart) Event()          {}
+func (eventTxnFinish) Event()         {}
+func (eventTxnRestart) Event()        {}
+func (eventNonRetriableErr) Event()   {}
+func (eventRetriableErr) Event()      {}
+func (eventTxnReleased) Event()       {}
+func (eventSavepointRollback) Event() {}
 
 // TxnStateTransitions describe the transitions used by a connExecutor's
 // fsm.Machine. Args.Extended is a txnState, which is muted by the Actions.
@@ -326,7 +332,7 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 			Next: stateAborted{RetryIntent: fsm.Var("retryIntent")},
 			Action: func(args fsm.Args) error {
 				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
+				ts.cleanupOnError(args.Payload.(payloadWithError).errorCause())
 				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
 				ts.txnAbortCount.Inc(1)
 				return nil
@@ -353,7 +359,7 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 			Next:        stateAborted{RetryIntent: fsm.False},
 			Action: func(args fsm.Args) error {
 				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
+				ts.cleanupOnError(args.Payload.(payloadWithError).errorCause())
 				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
 				ts.txnAbortCount.Inc(1)
 				return nil
@@ -417,7 +423,23 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 			Description: "any other statement",
 			Next:        stateAborted{RetryIntent: fsm.Var("retryIntent")},
 			Action: func(args fsm.Args) error {
-				args.Extended.(*txnState).setAdvanceInfo(skipBatch, noRewind, noEvent)
+				ts := args.Extended.(*txnState)
+				if args.Event.(eventNonRetriableErr).IsCommit.Get() {
+					// The connExecutor is closing; nobody is going to roll back to
+					// a savepoint anymore.
+					ts.rollbackIfCleanupDeferred()
+				}
+				ts.setAdvanceInfo(skipBatch, noRewind, noEvent)
+				return nil
+			},
+		},
+		eventSavepointRollback{}: {
+			Description: "ROLLBACK TO SAVEPOINT (not cockroach_restart)",
+			Next:        stateOpen{ImplicitTxn: fsm.False, RetryIntent: fsm.Var("retryIntent")},
+			Action: func(args fsm.Args) error {
+				ts := args.Extended.(*txnState)
+				ts.cleanupDeferred = false
+				ts.setAdvanceInfo(advanceOne, noRewind, noEvent)
 				return nil
 			},
 		},
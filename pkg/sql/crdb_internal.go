@@ -220,11 +220,14 @@ CREATE TABLE crdb_internal.tables (
 			return err
 		}
 		dbNames := make(map[sqlbase.ID]string)
-		// Record database descriptors for name lookups.
+		scNames := make(map[sqlbase.ID]string)
+		// Record database and schema descriptors for name lookups.
 		for _, desc := range descs {
-			db, ok := desc.(*sqlbase.DatabaseDescriptor)
-			if ok {
-				dbNames[db.ID] = db.Name
+			switch d := desc.(type) {
+			case *sqlbase.DatabaseDescriptor:
+				dbNames[d.ID] = d.Name
+			case *sqlbase.SchemaDescriptor:
+				scNames[d.ID] = d.Name
 			}
 		}
 
@@ -275,7 +278,14 @@ CREATE TABLE crdb_internal.tables (
 				// effectively deleted.
 				dbName = fmt.Sprintf("[%d]", table.GetParentID())
 			}
-			if err := addDesc(table, tree.NewDString(dbName), "public"); err != nil {
+			scName := tree.PublicSchema
+			if table.UnexposedParentSchemaID != sqlbase.InvalidID && !table.Temporary {
+				if scName = scNames[table.UnexposedParentSchemaID]; scName == "" {
+					// The schema was dropped.
+					scName = fmt.Sprintf("[%d]", table.UnexposedParentSchemaID)
+				}
+			}
+			if err := addDesc(table, tree.NewDString(dbName), scName); err != nil {
 				return err
 			}
 		}
@@ -1116,6 +1126,7 @@ CREATE TABLE crdb_internal.create_statements (
 
 		// Prepare the row populate function.
 		typeView := tree.NewDString("view")
+		typeMaterializedView := tree.NewDString("materialized view")
 		typeTable := tree.NewDString("table")
 		typeSequence := tree.NewDString("sequence")
 
@@ -1134,6 +1145,9 @@ CREATE TABLE crdb_internal.create_statements (
 				var err error
 				if table.IsView() {
 					descType = typeView
+					if table.MaterializedView() {
+						descType = typeMaterializedView
+					}
 					stmt, err = ShowCreateView(ctx, (*tree.Name)(&table.Name), table)
 				} else if table.IsSequence() {
 					descType = typeSequence
@@ -1173,6 +1187,11 @@ CREATE TABLE crdb_internal.create_statements (
 							}
 						}
 					}
+					for _, trig := range showCreateTriggers(tn, table) {
+						if err := alterStmts.Append(tree.NewDString(trig)); err != nil {
+							return err
+						}
+					}
 					stmt, err = ShowCreateTable(ctx, tn, contextName, table, lCtx, false /* ignoreFKs */)
 				}
 				if err != nil {
@@ -0,0 +1,303 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type createDomainNode struct {
+	n      *tree.CreateDomain
+	tn     *tree.TableName
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateDomain creates a domain, a user-defined type whose values are the
+// values of a base type that satisfy the constraints of the domain.
+// Privileges: CREATE on database.
+func (p *planner) CreateDomain(ctx context.Context, n *tree.CreateDomain) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionDomains) {
+		return nil, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`CREATE DOMAIN requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionDomains),
+		)
+	}
+
+	tn := n.TypeName.ToTableName()
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &tn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createDomainNode{
+		n:      n,
+		tn:     &tn,
+		dbDesc: dbDesc,
+	}, nil
+}
+
+func (n *createDomainNode) startExec(params runParams) error {
+	p := params.p
+	typeName := n.tn.Table()
+
+	// Names of builtin types cannot be shadowed, since they are resolved before
+	// user-defined types.
+	if _, ok, _ := types.TypeForNonKeywordTypeName(typeName); ok {
+		return sqlbase.NewTypeAlreadyExistsError(typeName)
+	}
+	key := sqlbase.NewTableKey(n.dbDesc.ID, typeName)
+	if id, err := getDescriptorID(params.ctx, p.txn, key); err != nil {
+		return err
+	} else if id != sqlbase.InvalidID {
+		if _, err := sqlbase.GetTypeDescFromID(params.ctx, p.txn, id); err == nil {
+			return sqlbase.NewTypeAlreadyExistsError(typeName)
+		}
+		return sqlbase.NewRelationAlreadyExistsError(typeName)
+	}
+
+	baseType, err := tree.ResolveType(n.n.BaseType, p)
+	if err != nil {
+		return err
+	}
+	// Domains over enums and other domains are not supported: the base type of
+	// a domain is stored in its descriptor and is not updated when the
+	// user-defined base type changes, and dropping the base type does not
+	// check for domains that use it.
+	if baseType.UserDefined() {
+		return pgerror.Unimplementedf("domain over user-defined type",
+			"domains over user-defined types are not supported: %s is a user-defined type",
+			baseType.SQLString()).SetHintf(
+			"Create the domain over a built-in type instead, e.g. STRING CHECK (VALUE IN (...)).")
+	}
+	if err := sqlbase.ValidateColumnDefType(baseType); err != nil {
+		return err
+	}
+
+	domain := &sqlbase.TypeDescriptor_Domain{
+		BaseType: *baseType,
+		NotNull:  n.n.NotNull,
+	}
+	if n.n.DefaultExpr != nil {
+		typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+			n.n.DefaultExpr, baseType, "DEFAULT", &p.semaCtx, true, /* allowImpure */
+		)
+		if err != nil {
+			return err
+		}
+		s := tree.Serialize(typedExpr)
+		domain.DefaultExpr = &s
+	}
+
+	// The CHECK expressions are validated with VALUE replaced by a NULL of the
+	// base type. Unnamed constraints are named after the domain.
+	names := make(map[tree.Name]struct{}, len(n.n.Checks))
+	for i := range n.n.Checks {
+		if name := n.n.Checks[i].Name; name != "" {
+			if _, ok := names[name]; ok {
+				return pgerror.Newf(pgerror.CodeDuplicateObjectError,
+					"duplicate constraint name: %q", name)
+			}
+			names[name] = struct{}{}
+		}
+	}
+	for i := range n.n.Checks {
+		check := &n.n.Checks[i]
+		expr, err := replaceDomainValue(check.Expr, &tree.CastExpr{Expr: tree.DNull, Type: baseType})
+		if err != nil {
+			return err
+		}
+		typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+			expr, types.Any, "CHECK", &p.semaCtx, false, /* allowImpure */
+		)
+		if err != nil {
+			return err
+		}
+		if typ := typedExpr.ResolvedType(); typ.Family() != types.BoolFamily &&
+			typ.Family() != types.UnknownFamily {
+			return pgerror.Newf(pgerror.CodeDatatypeMismatchError,
+				"argument of CHECK must be type bool, not type %s", typ)
+		}
+		name := check.Name
+		if name == "" {
+			name = tree.Name(typeName + "_check")
+			for j := 1; ; j++ {
+				if _, ok := names[name]; !ok {
+					break
+				}
+				name = tree.Name(fmt.Sprintf("%s_check%d", typeName, j))
+			}
+			names[name] = struct{}{}
+		}
+		domain.Checks = append(domain.Checks, sqlbase.TypeDescriptor_Domain_CheckConstraint{
+			Name: string(name),
+			Expr: tree.Serialize(check.Expr),
+		})
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	typeDesc := sqlbase.TypeDescriptor{
+		Name:       typeName,
+		ID:         id,
+		ParentID:   n.dbDesc.ID,
+		Version:    1,
+		Domain:     domain,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	if err := typeDesc.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.createDescriptorWithID(
+		params.ctx, key.Key(), id, &typeDesc, params.EvalContext().Settings,
+	); err != nil {
+		return err
+	}
+
+	// Log Create Type event. This is an auditable log event and is recorded in
+	// the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		p.txn,
+		EventLogCreateType,
+		int32(typeDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.tn.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createDomainNode) Next(runParams) (bool, error) { return false, nil }
+func (*createDomainNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createDomainNode) Close(context.Context)        {}
+
+// replaceDomainValue returns a copy of the CHECK expression of a domain in
+// which the references to VALUE are replaced by the given expression.
+func replaceDomainValue(expr tree.Expr, value tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(e tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if name, ok := e.(*tree.UnresolvedName); ok &&
+			!name.Star && name.NumParts == 1 && name.Parts[0] == "value" {
+			return false, value, nil
+		}
+		return true, e, nil
+	})
+}
+
+// resolveColumnType resolves the type of the given column definition. When
+// the column is declared with a domain, the column inherits the NOT NULL
+// constraint and the default value of the domain, and the CHECK constraints
+// of the domain, applied to the column, are returned so that the caller can
+// add them to the table.
+func (p *planner) resolveColumnType(
+	ctx context.Context, d *tree.ColumnTableDef,
+) ([]*tree.CheckConstraintTableDef, error) {
+	typ, err := tree.ResolveType(d.Type, p)
+	if err != nil {
+		return nil, err
+	}
+	d.Type = typ
+	if typ.DomainID() == 0 {
+		return nil, nil
+	}
+	typeDesc, err := sqlbase.GetTypeDescFromID(ctx, p.txn, sqlbase.ID(typ.DomainID()))
+	if err != nil {
+		return nil, err
+	}
+	if typeDesc.Domain == nil {
+		return nil, pgerror.AssertionFailedf("type %q is not a domain", typeDesc.Name)
+	}
+	if typeDesc.Domain.NotNull {
+		d.Nullable.Nullability = tree.NotNull
+	}
+	if !d.HasDefaultExpr() && typeDesc.Domain.DefaultExpr != nil {
+		expr, err := parser.ParseExpr(*typeDesc.Domain.DefaultExpr)
+		if err != nil {
+			return nil, err
+		}
+		d.DefaultExpr.Expr = expr
+	}
+	checks := make([]*tree.CheckConstraintTableDef, len(typeDesc.Domain.Checks))
+	for i := range typeDesc.Domain.Checks {
+		expr, err := parser.ParseExpr(typeDesc.Domain.Checks[i].Expr)
+		if err != nil {
+			return nil, err
+		}
+		if expr, err = replaceDomainValue(expr, tree.NewUnresolvedName(string(d.Name))); err != nil {
+			return nil, err
+		}
+		checks[i] = &tree.CheckConstraintTableDef{Expr: expr}
+	}
+	return checks, nil
+}
+
+// CheckDomainConstraints is part of the tree.EvalPlanner interface.
+func (p *planner) CheckDomainConstraints(typ *types.T, d tree.Datum) error {
+	ctx := p.EvalContext().Ctx()
+	typeDesc, err := sqlbase.GetTypeDescFromID(ctx, p.txn, sqlbase.ID(typ.DomainID()))
+	if err != nil {
+		return err
+	}
+	if typeDesc.Domain == nil {
+		return pgerror.AssertionFailedf("type %q is not a domain", typeDesc.Name)
+	}
+	if d == tree.DNull {
+		if typeDesc.Domain.NotNull {
+			return pgerror.Newf(pgerror.CodeNotNullViolationError,
+				"domain %s does not allow null values", typeDesc.Name)
+		}
+		return nil
+	}
+	for i := range typeDesc.Domain.Checks {
+		check := &typeDesc.Domain.Checks[i]
+		expr, err := parser.ParseExpr(check.Expr)
+		if err != nil {
+			return err
+		}
+		if expr, err = replaceDomainValue(expr, d); err != nil {
+			return err
+		}
+		typedExpr, err := tree.TypeCheckAndRequire(expr, &p.semaCtx, types.Bool, "CHECK")
+		if err != nil {
+			return err
+		}
+		res, err := typedExpr.Eval(p.EvalContext())
+		if err != nil {
+			return err
+		}
+		if res == tree.DBoolFalse {
+			return pgerror.Newf(pgerror.CodeCheckViolationError,
+				"value for domain %s violates check constraint %q", typeDesc.Name, check.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,505 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	tn     *tree.TableName
+	dbDesc *sqlbase.DatabaseDescriptor
+	// desc is the descriptor of the function, whose ID is assigned by startExec.
+	desc *sqlbase.FunctionDescriptor
+}
+
+// CreateFunction creates a user-defined function, or replaces the overload of
+// an existing one with the same parameter types.
+// Privileges: CREATE on database.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionUserDefinedFunctions) {
+		return nil, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`CREATE FUNCTION requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionUserDefinedFunctions),
+		)
+	}
+
+	if !n.HasLanguage() {
+		return nil, pgerror.New(pgerror.CodeInvalidFunctionDefinitionError, "no language specified")
+	}
+	if lang := strings.ToLower(string(n.Language)); lang != "sql" {
+		return nil, pgerror.UnimplementedWithIssueDetailf(17511, "language",
+			"functions in language %q are not supported", lang)
+	}
+	if !n.HasBody() {
+		return nil, pgerror.New(pgerror.CodeInvalidFunctionDefinitionError,
+			"no function body specified")
+	}
+
+	tn := n.FuncName.ToTableName()
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &tn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	// Names of builtin functions cannot be shadowed, since they are resolved
+	// before user-defined functions.
+	name := tn.Table()
+	if _, ok := tree.FunDefs[name]; ok {
+		return nil, pgerror.Newf(pgerror.CodeDuplicateFunctionError,
+			"function %q already exists as a builtin function", name)
+	}
+
+	returnType, err := tree.ResolveType(n.ReturnType, p)
+	if err != nil {
+		return nil, err
+	}
+	desc := &sqlbase.FunctionDescriptor{
+		Name:       name,
+		ParentID:   dbDesc.ID,
+		ReturnType: *returnType,
+		Body:       n.Body,
+		Volatility: functionVolatility(n.Volatility),
+		Strict:     n.Strict,
+		// Inherit permissions from the database descriptor.
+		Privileges: dbDesc.GetPrivileges(),
+	}
+	seen := make(map[tree.Name]struct{}, len(n.Params))
+	for _, param := range n.Params {
+		if param.Name != "" {
+			if _, ok := seen[param.Name]; ok {
+				return nil, pgerror.Newf(pgerror.CodeInvalidFunctionDefinitionError,
+					"parameter name %q used more than once", param.Name)
+			}
+			seen[param.Name] = struct{}{}
+		}
+		typ, err := tree.ResolveType(param.Type, p)
+		if err != nil {
+			return nil, err
+		}
+		desc.Parameters = append(desc.Parameters, sqlbase.FunctionDescriptor_Parameter{
+			Name: string(param.Name),
+			Type: *typ,
+		})
+	}
+
+	return &createFunctionNode{
+		n:      n,
+		tn:     &tn,
+		dbDesc: dbDesc,
+		desc:   desc,
+	}, nil
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	body, err := parseFunctionBody(n.desc)
+	if err != nil {
+		return err
+	}
+	if err := checkFunctionNotRecursive(n.desc.Name, body); err != nil {
+		return err
+	}
+	if err := p.validateFunctionBody(ctx, n.desc, body); err != nil {
+		return err
+	}
+
+	existing, err := findFunctionOverload(
+		ctx, p.txn, n.dbDesc, n.desc.Name, n.desc.ParameterTypes(),
+	)
+	if err != nil {
+		return err
+	}
+
+	b := &client.Batch{}
+	if existing != nil {
+		if !n.n.Replace {
+			return pgerror.Newf(pgerror.CodeDuplicateFunctionError,
+				"function %q already exists with same argument types", n.desc.Name)
+		}
+		if !existing.ReturnType.Equivalent(&n.desc.ReturnType) {
+			return pgerror.Newf(pgerror.CodeInvalidFunctionDefinitionError,
+				"cannot change return type of existing function").SetHintf(
+				"Use DROP FUNCTION %s first.", existing.Signature())
+		}
+		n.desc.ID = existing.ID
+		n.desc.Privileges = existing.Privileges
+		if err := n.desc.Validate(); err != nil {
+			return err
+		}
+		descKey := sqlbase.MakeDescMetadataKey(n.desc.ID)
+		descVal := sqlbase.WrapDescriptor(n.desc)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Put %s -> %s", descKey, descVal)
+		}
+		b.Put(descKey, descVal)
+	} else {
+		id, err := GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+		if err != nil {
+			return err
+		}
+		n.desc.ID = id
+		if err := n.desc.Validate(); err != nil {
+			return err
+		}
+		// Overloads share a name, so functions have no entry in the namespace
+		// table. Instead, the database descriptor lists its functions.
+		n.dbDesc.AddFunction(n.desc.Name, id)
+		descKey := sqlbase.MakeDescMetadataKey(id)
+		descVal := sqlbase.WrapDescriptor(n.desc)
+		dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+		dbDescVal := sqlbase.WrapDescriptor(n.dbDesc)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "CPut %s -> %s", descKey, descVal)
+			log.VEventf(ctx, 2, "Put %s -> %s", dbDescKey, dbDescVal)
+		}
+		b.CPut(descKey, descVal, nil)
+		b.Put(dbDescKey, dbDescVal)
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	// Log Create Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogCreateFunction,
+		int32(n.desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{n.tn.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
+
+func functionVolatility(v tree.FunctionVolatility) sqlbase.FunctionDescriptor_Volatility {
+	switch v {
+	case tree.FunctionStable:
+		return sqlbase.FunctionDescriptor_STABLE
+	case tree.FunctionImmutable:
+		return sqlbase.FunctionDescriptor_IMMUTABLE
+	default:
+		return sqlbase.FunctionDescriptor_VOLATILE
+	}
+}
+
+// findFunctionOverload returns the descriptor of the overload of the
+// user-defined function with the given name and parameter types in the given
+// database, or nil if there is none.
+func findFunctionOverload(
+	ctx context.Context,
+	txn *client.Txn,
+	dbDesc *sqlbase.DatabaseDescriptor,
+	name string,
+	paramTypes []*types.T,
+) (*sqlbase.FunctionDescriptor, error) {
+	for _, id := range dbDesc.FunctionIDs(name) {
+		desc, err := sqlbase.GetFunctionDescFromID(ctx, txn, id)
+		if err != nil {
+			return nil, err
+		}
+		if sameParameterTypes(desc.ParameterTypes(), paramTypes) {
+			return desc, nil
+		}
+	}
+	return nil, nil
+}
+
+func sameParameterTypes(a, b []*types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equivalent(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFunctionBody parses the body of the given function. The references to
+// the parameters of the function in the body, by name or by position, are
+// replaced with placeholders annotated with the types of the parameters, so
+// that the body can be evaluated with the arguments of a call as the values
+// of the placeholders. Parameter names take precedence over column names.
+func parseFunctionBody(desc *sqlbase.FunctionDescriptor) (*tree.Select, error) {
+	stmts, err := parser.Parse(desc.Body)
+	if err != nil {
+		return nil, err
+	}
+	var sel *tree.Select
+	if len(stmts) == 1 {
+		sel, _ = stmts[0].AST.(*tree.Select)
+	}
+	if sel == nil {
+		return nil, pgerror.UnimplementedWithIssueDetail(17511, "body",
+			"only a single SELECT statement is supported as the body of a function")
+	}
+
+	paramTypes := desc.ParameterTypes()
+	param := func(idx int) tree.Expr {
+		return &tree.AnnotateTypeExpr{
+			Expr:       &tree.Placeholder{Idx: tree.PlaceholderIdx(idx)},
+			Type:       paramTypes[idx],
+			SyntaxMode: tree.AnnotateShort,
+		}
+	}
+	return walkFunctionBody(sel, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch t := expr.(type) {
+		case *tree.Placeholder:
+			if int(t.Idx) >= len(paramTypes) {
+				return false, expr, pgerror.Newf(pgerror.CodeUndefinedParameterError,
+					"there is no parameter %s", t)
+			}
+			return false, param(int(t.Idx)), nil
+		case *tree.UnresolvedName:
+			if t.NumParts == 1 && !t.Star {
+				for i := range desc.Parameters {
+					if desc.Parameters[i].Name == t.Parts[0] {
+						return false, param(i), nil
+					}
+				}
+			}
+		}
+		return true, expr, nil
+	})
+}
+
+// walkFunctionBody runs fn on the expressions of the given function body,
+// including those of its subqueries, and returns the modified body.
+func walkFunctionBody(sel *tree.Select, fn tree.SimpleVisitFn) (*tree.Select, error) {
+	// Wrapping the body in a subquery lets the visitor walk the statement.
+	expr, err := tree.SimpleVisit(&tree.Subquery{Select: &tree.ParenSelect{Select: sel}}, fn)
+	if err != nil {
+		return nil, err
+	}
+	return expr.(*tree.Subquery).Select.(*tree.ParenSelect).Select, nil
+}
+
+// checkFunctionNotRecursive returns an error if the body of the function with
+// the given name calls the function itself.
+func checkFunctionNotRecursive(name string, body *tree.Select) error {
+	_, err := walkFunctionBody(body, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if f, ok := expr.(*tree.FuncExpr); ok {
+			if fn, ok := f.Func.FunctionReference.(*tree.UnresolvedName); ok && fn.Parts[0] == name {
+				return false, expr, pgerror.Newf(pgerror.CodeInvalidFunctionDefinitionError,
+					"function %q cannot call itself", name)
+			}
+		}
+		return true, expr, nil
+	})
+	return err
+}
+
+// functionQuery returns a query which evaluates the given body of a function
+// with the arguments of the function as placeholder values. The first column
+// of its result is the value of the first row of the body, cast to castType
+// unless it is nil. The query also selects the placeholders of all the
+// parameters, so that it accepts all the arguments even when the body does not
+// use some of them.
+func functionQuery(
+	desc *sqlbase.FunctionDescriptor, body *tree.Select, castType *types.T,
+) string {
+	limited := *body
+	if limited.Limit == nil {
+		limited.Limit = &tree.Limit{Count: tree.NewDInt(1)}
+	}
+	subquery := tree.AsStringWithFlags(&limited, tree.FmtParsable)
+
+	var buf bytes.Buffer
+	if castType != nil {
+		fmt.Fprintf(&buf, "SELECT CAST((%s) AS %s)", subquery, castType.SQLString())
+	} else {
+		fmt.Fprintf(&buf, "SELECT (%s)", subquery)
+	}
+	for i := range desc.Parameters {
+		fmt.Fprintf(&buf, ", $%d:::%s", i+1, desc.Parameters[i].Type.SQLString())
+	}
+	return buf.String()
+}
+
+// validateFunctionBody type checks the body of the given function by running
+// it with NULL arguments, and verifies that it returns a single value of the
+// return type of the function.
+func (p *planner) validateFunctionBody(
+	ctx context.Context, desc *sqlbase.FunctionDescriptor, body *tree.Select,
+) error {
+	ie, ok := p.ExtendedEvalContext().InternalExecutor.(*SessionBoundInternalExecutor)
+	if !ok {
+		return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+			"cannot create functions in this context")
+	}
+	args := make([]interface{}, len(desc.Parameters))
+	for i := range args {
+		args[i] = tree.DNull
+	}
+	query := functionQuery(desc, body, nil /* castType */)
+	_, cols, err := ie.QueryWithCols(ctx, "validate-function", p.txn, query, args...)
+	if err != nil {
+		return err
+	}
+	if typ := cols[0].Typ; typ.Family() != types.UnknownFamily && !typ.Equivalent(&desc.ReturnType) {
+		return pgerror.Newf(pgerror.CodeInvalidFunctionDefinitionError,
+			"return type mismatch in function declared to return %s",
+			desc.ReturnType.SQLString()).SetDetailf("Actual return type is %s.", typ.SQLString())
+	}
+	return nil
+}
+
+// makeFunctionDefinition returns the definition of the user-defined function
+// with the given name and overloads.
+func makeFunctionDefinition(
+	name string, descs []*sqlbase.FunctionDescriptor,
+) (*tree.FunctionDefinition, error) {
+	props := tree.FunctionProperties{
+		// Strictness is a property of each overload; see makeFunctionOverload.
+		NullableArgs: true,
+		// The functions are evaluated with the internal executor of the session,
+		// which is not available on remote nodes.
+		DistsqlBlacklist: true,
+		UserDefined:      true,
+	}
+	overloads := make([]tree.Overload, len(descs))
+	for i, desc := range descs {
+		if desc.Volatility == sqlbase.FunctionDescriptor_VOLATILE {
+			props.Impure = true
+		}
+		var err error
+		if overloads[i], err = makeFunctionOverload(desc); err != nil {
+			return nil, err
+		}
+	}
+	return tree.NewFunctionDefinition(name, &props, overloads), nil
+}
+
+// makeFunctionOverload returns the overload for the given function
+// descriptor. Calls are evaluated by running the body of the function with the
+// internal executor, in the transaction of the caller.
+func makeFunctionOverload(desc *sqlbase.FunctionDescriptor) (tree.Overload, error) {
+	body, err := parseFunctionBody(desc)
+	if err != nil {
+		return tree.Overload{}, err
+	}
+
+	argTypes := make(tree.ArgTypes, len(desc.Parameters))
+	for i := range desc.Parameters {
+		argTypes[i].Name = desc.Parameters[i].Name
+		if argTypes[i].Name == "" {
+			argTypes[i].Name = fmt.Sprintf("arg%d", i+1)
+		}
+		argTypes[i].Typ = &desc.Parameters[i].Type
+	}
+
+	name := desc.Name
+	strict := desc.Strict
+	query := functionQuery(desc, body, &desc.ReturnType)
+	return tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(&desc.ReturnType),
+		Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			qargs := make([]interface{}, len(args))
+			for i, arg := range args {
+				if arg == tree.DNull && strict {
+					return tree.DNull, nil
+				}
+				qargs[i] = arg
+			}
+			if ctx.InternalExecutor == nil {
+				return nil, pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+					"cannot evaluate function %s in this context", name)
+			}
+			row, err := ctx.InternalExecutor.QueryRow(ctx.Ctx(), "udf-"+name, ctx.Txn, query, qargs...)
+			if err != nil {
+				return nil, err
+			}
+			if row == nil {
+				return tree.DNull, nil
+			}
+			return row[0], nil
+		},
+		InlineBody: inlinableFunctionBody(desc, body),
+	}, nil
+}
+
+// inlinableFunctionBody returns the expression that the optimizer can
+// substitute for calls to the given function, or nil if the function cannot be
+// inlined. Only IMMUTABLE functions which are not STRICT are inlined, when
+// their body selects a single expression which uses each parameter at most
+// once, does not refer to any column and only calls immutable builtin
+// functions.
+func inlinableFunctionBody(desc *sqlbase.FunctionDescriptor, body *tree.Select) tree.Expr {
+	if desc.Volatility != sqlbase.FunctionDescriptor_IMMUTABLE || desc.Strict {
+		return nil
+	}
+	if body.With != nil || body.OrderBy != nil || body.Limit != nil || body.Locking != nil {
+		return nil
+	}
+	clause, ok := body.Select.(*tree.SelectClause)
+	if !ok || clause.Distinct || clause.DistinctOn != nil || len(clause.Exprs) != 1 ||
+		(clause.From != nil && (len(clause.From.Tables) > 0 || clause.From.AsOf.Expr != nil)) ||
+		clause.Where != nil || clause.GroupBy != nil || clause.Having != nil ||
+		clause.Window != nil || clause.TableSelect {
+		return nil
+	}
+
+	expr := clause.Exprs[0].Expr
+	used := make([]bool, len(desc.Parameters))
+	inlinable := true
+	_, _ = tree.SimpleVisit(expr, func(e tree.Expr) (bool, tree.Expr, error) {
+		switch t := e.(type) {
+		case *tree.Placeholder:
+			inlinable = !used[t.Idx]
+			used[t.Idx] = true
+		case *tree.UnresolvedName, *tree.Subquery:
+			inlinable = false
+		case *tree.FuncExpr:
+			def, err := t.Func.Resolve(sessiondata.SearchPath{})
+			inlinable = err == nil && def.Class == tree.NormalClass && !def.Impure &&
+				!def.UserDefined && t.WindowDef == nil && t.Filter == nil
+		}
+		return inlinable, e, nil
+	})
+	if !inlinable {
+		return nil
+	}
+	return expr
+}
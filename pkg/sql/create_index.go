@@ -15,10 +15,12 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 type createIndexNode struct {
@@ -78,6 +80,74 @@ func MakeIndexDescriptor(n *tree.CreateIndex) (*sqlbase.IndexDescriptor, error)
 	return &indexDesc, nil
 }
 
+// checkArrayInvertedIndexSupported returns an error if an inverted index is on
+// an ARRAY column but the cluster version does not support it yet. Nodes
+// running older versions cannot encode the index entries of arrays.
+func checkArrayInvertedIndexSupported(
+	st *cluster.Settings, desc *sqlbase.MutableTableDescriptor, idx *sqlbase.IndexDescriptor,
+) error {
+	if idx.Type != sqlbase.IndexDescriptor_INVERTED ||
+		st.Version.IsActive(cluster.VersionArrayInvertedIndexes) {
+		return nil
+	}
+	for _, name := range idx.ColumnNames {
+		col, _, err := desc.FindColumnByName(tree.Name(name))
+		if err == nil && col.Type.Family() == types.ArrayFamily {
+			return pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+				`inverted indexes on arrays require all nodes to be upgraded to %s`,
+				cluster.VersionByKey(cluster.VersionArrayInvertedIndexes),
+			)
+		}
+	}
+	return nil
+}
+
+// MakePartialIndexPredicate validates the predicate of a partial index and
+// returns its serialized representation. The predicate must be a boolean
+// expression over the columns of the table that does not contain impure
+// functions, so that whether a row belongs in the index only depends on the
+// contents of the row.
+func MakePartialIndexPredicate(
+	ctx context.Context,
+	st *cluster.Settings,
+	desc *sqlbase.MutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	predicate tree.Expr,
+	semaCtx *tree.SemaContext,
+	tableName tree.TableName,
+) (string, error) {
+	// Nodes running older versions ignore the predicate and maintain the
+	// index as a full index.
+	if !st.Version.IsActive(cluster.VersionPartialIndexes) {
+		return "", pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`partial indexes require all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionPartialIndexes),
+		)
+	}
+	if idx.Type == sqlbase.IndexDescriptor_INVERTED {
+		return "", pgerror.New(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes can't be partial")
+	}
+
+	expr, _, err := replaceVars(desc, predicate)
+	if err != nil {
+		return "", err
+	}
+	if _, err := sqlbase.SanitizeVarFreeExpr(
+		expr, types.Bool, "index predicate", semaCtx, false, /* allowImpure */
+	); err != nil {
+		return "", err
+	}
+
+	sourceInfo := sqlbase.NewSourceInfoForSingleTable(
+		tableName, sqlbase.ResultColumnsFromColDescs(desc.TableDesc().AllNonDropColumns()),
+	)
+	expr, err = dequalifyColumnRefs(ctx, sqlbase.MultiSourceInfo{sourceInfo}, predicate)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(expr), nil
+}
+
 func (n *createIndexNode) startExec(params runParams) error {
 	_, dropped, err := n.tableDesc.FindIndexByName(string(n.n.Name))
 	if err == nil {
@@ -94,6 +164,20 @@ func (n *createIndexNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	if err := checkArrayInvertedIndexSupported(
+		params.p.ExecCfg().Settings, n.tableDesc, indexDesc,
+	); err != nil {
+		return err
+	}
+
+	if n.n.Predicate != nil {
+		if indexDesc.Predicate, err = MakePartialIndexPredicate(
+			params.ctx, params.p.ExecCfg().Settings, n.tableDesc, indexDesc, n.n.Predicate,
+			&params.p.semaCtx, n.n.Table,
+		); err != nil {
+			return err
+		}
+	}
 
 	if n.n.PartitionBy != nil {
 		partitioning, err := CreatePartitioning(params.ctx, params.p.ExecCfg().Settings,
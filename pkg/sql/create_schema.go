@@ -0,0 +1,141 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createSchemaNode struct {
+	n      *tree.CreateSchema
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateSchema creates a schema in the current database.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on database.
+func (p *planner) CreateSchema(ctx context.Context, n *tree.CreateSchema) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionUserDefinedSchemas) {
+		return nil, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`CREATE SCHEMA requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionUserDefinedSchemas),
+		)
+	}
+
+	if err := checkSchemaName(string(n.Schema)); err != nil {
+		return nil, err
+	}
+
+	dbName := p.CurrentDatabase()
+	if dbName == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createSchemaNode{n: n, dbDesc: dbDesc}, nil
+}
+
+// checkSchemaName verifies that a user-defined schema can be created with the
+// given name.
+func checkSchemaName(scName string) error {
+	if scName == tree.PublicSchema || isVirtualSchemaName(scName) {
+		return sqlbase.NewSchemaAlreadyExistsError(scName)
+	}
+	if strings.HasPrefix(scName, "pg_") {
+		return pgerror.Newf(pgerror.CodeReservedNameError,
+			"unacceptable schema name %q", scName).
+			SetDetailf(`The prefix "pg_" is reserved for system schemas.`)
+	}
+	return nil
+}
+
+func (n *createSchemaNode) startExec(params runParams) error {
+	p := params.p
+	scName := string(n.n.Schema)
+
+	// Schemas share the namespace of the database with the tables and types
+	// in its public schema.
+	key := sqlbase.NewSchemaKey(n.dbDesc.ID, scName)
+	if exists, err := descExists(params.ctx, p.txn, key.Key()); err == nil && exists {
+		scDesc, err := getSchemaDescByName(params.ctx, p.txn, n.dbDesc.ID, scName)
+		if err != nil {
+			return err
+		}
+		if scDesc == nil {
+			return pgerror.Newf(pgerror.CodeDuplicateObjectError,
+				"cannot create schema %q: a relation or type with the same name already exists",
+				scName)
+		}
+		if n.n.IfNotExists {
+			return nil
+		}
+		return sqlbase.NewSchemaAlreadyExistsError(scName)
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	scDesc := sqlbase.SchemaDescriptor{
+		Name:       scName,
+		ID:         id,
+		ParentID:   n.dbDesc.ID,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	if err := scDesc.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.createDescriptorWithID(
+		params.ctx, key.Key(), id, &scDesc, params.EvalContext().Settings,
+	); err != nil {
+		return err
+	}
+
+	// Log Create Schema event. This is an auditable log event and is recorded
+	// in the same transaction as the schema descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		p.txn,
+		EventLogCreateSchema,
+		int32(scDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			SchemaName string
+			Statement  string
+			User       string
+		}{scName, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*createSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createSchemaNode) Close(context.Context)        {}
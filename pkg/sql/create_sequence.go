@@ -18,7 +18,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -31,12 +30,12 @@ type createSequenceNode struct {
 }
 
 func (p *planner) CreateSequence(ctx context.Context, n *tree.CreateSequence) (planNode, error) {
-	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
+	dbDesc, err := p.resolveUncachedDatabaseForRelation(ctx, &n.Name, false /* allowTemporary */)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if err := p.checkSchemaCreatePrivilege(ctx, dbDesc, n.Name.Schema()); err != nil {
 		return nil, err
 	}
 
@@ -47,7 +46,11 @@ func (p *planner) CreateSequence(ctx context.Context, n *tree.CreateSequence) (p
 }
 
 func (n *createSequenceNode) startExec(params runParams) error {
-	tKey := sqlbase.NewTableKey(n.dbDesc.ID, n.n.Name.Table())
+	parentID, _, err := params.p.schemaForCreate(params.ctx, n.dbDesc, n.n.Name.Schema())
+	if err != nil {
+		return err
+	}
+	tKey := sqlbase.NewTableKey(parentID, n.n.Name.Table())
 	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
 		if n.n.IfNotExists {
 			// If the sequence exists but the user specified IF NOT EXISTS, return without doing anything.
@@ -75,19 +78,26 @@ func doCreateSequence(
 		return err
 	}
 
-	// Inherit permissions from the database descriptor.
-	privs := dbDesc.GetPrivileges()
+	// Inherit permissions from the schema, or from the database descriptor
+	// for the public schema.
+	parentID, privs, err := params.p.schemaForCreate(params.ctx, dbDesc, name.Schema())
+	if err != nil {
+		return err
+	}
 
 	desc, err := MakeSequenceTableDesc(name.Table(), opts,
 		dbDesc.ID, id, params.p.txn.CommitTimestamp(), privs, params.EvalContext().Settings)
 	if err != nil {
 		return err
 	}
+	if parentID != dbDesc.ID {
+		desc.UnexposedParentSchemaID = parentID
+	}
 
 	// makeSequenceTableDesc already validates the table. No call to
 	// desc.ValidateTable() needed here.
 
-	key := sqlbase.NewTableKey(dbDesc.ID, name.Table()).Key()
+	key := sqlbase.NewTableKey(parentID, name.Table()).Key()
 	if err = params.p.createDescriptorWithID(params.ctx, key, id, &desc, params.EvalContext().Settings); err != nil {
 		return err
 	}
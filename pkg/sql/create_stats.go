@@ -154,7 +154,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 	var err error
 	switch t := n.Table.(type) {
 	case *tree.UnresolvedObjectName:
-		tableDesc, err = n.p.ResolveExistingObjectEx(ctx, t, true /*required*/, ResolveRequireTableDesc)
+		tableDesc, err = n.p.ResolveExistingObjectEx(ctx, t, true /*required*/, ResolveRequireTableOrViewDesc)
 		if err != nil {
 			return nil, err
 		}
@@ -180,7 +180,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		)
 	}
 
-	if tableDesc.IsView() {
+	if tableDesc.IsView() && !tableDesc.MaterializedView() {
 		return nil, pgerror.New(
 			pgerror.CodeWrongObjectTypeError, "cannot create statistics on views",
 		)
@@ -208,6 +208,10 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 				return nil, pgerror.UnimplementedWithIssuef(35844,
 					"CREATE STATISTICS is not supported for JSON columns")
 			}
+			if isSpatialType(&columns[i].Type) {
+				return nil, pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+					"CREATE STATISTICS is not supported for %s columns", columns[i].Type.Name())
+			}
 			columnIDs[i] = columns[i].ID
 		}
 		createStatsColLists = []jobspb.CreateStatsDetails_ColList{{IDs: columnIDs}}
@@ -299,11 +303,13 @@ func createStatsDefaultColumns(
 		}
 	}
 
-	// Add all remaining non-json columns in the table, up to maxNonIndexCols.
+	// Add all remaining non-json, non-spatial columns in the table, up to
+	// maxNonIndexCols.
 	nonIdxCols := 0
 	for i := 0; i < len(desc.Columns) && nonIdxCols < maxNonIndexCols; i++ {
 		col := &desc.Columns[i]
-		if col.Type.Family() != types.JsonFamily && !requestedCols.Contains(int(col.ID)) {
+		if col.Type.Family() != types.JsonFamily && !isSpatialType(&col.Type) &&
+			!requestedCols.Contains(int(col.ID)) {
 			columns = append(
 				columns, jobspb.CreateStatsDetails_ColList{IDs: []sqlbase.ColumnID{col.ID}},
 			)
@@ -314,6 +320,12 @@ func createStatsDefaultColumns(
 	return columns, nil
 }
 
+// isSpatialType returns whether typ is one of the spatial types, which do not
+// have the key encoding needed to build histograms.
+func isSpatialType(typ *types.T) bool {
+	return typ.Family() == types.GeometryFamily || typ.Family() == types.GeographyFamily
+}
+
 // makePlanForExplainDistSQL is part of the distSQLExplainable interface.
 func (n *createStatsNode) makePlanForExplainDistSQL(
 	planCtx *PlanningCtx, distSQLPlanner *DistSQLPlanner,
@@ -45,15 +45,21 @@ type createTableNode struct {
 }
 
 // CreateTable creates a table.
-// Privileges: CREATE on database.
+// Privileges: CREATE on schema (on database for the public and temporary
+// schemas).
 //   Notes: postgres/mysql require CREATE on database.
 func (p *planner) CreateTable(ctx context.Context, n *tree.CreateTable) (planNode, error) {
-	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Table)
+	dbDesc, err := p.resolveUncachedDatabaseForRelation(ctx, &n.Table, true /* allowTemporary */)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if isTemporarySchemaRef(n.Table.Schema()) {
+		err = p.CheckPrivilege(ctx, dbDesc, privilege.CREATE)
+	} else {
+		err = p.checkSchemaCreatePrivilege(ctx, dbDesc, n.Table.Schema())
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -103,7 +109,43 @@ type createTableRun struct {
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	tKey := sqlbase.NewTableKey(n.dbDesc.ID, n.n.Table.Table())
+	// A table created in the session's temporary schema is temporary, even
+	// without the TEMPORARY keyword.
+	if isTemporarySchemaRef(n.n.Table.Schema()) {
+		n.n.Temporary = true
+	} else if n.n.Temporary && n.n.Table.ExplicitSchema {
+		return pgerror.Newf(pgerror.CodeInvalidTableDefinitionError,
+			"cannot create temporary relation in non-temporary schema")
+	}
+
+	// Tables in user-defined schemas and temporary tables are keyed in the
+	// namespace by the ID of their schema, instead of the database ID.
+	var parentID sqlbase.ID
+	var privs *sqlbase.PrivilegeDescriptor
+	if n.n.Temporary {
+		if n.n.Interleave != nil {
+			return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+				"interleaved temporary tables are not supported")
+		}
+		if n.n.PartitionBy != nil {
+			return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+				"partitioned temporary tables are not supported")
+		}
+		schemaID, err := params.p.getOrCreateTemporarySchemaID(params.ctx, n.dbDesc.ID)
+		if err != nil {
+			return err
+		}
+		parentID, privs = schemaID, n.dbDesc.GetPrivileges()
+		n.n.Table.SchemaName = tree.Name(params.SessionData().SearchPath.GetTemporarySchemaName())
+	} else {
+		var err error
+		parentID, privs, err = params.p.schemaForCreate(params.ctx, n.dbDesc, n.n.Table.Schema())
+		if err != nil {
+			return err
+		}
+	}
+
+	tKey := sqlbase.NewTableKey(parentID, n.n.Table.Table())
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		if n.n.IfNotExists {
@@ -121,7 +163,6 @@ func (n *createTableNode) startExec(params runParams) error {
 
 	// If a new system table is being created (which should only be doable by
 	// an internal user account), make sure it gets the correct privileges.
-	privs := n.dbDesc.GetPrivileges()
 	if n.dbDesc.ID == keys.SystemDatabaseID {
 		privs = sqlbase.NewDefaultPrivilegeDescriptor()
 	}
@@ -142,12 +183,24 @@ func (n *createTableNode) startExec(params runParams) error {
 			n.n, n.dbDesc.ID, id, creationTime, asCols,
 			privs, &params.p.semaCtx)
 	} else {
+		var defs tree.TableDefs
+		defs, err = params.p.resolveColumnTypes(params.ctx, n.n.Defs)
+		if err != nil {
+			return err
+		}
+		// The CHECK constraints of the domains used by the columns are added
+		// to a copy of the statement, which is otherwise left as written.
+		createTable := *n.n
+		createTable.Defs = defs
 		affected = make(map[sqlbase.ID]*sqlbase.MutableTableDescriptor)
-		desc, err = makeTableDesc(params, n.n, n.dbDesc.ID, id, creationTime, privs, affected)
+		desc, err = makeTableDesc(params, &createTable, n.dbDesc.ID, id, creationTime, privs, affected)
 	}
 	if err != nil {
 		return err
 	}
+	if parentID != n.dbDesc.ID {
+		desc.UnexposedParentSchemaID = parentID
+	}
 
 	if desc.Adding() {
 		// if this table and all its references are created in the same
@@ -180,6 +233,12 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if err := params.p.addTypeBackReferences(
+		params.ctx, typeIDsReferencedByTable(desc.TableDesc()), desc.ID,
+	); err != nil {
+		return err
+	}
+
 	for _, index := range desc.AllNonDropIndexes() {
 		if len(index.Interleave.Ancestors) > 0 {
 			if err := params.p.finalizeInterleave(params.ctx, &desc, index); err != nil {
@@ -222,6 +281,7 @@ func (n *createTableNode) startExec(params runParams) error {
 			nil,
 			desc.Columns,
 			row.SkipFKs,
+			params.EvalContext(),
 			&params.p.alloc)
 		if err != nil {
 			return err
@@ -252,20 +312,8 @@ func (n *createTableNode) startExec(params runParams) error {
 		// a rowID expression to be evaluated separately.
 		var defTypedExpr tree.TypedExpr
 		if n.run.synthRowID {
-			// Prepare the rowID expression.
-			defExprSQL := *desc.Columns[pkColIdx].DefaultExpr
-			defExpr, err := parser.ParseExpr(defExprSQL)
-			if err != nil {
-				return err
-			}
-			defTypedExpr, err = params.p.analyzeExpr(
-				params.ctx,
-				defExpr,
-				nil, /*sources*/
-				tree.IndexedVarHelper{},
-				types.Any,
-				false, /*requireType*/
-				"CREATE TABLE AS")
+			defTypedExpr, err = params.p.makeRowIDExpr(
+				params.ctx, &desc.Columns[pkColIdx], "CREATE TABLE AS")
 			if err != nil {
 				return err
 			}
@@ -311,6 +359,25 @@ func (n *createTableNode) startExec(params runParams) error {
 	return nil
 }
 
+// makeRowIDExpr prepares the default expression of the hidden rowid column
+// col, so that it can be evaluated separately for each row written.
+func (p *planner) makeRowIDExpr(
+	ctx context.Context, col *sqlbase.ColumnDescriptor, op string,
+) (tree.TypedExpr, error) {
+	defExpr, err := parser.ParseExpr(*col.DefaultExpr)
+	if err != nil {
+		return nil, err
+	}
+	return p.analyzeExpr(
+		ctx,
+		defExpr,
+		nil, /*sources*/
+		tree.IndexedVarHelper{},
+		types.Any,
+		false, /*requireType*/
+		op)
+}
+
 // enableAutoCommit is part of the autoCommitNode interface.
 func (n *createTableNode) enableAutoCommit() {
 	n.run.autoCommit = autoCommitEnabled
@@ -349,6 +416,11 @@ func matchesIndex(
 	if len(cols) > len(idx.ColumnIDs) || (exact && len(cols) != len(idx.ColumnIDs)) {
 		return false
 	}
+	// A partial index doesn't contain all the rows of the table, so it can't
+	// be used to look up or enforce foreign key references.
+	if idx.IsPartial() {
+		return false
+	}
 
 	for i := range cols {
 		if cols[i].ID != idx.ColumnIDs[i] {
@@ -372,6 +444,33 @@ func (p *planner) resolveFK(
 	return ResolveFK(ctx, p.txn, p, tbl, d, backrefs, ts)
 }
 
+// checkDeferrableSupported returns an error if a constraint is DEFERRABLE but
+// the cluster version does not support deferrable constraints yet. Nodes
+// running older versions ignore the deferrability and check the constraint
+// immediately.
+func checkDeferrableSupported(st *cluster.Settings, d tree.ConstraintDeferrability) error {
+	if d != tree.NotDeferrable && !st.Version.IsActive(cluster.VersionDeferrableConstraints) {
+		return pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`deferrable constraints require all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionDeferrableConstraints),
+		)
+	}
+	return nil
+}
+
+// checkVirtualColumnSupported returns an error if a column is a virtual
+// computed column but the cluster version does not support them yet. Nodes
+// running older versions expect the column to be stored.
+func checkVirtualColumnSupported(st *cluster.Settings, d *tree.ColumnTableDef) error {
+	if d.IsComputed() && d.Computed.Virtual && !st.Version.IsActive(cluster.VersionVirtualColumns) {
+		return pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`virtual computed columns require all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionVirtualColumns),
+		)
+	}
+	return nil
+}
+
 func qualifyFKColErrorWithDB(
 	ctx context.Context, txn *client.Txn, tbl *sqlbase.TableDescriptor, col string,
 ) string {
@@ -449,6 +548,17 @@ func ResolveFK(
 		// we edit the same copy.
 		target = tbl
 	} else {
+		// Temporary tables are dropped when their session ends, so constraints
+		// cannot span temporary and permanent tables.
+		if tbl.Temporary && !target.Temporary {
+			return pgerror.New(pgerror.CodeInvalidTableDefinitionError,
+				"constraints on temporary tables may reference only temporary tables")
+		}
+		if !tbl.Temporary && target.Temporary {
+			return pgerror.New(pgerror.CodeInvalidTableDefinitionError,
+				"constraints on permanent tables may reference only permanent tables")
+		}
+
 		// Since this FK is referencing another table, this table must be created in
 		// a non-public "ADD" state and made public only after all leases on the
 		// other table are updated to include the backref, if it does not already
@@ -567,12 +677,13 @@ func ResolveFK(
 		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:           sqlbase.CompositeKeyMatchMethodValue[d.Match],
+		Deferrability:   sqlbase.ConstraintDeferrabilityValue[d.Deferrable],
 	}
 
 	if ts != NewTable {
 		ref.Validity = sqlbase.ConstraintValidity_Validating
 	}
-	backref := sqlbase.ForeignKeyReference{Table: tbl.ID}
+	backref := sqlbase.ForeignKeyReference{Table: tbl.ID, Deferrability: ref.Deferrability}
 
 	var idx *sqlbase.IndexDescriptor
 	found := false
@@ -743,12 +854,21 @@ func addInterleave(
 			7854, "unsupported shorthand %s", interleave.DropBehavior)
 	}
 
+	if desc.Temporary {
+		return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+			"interleaved temporary tables are not supported")
+	}
+
 	parentTable, err := ResolveExistingObject(
 		ctx, vt, &interleave.Parent, true /*required*/, ResolveRequireTableDesc,
 	)
 	if err != nil {
 		return err
 	}
+	if parentTable.Temporary {
+		return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+			"cannot interleave a table in a temporary table")
+	}
 	parentIndex := parentTable.PrimaryIndex
 
 	// typeOfIndex is used to give more informative error messages.
@@ -924,6 +1044,7 @@ func makeTableDescIfAs(
 	semaCtx *tree.SemaContext,
 ) (desc sqlbase.MutableTableDescriptor, err error) {
 	desc = InitTableDescriptor(id, parentID, p.Table.Table(), creationTime, privileges)
+	desc.Temporary = p.Temporary
 	for i, colRes := range resultColumns {
 		columnTableDef := tree.ColumnTableDef{Name: tree.Name(colRes.Name), Type: colRes.Typ}
 		columnTableDef.Nullable.Nullability = tree.SilentNull
@@ -1011,6 +1132,7 @@ func MakeTableDesc(
 	evalCtx *tree.EvalContext,
 ) (sqlbase.MutableTableDescriptor, error) {
 	desc := InitTableDescriptor(id, parentID, n.Table.Table(), creationTime, privileges)
+	desc.Temporary = n.Temporary
 
 	for _, def := range n.Defs {
 		if d, ok := def.(*tree.ColumnTableDef); ok {
@@ -1023,6 +1145,9 @@ func MakeTableDesc(
 					)
 				}
 			}
+			if err := checkVirtualColumnSupported(st, d); err != nil {
+				return desc, err
+			}
 			col, idx, expr, err := sqlbase.MakeColumnDefDescs(d, semaCtx)
 			if err != nil {
 				return desc, err
@@ -1097,6 +1222,9 @@ func MakeTableDesc(
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
+			if err := checkArrayInvertedIndexSupported(st, &desc, &idx); err != nil {
+				return desc, err
+			}
 			if d.PartitionBy != nil {
 				partitioning, err := CreatePartitioning(ctx, st, evalCtx, &desc, &idx, d.PartitionBy)
 				if err != nil {
@@ -1104,6 +1232,14 @@ func MakeTableDesc(
 				}
 				idx.Partitioning = partitioning
 			}
+			if d.Predicate != nil {
+				var err error
+				if idx.Predicate, err = MakePartialIndexPredicate(
+					ctx, st, &desc, &idx, d.Predicate, semaCtx, n.Table,
+				); err != nil {
+					return desc, err
+				}
+			}
 			if err := desc.AddIndex(idx, false); err != nil {
 				return desc, err
 			}
@@ -1111,11 +1247,20 @@ func MakeTableDesc(
 				return desc, pgerror.UnimplementedWithIssue(9148, "use CREATE INDEX to make interleaved indexes")
 			}
 		case *tree.UniqueConstraintTableDef:
+			if err := checkDeferrableSupported(st, d.Deferrable); err != nil {
+				return desc, err
+			}
 			idx := sqlbase.IndexDescriptor{
 				Name:             string(d.Name),
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
+			if d.Deferrable != tree.NotDeferrable {
+				// The index of a deferrable constraint can hold duplicate values
+				// until the constraint is checked, so it is not unique.
+				idx.Unique = false
+				idx.UniqueDeferrability = sqlbase.ConstraintDeferrabilityValue[d.Deferrable]
+			}
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
@@ -1126,6 +1271,14 @@ func MakeTableDesc(
 				}
 				idx.Partitioning = partitioning
 			}
+			if d.Predicate != nil {
+				var err error
+				if idx.Predicate, err = MakePartialIndexPredicate(
+					ctx, st, &desc, &idx, d.Predicate, semaCtx, n.Table,
+				); err != nil {
+					return desc, err
+				}
+			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
 				return desc, err
 			}
@@ -1168,6 +1321,12 @@ func MakeTableDesc(
 		}
 	}
 
+	for _, colName := range desc.PrimaryIndex.ColumnNames {
+		if col, _, err := desc.FindColumnByName(tree.Name(colName)); err == nil && col.Virtual {
+			return desc, sqlbase.NewVirtualPrimaryKeyColumnError(colName)
+		}
+	}
+
 	if err := desc.AllocateIDs(); err != nil {
 		return desc, err
 	}
@@ -1220,6 +1379,9 @@ func MakeTableDesc(
 			desc.Checks = append(desc.Checks, ck)
 
 		case *tree.ForeignKeyConstraintTableDef:
+			if err := checkDeferrableSupported(st, d.Deferrable); err != nil {
+				return desc, err
+			}
 			if err := ResolveFK(ctx, txn, fkResolver, &desc, d, affected, NewTable); err != nil {
 				return desc, err
 			}
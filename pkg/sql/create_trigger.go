@@ -0,0 +1,188 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+}
+
+// CreateTrigger creates a trigger on a table.
+// Privileges: CREATE on table.
+//   notes: postgres requires TRIGGER on the table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	// Writes through nodes running older versions would not fire triggers.
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionTriggers) {
+		return nil, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`CREATE TRIGGER requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionTriggers),
+		)
+	}
+
+	tableDesc, err := p.ResolveMutableTableDescriptor(ctx, &n.Table, true /* required */, ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc.MaterializedView() {
+		return nil, pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+			"cannot create trigger on materialized view %q", tableDesc.Name)
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.Name) {
+			return nil, pgerror.Newf(pgerror.CodeDuplicateObjectError,
+				"trigger %q for table %q already exists", n.Name, tableDesc.Name)
+		}
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	trig := sqlbase.TableDescriptor_Trigger{
+		Name:             string(n.n.Name),
+		OnInsert:         n.n.Events&tree.TriggerInsert != 0,
+		OnUpdate:         n.n.Events&tree.TriggerUpdate != 0,
+		OnDelete:         n.n.Events&tree.TriggerDelete != 0,
+		Body:             n.n.Body,
+		ForEachStatement: n.n.ForEachStatement,
+	}
+	if n.n.ActionTime == tree.TriggerAfter {
+		trig.ActionTime = sqlbase.TableDescriptor_Trigger_AFTER
+	}
+	if err := p.validateTriggerBody(ctx, n.tableDesc.TableDesc(), &trig); err != nil {
+		return err
+	}
+
+	n.tableDesc.Triggers = append(n.tableDesc.Triggers, trig)
+	sort.Slice(n.tableDesc.Triggers, func(i, j int) bool {
+		return n.tableDesc.Triggers[i].Name < n.tableDesc.Triggers[j].Name
+	})
+	if err := n.tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
+		return err
+	}
+	if err := p.writeSchemaChange(ctx, n.tableDesc, sqlbase.InvalidMutationID); err != nil {
+		return err
+	}
+
+	// Record this trigger creation in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogCreateTrigger,
+		int32(n.tableDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TableName   string
+			TriggerName string
+			Statement   string
+			User        string
+		}{n.n.Table.FQString(), trig.Name, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
+
+// validateTriggerBody verifies that the body of the given trigger of the
+// given table is a valid statement, without running it. A statement which
+// modifies the table itself is rejected. The result of the SELECT statement of
+// a BEFORE trigger must name columns of the table which can be modified.
+func (p *planner) validateTriggerBody(
+	ctx context.Context, desc *sqlbase.TableDescriptor, trig *sqlbase.TableDescriptor_Trigger,
+) error {
+	ie, ok := p.ExtendedEvalContext().InternalExecutor.(*SessionBoundInternalExecutor)
+	if !ok {
+		return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+			"cannot create triggers in this context")
+	}
+	t, err := makeTrigger(desc, trig)
+	if err != nil {
+		return err
+	}
+	stmt, _, err := parseTriggerBody(desc, trig)
+	if err != nil {
+		return err
+	}
+
+	var target tree.TableExpr
+	switch s := stmt.(type) {
+	case *tree.Insert:
+		target = s.Table
+	case *tree.Update:
+		target = s.Table
+	case *tree.Delete:
+		target = s.Table
+	}
+	if target != nil {
+		tn, _, err := p.getAliasedTableName(target)
+		if err != nil {
+			return err
+		}
+		targetDesc, err := ResolveExistingObject(ctx, p, tn, true /* required */, ResolveRequireTableDesc)
+		if err != nil {
+			return err
+		}
+		if targetDesc.ID == desc.ID {
+			return pgerror.Newf(pgerror.CodeInvalidObjectDefinitionError,
+				"trigger %q cannot modify table %q, on which it is defined", trig.Name, desc.Name)
+		}
+	}
+
+	// The body is planned with NULL values for the columns of the rows: the
+	// SELECT statements are run without returning any row, and the other
+	// statements are only explained.
+	args := make([]interface{}, len(t.refs))
+	for i := range args {
+		args[i] = tree.DNull
+	}
+	if target != nil {
+		_, err := ie.Query(ctx, "validate-trigger", p.txn, "EXPLAIN "+t.query, args...)
+		return err
+	}
+	_, cols, err := ie.QueryWithCols(
+		ctx, "validate-trigger", p.txn, "SELECT * FROM ("+t.query+") LIMIT 0", args...,
+	)
+	if err != nil || !t.returnsRow || !(trig.OnInsert || trig.OnUpdate) {
+		// The result of a trigger which only fires on DELETE only determines
+		// whether the row is deleted.
+		return err
+	}
+	for i := range cols {
+		if _, err := triggerResultColumn(desc, trig.Name, &cols[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/enum"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type createTypeNode struct {
+	n      *tree.CreateType
+	tn     *tree.TableName
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateType creates a user-defined type.
+// Privileges: CREATE on database.
+func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionEnums) {
+		return nil, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`CREATE TYPE requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionEnums),
+		)
+	}
+
+	tn := n.TypeName.ToTableName()
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &tn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createTypeNode{
+		n:      n,
+		tn:     &tn,
+		dbDesc: dbDesc,
+	}, nil
+}
+
+func (n *createTypeNode) startExec(params runParams) error {
+	switch n.n.Variety {
+	case tree.Enum:
+		return params.p.createEnum(params, n)
+	default:
+		return pgerror.AssertionFailedf("unknown type variety: %v", n.n.Variety)
+	}
+}
+
+func (*createTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTypeNode) Close(context.Context)        {}
+
+// maxEnumLabelLength is the maximum length of an enum label, in bytes. It
+// matches the limit imposed by Postgres.
+const maxEnumLabelLength = 63
+
+func (p *planner) createEnum(params runParams, n *createTypeNode) error {
+	typeName := n.tn.Table()
+
+	// Names of builtin types cannot be shadowed, since they are resolved before
+	// user-defined types.
+	if _, ok, _ := types.TypeForNonKeywordTypeName(typeName); ok {
+		return sqlbase.NewTypeAlreadyExistsError(typeName)
+	}
+	key := sqlbase.NewTableKey(n.dbDesc.ID, typeName)
+	if id, err := getDescriptorID(params.ctx, p.txn, key); err != nil {
+		return err
+	} else if id != sqlbase.InvalidID {
+		if _, err := sqlbase.GetTypeDescFromID(params.ctx, p.txn, id); err == nil {
+			return sqlbase.NewTypeAlreadyExistsError(typeName)
+		}
+		return sqlbase.NewRelationAlreadyExistsError(typeName)
+	}
+
+	seen := make(map[tree.EnumValue]struct{}, len(n.n.EnumLabels))
+	for _, label := range n.n.EnumLabels {
+		if err := checkEnumLabel(label); err != nil {
+			return err
+		}
+		if _, ok := seen[label]; ok {
+			return pgerror.Newf(pgerror.CodeDuplicateObjectError,
+				"enum definition contains duplicate value %q", label)
+		}
+		seen[label] = struct{}{}
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	physReps := enum.GenerateNEvenlySpacedBytes(len(n.n.EnumLabels))
+	members := make([]sqlbase.TypeDescriptor_EnumMember, len(n.n.EnumLabels))
+	for i := range n.n.EnumLabels {
+		members[i] = sqlbase.TypeDescriptor_EnumMember{
+			PhysicalRepresentation: physReps[i],
+			LogicalRepresentation:  string(n.n.EnumLabels[i]),
+			Capability:             sqlbase.TypeDescriptor_EnumMember_ALL,
+		}
+	}
+
+	// Inherit permissions from the database descriptor.
+	typeDesc := sqlbase.TypeDescriptor{
+		Name:        typeName,
+		ID:          id,
+		ParentID:    n.dbDesc.ID,
+		Version:     1,
+		EnumMembers: members,
+		Privileges:  n.dbDesc.GetPrivileges(),
+	}
+	if err := typeDesc.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.createDescriptorWithID(
+		params.ctx, key.Key(), id, &typeDesc, params.EvalContext().Settings,
+	); err != nil {
+		return err
+	}
+
+	// Log Create Type event. This is an auditable log event and is recorded in
+	// the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		p.txn,
+		EventLogCreateType,
+		int32(typeDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.tn.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+// checkEnumLabel verifies that the given string can be used as the label of
+// an enum member.
+func checkEnumLabel(label tree.EnumValue) error {
+	if len(label) == 0 || len(label) > maxEnumLabelLength {
+		return pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"invalid enum label %q", label).SetDetailf(
+			"Labels must be non-empty and %d bytes or less.", maxEnumLabelLength)
+	}
+	return nil
+}
+
+// writeTypeDesc bumps the version of the given type descriptor and writes it
+// to the store.
+func (p *planner) writeTypeDesc(ctx context.Context, typeDesc *sqlbase.TypeDescriptor) error {
+	typeDesc.Version++
+	if err := typeDesc.Validate(); err != nil {
+		return err
+	}
+	descKey := sqlbase.MakeDescMetadataKey(typeDesc.ID)
+	descVal := sqlbase.WrapDescriptor(typeDesc)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", descKey, descVal)
+	}
+	b := &client.Batch{}
+	b.Put(descKey, descVal)
+	return p.txn.Run(ctx, b)
+}
+
+// resolveColumnTypes resolves the references to user-defined types in the
+// given column definitions, replacing them with the types they refer to. It
+// returns the definitions followed by the CHECK constraints of the domains
+// used by the columns.
+func (p *planner) resolveColumnTypes(
+	ctx context.Context, defs tree.TableDefs,
+) (tree.TableDefs, error) {
+	var checks tree.TableDefs
+	for _, def := range defs {
+		if d, ok := def.(*tree.ColumnTableDef); ok {
+			domainChecks, err := p.resolveColumnType(ctx, d)
+			if err != nil {
+				return nil, err
+			}
+			for _, check := range domainChecks {
+				checks = append(checks, check)
+			}
+		}
+	}
+	if len(checks) == 0 {
+		return defs, nil
+	}
+	return append(append(tree.TableDefs(nil), defs...), checks...), nil
+}
+
+// typeIDsReferencedByColumns returns the IDs of the user-defined types used by
+// the given columns, including the domains they were declared with, without
+// duplicates.
+func typeIDsReferencedByColumns(cols []sqlbase.ColumnDescriptor) []sqlbase.ID {
+	var ids []sqlbase.ID
+	add := func(id sqlbase.ID) {
+		for _, other := range ids {
+			if other == id {
+				return
+			}
+		}
+		ids = append(ids, id)
+	}
+	for i := range cols {
+		if id, ok := types.UserDefinedTypeOIDToID(cols[i].Type.Oid()); ok {
+			add(sqlbase.ID(id))
+		}
+		if cols[i].DomainID != sqlbase.InvalidID {
+			add(cols[i].DomainID)
+		}
+	}
+	return ids
+}
+
+// typeIDsReferencedByTable returns the IDs of the user-defined types used by
+// the columns of the given table, including the columns that are being added.
+func typeIDsReferencedByTable(desc *sqlbase.TableDescriptor) []sqlbase.ID {
+	cols := append([]sqlbase.ColumnDescriptor(nil), desc.Columns...)
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil && m.Direction == sqlbase.DescriptorMutation_ADD {
+			cols = append(cols, *col)
+		}
+	}
+	return typeIDsReferencedByColumns(cols)
+}
+
+// addTypeBackReferences records in the descriptors of the given types that
+// they are referenced by the table with the given ID.
+func (p *planner) addTypeBackReferences(
+	ctx context.Context, typeIDs []sqlbase.ID, tableID sqlbase.ID,
+) error {
+	for _, id := range typeIDs {
+		typeDesc, err := sqlbase.GetTypeDescFromID(ctx, p.txn, id)
+		if err != nil {
+			return err
+		}
+		typeDesc.AddReferencingDescriptorID(tableID)
+		if err := p.writeTypeDesc(ctx, typeDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeTypeBackReferences records in the descriptors of the given types that
+// they are no longer referenced by the table with the given ID.
+func (p *planner) removeTypeBackReferences(
+	ctx context.Context, typeIDs []sqlbase.ID, tableID sqlbase.ID,
+) error {
+	for _, id := range typeIDs {
+		typeDesc, err := sqlbase.GetTypeDescFromID(ctx, p.txn, id)
+		if err == sqlbase.ErrDescriptorNotFound {
+			// The type was dropped along with its database.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		typeDesc.RemoveReferencingDescriptorID(tableID)
+		if err := p.writeTypeDesc(ctx, typeDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateTypeBackReferences updates the back-references to the table with the
+// given ID in the descriptors of the user-defined types, after the set of
+// types used by the table has changed from oldTypeIDs to newTypeIDs.
+func (p *planner) updateTypeBackReferences(
+	ctx context.Context, oldTypeIDs, newTypeIDs []sqlbase.ID, tableID sqlbase.ID,
+) error {
+	contains := func(ids []sqlbase.ID, id sqlbase.ID) bool {
+		for _, other := range ids {
+			if other == id {
+				return true
+			}
+		}
+		return false
+	}
+	var added, removed []sqlbase.ID
+	for _, id := range newTypeIDs {
+		if !contains(oldTypeIDs, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range oldTypeIDs {
+		if !contains(newTypeIDs, id) {
+			removed = append(removed, id)
+		}
+	}
+	if err := p.addTypeBackReferences(ctx, added, tableID); err != nil {
+		return err
+	}
+	return p.removeTypeBackReferences(ctx, removed, tableID)
+}
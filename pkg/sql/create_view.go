@@ -16,7 +16,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -36,17 +37,25 @@ type createViewNode struct {
 }
 
 // CreateView creates a view.
-// Privileges: CREATE on database plus SELECT on all the selected columns.
+// Privileges: CREATE on schema (on database for the public schema) plus
+// SELECT on all the selected columns.
 //   notes: postgres requires CREATE on database plus SELECT on all the
 //						selected columns.
 //          mysql requires CREATE VIEW plus SELECT on all the selected columns.
 func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode, error) {
-	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Name)
+	if n.Materialized && !p.ExecCfg().Settings.Version.IsActive(cluster.VersionMaterializedViews) {
+		return nil, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`CREATE MATERIALIZED VIEW requires all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionMaterializedViews),
+		)
+	}
+
+	dbDesc, err := p.resolveUncachedDatabaseForRelation(ctx, &n.Name, false /* allowTemporary */)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if err := p.checkSchemaCreatePrivilege(ctx, dbDesc, n.Name.Schema()); err != nil {
 		return nil, err
 	}
 
@@ -62,6 +71,15 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 		return nil, err
 	}
 
+	// Temporary tables are dropped when their session ends, so permanent views
+	// cannot depend on them.
+	for _, dep := range planDeps {
+		if dep.desc.Temporary {
+			return nil, pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+				"cannot create view %q on temporary table %q", tree.ErrString(&n.Name), dep.desc.Name)
+		}
+	}
+
 	// Ensure that all the table names pretty-print as fully qualified,
 	// so we store that in the view descriptor.
 	//
@@ -107,8 +125,15 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 }
 
 func (n *createViewNode) startExec(params runParams) error {
+	// Inherit permissions from the schema, or from the database descriptor
+	// for the public schema.
+	parentID, privs, err := params.p.schemaForCreate(params.ctx, n.dbDesc, n.n.Name.Schema())
+	if err != nil {
+		return err
+	}
+
 	viewName := n.n.Name.Table()
-	tKey := sqlbase.NewTableKey(n.dbDesc.ID, viewName)
+	tKey := sqlbase.NewTableKey(parentID, viewName)
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		// TODO(a-robinson): Support CREATE OR REPLACE commands.
@@ -122,9 +147,6 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
-	// Inherit permissions from the database descriptor.
-	privs := n.dbDesc.GetPrivileges()
-
 	desc, err := n.makeViewTableDesc(
 		params,
 		viewName,
@@ -137,6 +159,9 @@ func (n *createViewNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	if parentID != n.dbDesc.ID {
+		desc.UnexposedParentSchemaID = parentID
+	}
 
 	// Collect all the tables/views this view depends on.
 	for backrefID := range n.planDeps {
@@ -173,6 +198,14 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
+	if desc.MaterializedView() {
+		if err := params.p.refreshMaterializedView(
+			params.ctx, desc.TableDesc(), hlc.Timestamp{}, true, /* isNew */
+		); err != nil {
+			return err
+		}
+	}
+
 	// Log Create View event. This is an auditable log event and is
 	// recorded in the same transaction as the table descriptor update.
 	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
@@ -212,6 +245,9 @@ func (n *createViewNode) makeViewTableDesc(
 	desc := InitTableDescriptor(id, parentID, viewName,
 		params.p.txn.CommitTimestamp(), privileges)
 	desc.ViewQuery = tree.AsStringWithFlags(n.n.AsSource, tree.FmtParsable)
+	// A materialized view stores its rows in a primary index keyed on a
+	// hidden rowid column, which AllocateIDs adds below.
+	desc.IsMaterializedView = n.n.Materialized
 	for i, colRes := range resultColumns {
 		columnTableDef := tree.ColumnTableDef{Name: tree.Name(colRes.Name), Type: colRes.Typ}
 		if len(columnNames) > i {
@@ -309,7 +309,7 @@ func (p *planner) getPlanForDesc(
 	indexFlags *tree.IndexFlags,
 	colCfg scanColumnsConfig,
 ) (planDataSource, error) {
-	if desc.IsView() {
+	if desc.IsView() && !desc.MaterializedView() {
 		if colCfg.wantedColumns != nil {
 			return planDataSource{},
 				errors.Errorf("cannot specify an explicit column list when accessing a view by reference")
@@ -319,12 +319,12 @@ func (p *planner) getPlanForDesc(
 	if desc.IsSequence() {
 		return p.getSequenceSource(ctx, *tn, desc)
 	}
-	if !desc.IsTable() {
+	if !desc.IsTable() && !desc.MaterializedView() {
 		return planDataSource{}, errors.Errorf(
 			"unexpected table descriptor of type %s for %q", desc.TypeName(), tree.ErrString(tn))
 	}
 
-	// This name designates a real table.
+	// This name designates a real table or a materialized view.
 	scan := p.Scan()
 	if err := scan.initTable(ctx, p, desc, indexFlags, colCfg); err != nil {
 		return planDataSource{}, err
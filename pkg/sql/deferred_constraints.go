@@ -0,0 +1,214 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// constraintsMode is the checking mode of the deferrable constraints set by
+// SET CONSTRAINTS ALL for the rest of a transaction.
+type constraintsMode int
+
+const (
+	// constraintsDefault checks each constraint as it was declared:
+	// INITIALLY DEFERRED constraints are checked at commit, and the other
+	// ones at the end of each statement.
+	constraintsDefault constraintsMode = iota
+	// constraintsAllDeferred checks every deferrable constraint at commit.
+	constraintsAllDeferred
+	// constraintsAllImmediate checks every constraint at the end of each
+	// statement.
+	constraintsAllImmediate
+)
+
+// deferredConstraints queues the checks of the deferrable constraints of a
+// transaction. It is part of the per-transaction state of a connExecutor.
+type deferredConstraints struct {
+	// boundTxn is set for the executors which run statements in the
+	// transaction of another executor, such as the session-bound internal
+	// executors. Such an executor does not commit the transaction, so none of
+	// its checks can be deferred.
+	boundTxn bool
+
+	// mode is set by SET CONSTRAINTS ALL.
+	mode constraintsMode
+
+	// named holds the constraints set by SET CONSTRAINTS with a list of
+	// names since the last SET CONSTRAINTS ALL, which override mode. The
+	// value is set for deferred constraints.
+	named map[tree.ConstraintID]bool
+
+	// stmtChecks are run at the end of the current statement, once its
+	// writes have been sent.
+	stmtChecks []tree.ConstraintCheck
+
+	// txnChecks are run when the transaction commits.
+	txnChecks []queuedConstraintCheck
+
+	// numDeferred is the number of checks deferred in the transaction so far.
+	// It identifies the checks deferred after a savepoint was established.
+	numDeferred int
+}
+
+// queuedConstraintCheck is a check deferred until the transaction commits.
+type queuedConstraintCheck struct {
+	id    tree.ConstraintID
+	check tree.ConstraintCheck
+	// seq is the position of the check among all the checks deferred in the
+	// transaction.
+	seq int
+}
+
+var _ tree.DeferrableConstraints = &deferredConstraints{}
+
+// Deferred is part of the tree.DeferrableConstraints interface.
+func (dc *deferredConstraints) Deferred(id tree.ConstraintID, initiallyDeferred bool) bool {
+	if dc.boundTxn {
+		return false
+	}
+	if deferred, ok := dc.named[id]; ok {
+		return deferred
+	}
+	switch dc.mode {
+	case constraintsAllDeferred:
+		return true
+	case constraintsAllImmediate:
+		return false
+	default:
+		return initiallyDeferred
+	}
+}
+
+// QueueCheck is part of the tree.DeferrableConstraints interface.
+func (dc *deferredConstraints) QueueCheck(
+	id tree.ConstraintID, check tree.ConstraintCheck, deferred bool,
+) {
+	if deferred {
+		dc.txnChecks = append(dc.txnChecks, queuedConstraintCheck{
+			id: id, check: check, seq: dc.numDeferred,
+		})
+		dc.numDeferred++
+	} else {
+		dc.stmtChecks = append(dc.stmtChecks, check)
+	}
+}
+
+// HasChecks is part of the tree.DeferrableConstraints interface.
+func (dc *deferredConstraints) HasChecks() bool {
+	return len(dc.stmtChecks) > 0 || len(dc.txnChecks) > 0
+}
+
+// RunStatementChecks is part of the tree.DeferrableConstraints interface.
+func (dc *deferredConstraints) RunStatementChecks(ctx context.Context, txn *client.Txn) error {
+	checks := dc.stmtChecks
+	dc.stmtChecks = nil
+	return runConstraintChecks(ctx, txn, checks)
+}
+
+// discardStatementChecks drops the checks queued by the current statement.
+// It is called when the statement fails, so that they are not run at the end
+// of the next one.
+func (dc *deferredConstraints) discardStatementChecks() {
+	dc.stmtChecks = nil
+}
+
+// savepointMark returns a mark identifying the checks deferred so far, which
+// is recorded when a savepoint is established.
+func (dc *deferredConstraints) savepointMark() int {
+	return dc.numDeferred
+}
+
+// rollbackToSavepoint drops the checks queued since the savepoint with the
+// given mark was established, since the writes they check have been rolled
+// back.
+func (dc *deferredConstraints) rollbackToSavepoint(mark int) {
+	dc.stmtChecks = nil
+	i := len(dc.txnChecks)
+	for i > 0 && dc.txnChecks[i-1].seq >= mark {
+		i--
+	}
+	dc.txnChecks = dc.txnChecks[:i]
+}
+
+// setMode implements SET CONSTRAINTS ALL. When the constraints become
+// immediate, the checks deferred so far are run right away.
+func (dc *deferredConstraints) setMode(ctx context.Context, txn *client.Txn, deferred bool) error {
+	dc.named = nil
+	if deferred {
+		dc.mode = constraintsAllDeferred
+		return nil
+	}
+	dc.mode = constraintsAllImmediate
+	return dc.runDeferred(ctx, txn)
+}
+
+// setNamedMode implements SET CONSTRAINTS with a list of names, which have
+// been resolved to the given deferrable constraints. When they become
+// immediate, the checks of these constraints deferred so far are run right
+// away.
+func (dc *deferredConstraints) setNamedMode(
+	ctx context.Context, txn *client.Txn, ids []tree.ConstraintID, deferred bool,
+) error {
+	if dc.named == nil {
+		dc.named = make(map[tree.ConstraintID]bool, len(ids))
+	}
+	for _, id := range ids {
+		dc.named[id] = deferred
+	}
+	if deferred {
+		return nil
+	}
+	var checks []tree.ConstraintCheck
+	remaining := dc.txnChecks[:0]
+	for _, c := range dc.txnChecks {
+		if deferred, ok := dc.named[c.id]; ok && !deferred {
+			checks = append(checks, c.check)
+		} else {
+			remaining = append(remaining, c)
+		}
+	}
+	dc.txnChecks = remaining
+	return runConstraintChecks(ctx, txn, checks)
+}
+
+// runDeferred runs the checks deferred until the transaction commits.
+func (dc *deferredConstraints) runDeferred(ctx context.Context, txn *client.Txn) error {
+	checks := make([]tree.ConstraintCheck, len(dc.txnChecks))
+	for i := range dc.txnChecks {
+		checks[i] = dc.txnChecks[i].check
+	}
+	dc.txnChecks = nil
+	return runConstraintChecks(ctx, txn, checks)
+}
+
+// reset prepares for a new transaction.
+func (dc *deferredConstraints) reset() {
+	*dc = deferredConstraints{boundTxn: dc.boundTxn}
+}
+
+// runConstraintChecks runs the given checks in order, and returns the error
+// of the first one which fails.
+func runConstraintChecks(
+	ctx context.Context, txn *client.Txn, checks []tree.ConstraintCheck,
+) error {
+	for _, check := range checks {
+		if err := check.Run(ctx, txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -58,6 +58,10 @@ func (p *planner) Delete(
 		return nil, pgerror.DangerousStatementf("DELETE without WHERE clause")
 	}
 
+	if len(n.Using) > 0 {
+		return nil, pgerror.Unimplemented("delete using", "DELETE ... USING requires the optimizer")
+	}
+
 	// CTE analysis.
 	resetter, err := p.initWith(ctx, n.With)
 	if err != nil {
@@ -116,11 +120,12 @@ func (p *planner) Delete(
 	// Also, rowsNeeded determines which rows of the source we need
 	// in the table deleter.
 	var requestedCols []sqlbase.ColumnDescriptor
-	if rowsNeeded {
+	if rowsNeeded || len(desc.Triggers) > 0 {
 		// Note: in contrast to INSERT and UPDATE which also require the
 		// data if there are CHECK expressions, DELETE does not care about
 		// constraint checking (because the rows are being deleted after
-		// all).
+		// all). Row triggers however have access to all the columns of the
+		// deleted rows.
 
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
 		// exprs.
@@ -231,7 +236,12 @@ func (d *deleteNode) startExec(params runParams) error {
 			params.EvalContext().Mon.MakeBoundAccount(),
 			sqlbase.ColTypeInfoFromResCols(d.columns), 0)
 	}
-	return d.run.td.init(params.p.txn, params.EvalContext())
+	if err := d.run.td.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	return d.run.td.triggers.init(
+		params.ctx, params.p.txn, params.EvalContext(), d.run.td.tableDesc(), tree.TriggerDelete,
+	)
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -279,8 +289,6 @@ func (d *deleteNode) BatchedNext(params runParams) (bool, error) {
 			return false, err
 		}
 
-		d.run.rowCount++
-
 		// Are we done yet with the current batch?
 		if d.run.td.curBatchSize() >= maxDeleteBatchSize {
 			break
@@ -321,10 +329,16 @@ func (d *deleteNode) BatchedNext(params runParams) (bool, error) {
 // processSourceRow processes one row from the source for deletion and, if
 // result rows are needed, saves it in the result row container
 func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums) error {
+	// Fire the BEFORE triggers, if any. They can skip the row.
+	if skip, err := d.run.td.fireBeforeTriggers(params.ctx, sourceVals); skip || err != nil {
+		return err
+	}
+
 	// Queue the deletion in the KV batch.
 	if err := d.run.td.row(params.ctx, sourceVals, d.run.traceKV); err != nil {
 		return err
 	}
+	d.run.rowCount++
 
 	// If result rows need to be accumulated, do it.
 	if d.run.rows != nil {
@@ -68,6 +68,11 @@ func maybeCreateDeleteFastNode(
 		return nil, false
 	}
 
+	// Row triggers fire for each deleted row, so the rows must be fetched.
+	if len(desc.Triggers) > 0 {
+		return nil, false
+	}
+
 	// If the rows are needed (a RETURNING clause), we can't skip them.
 	if rowsNeeded {
 		return nil, false
@@ -145,7 +150,7 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 		return err
 	}
 	if err := d.fetcher.Init(
-		false, false, false, &params.p.alloc,
+		params.EvalContext(), false, false, false, &params.p.alloc,
 		row.FetcherTableArgs{
 			Desc:  d.desc,
 			Index: &d.desc.PrimaryIndex,
@@ -37,6 +37,19 @@ var (
 	errNoDatabase        = pgerror.New(pgerror.CodeInvalidNameError, "no database specified")
 	errNoTable           = pgerror.New(pgerror.CodeInvalidNameError, "no table specified")
 	errNoMatch           = pgerror.New(pgerror.CodeUndefinedObjectError, "no object matched")
+
+	// errDescriptorIsType is returned by getDescriptorByID when a table is
+	// requested but the descriptor is a user-defined type. Types share the
+	// namespace with tables, so name resolution for relations treats it as
+	// "not found".
+	errDescriptorIsType = pgerror.New(pgerror.CodeWrongObjectTypeError, "descriptor is a type")
+
+	// errDescriptorIsSchema is returned by getDescriptorByID when a table is
+	// requested but the ID belongs to a schema: either a user-defined schema,
+	// or a temporary schema, which has a namespace entry but no descriptor.
+	// Schemas share the namespace with tables, so name resolution for
+	// relations treats it as "not found".
+	errDescriptorIsSchema = pgerror.New(pgerror.CodeWrongObjectTypeError, "descriptor is a schema")
 )
 
 // GenerateUniqueDescID returns the next available Descriptor ID and increments
@@ -174,6 +187,12 @@ func getDescriptorByID(
 	case *sqlbase.TableDescriptor:
 		table := desc.GetTable()
 		if table == nil {
+			if desc.GetType() != nil {
+				return errDescriptorIsType
+			}
+			if desc.GetSchema() != nil || desc.Union == nil {
+				return errDescriptorIsSchema
+			}
 			return pgerror.Newf(pgerror.CodeWrongObjectTypeError,
 				"%q is not a table", desc.String())
 		}
@@ -194,10 +213,95 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.TypeDescriptor:
+		typ := desc.GetType()
+		if typ == nil {
+			return pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+				"%q is not a type", desc.String())
+		}
+
+		if err := typ.Validate(); err != nil {
+			return err
+		}
+		*t = *typ
+	case *sqlbase.SchemaDescriptor:
+		schema := desc.GetSchema()
+		if schema == nil {
+			return pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+				"%q is not a schema", desc.String())
+		}
+
+		if err := schema.Validate(); err != nil {
+			return err
+		}
+		*t = *schema
+	case *sqlbase.FunctionDescriptor:
+		fn := desc.GetFunction()
+		if fn == nil {
+			return pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+				"%q is not a function", desc.String())
+		}
+
+		if err := fn.Validate(); err != nil {
+			return err
+		}
+		*t = *fn
 	}
 	return nil
 }
 
+// getDescriptorsByID looks up the descriptors with the given IDs, indexed by
+// ID. IDs without a descriptor are omitted.
+func getDescriptorsByID(
+	ctx context.Context, txn *client.Txn, ids []sqlbase.ID,
+) (map[sqlbase.ID]*sqlbase.Descriptor, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	b := txn.NewBatch()
+	for _, id := range ids {
+		b.Get(sqlbase.MakeDescMetadataKey(id))
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	res := make(map[sqlbase.ID]*sqlbase.Descriptor, len(ids))
+	for i := range b.Results {
+		for _, kv := range b.Results[i].Rows {
+			if !kv.Exists() {
+				continue
+			}
+			desc := &sqlbase.Descriptor{}
+			if err := kv.ValueProto(desc); err != nil {
+				return nil, err
+			}
+			res[ids[i]] = desc
+		}
+	}
+	return res, nil
+}
+
+// getTypeDescriptorsByID looks up the descriptors with the given IDs and
+// returns the ones that describe user-defined types, indexed by ID.
+func getTypeDescriptorsByID(
+	ctx context.Context, txn *client.Txn, ids []sqlbase.ID,
+) (map[sqlbase.ID]*sqlbase.TypeDescriptor, error) {
+	descs, err := getDescriptorsByID(ctx, txn, ids)
+	if err != nil {
+		return nil, err
+	}
+	var res map[sqlbase.ID]*sqlbase.TypeDescriptor
+	for _, desc := range descs {
+		if typ := desc.GetType(); typ != nil {
+			if res == nil {
+				res = make(map[sqlbase.ID]*sqlbase.TypeDescriptor)
+			}
+			res[typ.ID] = typ
+		}
+	}
+	return res, nil
+}
+
 // GetAllDescriptors looks up and returns all available descriptors.
 func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.DescriptorProto, error) {
 	log.Eventf(ctx, "fetching all descriptors")
@@ -218,6 +322,12 @@ func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 			descs[i] = desc.GetTable()
 		case *sqlbase.Descriptor_Database:
 			descs[i] = desc.GetDatabase()
+		case *sqlbase.Descriptor_Type:
+			descs[i] = desc.GetType()
+		case *sqlbase.Descriptor_Schema:
+			descs[i] = desc.GetSchema()
+		case *sqlbase.Descriptor_Function:
+			descs[i] = desc.GetFunction()
 		default:
 			return nil, pgerror.AssertionFailedf("Descriptor.Union has unexpected type %T", t)
 		}
@@ -36,12 +36,41 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// DISCARD TEMP
+		if err := p.discardTemporaryObjects(ctx); err != nil {
+			return nil, err
+		}
+	case tree.DiscardModeTemp:
+		if !p.autoCommit {
+			return nil, pgerror.New(pgerror.CodeActiveSQLTransactionError,
+				"DISCARD TEMP cannot run inside a transaction block")
+		}
+		if err := p.discardTemporaryObjects(ctx); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, pgerror.AssertionFailedf("unknown mode for DISCARD: %d", s.Mode)
 	}
 	return newZeroNode(nil /* columns */), nil
 }
 
+// discardTemporaryObjects drops all the temporary objects created by the
+// current session. The objects are dropped in their own transactions, which
+// is why DISCARD cannot run inside a transaction block.
+func (p *planner) discardTemporaryObjects(ctx context.Context) error {
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	if tempSchemaName == "" {
+		return nil
+	}
+	ie, ok := p.ExtendedEvalContext().InternalExecutor.(*SessionBoundInternalExecutor)
+	if !ok {
+		return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+			"cannot discard temporary objects in this context")
+	}
+	return cleanupSessionTempObjects(ctx, p.ExecCfg().DB, ie, tempSchemaName)
+}
+
 func resetSessionVars(ctx context.Context, m *sessionDataMutator) error {
 	for _, varName := range varNames {
 		v := varGen[varName]
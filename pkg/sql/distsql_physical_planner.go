@@ -218,11 +218,23 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 	case *tree.DOid:
 		v.err = newQueryNotSupportedError("OID expressions are not supported by distsql")
 		return false, expr
+	case *tree.DEnum:
+		// Enum values are serialized as their labels, which remote nodes cannot
+		// resolve back to the user-defined type.
+		v.err = newQueryNotSupportedError("enum expressions are not supported by distsql")
+		return false, expr
 	case *tree.CastExpr:
-		if t.Type.Family() == types.OidFamily {
+		switch t.Type.Family() {
+		case types.OidFamily, types.EnumFamily:
 			v.err = newQueryNotSupportedErrorf("cast to %s is not supported by distsql", t.Type)
 			return false, expr
 		}
+		// Checking the constraints of a domain requires its descriptor, which
+		// remote nodes cannot look up.
+		if t.Type.DomainID() != 0 {
+			v.err = newQueryNotSupportedErrorf("cast to domain %s is not supported by distsql", t.Type)
+			return false, expr
+		}
 	}
 	return true, expr
 }
@@ -313,6 +325,7 @@ func (dsp *DistSQLPlanner) mustWrapNode(planCtx *PlanningCtx, node planNode) boo
 	case *projectSetNode:
 	case *unaryNode:
 	case *windowNode:
+	case *recursiveCTENode:
 	case *zeroNode:
 	case *valuesNode:
 		// This is unfortunately duplicated by createPlanForNode, and must be kept
@@ -381,6 +394,12 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *scanNode:
+		if n.lockingStrength != sqlbase.ScanLockingStrength_FOR_NONE {
+			// Scans that lock rows need to run on the root transaction, since
+			// leaf transactions can't write intents.
+			return cannotDistribute, nil
+		}
+
 		rec := canDistribute
 		if n.softLimit != 0 {
 			// We don't yet recommend distributing plans where soft limits propagate
@@ -405,12 +424,17 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 	case *indexJoinNode:
 		// n.table doesn't have meaningful spans, but we need to check support (e.g.
 		// for any filtering expression).
-		if _, err := dsp.checkSupportForNode(n.table); err != nil {
+		if rec, err := dsp.checkSupportForNode(n.table); err != nil || rec == cannotDistribute {
 			return cannotDistribute, err
 		}
 		return dsp.checkSupportForNode(n.index)
 
 	case *lookupJoinNode:
+		if n.table.lockingStrength != sqlbase.ScanLockingStrength_FOR_NONE {
+			// Lookups that lock rows need to run on the root transaction, since
+			// leaf transactions can't write intents.
+			return cannotDistribute, nil
+		}
 		if err := dsp.checkExpr(n.onCond); err != nil {
 			return cannotDistribute, err
 		}
@@ -493,6 +517,11 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 	case *windowNode:
 		return dsp.checkSupportForNode(n.plan)
 
+	case *recursiveCTENode:
+		// The recursive CTE processor runs on the gateway, but the initial query
+		// can be distributed.
+		return dsp.checkSupportForNode(n.initial)
+
 	default:
 		return cannotDistribute, newQueryNotSupportedErrorf("unsupported node %T", node)
 	}
@@ -872,6 +901,9 @@ func initTableReaderSpec(
 		IsCheck:    n.isCheck,
 		Visibility: n.colCfg.visibility.toDistSQLScanVisibility(),
 
+		LockingStrength:   n.lockingStrength,
+		LockingWaitPolicy: n.lockingWaitPolicy,
+
 		// Retain the capacity of the spans slice.
 		Spans: s.Spans[:0],
 	}
@@ -1362,7 +1394,8 @@ func (dsp *DistSQLPlanner) addAggregators(
 		}
 	}
 
-	if prevStageNode == 0 {
+	// Grouping sets are always aggregated in a single stage.
+	if prevStageNode == 0 && len(n.groupingSets) == 0 {
 		// Check that all aggregation functions support a local stage.
 		multiStage = true
 		for _, e := range aggregations {
@@ -1440,6 +1473,17 @@ func (dsp *DistSQLPlanner) addAggregators(
 			GroupCols:        groupCols,
 			OrderedGroupCols: orderedGroupCols,
 		}
+		if len(n.groupingSets) > 0 {
+			finalAggsSpec.OrderedGroupCols = nil
+			finalAggsSpec.GroupingSets = make([]distsqlpb.AggregatorSpec_GroupingSet, len(n.groupingSets))
+			for i, set := range n.groupingSets {
+				cols := make([]uint32, len(set))
+				for j, idx := range set {
+					cols[j] = uint32(p.PlanToStreamColMap[idx])
+				}
+				finalAggsSpec.GroupingSets[i].Cols = cols
+			}
+		}
 	} else {
 		// Some aggregations might need multiple aggregation as part of
 		// their local and final stages (along with a final render
@@ -1759,20 +1803,27 @@ func (dsp *DistSQLPlanner) addAggregators(
 		}
 		finalOutTypes[i] = *returnTyp
 	}
+	if len(n.groupingSets) > 0 {
+		// The aggregator outputs the index of the grouping set as an extra
+		// column.
+		finalOutTypes = append(finalOutTypes, *types.Int)
+	}
 
 	// Update p.PlanToStreamColMap; we will have a simple 1-to-1 mapping of
 	// planNode columns to stream columns because the aggregator
 	// has been programmed to produce the same columns as the groupNode.
 	if !planToStreamMapSet {
-		p.PlanToStreamColMap = identityMap(p.PlanToStreamColMap, len(aggregations))
+		p.PlanToStreamColMap = identityMap(p.PlanToStreamColMap, len(finalOutTypes))
 	}
 
-	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 {
+	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 || len(n.groupingSets) > 0 {
 		// No GROUP BY, or we have a single stream. Use a single final aggregator.
 		// If the previous stage was all on a single node, put the final
 		// aggregator there. Otherwise, bring the results back on this node.
+		// Grouping sets can't be distributed by hash on the group columns, so
+		// they are always aggregated on this node.
 		node := dsp.nodeDesc.NodeID
-		if prevStageNode != 0 {
+		if prevStageNode != 0 && len(n.groupingSets) == 0 {
 			node = prevStageNode
 		}
 		p.AddSingleGroupStage(
@@ -1847,6 +1898,9 @@ func (dsp *DistSQLPlanner) createPlanForIndexJoin(
 		Table:      *n.index.desc.TableDesc(),
 		IndexIdx:   0,
 		Visibility: n.table.colCfg.visibility.toDistSQLScanVisibility(),
+
+		LockingStrength:   n.table.lockingStrength,
+		LockingWaitPolicy: n.table.lockingWaitPolicy,
 	}
 
 	filter, err := distsqlplan.MakeExpression(
@@ -1914,6 +1968,9 @@ func (dsp *DistSQLPlanner) createPlanForLookupJoin(
 	joinReaderSpec := distsqlpb.JoinReaderSpec{
 		Table: *n.table.desc.TableDesc(),
 		Type:  n.joinType,
+
+		LockingStrength:   n.table.lockingStrength,
+		LockingWaitPolicy: n.table.lockingWaitPolicy,
 	}
 	joinReaderSpec.IndexIdx, err = getIndexIdx(n.table)
 	if err != nil {
@@ -2399,6 +2456,9 @@ func (dsp *DistSQLPlanner) createPlanForNode(
 	case *windowNode:
 		plan, err = dsp.createPlanForWindow(planCtx, n)
 
+	case *recursiveCTENode:
+		plan, err = dsp.createPlanForRecursiveCTE(planCtx, n)
+
 	default:
 		// Can't handle a node? We wrap it and continue on our way.
 		plan, err = dsp.wrapPlan(planCtx, n)
@@ -2550,6 +2610,77 @@ func (dsp *DistSQLPlanner) wrapPlan(planCtx *PlanningCtx, n planNode) (PhysicalP
 	return p, nil
 }
 
+// createPlanForRecursiveCTE plans the initial query of a recursive CTE and
+// adds a recursive CTE processor on the gateway. The processor is a
+// LocalProcessor because it plans and runs the recursive query for each
+// iteration.
+func (dsp *DistSQLPlanner) createPlanForRecursiveCTE(
+	planCtx *PlanningCtx, n *recursiveCTENode,
+) (PhysicalPlan, error) {
+	p, err := dsp.createPlanForNode(planCtx, n.initial)
+	if err != nil {
+		return PhysicalPlan{}, err
+	}
+	// The processor expects the columns of the initial query, in order.
+	projection := make([]uint32, len(p.PlanToStreamColMap))
+	for i, col := range p.PlanToStreamColMap {
+		projection[i] = uint32(col)
+	}
+	p.AddProjection(projection)
+
+	outputTypes, err := getTypesForPlanResult(n.initial, nil /* planToStreamColMap */)
+	if err != nil {
+		return PhysicalPlan{}, err
+	}
+	// Copy the evalCtx.
+	evalCtx := *planCtx.ExtendedEvalCtx
+	params := runParams{
+		extendedEvalCtx: &evalCtx,
+		p:               planCtx.planner,
+	}
+	distSQLSrv := evalCtx.ExecCfg.DistSQLSrv
+	proc := distsqlrun.NewRecursiveCTEProcessor(
+		&evalCtx.EvalContext,
+		distSQLSrv.TempStorage,
+		distSQLSrv.DiskMonitor,
+		outputTypes,
+		n.deduplicate,
+		n.iterationFn(params),
+	)
+
+	idx := uint32(len(p.LocalProcessors))
+	p.LocalProcessors = append(p.LocalProcessors, proc)
+	p.LocalProcessorIndexes = append(p.LocalProcessorIndexes, &idx)
+	nInputs := uint32(1)
+	name := nodeName(n)
+	pIdx := p.AddProcessor(distsqlplan.Processor{
+		Node: dsp.nodeDesc.NodeID,
+		Spec: distsqlpb.ProcessorSpec{
+			Input: []distsqlpb.InputSyncSpec{{
+				Type:        distsqlpb.InputSyncSpec_UNORDERED,
+				ColumnTypes: p.ResultTypes,
+			}},
+			Core: distsqlpb.ProcessorCoreUnion{LocalPlanNode: &distsqlpb.LocalPlanNodeSpec{
+				RowSourceIdx: &idx,
+				NumInputs:    &nInputs,
+				Name:         &name,
+			}},
+			Post: distsqlpb.PostProcessSpec{},
+			Output: []distsqlpb.OutputRouterSpec{{
+				Type: distsqlpb.OutputRouterSpec_PASS_THROUGH,
+			}},
+			StageID: p.NewStageID(),
+		},
+	})
+	p.MergeResultStreams(p.ResultRouters, 0, distsqlpb.Ordering{}, pIdx, 0)
+	p.ResultRouters = p.ResultRouters[:1]
+	p.ResultRouters[0] = pIdx
+	p.ResultTypes = outputTypes
+	p.MergeOrdering = distsqlpb.Ordering{}
+	p.PlanToStreamColMap = identityMapInPlace(make([]int, len(outputTypes)))
+	return p, nil
+}
+
 // createValuesPlan creates a plan with a single Values processor
 // located on the gateway node and initialized with given numRows
 // and rawBytes that need to be precomputed beforehand.
@@ -24,6 +24,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -127,6 +129,13 @@ type aggregatorBase struct {
 	orderedGroupCols columns
 	aggregations     []distsqlpb.AggregatorSpec_Aggregation
 
+	// groupingSets contains the columns of each grouping set, if any (see
+	// AggregatorSpec.GroupingSets). groupingSetNullAggs contains, for each
+	// grouping set, the ANY_NOT_NULL aggregations over grouping columns that are
+	// not part of the set; these return NULL for the rows of that set.
+	groupingSets        []columns
+	groupingSetNullAggs []util.FastIntSet
+
 	lastOrdGroupCols sqlbase.EncDatumRow
 	arena            stringarena.Arena
 	row              sqlbase.EncDatumRow
@@ -169,8 +178,8 @@ func (ag *aggregatorBase) init(
 	ag.orderedGroupCols = spec.OrderedGroupCols
 	ag.aggregations = spec.Aggregations
 	ag.funcs = make([]*aggregateFuncHolder, len(spec.Aggregations))
-	ag.outputTypes = make([]types.T, len(spec.Aggregations))
-	ag.row = make(sqlbase.EncDatumRow, len(spec.Aggregations))
+	ag.outputTypes = make([]types.T, len(spec.Aggregations), len(spec.Aggregations)+1)
+	ag.row = make(sqlbase.EncDatumRow, len(spec.Aggregations), len(spec.Aggregations)+1)
 	ag.bucketsAcc = memMonitor.MakeBoundAccount()
 	ag.arena = stringarena.Make(&ag.bucketsAcc)
 
@@ -232,6 +241,12 @@ func (ag *aggregatorBase) init(
 		ag.outputTypes[i] = *retType
 	}
 
+	if len(spec.GroupingSets) > 0 {
+		if err := ag.initGroupingSets(spec); err != nil {
+			return err
+		}
+	}
+
 	return ag.ProcessorBase.Init(
 		self, post, ag.outputTypes, flowCtx, processorID, output, memMonitor,
 		ProcStateOpts{
@@ -241,6 +256,42 @@ func (ag *aggregatorBase) init(
 	)
 }
 
+// initGroupingSets sets up the aggregatorBase for aggregating over multiple
+// grouping sets. The index of the grouping set that produced each output row
+// is appended as an extra INT output column.
+func (ag *aggregatorBase) initGroupingSets(spec *distsqlpb.AggregatorSpec) error {
+	if len(spec.OrderedGroupCols) > 0 {
+		return errors.Errorf("ordered group columns are not supported with grouping sets")
+	}
+	var groupCols util.FastIntSet
+	for _, c := range spec.GroupCols {
+		groupCols.Add(int(c))
+	}
+	ag.groupingSets = make([]columns, len(spec.GroupingSets))
+	ag.groupingSetNullAggs = make([]util.FastIntSet, len(spec.GroupingSets))
+	for i := range spec.GroupingSets {
+		var setCols util.FastIntSet
+		for _, c := range spec.GroupingSets[i].Cols {
+			if !groupCols.Contains(int(c)) {
+				return errors.Errorf("grouping set column %d is not a group column", c)
+			}
+			setCols.Add(int(c))
+		}
+		ag.groupingSets[i] = spec.GroupingSets[i].Cols
+		for j, aggInfo := range spec.Aggregations {
+			if aggInfo.Func != distsqlpb.AggregatorSpec_ANY_NOT_NULL || len(aggInfo.ColIdx) != 1 {
+				continue
+			}
+			if c := int(aggInfo.ColIdx[0]); groupCols.Contains(c) && !setCols.Contains(c) {
+				ag.groupingSetNullAggs[i].Add(j)
+			}
+		}
+	}
+	ag.outputTypes = append(ag.outputTypes, *types.Int)
+	ag.row = append(ag.row, sqlbase.EncDatum{})
+	return nil
+}
+
 var _ distsqlpb.DistSQLSpanStats = &AggregatorStats{}
 
 const aggregatorTagPrefix = "aggregator."
@@ -329,13 +380,14 @@ func newAggregator(
 	output RowReceiver,
 ) (Processor, error) {
 	if len(spec.GroupCols) == 0 &&
+		len(spec.GroupingSets) == 0 &&
 		len(spec.Aggregations) == 1 &&
 		spec.Aggregations[0].FilterColIdx == nil &&
 		spec.Aggregations[0].Func == distsqlpb.AggregatorSpec_COUNT_ROWS &&
 		!spec.Aggregations[0].Distinct {
 		return newCountAggregator(flowCtx, processorID, input, post, output)
 	}
-	if len(spec.OrderedGroupCols) == len(spec.GroupCols) {
+	if len(spec.OrderedGroupCols) == len(spec.GroupCols) && len(spec.GroupingSets) == 0 {
 		return newOrderedAggregator(flowCtx, processorID, spec, input, post, output)
 	}
 
@@ -498,7 +550,7 @@ func (ag *hashAggregator) accumulateRows() (
 
 	// Queries like `SELECT MAX(n) FROM t` expect a row of NULLs if nothing was
 	// aggregated.
-	if len(ag.buckets) < 1 && len(ag.groupCols) == 0 {
+	if len(ag.buckets) < 1 && len(ag.groupCols) == 0 && len(ag.groupingSets) == 0 {
 		bucket, err := ag.createAggregateFuncs()
 		if err != nil {
 			ag.MoveToDraining(err)
@@ -507,6 +559,23 @@ func (ag *hashAggregator) accumulateRows() (
 		ag.buckets[""] = bucket
 	}
 
+	// Similarly, an empty grouping set always produces a row.
+	for i := range ag.groupingSets {
+		if len(ag.groupingSets[i]) > 0 {
+			continue
+		}
+		key := string(encoding.EncodeUvarintAscending(nil /* appendTo */, uint64(i)))
+		if _, ok := ag.buckets[key]; ok {
+			continue
+		}
+		bucket, err := ag.createAggregateFuncs()
+		if err != nil {
+			ag.MoveToDraining(err)
+			return aggStateUnknown, nil, nil
+		}
+		ag.buckets[key] = bucket
+	}
+
 	ag.bucketsIter = make([]string, 0, len(ag.buckets))
 	for bucket := range ag.buckets {
 		ag.bucketsIter = append(ag.bucketsIter, bucket)
@@ -574,10 +643,17 @@ func (ag *orderedAggregator) accumulateRows() (
 	return aggEmittingRows, nil, nil
 }
 
+// getAggResults constructs an output row from the given bucket. groupingSet
+// is the index of the grouping set the bucket belongs to; it is only used if
+// there are grouping sets.
 func (ag *aggregatorBase) getAggResults(
-	bucket aggregateFuncs,
+	bucket aggregateFuncs, groupingSet int,
 ) (aggregatorState, sqlbase.EncDatumRow, *distsqlpb.ProducerMetadata) {
 	for i, b := range bucket {
+		if ag.groupingSets != nil && ag.groupingSetNullAggs[groupingSet].Contains(i) {
+			ag.row[i] = sqlbase.DatumToEncDatum(&ag.outputTypes[i], tree.DNull)
+			continue
+		}
 		result, err := b.Result()
 		if err != nil {
 			ag.MoveToDraining(err)
@@ -591,6 +667,12 @@ func (ag *aggregatorBase) getAggResults(
 	}
 	bucket.close(ag.Ctx)
 
+	if ag.groupingSets != nil {
+		ag.row[len(bucket)] = sqlbase.DatumToEncDatum(
+			types.Int, ag.datumAlloc.NewDInt(tree.DInt(groupingSet)),
+		)
+	}
+
 	if outRow := ag.ProcessRowHelper(ag.row); outRow != nil {
 		return aggEmittingRows, outRow, nil
 	}
@@ -645,7 +727,17 @@ func (ag *hashAggregator) emitRow() (
 	bucket := ag.bucketsIter[0]
 	ag.bucketsIter = ag.bucketsIter[1:]
 
-	return ag.getAggResults(ag.buckets[bucket])
+	groupingSet := 0
+	if ag.groupingSets != nil {
+		// The bucket key is prefixed with the index of its grouping set.
+		_, idx, err := encoding.DecodeUvarintAscending([]byte(bucket))
+		if err != nil {
+			ag.MoveToDraining(err)
+			return aggStateUnknown, nil, nil
+		}
+		groupingSet = int(idx)
+	}
+	return ag.getAggResults(ag.buckets[bucket], groupingSet)
 }
 
 // emitRow constructs an output row from an accumulated bucket and returns it.
@@ -690,7 +782,7 @@ func (ag *orderedAggregator) emitRow() (
 
 	bucket := ag.bucket
 	ag.bucket = nil
-	return ag.getAggResults(bucket)
+	return ag.getAggResults(bucket, 0 /* groupingSet */)
 }
 
 // Next is part of the RowSource interface.
@@ -808,6 +900,21 @@ func (ag *hashAggregator) accumulateRow(row sqlbase.EncDatumRow) error {
 		return err
 	}
 
+	if ag.groupingSets != nil {
+		for i := range ag.groupingSets {
+			encoded := encoding.EncodeUvarintAscending(ag.scratch, uint64(i))
+			encoded, err := ag.encodeCols(encoded, row, ag.groupingSets[i])
+			if err != nil {
+				return err
+			}
+			ag.scratch = encoded[:0]
+			if err := ag.accumulateRowIntoGroup(row, encoded); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// The encoding computed here determines which bucket the non-grouping
 	// datums are accumulated to.
 	encoded, err := ag.encode(ag.scratch, row)
@@ -816,6 +923,14 @@ func (ag *hashAggregator) accumulateRow(row sqlbase.EncDatumRow) error {
 	}
 	ag.scratch = encoded[:0]
 
+	return ag.accumulateRowIntoGroup(row, encoded)
+}
+
+// accumulateRowIntoGroup accumulates a single row into the bucket with the
+// given group key, creating the bucket if necessary.
+func (ag *hashAggregator) accumulateRowIntoGroup(
+	row sqlbase.EncDatumRow, encoded []byte,
+) error {
 	bucket, ok := ag.buckets[string(encoded)]
 	if !ok {
 		s, err := ag.arena.AllocBytes(ag.Ctx, encoded)
@@ -910,7 +1025,14 @@ func (a *aggregateFuncHolder) canAdd(
 func (ag *aggregatorBase) encode(
 	appendTo []byte, row sqlbase.EncDatumRow,
 ) (encoding []byte, err error) {
-	for _, colIdx := range ag.groupCols {
+	return ag.encodeCols(appendTo, row, ag.groupCols)
+}
+
+// encodeCols appends the encoding for the given columns of the row.
+func (ag *aggregatorBase) encodeCols(
+	appendTo []byte, row sqlbase.EncDatumRow, cols columns,
+) (encoding []byte, err error) {
+	for _, colIdx := range cols {
 		appendTo, err = row[colIdx].Encode(
 			&ag.inputTypes[colIdx], &ag.datumAlloc, sqlbase.DatumEncoding_ASCENDING_KEY, appendTo)
 		if err != nil {
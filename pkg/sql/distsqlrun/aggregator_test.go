@@ -387,6 +387,72 @@ func TestAggregator(t *testing.T) {
 				},
 			},
 		},
+		{
+			// SELECT @1, @2, sum_int(@3) GROUP BY ROLLUP (@1, @2).
+			Name: "SumGroupByRollup",
+			Input: ProcessorTestCaseRows{
+				Rows: [][]interface{}{
+					{1, 1, 10},
+					{1, 2, 20},
+					{2, 1, 30},
+					{1, 1, 40},
+				},
+				Types: sqlbase.MakeIntCols(3),
+			},
+			Output: ProcessorTestCaseRows{
+				Rows: [][]interface{}{
+					{nil, nil, 100, 2},
+					{1, nil, 70, 1},
+					{1, 1, 50, 0},
+					{1, 2, 20, 0},
+					{2, nil, 30, 1},
+					{2, 1, 30, 0},
+				},
+				Types: sqlbase.MakeIntCols(4),
+			},
+			ProcessorCore: distsqlpb.ProcessorCoreUnion{
+				Aggregator: &distsqlpb.AggregatorSpec{
+					GroupCols: []uint32{0, 1},
+					GroupingSets: []distsqlpb.AggregatorSpec_GroupingSet{
+						{Cols: []uint32{0, 1}},
+						{Cols: col0},
+						{},
+					},
+					Aggregations: aggregations([]aggTestSpec{
+						{fname: "ANY_NOT_NULL", colIdx: col0},
+						{fname: "ANY_NOT_NULL", colIdx: col1},
+						{fname: "SUM_INT", colIdx: col2},
+					}),
+				},
+			},
+		},
+		{
+			// SELECT @1, count(*) GROUP BY GROUPING SETS ((@1), ()) (no rows).
+			Name: "CountRowsGroupingSetsNoRows",
+			Input: ProcessorTestCaseRows{
+				Rows:  [][]interface{}{},
+				Types: sqlbase.MakeIntCols(1),
+			},
+			Output: ProcessorTestCaseRows{
+				Rows: [][]interface{}{
+					{nil, 0, 1},
+				},
+				Types: sqlbase.MakeIntCols(3),
+			},
+			ProcessorCore: distsqlpb.ProcessorCoreUnion{
+				Aggregator: &distsqlpb.AggregatorSpec{
+					GroupCols: col0,
+					GroupingSets: []distsqlpb.AggregatorSpec_GroupingSet{
+						{Cols: col0},
+						{},
+					},
+					Aggregations: aggregations([]aggTestSpec{
+						{fname: "ANY_NOT_NULL", colIdx: col0},
+						{fname: "COUNT_ROWS"},
+					}),
+				},
+			},
+		},
 	}
 
 	ctx := context.Background()
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/exec/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -94,6 +95,10 @@ func newColBatchScan(
 	}
 
 	neededColumns := helper.neededColumns()
+	if spec.IndexIdx == 0 && scansVirtualColumns(&spec.Table, neededColumns, returnMutations) {
+		return nil, pgerror.Newf(pgerror.CodeDataExceptionError,
+			"virtual computed columns not supported")
+	}
 
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
 	fetcher := row.CFetcher{}
@@ -117,6 +122,32 @@ func newColBatchScan(
 	}, nil
 }
 
+// scansVirtualColumns returns whether any of the needed columns of a scan of
+// the primary index of the given table is a virtual computed column. These
+// columns are not stored, and only the row-based Fetcher computes them.
+func scansVirtualColumns(
+	desc *sqlbase.TableDescriptor, neededColumns util.FastIntSet, mutations bool,
+) bool {
+	for i := range desc.Columns {
+		if desc.Columns[i].Virtual && neededColumns.Contains(i) {
+			return true
+		}
+	}
+	if !mutations {
+		return false
+	}
+	idx := len(desc.Columns)
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil {
+			if col.Virtual && neededColumns.Contains(idx) {
+				return true
+			}
+			idx++
+		}
+	}
+	return false
+}
+
 // initCRowFetcher initializes a row.CFetcher. See initRowFetcher.
 func initCRowFetcher(
 	fetcher *row.CFetcher,
@@ -116,6 +116,10 @@ func newColOperator(
 		if err := checkNumIn(inputs, 0); err != nil {
 			return nil, err
 		}
+		if core.TableReader.LockingStrength != sqlbase.ScanLockingStrength_FOR_NONE {
+			return nil, pgerror.Newf(pgerror.CodeDataExceptionError,
+				"row-level locking not supported")
+		}
 		op, err = newColBatchScan(flowCtx, core.TableReader, post)
 		// We want to check for cancellation once per input batch, and wrapping
 		// only colBatchScan with an exec.CancelChecker allows us to do just that.
@@ -131,6 +135,10 @@ func newColOperator(
 			return nil, err
 		}
 		aggSpec := core.Aggregator
+		if len(aggSpec.GroupingSets) > 0 {
+			return nil, pgerror.Newf(pgerror.CodeDataExceptionError,
+				"grouping sets not supported")
+		}
 		if len(aggSpec.GroupCols) == 0 &&
 			len(aggSpec.Aggregations) == 1 &&
 			aggSpec.Aggregations[0].FilterColIdx == nil &&
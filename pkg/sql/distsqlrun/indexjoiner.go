@@ -99,6 +99,7 @@ func newIndexJoiner(
 	}
 	if _, _, err := initRowFetcher(
 		&ij.fetcher,
+		ij.evalCtx,
 		&ij.desc,
 		0, /* primary index */
 		ij.desc.ColumnIdxMapWithMutations(needMutations),
@@ -110,6 +111,7 @@ func newIndexJoiner(
 	); err != nil {
 		return nil, err
 	}
+	ij.fetcher.SetLocking(spec.LockingStrength, spec.LockingWaitPolicy)
 	ij.fetcherInput = &rowFetcherWrapper{Fetcher: &ij.fetcher}
 
 	if sp := opentracing.SpanFromContext(flowCtx.EvalCtx.Ctx()); sp != nil && tracing.IsRecording(sp) {
@@ -360,12 +360,6 @@ func newInterleavedReaderJoiner(
 		descendantJoinSide: descendantJoinSide,
 	}
 
-	if err := irj.initRowFetcher(
-		spec.Tables, spec.Reverse, &irj.alloc,
-	); err != nil {
-		return nil, err
-	}
-
 	irj.limitHint = limitHint(spec.LimitHint, post)
 
 	// TODO(richardwu): Generalize this to 2+ tables.
@@ -390,6 +384,12 @@ func newInterleavedReaderJoiner(
 		return nil, err
 	}
 
+	if err := irj.initRowFetcher(
+		spec.Tables, spec.Reverse, &irj.alloc,
+	); err != nil {
+		return nil, err
+	}
+
 	return irj, nil
 }
 
@@ -419,8 +419,9 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 		}
 	}
 
-	return irj.fetcher.Init(reverseScan, true /* returnRangeInfo */, true /* isCheck */, alloc,
-		args...)
+	return irj.fetcher.Init(
+		irj.evalCtx, reverseScan, true /* returnRangeInfo */, true /* isCheck */, alloc, args...,
+	)
 }
 
 func (irj *interleavedReaderJoiner) generateTrailingMeta(
@@ -194,13 +194,14 @@ func newJoinReader(
 	}
 
 	_, _, err = initRowFetcher(
-		&jr.fetcher, &jr.desc, int(spec.IndexIdx), jr.colIdxMap, false, /* reverse */
+		&jr.fetcher, jr.evalCtx, &jr.desc, int(spec.IndexIdx), jr.colIdxMap, false, /* reverse */
 		jr.neededRightCols(), false /* isCheck */, &jr.alloc,
 		distsqlpb.ScanVisibility_PUBLIC,
 	)
 	if err != nil {
 		return nil, err
 	}
+	jr.fetcher.SetLocking(spec.LockingStrength, spec.LockingWaitPolicy)
 	jr.fetcherInput = &rowFetcherWrapper{Fetcher: &jr.fetcher}
 	if collectingStats {
 		jr.input = NewInputStatCollector(jr.input)
@@ -0,0 +1,306 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package distsqlrun
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/diskmap"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// RecursiveCTEIterationFn runs an iteration of the recursive query of a
+// recursive CTE. The query reads the working table, which holds the rows
+// produced by the previous iteration (or by the initial query), and passes
+// each row it produces to emit.
+type RecursiveCTEIterationFn func(
+	ctx context.Context,
+	workingRows *rowcontainer.DiskBackedRowContainer,
+	emit func(ctx context.Context, row tree.Datums) error,
+) error
+
+// recursiveCTEProcessor implements a recursive CTE:
+//  1. The rows of the initial query (the input) are emitted and saved in the
+//     working table.
+//  2. So long as the working table is not empty, the recursive query is run
+//     against it; the rows it produces are emitted and become the working
+//     table of the next iteration.
+//
+// If deduplicate is set, rows that were already emitted are discarded (this
+// implements the UNION semantics, as opposed to UNION ALL). Both the working
+// table and the set of emitted rows spill to disk when they don't fit in
+// memory.
+//
+// The recursive query is planned anew for each iteration, so the processor is
+// a LocalProcessor that is always planned on the gateway.
+type recursiveCTEProcessor struct {
+	ProcessorBase
+
+	input       RowSource
+	iterate     RecursiveCTEIterationFn
+	deduplicate bool
+	types       []types.T
+
+	evalCtx           *tree.EvalContext
+	tempStorage       diskmap.Factory
+	diskMonitor       *mon.BytesMonitor
+	parentDiskMonitor *mon.BytesMonitor
+
+	// workingRows is the working table of the iteration that runs next, and
+	// nextRows holds the rows produced since then, which are emitted through
+	// iter once the initial query is done.
+	workingRows *rowcontainer.DiskBackedRowContainer
+	nextRows    *rowcontainer.DiskBackedRowContainer
+	iter        rowcontainer.RowIterator
+
+	// seen holds all the rows emitted so far; only used if deduplicate is set.
+	seen    rowcontainer.HashDiskBackedRowContainer
+	allCols []uint32
+
+	initialDone bool
+	scratchRow  sqlbase.EncDatumRow
+}
+
+var _ LocalProcessor = &recursiveCTEProcessor{}
+
+const recursiveCTEProcName = "recursive cte"
+
+// NewRecursiveCTEProcessor returns a LocalProcessor that runs a recursive CTE.
+// Its input is the initial query, and iterate runs the recursive query.
+func NewRecursiveCTEProcessor(
+	evalCtx *tree.EvalContext,
+	tempStorage diskmap.Factory,
+	diskMonitor *mon.BytesMonitor,
+	types []types.T,
+	deduplicate bool,
+	iterate RecursiveCTEIterationFn,
+) LocalProcessor {
+	return &recursiveCTEProcessor{
+		iterate:           iterate,
+		deduplicate:       deduplicate,
+		types:             types,
+		evalCtx:           evalCtx,
+		tempStorage:       tempStorage,
+		parentDiskMonitor: diskMonitor,
+	}
+}
+
+// InitWithOutput is part of the LocalProcessor interface.
+func (r *recursiveCTEProcessor) InitWithOutput(
+	post *distsqlpb.PostProcessSpec, output RowReceiver,
+) error {
+	ctx := r.evalCtx.Ctx()
+
+	// Limit the memory use by creating a child monitor with a hard limit. The
+	// working table and the set of emitted rows overflow to disk if this limit
+	// is not enough.
+	limitedMon := mon.MakeMonitorInheritWithLimit(
+		"recursive-cte-limited", settingWorkMemBytes.Get(&r.evalCtx.Settings.SV), r.evalCtx.Mon,
+	)
+	limitedMon.Start(ctx, r.evalCtx.Mon, mon.BoundAccount{})
+	if err := r.InitWithEvalCtx(
+		r, post, r.types, nil /* flowCtx */, r.evalCtx, 0 /* processorID */, output, &limitedMon,
+		ProcStateOpts{
+			TrailingMetaCallback: func(context.Context) []distsqlpb.ProducerMetadata {
+				r.close()
+				return nil
+			},
+		},
+	); err != nil {
+		limitedMon.Stop(ctx)
+		return err
+	}
+	r.diskMonitor = NewMonitor(ctx, r.parentDiskMonitor, "recursive-cte-disk")
+
+	r.workingRows = r.newRowContainer()
+	r.nextRows = r.newRowContainer()
+	if r.deduplicate {
+		r.seen = rowcontainer.MakeHashDiskBackedRowContainer(
+			nil /* mrc */, r.evalCtx, r.MemMonitor, r.diskMonitor, r.tempStorage,
+		)
+		r.allCols = make([]uint32, len(r.types))
+		for i := range r.allCols {
+			r.allCols[i] = uint32(i)
+		}
+		if err := r.seen.Init(
+			ctx, false /* shouldMark */, r.types, r.allCols, true, /* encodeNull */
+		); err != nil {
+			return err
+		}
+	}
+	r.scratchRow = make(sqlbase.EncDatumRow, len(r.types))
+	return nil
+}
+
+// SetInput is part of the LocalProcessor interface.
+func (r *recursiveCTEProcessor) SetInput(ctx context.Context, input RowSource) error {
+	r.input = input
+	r.AddInputToDrain(input)
+	return nil
+}
+
+func (r *recursiveCTEProcessor) newRowContainer() *rowcontainer.DiskBackedRowContainer {
+	rc := &rowcontainer.DiskBackedRowContainer{}
+	rc.Init(
+		nil, /* ordering */
+		r.types,
+		r.evalCtx,
+		r.tempStorage,
+		r.MemMonitor,
+		r.diskMonitor,
+		0, /* rowCapacity */
+	)
+	return rc
+}
+
+// Start is part of the RowSource interface.
+func (r *recursiveCTEProcessor) Start(ctx context.Context) context.Context {
+	r.input.Start(ctx)
+	return r.StartInternal(ctx, recursiveCTEProcName)
+}
+
+// Next is part of the RowSource interface.
+func (r *recursiveCTEProcessor) Next() (sqlbase.EncDatumRow, *distsqlpb.ProducerMetadata) {
+	for r.State == StateRunning {
+		if !r.initialDone {
+			row, meta := r.input.Next()
+			if meta != nil {
+				if meta.Err != nil {
+					r.MoveToDraining(nil /* err */)
+				}
+				return nil, meta
+			}
+			if row == nil {
+				r.initialDone = true
+				if err := r.nextIteration(); err != nil {
+					r.MoveToDraining(err)
+				}
+				continue
+			}
+			added, err := r.addRow(r.Ctx, row)
+			if err != nil {
+				r.MoveToDraining(err)
+				break
+			}
+			if !added {
+				continue
+			}
+			if outRow := r.ProcessRowHelper(row); outRow != nil {
+				return outRow, nil
+			}
+			continue
+		}
+
+		if ok, err := r.iter.Valid(); err != nil {
+			r.MoveToDraining(err)
+			break
+		} else if !ok {
+			if err := r.nextIteration(); err != nil {
+				r.MoveToDraining(err)
+			}
+			continue
+		}
+		row, err := r.iter.Row()
+		if err != nil {
+			r.MoveToDraining(err)
+			break
+		}
+		r.iter.Next()
+		if outRow := r.ProcessRowHelper(row); outRow != nil {
+			return outRow, nil
+		}
+	}
+	return nil, r.DrainHelper()
+}
+
+// nextIteration makes the rows produced since the last iteration the working
+// table, and runs the recursive query against it. The processor moves to
+// draining once the working table is empty.
+func (r *recursiveCTEProcessor) nextIteration() error {
+	if r.iter != nil {
+		r.iter.Close()
+		r.iter = nil
+	}
+	r.workingRows, r.nextRows = r.nextRows, r.workingRows
+	if err := r.nextRows.UnsafeReset(r.Ctx); err != nil {
+		return err
+	}
+	if r.workingRows.Len() == 0 {
+		r.MoveToDraining(nil /* err */)
+		return nil
+	}
+	if err := r.iterate(r.Ctx, r.workingRows, r.addIterationRow); err != nil {
+		return err
+	}
+	r.iter = r.nextRows.NewIterator(r.Ctx)
+	r.iter.Rewind()
+	return nil
+}
+
+// addIterationRow adds a row produced by the recursive query.
+func (r *recursiveCTEProcessor) addIterationRow(ctx context.Context, row tree.Datums) error {
+	for i := range row {
+		r.scratchRow[i] = sqlbase.DatumToEncDatum(&r.types[i], row[i])
+	}
+	_, err := r.addRow(ctx, r.scratchRow)
+	return err
+}
+
+// addRow adds a row to the rows produced since the last iteration, unless it
+// is a duplicate of a row that was already emitted. It returns whether the row
+// was added.
+func (r *recursiveCTEProcessor) addRow(ctx context.Context, row sqlbase.EncDatumRow) (bool, error) {
+	if r.deduplicate {
+		it, err := r.seen.NewBucketIterator(ctx, row, r.allCols)
+		if err != nil {
+			return false, err
+		}
+		it.Rewind()
+		seen, err := it.Valid()
+		it.Close()
+		if err != nil || seen {
+			return false, err
+		}
+		if err := r.seen.AddRow(ctx, row); err != nil {
+			return false, err
+		}
+	}
+	return true, r.nextRows.AddRow(ctx, row)
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (r *recursiveCTEProcessor) ConsumerClosed() {
+	// The consumer is done, Next() will not be called again.
+	r.close()
+}
+
+func (r *recursiveCTEProcessor) close() {
+	if r.InternalClose() {
+		ctx := r.Ctx
+		if r.iter != nil {
+			r.iter.Close()
+		}
+		r.workingRows.Close(ctx)
+		r.nextRows.Close(ctx)
+		if r.deduplicate {
+			r.seen.Close(ctx)
+		}
+		r.MemMonitor.Stop(ctx)
+		r.diskMonitor.Stop(ctx)
+	}
+}
@@ -124,8 +124,8 @@ func newScrubTableReader(
 	}
 
 	if _, _, err := initRowFetcher(
-		&tr.fetcher, &tr.tableDesc, int(spec.IndexIdx), tr.tableDesc.ColumnIdxMap(), spec.Reverse,
-		neededColumns, true /* isCheck */, &tr.alloc,
+		&tr.fetcher, tr.evalCtx, &tr.tableDesc, int(spec.IndexIdx), tr.tableDesc.ColumnIdxMap(),
+		spec.Reverse, neededColumns, true /* isCheck */, &tr.alloc,
 		distsqlpb.ScanVisibility_PUBLIC,
 	); err != nil {
 		return nil, err
@@ -435,6 +435,7 @@ func (z *zigzagJoiner) setupInfo(spec *distsqlpb.ZigzagJoinerSpec, side int, col
 	// Setup the Fetcher.
 	_, _, err := initRowFetcher(
 		&(info.fetcher),
+		z.evalCtx,
 		info.table,
 		int(info.index.ID)-1,
 		info.table.ColumnIdxMap(),
@@ -14,6 +14,7 @@ package sql
 
 import (
 	"context"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
@@ -30,6 +31,14 @@ type dropDatabaseNode struct {
 	n      *tree.DropDatabase
 	dbDesc *sqlbase.DatabaseDescriptor
 	td     []toDelete
+	types  []*sqlbase.TypeDescriptor
+	// functions are the overloads of the user-defined functions in the
+	// database.
+	functions []*sqlbase.FunctionDescriptor
+	// schemas are the user-defined schemas in the database.
+	schemas []*sqlbase.SchemaDescriptor
+	// tempSchemaNames are the names of the temporary schemas in the database.
+	tempSchemaNames []string
 }
 
 // DropDatabase drops a database.
@@ -68,7 +77,23 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	if len(tbNames) > 0 {
+	typeDescs, err := p.getTypesInDatabase(ctx, dbDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, tempSchemaNames, schemaTables, err := p.getSchemasInDatabase(ctx, dbDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	functions, err := p.getFunctionsInDatabase(ctx, dbDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tbNames) > 0 || len(typeDescs) > 0 || len(schemas) > 0 || len(schemaTables) > 0 ||
+		len(functions) > 0 {
 		switch n.DropBehavior {
 		case tree.DropRestrict:
 			return nil, pgerror.Newf(pgerror.CodeDependentObjectsStillExistError,
@@ -102,13 +127,168 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		}
 		td = append(td, toDelete{&tbNames[i], tbDesc})
 	}
+	td = append(td, schemaTables...)
 
 	td, err = p.filterCascadedTables(ctx, td)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td}, nil
+	// The types of the database can be dropped if they are only used by the
+	// tables of the database.
+	dropped := make(map[sqlbase.ID]struct{}, len(td))
+	for _, toDel := range td {
+		dropped[toDel.desc.ID] = struct{}{}
+	}
+	for _, typeDesc := range typeDescs {
+		if err := p.CheckPrivilege(ctx, typeDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		for _, id := range typeDesc.ReferencingDescriptorIDs {
+			if _, ok := dropped[id]; !ok {
+				return nil, pgerror.Newf(pgerror.CodeDependentObjectsStillExistError,
+					"cannot drop type %q because other objects depend on it", typeDesc.Name)
+			}
+		}
+	}
+
+	return &dropDatabaseNode{
+		n: n, dbDesc: dbDesc, td: td, types: typeDescs, functions: functions,
+		schemas: schemas, tempSchemaNames: tempSchemaNames,
+	}, nil
+}
+
+// getSchemasInDatabase returns the descriptors of the user-defined schemas in
+// the given database, the names of its temporary schemas, of all sessions,
+// and the tables in all these schemas. It checks that the user has the DROP
+// privilege on the user-defined schemas and on the tables.
+func (p *planner) getSchemasInDatabase(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor,
+) (schemas []*sqlbase.SchemaDescriptor, tempSchemaNames []string, td []toDelete, err error) {
+	entries, err := getNamespaceEntries(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var ids []sqlbase.ID
+	for scName, schemaID := range entries {
+		if isTemporarySchemaName(scName) {
+			tempSchemaNames = append(tempSchemaNames, scName)
+		} else {
+			ids = append(ids, schemaID)
+		}
+	}
+	descs, err := getDescriptorsByID(ctx, p.txn, ids)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, id := range ids {
+		if desc, ok := descs[id]; ok && desc.GetSchema() != nil {
+			schemas = append(schemas, desc.GetSchema())
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Name < schemas[j].Name })
+	sort.Strings(tempSchemaNames)
+
+	for _, scDesc := range schemas {
+		if err := p.CheckPrivilege(ctx, scDesc, privilege.DROP); err != nil {
+			return nil, nil, nil, err
+		}
+		tables, err := p.getTablesInSchema(ctx, dbDesc, scDesc.Name, scDesc.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		td = append(td, tables...)
+	}
+	for _, scName := range tempSchemaNames {
+		tables, err := p.getTablesInSchema(ctx, dbDesc, scName, entries[scName])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		td = append(td, tables...)
+	}
+	return schemas, tempSchemaNames, td, nil
+}
+
+// getTablesInSchema returns the tables, views and sequences in the schema
+// with the given name and ID, and checks that the user has the DROP privilege
+// on them. The tables are looked up by ID, since name resolution only gives
+// access to the current session's temporary schema.
+func (p *planner) getTablesInSchema(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor, scName string, schemaID sqlbase.ID,
+) ([]toDelete, error) {
+	tables, err := getNamespaceEntries(ctx, p.txn, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	tbNames := make([]string, 0, len(tables))
+	for tbName := range tables {
+		tbNames = append(tbNames, tbName)
+	}
+	sort.Strings(tbNames)
+	var td []toDelete
+	for _, tbName := range tbNames {
+		tbDesc, err := p.Tables().getMutableTableVersionByID(ctx, tables[tbName], p.txn)
+		if err != nil {
+			return nil, err
+		}
+		if tbDesc.Dropped() || tbDesc.Name != tbName {
+			// The name is draining.
+			continue
+		}
+		if err := p.CheckPrivilege(ctx, tbDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), tree.Name(tbName))
+		td = append(td, toDelete{&tn, tbDesc})
+	}
+	return td, nil
+}
+
+// getTypesInDatabase returns the descriptors of the user-defined types
+// in the given database.
+func (p *planner) getTypesInDatabase(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor,
+) ([]*sqlbase.TypeDescriptor, error) {
+	prefix := sqlbase.MakeNameMetadataKey(dbDesc.ID, "")
+	sr, err := p.txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]sqlbase.ID, len(sr))
+	for i, row := range sr {
+		ids[i] = sqlbase.ID(row.ValueInt())
+	}
+	typeDescsByID, err := getTypeDescriptorsByID(ctx, p.txn, ids)
+	if err != nil {
+		return nil, err
+	}
+	var typeDescs []*sqlbase.TypeDescriptor
+	for _, id := range ids {
+		if typeDesc, ok := typeDescsByID[id]; ok {
+			typeDescs = append(typeDescs, typeDesc)
+		}
+	}
+	return typeDescs, nil
+}
+
+// getFunctionsInDatabase returns the descriptors of the user-defined functions
+// in the given database, and checks that the user has the DROP privilege on
+// them.
+func (p *planner) getFunctionsInDatabase(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor,
+) ([]*sqlbase.FunctionDescriptor, error) {
+	functions := make([]*sqlbase.FunctionDescriptor, len(dbDesc.Functions))
+	for i := range dbDesc.Functions {
+		fnDesc, err := sqlbase.GetFunctionDescFromID(ctx, p.txn, dbDesc.Functions[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.CheckPrivilege(ctx, fnDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		functions[i] = fnDesc
+	}
+	return functions, nil
 }
 
 func (n *dropDatabaseNode) startExec(params runParams) error {
@@ -160,6 +340,14 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 		tbNameStrings = append(tbNameStrings, toDel.tn.FQString())
 	}
 
+	for _, typeDesc := range n.types {
+		if err := p.dropTypeImpl(ctx, typeDesc); err != nil {
+			return err
+		}
+		tn := tree.MakeTableName(tree.Name(n.dbDesc.Name), tree.Name(typeDesc.Name))
+		tbNameStrings = append(tbNameStrings, tn.FQString())
+	}
+
 	_ /* zoneKey */, nameKey, descKey := getKeysForDatabaseDescriptor(n.dbDesc)
 
 	b := &client.Batch{}
@@ -169,6 +357,25 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	}
 	b.Del(descKey)
 	b.Del(nameKey)
+	for _, fnDesc := range n.functions {
+		fnDescKey := sqlbase.MakeDescMetadataKey(fnDesc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", fnDescKey)
+		}
+		b.Del(fnDescKey)
+		tn := tree.MakeTableName(tree.Name(n.dbDesc.Name), tree.Name(fnDesc.Name))
+		tbNameStrings = append(tbNameStrings, tn.FQString())
+	}
+	for _, scDesc := range n.schemas {
+		p.deleteSchemaKeys(ctx, b, n.dbDesc.ID, scDesc)
+	}
+	for _, scName := range n.tempSchemaNames {
+		schemaKey := sqlbase.NewSchemaKey(n.dbDesc.ID, scName).Key()
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", schemaKey)
+		}
+		b.Del(schemaKey)
+	}
 
 	// No job was created because no tables were dropped, so zone config can be
 	// immediately removed.
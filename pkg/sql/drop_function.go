@@ -0,0 +1,146 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type functionToDelete struct {
+	tn   *tree.TableName
+	desc *sqlbase.FunctionDescriptor
+}
+
+type dropFunctionNode struct {
+	n  *tree.DropFunction
+	td []functionToDelete
+}
+
+// DropFunction drops user-defined functions. Dependencies on functions are
+// not tracked, so CASCADE and RESTRICT behave the same.
+// Privileges: DROP on function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	td := make([]functionToDelete, 0, len(n.Functions))
+	for i := range n.Functions {
+		fn := &n.Functions[i]
+		tn, descs, err := p.resolveFunctionDescriptors(ctx, fn.FuncName)
+		if err != nil {
+			return nil, err
+		}
+		if fn.HasParams {
+			paramTypes := make([]*types.T, len(fn.Params))
+			for j := range fn.Params {
+				if paramTypes[j], err = tree.ResolveType(fn.Params[j].Type, p); err != nil {
+					return nil, err
+				}
+			}
+			var matching []*sqlbase.FunctionDescriptor
+			for _, desc := range descs {
+				if sameParameterTypes(desc.ParameterTypes(), paramTypes) {
+					matching = append(matching, desc)
+				}
+			}
+			descs = matching
+		} else if len(descs) > 1 {
+			return nil, pgerror.Newf(pgerror.CodeAmbiguousFunctionError,
+				"function name %q is not unique", tn.Table()).SetHintf(
+				"Specify the argument list to select the function unambiguously.")
+		}
+		if len(descs) == 0 {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.Newf(pgerror.CodeUndefinedFunctionError,
+				"function %s does not exist", tree.ErrString(fn))
+		}
+		desc := descs[0]
+		if err := p.CheckPrivilege(ctx, desc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		dup := false
+		for _, other := range td {
+			dup = dup || other.desc.ID == desc.ID
+		}
+		if !dup {
+			td = append(td, functionToDelete{tn: tn, desc: desc})
+		}
+	}
+
+	if len(td) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	return &dropFunctionNode{n: n, td: td}, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+	for _, toDel := range n.td {
+		if err := p.dropFunctionImpl(ctx, toDel.desc); err != nil {
+			return err
+		}
+		// Log a Drop Function event. This is an auditable log event and is
+		// recorded in the same transaction as the function descriptor update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropFunction,
+			int32(toDel.desc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				FunctionName string
+				Statement    string
+				User         string
+			}{toDel.tn.FQString(), n.n.String(), params.SessionData().User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
+
+// dropFunctionImpl removes the descriptor of the given function, and the
+// reference to it from the descriptor of its database.
+func (p *planner) dropFunctionImpl(ctx context.Context, fnDesc *sqlbase.FunctionDescriptor) error {
+	dbDesc, err := MustGetDatabaseDescByID(ctx, p.txn, fnDesc.ParentID)
+	if err != nil {
+		return err
+	}
+	dbDesc.RemoveFunction(fnDesc.ID)
+
+	descKey := sqlbase.MakeDescMetadataKey(fnDesc.ID)
+	dbDescKey := sqlbase.MakeDescMetadataKey(dbDesc.ID)
+	dbDescVal := sqlbase.WrapDescriptor(dbDesc)
+
+	b := &client.Batch{}
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", descKey)
+		log.VEventf(ctx, 2, "Put %s -> %s", dbDescKey, dbDescVal)
+	}
+	b.Del(descKey)
+	b.Put(dbDescKey, dbDescVal)
+	return p.txn.Run(ctx, b)
+}
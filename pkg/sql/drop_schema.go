@@ -0,0 +1,190 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropSchemaNode struct {
+	n       *tree.DropSchema
+	dbDesc  *sqlbase.DatabaseDescriptor
+	schemas []*sqlbase.SchemaDescriptor
+	td      []toDelete
+}
+
+// DropSchema drops schemas of the current database.
+// Privileges: DROP on schema and DROP on all tables in the schema.
+//   Notes: postgres allows only the schema owner to DROP a schema.
+func (p *planner) DropSchema(ctx context.Context, n *tree.DropSchema) (planNode, error) {
+	dbName := p.CurrentDatabase()
+	if dbName == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []*sqlbase.SchemaDescriptor
+	var td []toDelete
+	for _, name := range n.Names {
+		scName := string(name)
+		if scName == tree.PublicSchema || isVirtualSchemaName(scName) || isTemporarySchemaRef(scName) {
+			return nil, pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+				"cannot drop schema %q", scName)
+		}
+		scDesc, err := getSchemaDescByName(ctx, p.txn, dbDesc.ID, scName)
+		if err != nil {
+			return nil, err
+		}
+		if scDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, sqlbase.NewUndefinedSchemaError(scName)
+		}
+
+		if err := p.CheckPrivilege(ctx, scDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+
+		tables, err := p.getTablesInSchema(ctx, dbDesc, scName, scDesc.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(tables) > 0 && n.DropBehavior != tree.DropCascade {
+			return nil, pgerror.Newf(pgerror.CodeDependentObjectsStillExistError,
+				"schema %q is not empty and CASCADE was not specified", scName)
+		}
+		for _, toDel := range tables {
+			// Recursively check permissions on all dependent views, since some may
+			// be in different schemas or databases.
+			for _, ref := range toDel.desc.DependedOnBy {
+				if err := p.canRemoveDependentView(ctx, toDel.desc, ref, tree.DropCascade); err != nil {
+					return nil, err
+				}
+			}
+		}
+		schemas = append(schemas, scDesc)
+		td = append(td, tables...)
+	}
+
+	td, err = p.filterCascadedTables(ctx, td)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dropSchemaNode{n: n, dbDesc: dbDesc, schemas: schemas, td: td}, nil
+}
+
+func (n *dropSchemaNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	droppedTableDetails := make([]jobspb.DroppedTableDetails, 0, len(n.td))
+	tableDescs := make([]*sqlbase.MutableTableDescriptor, 0, len(n.td))
+	for _, toDel := range n.td {
+		if toDel.desc.IsView() {
+			continue
+		}
+		droppedTableDetails = append(droppedTableDetails, jobspb.DroppedTableDetails{
+			Name: toDel.tn.FQString(),
+			ID:   toDel.desc.ID,
+		})
+		tableDescs = append(tableDescs, toDel.desc)
+	}
+	if len(tableDescs) > 0 {
+		if _, err := p.createDropTablesJob(
+			ctx,
+			tableDescs,
+			droppedTableDetails,
+			tree.AsStringWithFQNames(n.n, params.Ann()),
+			true, /* drainNames */
+			sqlbase.InvalidID /* droppedDatabaseID */); err != nil {
+			return err
+		}
+	}
+
+	droppedObjects := make([]string, 0, len(n.td))
+	for _, toDel := range n.td {
+		var cascadedViews []string
+		var err error
+		if toDel.desc.IsView() {
+			cascadedViews, err = p.dropViewImpl(ctx, toDel.desc, tree.DropCascade)
+		} else {
+			cascadedViews, err = p.dropTableImpl(params, toDel.desc)
+		}
+		if err != nil {
+			return err
+		}
+		droppedObjects = append(droppedObjects, cascadedViews...)
+		droppedObjects = append(droppedObjects, toDel.tn.FQString())
+	}
+
+	b := &client.Batch{}
+	for _, scDesc := range n.schemas {
+		p.deleteSchemaKeys(ctx, b, n.dbDesc.ID, scDesc)
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	// Log Drop Schema events. This is an auditable log event and is recorded
+	// in the same transaction as the schema descriptor update.
+	for _, scDesc := range n.schemas {
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropSchema,
+			int32(scDesc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				SchemaName           string
+				Statement            string
+				User                 string
+				DroppedSchemaObjects []string
+			}{scDesc.Name, n.n.String(), p.SessionData().User, droppedObjects},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropSchemaNode) Close(context.Context)        {}
+
+// deleteSchemaKeys adds to the batch the deletion of the namespace entry and
+// the descriptor of a user-defined schema of the database with ID dbID.
+func (p *planner) deleteSchemaKeys(
+	ctx context.Context, b *client.Batch, dbID sqlbase.ID, scDesc *sqlbase.SchemaDescriptor,
+) {
+	nameKey := sqlbase.NewSchemaKey(dbID, scDesc.Name).Key()
+	descKey := sqlbase.MakeDescMetadataKey(scDesc.ID)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", descKey)
+		log.VEventf(ctx, 2, "Del %s", nameKey)
+	}
+	b.Del(descKey)
+	b.Del(nameKey)
+}
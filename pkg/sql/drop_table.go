@@ -292,6 +292,13 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
+	// Remove back-references from the user-defined types used by the table.
+	if err := p.removeTypeBackReferences(
+		ctx, typeIDsReferencedByTable(tableDesc.TableDesc()), tableDesc.ID,
+	); err != nil {
+		return droppedViews, err
+	}
+
 	// Drop all views that depend on this table, assuming that we wouldn't have
 	// made it to this point if `cascade` wasn't enabled.
 	for _, ref := range tableDesc.DependedOnBy {
@@ -342,7 +349,7 @@ func (p *planner) initiateDropTable(
 	//
 	// TODO(bram): If interleaved and ON DELETE CASCADE, we will be
 	// able to use this faster mechanism.
-	if tableDesc.IsTable() && !tableDesc.IsInterleaved() &&
+	if (tableDesc.IsTable() || tableDesc.MaterializedView()) && !tableDesc.IsInterleaved() &&
 		p.ExecCfg().Settings.Version.IsActive(cluster.VersionClearRange) {
 		// Get the zone config applying to this table in order to
 		// ensure there is a GC TTL.
@@ -360,7 +367,7 @@ func (p *planner) initiateDropTable(
 	if drainName {
 		// Queue up name for draining.
 		nameDetails := sqlbase.TableDescriptor_NameInfo{
-			ParentID: tableDesc.ParentID,
+			ParentID: tableDesc.NamespaceParentID(),
 			Name:     tableDesc.Name}
 		tableDesc.DrainingNames = append(tableDesc.DrainingNames, nameDetails)
 	}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+	idx       int
+}
+
+// DropTrigger drops a trigger of a table. Nothing depends on triggers, so
+// CASCADE and RESTRICT behave the same.
+// Privileges: CREATE on table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	tableDesc, err := p.ResolveMutableTableDescriptor(ctx, &n.Table, !n.IfExists, ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	idx := -1
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.Name) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgerror.CodeUndefinedObjectError,
+			"trigger %q for table %q does not exist", n.Name, tableDesc.Name)
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &dropTriggerNode{n: n, tableDesc: tableDesc, idx: idx}, nil
+}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	triggers := n.tableDesc.Triggers
+	n.tableDesc.Triggers = append(triggers[:n.idx:n.idx], triggers[n.idx+1:]...)
+	if err := n.tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
+		return err
+	}
+	if err := p.writeSchemaChange(ctx, n.tableDesc, sqlbase.InvalidMutationID); err != nil {
+		return err
+	}
+
+	// Record this trigger removal in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogDropTrigger,
+		int32(n.tableDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TableName   string
+			TriggerName string
+			Statement   string
+			User        string
+		}{n.n.Table.FQString(), string(n.n.Name), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTriggerNode) Close(context.Context)        {}
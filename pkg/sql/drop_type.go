@@ -0,0 +1,167 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type typeToDelete struct {
+	tn   *tree.TableName
+	desc *sqlbase.TypeDescriptor
+}
+
+type dropTypeNode struct {
+	// n is the DROP TYPE or DROP DOMAIN statement.
+	n  tree.Statement
+	td []typeToDelete
+}
+
+// DropType drops user-defined types.
+// Privileges: DROP on type.
+//
+// DROP TYPE ... CASCADE is out of scope: in Postgres it also drops the columns
+// (and the domains) that use the type, which would require starting a schema
+// change on every referencing table. Users have to drop or alter these
+// columns first.
+func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	if n.DropBehavior == tree.DropCascade {
+		return nil, dropTypeCascadeUnimplemented("DROP TYPE")
+	}
+	return p.dropTypes(ctx, n, n.Names, n.IfExists, false /* domains */)
+}
+
+// DropDomain drops domains.
+// Privileges: DROP on type.
+func (p *planner) DropDomain(ctx context.Context, n *tree.DropDomain) (planNode, error) {
+	if n.DropBehavior == tree.DropCascade {
+		return nil, dropTypeCascadeUnimplemented("DROP DOMAIN")
+	}
+	return p.dropTypes(ctx, n, n.Names, n.IfExists, true /* domains */)
+}
+
+// dropTypes plans the removal of the given types for the DROP TYPE or DROP
+// DOMAIN statement n. Domains can only be dropped by DROP DOMAIN and the other
+// types only by DROP TYPE.
+func (p *planner) dropTypes(
+	ctx context.Context,
+	n tree.Statement,
+	names []*tree.UnresolvedObjectName,
+	ifExists bool,
+	domains bool,
+) (planNode, error) {
+	td := make([]typeToDelete, 0, len(names))
+	for _, name := range names {
+		tn, typeDesc, err := p.resolveTypeDescriptor(ctx, name, !ifExists)
+		if err != nil {
+			return nil, err
+		}
+		if typeDesc == nil {
+			// IfExists specified and descriptor does not exist.
+			continue
+		}
+		if isDomain := typeDesc.Domain != nil; isDomain && !domains {
+			return nil, pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+				"%q is a domain", tn.Table()).SetHintf("Use DROP DOMAIN to remove a domain.")
+		} else if !isDomain && domains {
+			return nil, pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+				"%q is not a domain", tn.Table())
+		}
+		if err := p.CheckPrivilege(ctx, typeDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		if err := typeDependencyError(tn, typeDesc); err != nil {
+			return nil, err
+		}
+		td = append(td, typeToDelete{tn: tn, desc: typeDesc})
+	}
+
+	if len(td) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	return &dropTypeNode{n: n, td: td}, nil
+}
+
+func (n *dropTypeNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+	for _, toDel := range n.td {
+		if err := p.dropTypeImpl(ctx, toDel.desc); err != nil {
+			return err
+		}
+		// Log a Drop Type event. This is an auditable log event and is recorded
+		// in the same transaction as the type descriptor update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropType,
+			int32(toDel.desc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				TypeName  string
+				Statement string
+				User      string
+			}{toDel.tn.FQString(), n.n.String(), params.SessionData().User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTypeNode) Close(context.Context)        {}
+
+// dropTypeCascadeUnimplemented returns the error reported for DROP TYPE and
+// DROP DOMAIN with CASCADE. See DropType.
+func dropTypeCascadeUnimplemented(stmt string) error {
+	return pgerror.Unimplementedf(strings.ToLower(stmt)+" cascade",
+		"%s CASCADE is not supported", stmt).SetHintf(
+		"Drop or alter the columns that use the type, then run %s without CASCADE.", stmt)
+}
+
+// typeDependencyError returns an error if the given type is still used by
+// some table.
+func typeDependencyError(tn *tree.TableName, typeDesc *sqlbase.TypeDescriptor) error {
+	if len(typeDesc.ReferencingDescriptorIDs) == 0 {
+		return nil
+	}
+	return pgerror.Newf(pgerror.CodeDependentObjectsStillExistError,
+		"cannot drop type %q because other objects depend on it", tn.Table())
+}
+
+// dropTypeImpl removes the name and the descriptor of the given type. Unlike
+// tables, types have no data, so they can be removed immediately.
+func (p *planner) dropTypeImpl(ctx context.Context, typeDesc *sqlbase.TypeDescriptor) error {
+	nameKey := sqlbase.NewTableKey(typeDesc.ParentID, typeDesc.Name).Key()
+	descKey := sqlbase.MakeDescMetadataKey(typeDesc.ID)
+
+	b := &client.Batch{}
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", descKey)
+		log.VEventf(ctx, 2, "Del %s", nameKey)
+	}
+	b.Del(descKey)
+	b.Del(nameKey)
+	return p.txn.Run(ctx, b)
+}
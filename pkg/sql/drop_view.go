@@ -45,6 +45,9 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 			// IfExists specified and the view did not exist.
 			continue
 		}
+		if err := checkViewMatchesMaterialized(droppedDesc, n.IsMaterialized); err != nil {
+			return nil, err
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
@@ -110,6 +113,22 @@ func (*dropViewNode) Next(runParams) (bool, error) { return false, nil }
 func (*dropViewNode) Values() tree.Datums          { return tree.Datums{} }
 func (*dropViewNode) Close(context.Context)        {}
 
+// checkViewMatchesMaterialized returns an error if a view is materialized and
+// the statement operating on it expects a regular view, or vice versa.
+func checkViewMatchesMaterialized(desc *sqlbase.MutableTableDescriptor, materialized bool) error {
+	if desc.MaterializedView() == materialized {
+		return nil
+	}
+	if desc.MaterializedView() {
+		pErr := pgerror.Newf(pgerror.CodeWrongObjectTypeError, "%q is a materialized view", desc.Name)
+		pErr.Hint = "use DROP MATERIALIZED VIEW to remove a materialized view"
+		return pErr
+	}
+	pErr := pgerror.Newf(pgerror.CodeWrongObjectTypeError, "%q is not a materialized view", desc.Name)
+	pErr.Hint = "use DROP VIEW to remove a view"
+	return pErr
+}
+
 func descInSlice(descID sqlbase.ID, td []toDelete) bool {
 	for _, toDel := range td {
 		if descID == toDel.desc.ID {
@@ -0,0 +1,95 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+// Package enum contains the logic for generating the physical representations
+// of the members of enum types.
+//
+// The physical representation of an enum member is a byte string. The byte
+// strings of the members of an enum are ordered in the same way as the members
+// themselves, so that values of an enum type can be encoded in keys as plain
+// bytes. New members can be added at any position without changing the
+// physical representations of the existing members: no generated byte string
+// ends with a zero byte, so there is always room for a byte string between
+// two existing ones.
+package enum
+
+import "bytes"
+
+// maxToken is the largest value of a byte in a physical representation.
+const maxToken = 255
+
+// GenerateNEvenlySpacedBytes returns n byte strings that are evenly spread
+// across the space of byte strings of the smallest length that can hold them.
+// The returned byte strings are sorted in increasing order and do not contain
+// any zero bytes.
+func GenerateNEvenlySpacedBytes(n int) [][]byte {
+	if n == 0 {
+		return nil
+	}
+	// Every byte of the generated byte strings is in the range [1, 255], so
+	// byte strings of length l can be seen as the numbers in [0, 255^l)
+	// written in base 255.
+	length, space := 1, uint64(maxToken)
+	for space <= uint64(n) {
+		length++
+		space *= maxToken
+	}
+	step := space / uint64(n+1)
+	result := make([][]byte, n)
+	for i := range result {
+		result[i] = encodeBase255(step*uint64(i+1), length)
+	}
+	return result
+}
+
+// encodeBase255 encodes v as a byte string of the given length, where every
+// byte is a base 255 digit offset by one.
+func encodeBase255(v uint64, length int) []byte {
+	result := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		result[i] = byte(v%maxToken) + 1
+		v /= maxToken
+	}
+	return result
+}
+
+// GenByteStringBetween returns a byte string that sorts strictly between prev
+// and next. A nil prev means that there is no lower bound, and a nil next that
+// there is no upper bound. prev must sort before next, and neither of them may
+// end with a zero byte. The returned byte string does not end with a zero
+// byte either.
+func GenByteStringBetween(prev []byte, next []byte) []byte {
+	if prev != nil && next != nil && bytes.Compare(prev, next) >= 0 {
+		panic("prev must sort before next")
+	}
+	var result []byte
+	// bounded is true while the generated prefix is equal to the prefix of
+	// next; past that point, there is no upper bound on the remaining bytes.
+	bounded := next != nil
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(prev) {
+			lo = int(prev[i])
+		}
+		hi := maxToken + 1
+		if bounded {
+			hi = int(next[i])
+		}
+		if hi-lo > 1 {
+			return append(result, byte((lo+hi)/2))
+		}
+		result = append(result, byte(lo))
+		if hi > lo {
+			bounded = false
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package enum
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func checkValid(t *testing.T, b []byte) {
+	t.Helper()
+	if len(b) == 0 {
+		t.Fatal("empty byte string")
+	}
+	if b[len(b)-1] == 0 {
+		t.Fatalf("byte string %v ends with a zero byte", b)
+	}
+}
+
+func TestGenerateNEvenlySpacedBytes(t *testing.T) {
+	for _, tc := range []struct {
+		n      int
+		length int
+	}{
+		{n: 0},
+		{n: 1, length: 1},
+		{n: 10, length: 1},
+		{n: 254, length: 1},
+		{n: 255, length: 2},
+		{n: 1000, length: 2},
+		{n: 70000, length: 3},
+	} {
+		res := GenerateNEvenlySpacedBytes(tc.n)
+		if len(res) != tc.n {
+			t.Fatalf("n=%d: expected %d byte strings, got %d", tc.n, tc.n, len(res))
+		}
+		for i, b := range res {
+			checkValid(t, b)
+			if len(b) != tc.length {
+				t.Fatalf("n=%d: expected byte strings of length %d, got %v", tc.n, tc.length, b)
+			}
+			if i > 0 && bytes.Compare(res[i-1], b) >= 0 {
+				t.Fatalf("n=%d: byte strings not sorted: %v >= %v", tc.n, res[i-1], b)
+			}
+		}
+	}
+}
+
+func TestGenByteStringBetween(t *testing.T) {
+	for _, tc := range []struct {
+		prev, next []byte
+		expected   []byte
+	}{
+		{prev: nil, next: nil, expected: []byte{128}},
+		{prev: []byte{128}, next: nil, expected: []byte{192}},
+		{prev: nil, next: []byte{128}, expected: []byte{64}},
+		{prev: []byte{1}, next: []byte{2}, expected: []byte{1, 128}},
+		{prev: nil, next: []byte{1}, expected: []byte{0, 128}},
+		{prev: []byte{255}, next: nil, expected: []byte{255, 128}},
+		{prev: []byte{1, 255}, next: []byte{2}, expected: []byte{1, 255, 128}},
+		{prev: []byte{1}, next: []byte{1, 1}, expected: []byte{1, 0, 128}},
+	} {
+		res := GenByteStringBetween(tc.prev, tc.next)
+		if !bytes.Equal(res, tc.expected) {
+			t.Errorf("between %v and %v: expected %v, got %v", tc.prev, tc.next, tc.expected, res)
+		}
+	}
+}
+
+// TestGenByteStringBetweenRandom repeatedly inserts new byte strings at random
+// positions of a sorted list and checks that the list remains sorted.
+func TestGenByteStringBetweenRandom(t *testing.T) {
+	rng, _ := randutil.NewPseudoRand()
+	reps := GenerateNEvenlySpacedBytes(3)
+	for i := 0; i < 1000; i++ {
+		pos := rng.Intn(len(reps) + 1)
+		var prev, next []byte
+		if pos > 0 {
+			prev = reps[pos-1]
+		}
+		if pos < len(reps) {
+			next = reps[pos]
+		}
+		b := GenByteStringBetween(prev, next)
+		checkValid(t, b)
+		if (prev != nil && bytes.Compare(prev, b) >= 0) || (next != nil && bytes.Compare(b, next) >= 0) {
+			t.Fatalf("%v is not between %v and %v", b, prev, next)
+		}
+		reps = append(reps, nil)
+		copy(reps[pos+1:], reps[pos:])
+		reps[pos] = b
+	}
+}
@@ -58,6 +58,9 @@ const (
 	EventLogCreateView EventLogType = "create_view"
 	// EventLogDropView is recorded when a view is dropped.
 	EventLogDropView EventLogType = "drop_view"
+	// EventLogRefreshMaterializedView is recorded when a materialized view is
+	// refreshed.
+	EventLogRefreshMaterializedView EventLogType = "refresh_materialized_view"
 
 	// EventLogCreateSequence is recorded when a sequence is created.
 	EventLogCreateSequence EventLogType = "create_sequence"
@@ -66,6 +69,29 @@ const (
 	// EventLogAlterSequence is recorded when a sequence is altered.
 	EventLogAlterSequence EventLogType = "alter_sequence"
 
+	// EventLogCreateType is recorded when a type is created.
+	EventLogCreateType EventLogType = "create_type"
+	// EventLogDropType is recorded when a type is dropped.
+	EventLogDropType EventLogType = "drop_type"
+	// EventLogAlterType is recorded when a type is altered.
+	EventLogAlterType EventLogType = "alter_type"
+
+	// EventLogCreateSchema is recorded when a schema is created.
+	EventLogCreateSchema EventLogType = "create_schema"
+	// EventLogDropSchema is recorded when a schema is dropped.
+	EventLogDropSchema EventLogType = "drop_schema"
+
+	// EventLogCreateFunction is recorded when a function is created or
+	// replaced.
+	EventLogCreateFunction EventLogType = "create_function"
+	// EventLogDropFunction is recorded when a function is dropped.
+	EventLogDropFunction EventLogType = "drop_function"
+
+	// EventLogCreateTrigger is recorded when a trigger is created.
+	EventLogCreateTrigger EventLogType = "create_trigger"
+	// EventLogDropTrigger is recorded when a trigger is dropped.
+	EventLogDropTrigger EventLogType = "drop_trigger"
+
 	// EventLogReverseSchemaChange is recorded when an in-progress schema change
 	// encounters a problem and is reversed.
 	EventLogReverseSchemaChange EventLogType = "reverse_schema_change"
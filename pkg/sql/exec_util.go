@@ -778,23 +778,17 @@ func checkResultType(typ *types.T) error {
 	case types.DateFamily:
 	case types.TimestampFamily:
 	case types.TimeFamily:
+	case types.TimeTZFamily:
 	case types.TimestampTZFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
+	case types.GeometryFamily:
+	case types.GeographyFamily:
 	case types.OidFamily:
 	case types.TupleFamily:
 	case types.ArrayFamily:
-		if typ.ArrayContents().Family() == types.ArrayFamily {
-			// Technically we could probably return arrays of arrays to a
-			// client (the encoding exists) but we don't want to give
-			// mixed signals -- that nested arrays appear to be supported
-			// in this case, and not in other cases (eg. CREATE). So we
-			// reject them in every case instead.
-			return pgerror.UnimplementedWithIssueDetail(32552,
-				"result", "arrays cannot have arrays as element type")
-		}
 	case types.AnyFamily:
 		// Placeholder case.
 		return errors.Errorf("could not determine data type of %s", typ)
@@ -871,12 +865,6 @@ func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
 	return &ts, err
 }
 
-// isSavepoint returns true if stmt is a SAVEPOINT statement.
-func isSavepoint(stmt Statement) bool {
-	_, isSavepoint := stmt.AST.(*tree.Savepoint)
-	return isSavepoint
-}
-
 // isSetTransaction returns true if stmt is a "SET TRANSACTION ..." statement.
 func isSetTransaction(stmt Statement) bool {
 	_, isSet := stmt.AST.(*tree.SetTransaction)
@@ -1060,15 +1048,21 @@ func newSchemaInterface(tables *TableCollection, vt VirtualTabler) *schemaInterf
 const MaxSQLBytes = 1000
 
 type schemaChangerCollection struct {
-	schemaChangers []SchemaChanger
+	schemaChangers     []SchemaChanger
+	typeSchemaChangers []typeSchemaChanger
 }
 
 func (scc *schemaChangerCollection) queueSchemaChanger(schemaChanger SchemaChanger) {
 	scc.schemaChangers = append(scc.schemaChangers, schemaChanger)
 }
 
+func (scc *schemaChangerCollection) queueTypeSchemaChanger(schemaChanger typeSchemaChanger) {
+	scc.typeSchemaChangers = append(scc.typeSchemaChangers, schemaChanger)
+}
+
 func (scc *schemaChangerCollection) reset() {
 	scc.schemaChangers = nil
+	scc.typeSchemaChangers = nil
 }
 
 // execSchemaChanges releases schema leases and runs the queued
@@ -1082,7 +1076,7 @@ func (scc *schemaChangerCollection) execSchemaChanges(
 	tracing *SessionTracing,
 	ieFactory sqlutil.SessionBoundInternalExecutorFactory,
 ) error {
-	if len(scc.schemaChangers) == 0 {
+	if len(scc.schemaChangers) == 0 && len(scc.typeSchemaChangers) == 0 {
 		return nil
 	}
 	if fn := cfg.SchemaChangerTestingKnobs.SyncFilter; fn != nil {
@@ -1131,6 +1125,23 @@ func (scc *schemaChangerCollection) execSchemaChanges(
 		}
 	}
 	scc.schemaChangers = nil
+
+	// Type schema changes run after the table schema changes, since they wait
+	// for the new versions of the tables written by the same statements.
+	for _, sc := range scc.typeSchemaChangers {
+		if err := sc.exec(ctx); err != nil {
+			if err == sqlbase.ErrDescriptorNotFound || err == ctx.Err() {
+				// The type was dropped, or the SchemaChangeManager will complete the
+				// schema change.
+				continue
+			}
+			log.Warningf(ctx, "error executing type schema change: %s", err)
+			if firstError == nil {
+				firstError = err
+			}
+		}
+	}
+	scc.typeSchemaChangers = nil
 	return firstError
 }
 
@@ -1815,7 +1826,13 @@ func (m *sessionDataMutator) SetSafeUpdates(val bool) {
 }
 
 func (m *sessionDataMutator) SetSearchPath(val sessiondata.SearchPath) {
-	m.data.SearchPath = val
+	m.data.SearchPath = val.WithTemporarySchemaName(m.data.SearchPath.GetTemporarySchemaName())
+}
+
+// SetTemporarySchemaName records the name of the session's temporary schema,
+// which is searched first when resolving names.
+func (m *sessionDataMutator) SetTemporarySchemaName(scName string) {
+	m.data.SearchPath = m.data.SearchPath.WithTemporarySchemaName(scName)
 }
 
 func (m *sessionDataMutator) SetLocation(loc *time.Location) {
@@ -355,10 +355,12 @@ func doExpandPlan(
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnColumnNode:
 	case *commentOnDatabaseNode:
 	case *commentOnTableNode:
+	case *refreshMatViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -370,12 +372,21 @@ func doExpandPlan(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createFunctionNode:
+	case *createTriggerNode:
+	case *createSchemaNode:
+	case *createDomainNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
+	case *dropTriggerNode:
+	case *dropSchemaNode:
+	case *dropTypeNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -877,10 +888,12 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnColumnNode:
 	case *commentOnDatabaseNode:
 	case *commentOnTableNode:
+	case *refreshMatViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -892,12 +905,21 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createFunctionNode:
+	case *createTriggerNode:
+	case *createSchemaNode:
+	case *createDomainNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
+	case *dropTriggerNode:
+	case *dropSchemaNode:
+	case *dropTypeNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -109,6 +109,13 @@ func (p *planner) changePrivileges(
 			descKey := sqlbase.MakeDescMetadataKey(descriptor.GetID())
 			b.Put(descKey, sqlbase.WrapDescriptor(descriptor))
 
+		case *sqlbase.SchemaDescriptor:
+			if err := d.Validate(); err != nil {
+				return nil, err
+			}
+			descKey := sqlbase.MakeDescMetadataKey(descriptor.GetID())
+			b.Put(descKey, sqlbase.WrapDescriptor(descriptor))
+
 		case *sqlbase.MutableTableDescriptor:
 			if !d.Dropped() {
 				if err := p.writeSchemaChangeToBatch(
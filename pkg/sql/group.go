@@ -56,6 +56,13 @@ type groupNode struct {
 	// even if there are no input rows, e.g. SELECT MIN(x) FROM t.
 	isScalar bool
 
+	// groupingSets, if set, contains the indices in the source plan of the
+	// columns of each grouping set; each set is a subset of groupCols. The
+	// aggregation is performed separately for each set and the last column
+	// contains the index of the set which produced the row. Only supported
+	// by the optimizer.
+	groupingSets [][]int
+
 	// funcs are the aggregation functions that the renders use.
 	funcs []*aggregateFuncHolder
 
@@ -306,6 +313,58 @@ func (p *planner) groupBy(
 	return plan, group, nil
 }
 
+// checkNoGroupingSets returns an error if the given SELECT clause uses
+// GROUPING SETS, ROLLUP, CUBE or the GROUPING function, which are only
+// supported by the optimizer. The heuristic planner is used when the
+// optimizer is disabled, or when it falls back on a query the optimizer
+// cannot plan.
+func checkNoGroupingSets(n *tree.SelectClause, orderBy tree.OrderBy) error {
+	for _, expr := range n.GroupBy {
+		if t, ok := expr.(*tree.GroupingSets); ok {
+			return groupingSetsRequireOptimizerError(t.Type.String())
+		}
+	}
+	var v groupingExprVisitor
+	for _, expr := range n.Exprs {
+		tree.WalkExprConst(&v, expr.Expr)
+	}
+	if n.Having != nil {
+		tree.WalkExprConst(&v, n.Having.Expr)
+	}
+	for _, o := range orderBy {
+		tree.WalkExprConst(&v, o.Expr)
+	}
+	if v.found {
+		return groupingSetsRequireOptimizerError("GROUPING")
+	}
+	return nil
+}
+
+func groupingSetsRequireOptimizerError(feature string) error {
+	return pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+		"%s requires the cost-based optimizer", feature,
+	).SetHintf("Run SET optimizer = on. If the optimizer is already on, another part " +
+		"of the query is not supported by it; rewrite the query using UNION ALL of " +
+		"separate GROUP BY queries instead.")
+}
+
+// groupingExprVisitor checks whether an expression contains a GROUPING
+// function call.
+type groupingExprVisitor struct {
+	found bool
+}
+
+var _ tree.Visitor = &groupingExprVisitor{}
+
+func (v *groupingExprVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if _, ok := expr.(*tree.GroupingExpr); ok {
+		v.found = true
+	}
+	return !v.found, expr
+}
+
+func (*groupingExprVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
 func (n *groupNode) startExec(params runParams) error {
 	panic("groupNode cannot be run in local mode")
 }
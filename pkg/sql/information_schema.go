@@ -922,8 +922,15 @@ CREATE TABLE information_schema.schema_privileges (
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		return forEachDatabaseDesc(ctx, p, dbContext, func(db *sqlbase.DatabaseDescriptor) error {
+			userSchemas, err := p.getUserSchemaDescs(ctx, db.ID)
+			if err != nil {
+				return err
+			}
 			return forEachSchemaName(ctx, p, db, func(scName string) error {
 				privs := db.Privileges.Show()
+				if scDesc, ok := userSchemas[scName]; ok {
+					privs = scDesc.Privileges.Show()
+				}
 				dbNameStr := tree.NewDString(db.Name)
 				scNameStr := tree.NewDString(scName)
 				// TODO(knz): This should filter for the current user, see
@@ -1249,6 +1256,9 @@ var (
 	tableTypeSystemView = tree.NewDString("SYSTEM VIEW")
 	tableTypeBaseTable  = tree.NewDString("BASE TABLE")
 	tableTypeView       = tree.NewDString("VIEW")
+	// tableTypeMaterializedView is not part of the SQL standard; postgres
+	// does not list materialized views in information_schema at all.
+	tableTypeMaterializedView = tree.NewDString("MATERIALIZED VIEW")
 )
 
 var informationSchemaTablesTable = virtualSchemaTable{
@@ -1267,6 +1277,9 @@ https://www.postgresql.org/docs/9.5/infoschema-tables.html`,
 				if table.IsVirtualTable() {
 					tableType = tableTypeSystemView
 					insertable = noString
+				} else if table.MaterializedView() {
+					tableType = tableTypeMaterializedView
+					insertable = noString
 				} else if table.IsView() {
 					tableType = tableTypeView
 					insertable = noString
@@ -1308,7 +1321,7 @@ CREATE TABLE information_schema.views (
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual schemas have no views */
 			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
-				if !table.IsView() {
+				if !table.IsView() || table.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -1344,6 +1357,22 @@ func forEachSchemaName(
 	for _, schema := range p.getVirtualTabler().getEntries() {
 		scNames = append(scNames, schema.desc.Name)
 	}
+	// Handle user-defined schemas.
+	userSchemas, err := p.getUserSchemaDescs(ctx, db.ID)
+	if err != nil {
+		return err
+	}
+	for scName := range userSchemas {
+		scNames = append(scNames, scName)
+	}
+	// Handle temporary schemas.
+	tempSchemaNames, err := getTemporarySchemaNames(ctx, p.txn, db.ID)
+	if err != nil {
+		return err
+	}
+	for _, scName := range tempSchemaNames {
+		scNames = append(scNames, scName)
+	}
 	sort.Strings(scNames)
 	for _, sc := range scNames {
 		if err := fn(sc); err != nil {
@@ -1385,6 +1414,29 @@ func forEachDatabaseDesc(
 	return nil
 }
 
+// forEachTypeDesc retrieves all the user-defined type descriptors of the
+// databases visible in the given context, and calls fn with each of them
+// and its database.
+func forEachTypeDesc(
+	ctx context.Context,
+	p *planner,
+	dbContext *DatabaseDescriptor,
+	fn func(*sqlbase.DatabaseDescriptor, *sqlbase.TypeDescriptor) error,
+) error {
+	return forEachDatabaseDesc(ctx, p, dbContext, func(db *sqlbase.DatabaseDescriptor) error {
+		typeDescs, err := p.getTypesInDatabase(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, typeDesc := range typeDescs {
+			if err := fn(db, typeDesc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // forEachTableDesc retrieves all table descriptors from the current
 // database and all system databases and iterates through them. For
 // each table, the function will call fn with its respective database
@@ -1518,13 +1570,42 @@ func forEachTableDescWithTableLookupInternal(
 	}
 
 	// Physical descriptors next.
+	// tempSchemaNames maps the IDs of temporary schemas to their names. It is
+	// populated on demand for the databases that contain temporary tables.
+	tempSchemaNames := make(map[sqlbase.ID]string)
+	scannedDBs := make(map[sqlbase.ID]struct{})
 	for _, tbID := range lCtx.tbIDs {
 		table := lCtx.tbDescs[tbID]
 		dbDesc, parentExists := lCtx.dbDescs[table.GetParentID()]
 		if table.Dropped() || !userCanSeeTable(ctx, p, table, allowAdding) || !parentExists {
 			continue
 		}
-		if err := fn(dbDesc, tree.PublicSchema, table, lCtx); err != nil {
+		scName := tree.PublicSchema
+		if table.UnexposedParentSchemaID != sqlbase.InvalidID && !table.Temporary {
+			scDesc, ok := lCtx.scDescs[table.UnexposedParentSchemaID]
+			if !ok {
+				// The schema is being dropped.
+				continue
+			}
+			scName = scDesc.Name
+		} else if table.Temporary {
+			if _, ok := scannedDBs[dbDesc.ID]; !ok {
+				names, err := getTemporarySchemaNames(ctx, p.txn, dbDesc.ID)
+				if err != nil {
+					return err
+				}
+				for id, name := range names {
+					tempSchemaNames[id] = name
+				}
+				scannedDBs[dbDesc.ID] = struct{}{}
+			}
+			var ok bool
+			if scName, ok = tempSchemaNames[table.UnexposedParentSchemaID]; !ok {
+				// The temporary schema is being dropped.
+				continue
+			}
+		}
+		if err := fn(dbDesc, scName, table, lCtx); err != nil {
 			return err
 		}
 	}
@@ -270,7 +270,7 @@ func (p *planner) Insert(
 
 	// Create the table insert, which does the bulk of the work.
 	ri, err := row.MakeInserter(p.txn, desc, fkTables, insertCols,
-		row.CheckFKs, &p.alloc)
+		row.CheckFKs, p.EvalContext(), &p.alloc)
 	if err != nil {
 		return nil, err
 	}
@@ -430,7 +430,12 @@ func (n *insertNode) startExec(params runParams) error {
 		}
 	}
 
-	return n.run.ti.init(params.p.txn, params.EvalContext())
+	if err := n.run.ti.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	return n.run.ti.triggers.init(
+		params.ctx, params.p.txn, params.EvalContext(), n.run.ti.tableDesc(), tree.TriggerInsert,
+	)
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -479,8 +484,6 @@ func (n *insertNode) BatchedNext(params runParams) (bool, error) {
 			return false, err
 		}
 
-		n.run.rowCount++
-
 		// Are we done yet with the current batch?
 		if n.run.ti.curBatchSize() >= maxInsertBatchSize {
 			break
@@ -535,6 +538,11 @@ func (n *insertNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		return err
 	}
 
+	// Fire the BEFORE triggers, if any. They can modify the row, or skip it.
+	if skip, err := n.run.ti.fireBeforeTriggers(params.ctx, rowVals); skip || err != nil {
+		return err
+	}
+
 	// Run the CHECK constraints, if any. CheckHelper will either evaluate the
 	// constraints itself, or else inspect boolean columns from the input that
 	// contain the results of evaluation.
@@ -560,6 +568,7 @@ func (n *insertNode) processSourceRow(params runParams, sourceVals tree.Datums)
 	if err = n.run.ti.row(params.ctx, rowVals, n.run.traceKV); err != nil {
 		return err
 	}
+	n.run.rowCount++
 
 	// If result rows need to be accumulated, do it.
 	if n.run.rows != nil {
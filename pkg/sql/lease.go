@@ -1228,7 +1228,7 @@ func (c *tableNameCache) get(
 	if !nameMatchesTable(&table.ImmutableTableDescriptor, dbID, tableName) {
 		panic(fmt.Sprintf("Out of sync entry in the name cache. "+
 			"Cache entry: %d.%q -> %d. Lease: %d.%q.",
-			dbID, tableName, table.ID, table.ParentID, table.Name))
+			dbID, tableName, table.ID, table.NamespaceParentID(), table.Name))
 	}
 
 	// Expired table. Don't hand it out.
@@ -1244,7 +1244,7 @@ func (c *tableNameCache) insert(table *tableVersionState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := makeTableNameCacheKey(table.ParentID, table.Name)
+	key := makeTableNameCacheKey(table.NamespaceParentID(), table.Name)
 	existing, ok := c.tables[key]
 	if !ok {
 		c.tables[key] = table
@@ -1264,7 +1264,7 @@ func (c *tableNameCache) remove(table *tableVersionState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := makeTableNameCacheKey(table.ParentID, table.Name)
+	key := makeTableNameCacheKey(table.NamespaceParentID(), table.Name)
 	existing, ok := c.tables[key]
 	if !ok {
 		// Table for lease not found in table name cache. This can happen if we had
@@ -1367,7 +1367,7 @@ func (m *LeaseManager) SetInternalExecutor(executor sqlutil.InternalExecutor) {
 func nameMatchesTable(
 	table *sqlbase.ImmutableTableDescriptor, dbID sqlbase.ID, tableName string,
 ) bool {
-	return table.ParentID == dbID && table.Name == tableName
+	return table.NamespaceParentID() == dbID && table.Name == tableName
 }
 
 // findNewest returns the newest table version state for the tableID.
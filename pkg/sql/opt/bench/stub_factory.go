@@ -41,6 +41,7 @@ func (f *stubFactory) ConstructScan(
 	reverse bool,
 	maxResults uint64,
 	reqOrdering exec.OutputOrdering,
+	locking *tree.LockingItem,
 ) (exec.Node, error) {
 	return struct{}{}, nil
 }
@@ -116,6 +117,15 @@ func (f *stubFactory) ConstructScalarGroupBy(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructGroupingSets(
+	input exec.Node,
+	groupCols []exec.ColumnOrdinal,
+	groupingSets []exec.ColumnOrdinalSet,
+	aggregations []exec.AggInfo,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) ConstructDistinct(
 	input exec.Node, distinctCols, orderedCols exec.ColumnOrdinalSet,
 ) (exec.Node, error) {
@@ -139,7 +149,11 @@ func (f *stubFactory) ConstructOrdinality(input exec.Node, colName string) (exec
 }
 
 func (f *stubFactory) ConstructIndexJoin(
-	input exec.Node, table cat.Table, cols exec.ColumnOrdinalSet, reqOrdering exec.OutputOrdering,
+	input exec.Node,
+	table cat.Table,
+	cols exec.ColumnOrdinalSet,
+	reqOrdering exec.OutputOrdering,
+	locking *tree.LockingItem,
 ) (exec.Node, error) {
 	return struct{}{}, nil
 }
@@ -153,6 +167,7 @@ func (f *stubFactory) ConstructLookupJoin(
 	lookupCols exec.ColumnOrdinalSet,
 	onCond tree.TypedExpr,
 	reqOrdering exec.OutputOrdering,
+	locking *tree.LockingItem,
 ) (exec.Node, error) {
 	return struct{}{}, nil
 }
@@ -193,6 +208,16 @@ func (f *stubFactory) ConstructWindow(n exec.Node, wi exec.WindowInfo) (exec.Nod
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructRecursiveCTE(
+	initial exec.Node, fn exec.RecursiveCTEIterationFn, label string, deduplicate bool,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
+func (f *stubFactory) ConstructScanBuffer(ref exec.Node, label string) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) RenameColumns(input exec.Node, colNames []string) (exec.Node, error) {
 	return struct{}{}, nil
 }
@@ -46,6 +46,12 @@ type Index interface {
 	// IsInverted returns true if this is a JSON inverted index.
 	IsInverted() bool
 
+	// Predicate returns the serialized boolean expression of a partial index,
+	// along with true if the index is partial. A partial index only contains
+	// entries for the rows of the table for which the predicate evaluates to
+	// true.
+	Predicate() (string, bool)
+
 	// ColumnCount returns the number of columns in the index. This includes
 	// columns that were part of the index definition (including the STORING
 	// clause), as well as implicitly added primary key columns.
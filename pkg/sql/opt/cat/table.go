@@ -43,6 +43,16 @@ type Table interface {
 	// information_schema tables.
 	IsVirtualTable() bool
 
+	// IsMaterializedView returns true if this table stores the results of a
+	// materialized view. Such tables can only be modified by REFRESH
+	// MATERIALIZED VIEW.
+	IsMaterializedView() bool
+
+	// HasTriggers returns true if triggers are defined on this table.
+	// Mutations of such tables need the values of all the columns of the
+	// affected rows, and cannot be planned as blind writes.
+	HasTriggers() bool
+
 	// IsInterleaved returns true if any of this table's indexes are interleaved
 	// with index(es) from other table(s).
 	IsInterleaved() bool
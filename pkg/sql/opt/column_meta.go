@@ -101,6 +101,23 @@ func ColSetToList(set ColSet) ColList {
 	return res
 }
 
+// ColSetList is a list of column id sets.
+type ColSetList []ColSet
+
+// Equals returns true if this list has the same column sets as the given
+// list, in the same order.
+func (l ColSetList) Equals(other ColSetList) bool {
+	if len(l) != len(other) {
+		return false
+	}
+	for i := range l {
+		if !l[i].Equals(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // ColMap provides a 1:1 mapping from one column id to another. It is used by
 // operators that need to match columns from its inputs.
 type ColMap = util.FastIntMap
@@ -47,6 +47,17 @@ type Builder struct {
 	// each relational subexpression when evalCtx.SessionData.SaveTablesPrefix is
 	// non-empty.
 	nameGen *memo.ExprNameGenerator
+
+	// workTables contains the buffers that WorkTable operators refer to. It is
+	// populated when building the recursive side of a RecursiveCTE.
+	workTables []workTableBinding
+}
+
+// workTableBinding associates the working table of a RecursiveCTE (identified
+// by its WithID) with the exec.Node that holds its rows.
+type workTableBinding struct {
+	id        opt.WithID
+	bufferRef exec.Node
 }
 
 // New constructs an instance of the execution node builder using the
@@ -184,6 +184,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.GroupByExpr, *memo.ScalarGroupByExpr:
 		ep, err = b.buildGroupBy(e)
 
+	case *memo.GroupingSetsExpr:
+		ep, err = b.buildGroupingSets(t)
+
 	case *memo.DistinctOnExpr:
 		ep, err = b.buildDistinct(t)
 
@@ -241,6 +244,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.SequenceSelectExpr:
 		ep, err = b.buildSequenceSelect(t)
 
+	case *memo.RecursiveCTEExpr:
+		ep, err = b.buildRecursiveCTE(t)
+
+	case *memo.WorkTableExpr:
+		ep, err = b.buildWorkTable(t)
+
 	default:
 		if opt.IsSetOp(e) {
 			ep, err = b.buildSetOp(e)
@@ -408,6 +417,7 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		ordering.ScanIsReverse(scan, &scan.RequiredPhysical().Ordering),
 		b.indexConstraintMaxResults(scan),
 		res.reqOrdering(scan),
+		scan.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -807,6 +817,67 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 	}
 
 	aggregations := *groupBy.Child(1).(*memo.AggregationsExpr)
+	aggInfos, err := b.buildAggInfos(aggregations, &input, &ep, len(groupingColIdx))
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	if groupBy.Op() == opt.ScalarGroupByOp {
+		ep.root, err = b.factory.ConstructScalarGroupBy(input.root, aggInfos)
+	} else {
+		groupBy := groupBy.(*memo.GroupByExpr)
+		groupingColOrder := input.sqlOrdering(ordering.StreamingGroupingColOrdering(
+			&groupBy.GroupingPrivate, &groupBy.RequiredPhysical().Ordering,
+		))
+		reqOrdering := ep.reqOrdering(groupBy)
+		ep.root, err = b.factory.ConstructGroupBy(
+			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering,
+		)
+	}
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
+func (b *Builder) buildGroupingSets(groupingSets *memo.GroupingSetsExpr) (execPlan, error) {
+	input, err := b.buildGroupByInput(groupingSets)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	var ep execPlan
+	groupingCols := groupingSets.GroupingCols
+	groupingColIdx := make([]exec.ColumnOrdinal, 0, groupingCols.Len())
+	for i, ok := groupingCols.Next(0); ok; i, ok = groupingCols.Next(i + 1) {
+		ep.outputCols.Set(i, len(groupingColIdx))
+		groupingColIdx = append(groupingColIdx, input.getColumnOrdinal(opt.ColumnID(i)))
+	}
+
+	sets := make([]exec.ColumnOrdinalSet, len(groupingSets.Sets))
+	for i := range groupingSets.Sets {
+		sets[i] = input.getColumnOrdinalSet(groupingSets.Sets[i])
+	}
+
+	aggInfos, err := b.buildAggInfos(groupingSets.Aggregations, &input, &ep, len(groupingColIdx))
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep.outputCols.Set(int(groupingSets.GroupingSetCol), len(groupingColIdx)+len(aggInfos))
+
+	ep.root, err = b.factory.ConstructGroupingSets(input.root, groupingColIdx, sets, aggInfos)
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
+// buildAggInfos returns the exec.AggInfo for each of the given aggregations,
+// whose arguments are resolved against the given input. The i-th aggregation
+// is mapped to output column offset+i in ep.
+func (b *Builder) buildAggInfos(
+	aggregations memo.AggregationsExpr, input *execPlan, ep *execPlan, offset int,
+) ([]exec.AggInfo, error) {
 	aggInfos := make([]exec.AggInfo, len(aggregations))
 	for i := range aggregations {
 		item := &aggregations[i]
@@ -822,7 +893,7 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			if aggFilter, ok := child.(*memo.AggFilterExpr); ok {
 				filter, ok := aggFilter.Filter.(*memo.VariableExpr)
 				if !ok {
-					return execPlan{}, errors.Errorf("only VariableOp args supported")
+					return nil, errors.Errorf("only VariableOp args supported")
 				}
 				filterOrd = input.getColumnOrdinal(filter.Col)
 				child = aggFilter.Input
@@ -834,7 +905,7 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			}
 			v, ok := child.(*memo.VariableExpr)
 			if !ok {
-				return execPlan{}, errors.Errorf("only VariableOp args supported")
+				return nil, errors.Errorf("only VariableOp args supported")
 			}
 			argIdx = []exec.ColumnOrdinal{input.getColumnOrdinal(v.Col)}
 		}
@@ -850,25 +921,9 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			ConstArgs:  constArgs,
 			Filter:     filterOrd,
 		}
-		ep.outputCols.Set(int(item.Col), len(groupingColIdx)+i)
-	}
-
-	if groupBy.Op() == opt.ScalarGroupByOp {
-		ep.root, err = b.factory.ConstructScalarGroupBy(input.root, aggInfos)
-	} else {
-		groupBy := groupBy.(*memo.GroupByExpr)
-		groupingColOrder := input.sqlOrdering(ordering.StreamingGroupingColOrdering(
-			&groupBy.GroupingPrivate, &groupBy.RequiredPhysical().Ordering,
-		))
-		reqOrdering := ep.reqOrdering(groupBy)
-		ep.root, err = b.factory.ConstructGroupBy(
-			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering,
-		)
+		ep.outputCols.Set(int(item.Col), offset+i)
 	}
-	if err != nil {
-		return execPlan{}, err
-	}
-	return ep, nil
+	return aggInfos, nil
 }
 
 // extractAggregateConstArgs returns the list of constant arguments associated with a given aggregate
@@ -933,8 +988,13 @@ func (b *Builder) buildGroupByInput(groupBy memo.RelExpr) (execPlan, error) {
 	// We address just the GroupBy case for now because there is a particularly
 	// important case with COUNT(*) where we can remove all input columns, which
 	// leads to significant speedup.
-	private := groupBy.Private().(*memo.GroupingPrivate)
-	neededCols := private.GroupingCols.Copy()
+	var neededCols opt.ColSet
+	switch private := groupBy.Private().(type) {
+	case *memo.GroupingPrivate:
+		neededCols = private.GroupingCols.Copy()
+	case *memo.GroupingSetsPrivate:
+		neededCols = private.GroupingCols.Copy()
+	}
 	aggs := *groupBy.Child(1).(*memo.AggregationsExpr)
 	for i := range aggs {
 		neededCols.UnionWith(memo.ExtractAggInputColumns(aggs[i].Agg))
@@ -1052,6 +1112,96 @@ func (b *Builder) buildSetOp(set memo.RelExpr) (execPlan, error) {
 	return ep, nil
 }
 
+func (b *Builder) buildRecursiveCTE(rec *memo.RecursiveCTEExpr) (execPlan, error) {
+	initial, err := b.buildRelational(rec.Initial)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// Make sure we have the columns in the correct order; the working table
+	// columns map 1-1 to the initial columns.
+	initial, err = b.ensureColumns(initial, rec.InitialCols, nil /* colNames */, nil /* provided */)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// To implement exec.RecursiveCTEIterationFn, we create a special Builder
+	// for each iteration. The Recursive expression refers to the working table
+	// of the previous iteration through a WorkTable operator, which is bound to
+	// the given buffer.
+	fn := func(bufferRef exec.Node) (_ exec.Plan, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				// This code allows us to propagate internal errors without having to
+				// add error checks everywhere throughout the code, just like Build.
+				if pgErr, ok := r.(*pgerror.Error); ok {
+					err = pgErr
+				} else {
+					panic(r)
+				}
+			}
+		}()
+
+		innerBld := New(b.factory, b.mem, rec.Recursive, b.evalCtx)
+		innerBld.disableTelemetry = true
+		innerBld.workTables = append(innerBld.workTables, b.workTables...)
+		innerBld.workTables = append(innerBld.workTables, workTableBinding{
+			id:        rec.WithID,
+			bufferRef: bufferRef,
+		})
+
+		plan, err := innerBld.buildRelational(rec.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		// Ensure columns are output in the same order as the working table.
+		plan, err = innerBld.ensureColumns(plan, rec.RecursiveCols, nil /* colNames */, nil /* provided */)
+		if err != nil {
+			return nil, err
+		}
+		return innerBld.factory.ConstructPlan(plan.root, innerBld.subqueries)
+	}
+
+	label := fmt.Sprintf("working buffer (%s)", rec.Name)
+	node, err := b.factory.ConstructRecursiveCTE(initial.root, fn, label, rec.Deduplicate)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	ep := execPlan{root: node}
+	for i, col := range rec.OutCols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
+func (b *Builder) buildWorkTable(wt *memo.WorkTableExpr) (execPlan, error) {
+	var bufferRef exec.Node
+	for i := len(b.workTables) - 1; i >= 0; i-- {
+		if b.workTables[i].id == wt.WithID {
+			bufferRef = b.workTables[i].bufferRef
+			break
+		}
+	}
+	if bufferRef == nil {
+		return execPlan{}, pgerror.AssertionFailedf(
+			"couldn't find working table for recursive CTE %s", log.Safe(wt.Name),
+		)
+	}
+
+	label := fmt.Sprintf("working buffer (%s)", wt.Name)
+	node, err := b.factory.ConstructScanBuffer(bufferRef, label)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	ep := execPlan{root: node}
+	for i, col := range wt.Cols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
 // buildLimitOffset builds a plan for a LimitOp or OffsetOp
 func (b *Builder) buildLimitOffset(e memo.RelExpr) (execPlan, error) {
 	input, err := b.buildRelational(e.Child(0).(memo.RelExpr))
@@ -1138,7 +1288,7 @@ func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 	}
 
 	res.root, err = b.factory.ConstructIndexJoin(
-		input.root, md.Table(join.Table), needed, reqOrdering,
+		input.root, md.Table(join.Table), needed, reqOrdering, join.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -1193,6 +1343,7 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 		lookupOrdinals,
 		onExpr,
 		res.reqOrdering(join),
+		join.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -1823,6 +1974,11 @@ func (b *Builder) canUseDeleteRange(del *memo.DeleteExpr) bool {
 		// is possible, because the integrity of those references must be checked.
 		return false
 	}
+	if tab.HasTriggers() {
+		// Triggers fire for the statement and for each deleted row, so the
+		// rows must be fetched.
+		return false
+	}
 
 	// Check for simple Scan input operator without a limit; anything else is not
 	// supported by a range delete.
@@ -56,6 +56,8 @@ type Factory interface {
 	//     the scan.
 	//   - If maxResults > 0, the scan is guaranteed to return at most maxResults
 	//     rows.
+	//   - If locking is not nil, the scan acquires row-level locks on the rows
+	//     it returns.
 	ConstructScan(
 		table cat.Table,
 		index cat.Index,
@@ -65,6 +67,7 @@ type Factory interface {
 		reverse bool,
 		maxResults uint64,
 		reqOrdering OutputOrdering,
+		locking *tree.LockingItem,
 	) (Node, error)
 
 	// ConstructVirtualScan returns a node that represents the scan of a virtual
@@ -174,6 +177,19 @@ type Factory interface {
 	// group) and has exactly one result row (even when there are no input rows).
 	ConstructScalarGroupBy(input Node, aggregations []AggInfo) (Node, error)
 
+	// ConstructGroupingSets returns a node that runs an aggregation separately
+	// for each of the given grouping sets, in a single pass over the input. Each
+	// grouping set is a subset of the groupCols; grouping columns which are not
+	// part of the set that produced a row are NULL in that row. The output
+	// consists of the groupCols, followed by the aggregations, followed by an
+	// INT column containing the index of the grouping set that produced the row.
+	ConstructGroupingSets(
+		input Node,
+		groupCols []ColumnOrdinal,
+		groupingSets []ColumnOrdinalSet,
+		aggregations []AggInfo,
+	) (Node, error)
+
 	// ConstructDistinct returns a node that filters out rows such that only the
 	// first row is kept for each set of values along the distinct columns.
 	// The orderedCols are a subset of distinctCols; the input is required to be
@@ -196,9 +212,14 @@ type Factory interface {
 
 	// ConstructIndexJoin returns a node that performs an index join.
 	// The input must be created by ConstructScan for the same table; cols is the
-	// set of columns produced by the index join.
+	// set of columns produced by the index join. If locking is not nil, the
+	// lookups acquire row-level locks on the rows they return.
 	ConstructIndexJoin(
-		input Node, table cat.Table, cols ColumnOrdinalSet, reqOrdering OutputOrdering,
+		input Node,
+		table cat.Table,
+		cols ColumnOrdinalSet,
+		reqOrdering OutputOrdering,
+		locking *tree.LockingItem,
 	) (Node, error)
 
 	// ConstructLookupJoin returns a node that preforms a lookup join.
@@ -207,7 +228,9 @@ type Factory interface {
 	// we are retrieving.
 	//
 	// The node produces the columns in the input and lookupCols (ordered by
-	// ordinal). The ON condition can refer to these using IndexedVars.
+	// ordinal). The ON condition can refer to these using IndexedVars. If
+	// locking is not nil, the lookups acquire row-level locks on the rows they
+	// return.
 	ConstructLookupJoin(
 		joinType sqlbase.JoinType,
 		input Node,
@@ -217,6 +240,7 @@ type Factory interface {
 		lookupCols ColumnOrdinalSet,
 		onCond tree.TypedExpr,
 		reqOrdering OutputOrdering,
+		locking *tree.LockingItem,
 	) (Node, error)
 
 	// ConstructZigzagJoin returns a node that performs a zigzag join.
@@ -260,6 +284,27 @@ type Factory interface {
 	// given node.
 	ConstructWindow(input Node, window WindowInfo) (Node, error)
 
+	// ConstructRecursiveCTE constructs a node that executes a recursive CTE:
+	//   * the initial plan is run first; the results are emitted and also saved
+	//     in a buffer.
+	//   * so long as the last buffer is not empty:
+	//     - the RecursiveCTEIterationFn is used to create a plan for the
+	//       recursive side; a reference to the last buffer is passed to this
+	//       function. The returned plan uses this reference with a
+	//       ConstructScanBuffer call.
+	//     - the plan is executed; the results are emitted and also saved in a new
+	//       buffer for the next iteration.
+	// If deduplicate is true, rows that were already emitted are discarded
+	// (UNION semantics).
+	ConstructRecursiveCTE(
+		initial Node, fn RecursiveCTEIterationFn, label string, deduplicate bool,
+	) (Node, error)
+
+	// ConstructScanBuffer constructs a node which refers to a node constructed by
+	// ConstructRecursiveCTE (the buffer reference passed to the iteration
+	// function).
+	ConstructScanBuffer(ref Node, label string) (Node, error)
+
 	// RenameColumns modifies the column names of a node.
 	RenameColumns(input Node, colNames []string) (Node, error)
 
@@ -432,6 +477,11 @@ const (
 	SubqueryAllRows
 )
 
+// RecursiveCTEIterationFn creates a plan for an iteration of WITH RECURSIVE,
+// given the result of the last iteration (as a Node created by
+// ConstructRecursiveCTE).
+type RecursiveCTEIterationFn func(bufferRef Node) (Plan, error)
+
 // ColumnOrdinal is the 0-based ordinal index of a column produced by a Node.
 type ColumnOrdinal int32
 
@@ -15,8 +15,10 @@ package idxconstraint
 import (
 	"context"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
@@ -775,6 +777,10 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 	constrained := false
 	switch nd.Op() {
 	case opt.ContainsOp:
+		if c.colType(0).Family() == types.ArrayFamily {
+			return c.makeArrayInvertedIndexSpans(nd, constraints, allPaths)
+		}
+
 		lhs, rhs := nd.Child(0), nd.Child(1)
 
 		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
@@ -859,6 +865,18 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return true, append(constraints, out)
 		}
 
+	case opt.OverlapsOp:
+		if c.colType(0).Family() == types.ArrayFamily {
+			return c.makeArrayInvertedIndexSpans(nd, constraints, allPaths)
+		}
+
+	case opt.FunctionOp:
+		if c.makeSpatialIndexSpans(nd.(*memo.FunctionExpr), out) {
+			// The cells of the index only approximate the shapes, so the spans
+			// are never tight.
+			return false, append(constraints, out)
+		}
+
 	case opt.AndOp, opt.FiltersOp:
 		for i, n := 0, nd.ChildCount(); i < n; i++ {
 			tight, constraints = c.makeInvertedIndexSpansForExpr(
@@ -900,6 +918,213 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 	return false, constraints
 }
 
+// makeArrayInvertedIndexSpans is the counterpart of
+// makeInvertedIndexSpansForExpr for a @>, <@ or && comparison between the
+// column of an inverted index on an array and a constant. An array is stored
+// under the key of each of its distinct non-NULL elements, or under the key of
+// the empty array if it has none, so the spans are expressed in terms of
+// single-element arrays and of the empty array.
+func (c *indexConstraintCtx) makeArrayInvertedIndexSpans(
+	nd opt.Expr, constraints []*constraint.Constraint, allPaths bool,
+) (bool, []*constraint.Constraint) {
+	out := &constraint.Constraint{}
+	lhs, rhs := nd.Child(0), nd.Child(1)
+	containedBy := false
+	switch {
+	case c.isIndexColumn(lhs, 0 /* index */) && opt.IsConstValueOp(rhs):
+	case c.isIndexColumn(rhs, 0 /* index */) && opt.IsConstValueOp(lhs):
+		// The <@ operator is built as a @> with its operands reversed, while &&
+		// is commutative.
+		containedBy = nd.Op() == opt.ContainsOp
+		rhs = lhs
+	default:
+		c.unconstrained(0 /* offset */, out)
+		return false, append(constraints, out)
+	}
+
+	arr, ok := tree.AsDArray(memo.ExtractConstDatum(rhs))
+	if !ok {
+		// The constant is NULL, so the comparison is never true.
+		c.contradiction(0 /* offset */, out)
+		return false, append(constraints, out)
+	}
+
+	// Collect the distinct non-NULL elements of the constant.
+	elems := make(tree.Datums, 0, len(arr.Array))
+	hasNulls := false
+	for _, d := range arr.Array {
+		if d == tree.DNull {
+			hasNulls = true
+			continue
+		}
+		elems = append(elems, d)
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		return elems[i].Compare(c.evalCtx, elems[j]) < 0
+	})
+	for i := 1; i < len(elems); {
+		if elems[i].Compare(c.evalCtx, elems[i-1]) == 0 {
+			elems = append(elems[:i], elems[i+1:]...)
+		} else {
+			i++
+		}
+	}
+	elemSpan := func(d tree.Datum, out *constraint.Constraint) {
+		key := tree.NewDArray(arr.ParamTyp)
+		key.Array = tree.Datums{d}
+		key.HasNonNulls = true
+		c.eqSpan(0 /* offset */, key, out)
+	}
+
+	switch {
+	case containedBy:
+		// Each element of a contained array is one of the elements of the
+		// constant, so the array is stored under the key of one of them, or under
+		// the key of the empty array. The spans are not tight, because the index
+		// does not record NULL elements, which are never contained.
+		c.eqSpan(0 /* offset */, tree.NewDArray(arr.ParamTyp), out)
+		for _, d := range elems {
+			var other constraint.Constraint
+			elemSpan(d, &other)
+			out.UnionWith(c.evalCtx, &other)
+		}
+		return false, append(constraints, out)
+
+	case nd.Op() == opt.ContainsOp:
+		if hasNulls {
+			// No array contains a NULL element.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		if len(elems) == 0 {
+			// Every array contains the empty array.
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		// A containing array is stored under the key of each of the elements,
+		// so any one of them constrains the scan. As with JSON paths, all of
+		// them are returned if allPaths is true, for use in zigzag joins.
+		for _, d := range elems {
+			elemSpan(d, out)
+			constraints = append(constraints, out)
+			if !allPaths {
+				break
+			}
+			out = &constraint.Constraint{}
+		}
+		// The span is tight if there is a single element.
+		return len(elems) == 1, constraints
+
+	default:
+		if len(elems) == 0 {
+			// No array overlaps an array without non-NULL elements.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		// An overlapping array is stored under the key of at least one of the
+		// elements.
+		elemSpan(elems[0], out)
+		for _, d := range elems[1:] {
+			var other constraint.Constraint
+			elemSpan(d, &other)
+			out.UnionWith(c.evalCtx, &other)
+		}
+		// The spans are tight if there is a single element; otherwise, the same
+		// row can be found under several of them.
+		return len(elems) == 1, append(constraints, out)
+	}
+}
+
+// spatialIndexFunctions are the spatial predicates that can only be true if
+// their arguments intersect (or, for st_dwithin, are within the given
+// distance of each other), and which can therefore be evaluated using a
+// spatial inverted index.
+var spatialIndexFunctions = map[string]bool{
+	"st_intersects": true,
+	"st_contains":   true,
+	"st_within":     true,
+	"st_dwithin":    true,
+}
+
+// makeSpatialIndexSpans constrains a spatial inverted index using a spatial
+// predicate between the index column and a constant, and returns false if
+// the function is not such a predicate. Each row is indexed under the
+// smallest cell containing its bounding box, so the rows that may satisfy the
+// predicate are those indexed under a descendant or an ancestor of a cell of
+// the covering of the constant. The spans are expressed in terms of the cell
+// IDs, as DInts.
+func (c *indexConstraintCtx) makeSpatialIndexSpans(
+	fn *memo.FunctionExpr, out *constraint.Constraint,
+) bool {
+	if !spatialIndexFunctions[fn.Name] || len(fn.Args) < 2 {
+		return false
+	}
+	var arg opt.ScalarExpr
+	switch {
+	case c.isIndexColumn(fn.Args[0], 0 /* index */) && opt.IsConstValueOp(fn.Args[1]):
+		arg = fn.Args[1]
+	case c.isIndexColumn(fn.Args[1], 0 /* index */) && opt.IsConstValueOp(fn.Args[0]):
+		arg = fn.Args[0]
+	default:
+		return false
+	}
+	var distance float64
+	if fn.Name == "st_dwithin" {
+		if len(fn.Args) != 3 || !opt.IsConstValueOp(fn.Args[2]) {
+			return false
+		}
+		d, ok := memo.ExtractConstDatum(fn.Args[2]).(*tree.DFloat)
+		if !ok {
+			// A NULL distance.
+			c.contradiction(0 /* offset */, out)
+			return true
+		}
+		distance = float64(*d)
+	}
+
+	var cells []geo.CellID
+	switch t := memo.ExtractConstDatum(arg).(type) {
+	case *tree.DGeometry:
+		cells = t.IndexCovering(distance)
+	case *tree.DGeography:
+		cells = t.IndexCovering(distance)
+	default:
+		// A NULL argument: the predicate is never true.
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+	if len(cells) == 0 {
+		// An empty shape does not intersect anything.
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+
+	cellKey := func(cell geo.CellID) constraint.Key {
+		return constraint.MakeKey(tree.NewDInt(tree.DInt(cell)))
+	}
+	ancestors := make(map[geo.CellID]bool)
+	for i, cell := range cells {
+		var span constraint.Span
+		span.Init(cellKey(cell.RangeMin()), includeBoundary, cellKey(cell.RangeMax()), includeBoundary)
+		if i == 0 {
+			out.InitSingleSpan(&c.keyCtx[0], &span)
+		} else {
+			var other constraint.Constraint
+			other.InitSingleSpan(&c.keyCtx[0], &span)
+			out.UnionWith(c.evalCtx, &other)
+		}
+		for _, a := range cell.Ancestors() {
+			ancestors[a] = true
+		}
+	}
+	for a := range ancestors {
+		var other constraint.Constraint
+		c.eqSpan(0 /* offset */, tree.NewDInt(tree.DInt(a)), &other)
+		out.UnionWith(c.evalCtx, &other)
+	}
+	return true
+}
+
 // getMaxSimplifyPrefix finds the longest prefix (maxSimplifyPrefix) such that
 // every span has the same first maxSimplifyPrefix values for the start and end
 // key. For example, for:
@@ -134,7 +134,7 @@ func (m *Memo) checkExpr(e opt.Expr) {
 			}
 		}
 
-	case *GroupByExpr, *ScalarGroupByExpr:
+	case *GroupByExpr, *ScalarGroupByExpr, *GroupingSetsExpr:
 		// Check that aggregates cannot be FirstAgg.
 		for _, item := range *t.Child(1).(*AggregationsExpr) {
 			switch item.Agg.Op() {
@@ -465,10 +465,10 @@ func ExprIsNeverNull(e opt.ScalarExpr, notNullCols opt.ColSet) bool {
 
 	case *AndExpr, *OrExpr, *GeExpr, *GtExpr, *NeExpr, *EqExpr, *LeExpr, *LtExpr, *LikeExpr,
 		*NotLikeExpr, *ILikeExpr, *NotILikeExpr, *SimilarToExpr, *NotSimilarToExpr, *RegMatchExpr,
-		*NotRegMatchExpr, *RegIMatchExpr, *NotRegIMatchExpr, *ContainsExpr, *JsonExistsExpr,
-		*JsonAllExistsExpr, *JsonSomeExistsExpr, *AnyScalarExpr, *BitandExpr, *BitorExpr, *BitxorExpr,
-		*PlusExpr, *MinusExpr, *MultExpr, *DivExpr, *FloorDivExpr, *ModExpr, *PowExpr, *ConcatExpr,
-		*LShiftExpr, *RShiftExpr, *WhenExpr:
+		*NotRegMatchExpr, *RegIMatchExpr, *NotRegIMatchExpr, *ContainsExpr, *OverlapsExpr,
+		*JsonExistsExpr, *JsonAllExistsExpr, *JsonSomeExistsExpr, *AnyScalarExpr, *BitandExpr,
+		*BitorExpr, *BitxorExpr, *PlusExpr, *MinusExpr, *MultExpr, *DivExpr, *FloorDivExpr, *ModExpr,
+		*PowExpr, *ConcatExpr, *LShiftExpr, *RShiftExpr, *WhenExpr:
 		return ExprIsNeverNull(t.Child(0).(opt.ScalarExpr), notNullCols) &&
 			ExprIsNeverNull(t.Child(1).(opt.ScalarExpr), notNullCols)
 
@@ -189,7 +189,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 
 	case *ScanExpr, *VirtualScanExpr, *IndexJoinExpr, *ShowTraceForSessionExpr,
 		*InsertExpr, *UpdateExpr, *UpsertExpr, *DeleteExpr, *SequenceSelectExpr,
-		*WindowExpr:
+		*WindowExpr, *RecursiveCTEExpr, *WorkTableExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -229,6 +229,12 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*UnionAllExpr, *IntersectAllExpr, *ExceptAllExpr:
 		colList = e.Private().(*SetPrivate).OutCols
 
+	case *RecursiveCTEExpr:
+		colList = t.OutCols
+
+	case *WorkTableExpr:
+		colList = t.Cols
+
 	default:
 		// Fall back to writing output columns in column id order.
 		colList = opt.ColSetToList(e.Relational().OutputCols)
@@ -248,6 +254,16 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			tp.Childf("internal-ordering: %s", private.Ordering)
 		}
 
+	case *GroupingSetsExpr:
+		if !f.HasFlags(ExprFmtHideColumns) {
+			f.formatColList(e, tp, "grouping columns:", opt.ColSetToList(t.GroupingCols))
+			tp.Childf("grouping sets: %s", formatColSetList(t.Sets))
+			f.formatColList(e, tp, "grouping set column:", opt.ColList{t.GroupingSetCol})
+		}
+		if !f.HasFlags(ExprFmtHideOrderings) && !t.Ordering.Any() {
+			tp.Childf("internal-ordering: %s", t.Ordering)
+		}
+
 	case *LimitExpr:
 		if !f.HasFlags(ExprFmtHideOrderings) && !t.Ordering.Any() {
 			tp.Childf("internal-ordering: %s", t.Ordering)
@@ -268,6 +284,12 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			f.formatColList(e, tp, "right columns:", private.RightCols)
 		}
 
+	case *RecursiveCTEExpr:
+		if !f.HasFlags(ExprFmtHideColumns) {
+			f.formatColList(e, tp, "initial columns:", t.InitialCols)
+			f.formatColList(e, tp, "recursive columns:", t.RecursiveCols)
+		}
+
 	case *ScanExpr:
 		if t.Constraint != nil {
 			tp.Childf("constraint: %s", t.Constraint)
@@ -291,11 +313,16 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 				tp.Childf("flags: force-index=%s%s", idx.Name(), dir)
 			}
 		}
+		f.formatLocking(tp, t.Locking)
+
+	case *IndexJoinExpr:
+		f.formatLocking(tp, t.Locking)
 
 	case *LookupJoinExpr:
 		if !t.Flags.Empty() {
 			tp.Childf("flags: %s", t.Flags.String())
 		}
+		f.formatLocking(tp, t.Locking)
 		idxCols := make(opt.ColList, len(t.KeyCols))
 		idx := md.Table(t.Table).Index(t.Index)
 		for i := range idxCols {
@@ -758,6 +785,37 @@ func (f *ExprFmtCtx) formatMutation(
 	}
 }
 
+// formatLocking adds a "locking" child to the tree if the given locking item
+// is set, similar to this:
+//
+//   locking: for-update,skip-locked
+//
+func (f *ExprFmtCtx) formatLocking(tp treeprinter.Node, locking *tree.LockingItem) {
+	if locking == nil {
+		return
+	}
+	strength := ""
+	switch locking.Strength {
+	case tree.ForNone:
+		return
+	case tree.ForKeyShare:
+		strength = "for-key-share"
+	case tree.ForShare:
+		strength = "for-share"
+	case tree.ForNoKeyUpdate:
+		strength = "for-no-key-update"
+	case tree.ForUpdate:
+		strength = "for-update"
+	}
+	switch locking.WaitPolicy {
+	case tree.LockWaitSkip:
+		strength += ",skip-locked"
+	case tree.LockWaitError:
+		strength += ",nowait"
+	}
+	tp.Childf("locking: %s", strength)
+}
+
 // formatCol outputs the specified column into the context's buffer using the
 // following format:
 //   label:index(type)
@@ -801,6 +859,19 @@ func formatCol(
 	}
 }
 
+// formatColSetList returns a string with the column sets in the given list,
+// for example: "(1,2) (1) ()".
+func formatColSetList(sets opt.ColSetList) string {
+	var buf bytes.Buffer
+	for i := range sets {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(sets[i].String())
+	}
+	return buf.String()
+}
+
 func frameBoundName(b tree.WindowFrameBoundType) string {
 	switch b {
 	case tree.UnboundedFollowing, tree.UnboundedPreceding:
@@ -867,6 +938,12 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
 		}
 
+	case *GroupingSetsPrivate:
+		fmt.Fprintf(f.Buffer, " cols=%s,sets=%s", t.GroupingCols.String(), formatColSetList(t.Sets))
+		if !t.Ordering.Any() {
+			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
+		}
+
 	case *IndexJoinPrivate:
 		tab := f.Memo.metadata.Table(t.Table)
 		fmt.Fprintf(f.Buffer, " %s", tab.Name().TableName)
@@ -882,6 +959,15 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *ValuesPrivate:
 		fmt.Fprintf(f.Buffer, " id=v%d", t.ID)
 
+	case *RecursiveCTEPrivate:
+		fmt.Fprintf(f.Buffer, " %s id=w%d", t.Name, t.WithID)
+		if !t.Deduplicate {
+			f.Buffer.WriteString(",all")
+		}
+
+	case *WorkTablePrivate:
+		fmt.Fprintf(f.Buffer, " %s id=w%d", t.Name, t.WithID)
+
 	case *ZigzagJoinPrivate:
 		leftTab := f.Memo.metadata.Table(t.LeftTable)
 		rightTab := f.Memo.metadata.Table(t.RightTable)
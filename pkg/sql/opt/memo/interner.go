@@ -355,8 +355,15 @@ func (h *hasher) HashDatum(val tree.Datum) {
 		h.HashUint64(uint64(t.PGEpochDays()))
 	case *tree.DTime:
 		h.HashUint64(uint64(*t))
+	case *tree.DTimeTZ:
+		h.HashUint64(uint64(t.TimeOfDay))
+		h.HashUint64(uint64(t.OffsetSecs))
 	case *tree.DJSON:
 		h.HashString(t.String())
+	case *tree.DGeometry:
+		h.HashBytes(t.EWKB())
+	case *tree.DGeography:
+		h.HashBytes(t.EWKB())
 	case *tree.DTuple:
 		// If labels are present, then hash of tuple's static type is needed to
 		// disambiguate when everything is the same except labels.
@@ -417,6 +424,13 @@ func (h *hasher) HashColList(val opt.ColList) {
 	h.hash = hash
 }
 
+func (h *hasher) HashColSetList(val opt.ColSetList) {
+	for i := range val {
+		h.HashInt(val[i].Len())
+		h.HashColSet(val[i])
+	}
+}
+
 func (h *hasher) HashOrdering(val opt.Ordering) {
 	hash := h.hash
 	for _, id := range val {
@@ -452,6 +466,10 @@ func (h *hasher) HashValuesID(val opt.ValuesID) {
 	h.HashUint64(uint64(val))
 }
 
+func (h *hasher) HashWithID(val opt.WithID) {
+	h.HashUint64(uint64(val))
+}
+
 func (h *hasher) HashScanLimit(val ScanLimit) {
 	h.HashUint64(uint64(val))
 }
@@ -638,10 +656,22 @@ func (h *hasher) IsDatumEqual(l, r tree.Datum) bool {
 		if rt, ok := r.(*tree.DTime); ok {
 			return uint64(*lt) == uint64(*rt)
 		}
+	case *tree.DTimeTZ:
+		if rt, ok := r.(*tree.DTimeTZ); ok {
+			return lt.TimeTZ == rt.TimeTZ
+		}
 	case *tree.DJSON:
 		if rt, ok := r.(*tree.DJSON); ok {
 			return h.IsStringEqual(lt.String(), rt.String())
 		}
+	case *tree.DGeometry:
+		if rt, ok := r.(*tree.DGeometry); ok {
+			return bytes.Equal(lt.EWKB(), rt.EWKB())
+		}
+	case *tree.DGeography:
+		if rt, ok := r.(*tree.DGeography); ok {
+			return bytes.Equal(lt.EWKB(), rt.EWKB())
+		}
 	case *tree.DTuple:
 		if rt, ok := r.(*tree.DTuple); ok {
 			// Compare datums and then compare static types if nulls or labels
@@ -710,6 +740,10 @@ func (h *hasher) IsColListEqual(l, r opt.ColList) bool {
 	return l.Equals(r)
 }
 
+func (h *hasher) IsColSetListEqual(l, r opt.ColSetList) bool {
+	return l.Equals(r)
+}
+
 func (h *hasher) IsOrderingEqual(l, r opt.Ordering) bool {
 	return l.Equals(r)
 }
@@ -734,6 +768,10 @@ func (h *hasher) IsValuesIDEqual(l, r opt.ValuesID) bool {
 	return l == r
 }
 
+func (h *hasher) IsWithIDEqual(l, r opt.WithID) bool {
+	return l == r
+}
+
 func (h *hasher) IsScanLimitEqual(l, r ScanLimit) bool {
 	return l == r
 }
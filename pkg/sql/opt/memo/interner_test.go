@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"golang.org/x/tools/container/intsets"
 )
@@ -240,6 +241,8 @@ func TestInterner(t *testing.T) {
 
 			{val1: tree.MakeDTime(timeofday.Min), val2: tree.MakeDTime(timeofday.Min), equal: true},
 			{val1: tree.MakeDTime(timeofday.Min), val2: tree.MakeDTime(timeofday.Max), equal: false},
+			{val1: tree.MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.Min, 0)), val2: tree.MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.Min, 0)), equal: true},
+			{val1: tree.MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.Min, 0)), val2: tree.MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.Min, 3600)), equal: false},
 
 			{val1: json1, val2: json2, equal: true},
 			{val1: json2, val2: json3, equal: false},
@@ -307,6 +310,14 @@ func TestInterner(t *testing.T) {
 			{val1: opt.ColList{1, 2}, val2: opt.ColList{1, 2, 3}, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashColSetList, eqFn: in.hasher.IsColSetListEqual, variations: []testVariation{
+			{val1: opt.ColSetList{}, val2: opt.ColSetList{}, equal: true},
+			{val1: opt.ColSetList{util.MakeFastIntSet(1, 2), {}}, val2: opt.ColSetList{util.MakeFastIntSet(1, 2), {}}, equal: true},
+			{val1: opt.ColSetList{util.MakeFastIntSet(1, 2), {}}, val2: opt.ColSetList{{}, util.MakeFastIntSet(1, 2)}, equal: false},
+			{val1: opt.ColSetList{util.MakeFastIntSet(1), util.MakeFastIntSet(2)}, val2: opt.ColSetList{util.MakeFastIntSet(1, 2)}, equal: false},
+			{val1: opt.ColSetList{util.MakeFastIntSet(1)}, val2: opt.ColSetList{util.MakeFastIntSet(1), util.MakeFastIntSet(1)}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashOrdering, eqFn: in.hasher.IsOrderingEqual, variations: []testVariation{
 			{val1: opt.Ordering{}, val2: opt.Ordering{}, equal: true},
 			{val1: opt.Ordering{-1, 1}, val2: opt.Ordering{-1, 1}, equal: true},
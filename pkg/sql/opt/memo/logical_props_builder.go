@@ -552,6 +552,71 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 	}
 }
 
+func (b *logicalPropsBuilder) buildGroupingSetsProps(
+	groupingSets *GroupingSetsExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, groupingSets, &rel.Shared)
+
+	inputProps := groupingSets.Input.Relational()
+	private := &groupingSets.GroupingSetsPrivate
+
+	// Output Columns
+	// --------------
+	// Output columns are the union of grouping columns with columns from the
+	// aggregate projection list, plus the grouping set column.
+	rel.OutputCols = private.GroupingCols.Copy()
+	for i := range groupingSets.Aggregations {
+		rel.OutputCols.Add(int(groupingSets.Aggregations[i].Col))
+	}
+	rel.OutputCols.Add(int(private.GroupingSetCol))
+
+	// Not Null Columns
+	// ----------------
+	// A grouping column is null in the rows produced by grouping sets that
+	// don't contain it, so only columns that are part of every grouping set
+	// can inherit the not null setting of the input.
+	rel.NotNullCols = inputProps.NotNullCols.Intersection(private.GroupingCols)
+	for i := range private.Sets {
+		rel.NotNullCols.IntersectionWith(private.Sets[i])
+	}
+	rel.NotNullCols.Add(int(private.GroupingSetCol))
+
+	// Outer Columns
+	// -------------
+	// Outer columns were derived by buildSharedProps; remove any that are bound
+	// by input columns.
+	rel.OuterCols.DifferenceWith(inputProps.OutputCols)
+
+	// Functional Dependencies
+	// -----------------------
+	// Rows produced by the same grouping set have distinct values for the
+	// columns in that set, and null values for the other grouping columns. The
+	// grouping columns together with the grouping set column therefore form a
+	// strict key.
+	keyCols := private.GroupingCols.Copy()
+	keyCols.Add(int(private.GroupingSetCol))
+	rel.FuncDeps.AddStrictKey(keyCols, rel.OutputCols)
+
+	// Cardinality
+	// -----------
+	// Each grouping set acts like a GroupBy, except for the empty grouping set,
+	// which acts like a ScalarGroupBy.
+	rel.Cardinality = props.ZeroCardinality
+	for i := range private.Sets {
+		if private.Sets[i].Empty() {
+			rel.Cardinality = rel.Cardinality.Add(props.OneCardinality)
+		} else {
+			rel.Cardinality = rel.Cardinality.Add(inputProps.Cardinality.AsLowAs(1))
+		}
+	}
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildGroupingSets(groupingSets, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildUnionProps(union *UnionExpr, rel *props.Relational) {
 	b.buildSetProps(union, rel)
 }
@@ -635,6 +700,79 @@ func (b *logicalPropsBuilder) buildSetProps(setNode RelExpr, rel *props.Relation
 	}
 }
 
+func (b *logicalPropsBuilder) buildRecursiveCTEProps(
+	rec *RecursiveCTEExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, rec, &rel.Shared)
+
+	initialProps := rec.Initial.Relational()
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = rec.OutCols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by buildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// Recursive CTE operator has an empty FD set.
+
+	// Cardinality
+	// -----------
+	// At least as many rows as the initial query are returned; the number of
+	// recursive iterations is unknown.
+	rel.Cardinality = props.AnyCardinality.AtLeast(props.Cardinality{
+		Min: initialProps.Cardinality.Min,
+		Max: initialProps.Cardinality.Min,
+	})
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildRecursiveCTE(rec, rel)
+	}
+}
+
+func (b *logicalPropsBuilder) buildWorkTableProps(wt *WorkTableExpr, rel *props.Relational) {
+	BuildSharedProps(b.mem, wt, &rel.Shared)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = wt.Cols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by buildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// WorkTable operator has an empty FD set.
+
+	// Cardinality
+	// -----------
+	// The working table contents change from one iteration to the next, so
+	// don't make any assumptions about cardinality.
+	rel.Cardinality = props.AnyCardinality
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildWorkTable(rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildValuesProps(values *ValuesExpr, rel *props.Relational) {
 	BuildSharedProps(b.mem, values, &rel.Shared)
 
@@ -321,6 +321,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.GroupByOp, opt.ScalarGroupByOp, opt.DistinctOnOp:
 		return sb.colStatGroupBy(colSet, e)
 
+	case opt.GroupingSetsOp:
+		return sb.colStatGroupingSets(colSet, e.(*GroupingSetsExpr))
+
 	case opt.LimitOp:
 		return sb.colStatLimit(colSet, e.(*LimitExpr))
 
@@ -345,6 +348,12 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.SequenceSelectOp:
 		return sb.colStatSequenceSelect(colSet, e.(*SequenceSelectExpr))
 
+	case opt.RecursiveCTEOp:
+		return sb.colStatRecursiveCTE(colSet, e.(*RecursiveCTEExpr))
+
+	case opt.WorkTableOp:
+		return sb.colStatWorkTable(colSet, e.(*WorkTableExpr))
+
 	case opt.ExplainOp:
 		return sb.colStatExplain(colSet, e.(*ExplainExpr))
 
@@ -1459,6 +1468,89 @@ func (sb *statisticsBuilder) colStatGroupBy(
 	return colStat
 }
 
+// +---------------+
+// | Grouping Sets |
+// +---------------+
+
+func (sb *statisticsBuilder) buildGroupingSets(
+	groupingSets *GroupingSetsExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The row count is the sum of the row counts of the groupings, each of
+	// which is estimated like the row count of a GroupBy.
+	s.RowCount = 0
+	for _, set := range groupingSets.Sets {
+		s.RowCount += sb.groupingSetRowCount(set, groupingSets)
+	}
+
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatGroupingSets(
+	colSet opt.ColSet, groupingSets *GroupingSetsExpr,
+) *props.ColumnStatistic {
+	relProps := groupingSets.Relational()
+	s := &relProps.Stats
+	private := &groupingSets.GroupingSetsPrivate
+
+	// Estimate the distinct and null counts as the sums of the counts within
+	// each grouping. Grouping columns that are not part of a grouping set
+	// contribute a single null value to its groups, and aggregates are assumed
+	// to be distinct for each group.
+	groupingCols := colSet.Intersection(private.GroupingCols)
+	hasAggCols := !colSet.Difference(private.GroupingCols).Difference(
+		util.MakeFastIntSet(int(private.GroupingSetCol)),
+	).Empty()
+	distinctCount, nullCount := 0.0, 0.0
+	for _, set := range private.Sets {
+		rowCount := sb.groupingSetRowCount(set, groupingSets)
+		if !groupingCols.SubsetOf(set) {
+			nullCount += rowCount
+		}
+		switch {
+		case hasAggCols:
+			distinctCount += rowCount
+
+		case groupingCols.Intersects(set):
+			inputColStat := sb.colStatFromChild(
+				groupingCols.Intersection(set), groupingSets, 0, /* childIdx */
+			)
+			distinctCount += min(inputColStat.DistinctCount, rowCount)
+
+		default:
+			distinctCount++
+		}
+	}
+
+	colStat, _ := s.ColStats.Add(colSet)
+	colStat.DistinctCount = distinctCount
+	colStat.NullCount = nullCount
+	if colSet.SubsetOf(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCount(colStat, s.RowCount)
+	return colStat
+}
+
+// groupingSetRowCount estimates the number of rows produced by the given
+// grouping set of a GroupingSets expression.
+func (sb *statisticsBuilder) groupingSetRowCount(
+	set opt.ColSet, groupingSets *GroupingSetsExpr,
+) float64 {
+	if set.Empty() {
+		// The empty grouping set always produces a single row.
+		return 1
+	}
+	inputColStat := sb.colStatFromChild(set, groupingSets, 0 /* childIdx */)
+	inputRowCount := sb.statsFromChild(groupingSets, 0 /* childIdx */).RowCount
+	return min(inputColStat.DistinctCount+min(1, inputColStat.NullCount), inputRowCount)
+}
+
 // +--------+
 // | Set Op |
 // +--------+
@@ -2057,6 +2149,59 @@ func (sb *statisticsBuilder) colStatSequenceSelect(
 	return colStat
 }
 
+// +---------------+
+// | Recursive CTE |
+// +---------------+
+
+func (sb *statisticsBuilder) buildRecursiveCTE(rec *RecursiveCTEExpr, relProps *props.Relational) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The number of iterations is unknown, so assume the recursive query
+	// produces as many rows as a generator with unknown output on top of the
+	// rows produced by the initial query.
+	initialStats := &rec.Initial.Relational().Stats
+	s.RowCount = initialStats.RowCount + unknownGeneratorRowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatRecursiveCTE(
+	colSet opt.ColSet, rec *RecursiveCTEExpr,
+) *props.ColumnStatistic {
+	s := &rec.Relational().Stats
+
+	colStat, _ := s.ColStats.Add(colSet)
+	colStat.DistinctCount = s.RowCount
+	colStat.NullCount = s.RowCount * unknownNullCountRatio
+	sb.finalizeFromRowCount(colStat, s.RowCount)
+	return colStat
+}
+
+// +------------+
+// | Work Table |
+// +------------+
+
+func (sb *statisticsBuilder) buildWorkTable(relProps *props.Relational) {
+	s := &relProps.Stats
+	s.RowCount = unknownGeneratorRowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatWorkTable(
+	colSet opt.ColSet, wt *WorkTableExpr,
+) *props.ColumnStatistic {
+	s := &wt.Relational().Stats
+
+	colStat, _ := s.ColStats.Add(colSet)
+	colStat.DistinctCount = s.RowCount
+	colStat.NullCount = s.RowCount * unknownNullCountRatio
+	sb.finalizeFromRowCount(colStat, s.RowCount)
+	return colStat
+}
+
 // +---------+
 // | Explain |
 // +---------+
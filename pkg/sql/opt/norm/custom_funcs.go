@@ -124,6 +124,11 @@ func (c *CustomFuncs) HasColType(scalar opt.ScalarExpr, dstTyp *types.T) bool {
 	return scalar.DataType().Identical(dstTyp)
 }
 
+// IsDomainType returns true if the given type is a domain.
+func (c *CustomFuncs) IsDomainType(typ *types.T) bool {
+	return typ.DomainID() != 0
+}
+
 // IsString returns true if the given scalar expression is of type String.
 func (c *CustomFuncs) IsString(scalar opt.ScalarExpr) bool {
 	return scalar.DataType().Family() == types.StringFamily
@@ -100,6 +100,23 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// tableCols returns all columns in the table, including mutation columns.
+	// The predicate of a partial index may reference any of them, and is not
+	// available in the metadata of the mutation table, so all are conservatively
+	// considered to be part of a partial index.
+	tableCols := func() opt.ColSet {
+		var colSet opt.ColSet
+		for i, n := 0, tabMeta.Table.DeletableColumnCount(); i < n; i++ {
+			colSet.Add(int(tabMeta.MetaID.ColumnID(i)))
+		}
+		return colSet
+	}
+
+	// Triggers have access to all the columns of the affected rows.
+	if tabMeta.Table.HasTriggers() {
+		return tableCols()
+	}
+
 	// Retain any FetchCols that are needed for ReturnCols. If a RETURN column
 	// is needed, then:
 	//   1. For Delete, the corresponding FETCH column is always needed, since
@@ -132,6 +149,9 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		// Make sure to consider indexes that are being added or dropped.
 		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
 			indexCols := tabMeta.IndexColumns(i)
+			if _, isPartial := tabMeta.Table.Index(i).Predicate(); isPartial {
+				indexCols = tableCols()
+			}
 			if !indexCols.Intersects(updateCols) {
 				// This index is not being updated.
 				continue
@@ -163,6 +183,12 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		// or dropped.
 		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
 			cols.UnionWith(tabMeta.IndexKeyColumns(i))
+
+			// The predicate of a partial index must be evaluated to determine
+			// whether the row has an entry in the index.
+			if _, isPartial := tabMeta.Table.Index(i).Predicate(); isPartial {
+				cols.UnionWith(tableCols())
+			}
 		}
 	}
 
@@ -98,6 +98,10 @@ type Builder struct {
 	// subquery contains a pointer to the subquery which is currently being built
 	// (if any).
 	subquery *subquery
+
+	// locking contains the row-level locking items that apply to the data
+	// sources currently being built (see lockingSpec).
+	locking lockingSpec
 }
 
 // New creates a new Builder structure initialized with the given
@@ -41,7 +41,7 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	}
 
 	if del.With != nil {
-		inScope = b.buildCTE(del.With.CTEList, inScope)
+		inScope = b.buildCTE(del.With.CTEList, del.With.Recursive, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
@@ -65,11 +65,11 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	// Build the input expression that selects the rows that will be deleted:
 	//
 	//   WITH <with>
-	//   SELECT <cols> FROM <table> WHERE <where>
+	//   SELECT <cols> FROM <table>, <using> WHERE <where>
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the delete table will be projected.
-	mb.buildInputForUpdateOrDelete(inScope, del.Where, del.Limit, del.OrderBy)
+	mb.buildInputForUpdateOrDelete(inScope, del.Using, del.Where, del.Limit, del.OrderBy)
 
 	// Build the final delete statement, including any returned expressions.
 	if resultsNeeded(del.Returning) {
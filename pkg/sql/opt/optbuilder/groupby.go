@@ -67,6 +67,17 @@ type groupby struct {
 	// projects that expression.
 	groupStrs groupByStrSet
 
+	// groupingSets contains the grouping sets of a GROUP BY clause with
+	// ROLLUP, CUBE or GROUPING SETS items, as subsets of the grouping columns.
+	// It is nil if the clause expands to a single grouping set, which contains
+	// all the grouping columns.
+	groupingSets opt.ColSetList
+
+	// groupingSetCol is the column produced by the aggregation that holds the
+	// index of the grouping set of each row. It is only set if groupingSets is
+	// set.
+	groupingSetCol opt.ColumnID
+
 	// inAgg is true within the body of an aggregate function. inAgg is used
 	// to ensure that nested aggregates are disallowed.
 	inAgg bool
@@ -131,6 +142,46 @@ func (b *Builder) needsAggregation(sel *tree.SelectClause, scope *scope) bool {
 func (b *Builder) constructGroupBy(
 	input memo.RelExpr, groupingColSet opt.ColSet, aggCols []scopeColumn, ordering opt.Ordering,
 ) memo.RelExpr {
+	aggs := b.constructAggregations(aggCols)
+	private := memo.GroupingPrivate{GroupingCols: groupingColSet}
+
+	// The ordering of the GROUP BY is inherited from the input. This ordering is
+	// only useful for intra-group ordering (for order-sensitive aggregations like
+	// ARRAY_AGG). So we add the grouping columns as optional columns.
+	private.Ordering.FromOrderingWithOptCols(ordering, groupingColSet)
+
+	if groupingColSet.Empty() {
+		return b.factory.ConstructScalarGroupBy(input, aggs, &private)
+	}
+	return b.factory.ConstructGroupBy(input, aggs, &private)
+}
+
+// constructGroupingSets constructs a GroupingSets expression that computes the
+// given aggregations for each of the given grouping sets.
+func (b *Builder) constructGroupingSets(
+	input memo.RelExpr,
+	groupingColSet opt.ColSet,
+	groupingSets opt.ColSetList,
+	groupingSetCol opt.ColumnID,
+	aggCols []scopeColumn,
+	ordering opt.Ordering,
+) memo.RelExpr {
+	aggs := b.constructAggregations(aggCols)
+	private := memo.GroupingSetsPrivate{
+		GroupingCols:   groupingColSet,
+		Sets:           groupingSets,
+		GroupingSetCol: groupingSetCol,
+	}
+
+	// As with GroupBy, the ordering is only useful for intra-group ordering.
+	private.Ordering.FromOrderingWithOptCols(ordering, groupingColSet)
+
+	return b.factory.ConstructGroupingSets(input, aggs, &private)
+}
+
+// constructAggregations constructs the list of aggregations computed by a
+// GroupBy or GroupingSets expression.
+func (b *Builder) constructAggregations(aggCols []scopeColumn) memo.AggregationsExpr {
 	aggs := make(memo.AggregationsExpr, 0, len(aggCols))
 
 	// Deduplicate the columns; we don't need to produce the same aggregation
@@ -150,18 +201,7 @@ func (b *Builder) constructGroupBy(
 			colSet.Add(int(id))
 		}
 	}
-
-	private := memo.GroupingPrivate{GroupingCols: groupingColSet}
-
-	// The ordering of the GROUP BY is inherited from the input. This ordering is
-	// only useful for intra-group ordering (for order-sensitive aggregations like
-	// ARRAY_AGG). So we add the grouping columns as optional columns.
-	private.Ordering.FromOrderingWithOptCols(ordering, groupingColSet)
-
-	if groupingColSet.Empty() {
-		return b.factory.ConstructScalarGroupBy(input, aggs, &private)
-	}
-	return b.factory.ConstructGroupBy(input, aggs, &private)
+	return aggs
 }
 
 // buildGroupingColumns builds the grouping columns and adds them to the
@@ -213,6 +253,13 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, fromScope *scope)
 	groupingCols := aggInScope.getGroupingCols(groupingsLen)
 	aggOutScope.appendColumns(groupingCols)
 
+	// With multiple grouping sets, the aggregation also produces a column that
+	// identifies the grouping set of each row (see buildGroupingExpr).
+	if fromScope.groupby.groupingSets != nil {
+		col := b.synthesizeColumn(aggOutScope, "grouping_set", types.Int, nil, nil /* scalar */)
+		fromScope.groupby.groupingSetCol = col.id
+	}
+
 	return groupingCols
 }
 
@@ -293,12 +340,23 @@ func (b *Builder) buildAggregation(
 		groupingColSet.Add(int(groupingCols[i].id))
 	}
 
-	aggOutScope.expr = b.constructGroupBy(
-		aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		aggInScope.ordering,
-	)
+	if groupingSets := fromScope.groupby.groupingSets; groupingSets != nil {
+		aggOutScope.expr = b.constructGroupingSets(
+			aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			groupingSets,
+			fromScope.groupby.groupingSetCol,
+			aggCols,
+			aggInScope.ordering,
+		)
+	} else {
+		aggOutScope.expr = b.constructGroupBy(
+			aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -349,6 +407,13 @@ func (b *Builder) buildHaving(having tree.TypedExpr, inScope *scope) opt.ScalarE
 //              SELECT count(*), k FROM t GROUP BY 2
 //          indicates that the grouping is on the second select expression, k.
 //
+// The GROUP BY list can contain ROLLUP, CUBE and GROUPING SETS items. The
+// grouping sets of the list are the cross product of the grouping sets of
+// its items; if there is more than one, they are stored in
+// inScope.groupby.groupingSets. For example:
+//
+//   GROUP BY a, ROLLUP (b, c)  =>  (a, b, c), (a, b), (a)
+//
 // See Builder.buildStmt for a description of the remaining input values.
 func (b *Builder) buildGroupingList(
 	groupBy tree.GroupBy, selects tree.SelectExprs, inScope *scope, outScope *scope,
@@ -359,10 +424,101 @@ func (b *Builder) buildGroupingList(
 	}
 
 	inScope.startBuildingGroupingCols()
+	groupingSets := opt.ColSetList{{}}
 	for _, e := range groupBy {
-		b.buildGrouping(e, selects, inScope, outScope)
+		itemSets := b.buildGroupingItem(e, selects, inScope, outScope)
+		checkGroupingSetsCount(len(groupingSets) * len(itemSets))
+		crossSets := make(opt.ColSetList, 0, len(groupingSets)*len(itemSets))
+		for i := range groupingSets {
+			for j := range itemSets {
+				crossSets = append(crossSets, groupingSets[i].Union(itemSets[j]))
+			}
+		}
+		groupingSets = crossSets
 	}
 	inScope.endBuildingGroupingCols()
+
+	if len(groupingSets) > 1 {
+		inScope.groupby.groupingSets = groupingSets
+	}
+}
+
+// maxGroupingSets is the maximum number of grouping sets in a GROUP BY clause.
+const maxGroupingSets = 4096
+
+// maxCubeElements is the maximum number of elements in a CUBE item.
+const maxCubeElements = 12
+
+// checkGroupingSetsCount raises an error if the given number of grouping sets
+// exceeds maxGroupingSets.
+func checkGroupingSetsCount(count int) {
+	if count > maxGroupingSets {
+		panic(pgerror.Newf(pgerror.CodeStatementTooComplexError,
+			"too many grouping sets present (maximum %d)", maxGroupingSets))
+	}
+}
+
+// buildGroupingItem builds the grouping columns for an item of a GROUP BY
+// list, and returns the grouping sets that the item expands to. A plain
+// grouping expression expands to a single grouping set. The expansions of
+// the other items are:
+//
+//   ROLLUP (a, b)              =>  (a, b), (a), ()
+//   CUBE (a, b)                =>  (a, b), (a), (b), ()
+//   GROUPING SETS (a, (b, c))  =>  (a), (b, c)
+//
+// See buildGroupingList for a description of the input values.
+func (b *Builder) buildGroupingItem(
+	item tree.Expr, selects tree.SelectExprs, inScope, outScope *scope,
+) opt.ColSetList {
+	groupingSets, ok := item.(*tree.GroupingSets)
+	if !ok {
+		return opt.ColSetList{b.buildGrouping(item, selects, inScope, outScope)}
+	}
+
+	switch groupingSets.Type {
+	case tree.GroupingSetsRollup:
+		n := len(groupingSets.Exprs)
+		res := make(opt.ColSetList, n+1)
+		var cols opt.ColSet
+		for i, e := range groupingSets.Exprs {
+			cols = cols.Union(b.buildGrouping(e, selects, inScope, outScope))
+			res[n-1-i] = cols
+		}
+		return res
+
+	case tree.GroupingSetsCube:
+		n := len(groupingSets.Exprs)
+		if n > maxCubeElements {
+			panic(pgerror.Newf(pgerror.CodeProgramLimitExceededError,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		elems := make(opt.ColSetList, n)
+		for i, e := range groupingSets.Exprs {
+			elems[i] = b.buildGrouping(e, selects, inScope, outScope)
+		}
+		// Enumerate the subsets of the elements in decreasing order of their bit
+		// masks, where the first element corresponds to the highest bit.
+		res := make(opt.ColSetList, 0, 1<<uint(n))
+		for mask := 1<<uint(n) - 1; mask >= 0; mask-- {
+			var cols opt.ColSet
+			for i := range elems {
+				if mask&(1<<uint(n-1-i)) != 0 {
+					cols.UnionWith(elems[i])
+				}
+			}
+			res = append(res, cols)
+		}
+		return res
+
+	default:
+		var res opt.ColSetList
+		for _, e := range groupingSets.Exprs {
+			res = append(res, b.buildGroupingItem(e, selects, inScope, outScope)...)
+			checkGroupingSetsCount(len(res))
+		}
+		return res
+	}
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
@@ -372,10 +528,12 @@ func (b *Builder) buildGroupingList(
 // selects  The select expressions are needed in case the GROUP BY expression
 //          is an index into to the select list.
 //
+// buildGrouping returns the set of grouping columns for the expression.
+//
 // See Builder.buildStmt for a description of the remaining input values.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, inScope, outScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 
@@ -405,7 +563,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := inScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := inScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(int(col.id))
 			continue
 		}
 
@@ -415,7 +574,77 @@ func (b *Builder) buildGrouping(
 		col := b.addColumn(outScope, alias, e)
 		b.buildScalar(e, inScope, outScope, col, nil)
 		inScope.groupby.groupStrs[exprStr] = col
+		cols.Add(int(col.id))
 	}
+	return cols
+}
+
+// buildGroupingExpr builds a GROUPING(...) expression. Each argument must match
+// a grouping expression of the current query level. The result is a bit mask
+// in which the last argument corresponds to the lowest bit, and a bit is set
+// if its argument is not part of the grouping set of the current row. With
+// multiple grouping sets, the mask is computed from the grouping set column:
+//
+//   SELECT GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b)
+//   =>
+//   CASE grouping_set WHEN 0 THEN 0 WHEN 1 THEN 1 ELSE 3 END
+//
+func (b *Builder) buildGroupingExpr(
+	grouping *tree.GroupingExpr, inScope *scope, inGroupingContext bool, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	cols := make(opt.ColList, len(grouping.Exprs))
+	for i := range grouping.Exprs {
+		var col *scopeColumn
+		if inGroupingContext {
+			col = inScope.groupby.groupStrs[symbolicExprStr(grouping.TypedExprAt(i))]
+		}
+		if col == nil {
+			panic(pgerror.Newf(pgerror.CodeGroupingError,
+				"arguments to GROUPING must be grouping expressions of the associated query level"))
+		}
+		cols[i] = col.id
+	}
+
+	makeMask := func(set opt.ColSet) tree.DInt {
+		var mask tree.DInt
+		for i, col := range cols {
+			if !set.Contains(int(col)) {
+				mask |= 1 << uint(len(cols)-1-i)
+			}
+		}
+		return mask
+	}
+	makeConst := func(i tree.DInt) opt.ScalarExpr {
+		return b.factory.ConstructConstVal(tree.NewDInt(i), types.Int)
+	}
+
+	groupingSets := inScope.groupby.groupingSets
+	if groupingSets == nil {
+		// All the arguments are part of the only grouping set.
+		return makeConst(0)
+	}
+
+	// The last grouping set becomes the ELSE branch. Grouping sets with the
+	// same mask don't need a WHEN branch.
+	last := len(groupingSets) - 1
+	elseMask := makeMask(groupingSets[last])
+	whens := make(memo.ScalarListExpr, 0, last)
+	for i := 0; i < last; i++ {
+		if mask := makeMask(groupingSets[i]); mask != elseMask {
+			whens = append(whens, b.factory.ConstructWhen(makeConst(tree.DInt(i)), makeConst(mask)))
+		}
+	}
+	if len(whens) == 0 {
+		return makeConst(elseMask)
+	}
+
+	groupingSetCol := inScope.groupby.groupingSetCol
+	if colRefs != nil {
+		colRefs.Add(int(groupingSetCol))
+	}
+	return b.factory.ConstructCase(
+		b.factory.ConstructVariable(groupingSetCol), whens, makeConst(elseMask),
+	)
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -19,9 +19,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 )
@@ -155,7 +157,7 @@ func init() {
 // and thereby scrambles the input ordering.
 func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope) {
 	if ins.With != nil {
-		inScope = b.buildCTE(ins.With.CTEList, inScope)
+		inScope = b.buildCTE(ins.With.CTEList, ins.With.Recursive, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
@@ -285,7 +287,11 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		if mb.needExistingRows() {
 			// Left-join each input row to the target table, using conflict columns
 			// derived from the primary index as the join condition.
-			mb.buildInputForUpsert(inScope, mb.getPrimaryKeyColumnNames(), nil /* whereClause */)
+			mb.buildInputForUpsert(inScope, mb.tab.Index(cat.PrimaryIndex), nil /* whereClause */)
+
+			// BEFORE triggers can modify any column of the updated rows, so the
+			// columns which are not upserted are updated with their current value.
+			mb.addTriggerColsForUpsert()
 
 			// Add additional columns for computed expressions that may depend on any
 			// updated columns.
@@ -297,9 +303,9 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 
 	// Case 4: INSERT..ON CONFLICT..DO UPDATE statement.
 	default:
-		// Left-join each input row to the target table, using the conflict columns
-		// as the join condition.
-		mb.buildInputForUpsert(inScope, ins.OnConflict.Columns, ins.OnConflict.Where)
+		// Left-join each input row to the target table, using the columns of the
+		// arbiter index as the join condition.
+		mb.buildInputForUpsert(inScope, mb.findArbiterIndex(ins.OnConflict), ins.OnConflict.Where)
 
 		// Derive the columns that will be updated from the SET expressions.
 		mb.addTargetColsForUpdate(ins.OnConflict.Exprs)
@@ -324,6 +330,8 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //   2. All non-key columns (including mutation columns) have insert and update
 //      values specified for them.
 //   3. Each update value is the same as the corresponding insert value.
+//   4. There are no triggers, which need to know whether each row is
+//      inserted or updated, and the values of the updated rows.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -331,7 +339,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 // of edge cases (that caused real correctness bugs #13437 #13962). As a result,
 // this support was removed and needs to re-enabled. See #14482.
 func (mb *mutationBuilder) needExistingRows() bool {
-	if mb.tab.DeletableIndexCount() > 1 {
+	if mb.tab.DeletableIndexCount() > 1 || mb.tab.HasTriggers() {
 		return true
 	}
 
@@ -629,12 +637,12 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tree.OnConflict) {
 	// DO NOTHING clause does not require ON CONFLICT columns.
 	var conflictIndex cat.Index
-	if len(onConflict.Columns) != 0 {
+	if len(onConflict.Columns) != 0 || onConflict.Constraint != "" {
 		// Check that the ON CONFLICT columns reference at most one target row by
 		// ensuring they match columns of a UNIQUE index. Using LEFT OUTER JOIN
 		// to detect conflicts relies upon this being true (otherwise result
 		// cardinality could increase). This is also a Postgres requirement.
-		conflictIndex = mb.ensureUniqueConflictCols(onConflict.Columns)
+		conflictIndex = mb.findArbiterIndex(onConflict)
 	}
 
 	insertColSet := mb.outScope.expr.Relational().OutputCols
@@ -647,6 +655,13 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 			continue
 		}
 
+		// Partial unique indexes are not used to detect conflicts unless they
+		// are the arbiter index; a conflict on one of them is otherwise reported
+		// as a duplicate key error.
+		if _, isPartial := index.Predicate(); isPartial && conflictIndex != index {
+			continue
+		}
+
 		// If conflict columns were explicitly specified, then only check for a
 		// conflict on a single index. Otherwise, check on all indexes.
 		if conflictIndex != nil && conflictIndex != index {
@@ -687,6 +702,7 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 			)
 			on = append(on, memo.FiltersItem{Condition: condition})
 		}
+		on = append(on, mb.buildArbiterPredicate(index, scanScope)...)
 
 		// Construct the left join + filter.
 		// TODO(andyk): Convert this to use anti-join once we have support for
@@ -716,20 +732,14 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 }
 
 // buildInputForUpsert assumes that the output scope already contains the insert
-// columns. It left-joins each insert row to the target table, using the given
-// conflict columns as the join condition. It also selects one of the table
-// columns to be a "canary column" that can be tested to determine whether a
-// given insert row conflicts with an existing row in the table. If it is null,
-// then there is no conflict.
+// columns. It left-joins each insert row to the target table, using the key
+// columns of the given UNIQUE conflict index as the join condition. It also
+// selects one of the table columns to be a "canary column" that can be tested
+// to determine whether a given insert row conflicts with an existing row in the
+// table. If it is null, then there is no conflict.
 func (mb *mutationBuilder) buildInputForUpsert(
-	inScope *scope, conflictCols tree.NameList, whereClause *tree.Where,
+	inScope *scope, conflictIndex cat.Index, whereClause *tree.Where,
 ) {
-	// Check that the ON CONFLICT columns reference at most one target row.
-	// Using LEFT OUTER JOIN to detect conflicts relies upon this being true
-	// (otherwise result cardinality could increase). This is also a Postgres
-	// requirement.
-	mb.ensureUniqueConflictCols(conflictCols)
-
 	// Re-alias all INSERT columns so that they are accessible as if they were
 	// part of a special data source named "crdb_internal.excluded".
 	for i := range mb.outScope.cols {
@@ -770,20 +780,19 @@ func (mb *mutationBuilder) buildInputForUpsert(
 	//
 	//   ON ins.x = scan.a AND ins.y = scan.b
 	//
+	// Use lax key columns, which always contain the minimum columns that ensure
+	// uniqueness. If the conflict index is partial, then its predicate must
+	// hold for both rows as well.
 	var on memo.FiltersExpr
-	for _, name := range conflictCols {
-		for i := range fetchScope.cols {
-			fetchCol := &fetchScope.cols[i]
-			if fetchCol.name == name {
-				condition := mb.b.factory.ConstructEq(
-					mb.b.factory.ConstructVariable(mb.insertColID(i)),
-					mb.b.factory.ConstructVariable(fetchCol.id),
-				)
-				on = append(on, memo.FiltersItem{Condition: condition})
-				break
-			}
-		}
+	for i, n := 0, conflictIndex.LaxKeyColumnCount(); i < n; i++ {
+		ord := conflictIndex.Column(i).Ordinal
+		condition := mb.b.factory.ConstructEq(
+			mb.b.factory.ConstructVariable(mb.insertColID(ord)),
+			mb.b.factory.ConstructVariable(fetchScope.cols[ord].id),
+		)
+		on = append(on, memo.FiltersItem{Condition: condition})
 	}
+	on = append(on, mb.buildArbiterPredicate(conflictIndex, fetchScope)...)
 
 	// Construct the left join.
 	mb.outScope.expr = mb.b.factory.ConstructLeftJoin(
@@ -859,6 +868,27 @@ func (mb *mutationBuilder) setUpsertCols(insertCols tree.NameList) {
 	}
 }
 
+// addTriggerColsForUpsert sets the columns which are not updated by an UPSERT
+// statement with explicit target columns to be updated with their fetched
+// values, if the table has triggers. The BEFORE UPDATE triggers can then
+// modify them.
+func (mb *mutationBuilder) addTriggerColsForUpsert() {
+	if !mb.tab.HasTriggers() {
+		return
+	}
+	var pkOrds util.FastIntSet
+	primary := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		pkOrds.Add(primary.Column(i).Ordinal)
+	}
+	for ord, n := 0, mb.tab.ColumnCount(); ord < n; ord++ {
+		if mb.updateOrds[ord] != -1 || pkOrds.Contains(ord) || mb.tab.Column(ord).IsComputed() {
+			continue
+		}
+		mb.updateOrds[ord] = mb.fetchOrds[ord]
+	}
+}
+
 // buildUpsert constructs an Upsert operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
@@ -955,11 +985,43 @@ func (mb *mutationBuilder) projectUpsertColumns() {
 	mb.outScope = projectionsScope
 }
 
+// findArbiterIndex returns the UNIQUE index used to detect conflicts for the
+// given ON CONFLICT clause. It is either the unique constraint named by ON
+// CONSTRAINT, or an index matching the ON CONFLICT columns (see
+// ensureUniqueConflictCols).
+func (mb *mutationBuilder) findArbiterIndex(onConflict *tree.OnConflict) cat.Index {
+	if onConflict.Constraint == "" {
+		return mb.ensureUniqueConflictCols(onConflict.Columns, onConflict.ArbiterPredicate)
+	}
+
+	// Only the primary key and the unique indexes that are not partial are
+	// constraints.
+	for idx, idxCount := 0, mb.tab.IndexCount(); idx < idxCount; idx++ {
+		index := mb.tab.Index(idx)
+		if index.Name() != onConflict.Constraint || !index.IsUnique() {
+			continue
+		}
+		if _, isPartial := index.Predicate(); isPartial {
+			continue
+		}
+		return index
+	}
+	panic(sqlbase.NewUndefinedConstraintError(
+		string(onConflict.Constraint), mb.tab.Name().Table(),
+	))
+}
+
 // ensureUniqueConflictCols tries to prove that the given list of column names
 // correspond to the columns of at least one UNIQUE index on the target table.
 // If true, then ensureUniqueConflictCols returns the matching index. Otherwise,
 // it reports an error.
-func (mb *mutationBuilder) ensureUniqueConflictCols(cols tree.NameList) cat.Index {
+//
+// A partial UNIQUE index only matches if the given arbiter predicate implies
+// its predicate, and is only used if no other UNIQUE index matches.
+func (mb *mutationBuilder) ensureUniqueConflictCols(
+	cols tree.NameList, arbiterPredicate tree.Expr,
+) cat.Index {
+	var partialMatch cat.Index
 	for idx, idxCount := 0, mb.tab.IndexCount(); idx < idxCount; idx++ {
 		index := mb.tab.Index(idx)
 
@@ -978,22 +1040,71 @@ func (mb *mutationBuilder) ensureUniqueConflictCols(cols tree.NameList) cat.Inde
 				break
 			}
 		}
+		if !found {
+			continue
+		}
 
-		if found {
-			return index
+		// Partial indexes only enforce uniqueness among the rows that satisfy
+		// their predicate, so they can only be used if the arbiter predicate
+		// guarantees that the inserted rows satisfy it.
+		if pred, isPartial := index.Predicate(); isPartial {
+			if partialMatch == nil && arbiterPredicate != nil {
+				implied, err := sqlbase.ArbiterPredicateImplies(arbiterPredicate, pred)
+				if err != nil {
+					panic(builderError{err})
+				}
+				if implied {
+					partialMatch = index
+				}
+			}
+			continue
 		}
+
+		return index
+	}
+	if partialMatch != nil {
+		return partialMatch
 	}
 	panic(pgerror.Newf(pgerror.CodeInvalidColumnReferenceError,
 		"there is no unique or exclusion constraint matching the ON CONFLICT specification"))
 }
 
-// getPrimaryKeyColumnNames returns the names of all primary key columns in the
-// target table.
-func (mb *mutationBuilder) getPrimaryKeyColumnNames() tree.NameList {
-	pkIndex := mb.tab.Index(cat.PrimaryIndex)
-	names := make(tree.NameList, pkIndex.KeyColumnCount())
-	for i, n := 0, pkIndex.KeyColumnCount(); i < n; i++ {
-		names[i] = pkIndex.Column(i).ColName()
+// buildArbiterPredicate returns the filters that restrict the conflicts
+// detected on the given index to the rows of its predicate, if it is a
+// partial index. An insert row can only conflict with an existing row if
+// both satisfy the predicate. fetchScope contains the columns of the existing
+// rows.
+func (mb *mutationBuilder) buildArbiterPredicate(
+	index cat.Index, fetchScope *scope,
+) memo.FiltersExpr {
+	pred, isPartial := index.Predicate()
+	if !isPartial {
+		return nil
+	}
+
+	// Build a scope that exposes the insert columns under the names of the
+	// table columns.
+	insertScope := mb.b.allocScope()
+	for i, n := 0, mb.tab.ColumnCount(); i < n; i++ {
+		if mb.insertOrds[i] == -1 {
+			continue
+		}
+		col := mb.outScope.cols[mb.insertOrds[i]]
+		col.name = mb.tab.Column(i).ColName()
+		col.table = *mb.tab.Name()
+		insertScope.cols = append(insertScope.cols, col)
+	}
+
+	var filters memo.FiltersExpr
+	for _, s := range []*scope{insertScope, fetchScope} {
+		expr, err := parser.ParseExpr(pred)
+		if err != nil {
+			panic(builderError{err})
+		}
+		texpr := s.resolveAndRequireType(expr, types.Bool)
+		filters = append(filters, memo.FiltersItem{
+			Condition: mb.b.buildScalar(texpr, s, nil, nil, nil),
+		})
 	}
-	return names
+	return filters
 }
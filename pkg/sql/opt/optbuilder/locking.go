@@ -0,0 +1,154 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// lockingSpec maintains a collection of FOR [KEY] UPDATE/SHARE items that
+// apply to the data sources currently being built. A locking item either
+// applies to all data sources in the FROM clause of its SELECT, or only to
+// the data sources named in its OF list. In both cases it also applies to all
+// tables referenced by views and subqueries in the FROM clause, but not to
+// scalar subqueries or to WITH queries.
+//
+// As the builder descends into a FROM clause data source, the spec is
+// filtered down to the items that apply to that data source (see filter), so
+// by the time a table is scanned, every remaining item applies to it.
+type lockingSpec []*tree.LockingItem
+
+// isSet returns whether the spec contains any locking items.
+func (lm lockingSpec) isSet() bool {
+	return len(lm) != 0
+}
+
+// get returns the combined locking item that applies to a table scanned in
+// the current context, or nil if no locking applies. Only items without an
+// OF list apply; when there are several, the strongest strength and wait
+// policy are used.
+func (lm lockingSpec) get() *tree.LockingItem {
+	var res *tree.LockingItem
+	for _, li := range lm {
+		if len(li.Targets) != 0 {
+			continue
+		}
+		if res == nil {
+			res = li
+			continue
+		}
+		res = &tree.LockingItem{
+			Strength:   res.Strength.Max(li.Strength),
+			WaitPolicy: res.WaitPolicy.Max(li.WaitPolicy),
+		}
+	}
+	return res
+}
+
+// filter returns the subset of the spec that applies to the FROM clause data
+// source with the given alias. Items that name the data source in their OF
+// list apply to everything within it, so they are returned without targets.
+func (lm lockingSpec) filter(alias tree.Name) lockingSpec {
+	var res lockingSpec
+	for _, li := range lm {
+		if len(li.Targets) == 0 {
+			res = append(res, li)
+			continue
+		}
+		for i := range li.Targets {
+			if li.Targets[i].TableName == alias {
+				res = append(res, &tree.LockingItem{
+					Strength:   li.Strength,
+					WaitPolicy: li.WaitPolicy,
+				})
+				break
+			}
+		}
+	}
+	return res
+}
+
+// withLocking sets the locking spec of the builder to the given spec and
+// returns a function that restores the previous spec.
+func (b *Builder) withLocking(locking lockingSpec) (restore func()) {
+	prev := b.locking
+	b.locking = locking
+	return func() { b.locking = prev }
+}
+
+// validateLockingInSelectClause checks that the given SELECT clause can be
+// used with a row-level locking clause, and raises an error otherwise.
+// needsAgg indicates whether the clause performs an aggregation.
+func (b *Builder) validateLockingInSelectClause(
+	sel *tree.SelectClause, needsAgg bool, fromScope *scope,
+) {
+	if !b.locking.isSet() {
+		return
+	}
+	switch {
+	case sel.Distinct:
+		panic(notAllowedWithLockingErr(b.locking, "DISTINCT clause"))
+
+	case len(sel.GroupBy) > 0:
+		panic(notAllowedWithLockingErr(b.locking, "GROUP BY clause"))
+
+	case sel.Having != nil:
+		panic(notAllowedWithLockingErr(b.locking, "HAVING clause"))
+
+	case needsAgg:
+		panic(notAllowedWithLockingErr(b.locking, "aggregate functions"))
+
+	case len(fromScope.windows) > 0:
+		panic(notAllowedWithLockingErr(b.locking, "window functions"))
+
+	case len(fromScope.srfs) > 0:
+		panic(notAllowedWithLockingErr(b.locking, "set-returning functions in the target list"))
+	}
+}
+
+// validateLockingTargets checks that every table named in the OF list of a
+// locking item is a data source in the FROM clause. Items with an OF list
+// that are inherited from an enclosing query have already been resolved by
+// filter, so all remaining ones belong to the current SELECT.
+func (b *Builder) validateLockingTargets(fromScope *scope) {
+	for _, li := range b.locking {
+		for i := range li.Targets {
+			target := &li.Targets[i]
+			if target.ExplicitSchema || target.ExplicitCatalog {
+				panic(pgerror.Newf(pgerror.CodeSyntaxError,
+					"%s must specify unqualified relation names", li.Strength))
+			}
+			found := false
+			for j := range fromScope.cols {
+				if fromScope.cols[j].table.TableName == target.TableName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				panic(pgerror.Newf(pgerror.CodeUndefinedTableError,
+					"relation %q in %s clause not found in FROM clause",
+					tree.ErrString(&target.TableName), li.Strength))
+			}
+		}
+	}
+}
+
+// notAllowedWithLockingErr returns the error raised when a row-level locking
+// clause is used with a construct that doesn't produce rows of a single
+// table.
+func notAllowedWithLockingErr(locking lockingSpec, construct string) error {
+	return pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+		"%s is not allowed with %s", locking[0].Strength, construct)
+}
@@ -123,6 +123,13 @@ type mutationBuilder struct {
 }
 
 func (mb *mutationBuilder) init(b *Builder, op opt.Operator, tab cat.Table, alias tree.TableName) {
+	// The contents of a materialized view can only be changed by REFRESH
+	// MATERIALIZED VIEW.
+	if tab.IsMaterializedView() {
+		panic(builderError{pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+			"cannot change materialized view %q", tab.Name().Table())})
+	}
+
 	mb.b = b
 	mb.md = b.factory.Metadata()
 	mb.op = op
@@ -166,15 +173,24 @@ func (mb *mutationBuilder) insertColID(tabOrd int) opt.ColumnID {
 // the Update or Delete operator, similar to this:
 //
 //   SELECT <cols>
-//   FROM <table>
+//   FROM <table>, <from>
 //   WHERE <where>
 //   ORDER BY <order-by>
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+//
+// The tables in the FROM clause of an UPDATE (or the USING clause of a
+// DELETE) are inner joined with the target table. Their columns can be
+// referenced by the WHERE, ORDER BY and SET expressions, but are not fetched
+// from the target table. Since several rows of the FROM tables may join with
+// the same row of the target table, the join is followed by a DistinctOn on
+// the primary key of the target table, so that each row is updated or deleted
+// at most once. As in Postgres, it is unspecified which of the joined rows is
+// used to compute the new values in that case.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForUpdateOrDelete(
-	inScope *scope, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope, from tree.TableExprs, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
 ) {
 	// Fetch columns from different instance of the table metadata, so that it's
 	// possible to remap columns, as in this example:
@@ -191,10 +207,30 @@ func (mb *mutationBuilder) buildInputForUpdateOrDelete(
 		includeMutations,
 		inScope,
 	)
+	numFetchCols := len(mb.outScope.cols)
+
+	if len(from) > 0 {
+		fromScope := mb.b.buildFromTables(from, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.outScope, fromScope)
+
+		mb.outScope.appendColumnsFromScope(fromScope)
+
+		left := mb.outScope.expr.(memo.RelExpr)
+		right := fromScope.expr.(memo.RelExpr)
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(
+			left, right, memo.TrueFilter, memo.EmptyJoinPrivate,
+		)
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
 
+	if len(from) > 0 {
+		mb.buildDistinctOnPrimaryKey()
+	}
+
 	// SELECT + ORDER BY (which may add projected expressions)
 	projectionsScope := mb.outScope.replace()
 	projectionsScope.appendColumnsFromScope(mb.outScope)
@@ -209,12 +245,44 @@ func (mb *mutationBuilder) buildInputForUpdateOrDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	for i := range mb.outScope.cols {
+	// Set list of columns that will be fetched by the input expression. The
+	// columns of the target table come first in the scope.
+	for i := 0; i < numFetchCols; i++ {
 		mb.fetchOrds[i] = scopeOrdinal(i)
 	}
 }
 
+// buildDistinctOnPrimaryKey wraps the input expression in a DistinctOn
+// operator that groups on the primary key columns of the target table, so
+// that the input contains at most one row for each row of the target table.
+// All other columns, including those of any joined tables, keep the value of
+// an arbitrary row of their group. The target table columns must come first
+// in mb.outScope.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var pkCols opt.ColSet
+	primary := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		pkCols.Add(int(mb.outScope.cols[primary.Column(i).Ordinal].id))
+	}
+
+	// Build FirstAgg for all other columns (eliminating duplicates).
+	aggs := make(memo.AggregationsExpr, 0, len(mb.outScope.cols))
+	excluded := pkCols.Copy()
+	for i := range mb.outScope.cols {
+		if id := mb.outScope.cols[i].id; !excluded.Contains(int(id)) {
+			excluded.Add(int(id))
+			aggs = append(aggs, memo.AggregationsItem{
+				Agg:        mb.b.factory.ConstructFirstAgg(mb.b.factory.ConstructVariable(id)),
+				ColPrivate: memo.ColPrivate{Col: id},
+			})
+		}
+	}
+
+	private := memo.GroupingPrivate{GroupingCols: pkCols}
+	input := mb.outScope.expr.(memo.RelExpr)
+	mb.outScope.expr = mb.b.factory.ConstructDistinctOn(input, aggs, &private)
+}
+
 // addTargetColsByName adds one target column for each of the names in the given
 // list.
 func (mb *mutationBuilder) addTargetColsByName(names tree.NameList) {
@@ -482,8 +550,8 @@ func (mb *mutationBuilder) roundDecimalValues(scopeOrds []scopeOrdinal, roundCom
 // If an input decimal value has more than the required number of fractional
 // digits, it must be rounded before being inserted into these types.
 //
-// NOTE: CRDB does not allow nested array storage types, so only one level of
-// array nesting needs to be checked.
+// NOTE: only one level of array nesting is checked, so the elements of
+// multidimensional DECIMAL arrays are not rounded.
 func findRoundingFunction(typ *types.T, precision int) (*tree.FunctionProperties, *tree.Overload) {
 	if precision == 0 {
 		// Unlimited precision decimal target type never needs rounding.
@@ -506,8 +574,12 @@ func findRoundingFunction(typ *types.T, precision int) (*tree.FunctionProperties
 // addCheckConstraintCols synthesizes a boolean output column for each check
 // constraint defined on the target table. The mutation operator will report
 // a constraint violation error if the value of the column is false.
+//
+// The check constraints of tables with triggers are not synthesized, since
+// BEFORE triggers can modify the rows after the columns are computed. The
+// mutation operator evaluates them instead.
 func (mb *mutationBuilder) addCheckConstraintCols() {
-	if mb.tab.CheckCount() > 0 {
+	if mb.tab.CheckCount() > 0 && !mb.tab.HasTriggers() {
 		// Disambiguate names so that references in the constraint expression refer
 		// to the correct columns.
 		mb.disambiguateColumns()
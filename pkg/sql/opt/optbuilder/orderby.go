@@ -256,4 +256,7 @@ func ensureColumnOrderable(e tree.TypedExpr) {
 	if typ.Family() == types.JsonFamily {
 		panic(unimplementedWithIssueDetailf(32706, "", "can't order by column type jsonb"))
 	}
+	if f := typ.Family(); f == types.GeometryFamily || f == types.GeographyFamily {
+		panic(pgerror.Newf(pgerror.CodeFeatureNotSupportedError, "can't order by column type %s", typ))
+	}
 }
@@ -138,22 +138,21 @@ func (b *Builder) buildScalar(
 		out = b.factory.ConstructArrayFlatten(s.node, &subqueryPrivate)
 
 	case *tree.IndirectionExpr:
-		expr := b.buildScalar(t.Expr.(tree.TypedExpr), inScope, nil, nil, colRefs)
+		out = b.buildScalar(t.Expr.(tree.TypedExpr), inScope, nil, nil, colRefs)
 
-		if len(t.Indirection) != 1 {
-			panic(unimplementedWithIssueDetailf(32552, "ind", "multidimensional indexing is not supported"))
-		}
+		// Each subscript of a multidimensional indexing expression selects an
+		// element of the next dimension of the array.
+		for _, subscript := range t.Indirection {
+			if subscript.Slice {
+				panic(unimplementedWithIssueDetailf(32551, "", "array slicing is not supported"))
+			}
 
-		subscript := t.Indirection[0]
-		if subscript.Slice {
-			panic(unimplementedWithIssueDetailf(32551, "", "array slicing is not supported"))
+			out = b.factory.ConstructIndirection(
+				out,
+				b.buildScalar(subscript.Begin.(tree.TypedExpr), inScope, nil, nil, colRefs),
+			)
 		}
 
-		out = b.factory.ConstructIndirection(
-			expr,
-			b.buildScalar(subscript.Begin.(tree.TypedExpr), inScope, nil, nil, colRefs),
-		)
-
 	case *tree.IfErrExpr:
 		cond := b.buildScalar(t.Cond.(tree.TypedExpr), inScope, nil, nil, colRefs)
 
@@ -232,6 +231,9 @@ func (b *Builder) buildScalar(
 		}
 		out = b.factory.ConstructCoalesce(args)
 
+	case *tree.GroupingExpr:
+		out = b.buildGroupingExpr(t, inScope, inGroupingContext, colRefs)
+
 	case *tree.ColumnAccessExpr:
 		input := b.buildScalar(t.Expr.(tree.TypedExpr), inScope, nil, nil, colRefs)
 		out = b.factory.ConstructColumnAccess(input, memo.TupleOrdinal(t.ColIndex))
@@ -439,6 +441,15 @@ func (b *Builder) buildFunction(
 		panic(pgerror.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if def.UserDefined {
+		// User-defined functions are not tracked by the metadata, so the memo
+		// cannot be reused once they are replaced or dropped.
+		b.DisableMemoReuse = true
+		if body := f.ResolvedOverload().InlineBody; body != nil {
+			return b.buildInlinedFunction(f, body, inScope, outScope, outCol, colRefs)
+		}
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -460,6 +471,42 @@ func (b *Builder) buildFunction(
 	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
 }
 
+// buildInlinedFunction builds the body of a user-defined function in place of
+// the call f. The placeholders in the body are replaced with the arguments of
+// the call, cast to the types of the parameters, and the result is cast to the
+// return type of the function.
+func (b *Builder) buildInlinedFunction(
+	f *tree.FuncExpr,
+	body tree.Expr,
+	inScope, outScope *scope,
+	outCol *scopeColumn,
+	colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	paramTypes := f.ResolvedOverload().Types.Types()
+	expr, err := tree.SimpleVisit(body, func(e tree.Expr) (bool, tree.Expr, error) {
+		if p, ok := e.(*tree.Placeholder); ok {
+			arg := &tree.CastExpr{
+				Expr:       &tree.ParenExpr{Expr: f.Exprs[p.Idx]},
+				Type:       paramTypes[p.Idx],
+				SyntaxMode: tree.CastShort,
+			}
+			return false, arg, nil
+		}
+		return true, e, nil
+	})
+	if err != nil {
+		panic(builderError{err})
+	}
+	expr = &tree.CastExpr{
+		Expr:       &tree.ParenExpr{Expr: expr},
+		Type:       f.ResolvedType(),
+		SyntaxMode: tree.CastShort,
+	}
+	texpr := inScope.resolveAndRequireType(expr, f.ResolvedType())
+	out := b.buildScalar(texpr, inScope, nil, nil, colRefs)
+	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+}
+
 // buildRangeCond builds a RANGE clause as a simpler expression. Examples:
 // x BETWEEN a AND b                ->  x >= a AND x <= b
 // x NOT BETWEEN a AND b            ->  NOT (x >= a AND x <= b)
@@ -612,6 +659,8 @@ func (b *Builder) constructComparison(
 	case tree.ContainedBy:
 		// This is just syntatic sugar that reverses the operands.
 		return b.factory.ConstructContains(right, left)
+	case tree.Overlaps:
+		return b.factory.ConstructOverlaps(left, right)
 	case tree.JSONExists:
 		return b.factory.ConstructJsonExists(left, right)
 	case tree.JSONAllExists:
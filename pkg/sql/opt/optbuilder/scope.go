@@ -834,7 +834,7 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return false, colI.(*scopeColumn)
 
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := s.builder.semaCtx.ResolveFunction(&t.Func)
 		if err != nil {
 			panic(builderError{err})
 		}
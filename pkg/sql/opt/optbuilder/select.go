@@ -53,6 +53,16 @@ func (b *Builder) buildDataSource(
 			indexFlags = source.IndexFlags
 		}
 
+		// Restrict the row-level locking items to those that apply to this
+		// data source.
+		if b.locking.isSet() {
+			alias := source.As.Alias
+			if tn, ok := source.Expr.(*tree.TableName); ok && alias == "" {
+				alias = tn.TableName
+			}
+			defer b.withLocking(b.locking.filter(alias))()
+		}
+
 		outScope = b.buildDataSource(source.Expr, indexFlags, inScope)
 
 		if source.Ordinality {
@@ -89,10 +99,18 @@ func (b *Builder) buildDataSource(
 		ds, resName := b.resolveDataSource(tn, privilege.SELECT)
 		switch t := ds.(type) {
 		case cat.Table:
+			if b.locking.get() != nil && !b.skipSelectPrivilegeChecks {
+				// Locking rows requires the UPDATE privilege.
+				b.checkPrivilege(tn, t, privilege.UPDATE)
+			}
 			return b.buildScan(t, &resName, nil /* ordinals */, indexFlags, excludeMutations, inScope)
 		case cat.View:
 			return b.buildView(t, inScope)
 		case cat.Sequence:
+			if locking := b.locking.get(); locking != nil {
+				panic(pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+					"cannot lock rows in sequence %q", tree.ErrString(tn)))
+			}
 			return b.buildSequenceSelect(t, inScope)
 		default:
 			panic(pgerror.AssertionFailedf("unknown DataSource type %T", ds))
@@ -116,6 +134,7 @@ func (b *Builder) buildDataSource(
 		return outScope
 
 	case *tree.StatementSource:
+		defer b.withLocking(nil)()
 		outScope = b.buildStmt(source.Statement, nil /* desiredTypes */, inScope)
 		if len(outScope.cols) == 0 {
 			panic(pgerror.Newf(pgerror.CodeUndefinedColumnError,
@@ -350,15 +369,20 @@ func (b *Builder) buildScan(
 		})
 	}
 
+	locking := b.locking.get()
 	if tab.IsVirtualTable() {
 		if indexFlags != nil {
 			panic(pgerror.Newf(pgerror.CodeSyntaxError,
 				"index flags not allowed with virtual tables"))
 		}
+		if locking != nil {
+			panic(pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+				"cannot lock rows in virtual table %q", tree.ErrString(alias)))
+		}
 		private := memo.VirtualScanPrivate{Table: tabID, Cols: tabColIDs}
 		outScope.expr = b.factory.ConstructVirtualScan(&private)
 	} else {
-		private := memo.ScanPrivate{Table: tabID, Cols: tabColIDs}
+		private := memo.ScanPrivate{Table: tabID, Cols: tabColIDs, Locking: locking}
 
 		if indexFlags != nil {
 			private.Flags.NoIndexJoin = indexFlags.NoIndexJoin
@@ -387,6 +411,7 @@ func (b *Builder) buildScan(
 		}
 		outScope.expr = b.factory.ConstructScan(&private)
 		b.addCheckConstraintsToScan(outScope, tabID)
+		b.addPartialIndexPredicatesToScan(outScope, tabID)
 	}
 	return outScope
 }
@@ -420,6 +445,28 @@ func (b *Builder) addCheckConstraintsToScan(scope *scope, tabID opt.TableID) {
 	}
 }
 
+// addPartialIndexPredicatesToScan builds the predicates of the table's partial
+// indexes and adds them to the table metadata, so that the optimizer can
+// determine whether the filters of a query imply them.
+func (b *Builder) addPartialIndexPredicatesToScan(scope *scope, tabID opt.TableID) {
+	tabMeta := b.factory.Metadata().TableMeta(tabID)
+	tab := tabMeta.Table
+
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		pred, isPartial := tab.Index(i).Predicate()
+		if !isPartial {
+			continue
+		}
+		expr, err := parser.ParseExpr(pred)
+		if err != nil {
+			panic(builderError{err})
+		}
+
+		texpr := scope.resolveAndRequireType(expr, types.Bool)
+		tabMeta.AddPartialIndexPredicate(i, b.buildScalar(texpr, scope, nil, nil, nil))
+	}
+}
+
 func (b *Builder) buildSequenceSelect(seq cat.Sequence, inScope *scope) (outScope *scope) {
 	tn := seq.SequenceName()
 	md := b.factory.Metadata()
@@ -471,12 +518,19 @@ func (b *Builder) buildWithOrdinality(colName string, inScope *scope) (outScope
 	return inScope
 }
 
-func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
+func (b *Builder) buildCTE(
+	ctes []*tree.CTE, recursive bool, inScope *scope,
+) (outScope *scope) {
 	outScope = inScope.push()
 
 	outScope.ctes = make(map[string]*cteSource)
 	for i := range ctes {
-		cteScope := b.buildStmt(ctes[i].Stmt, nil /* desiredTypes */, outScope)
+		var cteScope *scope
+		if recursive {
+			cteScope = b.buildRecursiveCTE(ctes[i], outScope)
+		} else {
+			cteScope = b.buildStmt(ctes[i].Stmt, nil /* desiredTypes */, outScope)
+		}
 		cols := cteScope.cols
 		name := ctes[i].Name.Alias
 
@@ -523,6 +577,310 @@ func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
 	return outScope
 }
 
+// buildRecursiveCTE builds a CTE that is part of a WITH RECURSIVE clause. A
+// recursive CTE must have the form:
+//
+//   <initial query> UNION [ALL] <recursive query>
+//
+// where only the recursive query refers to the CTE itself. The result is a
+// RecursiveCTE operator with the initial and recursive queries as inputs; the
+// self-reference inside the recursive query is built as a WorkTable operator.
+//
+// CTEs inside a WITH RECURSIVE clause that don't refer to themselves are built
+// as regular CTEs.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildRecursiveCTE(cte *tree.CTE, inScope *scope) (outScope *scope) {
+	name := cte.Name.Alias
+
+	// Whether the CTE is recursive is decided before building anything, since
+	// building marks the CTEs it refers to as used and they can't be built a
+	// second time.
+	if !cteReferences(cte.Stmt, name) {
+		// The CTE doesn't refer to itself, so build it as a regular CTE.
+		return b.buildStmt(cte.Stmt, nil /* desiredTypes */, inScope)
+	}
+	sel, ok := cte.Stmt.(*tree.Select)
+	var union *tree.UnionClause
+	if ok {
+		union, ok = sel.Select.(*tree.UnionClause)
+	}
+	if !ok || union.Type != tree.UnionOp {
+		panic(pgerror.Newf(
+			pgerror.CodeInvalidRecursionError,
+			"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+			name,
+		))
+	}
+	switch {
+	case sel.With != nil:
+		panic(pgerror.Newf(pgerror.CodeFeatureNotSupportedError, "WITH in a recursive query is not supported"))
+	case sel.OrderBy != nil:
+		panic(pgerror.Newf(pgerror.CodeFeatureNotSupportedError, "ORDER BY in a recursive query is not supported"))
+	case sel.Limit != nil:
+		panic(pgerror.Newf(pgerror.CodeFeatureNotSupportedError, "LIMIT in a recursive query is not supported"))
+	}
+	if cteReferences(union.Left, name) {
+		panic(pgerror.Newf(
+			pgerror.CodeInvalidRecursionError,
+			"recursive reference to query %q must not appear within its non-recursive term",
+			name,
+		))
+	}
+
+	// Build the initial query; it cannot refer to the CTE.
+	initialScope := b.buildSelect(union.Left, nil /* desiredTypes */, inScope)
+	initialScope.removeHiddenCols()
+
+	if cte.Name.Cols != nil && len(cte.Name.Cols) != len(initialScope.cols) {
+		panic(pgerror.Newf(
+			pgerror.CodeInvalidColumnReferenceError,
+			"source %q has %d columns available but %d columns specified",
+			name, len(initialScope.cols), len(cte.Name.Cols),
+		))
+	}
+	tableName := tree.MakeUnqualifiedTableName(name)
+	colName := func(i int) string {
+		if cte.Name.Cols != nil {
+			return string(cte.Name.Cols[i])
+		}
+		return string(initialScope.cols[i].name)
+	}
+
+	// Synthesize the columns of the working table, which is how the recursive
+	// query refers to the CTE. The working table has the same columns (and
+	// types) as the initial query.
+	withID := b.factory.Metadata().NextWithID()
+	workScope := inScope.push()
+	for i := range initialScope.cols {
+		col := b.synthesizeColumn(workScope, colName(i), initialScope.cols[i].typ, nil, nil /* scalar */)
+		col.table = tableName
+	}
+	workTable := &cteSource{
+		name: cte.Name,
+		cols: workScope.cols,
+		expr: b.factory.ConstructWorkTable(&memo.WorkTablePrivate{
+			Name:   name.String(),
+			WithID: withID,
+			Cols:   colsToColList(workScope.cols),
+		}),
+	}
+
+	recursiveScope := inScope.push()
+	recursiveScope.ctes = map[string]*cteSource{name.String(): workTable}
+	recursiveScope = b.buildSelect(union.Right, nil /* desiredTypes */, recursiveScope)
+	recursiveScope.removeHiddenCols()
+
+	if !workTable.used {
+		panic(pgerror.AssertionFailedf("recursive query %q does not use its working table", name))
+	}
+
+	if len(initialScope.cols) != len(recursiveScope.cols) {
+		panic(pgerror.Newf(
+			pgerror.CodeSyntaxError,
+			"each %v query must have the same number of columns: %d vs %d",
+			union.Type, len(initialScope.cols), len(recursiveScope.cols),
+		))
+	}
+
+	// The type of each column is determined by the initial query; the
+	// recursive query must produce matching types.
+	propagateTypes := false
+	for i := range initialScope.cols {
+		initialTyp := initialScope.cols[i].typ
+		recursiveTyp := recursiveScope.cols[i].typ
+		if recursiveTyp.Family() == types.UnknownFamily {
+			propagateTypes = true
+			continue
+		}
+		if !initialTyp.Equivalent(recursiveTyp) {
+			panic(pgerror.Newf(
+				pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				name, i+1, initialTyp, recursiveTyp,
+			))
+		}
+	}
+	if propagateTypes {
+		recursiveScope = b.propagateTypes(recursiveScope, initialScope)
+	}
+
+	// Synthesize the output columns; they contain values from both the initial
+	// and recursive queries.
+	outScope = inScope.push()
+	for i := range initialScope.cols {
+		col := b.synthesizeColumn(outScope, colName(i), initialScope.cols[i].typ, nil, nil /* scalar */)
+		col.table = tableName
+	}
+
+	private := memo.RecursiveCTEPrivate{
+		Name:          name.String(),
+		WithID:        withID,
+		InitialCols:   colsToColList(initialScope.cols),
+		RecursiveCols: colsToColList(recursiveScope.cols),
+		OutCols:       colsToColList(outScope.cols),
+		Deduplicate:   !union.All,
+	}
+	outScope.expr = b.factory.ConstructRecursiveCTE(initialScope.expr, recursiveScope.expr, &private)
+
+	telemetry.Inc(sqltelemetry.RecursiveCteUseCounter)
+
+	return outScope
+}
+
+// cteReferences returns whether the statement refers to the CTE with the
+// given name, ignoring the references to CTEs that shadow it.
+func cteReferences(stmt tree.Statement, name tree.Name) bool {
+	v := cteRefVisitor{name: name.String()}
+	v.walkStmt(stmt)
+	return v.found
+}
+
+// cteRefVisitor looks for references to a CTE in a statement. The statement
+// walker in the tree package only visits expressions, so cteRefVisitor walks
+// the data sources of SELECT statements itself.
+type cteRefVisitor struct {
+	name  string
+	found bool
+}
+
+var _ tree.Visitor = &cteRefVisitor{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *cteRefVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.found {
+		return false, expr
+	}
+	if sub, ok := expr.(*tree.Subquery); ok {
+		v.walkStmt(sub.Select)
+		return false, expr
+	}
+	return true, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *cteRefVisitor) VisitPost(expr tree.Expr) tree.Expr {
+	return expr
+}
+
+func (v *cteRefVisitor) walkExpr(expr tree.Expr) {
+	if expr != nil {
+		tree.WalkExprConst(v, expr)
+	}
+}
+
+func (v *cteRefVisitor) walkStmt(stmt tree.Statement) {
+	switch t := stmt.(type) {
+	case *tree.Select:
+		if t.With != nil {
+			shadowed := false
+			for _, cte := range t.With.CTEList {
+				v.walkStmt(cte.Stmt)
+				shadowed = shadowed || cte.Name.Alias.String() == v.name
+			}
+			if shadowed {
+				return
+			}
+		}
+		v.walkStmt(t.Select)
+		for _, o := range t.OrderBy {
+			v.walkExpr(o.Expr)
+		}
+		if t.Limit != nil {
+			v.walkExpr(t.Limit.Count)
+			v.walkExpr(t.Limit.Offset)
+		}
+
+	case *tree.ParenSelect:
+		v.walkStmt(t.Select)
+
+	case *tree.UnionClause:
+		v.walkStmt(t.Left)
+		v.walkStmt(t.Right)
+
+	case *tree.ValuesClause:
+		for _, row := range t.Rows {
+			for _, expr := range row {
+				v.walkExpr(expr)
+			}
+		}
+
+	case *tree.SelectClause:
+		for _, expr := range t.Exprs {
+			v.walkExpr(expr.Expr)
+		}
+		if t.From != nil {
+			for _, source := range t.From.Tables {
+				v.walkTableExpr(source)
+			}
+		}
+		if t.Where != nil {
+			v.walkExpr(t.Where.Expr)
+		}
+		for _, expr := range t.GroupBy {
+			v.walkExpr(expr)
+		}
+		if t.Having != nil {
+			v.walkExpr(t.Having.Expr)
+		}
+		for _, def := range t.Window {
+			v.walkWindowDef(def)
+		}
+	}
+}
+
+// walkWindowDef walks a window definition of a WINDOW clause. Definitions
+// inline in window function calls are walked along with their expressions.
+func (v *cteRefVisitor) walkWindowDef(def *tree.WindowDef) {
+	for _, expr := range def.Partitions {
+		v.walkExpr(expr)
+	}
+	for _, o := range def.OrderBy {
+		v.walkExpr(o.Expr)
+	}
+	if def.Frame != nil {
+		for _, bound := range []*tree.WindowFrameBound{
+			def.Frame.Bounds.StartBound, def.Frame.Bounds.EndBound,
+		} {
+			if bound != nil {
+				v.walkExpr(bound.OffsetExpr)
+			}
+		}
+	}
+}
+
+func (v *cteRefVisitor) walkTableExpr(source tree.TableExpr) {
+	switch t := source.(type) {
+	case *tree.AliasedTableExpr:
+		v.walkTableExpr(t.Expr)
+
+	case *tree.ParenTableExpr:
+		v.walkTableExpr(t.Expr)
+
+	case *tree.JoinTableExpr:
+		v.walkTableExpr(t.Left)
+		v.walkTableExpr(t.Right)
+		if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+			v.walkExpr(on.Expr)
+		}
+
+	case *tree.TableName:
+		v.found = v.found || t.String() == v.name
+
+	case *tree.Subquery:
+		v.walkStmt(t.Select)
+
+	case *tree.StatementSource:
+		v.walkStmt(t.Statement)
+
+	case *tree.RowsFromExpr:
+		for _, expr := range t.Items {
+			v.walkExpr(expr)
+		}
+	}
+}
+
 // checkCTEUsage ensures that a CTE that contains a mutation (like INSERT) is
 // used at least once by the query. Otherwise, it might not be executed.
 func (b *Builder) checkCTEUsage(inScope *scope) {
@@ -573,6 +931,7 @@ func (b *Builder) buildSelect(
 	orderBy := stmt.OrderBy
 	limit := stmt.Limit
 	with := stmt.With
+	locking := stmt.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		stmt = s.Select
@@ -602,22 +961,42 @@ func (b *Builder) buildSelect(
 			}
 			limit = stmt.Limit
 		}
+		if stmt.Locking != nil {
+			locking = append(locking[:len(locking):len(locking)], stmt.Locking...)
+		}
 	}
 
 	if with != nil {
-		inScope = b.buildCTE(with.CTEList, inScope)
+		// Row-level locking clauses don't apply to WITH queries.
+		restore := b.withLocking(nil)
+		inScope = b.buildCTE(with.CTEList, with.Recursive, inScope)
+		restore()
 		defer b.checkCTEUsage(inScope)
 	}
 
+	if locking != nil {
+		spec := make(lockingSpec, 0, len(b.locking)+len(locking))
+		spec = append(spec, b.locking...)
+		spec = append(spec, locking...)
+		defer b.withLocking(spec)()
+	}
+
 	// NB: The case statements are sorted lexicographically.
 	switch t := stmt.Select.(type) {
 	case *tree.SelectClause:
 		outScope = b.buildSelectClause(t, orderBy, desiredTypes, inScope)
 
 	case *tree.UnionClause:
+		if b.locking.isSet() {
+			panic(notAllowedWithLockingErr(b.locking, "UNION/INTERSECT/EXCEPT"))
+		}
 		outScope = b.buildUnion(t, desiredTypes, inScope)
 
 	case *tree.ValuesClause:
+		if b.locking.isSet() {
+			panic(pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+				"%s cannot be applied to VALUES", b.locking[0].Strength))
+		}
 		outScope = b.buildValuesClause(t, desiredTypes, inScope)
 
 	default:
@@ -659,6 +1038,7 @@ func (b *Builder) buildSelectClause(
 	sel *tree.SelectClause, orderBy tree.OrderBy, desiredTypes []*types.T, inScope *scope,
 ) (outScope *scope) {
 	fromScope := b.buildFrom(sel.From, inScope)
+	b.validateLockingTargets(fromScope)
 	b.processWindowDefs(sel, fromScope)
 	b.buildWhere(sel.Where, fromScope)
 
@@ -686,6 +1066,7 @@ func (b *Builder) buildSelectClause(
 		groupingCols = b.buildGroupingColumns(sel, fromScope)
 		having = b.buildHaving(havingExpr, fromScope)
 	}
+	b.validateLockingInSelectClause(sel, needsAgg, fromScope)
 
 	b.buildProjectionList(fromScope, projectionsScope)
 	b.buildOrderBy(fromScope, projectionsScope, orderByScope)
@@ -215,6 +215,10 @@ func (s *subquery) buildSubquery(desiredTypes []*types.T) {
 	defer func() { s.scope.builder.subquery = outer }()
 	s.scope.builder.subquery = s
 
+	// Row-level locking clauses of enclosing queries don't apply to scalar
+	// subqueries.
+	defer s.scope.builder.withLocking(nil)()
+
 	outScope := s.scope.builder.buildStmt(s.Subquery.Select, desiredTypes, s.scope)
 	ord := outScope.ordering
 
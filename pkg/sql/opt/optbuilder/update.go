@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
 // buildUpdate builds a memo group for an UpdateOp expression. First, an input
@@ -75,7 +76,7 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	}
 
 	if upd.With != nil {
-		inScope = b.buildCTE(upd.With.CTEList, inScope)
+		inScope = b.buildCTE(upd.With.CTEList, upd.With.Recursive, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
@@ -99,11 +100,11 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	// Build the input expression that selects the rows that will be updated:
 	//
 	//   WITH <with>
-	//   SELECT <cols> FROM <table> WHERE <where>
+	//   SELECT <cols> FROM <table>, <from> WHERE <where>
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the update table will be projected.
-	mb.buildInputForUpdateOrDelete(inScope, upd.Where, upd.Limit, upd.OrderBy)
+	mb.buildInputForUpdateOrDelete(inScope, upd.From, upd.Where, upd.Limit, upd.OrderBy)
 
 	// Derive the columns that will be updated from the SET expressions.
 	mb.addTargetColsForUpdate(upd.Exprs)
@@ -279,6 +280,25 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 		}
 	}
 
+	// BEFORE triggers can modify any column of the updated rows, so the columns
+	// which are not assigned by the statement are assigned their current value.
+	if mb.tab.HasTriggers() {
+		var pkCols util.FastIntSet
+		primary := mb.tab.Index(cat.PrimaryIndex)
+		for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+			pkCols.Add(primary.Column(i).Ordinal)
+		}
+		for ord, n := 0, mb.tab.ColumnCount(); ord < n; ord++ {
+			if mb.updateOrds[ord] != -1 || pkCols.Contains(ord) || mb.tab.Column(ord).IsComputed() {
+				continue
+			}
+			fetchCol := &inScope.cols[mb.fetchOrds[ord]]
+			scopeCol := mb.b.addColumn(projectionsScope, "" /* alias */, fetchCol)
+			mb.b.buildScalar(fetchCol, inScope, projectionsScope, scopeCol, nil)
+			checkCol(scopeCol, scopeOrdinal(len(projectionsScope.cols)-1), mb.tabID.ColumnID(ord))
+		}
+	}
+
 	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
 	mb.outScope = projectionsScope
 
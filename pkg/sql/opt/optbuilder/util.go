@@ -13,11 +13,13 @@
 package optbuilder
 
 import (
+
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -405,8 +407,9 @@ func (b *Builder) resolveSchemaForCreate(name *tree.TableName) (cat.Schema, cat.
 		panic(builderError{err})
 	}
 
-	// Only allow creation of objects in the public schema.
-	if resName.Schema() != tree.PublicSchema {
+	// Objects cannot be created in the virtual schemas.
+	switch resName.Schema() {
+	case sessiondata.PgCatalogName, "information_schema", "crdb_internal":
 		panic(pgerror.Newf(pgerror.CodeInvalidNameError,
 			"schema cannot be modified: %q", tree.ErrString(&resName)))
 	}
@@ -148,10 +148,12 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"ColumnID":       {fullName: "opt.ColumnID", passByVal: true},
 		"ColSet":         {fullName: "opt.ColSet", passByVal: true},
 		"ColList":        {fullName: "opt.ColList", passByVal: true},
+		"ColSetList":     {fullName: "opt.ColSetList", passByVal: true},
 		"TableID":        {fullName: "opt.TableID", passByVal: true},
 		"SchemaID":       {fullName: "opt.SchemaID", passByVal: true},
 		"SequenceID":     {fullName: "opt.SequenceID", passByVal: true},
 		"ValuesID":       {fullName: "opt.ValuesID", passByVal: true},
+		"WithID":         {fullName: "opt.WithID", passByVal: true},
 		"Ordering":       {fullName: "opt.Ordering", passByVal: true},
 		"OrderingChoice": {fullName: "physical.OrderingChoice", passByVal: true},
 		"TupleOrdinal":   {fullName: "memo.TupleOrdinal", passByVal: true},
@@ -173,6 +175,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"Constraint":     {fullName: "*constraint.Constraint", isPointer: true, usePointerIntern: true},
 		"FuncProps":      {fullName: "*tree.FunctionProperties", isPointer: true, usePointerIntern: true},
 		"FuncOverload":   {fullName: "*tree.Overload", isPointer: true, usePointerIntern: true},
+		"Locking":        {fullName: "*tree.LockingItem", isPointer: true, usePointerIntern: true},
 		"PhysProps":      {fullName: "*physical.Required", isPointer: true},
 		"Presentation":   {fullName: "physical.Presentation", passByVal: true},
 		"RelProps":       {fullName: "props.Relational"},
@@ -100,6 +100,18 @@ func distinctOnBuildProvided(expr memo.RelExpr, required *physical.OrderingChoic
 	return trimProvided(d.Input.ProvidedPhysical().Ordering, required, &d.Relational().FuncDeps)
 }
 
+func groupingSetsBuildChildReqOrdering(
+	parent memo.RelExpr, required *physical.OrderingChoice, childIdx int,
+) physical.OrderingChoice {
+	if childIdx != 0 {
+		return physical.OrderingChoice{}
+	}
+	// GroupingSets always uses a hash table, so it can't take advantage of an
+	// ordering on the grouping columns. It only requires the ordering in its
+	// private.
+	return parent.(*memo.GroupingSetsExpr).Ordering
+}
+
 // StreamingGroupingColOrdering returns an ordering on grouping columns that is
 // guaranteed on the input of an aggregation operator. This ordering can be used
 // perform a streaming aggregation.
@@ -142,6 +142,12 @@ type TableMeta struct {
 	// in certain queries. See comment above GenerateConstrainedScans for more
 	// detail.
 	constraints []ScalarExpr
+
+	// partialIndexPredicates maps the ordinals of the table's partial indexes
+	// to their predicates, stored in the ScalarExpr form so they can be
+	// compared against the filters of a query. See comment above
+	// GenerateConstrainedScans for more detail.
+	partialIndexPredicates map[int]ScalarExpr
 }
 
 // clearAnnotations resets all the table annotations; used when copying a
@@ -196,6 +202,23 @@ func (tm *TableMeta) AddConstraint(constraint ScalarExpr) {
 	tm.constraints = append(tm.constraints, constraint)
 }
 
+// PartialIndexPredicate returns the predicate of the partial index with the
+// given ordinal, or false if the index is not partial or its predicate has not
+// been added to the table's metadata.
+func (tm *TableMeta) PartialIndexPredicate(indexOrd int) (ScalarExpr, bool) {
+	pred, ok := tm.partialIndexPredicates[indexOrd]
+	return pred, ok
+}
+
+// AddPartialIndexPredicate adds the predicate of the partial index with the
+// given ordinal to the table's metadata.
+func (tm *TableMeta) AddPartialIndexPredicate(indexOrd int, pred ScalarExpr) {
+	if tm.partialIndexPredicates == nil {
+		tm.partialIndexPredicates = make(map[int]ScalarExpr)
+	}
+	tm.partialIndexPredicates[indexOrd] = pred
+}
+
 // TableAnnotation returns the given annotation that is associated with the
 // given table. If the table has no such annotation, TableAnnotation returns
 // nil.
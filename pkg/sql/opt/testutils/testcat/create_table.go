@@ -365,6 +365,9 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 		IdxZone:  &config.ZoneConfig{},
 		table:    tt,
 	}
+	if def.Predicate != nil {
+		idx.IdxPredicate = tree.Serialize(def.Predicate)
+	}
 
 	// Look for name suffixes indicating this is a mutation index.
 	if name, ok := extractWriteOnlyIndex(def); ok {
@@ -545,6 +545,16 @@ func (tt *Table) IsVirtualTable() bool {
 	return tt.IsVirtual
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (tt *Table) IsMaterializedView() bool {
+	return false
+}
+
+// HasTriggers is part of the cat.Table interface.
+func (tt *Table) HasTriggers() bool {
+	return false
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (tt *Table) IsInterleaved() bool {
 	return false
@@ -676,6 +686,10 @@ type Index struct {
 	// Inverted is true when this index is an inverted index.
 	Inverted bool
 
+	// IdxPredicate is the serialized predicate of a partial index, or empty if
+	// the index is not partial.
+	IdxPredicate string
+
 	Columns []cat.IndexColumn
 
 	// IdxZone is the zone associated with the index. This may be inherited from
@@ -711,6 +725,11 @@ func (ti *Index) IsInverted() bool {
 	return ti.Inverted
 }
 
+// Predicate is part of the cat.Index interface.
+func (ti *Index) Predicate() (string, bool) {
+	return ti.IdxPredicate, ti.IdxPredicate != ""
+}
+
 // ColumnCount is part of the cat.Index interface.
 func (ti *Index) ColumnCount() int {
 	return len(ti.Columns)
@@ -777,24 +796,22 @@ func (tc *Column) DatumType() *types.T {
 
 // ColTypePrecision is part of the cat.Column interface.
 func (tc *Column) ColTypePrecision() int {
-	if tc.ColType.Family() == types.ArrayFamily {
-		if tc.ColType.ArrayContents().Family() == types.ArrayFamily {
-			panic(pgerror.AssertionFailedf("column type should never be a nested array"))
-		}
-		return int(tc.ColType.ArrayContents().Precision())
+	// The precision of an array column is the precision of its element type.
+	typ := tc.ColType
+	for typ.Family() == types.ArrayFamily {
+		typ = typ.ArrayContents()
 	}
-	return int(tc.ColType.Precision())
+	return int(typ.Precision())
 }
 
 // ColTypeWidth is part of the cat.Column interface.
 func (tc *Column) ColTypeWidth() int {
-	if tc.ColType.Family() == types.ArrayFamily {
-		if tc.ColType.ArrayContents().Family() == types.ArrayFamily {
-			panic(pgerror.AssertionFailedf("column type should never be a nested array"))
-		}
-		return int(tc.ColType.ArrayContents().Width())
+	// The width of an array column is the width of its element type.
+	typ := tc.ColType
+	for typ.Family() == types.ArrayFamily {
+		typ = typ.ArrayContents()
 	}
-	return int(tc.ColType.Width())
+	return int(typ.Width())
 }
 
 // ColTypeStr is part of the cat.Column interface.
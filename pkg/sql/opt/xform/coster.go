@@ -188,6 +188,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 	case opt.GroupByOp, opt.ScalarGroupByOp, opt.DistinctOnOp:
 		cost = c.computeGroupingCost(candidate, required)
 
+	case opt.GroupingSetsOp:
+		cost = c.computeGroupingSetsCost(candidate.(*memo.GroupingSetsExpr))
+
 	case opt.LimitOp:
 		cost = c.computeLimitCost(candidate.(*memo.LimitExpr))
 
@@ -479,6 +482,23 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 	return cost
 }
 
+func (c *coster) computeGroupingSetsCost(groupingSets *memo.GroupingSetsExpr) memo.Cost {
+	// Add the CPU cost of emitting the rows.
+	cost := memo.Cost(groupingSets.Relational().Stats.RowCount) * cpuCostFactor
+
+	// Each input row is aggregated once per grouping set, using a hash table.
+	inputRowCount := groupingSets.Input.Relational().Stats.RowCount
+	aggsCount := len(groupingSets.Aggregations)
+	for _, set := range groupingSets.Sets {
+		perRowCost := memo.Cost(aggsCount + set.Len())
+		if !set.Empty() {
+			perRowCost++
+		}
+		cost += memo.Cost(inputRowCount) * perRowCost * cpuCostFactor
+	}
+	return cost
+}
+
 func (c *coster) computeLimitCost(limit *memo.LimitExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
 	cost := memo.Cost(limit.Relational().Stats.RowCount) * cpuCostFactor
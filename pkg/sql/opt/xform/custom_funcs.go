@@ -250,6 +250,12 @@ func (c *CustomFuncs) checkConstraintFilters(tabID opt.TableID) memo.FiltersExpr
 // GenerateConstrainedScans will further constrain the enumerated index scans
 // by trying to use the check constraints that apply to the table being
 // scanned.
+//
+// Partial indexes are only considered when the explicit filters imply the
+// index predicate, since otherwise the index may not contain all of the rows
+// that satisfy the filters. A partial index that cannot be constrained by the
+// filters still generates an unconstrained Scan over the index, because it
+// may contain far fewer rows than the table.
 func (c *CustomFuncs) GenerateConstrainedScans(
 	grp memo.RelExpr, scanPrivate *memo.ScanPrivate, explicitFilters memo.FiltersExpr,
 ) {
@@ -262,15 +268,26 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	// Consider the checkFilters as well to constrain each of the indexes.
 	filters := append(explicitFilters, checkFilters...)
 
-	// Iterate over all indexes.
+	// Iterate over all indexes, including partial indexes.
 	var iter scanIndexIter
 	iter.init(c.e.mem, scanPrivate)
+	iter.includePartial = true
 	for iter.next() {
+		_, isPartial := iter.index.Predicate()
+		if isPartial && !c.partialIndexPredicateImplied(
+			explicitFilters, scanPrivate.Table, iter.indexOrdinal,
+		) {
+			continue
+		}
+
 		// Check whether the filter can constrain the index.
 		constraintFilters, remainingFilters, ok := c.tryConstrainIndex(
 			filters, scanPrivate.Table, iter.indexOrdinal, false /* isInverted */)
 		if !ok {
-			continue
+			if !isPartial {
+				continue
+			}
+			constraintFilters, remainingFilters = nil, explicitFilters
 		}
 
 		// If a check constraint filter wasn't able to constrain the index, it
@@ -366,8 +383,8 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		newScanPrivate.Index = iter.indexOrdinal
 		newScanPrivate.Constraint = constraint
 
-		// Though the index is marked as containing the JSONB column being
-		// indexed, it doesn't actually, and it's only valid to extract the
+		// Though the index is marked as containing the JSONB or array column
+		// being indexed, it doesn't actually, and it's only valid to extract the
 		// primary key columns from it.
 		newScanPrivate.Cols = sb.primaryKeyCols()
 
@@ -378,6 +395,14 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		// correct columns, but it's difficult to tell at this point.
 		sb.setScan(&newScanPrivate)
 
+		// An array is stored under one key for each of its elements, so a scan
+		// of several keys of an array index can return the same row more than
+		// once. Unlike JSON containment spans, which never overlap in this way,
+		// these duplicates need to be removed.
+		if constraint.Spans.Count() > 1 && iter.index.Column(0).DatumType().Family() == types.ArrayFamily {
+			sb.addDistinct()
+		}
+
 		// If remaining filter exists, split it into one part that can be pushed
 		// below the IndexJoin, and one part that needs to stay above.
 		remaining = sb.addSelectAfterSplit(remaining, newScanPrivate.Cols)
@@ -388,6 +413,65 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 	}
 }
 
+// partialIndexPredicateImplied returns true if the given filters imply the
+// predicate of the partial index with the given ordinal. Each conjunct of the
+// predicate must either be identical to one of the filter conditions, or have
+// a tight constraint that contains the constraint derived from one of the
+// filter conditions. For example, the filter "a > 5" implies the predicate
+// "a > 0".
+func (c *CustomFuncs) partialIndexPredicateImplied(
+	filters memo.FiltersExpr, tabID opt.TableID, indexOrd int,
+) bool {
+	pred, ok := c.e.mem.Metadata().TableMeta(tabID).PartialIndexPredicate(indexOrd)
+	if !ok {
+		return false
+	}
+
+	var conjunctImplied func(conjunct opt.ScalarExpr) bool
+	conjunctImplied = func(conjunct opt.ScalarExpr) bool {
+		if and, ok := conjunct.(*memo.AndExpr); ok {
+			return conjunctImplied(and.Left) && conjunctImplied(and.Right)
+		}
+		for i := range filters {
+			if filters[i].Condition == conjunct {
+				return true
+			}
+		}
+
+		item := memo.FiltersItem{Condition: conjunct}
+		predProps := item.ScalarProps(c.e.mem)
+		if !predProps.TightConstraints || predProps.Constraints == nil ||
+			predProps.Constraints.Length() != 1 {
+			return false
+		}
+		predConstraint := predProps.Constraints.Constraint(0)
+		for i := range filters {
+			filterProps := filters[i].ScalarProps(c.e.mem)
+			if filterProps.Constraints == nil {
+				continue
+			}
+			for j, n := 0, filterProps.Constraints.Length(); j < n; j++ {
+				filterConstraint := filterProps.Constraints.Constraint(j)
+				if !filterConstraint.Columns.Equals(&predConstraint.Columns) {
+					continue
+				}
+				contained := true
+				for k, m := 0, filterConstraint.Spans.Count(); k < m; k++ {
+					if !predConstraint.ContainsSpan(c.e.evalCtx, filterConstraint.Spans.Get(k)) {
+						contained = false
+						break
+					}
+				}
+				if contained {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return conjunctImplied(pred)
+}
+
 func (c *CustomFuncs) initIdxConstraintForIndex(
 	filters memo.FiltersExpr, tabID opt.TableID, indexOrd int, isInverted bool,
 ) (ic *idxconstraint.Instance) {
@@ -814,6 +898,7 @@ func (c *CustomFuncs) GenerateLookupJoins(
 		lookupJoin.JoinType = joinType
 		lookupJoin.Table = scanPrivate.Table
 		lookupJoin.Index = iter.indexOrdinal
+		lookupJoin.Locking = scanPrivate.Locking
 
 		// Find the longest prefix of index key columns that are equality columns.
 		numIndexKeyCols := iter.index.LaxKeyColumnCount()
@@ -904,6 +989,7 @@ func (c *CustomFuncs) GenerateLookupJoins(
 		indexJoin.Table = scanPrivate.Table
 		indexJoin.Index = cat.PrimaryIndex
 		indexJoin.KeyCols = pkCols
+		indexJoin.Locking = scanPrivate.Locking
 		indexJoin.Cols = scanPrivate.Cols.Union(inputProps.OutputCols)
 
 		// Create the LookupJoin for the index join in the same group.
@@ -1032,8 +1118,9 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 	grp memo.RelExpr, scanPrivate *memo.ScanPrivate, filters memo.FiltersExpr,
 ) {
 
-	// Short circuit unless zigzag joins are explicitly enabled.
-	if !c.e.evalCtx.SessionData.ZigzagJoinEnabled {
+	// Short circuit unless zigzag joins are explicitly enabled. Zigzag joins
+	// don't support row-level locking.
+	if !c.e.evalCtx.SessionData.ZigzagJoinEnabled || scanPrivate.Locking != nil {
 		return
 	}
 
@@ -1256,8 +1343,9 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 func (c *CustomFuncs) GenerateInvertedIndexZigzagJoins(
 	grp memo.RelExpr, scanPrivate *memo.ScanPrivate, filters memo.FiltersExpr,
 ) {
-	// Short circuit unless zigzag joins are explicitly enabled.
-	if !c.e.evalCtx.SessionData.ZigzagJoinEnabled {
+	// Short circuit unless zigzag joins are explicitly enabled. Zigzag joins
+	// don't support row-level locking.
+	if !c.e.evalCtx.SessionData.ZigzagJoinEnabled || scanPrivate.Locking != nil {
 		return
 	}
 
@@ -1569,6 +1657,11 @@ type scanIndexIter struct {
 	indexOrdinal int
 	index        cat.Index
 	cols         opt.ColSet
+
+	// includePartial is true if next should also enumerate partial indexes.
+	// Callers that set it are responsible for checking that the query filters
+	// imply the predicate of each partial index they use.
+	includePartial bool
 }
 
 func (it *scanIndexIter) init(mem *memo.Memo, scanPrivate *memo.ScanPrivate) {
@@ -1581,10 +1674,11 @@ func (it *scanIndexIter) init(mem *memo.Memo, scanPrivate *memo.ScanPrivate) {
 
 // next advances iteration to the next index of the Scan operator's table. This
 // is the primary index if it's the first time next is called, or a secondary
-// index thereafter. Inverted index are skipped. If the ForceIndex flag is set,
-// then all indexes except the forced index are skipped. When there are no more
-// indexes to enumerate, next returns false. The current index is accessible via
-// the iterator's "index" field.
+// index thereafter. Inverted index are skipped, as are partial indexes unless
+// includePartial is set. If the ForceIndex flag is set, then all indexes except
+// the forced index are skipped. When there are no more indexes to enumerate,
+// next returns false. The current index is accessible via the iterator's
+// "index" field.
 func (it *scanIndexIter) next() bool {
 	for {
 		it.indexOrdinal++
@@ -1596,6 +1690,9 @@ func (it *scanIndexIter) next() bool {
 		if it.index.IsInverted() {
 			continue
 		}
+		if _, isPartial := it.index.Predicate(); isPartial && !it.includePartial {
+			continue
+		}
 		if it.scanPrivate.Flags.ForceIndex && it.scanPrivate.Flags.Index != it.indexOrdinal {
 			// If we are forcing a specific index, ignore the others.
 			continue
@@ -45,6 +45,7 @@ type indexScanBuilder struct {
 	tabID            opt.TableID
 	pkCols           opt.ColSet
 	scanPrivate      memo.ScanPrivate
+	distinct         bool
 	innerFilters     memo.FiltersExpr
 	outerFilters     memo.FiltersExpr
 	indexJoinPrivate memo.IndexJoinPrivate
@@ -74,11 +75,23 @@ func (b *indexScanBuilder) primaryKeyCols() opt.ColSet {
 // makes a copy of scanPrivate so that it doesn't escape.
 func (b *indexScanBuilder) setScan(scanPrivate *memo.ScanPrivate) {
 	b.scanPrivate = *scanPrivate
+	b.distinct = false
 	b.innerFilters = nil
 	b.outerFilters = nil
 	b.indexJoinPrivate = memo.IndexJoinPrivate{}
 }
 
+// addDistinct wraps the Scan expression with a DistinctOn expression that
+// removes duplicate primary keys. This is needed for scans over inverted
+// indexes that can return the same row under several of their keys. It must
+// be called before any filters or index joins are added.
+func (b *indexScanBuilder) addDistinct() {
+	if b.innerFilters != nil || b.indexJoinPrivate.Table != 0 {
+		panic(pgerror.AssertionFailedf("cannot add distinct after a filter or index join has been added"))
+	}
+	b.distinct = true
+}
+
 // addSelect wraps the input expression with a Select expression having the
 // given filter.
 func (b *indexScanBuilder) addSelect(filters memo.FiltersExpr) {
@@ -138,8 +151,9 @@ func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
 		panic(pgerror.AssertionFailedf("cannot add index join after an outer filter has been added"))
 	}
 	b.indexJoinPrivate = memo.IndexJoinPrivate{
-		Table: b.tabID,
-		Cols:  cols,
+		Table:   b.tabID,
+		Cols:    cols,
+		Locking: b.scanPrivate.Locking,
 	}
 }
 
@@ -147,13 +161,29 @@ func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
 // expressions that were specified by previous calls to various add methods.
 func (b *indexScanBuilder) build(grp memo.RelExpr) {
 	// 1. Only scan.
-	if len(b.innerFilters) == 0 && b.indexJoinPrivate.Table == 0 {
+	if len(b.innerFilters) == 0 && b.indexJoinPrivate.Table == 0 && !b.distinct {
 		b.mem.AddScanToGroup(&memo.ScanExpr{ScanPrivate: b.scanPrivate}, grp)
 		return
 	}
 
-	// 2. Wrap scan in inner filter if it was added.
+	// 2. Wrap scan in distinct if it was added.
 	input := b.f.ConstructScan(&b.scanPrivate)
+	if b.distinct {
+		private := memo.GroupingPrivate{GroupingCols: b.primaryKeyCols()}
+		if len(b.innerFilters) == 0 && b.indexJoinPrivate.Table == 0 {
+			distinct := &memo.DistinctOnExpr{
+				Input:           input,
+				Aggregations:    memo.EmptyAggregationsExpr,
+				GroupingPrivate: private,
+			}
+			b.mem.AddDistinctOnToGroup(distinct, grp)
+			return
+		}
+
+		input = b.f.ConstructDistinctOn(input, memo.EmptyAggregationsExpr, &private)
+	}
+
+	// 3. Wrap input in inner filter if it was added.
 	if len(b.innerFilters) != 0 {
 		if b.indexJoinPrivate.Table == 0 {
 			b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.innerFilters}, grp)
@@ -163,7 +193,7 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructSelect(input, b.innerFilters)
 	}
 
-	// 3. Wrap input in index join if it was added.
+	// 4. Wrap input in index join if it was added.
 	if b.indexJoinPrivate.Table != 0 {
 		if len(b.outerFilters) == 0 {
 			indexJoin := &memo.IndexJoinExpr{Input: input, IndexJoinPrivate: b.indexJoinPrivate}
@@ -174,10 +204,10 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructIndexJoin(input, &b.indexJoinPrivate)
 	}
 
-	// 4. Wrap input in outer filter (which must exist at this point).
+	// 5. Wrap input in outer filter (which must exist at this point).
 	if len(b.outerFilters) == 0 {
-		// indexJoinDef == 0: outerFilters == 0 handled by #1 and #2 above.
-		// indexJoinDef != 0: outerFilters == 0 handled by #3 above.
+		// indexJoinDef == 0: outerFilters == 0 handled by #1, #2 and #3 above.
+		// indexJoinDef != 0: outerFilters == 0 handled by #4 above.
 		panic(pgerror.AssertionFailedf("outer filter cannot be 0 at this point"))
 	}
 	b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.outerFilters}, grp)
@@ -82,18 +82,35 @@ type optSchema struct {
 	planner *planner
 	desc    *sqlbase.DatabaseDescriptor
 
+	// schemaDesc is the descriptor of the schema if it is a user-defined
+	// schema, and nil otherwise.
+	schemaDesc *sqlbase.SchemaDescriptor
+
 	name cat.SchemaName
 }
 
 // ID is part of the cat.Object interface.
 func (os *optSchema) ID() cat.StableID {
+	if os.schemaDesc != nil {
+		return cat.StableID(os.schemaDesc.ID)
+	}
 	return cat.StableID(os.desc.ID)
 }
 
 // Equals is part of the cat.Object interface.
 func (os *optSchema) Equals(other cat.Object) bool {
 	otherSchema, ok := other.(*optSchema)
-	return ok && os.desc.ID == otherSchema.desc.ID
+	return ok && os.ID() == otherSchema.ID()
+}
+
+// privilegeDesc returns the descriptor holding the privileges on the schema:
+// the schema descriptor for user-defined schemas, and the database descriptor
+// otherwise.
+func (os *optSchema) privilegeDesc() sqlbase.DescriptorProto {
+	if os.schemaDesc != nil {
+		return os.schemaDesc
+	}
+	return os.desc
 }
 
 // Name is part of the cat.Schema interface.
@@ -147,10 +164,19 @@ func (oc *optCatalog) ResolveSchema(
 			pgerror.CodeInvalidSchemaNameError, "target database or schema does not exist",
 		)
 	}
+	dbDesc := desc.(*DatabaseDescriptor)
+	var scDesc *sqlbase.SchemaDescriptor
+	if !isVirtualSchemaName(oc.tn.Schema()) {
+		scDesc, err = getSchemaDescByName(ctx, oc.planner.Txn(), dbDesc.ID, oc.tn.Schema())
+		if err != nil {
+			return nil, cat.SchemaName{}, err
+		}
+	}
 	return &optSchema{
-		planner: oc.planner,
-		desc:    desc.(*DatabaseDescriptor),
-		name:    oc.tn.TableNamePrefix,
+		planner:    oc.planner,
+		desc:       dbDesc,
+		schemaDesc: scDesc,
+		name:       oc.tn.TableNamePrefix,
 	}, oc.tn.TableNamePrefix, nil
 }
 
@@ -204,7 +230,7 @@ func (oc *optCatalog) ResolveDataSourceByID(
 func (oc *optCatalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	switch t := o.(type) {
 	case *optSchema:
-		return oc.planner.CheckPrivilege(ctx, t.desc, priv)
+		return oc.planner.CheckPrivilege(ctx, t.privilegeDesc(), priv)
 	case *optTable:
 		return oc.planner.CheckPrivilege(ctx, t.desc, priv)
 	case *optView:
@@ -220,7 +246,7 @@ func (oc *optCatalog) CheckPrivilege(ctx context.Context, o cat.Object, priv pri
 func (oc *optCatalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error {
 	switch t := o.(type) {
 	case *optSchema:
-		return oc.planner.CheckAnyPrivilege(ctx, t.desc)
+		return oc.planner.CheckAnyPrivilege(ctx, t.privilegeDesc())
 	case *optTable:
 		return oc.planner.CheckAnyPrivilege(ctx, t.desc)
 	case *optView:
@@ -245,8 +271,9 @@ func (oc *optCatalog) dataSourceForDesc(
 	desc *sqlbase.ImmutableTableDescriptor,
 	name *cat.DataSourceName,
 ) (cat.DataSource, error) {
-	if desc.IsTable() {
-		// Tables require invalidation logic for cached wrappers.
+	if desc.IsTable() || desc.MaterializedView() {
+		// Tables and materialized views require invalidation logic for cached
+		// wrappers.
 		return oc.dataSourceForTable(ctx, flags, desc, name)
 	}
 
@@ -714,6 +741,16 @@ func (ot *optTable) IsVirtualTable() bool {
 	return ot.desc.IsVirtualTable()
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optTable) IsMaterializedView() bool {
+	return ot.desc.MaterializedView()
+}
+
+// HasTriggers is part of the cat.Table interface.
+func (ot *optTable) HasTriggers() bool {
+	return len(ot.desc.Triggers) > 0
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (ot *optTable) IsInterleaved() bool {
 	return ot.desc.IsInterleaved()
@@ -935,6 +972,11 @@ func (oi *optIndex) IsInverted() bool {
 	return oi.desc.Type == sqlbase.IndexDescriptor_INVERTED
 }
 
+// Predicate is part of the cat.Index interface.
+func (oi *optIndex) Predicate() (string, bool) {
+	return oi.desc.Predicate, oi.desc.IsPartial()
+}
+
 // ColumnCount is part of the cat.Index interface.
 func (oi *optIndex) ColumnCount() int {
 	return oi.numCols
@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -74,6 +75,7 @@ func (ef *execFactory) ConstructScan(
 	reverse bool,
 	maxResults uint64,
 	reqOrdering exec.OutputOrdering,
+	locking *tree.LockingItem,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	indexDesc := index.(*optIndex).desc
@@ -101,6 +103,9 @@ func (ef *execFactory) ConstructScan(
 	scan.reverse = reverse
 	scan.maxResults = maxResults
 	scan.parallelScansEnabled = sqlbase.ParallelScans.Get(&ef.planner.extendedEvalCtx.Settings.SV)
+	if err := ef.initScanLocking(scan, locking); err != nil {
+		return nil, err
+	}
 	var err error
 	scan.spans, err = spansFromConstraint(
 		tabDesc,
@@ -433,6 +438,28 @@ func (ef *execFactory) ConstructGroupBy(
 	return n, nil
 }
 
+// ConstructGroupingSets is part of the exec.Factory interface.
+func (ef *execFactory) ConstructGroupingSets(
+	input exec.Node,
+	groupCols []exec.ColumnOrdinal,
+	groupingSets []exec.ColumnOrdinalSet,
+	aggregations []exec.AggInfo,
+) (exec.Node, error) {
+	node, err := ef.ConstructGroupBy(
+		input, groupCols, nil /* groupColOrdering */, aggregations, nil, /* reqOrdering */
+	)
+	if err != nil {
+		return nil, err
+	}
+	n := node.(*groupNode)
+	n.groupingSets = make([][]int, len(groupingSets))
+	for i := range groupingSets {
+		n.groupingSets[i] = groupingSets[i].Ordered()
+	}
+	n.columns = append(n.columns, sqlbase.ResultColumn{Name: "grouping_set", Typ: types.Int})
+	return n, nil
+}
+
 func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo) error {
 	inputCols := planColumns(n.plan)
 	for i := range aggregations {
@@ -542,7 +569,11 @@ func (ef *execFactory) ConstructOrdinality(input exec.Node, colName string) (exe
 
 // ConstructIndexJoin is part of the exec.Factory interface.
 func (ef *execFactory) ConstructIndexJoin(
-	input exec.Node, table cat.Table, cols exec.ColumnOrdinalSet, reqOrdering exec.OutputOrdering,
+	input exec.Node,
+	table cat.Table,
+	cols exec.ColumnOrdinalSet,
+	reqOrdering exec.OutputOrdering,
+	locking *tree.LockingItem,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	colCfg := makeScanColumnsConfig(table, cols)
@@ -577,6 +608,9 @@ func (ef *execFactory) ConstructIndexJoin(
 	tableScan.index = &primaryIndex
 	tableScan.isSecondaryIndex = false
 	tableScan.disableBatchLimit()
+	if err := ef.initScanLocking(tableScan, locking); err != nil {
+		return nil, err
+	}
 
 	primaryKeyColumns, colIDtoRowIndex := processIndexJoinColumns(tableScan, scan)
 	primaryKeyPrefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(tabDesc.TableDesc(), tableScan.index.ID))
@@ -607,6 +641,7 @@ func (ef *execFactory) ConstructLookupJoin(
 	lookupCols exec.ColumnOrdinalSet,
 	onCond tree.TypedExpr,
 	reqOrdering exec.OutputOrdering,
+	locking *tree.LockingItem,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	indexDesc := index.(*optIndex).desc
@@ -619,6 +654,9 @@ func (ef *execFactory) ConstructLookupJoin(
 
 	tableScan.index = indexDesc
 	tableScan.isSecondaryIndex = (indexDesc != &tabDesc.PrimaryIndex)
+	if err := ef.initScanLocking(tableScan, locking); err != nil {
+		return nil, err
+	}
 
 	n := &lookupJoinNode{
 		input:    input.(planNode),
@@ -643,6 +681,31 @@ func (ef *execFactory) ConstructLookupJoin(
 	return n, nil
 }
 
+// initScanLocking configures the given scanNode to acquire row-level locks
+// according to the given locking item, if any.
+func (ef *execFactory) initScanLocking(scan *scanNode, locking *tree.LockingItem) error {
+	if locking == nil {
+		return nil
+	}
+	if ef.planner.EvalContext().TxnReadOnly {
+		return pgerror.Newf(pgerror.CodeReadOnlySQLTransactionError,
+			"cannot execute %s in a read-only transaction", locking.Strength)
+	}
+	strength := sqlbase.ToScanLockingStrength(locking.Strength)
+	waitPolicy := sqlbase.ToScanLockingWaitPolicy(locking.WaitPolicy)
+	if strength.IsExclusive() || waitPolicy != sqlbase.ScanLockingWaitPolicy_BLOCK {
+		if !ef.planner.ExecCfg().Settings.Version.IsActive(cluster.VersionRowLevelLocking) {
+			return pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+				"%s requires all nodes to be upgraded to %s",
+				tree.AsString(&tree.LockingItem{Strength: locking.Strength, WaitPolicy: locking.WaitPolicy}),
+				cluster.VersionByKey(cluster.VersionRowLevelLocking))
+		}
+	}
+	scan.lockingStrength = strength
+	scan.lockingWaitPolicy = waitPolicy
+	return nil
+}
+
 // Helper function to create a scanNode from just a table / index descriptor
 // and requested cols.
 func (ef *execFactory) constructScanForZigzag(
@@ -779,6 +842,26 @@ func (ef *execFactory) ConstructMax1Row(input exec.Node) (exec.Node, error) {
 	}, nil
 }
 
+// ConstructRecursiveCTE is part of the exec.Factory interface.
+func (ef *execFactory) ConstructRecursiveCTE(
+	initial exec.Node, fn exec.RecursiveCTEIterationFn, label string, deduplicate bool,
+) (exec.Node, error) {
+	return &recursiveCTENode{
+		initial:        initial.(planNode),
+		genIterationFn: fn,
+		label:          label,
+		deduplicate:    deduplicate,
+	}, nil
+}
+
+// ConstructScanBuffer is part of the exec.Factory interface.
+func (ef *execFactory) ConstructScanBuffer(ref exec.Node, label string) (exec.Node, error) {
+	return &scanBufferNode{
+		buffer: ref.(*bufferNode),
+		label:  label,
+	}, nil
+}
+
 // ConstructProjectSet is part of the exec.Factory interface.
 func (ef *execFactory) ConstructProjectSet(
 	n exec.Node, exprs tree.TypedExprs, zipCols sqlbase.ResultColumns, numColsPerGen []int,
@@ -1153,7 +1236,10 @@ func (ef *execFactory) ConstructInsert(
 	colDescs := makeColDescList(table, insertCols)
 
 	// Construct the check helper if there are any check constraints.
-	checkHelper := sqlbase.NewInputCheckHelper(checks, tabDesc)
+	checkHelper, err := ef.makeCheckHelper(tabDesc, checks)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine the foreign key tables involved in the update.
 	fkTables, err := ef.makeFkMetadata(tabDesc, row.CheckInserts, checkHelper)
@@ -1163,7 +1249,7 @@ func (ef *execFactory) ConstructInsert(
 
 	// Create the table insert, which does the bulk of the work.
 	ri, err := row.MakeInserter(ef.planner.txn, tabDesc, fkTables, colDescs,
-		row.CheckFKs, &ef.planner.alloc)
+		row.CheckFKs, ef.planner.EvalContext(), &ef.planner.alloc)
 	if err != nil {
 		return nil, err
 	}
@@ -1228,7 +1314,10 @@ func (ef *execFactory) ConstructUpdate(
 	}
 
 	// Construct the check helper if there are any check constraints.
-	checkHelper := sqlbase.NewInputCheckHelper(checks, tabDesc)
+	checkHelper, err := ef.makeCheckHelper(tabDesc, checks)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine the foreign key tables involved in the update.
 	fkTables, err := row.MakeFkMetadata(
@@ -1314,6 +1403,21 @@ func (ef *execFactory) ConstructUpdate(
 	return &rowCountNode{source: upd}, nil
 }
 
+// makeCheckHelper returns the helper which verifies the check constraints of
+// the given table, if there are any. The optimizer does not compute the check
+// constraints of tables with triggers, so these are evaluated by the
+// helper.
+func (ef *execFactory) makeCheckHelper(
+	tabDesc *sqlbase.ImmutableTableDescriptor, checks exec.CheckOrdinalSet,
+) (*sqlbase.CheckHelper, error) {
+	if len(tabDesc.Triggers) > 0 {
+		return sqlbase.NewEvalCheckHelper(
+			ef.planner.extendedEvalCtx.Context, ef.planner.analyzeExpr, tabDesc,
+		)
+	}
+	return sqlbase.NewInputCheckHelper(checks, tabDesc), nil
+}
+
 func (ef *execFactory) makeFkMetadata(
 	tabDesc *sqlbase.ImmutableTableDescriptor,
 	fkCheckType row.FKCheckType,
@@ -1348,7 +1452,10 @@ func (ef *execFactory) ConstructUpsert(
 	updateColDescs := makeColDescList(table, updateCols)
 
 	// Construct the check helper if there are any check constraints.
-	checkHelper := sqlbase.NewInputCheckHelper(checks, tabDesc)
+	checkHelper, err := ef.makeCheckHelper(tabDesc, checks)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine the foreign key tables involved in the upsert.
 	fkTables, err := ef.makeFkMetadata(tabDesc, row.CheckUpdates, checkHelper)
@@ -1356,9 +1463,16 @@ func (ef *execFactory) ConstructUpsert(
 		return nil, err
 	}
 
+	// The check constraints of tables with triggers are verified by the
+	// upserter, once the BEFORE triggers have modified the rows.
+	var triggerCheckHelper *sqlbase.CheckHelper
+	if len(tabDesc.Triggers) > 0 {
+		checkHelper, triggerCheckHelper = nil, checkHelper
+	}
+
 	// Create the table inserter, which does the bulk of the insert-related work.
 	ri, err := row.MakeInserter(ef.planner.txn, tabDesc, fkTables, insertColDescs,
-		row.CheckFKs, &ef.planner.alloc)
+		row.CheckFKs, ef.planner.EvalContext(), &ef.planner.alloc)
 	if err != nil {
 		return nil, err
 	}
@@ -1409,8 +1523,9 @@ func (ef *execFactory) ConstructUpsert(
 		source:  input.(planNode),
 		columns: returnCols,
 		run: upsertRun{
-			checkHelper: checkHelper,
-			insertCols:  ri.InsertCols,
+			checkHelper:   checkHelper,
+			triggerEvents: tree.TriggerInsert | tree.TriggerUpdate,
+			insertCols:    ri.InsertCols,
 			iVarContainerForComputedCols: sqlbase.RowIndexedVarContainer{
 				Cols:    tabDesc.Columns,
 				Mapping: ri.InsertColIDtoRowIndex,
@@ -1420,6 +1535,7 @@ func (ef *execFactory) ConstructUpsert(
 					ri:          ri,
 					alloc:       &ef.planner.alloc,
 					collectRows: rowsNeeded,
+					checkHelper: triggerCheckHelper,
 				},
 				canaryOrdinal: int(canaryCol),
 				fkTables:      fkTables,
@@ -377,7 +377,9 @@ func (p *planner) propagateFilters(
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
+	case *refreshMatViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -392,6 +394,11 @@ func (p *planner) propagateFilters(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createFunctionNode:
+	case *createTriggerNode:
+	case *createSchemaNode:
+	case *createDomainNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *deleteRangeNode:
 	case *dropDatabaseNode:
@@ -399,6 +406,10 @@ func (p *planner) propagateFilters(
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
+	case *dropTriggerNode:
+	case *dropSchemaNode:
+	case *dropTypeNode:
 	case *DropUserNode:
 	case *hookFnNode:
 	case *valuesNode:
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -106,7 +107,15 @@ func (p *planner) selectIndex(
 	}
 
 	candidates := make([]*indexInfo, 0, len(s.desc.Indexes)+1)
-	if s.specifiedIndex != nil {
+	if s.specifiedIndex != nil && s.specifiedIndex.IsPartial() {
+		// The heuristic planner doesn't reason about partial index predicates,
+		// so it can't tell whether the requested index contains all the rows
+		// that are needed. Scan the primary index instead.
+		candidates = append(candidates, &indexInfo{
+			desc:  s.desc,
+			index: &s.desc.PrimaryIndex,
+		})
+	} else if s.specifiedIndex != nil {
 		// An explicit secondary index was requested. Only add it to the candidate
 		// indexes list.
 		candidates = append(candidates, &indexInfo{
@@ -120,6 +129,10 @@ func (p *planner) selectIndex(
 			index: &s.desc.PrimaryIndex,
 		})
 		for i := range s.desc.Indexes {
+			if s.desc.Indexes[i].IsPartial() {
+				// Partial indexes don't contain all the rows of the table.
+				continue
+			}
 			candidates = append(candidates, &indexInfo{
 				desc:  s.desc,
 				index: &s.desc.Indexes[i],
@@ -162,10 +175,13 @@ func (p *planner) selectIndex(
 	}
 
 	// Remove any inverted indexes that don't generate any spans, a full-scan of
-	// an inverted index is always invalid.
+	// an inverted index is always invalid. Also remove inverted indexes on
+	// arrays that generate more than one span: such scans can return the same
+	// row several times, and there is no way to remove the duplicates here.
 	for i := 0; i < len(candidates); {
 		c := candidates[i].ic.Constraint()
-		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED && (c == nil || c.IsUnconstrained()) {
+		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED &&
+			(c == nil || c.IsUnconstrained() || (c.Spans.Count() > 1 && candidates[i].isArrayIndex())) {
 			candidates[i] = candidates[len(candidates)-1]
 			candidates = candidates[:len(candidates)-1]
 		} else {
@@ -409,6 +425,12 @@ func (v *indexInfo) analyzeOrdering(
 	}
 }
 
+// isArrayIndex returns true if the first column of the index is an array.
+func (v *indexInfo) isArrayIndex() bool {
+	col, err := v.desc.FindColumnByID(v.index.ColumnIDs[0])
+	return err == nil && col.Type.Family() == types.ArrayFamily
+}
+
 // isCoveringIndex returns true if all of the columns needed from the scanNode are contained within
 // the index. This allows a scan of only the index to be performed without requiring subsequent
 // lookup of the full row.
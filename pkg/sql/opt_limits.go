@@ -208,8 +208,10 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *deleteRangeNode:
+	case *refreshMatViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -224,12 +226,21 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createFunctionNode:
+	case *createTriggerNode:
+	case *createSchemaNode:
+	case *createDomainNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
+	case *dropTriggerNode:
+	case *dropSchemaNode:
+	case *dropTypeNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -243,7 +254,7 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *scatterNode:
 	case *scanBufferNode:
 
-	case *applyJoinNode, *lookupJoinNode, *zigzagJoinNode, *saveTableNode:
+	case *applyJoinNode, *lookupJoinNode, *zigzagJoinNode, *saveTableNode, *recursiveCTENode:
 		// These nodes are only planned by the optimizer.
 
 	default:
@@ -60,6 +60,10 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER VIEW blah RENAME ??`, `ALTER VIEW`},
 		{`ALTER VIEW blah RENAME TO blih ??`, `ALTER VIEW`},
 
+		{`ALTER TYPE ??`, `ALTER TYPE`},
+		{`ALTER TYPE t ??`, `ALTER TYPE`},
+		{`ALTER TYPE t ADD VALUE 'a' ??`, `ALTER TYPE`},
+
 		{`ALTER SEQUENCE IF ??`, `ALTER SEQUENCE`},
 		{`ALTER SEQUENCE blah ??`, `ALTER SEQUENCE`},
 		{`ALTER SEQUENCE blah RENAME ??`, `ALTER SEQUENCE`},
@@ -105,12 +109,28 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE ROLE bleh ??`, `CREATE ROLE`},
 
 		{`CREATE VIEW blah (??`, `CREATE VIEW`},
+		{`CREATE MATERIALIZED VIEW blah (??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS (SELECT c FROM x) ??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ??`, `SELECT`},
 		{`CREATE VIEW blah AS (??`, `<SELECTCLAUSE>`},
 
 		{`CREATE SEQUENCE ??`, `CREATE SEQUENCE`},
 
+		{`CREATE SCHEMA ??`, `CREATE SCHEMA`},
+		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
+
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION f(??`, `CREATE FUNCTION`},
+
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER t BEFORE ??`, `CREATE TRIGGER`},
+
+		{`CREATE TYPE ??`, `CREATE TYPE`},
+		{`CREATE TYPE t AS ENUM ('a' ??`, `CREATE TYPE`},
+
+		{`CREATE DOMAIN ??`, `CREATE DOMAIN`},
+		{`CREATE DOMAIN d AS INT CHECK (??`, `CREATE DOMAIN`},
+
 		{`CREATE STATISTICS ??`, `CREATE STATISTICS`},
 
 		{`CREATE TABLE blah (??`, `CREATE TABLE`},
@@ -141,6 +161,25 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP ROLE IF ??`, `DROP ROLE`},
 		{`DROP ROLE IF EXISTS bluh ??`, `DROP ROLE`},
 
+		{`DROP SCHEMA ??`, `DROP SCHEMA`},
+		{`DROP SCHEMA IF ??`, `DROP SCHEMA`},
+		{`DROP SCHEMA a ??`, `DROP SCHEMA`},
+
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+		{`DROP FUNCTION IF ??`, `DROP FUNCTION`},
+		{`DROP FUNCTION f ??`, `DROP FUNCTION`},
+
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+		{`DROP TRIGGER t ON ??`, `DROP TRIGGER`},
+
+		{`DROP TYPE ??`, `DROP TYPE`},
+		{`DROP TYPE IF ??`, `DROP TYPE`},
+		{`DROP TYPE t ??`, `DROP TYPE`},
+
+		{`DROP DOMAIN ??`, `DROP DOMAIN`},
+		{`DROP DOMAIN IF ??`, `DROP DOMAIN`},
+		{`DROP DOMAIN d ??`, `DROP DOMAIN`},
+
 		{`DROP SEQUENCE blah ??`, `DROP SEQUENCE`},
 		{`DROP SEQUENCE IF ??`, `DROP SEQUENCE`},
 		{`DROP SEQUENCE IF EXISTS blih, bloh ??`, `DROP SEQUENCE`},
@@ -150,6 +189,7 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP TABLE IF EXISTS blih, bloh ??`, `DROP TABLE`},
 
 		{`DROP VIEW blah ??`, `DROP VIEW`},
+		{`DROP MATERIALIZED VIEW blah ??`, `DROP VIEW`},
 		{`DROP VIEW IF ??`, `DROP VIEW`},
 		{`DROP VIEW IF EXISTS blih, bloh ??`, `DROP VIEW`},
 
@@ -318,6 +358,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`VALUES (1) ??`, `VALUES`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET SESSION TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET SESSION TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET SESSION TIME ??`, `SET SESSION`},
@@ -354,6 +397,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SAVEPOINT blah ??`, `SAVEPOINT`},
 
+		{`REFRESH ??`, `REFRESH`},
+		{`REFRESH MATERIALIZED VIEW blah AS OF ??`, `REFRESH`},
+
 		{`RELEASE blah ??`, `RELEASE`},
 		{`RELEASE SAVEPOINT blah ??`, `RELEASE`},
 
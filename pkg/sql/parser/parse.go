@@ -349,15 +349,29 @@ func newDecimal(prec, scale int32) (*types.T, error) {
 	return types.MakeDecimal(prec, scale), nil
 }
 
+// maxArrayDimensions is the maximum number of dimensions of an array type, as
+// in Postgres.
+const maxArrayDimensions = 6
+
 // ArrayOf creates a type alias for an array of the given element type and fixed
 // bounds.
 func arrayOf(colType *types.T, bounds []int32) (*types.T, error) {
 	if err := types.CheckArrayElementType(colType); err != nil {
 		return nil, err
 	}
+	if len(bounds) > maxArrayDimensions {
+		return nil, pgerror.Newf(pgerror.CodeProgramLimitExceededError,
+			"number of array dimensions (%d) exceeds the maximum allowed (%d)",
+			len(bounds), maxArrayDimensions)
+	}
 
-	// Currently bounds are ignored.
-	return types.MakeArray(colType), nil
+	// Each bound introduces one array dimension. The bounds themselves are
+	// ignored, as in Postgres.
+	typ := types.MakeArray(colType)
+	for i := 1; i < len(bounds); i++ {
+		typ = types.MakeArray(typ)
+	}
+	return typ, nil
 }
 
 // The SERIAL types are pseudo-types that are only used during parsing. After
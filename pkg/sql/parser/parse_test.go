@@ -91,6 +91,9 @@ func TestParse(t *testing.T) {
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d.e (f, g)`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INDEX a ON b (c) WHERE d > 0`},
+		{`CREATE INDEX IF NOT EXISTS a ON b (c) STORING (d) WHERE (d > 0) AND (e IS NULL)`},
+		{`CREATE UNIQUE INDEX a ON b (c) WHERE d`},
 		{`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE INVERTED INDEX a ON b.c (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) STORING (d)`},
@@ -162,6 +165,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX d (b, c))`},
@@ -169,10 +174,15 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
+		{`CREATE TABLE a (b INT8, UNIQUE (b) WHERE b > 0)`},
+		{`CREATE TABLE a (b INT8, UNIQUE (b) DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, CONSTRAINT c UNIQUE (b) DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, c INT8, INDEX (b) WHERE c IS NOT NULL)`},
 		{`CREATE TABLE a (b INT8, INDEX (b))`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE RESTRICT)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE CASCADE)`},
@@ -206,7 +216,11 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8) INTERLEAVE IN PARENT foo (c) CASCADE`},
 		{`CREATE TABLE a.b (b INT8)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT8)`},
+		{`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a (b INT8)`},
+		{`CREATE TEMPORARY TABLE a AS SELECT * FROM b`},
 		{`CREATE TABLE a (b INT8 AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT8 AS (a + b) VIRTUAL)`},
 		{`CREATE TABLE view (view INT8)`},
 
 		{`CREATE TABLE a (b INT8 CONSTRAINT c PRIMARY KEY)`},
@@ -257,6 +271,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b STRING COLLATE de)`},
 		{`CREATE TABLE a (b STRING(3) COLLATE de)`},
 		{`CREATE TABLE a (b STRING[] COLLATE de)`},
+		{`CREATE TABLE a (b INT8[][], c STRING[][][])`},
 		{`CREATE TABLE a (b STRING(3)[] COLLATE de)`},
 
 		{`CREATE VIEW a AS SELECT * FROM b`},
@@ -267,6 +282,71 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
+		{`REFRESH MATERIALIZED VIEW a`},
+		{`REFRESH MATERIALIZED VIEW a.b AS OF SYSTEM TIME '-1s'`},
+
+		{`CREATE SCHEMA a`},
+		{`CREATE SCHEMA IF NOT EXISTS a`},
+		{`EXPLAIN CREATE SCHEMA a`},
+
+		{`DROP SCHEMA a`},
+		{`DROP SCHEMA a, b`},
+		{`DROP SCHEMA IF EXISTS a, b RESTRICT`},
+		{`DROP SCHEMA a CASCADE`},
+
+		{`CREATE TYPE a AS ENUM ()`},
+		{`CREATE TYPE a AS ENUM ('a')`},
+		{`CREATE TYPE a AS ENUM ('a', 'b', 'c')`},
+		{`CREATE TYPE a.b AS ENUM ('a', 'b', 'c')`},
+		{`CREATE TYPE a.b.c AS ENUM ('a', 'b', 'c')`},
+		{`EXPLAIN CREATE TYPE a AS ENUM ('a')`},
+
+		{`ALTER TYPE a ADD VALUE 'b'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b'`},
+		{`ALTER TYPE a ADD VALUE 'b' BEFORE 'a'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b' AFTER 'a'`},
+		{`ALTER TYPE a.b ADD VALUE 'c'`},
+
+		{`DROP TYPE a`},
+		{`DROP TYPE a, b`},
+		{`DROP TYPE IF EXISTS a, b.c`},
+		{`DROP TYPE a RESTRICT`},
+		{`DROP TYPE IF EXISTS a CASCADE`},
+
+		{`CREATE DOMAIN a AS INT8`},
+		{`CREATE DOMAIN a.b AS STRING NOT NULL`},
+		{`CREATE DOMAIN a AS INT8 DEFAULT 1 CHECK (value > 0)`},
+		{`CREATE DOMAIN a AS STRING NOT NULL CONSTRAINT b CHECK (length(value) < 10) CHECK (value != '')`},
+		{`EXPLAIN CREATE DOMAIN a AS INT8`},
+
+		{`DROP DOMAIN a`},
+		{`DROP DOMAIN a, b`},
+		{`DROP DOMAIN IF EXISTS a, b.c`},
+		{`DROP DOMAIN a RESTRICT`},
+		{`DROP DOMAIN IF EXISTS a CASCADE`},
+
+		{`CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql AS 'SELECT 1'`},
+		{`CREATE OR REPLACE FUNCTION f(a INT8, b STRING) RETURNS STRING LANGUAGE sql AS 'SELECT b || a::STRING'`},
+		{`CREATE FUNCTION a.b(INT8, INT8) RETURNS INT8 LANGUAGE sql IMMUTABLE STRICT AS 'SELECT $1 + $2'`},
+		{`CREATE FUNCTION f(x INT8[]) RETURNS BOOL LANGUAGE sql STABLE AS 'SELECT x = ''{}'''`},
+		{`EXPLAIN CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql AS 'SELECT 1'`},
+
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION f(INT8, STRING), g`},
+		{`DROP FUNCTION IF EXISTS a.f(x INT8) RESTRICT`},
+		{`DROP FUNCTION f CASCADE`},
+
+		{`CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW AS 'SELECT 1'`},
+		{`CREATE TRIGGER t AFTER INSERT OR UPDATE OR DELETE ON a.b FOR EACH ROW AS 'INSERT INTO c VALUES (NEW.x, OLD.x)'`},
+		{`CREATE TRIGGER t BEFORE UPDATE OR DELETE ON a FOR EACH ROW AS 'SELECT NEW.x + 1 AS x WHERE OLD.y > 0'`},
+		{`EXPLAIN CREATE TRIGGER t AFTER DELETE ON a FOR EACH ROW AS 'DELETE FROM b WHERE x = OLD.x'`},
+		{`CREATE TRIGGER t BEFORE INSERT OR UPDATE ON a FOR EACH STATEMENT AS 'INSERT INTO b VALUES (now())'`},
+
+		{`DROP TRIGGER t ON a`},
+		{`DROP TRIGGER IF EXISTS t ON a.b CASCADE`},
 
 		{`CREATE SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
@@ -310,8 +390,11 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b RETURNING a + b`},
 		{`DELETE FROM a WHERE a = b RETURNING NOTHING`},
 		{`DELETE FROM a WHERE a = b ORDER BY c LIMIT d RETURNING e`},
+		{`DELETE FROM a USING b WHERE a.c = b.c`},
+		{`DELETE FROM a AS x USING b AS y, c WHERE x.d = y.d RETURNING x.d`},
 
 		{`DISCARD ALL`},
+		{`DISCARD TEMPORARY`},
 
 		{`DROP DATABASE a`},
 		{`EXPLAIN DROP DATABASE a`},
@@ -347,6 +430,8 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP MATERIALIZED VIEW a`},
+		{`DROP MATERIALIZED VIEW IF EXISTS a, b CASCADE`},
 		{`DROP SEQUENCE a`},
 		{`EXPLAIN DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
@@ -543,6 +628,8 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT ON TABLE foo TO root`},
 		{`GRANT SELECT, DELETE, UPDATE ON TABLE foo, db.foo TO root, bar`},
 		{`GRANT DROP ON DATABASE foo TO root`},
+		{`GRANT CREATE ON SCHEMA foo TO root`},
+		{`GRANT ALL ON SCHEMA foo, bar TO root, test`},
 		{`GRANT ALL ON DATABASE foo TO root, test`},
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
@@ -555,6 +642,7 @@ func TestParse(t *testing.T) {
 		{`REVOKE SELECT ON TABLE foo FROM root`},
 		{`REVOKE UPDATE, DELETE ON TABLE foo, db.foo FROM root, bar`},
 		{`REVOKE INSERT ON DATABASE foo FROM root`},
+		{`REVOKE CREATE ON SCHEMA foo, bar FROM root`},
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
@@ -598,6 +686,10 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a, b) DO UPDATE SET a = 1`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET a = 1, b = excluded.a`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET a = 1 WHERE b > 2`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT (a) WHERE b > 2 DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT (a) WHERE b > 2 DO UPDATE SET a = 1 WHERE b > 3`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO UPDATE SET a = 1`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET a = DEFAULT`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2)`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING a, b`},
@@ -639,6 +731,7 @@ func TestParse(t *testing.T) {
 		{`SELECT 'Deutsch' COLLATE de`},
 		{`SELECT a @> b`},
 		{`SELECT a <@ b`},
+		{`SELECT a && b`},
 		{`SELECT a ? b`},
 		{`SELECT a ?| b`},
 		{`SELECT a ?& b`},
@@ -692,6 +785,7 @@ func TestParse(t *testing.T) {
 		{`SELECT BYTES 'foo', 'foo'::BYTES`},
 		{`SELECT DATE 'foo', 'foo'::DATE`},
 		{`SELECT TIME 'foo', 'foo'::TIME`},
+		{`SELECT TIMETZ 'foo', 'foo'::TIMETZ`},
 		{`SELECT TIMESTAMP 'foo', 'foo'::TIMESTAMP`},
 		{`SELECT TIMESTAMPTZ 'foo', 'foo'::TIMESTAMPTZ`},
 		{`SELECT JSONB 'foo', 'foo'::JSONB`},
@@ -706,6 +800,7 @@ func TestParse(t *testing.T) {
 		{`SELECT 'foo'::TIMESTAMP(6)`},
 		{`SELECT 'foo'::TIMESTAMPTZ(6)`},
 		{`SELECT 'foo'::TIME(6)`},
+		{`SELECT 'foo'::TIMETZ(6)`},
 
 		{`SELECT '192.168.0.1'::INET`},
 		{`SELECT '192.168.0.1':::INET`},
@@ -751,6 +846,9 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY`},
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY AS bar`},
 		{`SELECT a FROM ROWS FROM (a(x), b(y), c(z))`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH RECURSIVE a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH RECURSIVE a (x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM a WHERE x < 10) SELECT x FROM a`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t1, LATERAL (SELECT * FROM t2 WHERE a = b)`},
 		{`SELECT a FROM t1, LATERAL ROWS FROM (generate_series(1, t1.x))`},
@@ -840,6 +938,14 @@ func TestParse(t *testing.T) {
 
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
+		{`SELECT 1 FROM t GROUP BY ()`},
+		{`SELECT a, b, count(*) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT a, b, count(*) FROM t GROUP BY CUBE (a, (b, c))`},
+		{`SELECT a, b, count(*) FROM t GROUP BY GROUPING SETS ((a, b), a, ())`},
+		{`SELECT a, b, count(*) FROM t GROUP BY a, GROUPING SETS (b, ROLLUP (c, d), CUBE (e))`},
+		{`SELECT a, GROUPING(a) FROM t GROUP BY ROLLUP (a)`},
+		{`SELECT GROUPING(a, b) FROM t GROUP BY CUBE (a, b)`},
+		{`SELECT rollup, cube, sets, "grouping" FROM t GROUP BY rollup, cube, sets, "grouping"`},
 
 		{`SELECT a FROM t HAVING a = b`},
 
@@ -960,6 +1066,17 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t LIMIT a`},
 		{`SELECT a FROM t OFFSET b`},
 		{`SELECT a FROM t LIMIT a OFFSET b`},
+		{`SELECT a FROM t FOR UPDATE`},
+		{`SELECT a FROM t FOR NO KEY UPDATE`},
+		{`SELECT a FROM t FOR SHARE`},
+		{`SELECT a FROM t FOR KEY SHARE`},
+		{`SELECT a FROM t FOR UPDATE OF t`},
+		{`SELECT a FROM t, u FOR UPDATE OF t, u NOWAIT`},
+		{`SELECT a FROM t FOR SHARE SKIP LOCKED`},
+		{`SELECT a FROM t, u FOR UPDATE OF t FOR SHARE OF u SKIP LOCKED`},
+		{`SELECT a FROM t ORDER BY a LIMIT 1 FOR UPDATE`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a LIMIT 1 FOR UPDATE`},
+		{`SELECT * FROM (SELECT a FROM t FOR UPDATE) AS s`},
 		{`SELECT DISTINCT * FROM t`},
 		{`SELECT DISTINCT a, b FROM t`},
 		{`SELECT DISTINCT ON (a, b) c FROM t`},
@@ -971,6 +1088,10 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS foo DEFERRED`},
+		{`SET CONSTRAINTS foo, bar IMMEDIATE`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
@@ -1035,6 +1156,9 @@ func TestParse(t *testing.T) {
 		{`UPDATE a SET b = 3 WHERE a = b RETURNING a, a + b`},
 		{`UPDATE a SET b = 3 WHERE a = b RETURNING NOTHING`},
 		{`UPDATE a SET b = 3 WHERE a = b ORDER BY c LIMIT d RETURNING e`},
+		{`UPDATE a SET b = c.d FROM c WHERE a.e = c.e`},
+		{`UPDATE a AS x SET b = y.c FROM c AS y, d WHERE x.e = y.e RETURNING x.b`},
+		{`UPDATE a SET b = c FROM (SELECT 1 AS c) AS d`},
 
 		{`UPDATE t AS "0" SET k = ''`},                 // "0" lost its quotes
 		{`SELECT * FROM "0" JOIN "0" USING (id, "0")`}, // last "0" lost its quotes.
@@ -1302,8 +1426,13 @@ func TestParse(t *testing.T) {
 		// TODO(dan): Implement.
 		// {`CREATE CHANGEFEED FOR TABLE foo VALUES FROM (1) TO (2) INTO 'sink'`},
 		// {`CREATE CHANGEFEED FOR TABLE foo PARTITION bar, baz INTO 'sink'`},
-		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
+		{`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
+		{`EXPERIMENTAL CHANGEFEED FOR DATABASE foo WITH no_initial_scan`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
+		{`CREATE CHANGEFEED INTO 'sink' AS SELECT a, b + 1 AS c FROM foo WHERE d = 'e'`},
+		{`CREATE CHANGEFEED INTO 'sink' WITH bar = 'baz' AS SELECT * FROM foo`},
+		{`EXPERIMENTAL CHANGEFEED AS SELECT a FROM foo WHERE b > 1`},
+		{`EXPERIMENTAL CHANGEFEED WITH bar = 'baz' AS SELECT a FROM foo`},
 
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
@@ -1334,6 +1463,23 @@ func TestParse2(t *testing.T) {
 	}{
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
+		{`CREATE TEMP TABLE a (b INT8)`, `CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE DOMAIN a INT`, `CREATE DOMAIN a AS INT8`},
+		{`CREATE DOMAIN a AS INT NULL CHECK (VALUE > 0)`, `CREATE DOMAIN a AS INT8 CHECK (value > 0)`},
+		{`CREATE LOCAL TEMP TABLE a (b INT8)`, `CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE GLOBAL TEMPORARY TABLE a (b INT8)`, `CREATE TEMPORARY TABLE a (b INT8)`},
+		{`DISCARD TEMP`, `DISCARD TEMPORARY`},
+
+		{`CREATE FUNCTION f(a INT) RETURNS INT AS 'SELECT a' LANGUAGE SQL`,
+			`CREATE FUNCTION f(a INT8) RETURNS INT8 LANGUAGE sql AS 'SELECT a'`},
+		{`CREATE FUNCTION f() RETURNS INT LANGUAGE sql VOLATILE CALLED ON NULL INPUT AS $$SELECT 1$$`,
+			`CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql AS 'SELECT 1'`},
+		{`CREATE FUNCTION f(INT) RETURNS INT RETURNS NULL ON NULL INPUT IMMUTABLE LANGUAGE 'sql' AS $body$SELECT $1$body$`,
+			`CREATE FUNCTION f(INT8) RETURNS INT8 LANGUAGE sql IMMUTABLE STRICT AS 'SELECT $1'`},
+
+		{`CREATE TRIGGER t AFTER DELETE OR INSERT OR DELETE ON a FOR EACH ROW AS $$SELECT 1$$`,
+			`CREATE TRIGGER t AFTER INSERT OR DELETE ON a FOR EACH ROW AS 'SELECT 1'`},
+
 		{`CREATE DATABASE a TEMPLATE = template0`,
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
@@ -1350,6 +1496,8 @@ func TestParse2(t *testing.T) {
 			`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b USING GIN (c)`,
 			`CREATE UNIQUE INVERTED INDEX a ON b (c)`},
+		{`CREATE INDEX a ON b USING GIST (c)`,
+			`CREATE INVERTED INDEX a ON b (c)`},
 
 		{`CREATE TABLE a (b BIGSERIAL, c SMALLSERIAL, d SERIAL)`,
 			`CREATE TABLE a (b SERIAL8, c SERIAL2, d SERIAL8)`},
@@ -1365,8 +1513,12 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b STRING)`},
 		{`CREATE TABLE a (b JSON)`,
 			`CREATE TABLE a (b JSONB)`},
+		{`CREATE TABLE a (b geometry, c GEOGRAPHY)`,
+			`CREATE TABLE a (b GEOMETRY, c GEOGRAPHY)`},
 		{`CREATE TABLE a (b TIMESTAMP WITH TIME ZONE)`,
 			`CREATE TABLE a (b TIMESTAMPTZ)`},
+		{`CREATE TABLE a (b TIME WITH TIME ZONE, c TIME(6) WITH TIME ZONE)`,
+			`CREATE TABLE a (b TIMETZ, c TIMETZ(6))`},
 		{`CREATE TABLE a (b BYTES, c BYTEA, d BLOB)`,
 			`CREATE TABLE a (b BYTES, c BYTES, d BYTES)`},
 		{`CREATE TABLE a (b CHAR(1), c CHARACTER(1), d CHARACTER(3))`,
@@ -1384,6 +1536,14 @@ func TestParse2(t *testing.T) {
 		{`SELECT CAST(1 AS "timestamp")`, `SELECT CAST(1 AS TIMESTAMP)`},
 		{`SELECT CAST(1 AS _int8)`, `SELECT CAST(1 AS INT8[])`},
 		{`SELECT CAST(1 AS "_int8")`, `SELECT CAST(1 AS INT8[])`},
+		{`CREATE TABLE a (b INT[3][3], c INT[][2], d INT ARRAY[3])`,
+			`CREATE TABLE a (b INT8[][], c INT8[][], d INT8[])`},
+		{`SELECT '{{1}}'::INT[][]`, `SELECT '{{1}}'::INT8[][]`},
+		{`SELECT CAST(1.2+2.3 AS notatype)`, `SELECT CAST(1.2 + 2.3 AS notatype)`},
+		{`SELECT ANNOTATE_TYPE(1.2+2.3, notatype)`, `SELECT ANNOTATE_TYPE(1.2 + 2.3, notatype)`},
+		{`SELECT 'f'::"blah"`, `SELECT 'f'::blah`},
+		{`SELECT 'f'::"Blah"`, `SELECT 'f'::"Blah"`},
+		{`SELECT foo''`, `SELECT foo ''`},
 		{`SELECT SERIAL8 'foo', 'foo'::SERIAL8`, `SELECT INT8 'foo', 'foo'::INT8`},
 
 		{`SELECT 'a' FROM t@{FORCE_INDEX=bar}`, `SELECT 'a' FROM t@bar`},
@@ -1397,6 +1557,10 @@ func TestParse2(t *testing.T) {
 		{`SELECT a FROM t WHERE a IS UNKNOWN`, `SELECT a FROM t WHERE a IS NULL`},
 		{`SELECT a FROM t WHERE a IS NOT UNKNOWN`, `SELECT a FROM t WHERE a IS NOT NULL`},
 
+		{`SELECT a FROM t GROUP BY rollup(a), cube(b)`, `SELECT a FROM t GROUP BY ROLLUP (a), CUBE (b)`},
+		{`SELECT a FROM t GROUP BY grouping sets(a,(a,b),())`, `SELECT a FROM t GROUP BY GROUPING SETS (a, (a, b), ())`},
+		{`SELECT grouping(a,b), rollup(a), cube(b) FROM t`, `SELECT GROUPING(a, b), rollup(a), cube(b) FROM t`},
+
 		{`SELECT +1`, `SELECT 1`},
 		{`SELECT - - 5`, `SELECT 5`},
 		{`SELECT - + 5`, `SELECT -5`},
@@ -1421,7 +1585,6 @@ func TestParse2(t *testing.T) {
 
 		{`SELECT b <<= c`, `SELECT inet_contained_by_or_equals(b, c)`},
 		{`SELECT b >>= c`, `SELECT inet_contains_or_equals(b, c)`},
-		{`SELECT b && c`, `SELECT inet_contains_or_contained_by(b, c)`},
 
 		{`SELECT NUMERIC 'foo'`, `SELECT DECIMAL 'foo'`},
 		{`SELECT REAL 'foo'`, `SELECT FLOAT4 'foo'`},
@@ -1479,6 +1642,12 @@ func TestParse2(t *testing.T) {
 			`SELECT current_timestamp()`},
 		{`SELECT CURRENT_DATE`,
 			`SELECT current_date()`},
+		{`SELECT CURRENT_TIME`,
+			`SELECT current_time()`},
+		{`SELECT a AT TIME ZONE 'America/New_York'`,
+			`SELECT timezone('America/New_York', a)`},
+		{`SELECT a + b AT TIME ZONE c AT TIME ZONE 'UTC'`,
+			`SELECT a + timezone('UTC', timezone(c, b))`},
 		{`SELECT POSITION(a IN b)`,
 			`SELECT strpos(b, a)`},
 		{`SELECT TRIM(BOTH a FROM b)`,
@@ -1505,6 +1674,14 @@ func TestParse2(t *testing.T) {
 		// We allow OFFSET before LIMIT, but always output LIMIT first.
 		{`SELECT a FROM t OFFSET a LIMIT b`,
 			`SELECT a FROM t LIMIT b OFFSET a`},
+		// The locking clause may come before LIMIT/OFFSET, but is always output
+		// last.
+		{`SELECT a FROM t FOR UPDATE LIMIT 1`,
+			`SELECT a FROM t LIMIT 1 FOR UPDATE`},
+		{`SELECT a FROM t ORDER BY a FOR UPDATE OFFSET 1`,
+			`SELECT a FROM t ORDER BY a OFFSET 1 FOR UPDATE`},
+		{`SELECT a FROM t FOR READ ONLY`,
+			`SELECT a FROM t`},
 		// FETCH FIRST ... is alternative syntax for LIMIT.
 		{`SELECT a FROM t FETCH FIRST 3 ROWS ONLY`,
 			`SELECT a FROM t LIMIT 3`},
@@ -1738,6 +1915,8 @@ func TestParse2(t *testing.T) {
 
 		{`CREATE CHANGEFEED FOR TABLE foo INTO sink`,
 			`CREATE CHANGEFEED FOR TABLE foo INTO 'sink'`},
+		{`CREATE CHANGEFEED INTO sink AS SELECT a FROM foo`,
+			`CREATE CHANGEFEED INTO 'sink' AS SELECT a FROM foo`},
 
 		{`SHOW CLUSTER SETTING ALL`, `SHOW ALL CLUSTER SETTINGS`},
 
@@ -1898,6 +2077,18 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo (bar) MATCH SIMPLE ON UPDATE NO ACTION ON DELETE NO ACTION)`,
 			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo (bar))`,
 		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY DEFERRED)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, UNIQUE (b) INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, UNIQUE (b))`,
+		},
 		{
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE NO ACTION ON DELETE NO ACTION)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other)`,
@@ -2155,6 +2346,10 @@ SELECT INTERVAL 'foo'
 		{`SELECT 1 /* hello`, `lexical error: unterminated comment
 SELECT 1 /* hello
          ^
+`},
+		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, `syntax error: CHECK constraints cannot be marked DEFERRABLE at or near ")"
+CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)
+                                               ^
 `},
 		{`SELECT '1`, `lexical error: unterminated string
 SELECT '1
@@ -2223,6 +2418,14 @@ CREATE TABLE test (
   foo INT8 NULL NOT NULL
 )
 ^
+`},
+		{`CREATE DOMAIN a AS INT8 NULL NOT NULL`, `syntax error: conflicting NULL/NOT NULL constraints at or near "EOF"
+CREATE DOMAIN a AS INT8 NULL NOT NULL
+                                     ^
+`},
+		{`CREATE DOMAIN a AS INT8 PRIMARY KEY`, `syntax error: primary key constraints not possible for domains at or near "EOF"
+CREATE DOMAIN a AS INT8 PRIMARY KEY
+                                   ^
 `},
 		{`CREATE DATABASE a b`,
 			`syntax error at or near "b"
@@ -2260,11 +2463,6 @@ HINT: try \h SELECT`},
 SELECT 1e-
        ^
 HINT: try \h SELECT`},
-		{"SELECT foo''",
-			`syntax error: type does not exist at or near ""
-SELECT foo''
-          ^
-`},
 		{
 			`SELECT 0x FROM t`,
 			`lexical error: invalid hexadecimal numeric literal
@@ -2362,20 +2560,6 @@ HINT: try \h INSERT`,
 ALTER TABLE t RENAME COLUMN x TO family
                                  ^
 HINT: try \h ALTER TABLE`,
-		},
-		{
-			`SELECT CAST(1.2+2.3 AS notatype)`,
-			`syntax error: type does not exist at or near "notatype"
-SELECT CAST(1.2+2.3 AS notatype)
-                       ^
-`,
-		},
-		{
-			`SELECT ANNOTATE_TYPE(1.2+2.3, notatype)`,
-			`syntax error: type does not exist at or near "notatype"
-SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
-                              ^
-`,
 		},
 		{
 			`CREATE USER foo WITH PASSWORD`,
@@ -2438,13 +2622,6 @@ SELECT EXISTS(SELECT 1)[1]
 			`syntax error: + ANY <array> is invalid because "+" is not a boolean operator at or near "EOF"
 SELECT 1 + ANY ARRAY[1, 2, 3]
                              ^
-`,
-		},
-		{
-			`SELECT 'f'::"blah"`,
-			`syntax error: type does not exist at or near "blah"
-SELECT 'f'::"blah"
-            ^
 `,
 		},
 		// Ensure that the support for ON ROLE <namelist> doesn't leak
@@ -2844,57 +3021,43 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE EXTENSION a`, 0, `create extension a`},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`},
-		{`CREATE FUNCTION a`, 17511, `create`},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`},
+		{`CREATE FUNCTION a() RETURNS TABLE (b INT)`, 17511, `returns table`},
+		{`CREATE FUNCTION a() RETURNS INT AS 'b', 'c'`, 17511, `c function`},
 		{`CREATE LANGUAGE a`, 17511, `create language a`},
-		{`CREATE MATERIALIZED VIEW a`, 24747, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`},
 		{`CREATE PUBLICATION a`, 0, `create publication`},
 		{`CREATE RULE a`, 0, `create rule`},
-		{`CREATE SCHEMA a`, 26443, `create`},
 		{`CREATE SERVER a`, 0, `create server`},
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`},
-		{`CREATE TRIGGER a`, 28296, `create`},
+		{`CREATE TRIGGER a BEFORE INSERT ON b FOR EACH ROW EXECUTE FUNCTION c()`, 28296, `execute function`},
+		{`CREATE TRIGGER a AFTER UPDATE ON b FOR EACH STATEMENT EXECUTE PROCEDURE c()`, 28296, `execute function`},
 
 		{`DROP AGGREGATE a`, 0, `drop aggregate`},
 		{`DROP CAST a`, 0, `drop cast`},
 		{`DROP COLLATION a`, 0, `drop collation`},
 		{`DROP CONVERSION a`, 0, `drop conversion`},
-		{`DROP DOMAIN a`, 27796, `drop`},
 		{`DROP EXTENSION a`, 0, `drop extension a`},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`},
-		{`DROP FUNCTION a`, 17511, `drop `},
 		{`DROP LANGUAGE a`, 17511, `drop language a`},
 		{`DROP OPERATOR a`, 0, `drop operator`},
 		{`DROP PUBLICATION a`, 0, `drop publication`},
 		{`DROP RULE a`, 0, `drop rule`},
-		{`DROP SCHEMA a`, 26443, `drop`},
 		{`DROP SERVER a`, 0, `drop server`},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`},
-		{`DROP TRIGGER a`, 28296, `drop`},
-		{`DROP TYPE a`, 27793, `drop type`},
 
 		{`DISCARD PLANS`, 0, `discard plans`},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`},
-		{`DISCARD TEMP`, 0, `discard temp`},
-		{`DISCARD TEMPORARY`, 0, `discard temp`},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`},
 		{`SET LOCAL foo = bar`, 32562, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`},
 
-		{`CREATE TEMP TABLE a(b INT8)`, 5807, ``},
 		{`CREATE UNLOGGED TABLE a(b INT8)`, 0, `create unlogged`},
 		{`CREATE TEMP VIEW a AS SELECT b`, 5807, ``},
 		{`CREATE TEMP SEQUENCE a`, 5807, ``},
 
-		{`CREATE TABLE a(x INT[][])`, 32552, ``},
-		{`CREATE TABLE a(x INT[1][2])`, 32552, ``},
-		{`CREATE TABLE a(x INT ARRAY[1][2])`, 32552, ``},
-
 		{`CREATE TABLE a(LIKE b)`, 30840, ``},
 
 		{`CREATE TABLE a(b INT8) WITH OIDS`, 0, `create table with oids`},
@@ -2902,18 +3065,9 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`CREATE TABLE a AS SELECT b WITH NO DATA`, 0, `create table as with no data`},
 
-		{`CREATE TABLE a(b INT8 AS (123) VIRTUAL)`, 0, `virtual computed columns`},
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`},
-
 		{`CREATE SEQUENCE a AS DOUBLE PRECISION`, 25110, `FLOAT8`},
 		{`CREATE SEQUENCE a OWNED BY b`, 26382, ``},
 
@@ -2921,15 +3075,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE RECURSIVE VIEW a AS SELECT b`, 0, `create recursive view`},
 
 		{`CREATE TYPE a AS (b)`, 27792, ``},
-		{`CREATE TYPE a AS ENUM (b)`, 24873, ``},
 		{`CREATE TYPE a AS RANGE b`, 27791, ``},
 		{`CREATE TYPE a (b)`, 27793, `base`},
 		{`CREATE TYPE a`, 27793, `shell`},
-		{`CREATE DOMAIN a`, 27796, `create`},
 
-		{`CREATE INDEX a ON b(c) WHERE d > 0`, 9683, ``},
 		{`CREATE INDEX a ON b USING HASH (c)`, 0, `index using hash`},
-		{`CREATE INDEX a ON b USING GIST (c)`, 0, `index using gist`},
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`},
 		{`CREATE INDEX a ON b USING BRIN (c)`, 0, `index using brin`},
 
@@ -2938,15 +3088,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE INDEX a ON b(foo(c))`, 9682, ``},
 
 		{`INSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``},
-		{`INSERT INTO foo VALUES (1,2) ON CONFLICT ON CONSTRAINT a DO NOTHING`, 28161, ``},
 
 		{`SELECT max(a ORDER BY b) FROM ab`, 23620, ``},
 
-		{`SELECT * FROM a FOR UPDATE`, 6583, ``},
 		{`SELECT * FROM ROWS FROM (a(b) AS (d))`, 0, `ROWS FROM with col_def_list`},
 
-		{`SELECT 123 AT TIME ZONE 'b'`, 32005, ``},
-
 		{`SELECT 'a'::INTERVAL SECOND`, 0, `interval with unit qualifier`},
 		{`SELECT 'a'::INTERVAL(123)`, 32564, ``},
 		{`SELECT 'a'::INTERVAL SECOND(123)`, 32564, `interval second`},
@@ -2961,20 +3107,17 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`SELECT 'a'::TIME(123)`, 32565, ``},
 		{`SELECT 'a'::TIME(123) WITHOUT TIME ZONE`, 32565, ``},
-		{`SELECT 'a'::TIMETZ(123)`, 26097, `type with precision`},
+		{`SELECT 'a'::TIMETZ(123)`, 32565, ``},
 		{`SELECT 'a'::TIME(123) WITH TIME ZONE`, 32565, ``},
 		{`SELECT TIME(3) 'a'`, 32565, ``},
-		{`SELECT TIMETZ(3) 'a'`, 26097, `type with precision`},
+		{`SELECT TIMETZ(3) 'a'`, 32565, ``},
 
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`},
 		{`SELECT COLLATION FOR (a)`, 32563, ``},
-		{`SELECT CURRENT_TIME`, 26097, `current_time`},
-		{`SELECT CURRENT_TIME()`, 26097, `current_time`},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`},
 		{`SELECT a(b) WITHIN GROUP (ORDER BY c)`, 0, `within group`},
 
@@ -2994,15 +3137,9 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b TSVECTOR)`, 7821, `tsvector`},
 		{`CREATE TABLE a(b TXID_SNAPSHOT)`, 0, `txid_snapshot`},
 		{`CREATE TABLE a(b XML)`, 0, `xml`},
-		{`CREATE TABLE a(b TIMETZ)`, 26097, `type`},
-
-		{`INSERT INTO a VALUES (1) ON CONFLICT (x) WHERE x > 3 DO NOTHING`, 32557, ``},
-
-		{`WITH RECURSIVE a AS (TABLE b) SELECT c`, 21085, ``},
 
 		{`UPDATE foo SET (a, a.b) = (1, 2)`, 27792, ``},
 		{`UPDATE foo SET a.b = 1`, 27792, ``},
-		{`UPDATE foo SET x = y FROM a, b`, 7841, ``},
 		{`UPDATE Foo SET x.y = z`, 27792, ``},
 
 		{`UPSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``},
@@ -17,6 +17,7 @@ import (
 	"go/constant"
 	"go/token"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 	"unsafe"
 
@@ -116,6 +117,10 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.scanPlaceholder(lval)
 			return
 		}
+		// dollar-quoted string? $$...$$ or $tag$...$tag$
+		if s.scanDollarQuotedString(lval) {
+			lval.id = SCONST
+		}
 		return
 
 	case identQuote:
@@ -753,6 +758,37 @@ outer:
 	return true
 }
 
+// scanDollarQuotedString scans a dollar-quoted string constant, such as
+// $$...$$ or $tag$...$tag$. The initial '$' has already been consumed. It
+// returns false, without consuming anything, if the input at the current
+// position is not the rest of an opening delimiter. The content of the string
+// is taken verbatim; no escapes are processed.
+func (s *scanner) scanDollarQuotedString(lval *sqlSymType) bool {
+	start := s.pos
+	end := start
+	if end < len(s.in) && lex.IsIdentStart(int(s.in[end])) {
+		end++
+		for end < len(s.in) && s.in[end] != '$' && lex.IsIdentMiddle(int(s.in[end])) {
+			end++
+		}
+	}
+	if end >= len(s.in) || s.in[end] != '$' {
+		return false
+	}
+	delim := s.in[start-1 : end+1]
+	s.pos = end + 1
+	n := strings.Index(s.in[s.pos:], delim)
+	if n < 0 {
+		s.pos = len(s.in)
+		lval.id = ERROR
+		lval.str = errUnterminated
+		return false
+	}
+	lval.str = s.in[s.pos : s.pos+n]
+	s.pos += n + len(delim)
+	return true
+}
+
 // scanString scans the content inside '...'. This is used for simple
 // string literals '...' but also e'....' and b'...'. For x'...', see
 // scanHexString().
@@ -74,6 +74,7 @@ func TestScanner(t *testing.T) {
 		{`!~*`, []int{NOT_REGIMATCH}},
 		{`$1`, []int{PLACEHOLDER}},
 		{`$a`, []int{'$', IDENT}},
+		{`$$a$$`, []int{SCONST}},
 		{`a`, []int{IDENT}},
 		{`foo + bar`, []int{IDENT, '+', IDENT}},
 		{`select a from b`, []int{SELECT, IDENT, FROM, IDENT}},
@@ -290,6 +291,10 @@ world`},
 		{`X'626172'`, `bar`},
 		{`X'FF'`, "\xff"},
 		{`B'100101'`, "100101"},
+		{`$$a'b$$`, `a'b`},
+		{`$$$$`, ``},
+		{`$fn$SELECT $$1$$$fn$`, `SELECT $$1$$`},
+		{`$$a`, `unterminated string`},
 	}
 	for _, d := range testData {
 		s := makeScanner(d.sql)
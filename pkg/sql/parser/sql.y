@@ -149,6 +149,9 @@ func (u *sqlSymUnion) unresolvedName() *tree.UnresolvedName {
 func (u *sqlSymUnion) unresolvedObjectName() *tree.UnresolvedObjectName {
     return u.val.(*tree.UnresolvedObjectName)
 }
+func (u *sqlSymUnion) unresolvedObjectNames() []*tree.UnresolvedObjectName {
+    return u.val.([]*tree.UnresolvedObjectName)
+}
 func (u *sqlSymUnion) functionReference() tree.FunctionReference {
     return u.val.(tree.FunctionReference)
 }
@@ -299,6 +302,18 @@ func (u *sqlSymUnion) updateExprs() tree.UpdateExprs {
 func (u *sqlSymUnion) limit() *tree.Limit {
     return u.val.(*tree.Limit)
 }
+func (u *sqlSymUnion) lockingClause() tree.LockingClause {
+    return u.val.(tree.LockingClause)
+}
+func (u *sqlSymUnion) lockingItem() *tree.LockingItem {
+    return u.val.(*tree.LockingItem)
+}
+func (u *sqlSymUnion) lockingStrength() tree.LockingStrength {
+    return u.val.(tree.LockingStrength)
+}
+func (u *sqlSymUnion) lockingWaitPolicy() tree.LockingWaitPolicy {
+    return u.val.(tree.LockingWaitPolicy)
+}
 func (u *sqlSymUnion) targetList() tree.TargetList {
     return u.val.(tree.TargetList)
 }
@@ -431,6 +446,9 @@ func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -449,9 +467,36 @@ func (u *sqlSymUnion) scrubOption() tree.ScrubOption {
 func (u *sqlSymUnion) resolvableFuncRefFromName() tree.ResolvableFunctionReference {
     return tree.ResolvableFunctionReference{FunctionReference: u.unresolvedName()}
 }
+func (u *sqlSymUnion) enumValueList() tree.EnumValueList {
+    return u.val.(tree.EnumValueList)
+}
+func (u *sqlSymUnion) alterTypeAddValuePlacement() *tree.AlterTypeAddValuePlacement {
+    return u.val.(*tree.AlterTypeAddValuePlacement)
+}
 func (u *sqlSymUnion) rowsFromExpr() *tree.RowsFromExpr {
     return u.val.(*tree.RowsFromExpr)
 }
+func (u *sqlSymUnion) funcParam() tree.FuncParam {
+    return u.val.(tree.FuncParam)
+}
+func (u *sqlSymUnion) funcParams() tree.FuncParams {
+    return u.val.(tree.FuncParams)
+}
+func (u *sqlSymUnion) functionOptions() *tree.FunctionOptions {
+    return u.val.(*tree.FunctionOptions)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() []tree.FuncObj {
+    return u.val.([]tree.FuncObj)
+}
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvents() tree.TriggerEvents {
+    return u.val.(tree.TriggerEvents)
+}
 func newNameFromStr(s string) *tree.Name {
     return (*tree.Name)(&s)
 }
@@ -474,14 +519,14 @@ func newNameFromStr(s string) *tree.Name {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACTION ADD ADMIN AGGREGATE
+%token <str> ABORT ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT AUTOMATIC
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str> BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
-%token <str> CACHE CANCEL CASCADE CASE CAST CHANGEFEED CHAR
+%token <str> CACHE CALLED CANCEL CASCADE CASE CAST CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
 %token <str> COMMITTED COMPACT CONCAT CONFIGURATION CONFIGURATIONS CONFIGURE
@@ -494,7 +539,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> DEALLOCATE DEFERRABLE DEFERRED DELETE DESC
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING END ENUM ESCAPE EXCEPT
+%token <str> EACH ELSE ENCODING END ENUM ESCAPE EXCEPT
 %token <str> EXISTS EXECUTE EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
@@ -508,10 +553,10 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> HAVING HASH HIGH HISTOGRAM HOUR
 
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCREMENT INCREMENTAL
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS INET_CONTAINS_OR_CONTAINED_BY
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
+%token <str> INNER INPUT INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
 %token <str> INTERSECT INTERVAL INTO INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -520,11 +565,12 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> LANGUAGE LATERAL LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
-%token <str> LOCALTIME LOCALTIMESTAMP LOOKUP LOW LSHIFT
+%token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOOKUP LOW LSHIFT
 
 %token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE MINUTE MONTH
 
 %token <str> NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
+%token <str> NOWAIT
 %token <str> NOT NOTHING NOTNULL NULL NULLIF NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
@@ -536,18 +582,18 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> QUERIES QUERY
 
-%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
+%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES REFRESH
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
+%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETURNS REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE
 
 %token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIAL SERIAL2 SERIAL4 SERIAL8
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
-%token <str> SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
+%token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
+%token <str> STABLE START STATEMENT STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM SUBSCRIPTION
 
 %token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RANGES EXPERIMENTAL_RANGES TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -558,7 +604,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL VOLATILE
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -670,7 +716,24 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.CreateStatsOptions> create_stats_option_list
 %type <*tree.CreateStatsOptions> create_stats_option
 
+%type <tree.Statement> create_function_stmt
+%type <tree.FuncParams> opt_func_param_list func_param_list
+%type <tree.FuncParam> func_param
+%type <*tree.FunctionOptions> func_option_list func_option
+%type <tree.Statement> create_schema_stmt
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_domain_stmt
+%type <tree.Statement> create_trigger_stmt
+%type <tree.TriggerActionTime> trigger_action_time
+%type <tree.TriggerEvents> trigger_event_list trigger_event
+%type <tree.Statement> drop_function_stmt
+%type <[]tree.FuncObj> func_obj_list
+%type <tree.FuncObj> func_obj
+%type <tree.Statement> drop_schema_stmt
+%type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_domain_stmt
+%type <tree.Statement> drop_trigger_stmt
+%type <tree.Statement> alter_type_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -695,6 +758,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
 %type <tree.Statement> pause_stmt
+%type <tree.Statement> refresh_stmt
 %type <tree.Statement> release_stmt
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
 %type <tree.Statement> resume_stmt
@@ -709,6 +773,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -786,10 +851,13 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UnresolvedName> func_name
 %type <str> opt_collate
 
-%type <str> database_name index_name opt_index_name column_name insert_column_item statistics_name window_name
+%type <str> database_name schema_name index_name opt_index_name column_name insert_column_item statistics_name window_name
 %type <str> family_name opt_family_name table_alias_name constraint_name target_name zone_name partition_name collation_name
 %type <str> db_object_name_component
 %type <*tree.UnresolvedObjectName> table_name standalone_index_name sequence_name type_name view_name db_object_name simple_db_object_name complex_db_object_name
+%type <[]*tree.UnresolvedObjectName> type_name_list
+%type <tree.EnumValueList> opt_enum_val_list enum_val_list
+%type <*tree.AlterTypeAddValuePlacement> opt_add_val_placement
 %type <*tree.UnresolvedName> table_pattern complex_table_pattern
 %type <*tree.UnresolvedName> column_path prefixed_column_path column_path_with_star
 %type <tree.TableExpr> insert_target create_stats_target
@@ -815,12 +883,17 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DistinctOn> distinct_on_clause
 %type <tree.NameList> opt_column_list insert_column_list opt_stats_columns
 %type <tree.OrderBy> sort_clause opt_sort_clause
+%type <tree.LockingClause> for_locking_clause opt_for_locking_clause for_locking_items
+%type <*tree.LockingItem> for_locking_item
+%type <tree.LockingStrength> for_locking_strength
+%type <tree.LockingWaitPolicy> opt_nowait_or_skip
+%type <tree.TableNames> opt_locked_rels
 %type <[]*tree.Order> sortby_list
 %type <tree.IndexElemList> index_params
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
-%type <*tree.From> from_clause update_from_clause
-%type <tree.TableExprs> from_list rowsfrom_list
+%type <*tree.From> from_clause
+%type <tree.TableExprs> from_list rowsfrom_list update_from_clause opt_using_clause
 %type <tree.TablePatterns> table_pattern_list single_table_pattern_list
 %type <tree.TableNames> table_name_list
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -831,7 +904,9 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
-%type <*tree.Limit> select_limit
+%type <tree.Exprs> group_by_list
+%type <tree.Expr> group_by_item
+%type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
 
@@ -854,7 +929,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DurationField> opt_interval interval_second interval_qualifier
 %type <tree.Expr> overlay_placing
 
-%type <bool> opt_unique opt_cluster
+%type <bool> opt_unique opt_cluster opt_or_replace
+%type <bool> opt_temp_create_table
 %type <bool> opt_using_gin_btree
 
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
@@ -864,8 +940,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <empty> first_or_next
 
 %type <tree.Statement> insert_rest
-%type <tree.NameList> opt_conf_expr opt_col_def_list
-%type <*tree.OnConflict> on_conflict
+%type <tree.NameList> opt_col_def_list
+%type <*tree.OnConflict> on_conflict opt_conf_expr
 
 %type <tree.Statement> begin_transaction
 %type <tree.TransactionModes> transaction_mode_list transaction_mode
@@ -873,7 +949,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NameList> opt_storing
 %type <*tree.ColumnTableDef> column_def
 %type <tree.TableDef> table_elem
-%type <tree.Expr> where_clause opt_where_clause
+%type <tree.Expr> where_clause opt_where_clause opt_idx_where
 %type <*tree.ArraySubscript> array_subscript
 %type <tree.Expr> opt_slice_bound
 %type <*tree.IndexFlags> opt_index_flags
@@ -948,6 +1024,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NamedColumnQualification> col_qualification
 %type <tree.ColumnQualification> col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
+%type <tree.ConstraintDeferrability> opt_deferrable
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -970,7 +1047,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <[]tree.ColumnID> opt_tableref_col_list tableref_col_list
 
-%type <tree.TargetList> targets targets_roles changefeed_targets
+%type <tree.TargetList> targets targets_roles grant_targets changefeed_targets
 %type <*tree.TargetList> opt_on_targets_roles
 %type <tree.NameList> for_grantee_clause
 %type <privilege.List> privileges
@@ -1090,6 +1167,7 @@ alter_ddl_stmt:
 | alter_sequence_stmt // EXTEND WITH HELP: ALTER SEQUENCE
 | alter_database_stmt // EXTEND WITH HELP: ALTER DATABASE
 | alter_range_stmt    // EXTEND WITH HELP: ALTER RANGE
+| alter_type_stmt     // EXTEND WITH HELP: ALTER TYPE
 
 // %Help: ALTER TABLE - change the definition of a table
 // %Category: DDL
@@ -1182,6 +1260,58 @@ alter_sequence_options_stmt:
     $$.val = &tree.AlterSequence{Name: $5.unresolvedObjectName(), Options: $6.seqOpts(), IfExists: true}
   }
 
+// %Help: ALTER TYPE - change the definition of a type
+// %Category: DDL
+// %Text:
+// ALTER TYPE <typename> ADD VALUE [IF NOT EXISTS] <value> [ { BEFORE | AFTER } <value> ]
+// %SeeAlso: CREATE TYPE, DROP TYPE
+alter_type_stmt:
+  ALTER TYPE type_name ADD VALUE SCONST opt_add_val_placement
+  {
+    $$.val = &tree.AlterType{
+      Type: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterTypeAddValue{
+        NewVal: tree.EnumValue($6),
+        IfNotExists: false,
+        Placement: $7.alterTypeAddValuePlacement(),
+      },
+    }
+  }
+| ALTER TYPE type_name ADD VALUE IF NOT EXISTS SCONST opt_add_val_placement
+  {
+    $$.val = &tree.AlterType{
+      Type: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterTypeAddValue{
+        NewVal: tree.EnumValue($9),
+        IfNotExists: true,
+        Placement: $10.alterTypeAddValuePlacement(),
+      },
+    }
+  }
+| ALTER TYPE type_name RENAME error { return unimplementedWithIssueDetail(sqllex, 24873, "alter type rename") }
+| ALTER TYPE type_name SET error    { return unimplementedWithIssueDetail(sqllex, 24873, "alter type set") }
+| ALTER TYPE error // SHOW HELP: ALTER TYPE
+
+opt_add_val_placement:
+  BEFORE SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{
+      Before: true,
+      ExistingVal: tree.EnumValue($2),
+    }
+  }
+| AFTER SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{
+      Before: false,
+      ExistingVal: tree.EnumValue($2),
+    }
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.AlterTypeAddValuePlacement)(nil)
+  }
+
 // %Help: ALTER USER - change user properties
 // %Category: Priv
 // %Text:
@@ -2111,22 +2241,17 @@ create_unsupported:
 | CREATE EXTENSION name error { return unimplemented(sqllex, "create extension " + $3) }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
-| CREATE MATERIALIZED VIEW error { return unimplementedWithIssue(sqllex, 24747) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
-| CREATE SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "create") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE { $$.val = true }
+| /* EMPTY */ { $$.val = false }
 
 opt_trusted:
   TRUSTED {}
@@ -2141,32 +2266,31 @@ drop_unsupported:
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplemented(sqllex, "drop extension " + $5) }
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
-| DROP SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "drop") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TYPE error { return unimplementedWithIssueDetail(sqllex, 27793, "drop type") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_changefeed_stmt
 | create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
+| create_function_stmt // EXTEND WITH HELP: CREATE FUNCTION
 | create_index_stmt    // EXTEND WITH HELP: CREATE INDEX
 | create_table_stmt    // EXTEND WITH HELP: CREATE TABLE
 | create_table_as_stmt // EXTEND WITH HELP: CREATE TABLE
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
-| CREATE opt_temp TABLE error   // SHOW HELP: CREATE TABLE
-| create_type_stmt     { /* SKIP DOC */ }
+| CREATE opt_temp_create_table TABLE error   // SHOW HELP: CREATE TABLE
+| create_schema_stmt   // EXTEND WITH HELP: CREATE SCHEMA
+| create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_domain_stmt   // EXTEND WITH HELP: CREATE DOMAIN
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
@@ -2274,6 +2398,14 @@ create_changefeed_stmt:
       Options: $6.kvOptions(),
     }
   }
+| CREATE CHANGEFEED opt_changefeed_sink opt_with_options AS select_stmt
+  {
+    $$.val = &tree.CreateChangefeed{
+      SinkURI: $3.expr(),
+      Options: $4.kvOptions(),
+      Select: $6.slct(),
+    }
+  }
 | EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_with_options
   {
     /* SKIP DOC */
@@ -2282,6 +2414,14 @@ create_changefeed_stmt:
       Options: $5.kvOptions(),
     }
   }
+| EXPERIMENTAL CHANGEFEED opt_with_options AS select_stmt
+  {
+    /* SKIP DOC */
+    $$.val = &tree.CreateChangefeed{
+      Options: $3.kvOptions(),
+      Select: $5.slct(),
+    }
+  }
 
 changefeed_targets:
   single_table_pattern_list
@@ -2292,6 +2432,10 @@ changefeed_targets:
   {
     $$.val = tree.TargetList{Tables: $2.tablePatterns()}
   }
+| DATABASE database_name
+  {
+    $$.val = tree.TargetList{Databases: tree.NameList{tree.Name($2)}}
+  }
 
 single_table_pattern_list:
   table_name
@@ -2317,28 +2461,40 @@ opt_changefeed_sink:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [USING <exprs...>]
+//               [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
 // %SeeAlso: WEBDOCS/delete.html
 delete_stmt:
-  opt_with_clause DELETE FROM table_name_expr_opt_alias_idx opt_where_clause opt_sort_clause opt_limit_clause returning_clause
+  opt_with_clause DELETE FROM table_name_expr_opt_alias_idx opt_using_clause opt_where_clause opt_sort_clause opt_limit_clause returning_clause
   {
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
-      Where: tree.NewWhere(tree.AstWhere, $5.expr()),
-      OrderBy: $6.orderBy(),
-      Limit: $7.limit(),
-      Returning: $8.retClause(),
+      Using: $5.tblExprs(),
+      Where: tree.NewWhere(tree.AstWhere, $6.expr()),
+      OrderBy: $7.orderBy(),
+      Limit: $8.limit(),
+      Returning: $9.retClause(),
     }
   }
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
+opt_using_clause:
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
+
 // %Help: DISCARD - reset the session to its initial state
 // %Category: Cfg
-// %Text: DISCARD ALL
+// %Text: DISCARD { ALL | { TEMP | TEMPORARY } }
 discard_stmt:
   DISCARD ALL
   {
@@ -2346,8 +2502,14 @@ discard_stmt:
   }
 | DISCARD PLANS { return unimplemented(sqllex, "discard plans") }
 | DISCARD SEQUENCES { return unimplemented(sqllex, "discard sequences") }
-| DISCARD TEMP { return unimplemented(sqllex, "discard temp") }
-| DISCARD TEMPORARY { return unimplemented(sqllex, "discard temp") }
+| DISCARD TEMP
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
+| DISCARD TEMPORARY
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
 | DISCARD error // SHOW HELP: DISCARD
 
 // %Help: DROP
@@ -2368,10 +2530,15 @@ drop_ddl_stmt:
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
+| drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
+| drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_domain_stmt   // EXTEND WITH HELP: DROP DOMAIN
+| drop_function_stmt // EXTEND WITH HELP: DROP FUNCTION
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
-// %Text: DROP VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP [MATERIALIZED] VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
 // %SeeAlso: WEBDOCS/drop-index.html
 drop_view_stmt:
   DROP VIEW table_name_list opt_drop_behavior
@@ -2382,6 +2549,24 @@ drop_view_stmt:
   {
     $$.val = &tree.DropView{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $4.tableNames(),
+      IfExists: false,
+      DropBehavior: $5.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $6.tableNames(),
+      IfExists: true,
+      DropBehavior: $7.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
 | DROP VIEW error // SHOW HELP: DROP VIEW
 
 // %Help: DROP SEQUENCE - remove a sequence
@@ -2399,6 +2584,149 @@ drop_sequence_stmt:
   }
 | DROP SEQUENCE error // SHOW HELP: DROP VIEW
 
+// %Help: DROP SCHEMA - remove a schema
+// %Category: DDL
+// %Text: DROP SCHEMA [IF EXISTS] <schema_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE SCHEMA
+drop_schema_stmt:
+  DROP SCHEMA name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{Names: $3.nameList(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP SCHEMA IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{Names: $5.nameList(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP SCHEMA error // SHOW HELP: DROP SCHEMA
+
+// %Help: DROP TYPE - remove a type
+// %Category: DDL
+// %Text: DROP TYPE [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TYPE, ALTER TYPE
+drop_type_stmt:
+  DROP TYPE type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP TYPE IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TYPE error // SHOW HELP: DROP TYPE
+
+// %Help: DROP DOMAIN - remove a domain
+// %Category: DDL
+// %Text: DROP DOMAIN [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE DOMAIN
+drop_domain_stmt:
+  DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP DOMAIN
+
+type_name_list:
+  type_name
+  {
+    $$.val = []*tree.UnresolvedObjectName{$1.unresolvedObjectName()}
+  }
+| type_name_list ',' type_name
+  {
+    $$.val = append($1.unresolvedObjectNames(), $3.unresolvedObjectName())
+  }
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <tablename> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      IfExists: false,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+// %Help: DROP FUNCTION - remove a user-defined function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [ ( [ <argtype> [, ...] ] ) ] [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_function_stmt:
+  DROP FUNCTION func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.funcObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+func_obj_list:
+  func_obj
+  {
+    $$.val = []tree.FuncObj{$1.funcObj()}
+  }
+| func_obj_list ',' func_obj
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+func_obj:
+  db_object_name
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName()}
+  }
+| db_object_name '(' opt_func_param_list ')'
+  {
+    $$.val = tree.FuncObj{
+      FuncName: $1.unresolvedObjectName(),
+      HasParams: true,
+      Params: $3.funcParams(),
+    }
+  }
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -2553,6 +2881,7 @@ preparable_stmt:
 | import_stmt       // EXTEND WITH HELP: IMPORT
 | insert_stmt       // EXTEND WITH HELP: INSERT
 | pause_stmt        // EXTEND WITH HELP: PAUSE JOBS
+| refresh_stmt      // EXTEND WITH HELP: REFRESH
 | reset_stmt        // help texts in sub-rule
 | restore_stmt      // EXTEND WITH HELP: RESTORE
 | resume_stmt       // EXTEND WITH HELP: RESUME JOBS
@@ -2694,11 +3023,12 @@ deallocate_stmt:
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
+//   SCHEMA <schemaname> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: REVOKE, WEBDOCS/grant.html
 grant_stmt:
-  GRANT privileges ON targets TO name_list
+  GRANT privileges ON grant_targets TO name_list
   {
     $$.val = &tree.Grant{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
@@ -2725,11 +3055,12 @@ grant_stmt:
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
+//   SCHEMA <schemaname> [, <schemaname>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: GRANT, WEBDOCS/revoke.html
 revoke_stmt:
-  REVOKE privileges ON targets FROM name_list
+  REVOKE privileges ON grant_targets FROM name_list
   {
     $$.val = &tree.Revoke{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
@@ -2825,7 +3156,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
 
 // SET SESSION / SET CLUSTER SETTING
@@ -3008,6 +3339,34 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Deferred constraints are checked when the transaction commits.
+// Immediate constraints are checked at the end of each statement.
+// Named constraints are looked up in the tables of the current database.
+// %SeeAlso: SET TRANSACTION, CREATE TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS ALL DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Deferred: true}
+  }
+| SET CONSTRAINTS ALL IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Deferred: false}
+  }
+| SET CONSTRAINTS name_list DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: true}
+  }
+| SET CONSTRAINTS name_list IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: false}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -3910,6 +4269,16 @@ targets:
     $$.val = tree.TargetList{Databases: $2.nameList()}
   }
 
+// grant_targets is the variant of targets which recognizes ON SCHEMA
+// with a name list. This cannot be included in targets directly
+// because some statements must not recognize this syntax.
+grant_targets:
+  SCHEMA name_list
+  {
+    $$.val = tree.TargetList{Schemas: $2.nameList()}
+  }
+| targets
+
 // target_roles is the variant of targets which recognizes ON ROLES
 // with a name list. This cannot be included in targets directly
 // because some statements must not recognize this syntax.
@@ -3955,8 +4324,8 @@ pause_stmt:
 // %Help: CREATE TABLE - create a new table
 // %Category: DDL
 // %Text:
-// CREATE TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
-// CREATE TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
+// CREATE [TEMPORARY] TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
+// CREATE [TEMPORARY] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
 //
 // Table elements:
 //    <name> <type> [<qualifiers...>]
@@ -3985,11 +4354,12 @@ pause_stmt:
 // WEBDOCS/create-table.html
 // WEBDOCS/create-table-as.html
 create_table_stmt:
-  CREATE opt_temp TABLE table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by opt_table_with
+  CREATE opt_temp_create_table TABLE table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by opt_table_with
   {
     name := $4.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateTable{
       Table: name,
+      Temporary: $2.bool(),
       IfNotExists: false,
       Interleave: $8.interleave(),
       Defs: $6.tblDefs(),
@@ -3998,11 +4368,12 @@ create_table_stmt:
       PartitionBy: $9.partitionBy(),
     }
   }
-| CREATE opt_temp TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by opt_table_with
+| CREATE opt_temp_create_table TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by opt_table_with
   {
     name := $7.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateTable{
       Table: name,
+      Temporary: $2.bool(),
       IfNotExists: true,
       Interleave: $11.interleave(),
       Defs: $9.tblDefs(),
@@ -4018,11 +4389,12 @@ opt_table_with:
 | WITH name error { return unimplemented(sqllex, "create table with " + $2) }
 
 create_table_as_stmt:
-  CREATE opt_temp TABLE table_name opt_column_list opt_table_with AS select_stmt opt_create_as_data
+  CREATE opt_temp_create_table TABLE table_name opt_column_list opt_table_with AS select_stmt opt_create_as_data
   {
     name := $4.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateTable{
       Table: name,
+      Temporary: $2.bool(),
       IfNotExists: false,
       Interleave: nil,
       Defs: nil,
@@ -4030,11 +4402,12 @@ create_table_as_stmt:
       AsColumnNames: $5.nameList(),
     }
   }
-| CREATE opt_temp TABLE IF NOT EXISTS table_name opt_column_list opt_table_with AS select_stmt opt_create_as_data
+| CREATE opt_temp_create_table TABLE IF NOT EXISTS table_name opt_column_list opt_table_with AS select_stmt opt_create_as_data
   {
     name := $7.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateTable{
       Table: name,
+      Temporary: $2.bool(),
       IfNotExists: true,
       Interleave: nil,
       Defs: nil,
@@ -4069,6 +4442,16 @@ opt_temp:
 | UNLOGGED          { return unimplemented(sqllex, "create unlogged") }
 | /*EMPTY*/         { /* no error */ }
 
+opt_temp_create_table:
+  TEMPORARY         { $$.val = true }
+| TEMP              { $$.val = true }
+| LOCAL TEMPORARY   { $$.val = true }
+| LOCAL TEMP        { $$.val = true }
+| GLOBAL TEMPORARY  { $$.val = true }
+| GLOBAL TEMP       { $$.val = true }
+| UNLOGGED          { return unimplemented(sqllex, "create unlogged") }
+| /*EMPTY*/         { $$.val = false }
+
 opt_table_elem_list:
   table_elem_list
 | /* EMPTY */
@@ -4303,7 +4686,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
  {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -4311,6 +4694,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrable: $6.constraintDeferrability(),
     }
  }
 | AS '(' a_expr ')' STORED
@@ -4319,16 +4703,16 @@ col_qualification_elem:
  }
 | AS '(' a_expr ')' VIRTUAL
  {
-    return unimplemented(sqllex, "virtual computed columns")
+    $$.val = &tree.ColumnComputedDef{Expr: $3.expr(), Virtual: true}
  }
 | AS error
  {
-    sqllex.Error("syntax error: use AS ( <expr> ) STORED")
+    sqllex.Error("syntax error: use AS ( <expr> ) STORED or AS ( <expr> ) VIRTUAL")
     return 1
  }
 
 index_def:
-  INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
+  INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
   {
     $$.val = &tree.IndexTableDef{
       Name:    tree.Name($2),
@@ -4336,9 +4720,10 @@ index_def:
       Storing: $6.nameList(),
       Interleave: $7.interleave(),
       PartitionBy: $8.partitionBy(),
+      Predicate: $9.expr(),
     }
   }
-| UNIQUE INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
+| UNIQUE INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef {
@@ -4347,6 +4732,7 @@ index_def:
         Storing: $7.nameList(),
         Interleave: $8.interleave(),
         PartitionBy: $9.partitionBy(),
+        Predicate: $10.expr(),
       },
     }
   }
@@ -4385,11 +4771,15 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by  opt_deferrable
+| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where opt_deferrable
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef{
@@ -4397,7 +4787,9 @@ constraint_elem:
         Storing: $5.nameList(),
         Interleave: $6.interleave(),
         PartitionBy: $7.partitionBy(),
+        Predicate: $8.expr(),
       },
+      Deferrable: $9.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')'
@@ -4419,16 +4811,37 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrable: $11.constraintDeferrability(),
     }
   }
 
+// INITIALLY DEFERRED implies DEFERRABLE, while INITIALLY IMMEDIATE on its own
+// leaves the constraint not deferrable, as in Postgres.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrable
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrable
+  }
 
 storing:
   COVERING
@@ -4631,6 +5044,20 @@ sequence_option_elem:
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptStart, IntVal: &x, OptionalWord: true} }
 | VIRTUAL                      { $$.val = tree.SequenceOption{Name: tree.SeqOptVirtual} }
 
+// %Help: REFRESH - recompute a materialized view
+// %Category: DDL
+// %Text: REFRESH MATERIALIZED VIEW <viewname> [AS OF SYSTEM TIME <expr>]
+// %SeeAlso: CREATE VIEW
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW view_name opt_as_of_clause
+  {
+    $$.val = &tree.RefreshMaterializedView{
+      Name: $4.unresolvedObjectName(),
+      AsOf: $5.asOfClause(),
+    }
+  }
+| REFRESH error // SHOW HELP: REFRESH
+
 // %Help: TRUNCATE - empty one or more tables
 // %Category: DML
 // %Text: TRUNCATE [TABLE] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -4690,8 +5117,8 @@ role_or_group:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE VIEW <viewname> [( <colnames...> )] AS <source>
-// %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
+// %Text: CREATE [MATERIALIZED] VIEW <viewname> [( <colnames...> )] AS <source>
+// %SeeAlso: CREATE TABLE, REFRESH, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
   {
@@ -4702,28 +5129,305 @@ create_view_stmt:
       AsSource: $8.slct(),
     }
   }
+| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+  {
+    name := $4.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateView{
+      Name: name,
+      ColumnNames: $5.nameList(),
+      AsSource: $7.slct(),
+      Materialized: true,
+    }
+  }
 | CREATE OR REPLACE opt_temp opt_view_recursive VIEW error { return unimplementedWithIssue(sqllex, 24897) }
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
+| CREATE MATERIALIZED VIEW error // SHOW HELP: CREATE VIEW
 
 opt_view_recursive:
   /* EMPTY */ { /* no error */ }
 | RECURSIVE { return unimplemented(sqllex, "create recursive view") }
 
-// CREATE TYPE/DOMAIN is not yet supported by CockroachDB but we
-// want to report it with the right issue number.
+// %Help: CREATE SCHEMA - create a new schema
+// %Category: DDL
+// %Text: CREATE SCHEMA [IF NOT EXISTS] <schema_name>
+// %SeeAlso: DROP SCHEMA, SHOW SCHEMAS
+create_schema_stmt:
+  CREATE SCHEMA schema_name
+  {
+    $$.val = &tree.CreateSchema{Schema: tree.Name($3)}
+  }
+| CREATE SCHEMA IF NOT EXISTS schema_name
+  {
+    $$.val = &tree.CreateSchema{Schema: tree.Name($6), IfNotExists: true}
+  }
+| CREATE SCHEMA error // SHOW HELP: CREATE SCHEMA
+
+// %Help: CREATE FUNCTION - create a user-defined function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [ [<argname>] <argtype> [, ...] ] )
+//   RETURNS <rettype>
+//   { LANGUAGE SQL
+//   | IMMUTABLE | STABLE | VOLATILE
+//   | CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT
+//   | AS '<definition>'
+//   } ...
+// %SeeAlso: DROP FUNCTION
+create_function_stmt:
+  CREATE opt_or_replace FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS typename func_option_list
+  {
+    $$.val = &tree.CreateFunction{
+      FuncName: $4.unresolvedObjectName(),
+      Replace: $2.bool(),
+      Params: $6.funcParams(),
+      ReturnType: $9.colType(),
+      FunctionOptions: *$10.functionOptions(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS TABLE error
+  {
+    return unimplementedWithIssueDetail(sqllex, 17511, "returns table")
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_param_list:
+  func_param_list
+  {
+    $$.val = $1.funcParams()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.FuncParams(nil)
+  }
+
+func_param_list:
+  func_param
+  {
+    $$.val = tree.FuncParams{$1.funcParam()}
+  }
+| func_param_list ',' func_param
+  {
+    $$.val = append($1.funcParams(), $3.funcParam())
+  }
+
+// Parameter names are restricted to identifiers, since type names such as
+// STRING or DATE are also unreserved keywords. Keywords can be used as
+// parameter names by quoting them.
+func_param:
+  IDENT typename
+  {
+    $$.val = tree.FuncParam{Name: tree.Name($1), Type: $2.colType()}
+  }
+| typename
+  {
+    $$.val = tree.FuncParam{Type: $1.colType()}
+  }
+
+func_option_list:
+  func_option
+  {
+    $$.val = $1.functionOptions()
+  }
+| func_option_list func_option
+  {
+    a := $1.functionOptions()
+    if err := a.CombineWith($2.functionOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = a
+  }
+
+func_option:
+  LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = tree.LanguageOption(tree.Name($2))
+  }
+| IMMUTABLE
+  {
+    $$.val = tree.VolatilityOption(tree.FunctionImmutable)
+  }
+| STABLE
+  {
+    $$.val = tree.VolatilityOption(tree.FunctionStable)
+  }
+| VOLATILE
+  {
+    $$.val = tree.VolatilityOption(tree.FunctionVolatile)
+  }
+| CALLED ON NULL INPUT
+  {
+    $$.val = tree.StrictOption(false)
+  }
+| RETURNS NULL ON NULL INPUT
+  {
+    $$.val = tree.StrictOption(true)
+  }
+| STRICT
+  {
+    $$.val = tree.StrictOption(true)
+  }
+| AS SCONST
+  {
+    $$.val = tree.BodyOption($2)
+  }
+| AS SCONST ',' SCONST
+  {
+    return unimplementedWithIssueDetail(sqllex, 17511, "c function")
+  }
+
+// %Help: CREATE TRIGGER - create a trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER <name> { BEFORE | AFTER } <event> [ OR ... ]
+//   ON <tablename> FOR EACH { ROW | STATEMENT } AS '<statement>'
+//
+// Events:
+//   INSERT, UPDATE, DELETE
+//
+// The statement of a row-level trigger refers to the values of the affected
+// row as NEW.<column> and OLD.<column>. The result of a SELECT statement in a
+// BEFORE trigger replaces the columns of the row it names; if it returns no
+// row, the row is skipped. A statement-level trigger fires once per statement.
+// %SeeAlso: DROP TRIGGER, SHOW CREATE
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH ROW AS SCONST
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      Body: $12,
+    }
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH STATEMENT AS SCONST
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      ForEachStatement: true,
+      Body: $12,
+    }
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH ROW EXECUTE error
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "execute function")
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH STATEMENT EXECUTE error
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "execute function")
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerAfter
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = $1.triggerEvents()
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = $1.triggerEvents() | $3.triggerEvents()
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = tree.TriggerInsert
+  }
+| UPDATE
+  {
+    $$.val = tree.TriggerUpdate
+  }
+| DELETE
+  {
+    $$.val = tree.TriggerDelete
+  }
+
+// %Help: CREATE TYPE - create a type
+// %Category: DDL
+// %Text: CREATE TYPE <type_name> AS ENUM (...)
+// %SeeAlso: ALTER TYPE, DROP TYPE
 create_type_stmt:
-  // Record/Composite types.
-  CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Enum types.
-| CREATE TYPE type_name AS ENUM '(' error { return unimplementedWithIssue(sqllex, 24873) }
+  CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Enum,
+      EnumLabels: $7.enumValueList(),
+    }
+  }
+| CREATE TYPE error // SHOW HELP: CREATE TYPE
+  // Record/Composite types.
+| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
 | CREATE TYPE type_name '(' error         { return unimplementedWithIssueDetail(sqllex, 27793, "base") }
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
-  // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+
+// %Help: CREATE DOMAIN - create a domain
+// %Category: DDL
+// %Text:
+// CREATE DOMAIN <type_name> [AS] <typename> [<constraint> ...]
+//
+// Constraints:
+//    [CONSTRAINT <name>] NOT NULL
+//    [CONSTRAINT <name>] NULL
+//    [CONSTRAINT <name>] CHECK (<expr>)
+//    DEFAULT <expr>
+//
+// The CHECK expressions refer to the value being checked as VALUE.
+// The base type must be a built-in type. The type of a column cannot be
+// changed from or to a domain with ALTER COLUMN TYPE.
+// %SeeAlso: DROP DOMAIN, CREATE TYPE
+create_domain_stmt:
+  CREATE DOMAIN type_name opt_as typename col_qual_list
+  {
+    n, err := tree.NewCreateDomain($3.unresolvedObjectName(), $5.colType(), $6.colQuals())
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = n
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE DOMAIN
+
+opt_as:
+  AS {}
+| /* EMPTY */ {}
+
+opt_enum_val_list:
+  enum_val_list
+  {
+    $$.val = $1.enumValueList()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.EnumValueList(nil)
+  }
+
+enum_val_list:
+  SCONST
+  {
+    $$.val = tree.EnumValueList{tree.EnumValue($1)}
+  }
+| enum_val_list ',' SCONST
+  {
+    $$.val = append($1.enumValueList(), tree.EnumValue($3))
+  }
 
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
@@ -4750,6 +5454,7 @@ create_index_stmt:
       Interleave: $12.interleave(),
       PartitionBy: $13.partitionBy(),
       Inverted: $7.bool(),
+      Predicate: $14.expr(),
     }
   }
 | CREATE opt_unique INDEX IF NOT EXISTS index_name ON table_name opt_using_gin_btree '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
@@ -4765,6 +5470,7 @@ create_index_stmt:
       Interleave:  $15.interleave(),
       PartitionBy: $16.partitionBy(),
       Inverted:    $10.bool(),
+      Predicate:   $17.expr(),
     }
   }
 | CREATE opt_unique INVERTED INDEX opt_index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
@@ -4779,6 +5485,7 @@ create_index_stmt:
       Storing:     $11.nameList(),
       Interleave:  $12.interleave(),
       PartitionBy: $13.partitionBy(),
+      Predicate:   $14.expr(),
     }
   }
 | CREATE opt_unique INVERTED INDEX IF NOT EXISTS index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
@@ -4794,24 +5501,31 @@ create_index_stmt:
       Storing:     $14.nameList(),
       Interleave:  $15.interleave(),
       PartitionBy: $16.partitionBy(),
+      Predicate:   $17.expr(),
     }
   }
 | CREATE opt_unique INDEX error // SHOW HELP: CREATE INDEX
 
 opt_idx_where:
-  /* EMPTY */ { /* no error */ }
-| WHERE error { return unimplementedWithIssue(sqllex, 9683) }
+  WHERE a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
 
 opt_using_gin_btree:
   USING name
   {
     /* FORCE DOC */
     switch $2 {
-      case "gin":
+      case "gin", "gist":
         $$.val = true
       case "btree":
         $$.val = false
-      case "hash", "gist", "spgist", "brin":
+      case "hash", "spgist", "brin":
         return unimplemented(sqllex, "index using " + $2)
       default:
         sqllex.Error("unrecognized access method: " + $2)
@@ -4953,9 +5667,11 @@ opt_set_data:
   SET DATA {}
 | /* EMPTY */ {}
 
-// %Help: RELEASE - complete a retryable block
+// %Help: RELEASE - complete a retryable block or destroy a savepoint
 // %Category: Txn
-// %Text: RELEASE [SAVEPOINT] cockroach_restart
+// %Text:
+// RELEASE [SAVEPOINT] cockroach_restart
+// RELEASE [SAVEPOINT] <savepoint name>
 // %SeeAlso: SAVEPOINT, WEBDOCS/savepoint.html
 release_stmt:
   RELEASE savepoint_name
@@ -4986,9 +5702,11 @@ resume_stmt:
   }
 | RESUME error // SHOW HELP: RESUME JOBS
 
-// %Help: SAVEPOINT - start a retryable block
+// %Help: SAVEPOINT - start a retryable block or establish a savepoint
 // %Category: Txn
-// %Text: SAVEPOINT cockroach_restart
+// %Text:
+// SAVEPOINT cockroach_restart
+// SAVEPOINT <savepoint name>
 // %SeeAlso: RELEASE, WEBDOCS/savepoint.html
 savepoint_stmt:
   SAVEPOINT name
@@ -5058,7 +5776,7 @@ opt_abort_mod:
 
 // %Help: ROLLBACK - abort the current transaction
 // %Category: Txn
-// %Text: ROLLBACK [TRANSACTION] [TO [SAVEPOINT] cockroach_restart]
+// %Text: ROLLBACK [TRANSACTION] [TO [SAVEPOINT] { cockroach_restart | <savepoint name> }]
 // %SeeAlso: BEGIN, COMMIT, SAVEPOINT, WEBDOCS/rollback-transaction.html
 rollback_stmt:
   ROLLBACK opt_to_savepoint
@@ -5354,23 +6072,30 @@ insert_column_item:
 on_conflict:
   ON CONFLICT opt_conf_expr DO UPDATE SET set_clause_list opt_where_clause
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), Exprs: $7.updateExprs(), Where: tree.NewWhere(tree.AstWhere, $8.expr())}
+    oc := $3.onConflict()
+    oc.Exprs = $7.updateExprs()
+    oc.Where = tree.NewWhere(tree.AstWhere, $8.expr())
+    $$.val = oc
   }
 | ON CONFLICT opt_conf_expr DO NOTHING
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), DoNothing: true}
+    oc := $3.onConflict()
+    oc.DoNothing = true
+    $$.val = oc
   }
 
 opt_conf_expr:
-  '(' name_list ')'
+  '(' name_list ')' opt_where_clause
   {
-    $$.val = $2.nameList()
+    $$.val = &tree.OnConflict{Columns: $2.nameList(), ArbiterPredicate: $4.expr()}
+  }
+| ON CONSTRAINT constraint_name
+  {
+    $$.val = &tree.OnConflict{Constraint: tree.Name($3)}
   }
-| '(' name_list ')' where_clause { return unimplementedWithIssue(sqllex, 32557) }
-| ON CONSTRAINT constraint_name { return unimplementedWithIssue(sqllex, 28161) }
 | /* EMPTY */
   {
-    $$.val = tree.NameList(nil)
+    $$.val = &tree.OnConflict{}
   }
 
 returning_clause:
@@ -5393,6 +6118,7 @@ returning_clause:
 // %Text:
 // UPDATE <tablename> [[AS] <name>]
 //        SET ...
+//        [FROM <source>]
 //        [WHERE <expr>]
 //        [ORDER BY <exprs...>]
 //        [LIMIT <expr>]
@@ -5406,6 +6132,7 @@ update_stmt:
       With: $1.with(),
       Table: $3.tblExpr(),
       Exprs: $5.updateExprs(),
+      From: $6.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $7.expr()),
       OrderBy: $8.orderBy(),
       Limit: $9.limit(),
@@ -5414,10 +6141,15 @@ update_stmt:
   }
 | opt_with_clause UPDATE error // SHOW HELP: UPDATE
 
-// Mark this as unimplemented until the normal from_clause is supported here.
 update_from_clause:
-  FROM from_list { return unimplementedWithIssue(sqllex, 7841) }
-| /* EMPTY */ {}
+  FROM from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 set_clause_list:
   set_clause
@@ -5512,34 +6244,81 @@ select_with_parens:
 //      clause.
 //      - 2002-08-28 bjm
 select_no_parens:
-  simple_select opt_for
+  simple_select
   {
     $$.val = &tree.Select{Select: $1.selectStmt()}
   }
-| select_clause sort_clause opt_for
+| select_clause sort_clause
   {
     $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy()}
   }
-| select_clause opt_sort_clause select_limit opt_for
+| select_clause opt_sort_clause for_locking_clause opt_select_limit
+  {
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $4.limit(), Locking: $3.lockingClause()}
+  }
+| select_clause opt_sort_clause select_limit opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit(), Locking: $4.lockingClause()}
   }
-| with_clause select_clause opt_for
+| with_clause select_clause
   {
     $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt()}
   }
-| with_clause select_clause sort_clause opt_for
+| with_clause select_clause sort_clause
   {
     $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
   }
-| with_clause select_clause opt_sort_clause select_limit opt_for
+| with_clause select_clause opt_sort_clause for_locking_clause opt_select_limit
+  {
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $5.limit(), Locking: $4.lockingClause()}
+  }
+| with_clause select_clause opt_sort_clause select_limit opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit(), Locking: $5.lockingClause()}
   }
 
-opt_for:
-  /* EMPTY */ { /* no error */ }
-| FOR error { return unimplementedWithIssue(sqllex, 6583) }
+for_locking_clause:
+  for_locking_items { $$.val = $1.lockingClause() }
+| FOR READ ONLY     { $$.val = (tree.LockingClause)(nil) }
+
+opt_for_locking_clause:
+  for_locking_clause { $$.val = $1.lockingClause() }
+| /* EMPTY */        { $$.val = (tree.LockingClause)(nil) }
+
+for_locking_items:
+  for_locking_item
+  {
+    $$.val = tree.LockingClause{$1.lockingItem()}
+  }
+| for_locking_items for_locking_item
+  {
+    $$.val = append($1.lockingClause(), $2.lockingItem())
+  }
+
+for_locking_item:
+  for_locking_strength opt_locked_rels opt_nowait_or_skip
+  {
+    $$.val = &tree.LockingItem{
+      Strength:   $1.lockingStrength(),
+      Targets:    $2.tableNames(),
+      WaitPolicy: $3.lockingWaitPolicy(),
+    }
+  }
+
+for_locking_strength:
+  FOR UPDATE        { $$.val = tree.ForUpdate }
+| FOR NO KEY UPDATE { $$.val = tree.ForNoKeyUpdate }
+| FOR SHARE         { $$.val = tree.ForShare }
+| FOR KEY SHARE     { $$.val = tree.ForKeyShare }
+
+opt_locked_rels:
+  /* EMPTY */        { $$.val = tree.TableNames{} }
+| OF table_name_list { $$.val = $2.tableNames() }
+
+opt_nowait_or_skip:
+  /* EMPTY */ { $$.val = tree.LockWaitBlock }
+| SKIP LOCKED { $$.val = tree.LockWaitSkip }
+| NOWAIT      { $$.val = tree.LockWaitError }
 
 select_clause:
 // We only provide help if an open parenthesis is provided, because
@@ -5598,6 +6377,7 @@ simple_select:
 //        [ ORDER BY <expr> [ ASC | DESC ] [, ...] ]
 //        [ LIMIT { <expr> | ALL } ]
 //        [ OFFSET <expr> [ ROW | ROWS ] ]
+//        [ FOR { UPDATE | NO KEY UPDATE | SHARE | KEY SHARE } [ OF <tablename> [, ...] ] [ NOWAIT | SKIP LOCKED ] ]
 // %SeeAlso: WEBDOCS/select-clause.html
 simple_select_clause:
   SELECT opt_all_clause target_list
@@ -5706,7 +6486,10 @@ with_clause:
     /* SKIP DOC */
     $$.val = &tree.With{CTEList: $2.ctes()}
   }
-| WITH RECURSIVE cte_list { return unimplementedWithIssue(sqllex, 21085) }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &tree.With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
   common_table_expr
@@ -5849,6 +6632,10 @@ select_limit:
 | limit_clause
 | offset_clause
 
+opt_select_limit:
+  select_limit { $$.val = $1.limit() }
+| /* EMPTY */  { $$.val = (*tree.Limit)(nil) }
+
 opt_limit_clause:
   limit_clause
 | /* EMPTY */ { $$.val = (*tree.Limit)(nil) }
@@ -5928,7 +6715,7 @@ first_or_next:
 // Each item in the group_clause list is either an expression tree or a
 // GroupingSet node of some type.
 group_clause:
-  GROUP BY expr_list
+  GROUP BY group_by_list
   {
     $$.val = tree.GroupBy($3.exprs())
   }
@@ -5937,6 +6724,32 @@ group_clause:
     $$.val = tree.GroupBy(nil)
   }
 
+group_by_list:
+  group_by_item
+  {
+    $$.val = tree.Exprs{$1.expr()}
+  }
+| group_by_list ',' group_by_item
+  {
+    $$.val = append($1.exprs(), $3.expr())
+  }
+
+// The empty grouping set () is parsed as an empty tuple by a_expr.
+group_by_item:
+  a_expr
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSets{Type: tree.GroupingSetsRollup, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSets{Type: tree.GroupingSetsCube, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSets{Type: tree.GroupingSetsExplicit, Exprs: $4.exprs()}
+  }
+
 having_clause:
   HAVING a_expr
   {
@@ -6466,7 +7279,7 @@ typename:
       $$.val = $1.colType()
     }
   }
-  // SQL standard syntax, currently only one-dimensional
+  // SQL standard syntax, only one-dimensional
   // Undocumented but support for potential Postgres compat
 | simple_typename ARRAY '[' ICONST ']' {
     /* SKIP DOC */
@@ -6476,7 +7289,6 @@ typename:
       return setErr(sqllex, err)
     }
   }
-| simple_typename ARRAY '[' ICONST ']' '[' error { return unimplementedWithIssue(sqllex, 32552) }
 | simple_typename ARRAY {
     var err error
     $$.val, err = arrayOf($1.colType(), nil)
@@ -6496,20 +7308,16 @@ cast_target:
   }
 
 opt_array_bounds:
-  // TODO(justin): reintroduce multiple array bounds
-  // opt_array_bounds '[' ']' { $$.val = append($1.int32s(), -1) }
-  '[' ']' { $$.val = []int32{-1} }
-| '[' ']' '[' error { return unimplementedWithIssue(sqllex, 32552) }
-| '[' ICONST ']'
+  opt_array_bounds '[' ']' { $$.val = append($1.int32s(), -1) }
+| opt_array_bounds '[' ICONST ']'
   {
     /* SKIP DOC */
-    bound, err := $2.numVal().AsInt32()
+    bound, err := $3.numVal().AsInt32()
     if err != nil {
       return setErr(sqllex, err)
     }
-    $$.val = []int32{bound}
+    $$.val = append($1.int32s(), bound)
   }
-| '[' ICONST ']' '[' error { return unimplementedWithIssue(sqllex, 32552) }
 | /* EMPTY */ { $$.val = []int32(nil) }
 
 const_json:
@@ -6617,7 +7425,7 @@ const_typename:
     // See https://www.postgresql.org/docs/9.1/static/datatype-character.html
     // Postgres supports a special character type named "char" (with the quotes)
     // that is a single-character column type. It's used by system tables.
-    // Eventually this clause will be used to parse user-defined types as well,
+    // This clause is also used to parse references to user-defined types,
     // since their names can be quoted.
     if $1 == "char" {
       $$.val = types.MakeQChar(0)
@@ -6628,10 +7436,9 @@ const_typename:
       if !ok {
           switch unimp {
               case 0:
-                // Note: we can only report an unimplemented error for specific
-                // known type names. Anything else may return PII.
-                sqllex.Error("type does not exist")
-                return 1
+                // This is not a builtin type name. It may refer to a
+                // user-defined type, which is resolved during planning.
+                $$.val = types.MakeUnresolvedTypeReference([]string{$1})
               case -1:
                 return unimplemented(sqllex, "type name " + $1)
               default:
@@ -6872,8 +7679,11 @@ const_datetime:
   }
 | TIME opt_timezone
   {
-    if $2.bool() { return unimplementedWithIssueDetail(sqllex, 26097, "type") }
-    $$.val = types.Time
+    if $2.bool() {
+      $$.val = types.TimeTZ
+    } else {
+      $$.val = types.Time
+    }
   }
 | TIME '(' iconst32 ')' opt_timezone
   {
@@ -6881,10 +7691,24 @@ const_datetime:
     if prec != 6 {
          return unimplementedWithIssue(sqllex, 32565)
     }
-    $$.val = types.MakeTime(prec)
+    if $5.bool() {
+      $$.val = types.MakeTimeTZ(prec)
+    } else {
+      $$.val = types.MakeTime(prec)
+    }
+  }
+| TIMETZ
+  {
+    $$.val = types.TimeTZ
+  }
+| TIMETZ '(' iconst32 ')'
+  {
+    prec := $3.int32()
+    if prec != 6 {
+         return unimplementedWithIssue(sqllex, 32565)
+    }
+    $$.val = types.MakeTimeTZ(prec)
   }
-| TIMETZ                             { return unimplementedWithIssueDetail(sqllex, 26097, "type") }
-| TIMETZ '(' ICONST ')'              { return unimplementedWithIssueDetail(sqllex, 26097, "type with precision") }
 | TIMESTAMP opt_timezone
   {
     if $2.bool() {
@@ -7032,7 +7856,10 @@ a_expr:
   {
     $$.val = &tree.CollateExpr{Expr: $1.expr(), Locale: $3}
   }
-| a_expr AT TIME ZONE a_expr %prec AT { return unimplementedWithIssue(sqllex, 32005) }
+| a_expr AT TIME ZONE a_expr %prec AT
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("timezone"), Exprs: tree.Exprs{$5.expr(), $1.expr()}}
+  }
   // These operators must be called out explicitly in order to make use of
   // bison's automatic operator-precedence handling. All other operator names
   // are handled by the generic productions using "OP", below; and all those
@@ -7163,7 +7990,7 @@ a_expr:
   }
 | a_expr INET_CONTAINS_OR_CONTAINED_BY a_expr
   {
-    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_contained_by"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
+    $$.val = &tree.ComparisonExpr{Operator: tree.Overlaps, Left: $1.expr(), Right: $3.expr()}
   }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
@@ -7647,7 +8474,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingExpr{Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -7725,7 +8555,7 @@ func_expr_common_subexpr:
   }
 | CURRENT_TIME
   {
-    return unimplementedWithIssueDetail(sqllex, 26097, "current_time")
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction($1)}
   }
 | CURRENT_USER
   {
@@ -7805,7 +8635,7 @@ special_function:
 | CURRENT_TIMESTAMP '(' error { return helpWithFunctionByName(sqllex, $1) }
 | CURRENT_TIME '(' ')'
   {
-    return unimplementedWithIssueDetail(sqllex, 26097, "current_time")
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction($1)}
   }
 | CURRENT_TIME '(' error { return helpWithFunctionByName(sqllex, $1) }
 | CURRENT_USER '(' ')'
@@ -8686,6 +9516,8 @@ constraint_name:       name
 
 database_name:         name
 
+schema_name:           name
+
 column_name:           name
 
 family_name:           name
@@ -8893,11 +9725,13 @@ unreserved_keyword:
 | ACTION
 | ADD
 | ADMIN
+| AFTER
 | AGGREGATE
 | ALTER
 | AT
 | AUTOMATIC
 | BACKUP
+| BEFORE
 | BEGIN
 | BIGSERIAL
 | BLOB
@@ -8906,6 +9740,7 @@ unreserved_keyword:
 | BYTEA
 | BYTES
 | CACHE
+| CALLED
 | CANCEL
 | CASCADE
 | CHANGEFEED
@@ -8938,6 +9773,7 @@ unreserved_keyword:
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
 | ENCODING
 | ENUM
 | ESCAPE
@@ -8968,12 +9804,14 @@ unreserved_keyword:
 | HISTOGRAM
 | HOUR
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCREMENT
 | INCREMENTAL
 | INDEXES
 | INET
 | INJECT
+| INPUT
 | INSERT
 | INT2
 | INT2VECTOR
@@ -8998,6 +9836,7 @@ unreserved_keyword:
 | LEVEL
 | LIST
 | LOCAL
+| LOCKED
 | LOOKUP
 | LOW
 | MATCH
@@ -9013,6 +9852,7 @@ unreserved_keyword:
 | NEXT
 | NO
 | NORMAL
+| NOWAIT
 | NO_INDEX_JOIN
 | IGNORE_FOREIGN_KEYS
 | OF
@@ -9046,6 +9886,7 @@ unreserved_keyword:
 | READ
 | RECURSIVE
 | REF
+| REFRESH
 | REGCLASS
 | REGPROC
 | REGPROCEDURE
@@ -9059,6 +9900,7 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESUME
+| RETURNS
 | REVOKE
 | ROLE
 | ROLES
@@ -9066,8 +9908,11 @@ unreserved_keyword:
 | ROLLUP
 | ROWS
 | RULE
+| SETS
 | SETTING
 | SETTINGS
+| SHARE
+| SKIP
 | STATUS
 | SAVEPOINT
 | SCATTER
@@ -9092,7 +9937,9 @@ unreserved_keyword:
 | SMALLSERIAL
 | SNAPSHOT
 | SQL
+| STABLE
 | START
+| STATEMENT
 | STATISTICS
 | STDIN
 | STORE
@@ -9133,6 +9980,7 @@ unreserved_keyword:
 | VALUE
 | VARYING
 | VIEW
+| VOLATILE
 | WITHIN
 | WITHOUT
 | WRITE
@@ -24,6 +24,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -468,9 +469,11 @@ var (
 	relKindTable    = tree.NewDString("r")
 	relKindIndex    = tree.NewDString("i")
 	relKindView     = tree.NewDString("v")
+	relKindMatView  = tree.NewDString("m")
 	relKindSequence = tree.NewDString("S")
 
 	relPersistencePermanent = tree.NewDString("p")
+	relPersistenceTemporary = tree.NewDString("t")
 )
 
 var pgCatalogClassTable = virtualSchemaTable{
@@ -512,11 +515,17 @@ CREATE TABLE pg_catalog.pg_class (
 			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
 				// The only difference between tables, views and sequences is the relkind column.
 				relKind := relKindTable
-				if table.IsView() {
+				if table.MaterializedView() {
+					relKind = relKindMatView
+				} else if table.IsView() {
 					relKind = relKindView
 				} else if table.IsSequence() {
 					relKind = relKindSequence
 				}
+				relPersistence := relPersistencePermanent
+				if table.Temporary {
+					relPersistence = relPersistenceTemporary
+				}
 				namespaceOid := h.NamespaceOid(db, scName)
 				if err := addRow(
 					defaultOid(table.ID),      // oid
@@ -532,10 +541,10 @@ CREATE TABLE pg_catalog.pg_class (
 					zeroVal,                   // relallvisible
 					oidZero,                   // reltoastrelid
 					tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // relhasindex
-					tree.DBoolFalse,         // relisshared
-					relPersistencePermanent, // relPersistence
-					tree.DBoolFalse,         // relistemp
-					relKind,                 // relkind
+					tree.DBoolFalse, // relisshared
+					relPersistence,  // relPersistence
+					tree.MakeDBool(tree.DBool(table.Temporary)), // relistemp
+					relKind, // relkind
 					tree.NewDInt(tree.DInt(len(table.Columns))), // relnatts
 					tree.NewDInt(tree.DInt(len(table.Checks))),  // relchecks
 					tree.DBoolFalse, // relhasoids
@@ -574,9 +583,9 @@ CREATE TABLE pg_catalog.pg_class (
 						oidZero,                              // reltoastrelid
 						tree.DBoolFalse,                      // relhasindex
 						tree.DBoolFalse,                      // relisshared
-						relPersistencePermanent,              // relPersistence
-						tree.DBoolFalse,                      // relistemp
-						relKindIndex,                         // relkind
+						relPersistence,                       // relPersistence
+						tree.MakeDBool(tree.DBool(table.Temporary)), // relistemp
+						relKindIndex, // relkind
 						tree.NewDInt(tree.DInt(len(index.ColumnNames))), // relnatts
 						zeroVal,         // relchecks
 						tree.DBoolFalse, // relhasoids
@@ -1109,7 +1118,7 @@ CREATE TABLE pg_catalog.pg_shdescription (
 }
 
 var pgCatalogEnumTable = virtualSchemaTable{
-	comment: `enum types and labels (empty - feature does not exist)
+	comment: `enum types and labels
 https://www.postgresql.org/docs/9.5/catalog-pg-enum.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_enum (
@@ -1118,9 +1127,23 @@ CREATE TABLE pg_catalog.pg_enum (
   enumsortorder FLOAT,
   enumlabel STRING
 )`,
-	populate: func(_ context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Enum types are not currently supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTypeDesc(ctx, p, dbContext, func(_ *DatabaseDescriptor, typ *sqlbase.TypeDescriptor) error {
+			typOid := tree.NewDOid(tree.DInt(types.TypeIDToOID(uint32(typ.ID))))
+			for i := range typ.EnumMembers {
+				label := typ.EnumMembers[i].LogicalRepresentation
+				if err := addRow(
+					h.EnumEntryOid(typOid, label),    // oid
+					typOid,                           // enumtypid
+					tree.NewDFloat(tree.DFloat(i+1)), // enumsortorder
+					tree.NewDString(label),           // enumlabel
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	},
 }
 
@@ -1279,6 +1302,10 @@ CREATE TABLE pg_catalog.pg_index (
 					if err != nil {
 						return err
 					}
+					indpred := tree.DNull
+					if index.IsPartial() {
+						indpred = tree.NewDString(index.Predicate)
+					}
 					return addRow(
 						h.IndexOid(db, scName, table, index), // indexrelid
 						tableOid,                             // indrelid
@@ -1298,7 +1325,7 @@ CREATE TABLE pg_catalog.pg_index (
 						indclass,                                 // indclass
 						indoption,                                // indoption
 						tree.DNull,                               // indexprs
-						tree.DNull,                               // indpred
+						indpred,                                  // indpred
 					)
 				})
 			})
@@ -1398,6 +1425,13 @@ func indexDefFromDescriptor(
 		}
 		indexDef.Interleave = intlDef
 	}
+	if index.IsPartial() {
+		predicate, err := parser.ParseExpr(index.Predicate)
+		if err != nil {
+			return "", err
+		}
+		indexDef.Predicate = predicate
+	}
 	return indexDef.String(), nil
 }
 
@@ -1587,7 +1621,7 @@ var (
 )
 
 var pgCatalogProcTable = virtualSchemaTable{
-	comment: `built-in functions (incomplete)
+	comment: `built-in and user-defined functions (incomplete)
 https://www.postgresql.org/docs/9.5/catalog-pg-proc.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_proc (
@@ -1739,11 +1773,91 @@ CREATE TABLE pg_catalog.pg_proc (
 					}
 				}
 			}
-			return nil
+			return addUserDefinedFunctionRows(ctx, p, h, db, addRow)
 		})
 	},
 }
 
+// sqlLanguageOid is the OID of the sql language in Postgres, which is the
+// language of all user-defined functions.
+var sqlLanguageOid = tree.NewDOid(14)
+
+var functionVolatilityChar = map[sqlbase.FunctionDescriptor_Volatility]tree.Datum{
+	sqlbase.FunctionDescriptor_VOLATILE:  tree.NewDString("v"),
+	sqlbase.FunctionDescriptor_STABLE:    tree.NewDString("s"),
+	sqlbase.FunctionDescriptor_IMMUTABLE: tree.NewDString("i"),
+}
+
+// addUserDefinedFunctionRows adds the rows of pg_proc for the overloads of the
+// user-defined functions in the given database.
+func addUserDefinedFunctionRows(
+	ctx context.Context,
+	p *planner,
+	h oidHasher,
+	db *DatabaseDescriptor,
+	addRow func(...tree.Datum) error,
+) error {
+	nspOid := h.NamespaceOid(db, tree.PublicSchema)
+	for _, ref := range db.Functions {
+		fn, err := sqlbase.GetFunctionDescFromID(ctx, p.txn, ref.ID)
+		if err != nil {
+			return err
+		}
+		dArgTypes := tree.NewDArray(types.Oid)
+		dArgNames := tree.NewDArray(types.String)
+		hasArgNames := false
+		for i := range fn.Parameters {
+			param := &fn.Parameters[i]
+			if err := dArgTypes.Append(tree.NewDOid(tree.DInt(param.Type.Oid()))); err != nil {
+				return err
+			}
+			if err := dArgNames.Append(tree.NewDString(param.Name)); err != nil {
+				return err
+			}
+			hasArgNames = hasArgNames || param.Name != ""
+		}
+		argNames := tree.Datum(tree.DNull)
+		if hasArgNames {
+			argNames = dArgNames
+		}
+		if err := addRow(
+			h.UserDefinedFunctionOid(fn),          // oid
+			tree.NewDName(fn.Name),                // proname
+			nspOid,                                // pronamespace
+			tree.DNull,                            // proowner
+			sqlLanguageOid,                        // prolang
+			tree.DNull,                            // procost
+			tree.DNull,                            // prorows
+			oidZero,                               // provariadic
+			tree.DNull,                            // protransform
+			tree.DBoolFalse,                       // proisagg
+			tree.DBoolFalse,                       // proiswindow
+			tree.DBoolFalse,                       // prosecdef
+			tree.DBoolFalse,                       // proleakproof
+			tree.MakeDBool(tree.DBool(fn.Strict)), // proisstrict
+			tree.DBoolFalse,                       // proretset
+			functionVolatilityChar[fn.Volatility], // provolatile
+			tree.DNull,                            // proparallel
+			tree.NewDInt(tree.DInt(len(fn.Parameters))),  // pronargs
+			tree.NewDInt(tree.DInt(0)),                   // pronargdefaults
+			tree.NewDOid(tree.DInt(fn.ReturnType.Oid())), // prorettype
+			tree.NewDOidVectorFromDArray(dArgTypes),      // proargtypes
+			tree.DNull,                                   // proallargtypes
+			tree.DNull,                                   // proargmodes
+			argNames,                                     // proargnames
+			tree.DNull,                                   // proargdefaults
+			tree.DNull,                                   // protrftypes
+			tree.NewDString(fn.Body),                     // prosrc
+			tree.DNull,                                   // probin
+			tree.DNull,                                   // proconfig
+			tree.DNull,                                   // proacl
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var pgCatalogRangeTable = virtualSchemaTable{
 	comment: `range types (empty - feature does not exist)
 https://www.postgresql.org/docs/9.5/catalog-pg-range.html`,
@@ -2048,8 +2162,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = typTypeComposite
-	_ = typTypeDomain
-	_ = typTypeEnum
 	_ = typTypePseudo
 	_ = typTypeRange
 
@@ -2072,7 +2184,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = typCategoryComposite
-	_ = typCategoryEnum
 	_ = typCategoryGeometric
 	_ = typCategoryRange
 	_ = typCategoryBitString
@@ -2119,7 +2230,7 @@ CREATE TABLE pg_catalog.pg_type (
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		h := makeOidHasher()
-		return forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
+		if err := forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
 			nspOid := h.NamespaceOid(db, pgCatalogName)
 
 			for o, typ := range types.OidToType {
@@ -2194,10 +2305,115 @@ CREATE TABLE pg_catalog.pg_type (
 				}
 			}
 			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Now generate rows for user-defined types.
+		return forEachTypeDesc(ctx, p, dbContext, func(db *DatabaseDescriptor, typDesc *sqlbase.TypeDescriptor) error {
+			nspOid := h.NamespaceOid(db, tree.PublicSchema)
+			if typDesc.Domain != nil {
+				return addPGTypeDomainRow(h, nspOid, typDesc, addRow)
+			}
+			typ := typDesc.MakeTypesT()
+			return addRow(
+				tree.NewDOid(tree.DInt(typ.Oid())), // oid
+				tree.NewDName(typDesc.Name),        // typname
+				nspOid,                             // typnamespace
+				tree.DNull,                         // typowner
+				typLen(typ),                        // typlen
+				typByVal(typ),                      // typbyval
+				typTypeEnum,                        // typtype
+				typCategoryEnum,                    // typcategory
+				tree.DBoolFalse,                    // typispreferred
+				tree.DBoolTrue,                     // typisdefined
+				typDelim,                           // typdelim
+				oidZero,                            // typrelid
+				oidZero,                            // typelem
+				oidZero,                            // typarray
+
+				// regproc references
+				h.RegProc("enum_in"),   // typinput
+				h.RegProc("enum_out"),  // typoutput
+				h.RegProc("enum_recv"), // typreceive
+				h.RegProc("enum_send"), // typsend
+				oidZero,                // typmodin
+				oidZero,                // typmodout
+				oidZero,                // typanalyze
+
+				tree.DNull,      // typalign
+				tree.DNull,      // typstorage
+				tree.DBoolFalse, // typnotnull
+				oidZero,         // typbasetype
+				negOneVal,       // typtypmod
+				zeroVal,         // typndims
+				oidZero,         // typcollation
+				tree.DNull,      // typdefaultbin
+				tree.DNull,      // typdefault
+				tree.DNull,      // typacl
+			)
 		})
 	},
 }
 
+// addPGTypeDomainRow adds the row of pg_catalog.pg_type that describes the
+// given domain. Domains have the storage and the I/O functions of their base
+// type.
+func addPGTypeDomainRow(
+	h oidHasher, nspOid tree.Datum, typDesc *sqlbase.TypeDescriptor, addRow func(...tree.Datum) error,
+) error {
+	base := &typDesc.Domain.BaseType
+	builtinPrefix := builtins.PGIOBuiltinPrefix(base)
+	typElem := oidZero
+	if base.Family() == types.ArrayFamily {
+		builtinPrefix = "array_"
+		typElem = tree.NewDOid(tree.DInt(base.ArrayContents().Oid()))
+	}
+	typDefault := tree.DNull
+	if typDesc.Domain.DefaultExpr != nil {
+		typDefault = tree.NewDString(*typDesc.Domain.DefaultExpr)
+	}
+	typOid := tree.NewDOid(tree.DInt(types.TypeIDToOID(uint32(typDesc.ID))))
+	typNotNull := tree.MakeDBool(tree.DBool(typDesc.Domain.NotNull))
+	typBaseType := tree.NewDOid(tree.DInt(base.Oid()))
+	return addRow(
+		typOid,                      // oid
+		tree.NewDName(typDesc.Name), // typname
+		nspOid,                      // typnamespace
+		tree.DNull,                  // typowner
+		typLen(base),                // typlen
+		typByVal(base),              // typbyval
+		typTypeDomain,               // typtype
+		typCategory(base),           // typcategory
+		tree.DBoolFalse,             // typispreferred
+		tree.DBoolTrue,              // typisdefined
+		typDelim,                    // typdelim
+		oidZero,                     // typrelid
+		typElem,                     // typelem
+		oidZero,                     // typarray
+
+		// regproc references
+		h.RegProc(builtinPrefix+"in"),   // typinput
+		h.RegProc(builtinPrefix+"out"),  // typoutput
+		h.RegProc(builtinPrefix+"recv"), // typreceive
+		h.RegProc(builtinPrefix+"send"), // typsend
+		oidZero,                         // typmodin
+		oidZero,                         // typmodout
+		oidZero,                         // typanalyze
+
+		tree.DNull,       // typalign
+		tree.DNull,       // typstorage
+		typNotNull,       // typnotnull
+		typBaseType,      // typbasetype
+		negOneVal,        // typtypmod
+		zeroVal,          // typndims
+		typColl(base, h), // typcollation
+		tree.DNull,       // typdefaultbin
+		typDefault,       // typdefault
+		tree.DNull,       // typacl
+	)
+}
+
 var pgCatalogUserTable = virtualSchemaTable{
 	comment: `database users
 https://www.postgresql.org/docs/9.5/view-pg-user.html`,
@@ -2363,10 +2579,13 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.BytesFamily:       typCategoryUserDefined,
 	types.DateFamily:        typCategoryDateTime,
 	types.TimeFamily:        typCategoryDateTime,
+	types.TimeTZFamily:      typCategoryDateTime,
 	types.FloatFamily:       typCategoryNumeric,
 	types.IntFamily:         typCategoryNumeric,
 	types.IntervalFamily:    typCategoryTimespan,
 	types.JsonFamily:        typCategoryUserDefined,
+	types.GeometryFamily:    typCategoryUserDefined,
+	types.GeographyFamily:   typCategoryUserDefined,
 	types.DecimalFamily:     typCategoryNumeric,
 	types.StringFamily:      typCategoryString,
 	types.TimestampFamily:   typCategoryDateTime,
@@ -2377,6 +2596,7 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
 	types.UnknownFamily:     typCategoryUnknown,
+	types.EnumFamily:        typCategoryEnum,
 }
 
 func typCategory(typ *types.T) tree.Datum {
@@ -2402,7 +2622,8 @@ CREATE TABLE pg_catalog.pg_views (
 		// because it does not distinguish views in separate databases.
 		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /*virtual schemas do not have views*/
 			func(db *sqlbase.DatabaseDescriptor, scName string, desc *sqlbase.TableDescriptor) error {
-				if !desc.IsView() {
+				// Like postgres, pg_views does not list materialized views.
+				if !desc.IsView() || desc.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -2495,6 +2716,8 @@ const (
 	userTypeTag
 	collationTypeTag
 	operatorTypeTag
+	enumEntryTypeTag
+	userDefinedFunctionTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2624,6 +2847,14 @@ func (h oidHasher) BuiltinOid(name string, builtin *tree.Overload) *tree.DOid {
 	return h.getOid()
 }
 
+// UserDefinedFunctionOid returns the OID of an overload of a user-defined
+// function.
+func (h oidHasher) UserDefinedFunctionOid(fn *sqlbase.FunctionDescriptor) *tree.DOid {
+	h.writeTypeTag(userDefinedFunctionTypeTag)
+	h.writeUInt32(uint32(fn.ID))
+	return h.getOid()
+}
+
 func (h oidHasher) RegProc(name string) tree.Datum {
 	_, overloads := builtins.GetBuiltinProperties(name)
 	if len(overloads) == 0 {
@@ -2653,6 +2884,13 @@ func (h oidHasher) OperatorOid(name string, leftType, rightType, returnType *tre
 	return h.getOid()
 }
 
+func (h oidHasher) EnumEntryOid(typOid *tree.DOid, label string) *tree.DOid {
+	h.writeTypeTag(enumEntryTypeTag)
+	h.writeOID(typOid)
+	h.writeStr(label)
+	return h.getOid()
+}
+
 func defaultOid(id sqlbase.ID) *tree.DOid {
 	return tree.NewDOid(tree.DInt(id))
 }
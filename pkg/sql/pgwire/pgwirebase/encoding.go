@@ -23,6 +23,7 @@ import (
 	"unicode/utf8"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -32,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/jackc/pgx/pgtype"
@@ -188,25 +190,68 @@ func NewProtocolViolationErrorf(format string, args ...interface{}) error {
 	return pgerror.Newf(pgerror.CodeProtocolViolationError, format, args...)
 }
 
-// validateArrayDimensions takes the number of dimensions and elements and
-// returns an error if we don't support that combination.
-func validateArrayDimensions(nDimensions int, nElements int) error {
-	switch nDimensions {
-	case 1:
-		break
-	case 0:
-		// 0-dimensional array means 0-length array: validate that.
-		if nElements == 0 {
-			break
+// validateArrayDimensions takes the length of each dimension of an array and
+// its number of elements, and returns an error if they don't match.
+func validateArrayDimensions(dims []int32, nElements int) error {
+	// 0-dimensional array means 0-length array.
+	n := 0
+	if len(dims) > 0 {
+		n = 1
+	}
+	for _, dim := range dims {
+		if dim < 0 {
+			return NewProtocolViolationErrorf("invalid array dimension: %d", dim)
 		}
-		fallthrough
-	default:
-		return pgerror.UnimplementedWithIssuef(32552,
-			"%d-dimension arrays not supported; only 1-dimension", nDimensions)
+		n *= int(dim)
+	}
+	if n != nElements {
+		return NewProtocolViolationErrorf(
+			"array dimensions do not match the number of elements: %d", nElements)
 	}
 	return nil
 }
 
+// makeArray builds an array with the given element type from the elements of
+// its innermost dimension, in row-major order. Arrays with more than one
+// dimension are built as nested arrays.
+func makeArray(elemTyp *types.T, dims []int32, elems tree.Datums) (*tree.DArray, error) {
+	if len(dims) <= 1 {
+		arr := tree.NewDArray(elemTyp)
+		for _, elem := range elems {
+			if err := arr.Append(elem); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	innerTyp := elemTyp
+	for range dims[1:] {
+		innerTyp = types.MakeArray(innerTyp)
+	}
+	arr := tree.NewDArray(innerTyp)
+	for i := 0; i < int(dims[0]); i++ {
+		stride := len(elems) / int(dims[0])
+		inner, err := makeArray(elemTyp, dims[1:], elems[i*stride:(i+1)*stride])
+		if err != nil {
+			return nil, err
+		}
+		if err := arr.Append(inner); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+// pgtypeArrayDimensions returns the length of each dimension of an array
+// decoded by pgtype.
+func pgtypeArrayDimensions(dims []pgtype.ArrayDimension) []int32 {
+	res := make([]int32, len(dims))
+	for i := range dims {
+		res[i] = dims[i].Length
+	}
+	return res
+}
+
 // DecodeOidDatum decodes bytes with specified Oid and format code into
 // a datum. If the ParseTimeContext is nil, reasonable defaults
 // will be applied.
@@ -282,6 +327,12 @@ func DecodeOidDatum(
 				return nil, pgerror.Newf(pgerror.CodeSyntaxError, "could not parse string %q as time", b)
 			}
 			return d, nil
+		case oid.T_timetz:
+			d, err := tree.ParseDTimeTZ(ctx, string(b))
+			if err != nil {
+				return nil, pgerror.Newf(pgerror.CodeSyntaxError, "could not parse string %q as timetz", b)
+			}
+			return d, nil
 
 		case oid.T_interval:
 			d, err := tree.ParseDInterval(string(b))
@@ -311,22 +362,19 @@ func DecodeOidDatum(
 			if arr.Status != pgtype.Present {
 				return tree.DNull, nil
 			}
-			if err := validateArrayDimensions(len(arr.Dimensions), len(arr.Elements)); err != nil {
+			dims := pgtypeArrayDimensions(arr.Dimensions)
+			if err := validateArrayDimensions(dims, len(arr.Elements)); err != nil {
 				return nil, err
 			}
-			out := tree.NewDArray(types.Int)
-			var d tree.Datum
-			for _, v := range arr.Elements {
+			elems := make(tree.Datums, len(arr.Elements))
+			for i, v := range arr.Elements {
 				if v.Status != pgtype.Present {
-					d = tree.DNull
+					elems[i] = tree.DNull
 				} else {
-					d = tree.NewDInt(tree.DInt(v.Int))
-				}
-				if err := out.Append(d); err != nil {
-					return nil, err
+					elems[i] = tree.NewDInt(tree.DInt(v.Int))
 				}
 			}
-			return out, nil
+			return makeArray(types.Int, dims, elems)
 		case oid.T__text, oid.T__name:
 			var arr pgtype.TextArray
 			if err := arr.DecodeText(nil, b); err != nil {
@@ -336,33 +384,41 @@ func DecodeOidDatum(
 			if arr.Status != pgtype.Present {
 				return tree.DNull, nil
 			}
-			if err := validateArrayDimensions(len(arr.Dimensions), len(arr.Elements)); err != nil {
+			dims := pgtypeArrayDimensions(arr.Dimensions)
+			if err := validateArrayDimensions(dims, len(arr.Elements)); err != nil {
 				return nil, err
 			}
-			out := tree.NewDArray(types.String)
+			elemTyp := types.String
 			if id == oid.T__name {
-				out.ParamTyp = types.Name
+				elemTyp = types.Name
 			}
-			var d tree.Datum
-			for _, v := range arr.Elements {
+			elems := make(tree.Datums, len(arr.Elements))
+			for i, v := range arr.Elements {
 				if v.Status != pgtype.Present {
-					d = tree.DNull
+					elems[i] = tree.DNull
 				} else {
-					d = tree.NewDString(v.String)
+					elems[i] = tree.NewDString(v.String)
 					if id == oid.T__name {
-						d = tree.NewDNameFromDString(d.(*tree.DString))
+						elems[i] = tree.NewDNameFromDString(elems[i].(*tree.DString))
 					}
 				}
-				if err := out.Append(d); err != nil {
-					return nil, err
-				}
 			}
-			return out, nil
+			return makeArray(elemTyp, dims, elems)
 		case oid.T_jsonb:
 			if err := validateStringBytes(b); err != nil {
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case types.GeometryOid:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDGeometry(string(b))
+		case types.GeographyOid:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDGeography(string(b))
 		}
 		if _, ok := types.ArrayOids[id]; ok {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -525,6 +581,13 @@ func DecodeOidDatum(
 			}
 			i := int64(binary.BigEndian.Uint64(b))
 			return tree.MakeDTime(timeofday.TimeOfDay(i)), nil
+		case oid.T_timetz:
+			if len(b) < 12 {
+				return nil, pgerror.Newf(pgerror.CodeSyntaxError, "timetz requires 12 bytes for binary format")
+			}
+			timeOfDayMicros := int64(binary.BigEndian.Uint64(b))
+			offsetSecs := int32(binary.BigEndian.Uint32(b[8:]))
+			return tree.MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.TimeOfDay(timeOfDayMicros), offsetSecs)), nil
 		case oid.T_interval:
 			if len(b) < 16 {
 				return nil, pgerror.Newf(pgerror.CodeSyntaxError, "interval requires 16 bytes for binary format")
@@ -554,6 +617,18 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case types.GeometryOid:
+			g, err := geo.ParseGeometryFromEWKB(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDGeometry(g), nil
+		case types.GeographyOid:
+			g, err := geo.ParseGeographyFromEWKB(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDGeography(g), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, pgerror.Newf(pgerror.CodeSyntaxError, "missing varbit bitlen prefix")
@@ -698,38 +773,57 @@ func pgBinaryToIPAddr(b []byte) (ipaddr.IPAddr, error) {
 	}, nil
 }
 
+// maxArrayDimensions is the maximum number of dimensions of an array sent in
+// binary format, as in Postgres.
+const maxArrayDimensions = 6
+
 func decodeBinaryArray(ctx tree.ParseTimeContext, b []byte, code FormatCode) (tree.Datum, error) {
 	hdr := struct {
 		Ndims int32
 		// Nullflag
 		_       int32
 		ElemOid int32
-		// The next two fields should be arrays of size Ndims. However, since
-		// we only support 1-dimensional arrays for now, for convenience we can
-		// leave them in this struct as such for `binary.Read` to parse for us.
-		DimSize int32
-		// Dim lower bound
-		_ int32
 	}{}
 	r := bytes.NewBuffer(b)
 	if err := binary.Read(r, binary.BigEndian, &hdr); err != nil {
 		return nil, err
 	}
-	if err := validateArrayDimensions(int(hdr.Ndims), int(hdr.DimSize)); err != nil {
-		return nil, err
+	if hdr.Ndims < 0 || hdr.Ndims > maxArrayDimensions {
+		return nil, NewProtocolViolationErrorf("invalid number of array dimensions: %d", hdr.Ndims)
+	}
+	// Each dimension is described by its length and its lower bound. A
+	// 0-dimensional array is an empty array.
+	dims := make([]int32, hdr.Ndims)
+	nElems := 0
+	for i := range dims {
+		dim := struct {
+			Size int32
+			// Dim lower bound
+			_ int32
+		}{}
+		if err := binary.Read(r, binary.BigEndian, &dim); err != nil {
+			return nil, err
+		}
+		if dim.Size < 0 {
+			return nil, NewProtocolViolationErrorf("invalid array dimension: %d", dim.Size)
+		}
+		dims[i] = dim.Size
+		if i == 0 {
+			nElems = int(dim.Size)
+		} else {
+			nElems *= int(dim.Size)
+		}
 	}
 
 	elemOid := oid.Oid(hdr.ElemOid)
-	arr := tree.NewDArray(types.OidToType[elemOid])
+	var elems tree.Datums
 	var vlen int32
-	for i := int32(0); i < hdr.DimSize; i++ {
+	for i := 0; i < nElems; i++ {
 		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
 			return nil, err
 		}
 		if vlen < 0 {
-			if err := arr.Append(tree.DNull); err != nil {
-				return nil, err
-			}
+			elems = append(elems, tree.DNull)
 			continue
 		}
 		buf := r.Next(int(vlen))
@@ -737,11 +831,9 @@ func decodeBinaryArray(ctx tree.ParseTimeContext, b []byte, code FormatCode) (tr
 		if err != nil {
 			return nil, err
 		}
-		if err := arr.Append(elem); err != nil {
-			return nil, err
-		}
+		elems = append(elems, elem)
 	}
-	return arr, nil
+	return makeArray(types.OidToType[elemOid], dims, elems)
 }
 
 var invalidUTF8Error = pgerror.Newf(pgerror.CodeCharacterNotInRepertoireError, "invalid UTF-8 sequence")
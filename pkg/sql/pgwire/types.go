@@ -148,6 +148,9 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DDate:
 		s := v.Date.String()
 		b.putInt32(int32(len(s)))
@@ -159,6 +162,9 @@ func (b *writeBuffer) writeTextDatum(
 		b.putInt32(int32(len(s)))
 		b.write(s)
 
+	case *tree.DTimeTZ:
+		b.writeLengthPrefixedString(v.TimeTZ.String())
+
 	case *tree.DTimestamp:
 		// Start at offset 4 because `putInt32` clobbers the first 4 bytes.
 		s := formatTs(v.Time, nil, b.putbuf[4:4])
@@ -177,6 +183,11 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DGeometry, *tree.DGeography:
+		// Spatial values are sent as hex-encoded EWKB, like PostGIS.
+		b.textFormatter.FormatNode(v)
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -186,8 +197,6 @@ func (b *writeBuffer) writeTextDatum(
 		case oid.T_int2vector, oid.T_oidvector:
 			// vectors are serialized as a string of space-separated values.
 			sep := ""
-			// TODO(justin): add a test for nested arrays when #32552 is
-			// addressed.
 			for _, d := range v.Array {
 				b.textFormatter.WriteString(sep)
 				b.textFormatter.FormatNode(d)
@@ -420,6 +429,9 @@ func (b *writeBuffer) writeBinaryDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DTimestamp:
 		b.putInt32(8)
 		b.putInt64(timeToPgBinary(v.Time, nil))
@@ -436,6 +448,11 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.putInt32(8)
 		b.putInt64(int64(*v))
 
+	case *tree.DTimeTZ:
+		b.putInt32(12)
+		b.putInt64(int64(v.TimeOfDay))
+		b.putInt32(v.OffsetSecs)
+
 	case *tree.DInterval:
 		b.putInt32(16)
 		b.putInt64(v.Nanos() / int64(time.Microsecond/time.Nanosecond))
@@ -455,26 +472,46 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
 
 	case *tree.DArray:
-		if v.ParamTyp.Family() == types.ArrayFamily {
-			b.setError(pgerror.UnimplementedWithIssueDetail(32552,
-				"binenc", "unsupported binary serialization of multidimensional arrays"))
-			return
-		}
 		// TODO(andrei): We shouldn't be allocating a new buffer for every array.
 		subWriter := newWriteBuffer(nil /* bytecount */)
-		// Put the number of dimensions. We currently support 1d arrays only.
-		subWriter.putInt32(1)
-		hasNulls := 0
-		if v.HasNulls {
-			hasNulls = 1
+		// Multidimensional arrays are serialized as the elements of their
+		// innermost dimension, preceded by the length of each dimension.
+		dims := []int{v.Len()}
+		elems := v.Array
+		elemTyp := v.ParamTyp
+		hasNulls := v.HasNulls
+		if elemTyp.Family() == types.ArrayFamily {
+			dims = v.Dimensions()
+			elems = appendArrayElements(nil /* elems */, v)
+			for elemTyp.Family() == types.ArrayFamily {
+				elemTyp = elemTyp.ArrayContents()
+			}
+			hasNulls = false
+			for _, elem := range elems {
+				if elem == tree.DNull {
+					hasNulls = true
+				}
+			}
+			// Nested empty arrays have no elements, and so no dimensions.
+			if len(elems) == 0 {
+				dims = nil
+			}
 		}
-		oid := v.ParamTyp.Oid()
-		subWriter.putInt32(int32(hasNulls))
+		// Put the number of dimensions.
+		subWriter.putInt32(int32(len(dims)))
+		nullFlag := 0
+		if hasNulls {
+			nullFlag = 1
+		}
+		oid := elemTyp.Oid()
+		subWriter.putInt32(int32(nullFlag))
 		subWriter.putInt32(int32(oid))
-		subWriter.putInt32(int32(v.Len()))
-		// Lower bound, we only support a lower bound of 1.
-		subWriter.putInt32(1)
-		for _, elem := range v.Array {
+		for _, dim := range dims {
+			subWriter.putInt32(int32(dim))
+			// Lower bound, we only support a lower bound of 1.
+			subWriter.putInt32(1)
+		}
+		for _, elem := range elems {
 			subWriter.writeBinaryDatum(ctx, elem, sessionLoc, oid)
 		}
 		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
@@ -484,6 +521,14 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DGeometry:
+		ewkb := v.EWKB()
+		b.putInt32(int32(len(ewkb)))
+		b.write(ewkb)
+	case *tree.DGeography:
+		ewkb := v.EWKB()
+		b.putInt32(int32(len(ewkb)))
+		b.write(ewkb)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
@@ -499,6 +544,19 @@ const (
 	pgTimeStampFormat         = pgTimeStampFormatNoOffset + "-07:00"
 )
 
+// appendArrayElements appends the elements of the innermost dimension of the
+// given array to elems, in row-major order.
+func appendArrayElements(elems tree.Datums, arr *tree.DArray) tree.Datums {
+	for _, elem := range arr.Array {
+		if inner, ok := tree.AsDArray(elem); ok {
+			elems = appendArrayElements(elems, inner)
+		} else {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
 // formatTime formats t into a format lib/pq understands, appending to the
 // provided tmp buffer and reallocating if needed. The function will then return
 // the resulting buffer.
@@ -76,9 +76,15 @@ func (a UncachedPhysicalAccessor) GetDatabaseDesc(
 }
 
 // IsValidSchema implements the SchemaAccessor interface.
-func (a UncachedPhysicalAccessor) IsValidSchema(dbDesc *DatabaseDescriptor, scName string) bool {
-	// At this point, only the public schema is recognized.
-	return scName == tree.PublicSchema
+func (a UncachedPhysicalAccessor) IsValidSchema(
+	ctx context.Context, txn *client.Txn, dbDesc *DatabaseDescriptor, scName string,
+) (bool, error) {
+	if scName == tree.PublicSchema || isTemporarySchemaName(scName) {
+		schemaID, err := resolveSchemaID(ctx, txn, dbDesc.ID, scName)
+		return schemaID != sqlbase.InvalidID, err
+	}
+	scDesc, err := getSchemaDescByName(ctx, txn, dbDesc.ID, scName)
+	return scDesc != nil, err
 }
 
 // GetObjectNames implements the SchemaAccessor interface.
@@ -89,7 +95,11 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 	scName string,
 	flags DatabaseListFlags,
 ) (TableNames, error) {
-	if ok := a.IsValidSchema(dbDesc, scName); !ok {
+	schemaID, err := resolveSchemaID(ctx, txn, dbDesc.ID, scName)
+	if err != nil {
+		return nil, err
+	}
+	if schemaID == sqlbase.InvalidID {
 		if flags.required {
 			tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), "")
 			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(&tn.TableNamePrefix))
@@ -98,20 +108,34 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 	}
 
 	log.Eventf(ctx, "fetching list of objects for %q", dbDesc.Name)
-	prefix := sqlbase.MakeNameMetadataKey(dbDesc.ID, "")
+	prefix := sqlbase.MakeNameMetadataKey(schemaID, "")
 	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
 	if err != nil {
 		return nil, err
 	}
 
+	// Types and temporary schemas share the namespace with tables, but are
+	// not relations.
+	ids := make([]sqlbase.ID, len(sr))
+	for i, row := range sr {
+		ids[i] = sqlbase.ID(row.ValueInt())
+	}
+	descs, err := getDescriptorsByID(ctx, txn, ids)
+	if err != nil {
+		return nil, err
+	}
+
 	var tableNames tree.TableNames
-	for _, row := range sr {
+	for i, row := range sr {
+		if desc, ok := descs[ids[i]]; !ok || desc.GetTable() == nil {
+			continue
+		}
 		_, tableName, err := encoding.DecodeUnsafeStringAscending(
 			bytes.TrimPrefix(row.Key, prefix), nil)
 		if err != nil {
 			return nil, err
 		}
-		tn := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(tableName))
+		tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), tree.Name(tableName))
 		tn.ExplicitCatalog = flags.explicitPrefix
 		tn.ExplicitSchema = flags.explicitPrefix
 		tableNames = append(tableNames, tn)
@@ -123,14 +147,6 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 func (a UncachedPhysicalAccessor) GetObjectDesc(
 	ctx context.Context, txn *client.Txn, name *ObjectName, flags ObjectLookupFlags,
 ) (ObjectDescriptor, error) {
-	// At this point, only the public schema is recognized.
-	if name.Schema() != tree.PublicSchema {
-		if flags.required {
-			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(name))
-		}
-		return nil, nil
-	}
-
 	// Look up the database ID.
 	dbID, err := getDatabaseID(ctx, txn, name.Catalog(), flags.required)
 	if err != nil || dbID == sqlbase.InvalidID {
@@ -138,13 +154,25 @@ func (a UncachedPhysicalAccessor) GetObjectDesc(
 		return nil, err
 	}
 
+	// Look up the schema ID.
+	parentID, err := resolveSchemaID(ctx, txn, dbID, name.Schema())
+	if err != nil {
+		return nil, err
+	}
+	if parentID == sqlbase.InvalidID {
+		if flags.required {
+			return nil, sqlbase.NewUndefinedRelationError(name)
+		}
+		return nil, nil
+	}
+
 	// Try to use the system name resolution bypass. This avoids a hotspot.
 	// Note: we can only bypass name to ID resolution. The desc
 	// lookup below must still go through KV because system descriptors
 	// can be modified on a running cluster.
-	descID := sqlbase.LookupSystemTableDescriptorID(dbID, name.Table())
+	descID := sqlbase.LookupSystemTableDescriptorID(parentID, name.Table())
 	if descID == sqlbase.InvalidID {
-		descID, err = getDescriptorID(ctx, txn, sqlbase.NewTableKey(dbID, name.Table()))
+		descID, err = getDescriptorID(ctx, txn, sqlbase.NewTableKey(parentID, name.Table()))
 		if err != nil {
 			return nil, err
 		}
@@ -160,6 +188,14 @@ func (a UncachedPhysicalAccessor) GetObjectDesc(
 	// Look up the table using the discovered database descriptor.
 	desc := &sqlbase.TableDescriptor{}
 	err = getDescriptorByID(ctx, txn, descID, desc)
+	if err == errDescriptorIsType || err == errDescriptorIsSchema {
+		// The name refers to a user-defined type or a temporary schema, not a
+		// relation.
+		if flags.required {
+			return nil, sqlbase.NewUndefinedRelationError(name)
+		}
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
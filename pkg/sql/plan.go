@@ -168,14 +168,20 @@ type planNodeFastPath interface {
 var _ planNode = &alterIndexNode{}
 var _ planNode = &alterSequenceNode{}
 var _ planNode = &alterTableNode{}
+var _ planNode = &alterTypeNode{}
 var _ planNode = &bufferNode{}
 var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &createDatabaseNode{}
+var _ planNode = &createFunctionNode{}
+var _ planNode = &createTriggerNode{}
 var _ planNode = &createIndexNode{}
+var _ planNode = &createSchemaNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
+var _ planNode = &createDomainNode{}
+var _ planNode = &createTypeNode{}
 var _ planNode = &CreateUserNode{}
 var _ planNode = &createViewNode{}
 var _ planNode = &delayedNode{}
@@ -183,9 +189,13 @@ var _ planNode = &deleteNode{}
 var _ planNode = &deleteRangeNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
+var _ planNode = &dropFunctionNode{}
+var _ planNode = &dropTriggerNode{}
 var _ planNode = &dropIndexNode{}
+var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
+var _ planNode = &dropTypeNode{}
 var _ planNode = &DropUserNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &errorIfRowsNode{}
@@ -201,6 +211,8 @@ var _ planNode = &limitNode{}
 var _ planNode = &max1RowNode{}
 var _ planNode = &ordinalityNode{}
 var _ planNode = &projectSetNode{}
+var _ planNode = &recursiveCTENode{}
+var _ planNode = &refreshMatViewNode{}
 var _ planNode = &relocateNode{}
 var _ planNode = &renameColumnNode{}
 var _ planNode = &renameDatabaseNode{}
@@ -610,6 +622,8 @@ func (p *planner) newPlan(
 		return p.AlterTable(ctx, n)
 	case *tree.AlterSequence:
 		return p.AlterSequence(ctx, n)
+	case *tree.AlterType:
+		return p.AlterType(ctx, n)
 	case *tree.AlterUserSetPassword:
 		return p.AlterUserSetPassword(ctx, n)
 	case *tree.CancelQueries:
@@ -628,10 +642,20 @@ func (p *planner) newPlan(
 		return p.Scrub(ctx, n)
 	case *tree.CreateDatabase:
 		return p.CreateDatabase(ctx, n)
+	case *tree.CreateFunction:
+		return p.CreateFunction(ctx, n)
+	case *tree.CreateTrigger:
+		return p.CreateTrigger(ctx, n)
 	case *tree.CreateIndex:
 		return p.CreateIndex(ctx, n)
+	case *tree.CreateSchema:
+		return p.CreateSchema(ctx, n)
 	case *tree.CreateTable:
 		return p.CreateTable(ctx, n)
+	case *tree.CreateDomain:
+		return p.CreateDomain(ctx, n)
+	case *tree.CreateType:
+		return p.CreateType(ctx, n)
 	case *tree.CreateUser:
 		return p.CreateUser(ctx, n)
 	case *tree.CreateView:
@@ -648,10 +672,20 @@ func (p *planner) newPlan(
 		return p.Discard(ctx, n)
 	case *tree.DropDatabase:
 		return p.DropDatabase(ctx, n)
+	case *tree.DropFunction:
+		return p.DropFunction(ctx, n)
+	case *tree.DropTrigger:
+		return p.DropTrigger(ctx, n)
 	case *tree.DropIndex:
 		return p.DropIndex(ctx, n)
+	case *tree.DropSchema:
+		return p.DropSchema(ctx, n)
 	case *tree.DropTable:
 		return p.DropTable(ctx, n)
+	case *tree.DropDomain:
+		return p.DropDomain(ctx, n)
+	case *tree.DropType:
+		return p.DropType(ctx, n)
 	case *tree.DropView:
 		return p.DropView(ctx, n)
 	case *tree.DropSequence:
@@ -668,6 +702,8 @@ func (p *planner) newPlan(
 		return p.newPlan(ctx, n.Select, desiredTypes)
 	case *tree.Relocate:
 		return p.Relocate(ctx, n)
+	case *tree.RefreshMaterializedView:
+		return p.RefreshMaterializedView(ctx, n)
 	case *tree.RenameColumn:
 		return p.RenameColumn(ctx, n)
 	case *tree.RenameDatabase:
@@ -687,6 +723,8 @@ func (p *planner) newPlan(
 			desiredTypes, publicColumns)
 	case *tree.SetClusterSetting:
 		return p.SetClusterSetting(ctx, n)
+	case *tree.SetConstraints:
+		return p.SetConstraints(ctx, n)
 	case *tree.SetZoneConfig:
 		return p.SetZoneConfig(ctx, n)
 	case *tree.SetVar:
@@ -779,6 +817,10 @@ func (p *planner) doPrepare(ctx context.Context, stmt tree.Statement) (planNode,
 		return p.CreateUser(ctx, n)
 	case *tree.CreateTable:
 		return p.CreateTable(ctx, n)
+	case *tree.CreateDomain:
+		return p.CreateDomain(ctx, n)
+	case *tree.CreateType:
+		return p.CreateType(ctx, n)
 	case *tree.Delete:
 		return p.Delete(ctx, n, nil)
 	case *tree.DropUser:
@@ -93,12 +93,14 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *applyJoinNode:
 	case *bufferNode:
 	case *scanBufferNode:
+	case *recursiveCTENode:
 
 	// Every other node simply has no guarantees on its output rows.
 	case *CreateUserNode:
 	case *DropUserNode:
 	case *alterIndexNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterTableNode:
 	case *alterUserSetPasswordNode:
 	case *cancelQueriesNode:
@@ -110,6 +112,11 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *createSequenceNode:
+	case *createFunctionNode:
+	case *createTriggerNode:
+	case *createSchemaNode:
+	case *createDomainNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *createTableNode:
 	case *createViewNode:
@@ -118,12 +125,17 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
+	case *dropTriggerNode:
+	case *dropSchemaNode:
+	case *dropTypeNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *errorIfRowsNode:
 	case *explainDistSQLNode:
 	case *hookFnNode:
 	case *relocateNode:
+	case *refreshMatViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
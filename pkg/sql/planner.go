@@ -42,6 +42,9 @@ type extendedEvalContext struct {
 
 	SessionMutator *sessionDataMutator
 
+	// SessionID is the ID of the session the context belongs to.
+	SessionID ClusterWideID
+
 	// VirtualSchemas can be used to access virtual tables.
 	VirtualSchemas VirtualTabler
 
@@ -67,6 +70,10 @@ type extendedEvalContext struct {
 
 	SchemaChangers *schemaChangerCollection
 
+	// Constraints is the same object as EvalContext.Constraints, for the
+	// statements which manage the queued checks.
+	Constraints *deferredConstraints
+
 	schemaAccessors *schemaInterface
 }
 
@@ -249,6 +256,8 @@ func newInternalPlanner(
 	p.semaCtx = tree.MakeSemaContext()
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		fmt.Sprintf("internal-planner.%s.%s", user, opName),
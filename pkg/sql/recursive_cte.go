@@ -0,0 +1,96 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// recursiveCTENode implements the logic for a recursive CTE:
+//  1. Evaluate the initial query; emit the results and also save them in
+//     a "working" table.
+//  2. So long as the working table is not empty:
+//     - evaluate the recursive query, substituting the current contents of
+//       the working table for the recursive self-reference;
+//     - emit all resulting rows, and save them as the next iteration's
+//       working table.
+// The recursive query tree is regenerated each time using a callback
+// (implemented by the execbuilder).
+//
+// If deduplicate is set, rows that have already been emitted are discarded
+// (this implements the UNION semantics, as opposed to UNION ALL).
+//
+// The node is executed by the recursive CTE processor of DistSQL (see
+// DistSQLPlanner.createPlanForRecursiveCTE), whose working table spills to
+// disk.
+type recursiveCTENode struct {
+	initial planNode
+
+	genIterationFn exec.RecursiveCTEIterationFn
+
+	// label is a string used to describe the node in an EXPLAIN output.
+	label string
+
+	deduplicate bool
+}
+
+func (n *recursiveCTENode) startExec(params runParams) error {
+	panic("recursiveCTENode can't be run in local mode")
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	panic("recursiveCTENode can't be run in local mode")
+}
+
+func (n *recursiveCTENode) Values() tree.Datums {
+	panic("recursiveCTENode can't be run in local mode")
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	n.initial.Close(ctx)
+}
+
+// iterationFn returns the function the recursive CTE processor uses to run
+// an iteration: it plans the recursive query against the working table and
+// runs it.
+func (n *recursiveCTENode) iterationFn(params runParams) distsqlrun.RecursiveCTEIterationFn {
+	return func(
+		ctx context.Context,
+		workingRows *rowcontainer.DiskBackedRowContainer,
+		emit func(ctx context.Context, row tree.Datums) error,
+	) error {
+		params.ctx = ctx
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		// Set up a bufferNode that can be used as a reference for a
+		// scanBufferNode.
+		buf := &bufferNode{
+			// The plan here is only useful for planColumns, so it's ok to always use
+			// the initial plan.
+			plan:        n.initial,
+			workingRows: workingRows,
+			label:       n.label,
+		}
+		newPlan, err := n.genIterationFn(buf)
+		if err != nil {
+			return err
+		}
+		return runPlanInsidePlan(params, newPlan.(*planTop), newCallbackResultWriter(emit))
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+type refreshMatViewNode struct {
+	n    *tree.RefreshMaterializedView
+	tn   *tree.TableName
+	desc *sqlbase.ImmutableTableDescriptor
+}
+
+// RefreshMaterializedView recomputes the contents of a materialized view.
+// Privileges: CREATE on view.
+//
+//	notes: postgres requires owner of the view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	tn := n.Name.ToTableName()
+	desc, err := p.ResolveUncachedTableDescriptor(ctx, &tn, true /* required */, ResolveRequireViewDesc)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.MaterializedView() {
+		return nil, pgerror.Newf(pgerror.CodeWrongObjectTypeError,
+			"%q is not a materialized view", tn.Table())
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return &refreshMatViewNode{n: n, tn: &tn, desc: desc}, nil
+}
+
+func (n *refreshMatViewNode) startExec(params runParams) error {
+	var asOf hlc.Timestamp
+	if n.n.AsOf.Expr != nil {
+		var err error
+		if asOf, err = params.p.EvalAsOfTimestamp(n.n.AsOf); err != nil {
+			return err
+		}
+	}
+	if err := params.p.refreshMaterializedView(
+		params.ctx, n.desc.TableDesc(), asOf, false, /* isNew */
+	); err != nil {
+		return err
+	}
+
+	// Record this refresh in the event log. This is an auditable log event and
+	// is recorded in the same transaction as the new contents of the view.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogRefreshMaterializedView,
+		int32(n.desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			ViewName  string
+			Statement string
+			User      string
+		}{n.tn.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*refreshMatViewNode) Next(runParams) (bool, error) { return false, nil }
+func (*refreshMatViewNode) Values() tree.Datums          { return tree.Datums{} }
+func (*refreshMatViewNode) Close(context.Context)        {}
+
+// refreshMaterializedView replaces the contents of a materialized view with
+// the result of its query. If asOf is non-empty, the query is evaluated at
+// that timestamp; otherwise it is evaluated in the planner's transaction. The
+// old rows are deleted and the new ones written in the planner's transaction,
+// so concurrent readers observe either the old or the new contents. isNew
+// indicates that the view was created in the current transaction and has no
+// rows to delete.
+func (p *planner) refreshMaterializedView(
+	ctx context.Context, desc *sqlbase.TableDescriptor, asOf hlc.Timestamp, isNew bool,
+) error {
+	query := desc.ViewQuery
+	queryTxn := p.txn
+	if !asOf.IsEmpty() {
+		query = fmt.Sprintf("SELECT * FROM (%s) AS OF SYSTEM TIME '%s'",
+			desc.ViewQuery, asOf.AsOfSystemTime())
+		queryTxn = nil
+	}
+	rows, err := p.ExecCfg().InternalExecutor.Query(
+		ctx, "refresh-materialized-view", queryTxn, query)
+	if err != nil {
+		return err
+	}
+
+	if !isNew {
+		tablePrefix := roachpb.Key(keys.MakeTablePrefix(uint32(desc.ID)))
+		if err := p.txn.DelRange(ctx, tablePrefix, tablePrefix.PrefixEnd()); err != nil {
+			return err
+		}
+	}
+
+	// Like CREATE TABLE AS, this is a very simplified version of the INSERT
+	// logic: there are no CHECK expressions or FKs to verify.
+	immutDesc := sqlbase.NewImmutableTableDescriptor(*desc)
+	ri, err := row.MakeInserter(
+		p.txn, immutDesc, nil /* fkTables */, immutDesc.Columns, row.SkipFKs, p.EvalContext(), &p.alloc)
+	if err != nil {
+		return err
+	}
+	ti := tableInserterPool.Get().(*tableInserter)
+	*ti = tableInserter{ri: ri}
+	tw := tableWriter(ti)
+	defer func() {
+		tw.close(ctx)
+		*ti = tableInserter{}
+		tableInserterPool.Put(ti)
+	}()
+	if err := tw.init(p.txn, p.EvalContext()); err != nil {
+		return err
+	}
+
+	// The hidden rowid column is the last column of the view's descriptor and
+	// does not appear in the result of the view's query.
+	rowBuffer := make(tree.Datums, len(immutDesc.Columns))
+	pkColIdx := len(immutDesc.Columns) - 1
+	rowIDExpr, err := p.makeRowIDExpr(ctx, &immutDesc.Columns[pkColIdx], "REFRESH MATERIALIZED VIEW")
+	if err != nil {
+		return err
+	}
+	traceKV := p.extendedEvalCtx.Tracing.KVTracingEnabled()
+	for _, r := range rows {
+		if err := p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		if len(r) != pkColIdx {
+			return pgerror.AssertionFailedf(
+				"materialized view %q has %d columns but its query returned %d",
+				desc.Name, pkColIdx, len(r))
+		}
+		copy(rowBuffer, r)
+		if rowBuffer[pkColIdx], err = rowIDExpr.Eval(p.EvalContext()); err != nil {
+			return err
+		}
+		if err := tw.row(ctx, rowBuffer, traceKV); err != nil {
+			return err
+		}
+	}
+	if _, err := tw.finalize(ctx, traceKV); err != nil {
+		return err
+	}
+
+	// The contents of the view have been replaced wholesale, so make sure its
+	// statistics get refreshed.
+	p.ExecCfg().StatsRefresher.NotifyMutation(desc.ID, math.MaxInt32 /* rowsAffected */)
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -31,7 +32,8 @@ type renameTableNode struct {
 }
 
 // RenameTable renames the table, view or sequence.
-// Privileges: DROP on source table/view/sequence, CREATE on destination database.
+// Privileges: DROP on source table/view/sequence, CREATE on destination schema
+// (on destination database for the public and temporary schemas).
 //   Notes: postgres requires the table owner.
 //          mysql requires ALTER, DROP on the original table, and CREATE, INSERT
 //          on the new table (and does not copy privileges over).
@@ -81,19 +83,41 @@ func (n *renameTableNode) startExec(params runParams) error {
 	newTn := n.newTn
 	tableDesc := n.tableDesc
 
-	prevDbDesc, err := p.ResolveUncachedDatabase(ctx, oldTn)
+	prevDbDesc, err := p.resolveUncachedDatabaseForRelation(ctx, oldTn, true /* allowTemporary */)
 	if err != nil {
 		return err
 	}
 
 	// Check if target database exists.
 	// We also look at uncached descriptors here.
-	targetDbDesc, err := p.ResolveUncachedDatabase(ctx, newTn)
+	targetDbDesc, err := p.resolveUncachedDatabaseForRelation(ctx, newTn, true /* allowTemporary */)
 	if err != nil {
 		return err
 	}
 
-	if err := p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE); err != nil {
+	// Temporary tables stay in the session's temporary schema, and permanent
+	// tables cannot be moved into it.
+	if tableDesc.Temporary {
+		if newTn.ExplicitSchema && !isTemporarySchemaRef(newTn.Schema()) {
+			return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+				"cannot move a temporary table out of its temporary schema")
+		}
+		if targetDbDesc.ID != prevDbDesc.ID {
+			return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+				"cannot move a temporary table to another database")
+		}
+		newTn.SchemaName = oldTn.SchemaName
+	} else if isTemporarySchemaRef(newTn.Schema()) {
+		return pgerror.New(pgerror.CodeFeatureNotSupportedError,
+			"cannot move a permanent table into a temporary schema")
+	}
+
+	if tableDesc.Temporary {
+		err = p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE)
+	} else {
+		err = p.checkSchemaCreatePrivilege(ctx, targetDbDesc, newTn.Schema())
+	}
+	if err != nil {
 		return err
 	}
 
@@ -105,11 +129,24 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return nil
 	}
 
+	prevParentID := tableDesc.NamespaceParentID()
 	tableDesc.SetName(newTn.Table())
 	tableDesc.ParentID = targetDbDesc.ID
+	if !tableDesc.Temporary {
+		// The table may move between the public schema and user-defined
+		// schemas.
+		schemaID, _, err := p.schemaForCreate(ctx, targetDbDesc, newTn.Schema())
+		if err != nil {
+			return err
+		}
+		tableDesc.UnexposedParentSchemaID = 0
+		if schemaID != targetDbDesc.ID {
+			tableDesc.UnexposedParentSchemaID = schemaID
+		}
+	}
 
 	descKey := sqlbase.MakeDescMetadataKey(tableDesc.GetID())
-	newTbKey := sqlbase.NewTableKey(targetDbDesc.ID, newTn.Table()).Key()
+	newTbKey := sqlbase.NewTableKey(tableDesc.NamespaceParentID(), newTn.Table()).Key()
 
 	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 		return err
@@ -119,7 +156,7 @@ func (n *renameTableNode) startExec(params runParams) error {
 	descDesc := sqlbase.WrapDescriptor(tableDesc)
 
 	renameDetails := sqlbase.TableDescriptor_NameInfo{
-		ParentID: prevDbDesc.ID,
+		ParentID: prevParentID,
 		Name:     oldTn.Table()}
 	tableDesc.DrainingNames = append(tableDesc.DrainingNames, renameDetails)
 	if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
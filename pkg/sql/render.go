@@ -94,9 +94,13 @@ func (p *planner) Select(
 	limit := n.Limit
 	orderBy := n.OrderBy
 	with := n.With
+	locking := n.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		wrapped = s.Select.Select
+		if s.Select.Locking != nil {
+			locking = s.Select.Locking
+		}
 		if s.Select.With != nil {
 			if with != nil {
 				return nil, pgerror.UnimplementedWithIssue(24303,
@@ -122,6 +126,11 @@ func (p *planner) Select(
 		}
 	}
 
+	if len(locking) > 0 {
+		return nil, pgerror.Unimplementedf("select for update",
+			"%s requires the optimizer", locking[0].Strength)
+	}
+
 	switch s := wrapped.(type) {
 	case *tree.SelectClause:
 		// Select can potentially optimize index selection if it's being ordered,
@@ -189,6 +198,10 @@ func (p *planner) SelectClause(
 	scalarProps := &p.semaCtx.Properties
 	defer scalarProps.Restore(*scalarProps)
 
+	if err := checkNoGroupingSets(parsed, orderBy); err != nil {
+		return nil, err
+	}
+
 	r := &renderNode{}
 
 	resetter, err := p.initWith(ctx, with)
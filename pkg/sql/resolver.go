@@ -15,6 +15,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 // SchemaResolver abstracts the interfaces needed from the logical
@@ -215,6 +217,16 @@ func (p *planner) ResolveUncachedTableDescriptor(
 // resolution.
 func ResolveTargetObject(
 	ctx context.Context, sc SchemaResolver, tn *ObjectName,
+) (res *DatabaseDescriptor, err error) {
+	return resolveTargetObject(ctx, sc, tn, false /* allowUserSchemas */, false /* allowTemporary */)
+}
+
+// resolveTargetObject is the implementation of ResolveTargetObject.
+// allowUserSchemas indicates whether the target object may live in a
+// user-defined schema, and allowTemporary whether it may live in the
+// session's temporary schema.
+func resolveTargetObject(
+	ctx context.Context, sc SchemaResolver, tn *ObjectName, allowUserSchemas, allowTemporary bool,
 ) (res *DatabaseDescriptor, err error) {
 	found, descI, err := tn.ResolveTarget(ctx, sc, sc.CurrentDatabase(), sc.CurrentSearchPath())
 	if err != nil {
@@ -228,7 +240,16 @@ func ResolveTargetObject(
 			"cannot create %q because the target database or schema does not exist",
 			tree.ErrString(tn)).SetHintf("verify that the current database and search_path are valid and/or the target database exists")
 	}
-	if tn.Schema() != tree.PublicSchema {
+	var allowed bool
+	switch scName := tn.Schema(); {
+	case scName == tree.PublicSchema:
+		allowed = true
+	case isTemporarySchemaRef(scName):
+		allowed = allowTemporary
+	default:
+		allowed = allowUserSchemas && !isVirtualSchemaName(scName)
+	}
+	if !allowed {
 		return nil, pgerror.Newf(pgerror.CodeInvalidNameError,
 			"schema cannot be modified: %q", tree.ErrString(&tn.TableNamePrefix))
 	}
@@ -244,6 +265,18 @@ func (p *planner) ResolveUncachedDatabase(
 	return res, err
 }
 
+// resolveUncachedDatabaseForRelation is like ResolveUncachedDatabase, but
+// also accepts targets in user-defined schemas and, if allowTemporary is set,
+// in the session's temporary schema.
+func (p *planner) resolveUncachedDatabaseForRelation(
+	ctx context.Context, tn *ObjectName, allowTemporary bool,
+) (res *UncachedDatabaseDescriptor, err error) {
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		res, err = resolveTargetObject(ctx, p, tn, true /* allowUserSchemas */, allowTemporary)
+	})
+	return res, err
+}
+
 // ResolveRequiredType can be passed to the ResolveExistingObject function to
 // require the returned descriptor to be of a specific type.
 type ResolveRequiredType int
@@ -273,13 +306,34 @@ func (p *planner) LookupSchema(
 	if err != nil || dbDesc == nil {
 		return false, nil, err
 	}
-	return sc.IsValidSchema(dbDesc, scName), dbDesc, nil
+	if scName == sessiondata.PgTempSchemaName {
+		// The pg_temp alias always refers to the session's temporary schema,
+		// which is created on demand.
+		return true, dbDesc, nil
+	}
+	if isTemporarySchemaName(scName) && scName != p.SessionData().SearchPath.GetTemporarySchemaName() {
+		// Other sessions' temporary schemas are not accessible.
+		return false, nil, nil
+	}
+	found, err = sc.IsValidSchema(ctx, p.txn, dbDesc, scName)
+	return found, dbDesc, err
 }
 
 // LookupObject implements the tree.TableNameExistingResolver interface.
 func (p *planner) LookupObject(
 	ctx context.Context, requireMutable bool, dbName, scName, tbName string,
 ) (found bool, objMeta tree.NameResolutionResult, err error) {
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	if scName == sessiondata.PgTempSchemaName {
+		// The pg_temp alias refers to the session's temporary schema, if any.
+		if tempSchemaName == "" {
+			return false, nil, nil
+		}
+		scName = tempSchemaName
+	} else if isTemporarySchemaName(scName) && scName != tempSchemaName {
+		// Other sessions' temporary schemas are not accessible.
+		return false, nil, nil
+	}
 	sc := p.LogicalSchemaAccessor()
 	p.tableName = tree.MakeTableNameWithSchema(tree.Name(dbName), tree.Name(scName), tree.Name(tbName))
 	objDesc, err := sc.GetObjectDesc(ctx, p.txn, &p.tableName, p.ObjectLookupFlags(false /*required*/, requireMutable))
@@ -322,6 +376,32 @@ func getDescriptorsFromTargetList(
 		return descs, nil
 	}
 
+	if targets.Schemas != nil {
+		if len(targets.Schemas) == 0 {
+			return nil, errNoMatch
+		}
+		dbName := p.CurrentDatabase()
+		if dbName == "" {
+			return nil, errNoDatabase
+		}
+		dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /*required*/)
+		if err != nil {
+			return nil, err
+		}
+		descs := make([]sqlbase.DescriptorProto, 0, len(targets.Schemas))
+		for _, schema := range targets.Schemas {
+			descriptor, err := getSchemaDescByName(ctx, p.txn, dbDesc.ID, string(schema))
+			if err != nil {
+				return nil, err
+			}
+			if descriptor == nil {
+				return nil, sqlbase.NewUndefinedSchemaError(string(schema))
+			}
+			descs = append(descs, descriptor)
+		}
+		return descs, nil
+	}
+
 	if len(targets.Tables) == 0 {
 		return nil, errNoTable
 	}
@@ -360,6 +440,15 @@ func (p *planner) getQualifiedTableName(
 		return "", err
 	}
 	tbName := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(desc.Name))
+	if desc.UnexposedParentSchemaID != sqlbase.InvalidID && !desc.Temporary {
+		// The table lives in a user-defined schema.
+		scDesc, err := sqlbase.GetSchemaDescFromID(ctx, p.txn, desc.UnexposedParentSchemaID)
+		if err != nil {
+			return "", err
+		}
+		tbName.SchemaName = tree.Name(scDesc.Name)
+		tbName.ExplicitSchema = true
+	}
 	return tbName.String(), nil
 }
 
@@ -571,6 +660,7 @@ type internalLookupCtx struct {
 	dbDescs map[sqlbase.ID]*DatabaseDescriptor
 	tbDescs map[sqlbase.ID]*TableDescriptor
 	tbIDs   []sqlbase.ID
+	scDescs map[sqlbase.ID]*sqlbase.SchemaDescriptor
 }
 
 // tableLookupFn can be used to retrieve a table descriptor and its corresponding
@@ -583,6 +673,7 @@ func newInternalLookupCtx(
 	dbNames := make(map[sqlbase.ID]string)
 	dbDescs := make(map[sqlbase.ID]*DatabaseDescriptor)
 	tbDescs := make(map[sqlbase.ID]*TableDescriptor)
+	scDescs := make(map[sqlbase.ID]*sqlbase.SchemaDescriptor)
 	var tbIDs, dbIDs []sqlbase.ID
 	// Record database descriptors for name lookups.
 	for _, desc := range descs {
@@ -599,6 +690,8 @@ func newInternalLookupCtx(
 				// Only make the table visible for iteration if the prefix was included.
 				tbIDs = append(tbIDs, d.ID)
 			}
+		case *sqlbase.SchemaDescriptor:
+			scDescs[d.ID] = d
 		}
 	}
 	return &internalLookupCtx{
@@ -607,6 +700,7 @@ func newInternalLookupCtx(
 		tbDescs: tbDescs,
 		tbIDs:   tbIDs,
 		dbIDs:   dbIDs,
+		scDescs: scDescs,
 	}
 }
 
@@ -691,3 +785,127 @@ func (p *planner) ResolveExistingObjectEx(
 func (p *planner) ResolvedName(u *tree.UnresolvedObjectName) *tree.TableName {
 	return u.Resolved(&p.semaCtx.Annotations)
 }
+
+// ResolveTypeByName implements the tree.TypeReferenceResolver interface.
+func (p *planner) ResolveTypeByName(nameParts []string) (*types.T, error) {
+	if len(nameParts) < 1 || len(nameParts) > 3 {
+		return nil, pgerror.Newf(pgerror.CodeSyntaxError,
+			"invalid type name: %s", strings.Join(nameParts, "."))
+	}
+	var parts [3]string
+	for i := range nameParts {
+		parts[i] = nameParts[len(nameParts)-1-i]
+	}
+	name, err := tree.NewUnresolvedObjectName(len(nameParts), parts, 0 /* annotationIdx */)
+	if err != nil {
+		return nil, err
+	}
+	_, desc, err := p.resolveTypeDescriptor(p.EvalContext().Context, name, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	return desc.MakeTypesT(), nil
+}
+
+// resolveTypeDescriptor looks up the descriptor of the user-defined type with
+// the given name. Type descriptors are not leased; they are always read from
+// the store using the planner's transaction. If the type does not exist, an
+// error is returned if required is true, otherwise a nil descriptor.
+func (p *planner) resolveTypeDescriptor(
+	ctx context.Context, name *tree.UnresolvedObjectName, required bool,
+) (*tree.TableName, *sqlbase.TypeDescriptor, error) {
+	tn := name.ToTableName()
+	var found bool
+	var scMeta tree.SchemaMeta
+	var err error
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		found, scMeta, err = tn.ResolveTarget(ctx, p, p.CurrentDatabase(), p.CurrentSearchPath())
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// At this point, only the public schema can contain types.
+	if found && tn.Schema() == tree.PublicSchema {
+		dbDesc := scMeta.(*DatabaseDescriptor)
+		id, err := getDescriptorID(ctx, p.txn, sqlbase.NewTableKey(dbDesc.ID, tn.Table()))
+		if err != nil {
+			return nil, nil, err
+		}
+		if id != sqlbase.InvalidID {
+			desc, err := sqlbase.GetTypeDescFromID(ctx, p.txn, id)
+			if err == nil {
+				return &tn, desc, nil
+			}
+			// The name may refer to a relation.
+			if err != sqlbase.ErrDescriptorNotFound {
+				return nil, nil, err
+			}
+		}
+	}
+	if required {
+		return nil, nil, sqlbase.NewUndefinedTypeError(&tn)
+	}
+	return &tn, nil, nil
+}
+
+// ResolveFunctionByName implements the tree.FunctionReferenceResolver
+// interface.
+func (p *planner) ResolveFunctionByName(name *tree.UnresolvedName) (*tree.FunctionDefinition, error) {
+	undefinedErr := func() error {
+		return pgerror.Newf(pgerror.CodeUndefinedFunctionError,
+			"unknown function: %s()", tree.ErrString(name))
+	}
+	if name.Star || name.NumParts > 3 || p.txn == nil {
+		return nil, undefinedErr()
+	}
+	if name.NumParts == 1 && p.CurrentDatabase() == "" {
+		return nil, undefinedErr()
+	}
+	objName, err := tree.NewUnresolvedObjectName(
+		name.NumParts, [3]string{name.Parts[0], name.Parts[1], name.Parts[2]}, 0, /* annotationIdx */
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, descs, err := p.resolveFunctionDescriptors(p.EvalContext().Context, objName)
+	if err != nil {
+		return nil, err
+	}
+	if len(descs) == 0 {
+		return nil, undefinedErr()
+	}
+	return makeFunctionDefinition(descs[0].Name, descs)
+}
+
+// resolveFunctionDescriptors looks up the descriptors of the overloads of the
+// user-defined function with the given name. Like type descriptors, function
+// descriptors are not leased. If the function does not exist, no descriptors
+// are returned.
+func (p *planner) resolveFunctionDescriptors(
+	ctx context.Context, name *tree.UnresolvedObjectName,
+) (*tree.TableName, []*sqlbase.FunctionDescriptor, error) {
+	tn := name.ToTableName()
+	var found bool
+	var scMeta tree.SchemaMeta
+	var err error
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		found, scMeta, err = tn.ResolveTarget(ctx, p, p.CurrentDatabase(), p.CurrentSearchPath())
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// At this point, only the public schema can contain functions.
+	if !found || tn.Schema() != tree.PublicSchema {
+		return &tn, nil, nil
+	}
+	dbDesc := scMeta.(*DatabaseDescriptor)
+	ids := dbDesc.FunctionIDs(tn.Table())
+	descs := make([]*sqlbase.FunctionDescriptor, len(ids))
+	for i, id := range ids {
+		descs[i], err = sqlbase.GetFunctionDescFromID(ctx, p.txn, id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return &tn, descs, nil
+}
@@ -372,6 +372,7 @@ func (c *cascader) addIndexPKRowFetcher(
 	isSecondary := table.PrimaryIndex.ID != index.ID
 	var rowFetcher Fetcher
 	if err := rowFetcher.Init(
+		c.evalCtx,
 		false, /* reverse */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
@@ -415,6 +416,7 @@ func (c *cascader) addRowDeleter(
 		c.fkTables,
 		nil, /* requestedCol */
 		CheckFKs,
+		c.evalCtx,
 		c.alloc,
 	)
 	if err != nil {
@@ -435,6 +437,7 @@ func (c *cascader) addRowDeleter(
 	}
 	var rowFetcher Fetcher
 	if err := rowFetcher.Init(
+		c.evalCtx,
 		false, /* reverse */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
@@ -475,6 +478,7 @@ func (c *cascader) addRowUpdater(
 		table.Columns,
 		nil, /* requestedCol */
 		UpdaterDefault,
+		c.evalCtx,
 		c.alloc,
 	)
 	if err != nil {
@@ -495,6 +499,7 @@ func (c *cascader) addRowUpdater(
 	}
 	var rowFetcher Fetcher
 	if err := rowFetcher.Init(
+		c.evalCtx,
 		false, /* reverse */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
@@ -287,6 +287,12 @@ func (rf *CFetcher) Init(
 	table.neededColsList = make([]int, 0, tableArgs.ValNeededForCol.Len())
 	for col, idx := range tableArgs.ColIdxMap {
 		if tableArgs.ValNeededForCol.Contains(idx) {
+			if colDescriptors[idx].Virtual && !tableArgs.IsSecondaryIndex {
+				// The scans that need virtual computed columns are not vectorized,
+				// see newColBatchScan.
+				return pgerror.AssertionFailedf(
+					"virtual computed column %q cannot be fetched by the CFetcher", colDescriptors[idx].Name)
+			}
 			// The idx-th column is required.
 			neededCols.Add(int(col))
 			table.neededColsList = append(table.neededColsList, int(col))
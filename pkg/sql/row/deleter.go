@@ -49,7 +49,7 @@ func MakeDeleter(
 	alloc *sqlbase.DatumAlloc,
 ) (Deleter, error) {
 	rowDeleter, err := makeRowDeleterWithoutCascader(
-		txn, tableDesc, fkTables, requestedCols, checkFKs, alloc,
+		txn, tableDesc, fkTables, requestedCols, checkFKs, evalCtx, alloc,
 	)
 	if err != nil {
 		return Deleter{}, err
@@ -72,6 +72,7 @@ func makeRowDeleterWithoutCascader(
 	fkTables FkTableMetadata,
 	requestedCols []sqlbase.ColumnDescriptor,
 	checkFKs checkFKConstraints,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (Deleter, error) {
 	indexes := tableDesc.DeletableIndexes()
@@ -107,17 +108,31 @@ func makeRowDeleterWithoutCascader(
 				return Deleter{}, err
 			}
 		}
+		// The columns referenced by the predicate of a partial index are
+		// needed to determine whether the row has an entry in the index.
+		predicateColIDs, err := index.PredicateColumnIDs(tableDesc.TableDesc())
+		if err != nil {
+			return Deleter{}, err
+		}
+		for _, colID := range predicateColIDs {
+			if err := maybeAddCol(colID); err != nil {
+				return Deleter{}, err
+			}
+		}
 	}
 
+	helper, err := newRowHelper(tableDesc, indexes, evalCtx)
+	if err != nil {
+		return Deleter{}, err
+	}
 	rd := Deleter{
-		Helper:               newRowHelper(tableDesc, indexes),
+		Helper:               helper,
 		FetchCols:            fetchCols,
 		FetchColIDtoRowIndex: fetchColIDtoRowIndex,
 	}
 	if checkFKs == CheckFKs {
-		var err error
 		if rd.Fks, err = makeFkExistenceCheckHelperForDelete(txn, tableDesc, fkTables,
-			fetchColIDtoRowIndex, alloc); err != nil {
+			fetchColIDtoRowIndex, evalCtx, alloc); err != nil {
 			return Deleter{}, err
 		}
 	}
@@ -144,6 +159,10 @@ func (rd *Deleter) DeleteRow(
 	// Delete the row from any secondary indices.
 	for i := range secondaryIndexEntries {
 		secondaryIndexEntry := &secondaryIndexEntries[i]
+		if secondaryIndexEntry.Key == nil {
+			// The row does not satisfy the predicate of a partial index.
+			continue
+		}
 		if traceKV {
 			log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(rd.Helper.secIndexValDirs[i], secondaryIndexEntry.Key))
 		}
@@ -80,6 +80,10 @@ type tableInfo struct {
 	// decoding values in that row.
 	neededValueCols int
 
+	// virtualCols computes the needed virtual computed columns, which are not
+	// stored in the primary index, once the row has been decoded.
+	virtualCols sqlbase.VirtualColumns
+
 	// Map used to get the index for columns in cols.
 	colIdxMap map[sqlbase.ColumnID]int
 
@@ -205,6 +209,12 @@ type Fetcher struct {
 	// when beginning a new scan.
 	traceKV bool
 
+	// lockStrength and lockWaitPolicy configure the row-level locking
+	// performed by scans started with StartScan. They are set with
+	// SetLocking.
+	lockStrength   sqlbase.ScanLockingStrength
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+
 	// -- Fields updated during a scan --
 
 	kvFetcher      kvFetcher
@@ -229,6 +239,9 @@ type Fetcher struct {
 
 	// Buffered allocation of decoded datums.
 	alloc *sqlbase.DatumAlloc
+
+	// evalCtx is used to compute the virtual computed columns.
+	evalCtx *tree.EvalContext
 }
 
 // Reset resets this Fetcher, preserving the memory capacity that was used
@@ -243,8 +256,10 @@ func (rf *Fetcher) Reset() {
 
 // Init sets up a Fetcher for a given table and index. If we are using a
 // non-primary index, tables.ValNeededForCol can only refer to columns in the
-// index.
+// index. The evalCtx is used to compute the needed virtual computed columns
+// and can be nil if there are none.
 func (rf *Fetcher) Init(
+	evalCtx *tree.EvalContext,
 	reverse, returnRangeInfo bool,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
@@ -254,6 +269,7 @@ func (rf *Fetcher) Init(
 		return pgerror.AssertionFailedf("no tables to fetch from")
 	}
 
+	rf.evalCtx = evalCtx
 	rf.reverse = reverse
 	rf.returnRangeInfo = returnRangeInfo
 	rf.alloc = alloc
@@ -324,10 +340,24 @@ func (rf *Fetcher) Init(
 			table.equivSignature = equivSignatures[len(equivSignatures)-1]
 		}
 
+		valNeededForCol := tableArgs.ValNeededForCol
+		if !table.isSecondaryIndex {
+			// The needed virtual computed columns are not read from the primary
+			// index, but computed from the columns they reference.
+			if table.virtualCols, err = sqlbase.MakeVirtualColumns(
+				table.desc, table.cols, table.colIdxMap, valNeededForCol, rf.evalCtx,
+			); err != nil {
+				return err
+			}
+			if !table.virtualCols.Empty() {
+				valNeededForCol = valNeededForCol.Union(table.virtualCols.Dependencies())
+			}
+		}
+
 		// Scan through the entire columns map to see which columns are
 		// required.
 		for col, idx := range table.colIdxMap {
-			if tableArgs.ValNeededForCol.Contains(idx) {
+			if valNeededForCol.Contains(idx) && !table.virtualCols.Columns().Contains(idx) {
 				// The idx-th column is required.
 				table.neededCols.Add(int(col))
 			}
@@ -338,7 +368,7 @@ func (rf *Fetcher) Init(
 		var indexColumnIDs []sqlbase.ColumnID
 		indexColumnIDs, table.indexColumnDirs = table.index.FullColumnIDs()
 
-		table.neededValueColsByIdx = tableArgs.ValNeededForCol.Copy()
+		table.neededValueColsByIdx = valNeededForCol.Copy()
 		neededIndexCols := 0
 		nIndexCols := len(indexColumnIDs)
 		if cap(table.indexColIdx) >= nIndexCols {
@@ -435,6 +465,17 @@ func (rf *Fetcher) Init(
 	return nil
 }
 
+// SetLocking configures the row-level locks that subsequent scans acquire on
+// the fetched rows, and what they do when a row is locked by another
+// transaction. Exclusive locks are acquired by writing intents, so they
+// require the fetcher's transaction to be a root transaction.
+func (rf *Fetcher) SetLocking(
+	strength sqlbase.ScanLockingStrength, waitPolicy sqlbase.ScanLockingWaitPolicy,
+) {
+	rf.lockStrength = strength
+	rf.lockWaitPolicy = waitPolicy
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *Fetcher) StartScan(
@@ -456,6 +497,9 @@ func (rf *Fetcher) StartScan(
 	if err != nil {
 		return err
 	}
+	f.lockStrength = rf.lockStrength
+	f.lockWaitPolicy = rf.lockWaitPolicy
+	f.lockTableName = rf.tables[0].desc.Name
 	return rf.StartScanFrom(ctx, &f)
 }
 
@@ -1353,7 +1397,7 @@ func (rf *Fetcher) finalizeRow() error {
 	for i := range table.cols {
 		if rf.valueColsFound == table.neededValueCols {
 			// Found all cols - done!
-			return nil
+			break
 		}
 		if table.neededCols.Contains(int(table.cols[i].ID)) && table.row[i].IsUnset() {
 			// If the row was deleted, we'll be missing any non-primary key
@@ -1387,7 +1431,18 @@ func (rf *Fetcher) finalizeRow() error {
 			rf.valueColsFound++
 		}
 	}
-	return nil
+
+	if table.virtualCols.Empty() || table.rowIsDeleted {
+		return nil
+	}
+	deps := table.virtualCols.Dependencies()
+	for idx, ok := deps.Next(0); ok; idx, ok = deps.Next(idx + 1) {
+		if err := table.row[idx].EnsureDecoded(&table.cols[idx].Type, rf.alloc); err != nil {
+			return err
+		}
+		table.decodedRow[idx] = table.row[idx].Datum
+	}
+	return table.virtualCols.Compute(table.decodedRow, table.row)
 }
 
 // Key returns the next key (the key that follows the last returned row).
@@ -72,7 +72,7 @@ func initFetcher(
 
 	fetcherArgs := makeFetcherArgs(entries)
 
-	if err := fetcher.Init(reverseScan, false /*reverse*/, false, /* isCheck */
+	if err := fetcher.Init(nil /* evalCtx */, reverseScan, false /*reverse*/, false, /* isCheck */
 		alloc, fetcherArgs...); err != nil {
 		return nil, err
 	}
@@ -1040,7 +1040,7 @@ func TestRowFetcherReset(t *testing.T) {
 	// didn't reset.
 
 	fetcherArgs := makeFetcherArgs(args)
-	if err := resetFetcher.Init(false, false /*reverse*/, false, /* isCheck */
+	if err := resetFetcher.Init(nil /* evalCtx */, false, false /*reverse*/, false, /* isCheck */
 		&da, fetcherArgs...); err != nil {
 		t.Fatal(err)
 	}
@@ -75,6 +75,10 @@ type fkExistenceCheckBaseHelper struct {
 	// mutation, only the match style. Simplify this.
 	ref sqlbase.ForeignKeyReference
 
+	// constraint identifies the FK constraint, which belongs to the
+	// referencing table, for the checks which can be deferred.
+	constraint tree.ConstraintID
+
 	// searchTable is the descriptor of the searched table. Stored only
 	// for error messages; lookups use the pre-computed searchPrefix.
 	searchTable *sqlbase.ImmutableTableDescriptor
@@ -159,6 +163,7 @@ func makeFkExistenceCheckBaseHelper(
 	}
 	rf := &Fetcher{}
 	if err := rf.Init(
+		nil, /* evalCtx */
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, alloc, tableArgs); err != nil {
 		return ret, err
 	}
@@ -241,3 +246,35 @@ func computeFkCheckColumnIDs(
 }
 
 var errSkipUnusedFK = errors.New("no columns involved in FK included in writer")
+
+// violation returns the error reported when the check of the FK constraint
+// fails for the given row. The row holds the new values for checks of
+// inserts, and the old values for checks of deletes.
+func (fk *fkExistenceCheckBaseHelper) violation(row tree.Datums, txn *client.Txn) error {
+	switch fk.dir {
+	case CheckInserts:
+		for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
+			fk.valuesScratch[valueIdx] = row[fk.ids[colID]]
+		}
+		return pgerror.Newf(pgerror.CodeForeignKeyViolationError,
+			"foreign key violation: value %s not found in %s@%s %s (txn=%s)",
+			fk.valuesScratch, fk.searchTable.Name, fk.searchIdx.Name,
+			fk.searchIdx.ColumnNames[:fk.prefixLen], txn.ID())
+
+	case CheckDeletes:
+		if row == nil {
+			return pgerror.Newf(pgerror.CodeForeignKeyViolationError,
+				"foreign key violation: non-empty columns %s referenced in table %q",
+				fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
+		}
+		for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
+			fk.valuesScratch[valueIdx] = row[fk.ids[colID]]
+		}
+		return pgerror.Newf(pgerror.CodeForeignKeyViolationError,
+			"foreign key violation: values %v in columns %s referenced in table %q",
+			fk.valuesScratch, fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
+
+	default:
+		return pgerror.AssertionFailedf("impossible case: fkExistenceCheckBaseHelper has dir=%v", fk.dir)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -33,6 +34,10 @@ type fkExistenceBatchChecker struct {
 	// should not be captured in structs.
 	txn *client.Txn
 
+	// constraints is used to queue the checks of deferred constraints until
+	// the transaction commits. It is nil if no check can be deferred.
+	constraints tree.DeferrableConstraints
+
 	// batch is the accumulated batch of existence checks so far.
 	batch roachpb.BatchRequest
 
@@ -48,6 +53,8 @@ func (f *fkExistenceBatchChecker) reset() {
 }
 
 // addCheck adds a check for the given row and fkExistenceCheckBaseHelper to the batch.
+// The check of a deferred constraint is queued until the transaction commits
+// instead.
 func (f *fkExistenceBatchChecker) addCheck(
 	ctx context.Context, row tree.Datums, source *fkExistenceCheckBaseHelper, traceKV bool,
 ) error {
@@ -55,6 +62,17 @@ func (f *fkExistenceBatchChecker) addCheck(
 	if err != nil {
 		return err
 	}
+	if f.deferred(source) {
+		if traceKV {
+			log.VEventf(ctx, 2, "FKScan %s (deferred)", span)
+		}
+		f.constraints.QueueCheck(source.constraint, &deferredFKCheck{
+			span: span,
+			dir:  source.dir,
+			err:  source.violation(row, f.txn),
+		}, true /* deferred */)
+		return nil
+	}
 	scan := roachpb.ScanRequest{
 		RequestHeader: roachpb.RequestHeaderFromSpan(span),
 	}
@@ -100,30 +118,13 @@ func (f *fkExistenceBatchChecker) runCheck(
 		case CheckInserts:
 			// If we're inserting, then there's a violation if the scan found nothing.
 			if fk.rf.kvEnd {
-				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
-					fk.valuesScratch[valueIdx] = newRow[fk.ids[colID]]
-				}
-				return pgerror.Newf(pgerror.CodeForeignKeyViolationError,
-					"foreign key violation: value %s not found in %s@%s %s (txn=%s)",
-					fk.valuesScratch, fk.searchTable.Name, fk.searchIdx.Name,
-					fk.searchIdx.ColumnNames[:fk.prefixLen], f.txn.ID())
+				return fk.violation(newRow, f.txn)
 			}
 
 		case CheckDeletes:
 			// If we're deleting, then there's a violation if the scan found something.
 			if !fk.rf.kvEnd {
-				if oldRow == nil {
-					return pgerror.Newf(pgerror.CodeForeignKeyViolationError,
-						"foreign key violation: non-empty columns %s referenced in table %q",
-						fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
-				}
-
-				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
-					fk.valuesScratch[valueIdx] = oldRow[fk.ids[colID]]
-				}
-				return pgerror.Newf(pgerror.CodeForeignKeyViolationError,
-					"foreign key violation: values %v in columns %s referenced in table %q",
-					fk.valuesScratch, fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
+				return fk.violation(oldRow, f.txn)
 			}
 
 		default:
@@ -134,6 +135,51 @@ func (f *fkExistenceBatchChecker) runCheck(
 	return nil
 }
 
+// deferred returns whether the check of the given FK constraint must be
+// queued until the transaction commits.
+func (f *fkExistenceBatchChecker) deferred(fk *fkExistenceCheckBaseHelper) bool {
+	if f.constraints == nil || fk.ref.Deferrability == sqlbase.ConstraintDeferrability_NotDeferrable {
+		return false
+	}
+	return f.constraints.Deferred(
+		fk.constraint, fk.ref.Deferrability == sqlbase.ConstraintDeferrability_InitiallyDeferred)
+}
+
+// deferredFKCheck is the check of a deferred FK constraint for one row,
+// which is run when the transaction commits.
+type deferredFKCheck struct {
+	// span covers the searched rows.
+	span roachpb.Span
+	// dir indicates whether the searched rows must exist or not.
+	dir FKCheckType
+	// err is the error reported if the check fails. It is prepared when the
+	// check is queued, while the values of the row are available.
+	err error
+}
+
+var _ tree.ConstraintCheck = &deferredFKCheck{}
+
+// Run implements the tree.ConstraintCheck interface.
+func (c *deferredFKCheck) Run(ctx context.Context, txn *client.Txn) error {
+	kvs, err := txn.Scan(ctx, c.span.Key, c.span.EndKey, 1 /* maxRows */)
+	if err != nil {
+		return err
+	}
+	switch c.dir {
+	case CheckInserts:
+		if len(kvs) == 0 {
+			return c.err
+		}
+	case CheckDeletes:
+		if len(kvs) > 0 {
+			return c.err
+		}
+	default:
+		return pgerror.AssertionFailedf("impossible case: deferredFKCheck has dir=%v", c.dir)
+	}
+	return nil
+}
+
 // SpanKVFetcher is a kvBatchFetcher that returns a set slice of kvs.
 type SpanKVFetcher struct {
 	KVs []roachpb.KeyValue
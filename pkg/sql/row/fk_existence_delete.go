@@ -39,6 +39,7 @@ func makeFkExistenceCheckHelperForDelete(
 	table *sqlbase.ImmutableTableDescriptor,
 	otherTables FkTableMetadata,
 	colMap map[sqlbase.ColumnID]int,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (fkExistenceCheckForDelete, error) {
 	h := fkExistenceCheckForDelete{
@@ -46,6 +47,9 @@ func makeFkExistenceCheckHelperForDelete(
 			txn: txn,
 		},
 	}
+	if evalCtx != nil {
+		h.checker.constraints = evalCtx.Constraints
+	}
 
 	// We need an existence check helper for every referencing
 	// table. Today, referencing tables are determined by
@@ -66,6 +70,7 @@ func makeFkExistenceCheckHelperForDelete(
 			if err != nil {
 				return fkExistenceCheckForDelete{}, err
 			}
+			fk.constraint = tree.ConstraintID{TableID: uint32(ref.Table), Name: fk.searchIdx.ForeignKey.Name}
 			if h.fks == nil {
 				h.fks = make(map[sqlbase.IndexID][]fkExistenceCheckBaseHelper)
 			}
@@ -48,6 +48,7 @@ func makeFkExistenceCheckHelperForInsert(
 	table *sqlbase.ImmutableTableDescriptor,
 	otherTables FkTableMetadata,
 	colMap map[sqlbase.ColumnID]int,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (fkExistenceCheckForInsert, error) {
 	h := fkExistenceCheckForInsert{
@@ -55,6 +56,9 @@ func makeFkExistenceCheckHelperForInsert(
 			txn: txn,
 		},
 	}
+	if evalCtx != nil {
+		h.checker.constraints = evalCtx.Constraints
+	}
 
 	// We need an existence check helper for every referenced
 	// table. Today, referenced tables are determined by
@@ -70,6 +74,7 @@ func makeFkExistenceCheckHelperForInsert(
 			if err != nil {
 				return h, err
 			}
+			fk.constraint = tree.ConstraintID{TableID: uint32(table.ID), Name: idx.ForeignKey.Name}
 			if h.fks == nil {
 				h.fks = make(map[sqlbase.IndexID][]fkExistenceCheckBaseHelper)
 			}
@@ -68,6 +68,7 @@ func makeFkExistenceCheckHelperForUpdate(
 	table *sqlbase.ImmutableTableDescriptor,
 	otherTables FkTableMetadata,
 	colMap map[sqlbase.ColumnID]int,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (fkExistenceCheckForUpdate, error) {
 	ret := fkExistenceCheckForUpdate{
@@ -76,12 +77,12 @@ func makeFkExistenceCheckHelperForUpdate(
 
 	// Instantiate a helper for the referencing tables.
 	var err error
-	if ret.inbound, err = makeFkExistenceCheckHelperForDelete(txn, table, otherTables, colMap, alloc); err != nil {
+	if ret.inbound, err = makeFkExistenceCheckHelperForDelete(txn, table, otherTables, colMap, evalCtx, alloc); err != nil {
 		return ret, err
 	}
 
 	// Instantiate a helper for the referenced table(s).
-	ret.outbound, err = makeFkExistenceCheckHelperForInsert(txn, table, otherTables, colMap, alloc)
+	ret.outbound, err = makeFkExistenceCheckHelperForInsert(txn, table, otherTables, colMap, evalCtx, alloc)
 	ret.outbound.checker = ret.inbound.checker
 
 	// We need *some* KV batch checker to perform the checks. It doesn't
@@ -28,6 +28,13 @@ type rowHelper struct {
 	Indexes      []sqlbase.IndexDescriptor
 	indexEntries []sqlbase.IndexEntry
 
+	// Predicates of the partial indexes among Indexes.
+	predicates sqlbase.PartialIndexPredicates
+
+	// constraints queues the checks of the deferrable UNIQUE constraints
+	// among Indexes. See queueUniqueChecks.
+	constraints tree.DeferrableConstraints
+
 	// Computed during initialization for pretty-printing.
 	primIndexValDirs []encoding.Direction
 	secIndexValDirs  [][]encoding.Direction
@@ -39,10 +46,20 @@ type rowHelper struct {
 }
 
 func newRowHelper(
-	desc *sqlbase.ImmutableTableDescriptor, indexes []sqlbase.IndexDescriptor,
-) rowHelper {
+	desc *sqlbase.ImmutableTableDescriptor,
+	indexes []sqlbase.IndexDescriptor,
+	evalCtx *tree.EvalContext,
+) (rowHelper, error) {
 	rh := rowHelper{TableDesc: desc, Indexes: indexes}
 
+	var err error
+	if rh.predicates, err = sqlbase.MakePartialIndexPredicates(desc, indexes, evalCtx); err != nil {
+		return rowHelper{}, err
+	}
+	if evalCtx != nil {
+		rh.constraints = evalCtx.Constraints
+	}
+
 	// Pre-compute the encoding directions of the index key values for
 	// pretty-printing in traces.
 	rh.primIndexValDirs = sqlbase.IndexKeyValDirs(&rh.TableDesc.PrimaryIndex)
@@ -52,7 +69,7 @@ func newRowHelper(
 		rh.secIndexValDirs[i] = sqlbase.IndexKeyValDirs(&rh.Indexes[i])
 	}
 
-	return rh
+	return rh, nil
 }
 
 // encodeIndexes encodes the primary and secondary index keys. The
@@ -79,7 +96,8 @@ func (rh *rowHelper) encodeIndexes(
 
 // encodeSecondaryIndexes encodes the secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
-// encodeSecondaryIndexes.
+// encodeSecondaryIndexes. The entry of a partial index whose predicate the row
+// does not satisfy has a nil Key.
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[sqlbase.ColumnID]int, values []tree.Datum,
 ) (secondaryIndexEntries []sqlbase.IndexEntry, err error) {
@@ -91,6 +109,19 @@ func (rh *rowHelper) encodeSecondaryIndexes(
 	if err != nil {
 		return nil, err
 	}
+	if !rh.predicates.Empty() {
+		// Partial indexes cannot be inverted, so the i-th entry belongs to
+		// the i-th index.
+		for i := range rh.Indexes {
+			holds, err := rh.predicates.Holds(i, colIDtoRowIndex, values)
+			if err != nil {
+				return nil, err
+			}
+			if !holds {
+				rh.indexEntries[i] = sqlbase.IndexEntry{}
+			}
+		}
+	}
 	return rh.indexEntries, nil
 }
 
@@ -47,10 +47,15 @@ func MakeInserter(
 	fkTables FkTableMetadata,
 	insertCols []sqlbase.ColumnDescriptor,
 	checkFKs checkFKConstraints,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (Inserter, error) {
+	helper, err := newRowHelper(tableDesc, tableDesc.WritableIndexes(), evalCtx)
+	if err != nil {
+		return Inserter{}, err
+	}
 	ri := Inserter{
-		Helper:                newRowHelper(tableDesc, tableDesc.WritableIndexes()),
+		Helper:                helper,
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
 		marshaled:             make([]roachpb.Value, len(insertCols)),
@@ -63,9 +68,8 @@ func MakeInserter(
 	}
 
 	if checkFKs == CheckFKs {
-		var err error
 		if ri.Fks, err = makeFkExistenceCheckHelperForInsert(txn, tableDesc, fkTables,
-			ri.InsertColIDtoRowIndex, alloc); err != nil {
+			ri.InsertColIDtoRowIndex, evalCtx, alloc); err != nil {
 			return ri, err
 		}
 	}
@@ -177,8 +181,12 @@ func (ri *Inserter) InsertRow(
 	putFn = insertInvertedPutFn
 	for i := range secondaryIndexEntries {
 		e := &secondaryIndexEntries[i]
+		if e.Key == nil {
+			// The row does not satisfy the predicate of a partial index.
+			continue
+		}
 		putFn(ctx, b, &e.Key, &e.Value, traceKV)
 	}
 
-	return nil
+	return ri.Helper.queueUniqueChecks(ctx, ri.InsertColIDtoRowIndex, values, traceKV)
 }
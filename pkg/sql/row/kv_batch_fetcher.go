@@ -15,11 +15,14 @@ package row
 import (
 	"bytes"
 	"context"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
@@ -77,6 +80,14 @@ type txnKVFetcher struct {
 	rangeInfos       []roachpb.RangeInfo
 	origSpan         roachpb.Span
 	remainingBatches [][]byte
+
+	// lockStrength and lockWaitPolicy configure the row-level locking
+	// performed on the fetched rows. See Fetcher.SetLocking.
+	lockStrength   sqlbase.ScanLockingStrength
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+	// lockTableName is the name of the table being fetched from, used in
+	// errors about rows that could not be locked.
+	lockTableName string
 }
 
 var _ kvBatchFetcher = &txnKVFetcher{}
@@ -229,6 +240,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	ba.Header.WaitPolicy = f.getWaitPolicy()
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	if f.reverse {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
@@ -268,7 +280,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 
 	br, err := f.sendFn(ctx, ba)
 	if err != nil {
-		return err
+		return f.handleLockConflict(ctx, err)
 	}
 	if br != nil {
 		f.responses = br.Responses
@@ -306,6 +318,12 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		}
 	}
 
+	if f.lockStrength.IsExclusive() {
+		if err := f.lockRows(ctx, f.responses); err != nil {
+			return f.handleLockConflict(ctx, err)
+		}
+	}
+
 	f.batchIdx++
 
 	// TODO(radu): We should fetch the next chunk in the background instead of waiting for the next
@@ -355,3 +373,136 @@ func (f *txnKVFetcher) nextBatch(
 	}
 	return f.nextBatch(ctx)
 }
+
+// getWaitPolicy returns the wait policy of the requests sent by the fetcher.
+func (f *txnKVFetcher) getWaitPolicy() roachpb.WaitPolicy {
+	if f.lockWaitPolicy != sqlbase.ScanLockingWaitPolicy_BLOCK {
+		return roachpb.WaitPolicy_ERROR
+	}
+	return roachpb.WaitPolicy_BLOCK
+}
+
+// handleLockConflict handles an error returned while scanning or locking the
+// requested spans. If the error is a conflict with another transaction's
+// intents, it is handled according to the wait policy: NOWAIT returns an
+// error, and SKIP LOCKED fetches the requested spans again without the rows
+// that are locked. Any other error is returned as is.
+func (f *txnKVFetcher) handleLockConflict(ctx context.Context, err error) error {
+	wiErr, ok := err.(*roachpb.WriteIntentError)
+	if !ok {
+		return err
+	}
+	switch f.lockWaitPolicy {
+	case sqlbase.ScanLockingWaitPolicy_ERROR:
+		return pgerror.Newf(pgerror.CodeLockNotAvailableError,
+			"could not obtain lock on row in relation %q", f.lockTableName)
+	case sqlbase.ScanLockingWaitPolicy_SKIP:
+		// Retry the batch without the rows that are locked by other
+		// transactions.
+		spans, changed, err := skipLockedRows(f.requestSpans, wiErr.Intents, f.reverse)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return wiErr
+		}
+		f.spans = spans
+		if len(f.spans) == 0 {
+			f.responses = nil
+			f.fetchEnd = true
+			return nil
+		}
+		return f.fetch(ctx)
+	}
+	return wiErr
+}
+
+// lockRows acquires exclusive locks on the rows returned in the given scan
+// responses. The locks are held until the transaction finishes and block
+// other transactions that try to lock or write the same rows.
+func (f *txnKVFetcher) lockRows(ctx context.Context, responses []roachpb.ResponseUnion) error {
+	var ba roachpb.BatchRequest
+	ba.Header.WaitPolicy = f.getWaitPolicy()
+	lockKV := func(key roachpb.Key) {
+		// Copy the key since it may point into the scan response.
+		ba.Add(roachpb.NewLock(append(roachpb.Key(nil), key...)))
+	}
+	for _, resp := range responses {
+		var kvs []roachpb.KeyValue
+		var batchResponses [][]byte
+		switch t := resp.GetInner().(type) {
+		case *roachpb.ScanResponse:
+			kvs, batchResponses = t.Rows, t.BatchResponses
+		case *roachpb.ReverseScanResponse:
+			kvs, batchResponses = t.Rows, t.BatchResponses
+		}
+		for i := range kvs {
+			lockKV(kvs[i].Key)
+		}
+		for _, batchResp := range batchResponses {
+			for len(batchResp) > 0 {
+				var key []byte
+				var err error
+				key, _, batchResp, err = enginepb.ScanDecodeKeyValueNoTS(batchResp)
+				if err != nil {
+					return err
+				}
+				lockKV(key)
+			}
+		}
+	}
+	if len(ba.Requests) == 0 {
+		return nil
+	}
+	log.VEventf(ctx, 2, "locking %d keys", len(ba.Requests))
+	_, err := f.sendFn(ctx, ba)
+	return err
+}
+
+// skipLockedRows removes the rows containing the given intents from the
+// spans. The order of the spans, which is decreasing for reverse scans, is
+// preserved. It returns whether any of the spans changed.
+func skipLockedRows(
+	spans roachpb.Spans, intents []roachpb.Intent, reverse bool,
+) (_ roachpb.Spans, changed bool, _ error) {
+	rows := make(roachpb.Spans, 0, len(intents))
+	for i := range intents {
+		rowKey, err := keys.EnsureSafeSplitKey(intents[i].Span.Key)
+		if err != nil {
+			return nil, false, err
+		}
+		rows = append(rows, roachpb.Span{Key: rowKey, EndKey: rowKey.PrefixEnd()})
+	}
+	sort.Sort(rows)
+
+	res := make(roachpb.Spans, 0, len(spans))
+	for _, sp := range spans {
+		if len(sp.EndKey) == 0 {
+			sp.EndKey = sp.Key.Next()
+		}
+		start := len(res)
+		for _, row := range rows {
+			if !sp.Overlaps(row) {
+				continue
+			}
+			changed = true
+			if sp.Key.Compare(row.Key) < 0 {
+				res = append(res, roachpb.Span{Key: sp.Key, EndKey: row.Key})
+			}
+			sp.Key = row.EndKey
+			if sp.Key.Compare(sp.EndKey) >= 0 {
+				break
+			}
+		}
+		if sp.Key.Compare(sp.EndKey) < 0 {
+			res = append(res, sp)
+		}
+		if reverse {
+			// The pieces of each span need to be scanned in decreasing order too.
+			for i, j := start, len(res)-1; i < j; i, j = i+1, j-1 {
+				res[i], res[j] = res[j], res[i]
+			}
+		}
+	}
+	return res, changed, nil
+}
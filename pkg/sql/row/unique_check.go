@@ -0,0 +1,128 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package row
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// uniqueCheck is the check of a deferrable UNIQUE constraint for one written
+// row. The index of such a constraint is not unique, so its entries are
+// suffixed with the primary key and duplicate values can be written; the
+// check verifies that a single entry exists for the values of the row once
+// the statement, or the transaction, has written all its rows.
+type uniqueCheck struct {
+	// span covers the entries of the index for the values of the row.
+	span roachpb.Span
+	// err is the error reported if the check fails. It is prepared when the
+	// check is queued, while the values of the row are available.
+	err error
+}
+
+var _ tree.ConstraintCheck = &uniqueCheck{}
+
+// Run implements the tree.ConstraintCheck interface.
+func (c *uniqueCheck) Run(ctx context.Context, txn *client.Txn) error {
+	kvs, err := txn.Scan(ctx, c.span.Key, c.span.EndKey, 2 /* maxRows */)
+	if err != nil {
+		return err
+	}
+	if len(kvs) > 1 {
+		return c.err
+	}
+	return nil
+}
+
+// queueUniqueChecks queues a check for every deferrable UNIQUE constraint
+// whose index has an entry for the given row. Nothing is checked when no
+// queue of constraint checks was provided at initialization.
+func (rh *rowHelper) queueUniqueChecks(
+	ctx context.Context, colIDtoRowIndex map[sqlbase.ColumnID]int, values tree.Datums, traceKV bool,
+) error {
+	if rh.constraints == nil {
+		return nil
+	}
+	for i := range rh.Indexes {
+		if rh.Indexes[i].UniqueDeferrability == sqlbase.ConstraintDeferrability_NotDeferrable {
+			continue
+		}
+		if !rh.predicates.Empty() {
+			holds, err := rh.predicates.Holds(i, colIDtoRowIndex, values)
+			if err != nil {
+				return err
+			}
+			if !holds {
+				continue
+			}
+		}
+		if err := rh.queueUniqueCheck(ctx, i, colIDtoRowIndex, values, traceKV); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueUniqueCheck queues the check of the deferrable UNIQUE constraint of
+// the i-th index for the given row, which has an entry in the index.
+func (rh *rowHelper) queueUniqueCheck(
+	ctx context.Context,
+	i int,
+	colIDtoRowIndex map[sqlbase.ColumnID]int,
+	values tree.Datums,
+	traceKV bool,
+) error {
+	index := &rh.Indexes[i]
+	if rh.constraints == nil || index.UniqueDeferrability == sqlbase.ConstraintDeferrability_NotDeferrable {
+		return nil
+	}
+	prefix := sqlbase.MakeIndexKeyPrefix(rh.TableDesc.TableDesc(), index.ID)
+	span, containsNull, err := sqlbase.EncodePartialIndexSpan(
+		rh.TableDesc.TableDesc(), index, len(index.ColumnIDs), colIDtoRowIndex, values, prefix)
+	if err != nil {
+		return err
+	}
+	if containsNull {
+		// NULL values are never equal to each other.
+		return nil
+	}
+	// The entries are suffixed with the primary key of their row.
+	span.EndKey = span.Key.PrefixEnd()
+
+	valStrs := make([]string, len(index.ColumnIDs))
+	for j, colID := range index.ColumnIDs {
+		valStrs[j] = values[colIDtoRowIndex[colID]].String()
+	}
+	id := tree.ConstraintID{TableID: uint32(rh.TableDesc.ID), Name: index.Name}
+	deferred := rh.constraints.Deferred(
+		id, index.UniqueDeferrability == sqlbase.ConstraintDeferrability_InitiallyDeferred)
+	if traceKV {
+		log.VEventf(ctx, 2, "UniqueScan %s (deferred=%t)", span, deferred)
+	}
+	rh.constraints.QueueCheck(id, &uniqueCheck{
+		span: span,
+		err: pgerror.Newf(pgerror.CodeUniqueViolationError,
+			"duplicate key value (%s)=(%s) violates unique constraint %q",
+			strings.Join(index.ColumnNames, ","),
+			strings.Join(valStrs, ","),
+			index.Name),
+	}, deferred)
+	return nil
+}
@@ -84,7 +84,7 @@ func MakeUpdater(
 	alloc *sqlbase.DatumAlloc,
 ) (Updater, error) {
 	rowUpdater, err := makeUpdaterWithoutCascader(
-		txn, tableDesc, fkTables, updateCols, requestedCols, updateType, alloc,
+		txn, tableDesc, fkTables, updateCols, requestedCols, updateType, evalCtx, alloc,
 	)
 	if err != nil {
 		return Updater{}, err
@@ -113,6 +113,7 @@ func makeUpdaterWithoutCascader(
 	updateCols []sqlbase.ColumnDescriptor,
 	requestedCols []sqlbase.ColumnDescriptor,
 	updateType rowUpdaterType,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (Updater, error) {
 	updateColIDtoRowIndex := ColIDtoRowIndexFromCols(updateCols)
@@ -130,29 +131,53 @@ func makeUpdaterWithoutCascader(
 		}
 	}
 
+	// runOverIndexColumns calls fn on all the columns of an index, including
+	// the columns referenced by its predicate if it is a partial index.
+	runOverIndexColumns := func(index *sqlbase.IndexDescriptor, fn func(sqlbase.ColumnID) error) error {
+		if err := index.RunOverAllColumns(fn); err != nil {
+			return err
+		}
+		predicateColIDs, err := index.PredicateColumnIDs(tableDesc.TableDesc())
+		if err != nil {
+			return err
+		}
+		for _, colID := range predicateColIDs {
+			if err := fn(colID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// Secondary indexes needing updating.
-	needsUpdate := func(index sqlbase.IndexDescriptor) bool {
+	needsUpdate := func(index *sqlbase.IndexDescriptor) (bool, error) {
 		if updateType == UpdaterOnlyColumns {
 			// Only update columns.
-			return false
+			return false, nil
 		}
 		// If the primary key changed, we need to update all of them.
 		if primaryKeyColChange {
-			return true
+			return true, nil
 		}
-		return index.RunOverAllColumns(func(id sqlbase.ColumnID) error {
+		err := runOverIndexColumns(index, func(id sqlbase.ColumnID) error {
 			if _, ok := updateColIDtoRowIndex[id]; ok {
 				return returnTruePseudoError
 			}
 			return nil
-		}) != nil
+		})
+		if err == returnTruePseudoError {
+			return true, nil
+		}
+		return false, err
 	}
 
 	writableIndexes := tableDesc.WritableIndexes()
 	includeIndexes := make([]sqlbase.IndexDescriptor, 0, len(writableIndexes))
-	for _, index := range writableIndexes {
-		if needsUpdate(index) {
-			includeIndexes = append(includeIndexes, index)
+	for i := range writableIndexes {
+		if update, err := needsUpdate(&writableIndexes[i]); err != nil {
+			return Updater{}, err
+		} else if update {
+			includeIndexes = append(includeIndexes, writableIndexes[i])
 		}
 	}
 
@@ -161,7 +186,9 @@ func makeUpdaterWithoutCascader(
 
 	var deleteOnlyIndexes []sqlbase.IndexDescriptor
 	for _, idx := range tableDesc.DeleteOnlyIndexes() {
-		if needsUpdate(idx) {
+		if update, err := needsUpdate(&idx); err != nil {
+			return Updater{}, err
+		} else if update {
 			if deleteOnlyIndexes == nil {
 				// Allocate at most once.
 				deleteOnlyIndexes = make([]sqlbase.IndexDescriptor, 0, len(tableDesc.DeleteOnlyIndexes()))
@@ -172,12 +199,19 @@ func makeUpdaterWithoutCascader(
 
 	var deleteOnlyHelper *rowHelper
 	if len(deleteOnlyIndexes) > 0 {
-		rh := newRowHelper(tableDesc, deleteOnlyIndexes)
+		rh, err := newRowHelper(tableDesc, deleteOnlyIndexes, evalCtx)
+		if err != nil {
+			return Updater{}, err
+		}
 		deleteOnlyHelper = &rh
 	}
 
+	helper, err := newRowHelper(tableDesc, includeIndexes, evalCtx)
+	if err != nil {
+		return Updater{}, err
+	}
 	ru := Updater{
-		Helper:                newRowHelper(tableDesc, includeIndexes),
+		Helper:                helper,
 		DeleteHelper:          deleteOnlyHelper,
 		UpdateCols:            updateCols,
 		UpdateColIDtoRowIndex: updateColIDtoRowIndex,
@@ -190,16 +224,15 @@ func makeUpdaterWithoutCascader(
 		// These fields are only used when the primary key is changing.
 		// When changing the primary key, we delete the old values and reinsert
 		// them, so request them all.
-		var err error
 		if ru.rd, err = makeRowDeleterWithoutCascader(
-			txn, tableDesc, fkTables, tableCols, SkipFKs, alloc,
+			txn, tableDesc, fkTables, tableCols, SkipFKs, evalCtx, alloc,
 		); err != nil {
 			return Updater{}, err
 		}
 		ru.FetchCols = ru.rd.FetchCols
 		ru.FetchColIDtoRowIndex = ColIDtoRowIndexFromCols(ru.FetchCols)
 		if ru.ri, err = MakeInserter(txn, tableDesc, fkTables,
-			tableCols, SkipFKs, alloc); err != nil {
+			tableCols, SkipFKs, evalCtx, alloc); err != nil {
 			return Updater{}, err
 		}
 	} else {
@@ -251,21 +284,20 @@ func makeUpdaterWithoutCascader(
 
 		// Fetch all columns from indices that are being update so that they can
 		// be used to create the new kv pairs for those indices.
-		for _, index := range includeIndexes {
-			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
+		for i := range includeIndexes {
+			if err := runOverIndexColumns(&includeIndexes[i], maybeAddCol); err != nil {
 				return Updater{}, err
 			}
 		}
-		for _, index := range deleteOnlyIndexes {
-			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
+		for i := range deleteOnlyIndexes {
+			if err := runOverIndexColumns(&deleteOnlyIndexes[i], maybeAddCol); err != nil {
 				return Updater{}, err
 			}
 		}
 	}
 
-	var err error
 	if ru.Fks, err = makeFkExistenceCheckHelperForUpdate(txn, tableDesc, fkTables,
-		ru.FetchColIDtoRowIndex, alloc); err != nil {
+		ru.FetchColIDtoRowIndex, evalCtx, alloc); err != nil {
 		return Updater{}, err
 	}
 	return ru, nil
@@ -427,10 +459,22 @@ func (ru *Updater) UpdateRow(
 		var expValue interface{}
 		if !bytes.Equal(newSecondaryIndexEntry.Key, oldSecondaryIndexEntry.Key) {
 			ru.Fks.addCheckForIndex(ru.Helper.Indexes[i].ID, ru.Helper.Indexes[i].Type)
-			if traceKV {
-				log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
+			// The key of a partial index entry is nil if the row does not
+			// satisfy the predicate of the index.
+			if oldSecondaryIndexEntry.Key != nil {
+				if traceKV {
+					log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
+				}
+				batch.Del(oldSecondaryIndexEntry.Key)
+			}
+			if newSecondaryIndexEntry.Key == nil {
+				continue
+			}
+			if err := ru.Helper.queueUniqueCheck(
+				ctx, i, ru.FetchColIDtoRowIndex, ru.newValues, traceKV,
+			); err != nil {
+				return nil, err
 			}
-			batch.Del(oldSecondaryIndexEntry.Key)
 		} else if !newSecondaryIndexEntry.Value.EqualData(oldSecondaryIndexEntry.Value) {
 			expValue = &oldSecondaryIndexEntry.Value
 		} else {
@@ -453,6 +497,9 @@ func (ru *Updater) UpdateRow(
 	// indexed will be handled separately.
 	if ru.DeleteHelper != nil {
 		for _, deletedSecondaryIndexEntry := range deleteOldSecondaryIndexEntries {
+			if deletedSecondaryIndexEntry.Key == nil {
+				continue
+			}
 			if traceKV {
 				log.VEventf(ctx, 2, "Del %s", deletedSecondaryIndexEntry.Key)
 			}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Besides the public schema, which is the database itself, a database can
+// contain user-defined schemas, created with CREATE SCHEMA, and the
+// temporary schemas of the sessions that created temporary tables in it.
+//
+// A user-defined schema has a SchemaDescriptor, and a namespace entry
+// (database ID, schema name) -> schema ID; schemas thus share the namespace
+// of the database with the tables in its public schema. The objects in a
+// user-defined schema have the database as their ParentID and use the schema
+// ID as their namespace parent (see TableDescriptor.NamespaceParentID).
+
+// resolveSchemaID returns the ID to use as the namespace parent for objects
+// in the given schema of the database with ID dbID. For the public schema
+// this is the database ID itself. InvalidID is returned if there is no
+// namespace entry with the schema's name.
+//
+// resolveSchemaID does not check that the namespace entry belongs to a
+// schema: entries for tables and types are never the namespace parent of any
+// object, so looking up an object in them simply finds nothing.
+func resolveSchemaID(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (sqlbase.ID, error) {
+	if scName == tree.PublicSchema {
+		return dbID, nil
+	}
+	return getDescriptorID(ctx, txn, sqlbase.NewSchemaKey(dbID, scName))
+}
+
+// getSchemaDescByName returns the descriptor of the user-defined schema with
+// the given name in the database with ID dbID, or nil if there is no such
+// schema.
+func getSchemaDescByName(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (*sqlbase.SchemaDescriptor, error) {
+	if scName == tree.PublicSchema || isTemporarySchemaRef(scName) {
+		return nil, nil
+	}
+	id, err := getDescriptorID(ctx, txn, sqlbase.NewSchemaKey(dbID, scName))
+	if err != nil || id == sqlbase.InvalidID {
+		return nil, err
+	}
+	desc := &sqlbase.Descriptor{}
+	if err := txn.GetProto(ctx, sqlbase.MakeDescMetadataKey(id), desc); err != nil {
+		return nil, err
+	}
+	// The name may belong to a table or a type instead.
+	schema := desc.GetSchema()
+	if schema == nil {
+		return nil, nil
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// isVirtualSchemaName returns true if scName is the name of one of the
+// virtual schemas, which are present in every database.
+func isVirtualSchemaName(scName string) bool {
+	switch scName {
+	case informationSchemaName, pgCatalogName, crdbInternalName:
+		return true
+	}
+	return false
+}
+
+// checkSchemaCreatePrivilege checks that the current user can create
+// objects in the non-temporary schema scName of dbDesc. This requires the
+// CREATE privilege on the schema, which for the public schema is the CREATE
+// privilege on the database.
+func (p *planner) checkSchemaCreatePrivilege(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor, scName string,
+) error {
+	scDesc, err := getSchemaDescByName(ctx, p.txn, dbDesc.ID, scName)
+	if err != nil {
+		return err
+	}
+	if scDesc != nil {
+		return p.CheckPrivilege(ctx, scDesc, privilege.CREATE)
+	}
+	return p.CheckPrivilege(ctx, dbDesc, privilege.CREATE)
+}
+
+// schemaForCreate returns the namespace parent ID for a new object in the
+// non-temporary schema scName of dbDesc, along with the privileges that the
+// object inherits: those of the schema, or of the database for the public
+// schema.
+func (p *planner) schemaForCreate(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor, scName string,
+) (sqlbase.ID, *sqlbase.PrivilegeDescriptor, error) {
+	if scName == tree.PublicSchema {
+		return dbDesc.ID, dbDesc.GetPrivileges(), nil
+	}
+	scDesc, err := getSchemaDescByName(ctx, p.txn, dbDesc.ID, scName)
+	if err != nil {
+		return sqlbase.InvalidID, nil, err
+	}
+	if scDesc == nil {
+		return sqlbase.InvalidID, nil, sqlbase.NewUndefinedSchemaError(scName)
+	}
+	return scDesc.ID, scDesc.GetPrivileges(), nil
+}
+
+// getUserSchemaDescs returns the descriptors of the user-defined schemas of
+// the database with ID dbID, keyed by name.
+func (p *planner) getUserSchemaDescs(
+	ctx context.Context, dbID sqlbase.ID,
+) (map[string]*sqlbase.SchemaDescriptor, error) {
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*sqlbase.SchemaDescriptor)
+	for _, desc := range descs {
+		if scDesc, ok := desc.(*sqlbase.SchemaDescriptor); ok && scDesc.ParentID == dbID {
+			res[scDesc.Name] = scDesc
+		}
+	}
+	return res, nil
+}
@@ -429,7 +429,7 @@ func (sc *SchemaChanger) truncateTable(
 	// If DropTime isn't set, assume this drop request is from a version
 	// 1.1 server and invoke legacy code that uses DeleteRange and range GC.
 	if table.DropTime == 0 {
-		return truncateTableInChunks(ctx, table, sc.db, false /* traceKV */)
+		return truncateTableInChunks(ctx, &evalCtx.EvalContext, table, sc.db, false /* traceKV */)
 	}
 
 	tableKey := roachpb.RKey(keys.MakeTablePrefix(uint32(table.ID)))
@@ -1659,9 +1659,12 @@ type SchemaChangeManager struct {
 	schemaChangers map[sqlbase.ID]SchemaChanger
 	// Create a schema changer for every table that is dropped or has
 	// dropped indexes that needs to be GC-ed.
-	forGC          map[sqlbase.ID]SchemaChanger
-	distSQLPlanner *DistSQLPlanner
-	ieFactory      sqlutil.SessionBoundInternalExecutorFactory
+	forGC map[sqlbase.ID]SchemaChanger
+	// Create a type schema changer for every enum type that has members
+	// being added.
+	typeSchemaChangers map[sqlbase.ID]typeSchemaChanger
+	distSQLPlanner     *DistSQLPlanner
+	ieFactory          sqlutil.SessionBoundInternalExecutorFactory
 }
 
 // NewSchemaChangeManager returns a new SchemaChangeManager.
@@ -1682,6 +1685,8 @@ func NewSchemaChangeManager(
 		forGC:          make(map[sqlbase.ID]SchemaChanger),
 		distSQLPlanner: dsp,
 		ieFactory:      ieFactory,
+
+		typeSchemaChangers: make(map[sqlbase.ID]typeSchemaChanger),
 	}
 }
 
@@ -1702,6 +1707,22 @@ func (s *SchemaChangeManager) newTimer(changers map[sqlbase.ID]SchemaChanger) *t
 	return time.NewTimer(waitDuration)
 }
 
+// newTypeTimer is like newTimer, for type schema changers.
+func (s *SchemaChangeManager) newTypeTimer() *time.Timer {
+	if len(s.typeSchemaChangers) == 0 {
+		return &time.Timer{}
+	}
+	waitDuration := time.Duration(math.MaxInt64)
+	now := timeutil.Now()
+	for _, sc := range s.typeSchemaChangers {
+		d := sc.execAfter.Sub(now)
+		if d < waitDuration {
+			waitDuration = d
+		}
+	}
+	return time.NewTimer(waitDuration)
+}
+
 // Start starts a goroutine that runs outstanding schema changes
 // for tables received in the latest system configuration via gossip.
 func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
@@ -1714,6 +1735,7 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 		gossipUpdateC := s.execCfg.Gossip.RegisterSystemConfigChannel()
 		timer := &time.Timer{}
 		gcTimer := &time.Timer{}
+		typeTimer := &time.Timer{}
 		// A jitter is added to reduce contention between nodes
 		// attempting to run the schema change.
 		delay := time.Duration(float64(asyncSchemaChangeDelay) * (0.9 + 0.2*rand.Float64()))
@@ -1757,6 +1779,29 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 			}
 		}
 
+		execOneTypeSchemaChange := func() {
+			for typeID, sc := range s.typeSchemaChangers {
+				if timeutil.Since(sc.execAfter) > 0 {
+					execCtx, cleanup := tracing.EnsureContext(ctx, s.ambientCtx.Tracer, "type schema change [async]")
+					err := sc.exec(execCtx)
+					cleanup()
+
+					if err != nil && err != sqlbase.ErrDescriptorNotFound {
+						log.Warningf(ctx, "Error executing type schema change: %s", err)
+						// Advance the execAfter time so that this schema
+						// changer doesn't get called again for a while.
+						sc.execAfter = timeutil.Now().Add(delay)
+						s.typeSchemaChangers[typeID] = sc
+					} else {
+						delete(s.typeSchemaChangers, typeID)
+					}
+
+					// Only attempt to run one schema changer.
+					break
+				}
+			}
+		}
+
 		for {
 			select {
 			case <-gossipUpdateC:
@@ -1964,12 +2009,29 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 
 					case *sqlbase.Descriptor_Database:
 						// Ignore.
+
+					case *sqlbase.Descriptor_Type:
+						typ := union.Type
+						if typ.HasReadOnlyMembers() {
+							if log.V(2) {
+								log.Infof(ctx, "%s: queue up pending type schema change; type: %d, version: %d",
+									kv.Key, typ.ID, typ.Version)
+							}
+							s.typeSchemaChangers[typ.ID] = typeSchemaChanger{
+								typeID:    typ.ID,
+								execCfg:   s.execCfg,
+								execAfter: execAfter,
+							}
+						} else {
+							delete(s.typeSchemaChangers, typ.ID)
+						}
 					}
 				})
 
 				if resetTimer {
 					timer = s.newTimer(s.schemaChangers)
 					gcTimer = s.newTimer(s.forGC)
+					typeTimer = s.newTypeTimer()
 				}
 
 			case <-timer.C:
@@ -1994,6 +2056,11 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 
 				gcTimer = s.newTimer(s.forGC)
 
+			case <-typeTimer.C:
+				execOneTypeSchemaChange()
+
+				typeTimer = s.newTypeTimer()
+
 			case <-stopper.ShouldStop():
 				return
 			}
@@ -37,6 +37,7 @@ func init() {
 	initWindowBuiltins()
 	initGeneratorBuiltins()
 	initPGBuiltins()
+	initGeoBuiltins()
 
 	AllBuiltinNames = make([]string, 0, len(builtins))
 	AllAggregateBuiltinNames = make([]string, 0, len(aggregates))
@@ -51,6 +51,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/knz/strtime"
@@ -1690,6 +1691,20 @@ CockroachDB supports the following flags:
 		},
 	),
 
+	"current_time": makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.TimeTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				t := ctx.GetTxnTimestamp(time.Microsecond).Time
+				return tree.MakeDTimeTZFromTime(t.In(ctx.GetLocation())), nil
+			},
+			Info: "Returns the time of day of the current transaction, along with the " +
+				"offset of the session time zone." + txnTSContextDoc,
+		},
+	),
+
 	"now":                   txnTSImpl,
 	"current_timestamp":     txnTSImpl,
 	"transaction_timestamp": txnTSImpl,
@@ -1976,6 +1991,114 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	// timezone converts between time zones, like the AT TIME ZONE operator
+	// (which is rewritten into a call to this function by the parser). As in
+	// PostgreSQL, the zone is either a time zone name or an interval giving a
+	// fixed offset east of UTC.
+	"timezone": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryDateAndTime,
+			// The offset of a named time zone for a TIMETZ depends on the
+			// transaction timestamp.
+			Impure: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timestamp", types.Timestamp}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneStringToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return timestampAtTimeZone(args[1].(*tree.DTimestamp), loc), nil
+			},
+			Info: "Treats `timestamp` as a local time in the time zone `timezone` and " +
+				"returns the corresponding timestamp with time zone.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timestamptz", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.Timestamp),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneStringToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return timestampTZAtTimeZone(args[1].(*tree.DTimestampTZ), loc), nil
+			},
+			Info: "Converts `timestamptz` to the local time in the time zone `timezone`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"time", types.Time}},
+			ReturnType: tree.FixedReturnType(types.TimeTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneStringToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				// Like PostgreSQL, treat the time as a local time in the session
+				// time zone.
+				t, err := tree.PerformCast(ctx, args[1], types.TimeTZ)
+				if err != nil {
+					return nil, err
+				}
+				return timeTZAtTimeZone(ctx, t.(*tree.DTimeTZ), loc), nil
+			},
+			Info: "Converts `time`, taken to be in the session time zone, to the time " +
+				"zone `timezone`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timetz", types.TimeTZ}},
+			ReturnType: tree.FixedReturnType(types.TimeTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneStringToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return timeTZAtTimeZone(ctx, args[1].(*tree.DTimeTZ), loc), nil
+			},
+			Info: "Converts `timetz` to the time zone `timezone`, using the offset in " +
+				"effect in that time zone at the current transaction timestamp.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timestamp", types.Timestamp}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return timestampAtTimeZone(args[1].(*tree.DTimestamp), loc), nil
+			},
+			Info: "Treats `timestamp` as a local time at the offset `timezone` east of " +
+				"UTC and returns the corresponding timestamp with time zone.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timestamptz", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.Timestamp),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return timestampTZAtTimeZone(args[1].(*tree.DTimestampTZ), loc), nil
+			},
+			Info: "Converts `timestamptz` to the local time at the offset `timezone` " +
+				"east of UTC.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timetz", types.TimeTZ}},
+			ReturnType: tree.FixedReturnType(types.TimeTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return timeTZAtTimeZone(ctx, args[1].(*tree.DTimeTZ), loc), nil
+			},
+			Info: "Converts `timetz` to the offset `timezone` east of UTC.",
+		},
+	),
+
 	// Math functions
 	"abs": makeBuiltin(defProps(),
 		floatOverload1(func(x float64) (tree.Datum, error) {
@@ -2391,9 +2514,7 @@ may increase either contention or retry errors, or both.`,
 				dimen := int64(tree.MustBeDInt(args[1]))
 				return arrayLength(arr, dimen), nil
 			},
-			Info: "Calculates the length of `input` on the provided `array_dimension`. However, " +
-				"because CockroachDB doesn't yet support multi-dimensional arrays, the only supported" +
-				" `array_dimension` is **1**.",
+			Info: "Calculates the length of `input` on the provided `array_dimension`.",
 		},
 	),
 
@@ -2406,9 +2527,7 @@ may increase either contention or retry errors, or both.`,
 				dimen := int64(tree.MustBeDInt(args[1]))
 				return arrayLower(arr, dimen), nil
 			},
-			Info: "Calculates the minimum value of `input` on the provided `array_dimension`. " +
-				"However, because CockroachDB doesn't yet support multi-dimensional arrays, the only " +
-				"supported `array_dimension` is **1**.",
+			Info: "Calculates the minimum value of `input` on the provided `array_dimension`.",
 		},
 	),
 
@@ -2421,9 +2540,41 @@ may increase either contention or retry errors, or both.`,
 				dimen := int64(tree.MustBeDInt(args[1]))
 				return arrayLength(arr, dimen), nil
 			},
-			Info: "Calculates the maximum value of `input` on the provided `array_dimension`. " +
-				"However, because CockroachDB doesn't yet support multi-dimensional arrays, the only " +
-				"supported `array_dimension` is **1**.",
+			Info: "Calculates the maximum value of `input` on the provided `array_dimension`.",
+		},
+	),
+
+	"array_ndims": makeBuiltin(arrayProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.AnyArray}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				dims := tree.MustBeDArray(args[0]).Dimensions()
+				if len(dims) == 0 {
+					return tree.DNull, nil
+				}
+				return tree.NewDInt(tree.DInt(len(dims))), nil
+			},
+			Info: "Returns the number of dimensions of `input`.",
+		},
+	),
+
+	"array_dims": makeBuiltin(arrayProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.AnyArray}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				dims := tree.MustBeDArray(args[0]).Dimensions()
+				if len(dims) == 0 {
+					return tree.DNull, nil
+				}
+				var buf bytes.Buffer
+				for _, dim := range dims {
+					fmt.Fprintf(&buf, "[1:%d]", dim)
+				}
+				return tree.NewDString(buf.String()), nil
+			},
+			Info: "Returns a text representation of the dimensions of `input`, such as `[1:2][1:3]`.",
 		},
 	),
 
@@ -3243,6 +3394,77 @@ var txnTSImpl = makeBuiltin(
 	},
 )
 
+// timeZoneStringToLocation loads the time zone with the given name. Like in
+// PostgreSQL, names are case insensitive: the lookup falls back to upper case
+// and to capitalizing each word, so that, for example, both utc and
+// america/new_york are found.
+func timeZoneStringToLocation(s string) (*time.Location, error) {
+	loc, err := timeutil.TimeZoneStringToLocation(s)
+	if err == nil {
+		return loc, nil
+	}
+	if loc, err1 := timeutil.LoadLocation(strings.ToUpper(s)); err1 == nil {
+		return loc, nil
+	}
+	if loc, err1 := timeutil.LoadLocation(capitalizeTimeZoneName(s)); err1 == nil {
+		return loc, nil
+	}
+	return nil, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+		"time zone %q not recognized", s)
+}
+
+// capitalizeTimeZoneName capitalizes each word of a time zone name, where
+// words are separated by slashes or underscores, e.g. america/new_york becomes
+// America/New_York.
+func capitalizeTimeZoneName(s string) string {
+	b := []byte(strings.ToLower(s))
+	for i := range b {
+		if (i == 0 || b[i-1] == '/' || b[i-1] == '_') && b[i] >= 'a' && b[i] <= 'z' {
+			b[i] -= 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// timeZoneIntervalToLocation returns a time zone with a fixed offset east of
+// UTC given by the interval, which must not have months or days.
+func timeZoneIntervalToLocation(d *tree.DInterval) (*time.Location, error) {
+	if d.Months != 0 || d.Days != 0 {
+		return nil, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"interval time zone %q must not include months or days", d.Duration.String())
+	}
+	offset := d.Nanos() / int64(time.Second)
+	if offset < int64(timetz.MinOffsetSecs) || offset > int64(timetz.MaxOffsetSecs) {
+		return nil, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"interval time zone %q out of range", d.Duration.String())
+	}
+	return timeutil.FixedOffsetTimeZoneToLocation(int(offset), d.Duration.String()), nil
+}
+
+// timestampAtTimeZone returns the timestamp with time zone for the local time
+// ts in the time zone loc.
+func timestampAtTimeZone(ts *tree.DTimestamp, loc *time.Location) tree.Datum {
+	t := ts.Time
+	return tree.MakeDTimestampTZ(time.Date(
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc,
+	).UTC(), time.Microsecond)
+}
+
+// timestampTZAtTimeZone returns the local time of ts in the time zone loc.
+func timestampTZAtTimeZone(ts *tree.DTimestampTZ, loc *time.Location) tree.Datum {
+	t := ts.Time.In(loc)
+	return tree.MakeDTimestamp(time.Date(
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC,
+	), time.Microsecond)
+}
+
+// timeTZAtTimeZone converts t to the offset of the time zone loc at the
+// transaction timestamp.
+func timeTZAtTimeZone(ctx *tree.EvalContext, t *tree.DTimeTZ, loc *time.Location) tree.Datum {
+	_, offset := ctx.GetTxnTimestamp(time.Microsecond).Time.In(loc).Zone()
+	return tree.MakeDTimeTZ(t.WithOffset(-int32(offset)))
+}
+
 func currentDate(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
 	t := ctx.GetTxnTimestamp(time.Microsecond).Time
 	t = t.In(ctx.GetLocation())
@@ -4510,7 +4732,7 @@ func asJSONBuildObjectKey(d tree.Datum) (string, error) {
 		return t.Contents, nil
 	case *tree.DBool, *tree.DInt, *tree.DFloat, *tree.DDecimal, *tree.DTimestamp, *tree.DTimestampTZ,
 		*tree.DDate, *tree.DUuid, *tree.DInterval, *tree.DBytes, *tree.DIPAddr, *tree.DOid,
-		*tree.DTime, *tree.DBitArray:
+		*tree.DTime, *tree.DTimeTZ, *tree.DBitArray:
 		return tree.AsStringWithFlags(d, tree.FmtBareStrings), nil
 	default:
 		return "", pgerror.AssertionFailedf("unexpected type %T for key value", d)
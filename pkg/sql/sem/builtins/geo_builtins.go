@@ -0,0 +1,479 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package builtins
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+const categorySpatial = "Spatial"
+
+// maxSRID is the largest SRID accepted by the spatial builtins, matching
+// PostGIS.
+const maxSRID = 999999
+
+// initGeoBuiltins adds all of the spatial builtins to the Builtins map.
+func initGeoBuiltins() {
+	for k, v := range geoBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		v.props.Category = categorySpatial
+		builtins[k] = v
+	}
+}
+
+var geoBuiltins = map[string]builtinDefinition{
+	// Constructors.
+
+	"st_geomfromtext": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return geometryFromText(string(tree.MustBeDString(args[0])))
+			},
+			Info: "Returns the geometry represented by the given WKT or EWKT.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}, {"srid", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geometryFromText(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return setGeometrySRID(g.Geometry, args[1])
+			},
+			Info: "Returns the geometry represented by the given WKT, with the given SRID.",
+		},
+	),
+
+	"st_geomfromwkb": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"bytes", types.Bytes}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return geometryFromWKB([]byte(*args[0].(*tree.DBytes)))
+			},
+			Info: "Returns the geometry represented by the given WKB or EWKB.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"bytes", types.Bytes}, {"srid", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geometryFromWKB([]byte(*args[0].(*tree.DBytes)))
+				if err != nil {
+					return nil, err
+				}
+				return setGeometrySRID(g.Geometry, args[1])
+			},
+			Info: "Returns the geometry represented by the given WKB, with the given SRID.",
+		},
+	),
+
+	"st_geomfromgeojson": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return geometryFromGeoJSON(string(tree.MustBeDString(args[0])))
+			},
+			Info: "Returns the geometry represented by the given GeoJSON geometry object.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.Jsonb}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return geometryFromGeoJSON(tree.MustBeDJSON(args[0]).JSON.String())
+			},
+			Info: "Returns the geometry represented by the given GeoJSON geometry object.",
+		},
+	),
+
+	"st_geogfromtext": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geography),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.ParseGeographyFromEWKT(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, pgerror.Wrapf(err, pgerror.CodeInvalidParameterValueError,
+						"could not parse geography")
+				}
+				return tree.NewDGeography(g), nil
+			},
+			Info: "Returns the geography represented by the given WKT or EWKT. " +
+				"Coordinates are longitudes and latitudes in degrees.",
+		},
+	),
+
+	"st_geogfromwkb": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"bytes", types.Bytes}},
+			ReturnType: tree.FixedReturnType(types.Geography),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.ParseGeographyFromEWKB([]byte(*args[0].(*tree.DBytes)))
+				if err != nil {
+					return nil, pgerror.Wrapf(err, pgerror.CodeInvalidBinaryRepresentationError,
+						"could not parse geography")
+				}
+				return tree.NewDGeography(g), nil
+			},
+			Info: "Returns the geography represented by the given WKB or EWKB. " +
+				"Coordinates are longitudes and latitudes in degrees.",
+		},
+	),
+
+	"st_makepoint": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"x", types.Float}, {"y", types.Float}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				x, y := float64(*args[0].(*tree.DFloat)), float64(*args[1].(*tree.DFloat))
+				if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+					return nil, pgerror.New(pgerror.CodeInvalidParameterValueError,
+						"point coordinates must be finite")
+				}
+				return tree.NewDGeometry(geo.Geometry{Shape: geo.Point{Coord: geo.Coord{X: x, Y: y}}}), nil
+			},
+			Info: "Returns a point geometry with the given coordinates and an unknown SRID.",
+		},
+	),
+
+	"st_setsrid": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}, {"srid", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return setGeometrySRID(tree.MustBeDGeometry(args[0]).Geometry, args[1])
+			},
+			Info: "Returns the geometry with its SRID set to the given value, without " +
+				"transforming its coordinates.",
+		},
+	),
+
+	// Output.
+
+	"st_astext": makeBuiltin(defProps(),
+		geometryOverload1(types.String, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDString(g.WKT()), nil
+		}, "Returns the WKT representation of the geometry."),
+		geographyOverload1(types.String, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDString(g.WKT()), nil
+		}, "Returns the WKT representation of the geography."),
+	),
+
+	"st_asewkt": makeBuiltin(defProps(),
+		geometryOverload1(types.String, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDString(g.EWKT()), nil
+		}, "Returns the EWKT representation of the geometry."),
+		geographyOverload1(types.String, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDString(g.EWKT()), nil
+		}, "Returns the EWKT representation of the geography."),
+	),
+
+	"st_asbinary": makeBuiltin(defProps(),
+		geometryOverload1(types.Bytes, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDBytes(tree.DBytes(g.WKB())), nil
+		}, "Returns the WKB representation of the geometry."),
+		geographyOverload1(types.Bytes, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDBytes(tree.DBytes(g.WKB())), nil
+		}, "Returns the WKB representation of the geography."),
+	),
+
+	"st_asewkb": makeBuiltin(defProps(),
+		geometryOverload1(types.Bytes, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDBytes(tree.DBytes(g.EWKB())), nil
+		}, "Returns the EWKB representation of the geometry."),
+		geographyOverload1(types.Bytes, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDBytes(tree.DBytes(g.EWKB())), nil
+		}, "Returns the EWKB representation of the geography."),
+	),
+
+	"st_asgeojson": makeBuiltin(defProps(),
+		geometryOverload1(types.String, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDString(g.GeoJSON(geo.DefaultGeoJSONDecimalDigits)), nil
+		}, "Returns the GeoJSON representation of the geometry, with coordinates "+
+			"rounded to 9 decimal digits."),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}, {"max_decimal_digits", types.Int}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				digits, err := geoJSONDecimalDigits(args[1])
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(tree.MustBeDGeometry(args[0]).GeoJSON(digits)), nil
+			},
+			Info: "Returns the GeoJSON representation of the geometry, with coordinates " +
+				"rounded to the given number of decimal digits.",
+		},
+		geographyOverload1(types.String, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDString(g.GeoJSON(geo.DefaultGeoJSONDecimalDigits)), nil
+		}, "Returns the GeoJSON representation of the geography, with coordinates "+
+			"rounded to 9 decimal digits."),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography", types.Geography}, {"max_decimal_digits", types.Int}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				digits, err := geoJSONDecimalDigits(args[1])
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(tree.MustBeDGeography(args[0]).GeoJSON(digits)), nil
+			},
+			Info: "Returns the GeoJSON representation of the geography, with coordinates " +
+				"rounded to the given number of decimal digits.",
+		},
+	),
+
+	// Accessors.
+
+	"st_srid": makeBuiltin(defProps(),
+		geometryOverload1(types.Int, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDInt(tree.DInt(g.SRID)), nil
+		}, "Returns the SRID of the geometry, or 0 if it is unknown."),
+		geographyOverload1(types.Int, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDInt(tree.DInt(g.SRID)), nil
+		}, "Returns the SRID of the geography."),
+	),
+
+	"st_x": makeBuiltin(defProps(),
+		geometryOverload1(types.Float, func(g geo.Geometry) (tree.Datum, error) {
+			return pointCoord(g, func(c geo.Coord) float64 { return c.X })
+		}, "Returns the X coordinate of the point geometry, or NULL if it is empty."),
+	),
+
+	"st_y": makeBuiltin(defProps(),
+		geometryOverload1(types.Float, func(g geo.Geometry) (tree.Datum, error) {
+			return pointCoord(g, func(c geo.Coord) float64 { return c.Y })
+		}, "Returns the Y coordinate of the point geometry, or NULL if it is empty."),
+	),
+
+	"st_geometrytype": makeBuiltin(defProps(),
+		geometryOverload1(types.String, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDString("ST_" + g.Shape.Type().String()), nil
+		}, "Returns the type of the geometry as a string prefixed with ST_, e.g. ST_LineString."),
+	),
+
+	"st_npoints": makeBuiltin(defProps(),
+		geometryOverload1(types.Int, func(g geo.Geometry) (tree.Datum, error) {
+			return tree.NewDInt(tree.DInt(g.NumPoints())), nil
+		}, "Returns the number of points in the geometry."),
+		geographyOverload1(types.Int, func(g geo.Geography) (tree.Datum, error) {
+			return tree.NewDInt(tree.DInt(g.NumPoints())), nil
+		}, "Returns the number of points in the geography."),
+	),
+
+	// Measures and predicates.
+
+	"st_distance": makeBuiltin(defProps(),
+		geometryOverload2(types.Float, func(a, b geo.Geometry) (tree.Datum, error) {
+			return distanceDatum(geo.GeometryDistance(a, b))
+		}, "Returns the minimum planar distance between the two geometries, or NULL if "+
+			"either is empty."),
+		geographyOverload2(types.Float, func(a, b geo.Geography) (tree.Datum, error) {
+			return distanceDatum(geo.GeographyDistance(a, b))
+		}, "Returns the minimum distance in meters between the two geographies on a "+
+			"spherical model of the earth, or NULL if either is empty."),
+	),
+
+	"st_dwithin": makeBuiltin(defProps(),
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"geometry_a", types.Geometry}, {"geometry_b", types.Geometry}, {"distance", types.Float},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				within, err := geo.GeometryDWithin(
+					tree.MustBeDGeometry(args[0]).Geometry, tree.MustBeDGeometry(args[1]).Geometry,
+					float64(*args[2].(*tree.DFloat)),
+				)
+				return tree.MakeDBool(tree.DBool(within)), err
+			},
+			Info: "Returns whether the two geometries are within the given planar distance " +
+				"of each other. This function can use a spatial inverted index.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"geography_a", types.Geography}, {"geography_b", types.Geography}, {"distance_meters", types.Float},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				within, err := geo.GeographyDWithin(
+					tree.MustBeDGeography(args[0]).Geography, tree.MustBeDGeography(args[1]).Geography,
+					float64(*args[2].(*tree.DFloat)),
+				)
+				return tree.MakeDBool(tree.DBool(within)), err
+			},
+			Info: "Returns whether the two geographies are within the given distance in " +
+				"meters of each other. This function can use a spatial inverted index.",
+		},
+	),
+
+	"st_intersects": makeBuiltin(defProps(),
+		geometryOverload2(types.Bool, func(a, b geo.Geometry) (tree.Datum, error) {
+			intersects, err := geo.GeometryIntersects(a, b)
+			return tree.MakeDBool(tree.DBool(intersects)), err
+		}, "Returns whether the two geometries share any point. This function can use a "+
+			"spatial inverted index."),
+		geographyOverload2(types.Bool, func(a, b geo.Geography) (tree.Datum, error) {
+			intersects, err := geo.GeographyIntersects(a, b)
+			return tree.MakeDBool(tree.DBool(intersects)), err
+		}, "Returns whether the two geographies share any point. This function can use a "+
+			"spatial inverted index."),
+	),
+
+	"st_contains": makeBuiltin(defProps(),
+		geometryOverload2(types.Bool, func(a, b geo.Geometry) (tree.Datum, error) {
+			contains, err := geo.GeometryContains(a, b)
+			return tree.MakeDBool(tree.DBool(contains)), err
+		}, "Returns whether no point of geometry_b lies outside geometry_a and at least "+
+			"one point of the interior of geometry_b lies in the interior of geometry_a. "+
+			"This function can use a spatial inverted index."),
+	),
+
+	"st_within": makeBuiltin(defProps(),
+		geometryOverload2(types.Bool, func(a, b geo.Geometry) (tree.Datum, error) {
+			contains, err := geo.GeometryContains(b, a)
+			return tree.MakeDBool(tree.DBool(contains)), err
+		}, "Returns whether geometry_a is within geometry_b, that is, whether "+
+			"geometry_b contains geometry_a. This function can use a spatial inverted index."),
+	),
+}
+
+func geometryOverload1(
+	returnType *types.T, fn func(geo.Geometry) (tree.Datum, error), info string,
+) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+		ReturnType: tree.FixedReturnType(returnType),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			return fn(tree.MustBeDGeometry(args[0]).Geometry)
+		},
+		Info: info,
+	}
+}
+
+func geographyOverload1(
+	returnType *types.T, fn func(geo.Geography) (tree.Datum, error), info string,
+) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"geography", types.Geography}},
+		ReturnType: tree.FixedReturnType(returnType),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			return fn(tree.MustBeDGeography(args[0]).Geography)
+		},
+		Info: info,
+	}
+}
+
+func geometryOverload2(
+	returnType *types.T, fn func(a, b geo.Geometry) (tree.Datum, error), info string,
+) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"geometry_a", types.Geometry}, {"geometry_b", types.Geometry}},
+		ReturnType: tree.FixedReturnType(returnType),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			return fn(tree.MustBeDGeometry(args[0]).Geometry, tree.MustBeDGeometry(args[1]).Geometry)
+		},
+		Info: info,
+	}
+}
+
+func geographyOverload2(
+	returnType *types.T, fn func(a, b geo.Geography) (tree.Datum, error), info string,
+) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"geography_a", types.Geography}, {"geography_b", types.Geography}},
+		ReturnType: tree.FixedReturnType(returnType),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			return fn(tree.MustBeDGeography(args[0]).Geography, tree.MustBeDGeography(args[1]).Geography)
+		},
+		Info: info,
+	}
+}
+
+func geometryFromText(s string) (*tree.DGeometry, error) {
+	g, err := geo.ParseGeometryFromEWKT(s)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgerror.CodeInvalidParameterValueError, "could not parse geometry")
+	}
+	return tree.NewDGeometry(g), nil
+}
+
+func geometryFromWKB(b []byte) (*tree.DGeometry, error) {
+	g, err := geo.ParseGeometryFromEWKB(b)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgerror.CodeInvalidBinaryRepresentationError, "could not parse geometry")
+	}
+	return tree.NewDGeometry(g), nil
+}
+
+func geometryFromGeoJSON(s string) (*tree.DGeometry, error) {
+	g, err := geo.ParseGeometryFromGeoJSON([]byte(s))
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgerror.CodeInvalidParameterValueError, "could not parse geometry")
+	}
+	return tree.NewDGeometry(g), nil
+}
+
+func setGeometrySRID(g geo.Geometry, sridArg tree.Datum) (*tree.DGeometry, error) {
+	srid := int64(tree.MustBeDInt(sridArg))
+	if srid < 0 || srid > maxSRID {
+		return nil, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"SRID %d must be between 0 and %d", srid, maxSRID)
+	}
+	g.SRID = int32(srid)
+	return tree.NewDGeometry(g), nil
+}
+
+func geoJSONDecimalDigits(d tree.Datum) (int, error) {
+	digits := int64(tree.MustBeDInt(d))
+	// Float64 values have at most 17 significant decimal digits, so there is
+	// no point in allowing more fractional digits than that.
+	if digits < 0 || digits > 17 {
+		return 0, pgerror.New(pgerror.CodeInvalidParameterValueError,
+			"max_decimal_digits must be between 0 and 17")
+	}
+	return int(digits), nil
+}
+
+func pointCoord(g geo.Geometry, coord func(geo.Coord) float64) (tree.Datum, error) {
+	p, ok := g.Shape.(geo.Point)
+	if !ok {
+		return nil, pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+			"argument to st_x() and st_y() must be a point, not %s", g.Shape.Type())
+	}
+	if p.Empty {
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(coord(p.Coord))), nil
+}
+
+func distanceDatum(distance float64, err error) (tree.Datum, error) {
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(distance, 1) {
+		// One of the arguments is empty.
+		return tree.DNull, nil
+	}
+	return tree.NewDFloat(tree.DFloat(distance)), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package tree
+
+// AlterType represents an ALTER TYPE statement.
+type AlterType struct {
+	Type *UnresolvedObjectName
+	Cmd  AlterTypeCmd
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterType) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER TYPE ")
+	ctx.FormatNode(node.Type)
+	ctx.FormatNode(node.Cmd)
+}
+
+// AlterTypeCmd represents a type modification operation.
+type AlterTypeCmd interface {
+	NodeFormatter
+	alterTypeCmd()
+}
+
+func (*AlterTypeAddValue) alterTypeCmd() {}
+
+var _ AlterTypeCmd = &AlterTypeAddValue{}
+
+// AlterTypeAddValue represents an ALTER TYPE ADD VALUE command.
+type AlterTypeAddValue struct {
+	NewVal      EnumValue
+	IfNotExists bool
+	Placement   *AlterTypeAddValuePlacement
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAddValue) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ADD VALUE ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.NewVal)
+	if node.Placement != nil {
+		if node.Placement.Before {
+			ctx.WriteString(" BEFORE ")
+		} else {
+			ctx.WriteString(" AFTER ")
+		}
+		ctx.FormatNode(&node.Placement.ExistingVal)
+	}
+}
+
+// AlterTypeAddValuePlacement represents the placement clause for an ALTER
+// TYPE ADD VALUE command ([BEFORE | AFTER] value).
+type AlterTypeAddValuePlacement struct {
+	Before      bool
+	ExistingVal EnumValue
+}
@@ -17,6 +17,10 @@ type CreateChangefeed struct {
 	Targets TargetList
 	SinkURI Expr
 	Options KVOptions
+	// Select, if set, is the restricted SELECT of a CREATE CHANGEFEED ... AS
+	// SELECT statement. The table it reads from is then the only target and
+	// Targets is empty.
+	Select *Select
 }
 
 var _ Statement = &CreateChangefeed{}
@@ -30,8 +34,11 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 		// prefix. They're also still EXPERIMENTAL, so they get marked as such.
 		ctx.WriteString("EXPERIMENTAL ")
 	}
-	ctx.WriteString("CHANGEFEED FOR ")
-	ctx.FormatNode(&node.Targets)
+	ctx.WriteString("CHANGEFEED")
+	if node.Select == nil {
+		ctx.WriteString(" FOR ")
+		ctx.FormatNode(&node.Targets)
+	}
 	if node.SinkURI != nil {
 		ctx.WriteString(" INTO ")
 		ctx.FormatNode(node.SinkURI)
@@ -40,4 +47,8 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
 	}
+	if node.Select != nil {
+		ctx.WriteString(" AS ")
+		ctx.FormatNode(node.Select)
+	}
 }
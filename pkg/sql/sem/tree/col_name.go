@@ -13,6 +13,7 @@
 package tree
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -69,6 +70,13 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 	case *FuncExpr:
 		fd, err := e.Func.Resolve(sp)
 		if err != nil {
+			// The name may refer to a user-defined function, which cannot be
+			// resolved here. Unknown functions are reported by type checking.
+			if name, ok := e.Func.FunctionReference.(*UnresolvedName); ok {
+				if pgErr, ok := pgerror.GetPGCause(err); ok && pgErr.Code == pgerror.CodeUndefinedFunctionError {
+					return 2, name.Parts[0], nil
+				}
+			}
 			return 0, "", err
 		}
 		return 2, fd.Name, nil
@@ -143,6 +151,9 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 	case *CoalesceExpr:
 		return 2, "coalesce", nil
 
+	case *GroupingExpr:
+		return 2, "grouping", nil
+
 		// CockroachDB-specific nodes follow.
 	case *IfErrExpr:
 		if e.Else == nil {
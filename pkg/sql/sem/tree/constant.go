@@ -58,6 +58,15 @@ func isConstant(expr Expr) bool {
 }
 
 func typeCheckConstant(c Constant, ctx *SemaContext, desired *types.T) (ret TypedExpr, err error) {
+	// String literals can become values of any enum type. Enum types are not
+	// part of the available types of string literals, since those only contain
+	// types that can be resolved without further information.
+	if desired.Family() == types.EnumFamily && desired.EnumData() != nil {
+		if s, ok := c.(*StrVal); ok && !s.scannedAsBytes {
+			return s.ResolveAsType(ctx, desired)
+		}
+	}
+
 	avail := c.AvailableTypes()
 	if desired.Family() != types.AnyFamily {
 		for _, typ := range avail {
@@ -406,6 +415,7 @@ var (
 		types.Decimal,
 		types.Date,
 		types.Time,
+		types.TimeTZ,
 		types.Timestamp,
 		types.TimestampTZ,
 		types.Interval,
@@ -413,6 +423,8 @@ var (
 		types.INet,
 		types.Jsonb,
 		types.VarBit,
+		types.Geometry,
+		types.Geography,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []*types.T{types.Bytes, types.Uuid, types.String}
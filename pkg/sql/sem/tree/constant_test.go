@@ -210,6 +210,13 @@ func mustParseDTime(t *testing.T, s string) tree.Datum {
 	}
 	return d
 }
+func mustParseDTimeTZ(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDTimeTZ(nil, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
 func mustParseDTimestamp(t *testing.T, s string) tree.Datum {
 	d, err := tree.ParseDTimestamp(nil, s, time.Millisecond)
 	if err != nil {
@@ -239,16 +246,34 @@ func mustParseDJSON(t *testing.T, s string) tree.Datum {
 	return d
 }
 
+func mustParseDGeometry(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDGeometry(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+func mustParseDGeography(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDGeography(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
 var parseFuncs = map[*types.T]func(*testing.T, string) tree.Datum{
 	types.String:      func(t *testing.T, s string) tree.Datum { return tree.NewDString(s) },
 	types.Bytes:       func(t *testing.T, s string) tree.Datum { return tree.NewDBytes(tree.DBytes(s)) },
 	types.Bool:        mustParseDBool,
 	types.Date:        mustParseDDate,
 	types.Time:        mustParseDTime,
+	types.TimeTZ:      mustParseDTimeTZ,
 	types.Timestamp:   mustParseDTimestamp,
 	types.TimestampTZ: mustParseDTimestampTZ,
 	types.Interval:    mustParseDInterval,
 	types.Jsonb:       mustParseDJSON,
+	types.Geometry:    mustParseDGeometry,
+	types.Geography:   mustParseDGeography,
 }
 
 func typeSet(tys ...*types.T) map[*types.T]struct{} {
@@ -283,11 +308,11 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c:            tree.NewStrVal("2010-09-28 12:00:00.1"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Time, types.Timestamp, types.TimestampTZ, types.Date),
+			parseOptions: typeSet(types.String, types.Bytes, types.Time, types.TimeTZ, types.Timestamp, types.TimestampTZ, types.Date),
 		},
 		{
 			c:            tree.NewStrVal("2006-07-08T00:00:00.000000123Z"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Time, types.Timestamp, types.TimestampTZ, types.Date),
+			parseOptions: typeSet(types.String, types.Bytes, types.Time, types.TimeTZ, types.Timestamp, types.TimestampTZ, types.Date),
 		},
 		{
 			c:            tree.NewStrVal("PT12H2M"),
@@ -321,6 +346,10 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			c:            tree.NewBytesStrVal(string([]byte{0xff, 0xfe, 0xfd})),
 			parseOptions: typeSet(types.String, types.Bytes),
 		},
+		{
+			c:            tree.NewStrVal(`POINT(1 2)`),
+			parseOptions: typeSet(types.String, types.Bytes, types.Geometry, types.Geography),
+		},
 	}
 
 	for i, test := range testCases {
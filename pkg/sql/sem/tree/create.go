@@ -22,6 +22,7 @@
 package tree
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -108,6 +109,9 @@ type CreateIndex struct {
 	Storing     NameList
 	Interleave  *InterleaveDef
 	PartitionBy *PartitionBy
+	// Predicate, if set, makes this a partial index that only contains the
+	// rows satisfying it.
+	Predicate Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -143,6 +147,10 @@ func (node *CreateIndex) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -209,10 +217,12 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrable     ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
 		Expr     Expr
+		Virtual  bool
 	}
 	Family struct {
 		Name        Name
@@ -311,9 +321,11 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrable = t.Deferrable
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
+			d.Computed.Virtual = t.Virtual
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, pgerror.Newf(pgerror.CodeInvalidTableDefinitionError,
@@ -414,11 +426,16 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(node.References.Deferrable)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
 		ctx.FormatNode(node.Computed.Expr)
-		ctx.WriteString(") STORED")
+		if node.Computed.Virtual {
+			ctx.WriteString(") VIRTUAL")
+		} else {
+			ctx.WriteString(") STORED")
+		}
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -501,15 +518,19 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table      TableName
+	Col        Name // empty-string means use PK
+	Actions    ReferenceActions
+	Match      CompositeKeyMatchMethod
+	Deferrable ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
 type ColumnComputedDef struct {
 	Expr Expr
+	// Virtual is set for columns which are computed when read instead of
+	// being stored.
+	Virtual bool
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -528,6 +549,7 @@ type IndexTableDef struct {
 	Interleave  *InterleaveDef
 	Inverted    bool
 	PartitionBy *PartitionBy
+	Predicate   Expr
 }
 
 // SetName implements the TableDef interface.
@@ -559,6 +581,10 @@ func (node *IndexTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -577,6 +603,7 @@ func (*UniqueConstraintTableDef) constraintTableDef() {}
 type UniqueConstraintTableDef struct {
 	IndexTableDef
 	PrimaryKey bool
+	Deferrable ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -605,6 +632,11 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
+	ctx.FormatNode(node.Deferrable)
 }
 
 // ReferenceAction is the method used to maintain referential integrity through
@@ -674,14 +706,46 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability indicates whether the checking of a UNIQUE or
+// FOREIGN KEY constraint can be deferred until the end of the transaction,
+// and whether it is by default.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	NotDeferrable ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrable:                "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Format implements the NodeFormatter interface. Nothing is printed for
+// constraints which are not deferrable, which is the default.
+func (d ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if d != NotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(d.String())
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name       Name
+	Table      TableName
+	FromCols   NameList
+	ToCols     NameList
+	Actions    ReferenceActions
+	Match      CompositeKeyMatchMethod
+	Deferrable ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -709,6 +773,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrable)
 }
 
 // SetName implements the TableDef interface.
@@ -888,6 +953,7 @@ func (node *RangePartition) Format(ctx *FmtCtx) {
 type CreateTable struct {
 	IfNotExists   bool
 	Table         TableName
+	Temporary     bool
 	Interleave    *InterleaveDef
 	PartitionBy   *PartitionBy
 	Defs          TableDefs
@@ -903,7 +969,11 @@ func (node *CreateTable) As() bool {
 
 // Format implements the NodeFormatter interface.
 func (node *CreateTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE TABLE ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMPORARY ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -979,12 +1049,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:      *col.References.Table,
+					FromCols:   NameList{col.Name},
+					ToCols:     targetCol,
+					Name:       col.References.ConstraintName,
+					Actions:    col.References.Actions,
+					Match:      col.References.Match,
+					Deferrable: col.References.Deferrable,
 				})
 				col.References.Table = nil
 			}
@@ -992,6 +1063,435 @@ func (node *CreateTable) HoistConstraints() {
 	}
 }
 
+// EnumValue represents a single enum value.
+type EnumValue string
+
+// Format implements the NodeFormatter interface.
+func (n *EnumValue) Format(ctx *FmtCtx) {
+	f := ctx.flags
+	if f.HasFlags(FmtAnonymize) {
+		ctx.WriteByte('_')
+	} else {
+		lex.EncodeSQLString(&ctx.Buffer, string(*n))
+	}
+}
+
+// EnumValueList represents a list of enum values.
+type EnumValueList []EnumValue
+
+// Format implements the NodeFormatter interface.
+func (l *EnumValueList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
+// CreateTypeVariety represents a particular variety of user defined types.
+type CreateTypeVariety int
+
+const (
+	_ CreateTypeVariety = iota
+	// Enum represents an ENUM user defined type.
+	Enum
+)
+
+// CreateType represents a CREATE TYPE statement.
+type CreateType struct {
+	TypeName *UnresolvedObjectName
+	Variety  CreateTypeVariety
+	// EnumLabels is set when this represents a CREATE TYPE ... AS ENUM
+	// statement.
+	EnumLabels EnumValueList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateType) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TYPE ")
+	ctx.FormatNode(node.TypeName)
+	ctx.WriteString(" ")
+	switch node.Variety {
+	case Enum:
+		ctx.WriteString("AS ENUM (")
+		ctx.FormatNode(&node.EnumLabels)
+		ctx.WriteString(")")
+	}
+}
+
+// CreateDomain represents a CREATE DOMAIN statement.
+type CreateDomain struct {
+	TypeName *UnresolvedObjectName
+	BaseType *types.T
+	// NotNull is set if the domain does not allow NULL values.
+	NotNull bool
+	// DefaultExpr is the default value of the columns of the domain, or nil.
+	DefaultExpr Expr
+	// Checks are the CHECK constraints of the domain. Their expressions refer
+	// to the value being checked as VALUE.
+	Checks []DomainCheck
+}
+
+// DomainCheck represents a CHECK constraint of a domain.
+type DomainCheck struct {
+	Name Name
+	Expr Expr
+}
+
+// NewCreateDomain constructs a CreateDomain statement from the given column
+// qualifications. Only NULL, NOT NULL, DEFAULT and CHECK are allowed.
+func NewCreateDomain(
+	name *UnresolvedObjectName, baseType *types.T, qualifications []NamedColumnQualification,
+) (*CreateDomain, error) {
+	n := &CreateDomain{TypeName: name, BaseType: baseType}
+	nullSpecified := false
+	for _, c := range qualifications {
+		switch t := c.Qualification.(type) {
+		case *ColumnDefault:
+			if n.DefaultExpr != nil {
+				return nil, pgerror.New(pgerror.CodeSyntaxError,
+					"multiple default expressions")
+			}
+			n.DefaultExpr = t.Expr
+		case NotNullConstraint:
+			if nullSpecified && !n.NotNull {
+				return nil, pgerror.New(pgerror.CodeSyntaxError,
+					"conflicting NULL/NOT NULL constraints")
+			}
+			n.NotNull = true
+			nullSpecified = true
+		case NullConstraint:
+			if n.NotNull {
+				return nil, pgerror.New(pgerror.CodeSyntaxError,
+					"conflicting NULL/NOT NULL constraints")
+			}
+			nullSpecified = true
+		case *ColumnCheckConstraint:
+			n.Checks = append(n.Checks, DomainCheck{Name: c.Name, Expr: t.Expr})
+		default:
+			return nil, pgerror.Newf(pgerror.CodeSyntaxError,
+				"%s constraints not possible for domains", columnQualificationName(t))
+		}
+	}
+	return n, nil
+}
+
+// columnQualificationName returns the name of the given kind of column
+// qualification, for use in error messages.
+func columnQualificationName(c ColumnQualification) string {
+	switch c.(type) {
+	case ColumnCollation:
+		return "COLLATE"
+	case PrimaryKeyConstraint:
+		return "primary key"
+	case UniqueConstraint:
+		return "unique"
+	case *ColumnFKConstraint:
+		return "foreign key"
+	case *ColumnComputedDef:
+		return "computed column"
+	case *ColumnFamilyConstraint:
+		return "FAMILY"
+	default:
+		return fmt.Sprintf("%T", c)
+	}
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE DOMAIN ")
+	ctx.FormatNode(node.TypeName)
+	ctx.WriteString(" AS ")
+	ctx.WriteString(node.BaseType.SQLString())
+	if node.NotNull {
+		ctx.WriteString(" NOT NULL")
+	}
+	if node.DefaultExpr != nil {
+		ctx.WriteString(" DEFAULT ")
+		ctx.FormatNode(node.DefaultExpr)
+	}
+	for i := range node.Checks {
+		if node.Checks[i].Name != "" {
+			ctx.WriteString(" CONSTRAINT ")
+			ctx.FormatNode(&node.Checks[i].Name)
+		}
+		ctx.WriteString(" CHECK (")
+		ctx.FormatNode(node.Checks[i].Expr)
+		ctx.WriteByte(')')
+	}
+}
+
+// CreateSchema represents a CREATE SCHEMA statement.
+type CreateSchema struct {
+	IfNotExists bool
+	Schema      Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSchema) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEMA ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Schema)
+}
+
+// FuncParam represents a parameter of a user-defined function.
+type FuncParam struct {
+	// Name is empty for unnamed parameters.
+	Name Name
+	Type *types.T
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParam) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString(node.Type.SQLString())
+}
+
+// FuncParams represents a list of function parameters.
+type FuncParams []FuncParam
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParams) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionVolatility indicates whether the result of a user-defined function
+// depends only on its arguments.
+type FunctionVolatility int
+
+const (
+	// FunctionVolatile is the default volatility of functions.
+	FunctionVolatile FunctionVolatility = iota
+	// FunctionStable means that the function returns the same result for the
+	// same arguments within a single statement.
+	FunctionStable
+	// FunctionImmutable means that the function always returns the same result
+	// for the same arguments.
+	FunctionImmutable
+)
+
+var functionVolatilityName = [...]string{
+	FunctionVolatile:  "VOLATILE",
+	FunctionStable:    "STABLE",
+	FunctionImmutable: "IMMUTABLE",
+}
+
+func (v FunctionVolatility) String() string {
+	return functionVolatilityName[v]
+}
+
+// FunctionOptions holds the options of a CREATE FUNCTION statement, which can
+// be specified in any order.
+type FunctionOptions struct {
+	Language   Name
+	Volatility FunctionVolatility
+	// Strict is set by STRICT and RETURNS NULL ON NULL INPUT.
+	Strict bool
+	Body   string
+
+	// Which options were specified, to detect conflicting ones.
+	hasLanguage, hasVolatility, hasStrict, hasBody bool
+}
+
+// CombineWith merges other into o. It returns an error if an option is
+// specified in both.
+func (o *FunctionOptions) CombineWith(other *FunctionOptions) error {
+	conflict := func() error {
+		return pgerror.New(pgerror.CodeSyntaxError, "conflicting or redundant options")
+	}
+	if other.hasLanguage {
+		if o.hasLanguage {
+			return conflict()
+		}
+		o.Language, o.hasLanguage = other.Language, true
+	}
+	if other.hasVolatility {
+		if o.hasVolatility {
+			return conflict()
+		}
+		o.Volatility, o.hasVolatility = other.Volatility, true
+	}
+	if other.hasStrict {
+		if o.hasStrict {
+			return conflict()
+		}
+		o.Strict, o.hasStrict = other.Strict, true
+	}
+	if other.hasBody {
+		if o.hasBody {
+			return conflict()
+		}
+		o.Body, o.hasBody = other.Body, true
+	}
+	return nil
+}
+
+// HasLanguage returns true if the LANGUAGE option was specified.
+func (o *FunctionOptions) HasLanguage() bool { return o.hasLanguage }
+
+// HasBody returns true if the AS option was specified.
+func (o *FunctionOptions) HasBody() bool { return o.hasBody }
+
+// LanguageOption returns options with the given LANGUAGE.
+func LanguageOption(lang Name) *FunctionOptions {
+	return &FunctionOptions{Language: lang, hasLanguage: true}
+}
+
+// VolatilityOption returns options with the given volatility.
+func VolatilityOption(v FunctionVolatility) *FunctionOptions {
+	return &FunctionOptions{Volatility: v, hasVolatility: true}
+}
+
+// StrictOption returns options with the given strictness.
+func StrictOption(strict bool) *FunctionOptions {
+	return &FunctionOptions{Strict: strict, hasStrict: true}
+}
+
+// BodyOption returns options with the given function body.
+func BodyOption(body string) *FunctionOptions {
+	return &FunctionOptions{Body: body, hasBody: true}
+}
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	FuncName   *UnresolvedObjectName
+	Replace    bool
+	Params     FuncParams
+	ReturnType *types.T
+	FunctionOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(") RETURNS ")
+	ctx.WriteString(node.ReturnType.SQLString())
+	if node.hasLanguage {
+		ctx.WriteString(" LANGUAGE ")
+		ctx.FormatNode(&node.Language)
+	}
+	if node.Volatility != FunctionVolatile {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.Volatility.String())
+	}
+	if node.Strict {
+		ctx.WriteString(" STRICT")
+	}
+	ctx.WriteString(" AS ")
+	if ctx.flags.HasFlags(FmtAnonymize) {
+		ctx.WriteByte('_')
+	} else {
+		lex.EncodeSQLString(&ctx.Buffer, node.Body)
+	}
+}
+
+// TriggerActionTime indicates whether a trigger fires before or after the
+// row is written.
+type TriggerActionTime int
+
+const (
+	// TriggerBefore triggers fire before the row is written, and can modify
+	// or skip it.
+	TriggerBefore TriggerActionTime = iota
+	// TriggerAfter triggers fire after the row is written.
+	TriggerAfter
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerBefore: "BEFORE",
+	TriggerAfter:  "AFTER",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEvents is the set of events that fire a trigger.
+type TriggerEvents uint8
+
+const (
+	// TriggerInsert fires the trigger for inserted rows.
+	TriggerInsert TriggerEvents = 1 << iota
+	// TriggerUpdate fires the trigger for updated rows.
+	TriggerUpdate
+	// TriggerDelete fires the trigger for deleted rows.
+	TriggerDelete
+)
+
+var triggerEventName = [...]struct {
+	event TriggerEvents
+	name  string
+}{
+	{TriggerInsert, "INSERT"},
+	{TriggerUpdate, "UPDATE"},
+	{TriggerDelete, "DELETE"},
+}
+
+func (e TriggerEvents) String() string {
+	var buf bytes.Buffer
+	for _, ev := range triggerEventName {
+		if e&ev.event != 0 {
+			if buf.Len() > 0 {
+				buf.WriteString(" OR ")
+			}
+			buf.WriteString(ev.name)
+		}
+	}
+	return buf.String()
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	Table      TableName
+	// ForEachStatement is set for statement-level triggers, which fire once
+	// per statement instead of once per row.
+	ForEachStatement bool
+	Body             string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.Events.String())
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.ForEachStatement {
+		ctx.WriteString(" FOR EACH STATEMENT AS ")
+	} else {
+		ctx.WriteString(" FOR EACH ROW AS ")
+	}
+	if ctx.flags.HasFlags(FmtAnonymize) {
+		ctx.WriteByte('_')
+	} else {
+		lex.EncodeSQLString(&ctx.Buffer, node.Body)
+	}
+}
+
 // CreateSequence represents a CREATE SEQUENCE statement.
 type CreateSequence struct {
 	IfNotExists bool
@@ -1150,14 +1650,19 @@ func (node *CreateRole) Format(ctx *FmtCtx) {
 
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
-	Name        TableName
-	ColumnNames NameList
-	AsSource    *Select
+	Name         TableName
+	ColumnNames  NameList
+	AsSource     *Select
+	Materialized bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateView) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE VIEW ")
+	ctx.WriteString("CREATE ")
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+	ctx.WriteString("VIEW ")
 	ctx.FormatNode(&node.Name)
 
 	if len(node.ColumnNames) > 0 {
@@ -14,6 +14,7 @@ package tree
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"math/big"
@@ -27,6 +28,7 @@ import (
 	"unsafe"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -38,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stringencoding"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
@@ -1904,6 +1907,111 @@ func (d *DTime) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DTimeTZ is the time with time zone Datum.
+type DTimeTZ struct {
+	timetz.TimeTZ
+}
+
+var (
+	dTimeTZMin = MakeDTimeTZ(timetz.MinTimeTZ)
+	dTimeTZMax = MakeDTimeTZ(timetz.MaxTimeTZ)
+)
+
+// MakeDTimeTZ creates a DTimeTZ from a timetz.TimeTZ.
+func MakeDTimeTZ(t timetz.TimeTZ) *DTimeTZ {
+	return &DTimeTZ{t}
+}
+
+// MakeDTimeTZFromTime creates a DTimeTZ from the clock time and time zone
+// offset of a time.Time.
+func MakeDTimeTZFromTime(t time.Time) *DTimeTZ {
+	return &DTimeTZ{timetz.MakeTimeTZFromTime(t)}
+}
+
+// ParseDTimeTZ parses and returns the *DTimeTZ Datum value represented by the
+// provided string, or an error if parsing is unsuccessful. Inputs without a
+// time zone use the session time zone.
+func ParseDTimeTZ(ctx ParseTimeContext, s string) (*DTimeTZ, error) {
+	now := relativeParseTime(ctx)
+	t, err := timetz.ParseTimeTZ(now, s)
+	if err != nil {
+		// Build our own error message to avoid exposing the dummy date.
+		return nil, makeParseError(s, types.TimeTZ, nil)
+	}
+	return MakeDTimeTZ(t), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTimeTZ) ResolvedType() *types.T {
+	return types.TimeTZ
+}
+
+// Compare implements the Datum interface.
+func (d *DTimeTZ) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTimeTZ)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.TimeTZ.Compare(v.TimeTZ)
+}
+
+// Prev implements the Datum interface.
+func (d *DTimeTZ) Prev(_ *EvalContext) (Datum, bool) {
+	// Values are ordered by their UTC time and then by their offset, so there
+	// is no cheap way to compute the previous value.
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTimeTZ) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTimeTZ) IsMax(_ *EvalContext) bool {
+	return d.TimeTZ == dTimeTZMax.TimeTZ
+}
+
+// IsMin implements the Datum interface.
+func (d *DTimeTZ) IsMin(_ *EvalContext) bool {
+	return d.TimeTZ == dTimeTZMin.TimeTZ
+}
+
+// Max implements the Datum interface.
+func (d *DTimeTZ) Max(_ *EvalContext) (Datum, bool) {
+	return dTimeTZMax, true
+}
+
+// Min implements the Datum interface.
+func (d *DTimeTZ) Min(_ *EvalContext) (Datum, bool) {
+	return dTimeTZMin, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTimeTZ) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTimeTZ) Format(ctx *FmtCtx) {
+	f := ctx.flags
+	bareStrings := f.HasFlags(FmtFlags(lex.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	ctx.WriteString(d.TimeTZ.String())
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTimeTZ) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
 // DTimestamp is the timestamp Datum.
 type DTimestamp struct {
 	time.Time
@@ -2488,7 +2596,12 @@ func AsJSON(d Datum) (json.JSON, error) {
 	case *DTimestamp:
 		// This is RFC3339Nano, but without the TZ fields.
 		return json.FromString(t.UTC().Format("2006-01-02T15:04:05.999999999")), nil
-	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DBitArray:
+	case *DGeometry:
+		// Like PostGIS, spatial values are converted to GeoJSON objects.
+		return json.ParseJSON(t.GeoJSON(geo.DefaultGeoJSONDecimalDigits))
+	case *DGeography:
+		return json.ParseJSON(t.GeoJSON(geo.DefaultGeoJSONDecimalDigits))
+	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DEnum:
 		return json.FromString(AsStringWithFlags(t, FmtBareStrings)), nil
 	default:
 		if d == DNull {
@@ -2575,6 +2688,221 @@ func (d *DJSON) Size() uintptr {
 	return unsafe.Sizeof(*d) + d.JSON.Size()
 }
 
+// DGeometry is the Datum for planar spatial objects.
+type DGeometry struct{ geo.Geometry }
+
+// NewDGeometry is a helper routine to create a DGeometry initialized from its
+// argument.
+func NewDGeometry(g geo.Geometry) *DGeometry {
+	return &DGeometry{g}
+}
+
+// ParseDGeometry parses a geometry from WKT, EWKT, hex-encoded (E)WKB or
+// GeoJSON.
+func ParseDGeometry(s string) (*DGeometry, error) {
+	g, err := geo.ParseGeometry(s)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgerror.CodeInvalidTextRepresentationError, "could not parse geometry")
+	}
+	return NewDGeometry(g), nil
+}
+
+// AsDGeometry attempts to retrieve a *DGeometry from an Expr, returning a
+// *DGeometry and a flag signifying whether the assertion was successful.
+func AsDGeometry(e Expr) (*DGeometry, bool) {
+	switch t := e.(type) {
+	case *DGeometry:
+		return t, true
+	case *DOidWrapper:
+		return AsDGeometry(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDGeometry attempts to retrieve a *DGeometry from an Expr, panicking
+// if the assertion fails.
+func MustBeDGeometry(e Expr) *DGeometry {
+	g, ok := AsDGeometry(e)
+	if !ok {
+		panic(pgerror.AssertionFailedf("expected *DGeometry, found %T", e))
+	}
+	return g
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DGeometry) ResolvedType() *types.T {
+	return types.Geometry
+}
+
+// Compare implements the Datum interface.
+func (d *DGeometry) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DGeometry)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.Geometry.Compare(v.Geometry)
+}
+
+// Prev implements the Datum interface.
+func (d *DGeometry) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DGeometry) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DGeometry) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DGeometry) IsMin(_ *EvalContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DGeometry) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DGeometry) Min(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DGeometry) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface. Like PostGIS, spatial
+// values are formatted as hex-encoded EWKB.
+func (d *DGeometry) Format(ctx *FmtCtx) {
+	formatSpatial(ctx, d.EWKB())
+}
+
+// Size implements the Datum interface.
+func (d *DGeometry) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(d.NumPoints())*unsafe.Sizeof(geo.Coord{})
+}
+
+// DGeography is the Datum for spatial objects on the surface of the earth.
+type DGeography struct{ geo.Geography }
+
+// NewDGeography is a helper routine to create a DGeography initialized from
+// its argument.
+func NewDGeography(g geo.Geography) *DGeography {
+	return &DGeography{g}
+}
+
+// ParseDGeography parses a geography from WKT, EWKT, hex-encoded (E)WKB or
+// GeoJSON.
+func ParseDGeography(s string) (*DGeography, error) {
+	g, err := geo.ParseGeography(s)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgerror.CodeInvalidTextRepresentationError, "could not parse geography")
+	}
+	return NewDGeography(g), nil
+}
+
+// AsDGeography attempts to retrieve a *DGeography from an Expr, returning a
+// *DGeography and a flag signifying whether the assertion was successful.
+func AsDGeography(e Expr) (*DGeography, bool) {
+	switch t := e.(type) {
+	case *DGeography:
+		return t, true
+	case *DOidWrapper:
+		return AsDGeography(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDGeography attempts to retrieve a *DGeography from an Expr,
+// panicking if the assertion fails.
+func MustBeDGeography(e Expr) *DGeography {
+	g, ok := AsDGeography(e)
+	if !ok {
+		panic(pgerror.AssertionFailedf("expected *DGeography, found %T", e))
+	}
+	return g
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DGeography) ResolvedType() *types.T {
+	return types.Geography
+}
+
+// Compare implements the Datum interface.
+func (d *DGeography) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DGeography)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.Geography.Compare(v.Geography)
+}
+
+// Prev implements the Datum interface.
+func (d *DGeography) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DGeography) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DGeography) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DGeography) IsMin(_ *EvalContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DGeography) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DGeography) Min(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DGeography) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DGeography) Format(ctx *FmtCtx) {
+	formatSpatial(ctx, d.EWKB())
+}
+
+// Size implements the Datum interface.
+func (d *DGeography) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(d.NumPoints())*unsafe.Sizeof(geo.Coord{})
+}
+
+// formatSpatial formats the EWKB of a spatial value as upper-case hex.
+func formatSpatial(ctx *FmtCtx, ewkb []byte) {
+	s := strings.ToUpper(hex.EncodeToString(ewkb))
+	if ctx.flags.HasFlags(fmtRawStrings) {
+		ctx.WriteString(s)
+	} else {
+		lex.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
 // DTuple is the tuple Datum.
 type DTuple struct {
 	D Datums
@@ -3139,6 +3467,16 @@ func (d *DArray) Len() int {
 	return len(d.Array)
 }
 
+// Dimensions returns the length of each dimension of the array, from the
+// outermost to the innermost. An empty array has no dimensions.
+func (d *DArray) Dimensions() []int {
+	var dims []int
+	for a, ok := d, true; ok && a.Len() > 0; a, ok = AsDArray(a.Array[0]) {
+		dims = append(dims, a.Len())
+	}
+	return dims
+}
+
 // Size implements the Datum interface.
 func (d *DArray) Size() uintptr {
 	sz := unsafe.Sizeof(*d)
@@ -3170,10 +3508,16 @@ func (d *DArray) Append(v Datum) error {
 			if prevItem == DNull {
 				return errNonHomogeneousArray
 			}
-			expectedLen := MustBeDArray(prevItem).Len()
-			if MustBeDArray(v).Len() != expectedLen {
+			prevDims := MustBeDArray(prevItem).Dimensions()
+			dims := MustBeDArray(v).Dimensions()
+			if len(dims) != len(prevDims) {
 				return errNonHomogeneousArray
 			}
+			for i := range dims {
+				if dims[i] != prevDims[i] {
+					return errNonHomogeneousArray
+				}
+			}
 		}
 	}
 	if v == DNull {
@@ -3310,6 +3654,158 @@ func (d *DOid) Min(ctx *EvalContext) (Datum, bool) {
 	return &DOid{*min.(*DInt), d.semanticType, ""}, ok
 }
 
+// DEnum represents an enum value.
+type DEnum struct {
+	// EnumTyp is the enum type of the value. It contains all the members of the
+	// type.
+	EnumTyp *types.T
+	// PhysicalRep is the byte string used to encode the value in keys and
+	// values. Physical representations sort in the order of the enum members.
+	PhysicalRep []byte
+	// LogicalRep is the label of the enum member.
+	LogicalRep string
+}
+
+// MakeDEnumFromPhysicalRepresentation creates a DEnum of the given type from
+// the physical representation of one of its members.
+func MakeDEnumFromPhysicalRepresentation(typ *types.T, rep []byte) (*DEnum, error) {
+	data := typ.EnumData()
+	if data == nil {
+		return nil, pgerror.AssertionFailedf("%s is not an enum type", typ)
+	}
+	for i := range data.PhysicalRepresentations {
+		if bytes.Equal(data.PhysicalRepresentations[i], rep) {
+			return newDEnumFromOrdinal(typ, i), nil
+		}
+	}
+	return nil, pgerror.AssertionFailedf(
+		"could not find %v in physical representations of enum %s", rep, typ)
+}
+
+// MakeDEnumFromLogicalRepresentation creates a DEnum of the given type from
+// the label of one of its members. Members that are being added to the type
+// cannot be used yet, since some nodes might not be aware of them.
+func MakeDEnumFromLogicalRepresentation(typ *types.T, rep string) (*DEnum, error) {
+	data := typ.EnumData()
+	if data == nil {
+		return nil, pgerror.AssertionFailedf("%s is not an enum type", typ)
+	}
+	for i := range data.LogicalRepresentations {
+		if data.LogicalRepresentations[i] != rep {
+			continue
+		}
+		if data.IsMemberReadOnly[i] {
+			return nil, pgerror.Newf(pgerror.CodeUnsafeNewEnumValueUsageError,
+				"enum value %q is not yet public", rep)
+		}
+		return newDEnumFromOrdinal(typ, i), nil
+	}
+	return nil, pgerror.Newf(pgerror.CodeInvalidTextRepresentationError,
+		"invalid input value for enum %s: %q", typ, rep)
+}
+
+func newDEnumFromOrdinal(typ *types.T, i int) *DEnum {
+	data := typ.EnumData()
+	return &DEnum{
+		EnumTyp:     typ,
+		PhysicalRep: data.PhysicalRepresentations[i],
+		LogicalRep:  data.LogicalRepresentations[i],
+	}
+}
+
+// ordinal returns the position of the value among the members of its type.
+func (d *DEnum) ordinal() int {
+	reps := d.EnumTyp.EnumData().PhysicalRepresentations
+	for i := range reps {
+		if bytes.Equal(reps[i], d.PhysicalRep) {
+			return i
+		}
+	}
+	panic(pgerror.AssertionFailedf(
+		"could not find %v in physical representations of enum %s", d.PhysicalRep, d.EnumTyp))
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DEnum) ResolvedType() *types.T {
+	return d.EnumTyp
+}
+
+// Compare implements the Datum interface.
+func (d *DEnum) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DEnum)
+	if !ok || !d.EnumTyp.Equivalent(v.EnumTyp) {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.PhysicalRep, v.PhysicalRep)
+}
+
+// Prev implements the Datum interface.
+func (d *DEnum) Prev(_ *EvalContext) (Datum, bool) {
+	i := d.ordinal()
+	if i == 0 {
+		return nil, false
+	}
+	return newDEnumFromOrdinal(d.EnumTyp, i-1), true
+}
+
+// Next implements the Datum interface.
+func (d *DEnum) Next(_ *EvalContext) (Datum, bool) {
+	i := d.ordinal()
+	if i == len(d.EnumTyp.EnumData().PhysicalRepresentations)-1 {
+		return nil, false
+	}
+	return newDEnumFromOrdinal(d.EnumTyp, i+1), true
+}
+
+// IsMax implements the Datum interface.
+func (d *DEnum) IsMax(_ *EvalContext) bool {
+	return d.ordinal() == len(d.EnumTyp.EnumData().PhysicalRepresentations)-1
+}
+
+// IsMin implements the Datum interface.
+func (d *DEnum) IsMin(_ *EvalContext) bool {
+	return d.ordinal() == 0
+}
+
+// Max implements the Datum interface.
+func (d *DEnum) Max(_ *EvalContext) (Datum, bool) {
+	n := len(d.EnumTyp.EnumData().PhysicalRepresentations)
+	if n == 0 {
+		return nil, false
+	}
+	return newDEnumFromOrdinal(d.EnumTyp, n-1), true
+}
+
+// Min implements the Datum interface.
+func (d *DEnum) Min(_ *EvalContext) (Datum, bool) {
+	if len(d.EnumTyp.EnumData().PhysicalRepresentations) == 0 {
+		return nil, false
+	}
+	return newDEnumFromOrdinal(d.EnumTyp, 0), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DEnum) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DEnum) Format(ctx *FmtCtx) {
+	buf, f := &ctx.Buffer, ctx.flags
+	if f.HasFlags(fmtRawStrings) {
+		buf.WriteString(d.LogicalRep)
+	} else {
+		lex.EncodeSQLStringWithFlags(buf, d.LogicalRep, f.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DEnum) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.PhysicalRep)) + uintptr(len(d.LogicalRep))
+}
+
 // DOidWrapper is a Datum implementation which is a wrapper around a Datum, allowing
 // custom Oid values to be attached to the Datum and its types.T.
 // The reason the Datum type was introduced was to permit the introduction of Datum
@@ -3577,6 +4073,7 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.BytesFamily:          {unsafe.Sizeof(DBytes("")), variableSize},
 	types.DateFamily:           {unsafe.Sizeof(DDate{}), fixedSize},
 	types.TimeFamily:           {unsafe.Sizeof(DTime(0)), fixedSize},
+	types.TimeTZFamily:         {unsafe.Sizeof(DTimeTZ{}), fixedSize},
 	types.TimestampFamily:      {unsafe.Sizeof(DTimestamp{}), fixedSize},
 	types.TimestampTZFamily:    {unsafe.Sizeof(DTimestampTZ{}), fixedSize},
 	types.IntervalFamily:       {unsafe.Sizeof(DInterval{}), fixedSize},
@@ -3584,6 +4081,9 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.UuidFamily:           {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INetFamily:           {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	types.OidFamily:            {unsafe.Sizeof(DInt(0)), fixedSize},
+	types.EnumFamily:           {unsafe.Sizeof(DEnum{}), variableSize},
+	types.GeometryFamily:       {unsafe.Sizeof(DGeometry{}), variableSize},
+	types.GeographyFamily:      {unsafe.Sizeof(DGeography{}), variableSize},
 
 	// TODO(jordan,justin): This seems suspicious.
 	types.ArrayFamily: {unsafe.Sizeof(DString("")), variableSize},
@@ -25,6 +25,7 @@ package tree
 type Delete struct {
 	With      *With
 	Table     TableExpr
+	Using     TableExprs
 	Where     *Where
 	OrderBy   OrderBy
 	Limit     *Limit
@@ -36,6 +37,10 @@ func (node *Delete) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.With)
 	ctx.WriteString("DELETE FROM ")
 	ctx.FormatNode(node.Table)
+	if len(node.Using) > 0 {
+		ctx.WriteString(" USING ")
+		ctx.FormatNode(&node.Using)
+	}
 	if node.Where != nil {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Where)
@@ -25,6 +25,9 @@ type DiscardMode int
 const (
 	// DiscardModeAll represents a DISCARD ALL statement.
 	DiscardModeAll DiscardMode = iota
+
+	// DiscardModeTemp represents a DISCARD TEMPORARY statement.
+	DiscardModeTemp
 )
 
 // Format implements the NodeFormatter interface.
@@ -32,6 +35,8 @@ func (node *Discard) Format(ctx *FmtCtx) {
 	switch node.Mode {
 	case DiscardModeAll:
 		ctx.WriteString("DISCARD ALL")
+	case DiscardModeTemp:
+		ctx.WriteString("DISCARD TEMPORARY")
 	}
 }
 
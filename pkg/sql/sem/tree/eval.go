@@ -25,6 +25,7 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -42,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -594,6 +596,52 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return MakeDTime(t.Add(left.(*DInterval).Duration)), nil
 			},
 		},
+		&BinOp{
+			LeftType:   types.Date,
+			RightType:  types.TimeTZ,
+			ReturnType: types.TimestampTZ,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				d, err := MakeDTimestampTZFromDate(time.UTC, left.(*DDate))
+				if err != nil {
+					return nil, err
+				}
+				t := time.Duration(right.(*DTimeTZ).UTCMicros()) * time.Microsecond
+				return MakeDTimestampTZ(d.Add(t), time.Microsecond), nil
+			},
+		},
+		&BinOp{
+			LeftType:   types.TimeTZ,
+			RightType:  types.Date,
+			ReturnType: types.TimestampTZ,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				d, err := MakeDTimestampTZFromDate(time.UTC, right.(*DDate))
+				if err != nil {
+					return nil, err
+				}
+				t := time.Duration(left.(*DTimeTZ).UTCMicros()) * time.Microsecond
+				return MakeDTimestampTZ(d.Add(t), time.Microsecond), nil
+			},
+		},
+		&BinOp{
+			LeftType:   types.TimeTZ,
+			RightType:  types.Interval,
+			ReturnType: types.TimeTZ,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := left.(*DTimeTZ).TimeTZ
+				return MakeDTimeTZ(timetz.MakeTimeTZ(
+					t.TimeOfDay.Add(right.(*DInterval).Duration), t.OffsetSecs)), nil
+			},
+		},
+		&BinOp{
+			LeftType:   types.Interval,
+			RightType:  types.TimeTZ,
+			ReturnType: types.TimeTZ,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := right.(*DTimeTZ).TimeTZ
+				return MakeDTimeTZ(timetz.MakeTimeTZ(
+					t.TimeOfDay.Add(left.(*DInterval).Duration), t.OffsetSecs)), nil
+			},
+		},
 		&BinOp{
 			LeftType:   types.Timestamp,
 			RightType:  types.Interval,
@@ -848,6 +896,16 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return MakeDTime(t.Add(right.(*DInterval).Duration.Mul(-1))), nil
 			},
 		},
+		&BinOp{
+			LeftType:   types.TimeTZ,
+			RightType:  types.Interval,
+			ReturnType: types.TimeTZ,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := left.(*DTimeTZ).TimeTZ
+				return MakeDTimeTZ(timetz.MakeTimeTZ(
+					t.TimeOfDay.Add(right.(*DInterval).Duration.Mul(-1)), t.OffsetSecs)), nil
+			},
+		},
 		&BinOp{
 			LeftType:   types.Timestamp,
 			RightType:  types.Interval,
@@ -1687,6 +1745,31 @@ func cmpOpFixups(cmpOps map[ComparisonOperator]cmpOpOverload) map[ComparisonOper
 			Fn:           cmpOpScalarIsFn,
 			NullableArgs: true,
 		})
+
+		// Array containment and overlap comparisons.
+		cmpOps[Contains] = append(cmpOps[Contains], &CmpOp{
+			LeftType:  types.MakeArray(t),
+			RightType: types.MakeArray(t),
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayContains(ctx, MustBeDArray(left), MustBeDArray(right)))), nil
+			},
+		})
+
+		cmpOps[ContainedBy] = append(cmpOps[ContainedBy], &CmpOp{
+			LeftType:  types.MakeArray(t),
+			RightType: types.MakeArray(t),
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayContains(ctx, MustBeDArray(right), MustBeDArray(left)))), nil
+			},
+		})
+
+		cmpOps[Overlaps] = append(cmpOps[Overlaps], &CmpOp{
+			LeftType:  types.MakeArray(t),
+			RightType: types.MakeArray(t),
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayOverlaps(ctx, MustBeDArray(left), MustBeDArray(right)))), nil
+			},
+		})
 	}
 
 	for op, overload := range cmpOps {
@@ -1700,6 +1783,43 @@ func cmpOpFixups(cmpOps map[ComparisonOperator]cmpOpOverload) map[ComparisonOper
 	return cmpOps
 }
 
+// arrayContains returns whether every element of needles is also an element
+// of haystack. As in Postgres, NULL elements are never equal to anything, so
+// an array with a NULL element is not contained by any array.
+func arrayContains(ctx *EvalContext, haystack, needles *DArray) bool {
+	for _, n := range needles.Array {
+		if !arrayHasElement(ctx, haystack, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayOverlaps returns whether the two arrays have at least one non-NULL
+// element in common.
+func arrayOverlaps(ctx *EvalContext, left, right *DArray) bool {
+	for _, e := range right.Array {
+		if arrayHasElement(ctx, left, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// arrayHasElement returns whether the non-NULL datum d is an element of the
+// array. It always returns false if d is NULL.
+func arrayHasElement(ctx *EvalContext, array *DArray, d Datum) bool {
+	if d == DNull {
+		return false
+	}
+	for _, e := range array.Array {
+		if e != DNull && e.Compare(ctx, d) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // cmpOpOverload is an overloaded set of comparison operator implementations.
 type cmpOpOverload []overloadImpl
 
@@ -1746,7 +1866,10 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Date, types.Date),
 		makeEqFn(types.Decimal, types.Decimal),
 		makeEqFn(types.AnyCollatedString, types.AnyCollatedString),
+		makeEqFn(types.AnyEnum, types.AnyEnum),
 		makeEqFn(types.Float, types.Float),
+		makeEqFn(types.Geography, types.Geography),
+		makeEqFn(types.Geometry, types.Geometry),
 		makeEqFn(types.INet, types.INet),
 		makeEqFn(types.Int, types.Int),
 		makeEqFn(types.Interval, types.Interval),
@@ -1754,6 +1877,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Oid, types.Oid),
 		makeEqFn(types.String, types.String),
 		makeEqFn(types.Time, types.Time),
+		makeEqFn(types.TimeTZ, types.TimeTZ),
 		makeEqFn(types.Timestamp, types.Timestamp),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ),
 		makeEqFn(types.Uuid, types.Uuid),
@@ -1790,6 +1914,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Date, types.Date),
 		makeLtFn(types.Decimal, types.Decimal),
 		makeLtFn(types.AnyCollatedString, types.AnyCollatedString),
+		makeLtFn(types.AnyEnum, types.AnyEnum),
 		makeLtFn(types.Float, types.Float),
 		makeLtFn(types.INet, types.INet),
 		makeLtFn(types.Int, types.Int),
@@ -1797,6 +1922,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Oid, types.Oid),
 		makeLtFn(types.String, types.String),
 		makeLtFn(types.Time, types.Time),
+		makeLtFn(types.TimeTZ, types.TimeTZ),
 		makeLtFn(types.Timestamp, types.Timestamp),
 		makeLtFn(types.TimestampTZ, types.TimestampTZ),
 		makeLtFn(types.Uuid, types.Uuid),
@@ -1833,6 +1959,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Date, types.Date),
 		makeLeFn(types.Decimal, types.Decimal),
 		makeLeFn(types.AnyCollatedString, types.AnyCollatedString),
+		makeLeFn(types.AnyEnum, types.AnyEnum),
 		makeLeFn(types.Float, types.Float),
 		makeLeFn(types.INet, types.INet),
 		makeLeFn(types.Int, types.Int),
@@ -1840,6 +1967,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Oid, types.Oid),
 		makeLeFn(types.String, types.String),
 		makeLeFn(types.Time, types.Time),
+		makeLeFn(types.TimeTZ, types.TimeTZ),
 		makeLeFn(types.Timestamp, types.Timestamp),
 		makeLeFn(types.TimestampTZ, types.TimestampTZ),
 		makeLeFn(types.Uuid, types.Uuid),
@@ -1884,7 +2012,10 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Date, types.Date),
 		makeIsFn(types.Decimal, types.Decimal),
 		makeIsFn(types.AnyCollatedString, types.AnyCollatedString),
+		makeIsFn(types.AnyEnum, types.AnyEnum),
 		makeIsFn(types.Float, types.Float),
+		makeIsFn(types.Geography, types.Geography),
+		makeIsFn(types.Geometry, types.Geometry),
 		makeIsFn(types.INet, types.INet),
 		makeIsFn(types.Int, types.Int),
 		makeIsFn(types.Interval, types.Interval),
@@ -1892,6 +2023,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Oid, types.Oid),
 		makeIsFn(types.String, types.String),
 		makeIsFn(types.Time, types.Time),
+		makeIsFn(types.TimeTZ, types.TimeTZ),
 		makeIsFn(types.Timestamp, types.Timestamp),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ),
 		makeIsFn(types.Uuid, types.Uuid),
@@ -1931,6 +2063,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Date),
 		makeEvalTupleIn(types.Decimal),
 		makeEvalTupleIn(types.AnyCollatedString),
+		makeEvalTupleIn(types.AnyEnum),
 		makeEvalTupleIn(types.AnyTuple),
 		makeEvalTupleIn(types.Float),
 		makeEvalTupleIn(types.INet),
@@ -1940,6 +2073,7 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Oid),
 		makeEvalTupleIn(types.String),
 		makeEvalTupleIn(types.Time),
+		makeEvalTupleIn(types.TimeTZ),
 		makeEvalTupleIn(types.Timestamp),
 		makeEvalTupleIn(types.TimestampTZ),
 		makeEvalTupleIn(types.Uuid),
@@ -2089,6 +2223,18 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	Overlaps: {
+		&CmpOp{
+			LeftType:  types.INet,
+			RightType: types.INet,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				ipAddr := MustBeDIPAddr(left).IPAddr
+				other := MustBeDIPAddr(right).IPAddr
+				return MakeDBool(DBool(ipAddr.ContainsOrContainedBy(&other))), nil
+			},
+		},
+	},
 })
 
 // This map contains the inverses for operators in the CmpOps map that have
@@ -2455,6 +2601,10 @@ type EvalPlanner interface {
 
 	// EvalSubquery returns the Datum for the given subquery node.
 	EvalSubquery(expr *Subquery) (Datum, error)
+
+	// CheckDomainConstraints returns an error if the given datum does not
+	// satisfy the constraints of the domain typ.
+	CheckDomainConstraints(typ *types.T, d Datum) error
 }
 
 // EvalSessionAccessor is a limited interface to access session variables.
@@ -2505,6 +2655,44 @@ type SequenceOperators interface {
 	SetSequenceValue(ctx context.Context, seqName *TableName, newVal int64, isCalled bool) error
 }
 
+// ConstraintCheck is a check of a deferrable UNIQUE or FOREIGN KEY
+// constraint, which is run after the rows it covers have been written.
+type ConstraintCheck interface {
+	// Run performs the check in the given transaction, and returns the
+	// constraint violation error if it fails.
+	Run(ctx context.Context, txn *client.Txn) error
+}
+
+// ConstraintID identifies a constraint by the ID of its table and its name,
+// which is unique among the constraints of the table.
+type ConstraintID struct {
+	TableID uint32
+	Name    string
+}
+
+// DeferrableConstraints is used by the row writers to queue the checks of
+// deferrable constraints, either until the end of the statement or until the
+// transaction commits.
+type DeferrableConstraints interface {
+	// Deferred returns whether the checks of the given deferrable constraint,
+	// which is INITIALLY DEFERRED if initiallyDeferred is set, must currently
+	// wait until the transaction commits.
+	Deferred(id ConstraintID, initiallyDeferred bool) bool
+
+	// QueueCheck queues a check of the given constraint until the transaction
+	// commits if deferred is set, or until the end of the current statement
+	// otherwise.
+	QueueCheck(id ConstraintID, check ConstraintCheck, deferred bool)
+
+	// HasChecks returns whether any check is queued, either until the end of
+	// the current statement or until the transaction commits.
+	HasChecks() bool
+
+	// RunStatementChecks runs and clears the checks queued until the end of
+	// the current statement.
+	RunStatementChecks(ctx context.Context, txn *client.Txn) error
+}
+
 // EvalContextTestingKnobs contains test knobs.
 type EvalContextTestingKnobs struct {
 	// AssertFuncExprReturnTypes indicates whether FuncExpr evaluations
@@ -2613,6 +2801,11 @@ type EvalContext struct {
 
 	Sequence SequenceOperators
 
+	// Constraints tracks the checks of deferrable constraints of the current
+	// transaction. It is nil outside of SQL sessions, in which case no check
+	// is ever deferred.
+	Constraints DeferrableConstraints
+
 	// The transaction in which the statement is executing.
 	Txn *client.Txn
 	// A handle to the database.
@@ -3008,12 +3201,27 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		return nil, err
 	}
 
-	// NULL cast to anything is NULL.
+	// NULL cast to anything is NULL, unless the domain it is cast to
+	// disallows it.
 	if d == DNull {
+		if expr.Type.DomainID() != 0 {
+			if err := ctx.Planner.CheckDomainConstraints(expr.Type, d); err != nil {
+				return nil, err
+			}
+		}
 		return d, nil
 	}
 	d = UnwrapDatum(ctx, d)
-	return PerformCast(ctx, d, expr.Type)
+	res, err := PerformCast(ctx, d, expr.Type)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Type.DomainID() != 0 {
+		if err := ctx.Planner.CheckDomainConstraints(expr.Type, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
 // PerformCast performs a cast from the provided Datum to the specified
@@ -3243,7 +3451,7 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 				ctx.SessionData.DataConversion.GetFloatPrec(), 64)
 		case *DBool, *DInt, *DDecimal:
 			s = d.String()
-		case *DTimestamp, *DTimestampTZ, *DDate, *DTime:
+		case *DTimestamp, *DTimestampTZ, *DDate, *DTime, *DTimeTZ:
 			s = AsStringWithFlags(d, FmtBareStrings)
 		case *DTuple:
 			s = AsStringWithFlags(d, FmtPgwireText)
@@ -3269,6 +3477,10 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			s = t.name
 		case *DJSON:
 			s = t.JSON.String()
+		case *DEnum:
+			s = t.LogicalRep
+		case *DGeometry, *DGeography:
+			s = AsStringWithFlags(d, FmtBareStrings)
 		}
 		switch t.Family() {
 		case types.StringFamily:
@@ -3299,6 +3511,10 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			return NewDBytes(DBytes(t.Contents)), nil
 		case *DUuid:
 			return NewDBytes(DBytes(t.GetBytes())), nil
+		case *DGeometry:
+			return NewDBytes(DBytes(t.EWKB())), nil
+		case *DGeography:
+			return NewDBytes(DBytes(t.EWKB())), nil
 		case *DBytes:
 			return d, nil
 		}
@@ -3315,6 +3531,58 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			return d, nil
 		}
 
+	case types.EnumFamily:
+		switch v := d.(type) {
+		case *DString:
+			return MakeDEnumFromLogicalRepresentation(t, string(*v))
+		case *DCollatedString:
+			return MakeDEnumFromLogicalRepresentation(t, v.Contents)
+		case *DEnum:
+			if v.EnumTyp.Oid() == t.Oid() {
+				return d, nil
+			}
+		}
+
+	case types.GeometryFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDGeometry(string(*v))
+		case *DCollatedString:
+			return ParseDGeometry(v.Contents)
+		case *DBytes:
+			g, err := geo.ParseGeometryFromEWKB([]byte(*v))
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeometry(g), nil
+		case *DGeometry:
+			return d, nil
+		case *DGeography:
+			return NewDGeometry(v.AsGeometry()), nil
+		}
+
+	case types.GeographyFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDGeography(string(*v))
+		case *DCollatedString:
+			return ParseDGeography(v.Contents)
+		case *DBytes:
+			g, err := geo.ParseGeographyFromEWKB([]byte(*v))
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeography(g), nil
+		case *DGeography:
+			return d, nil
+		case *DGeometry:
+			g, err := v.AsGeography()
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeography(g), nil
+		}
+
 	case types.INetFamily:
 		switch t := d.(type) {
 		case *DString:
@@ -3351,6 +3619,8 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			return ParseDTime(ctx, d.Contents)
 		case *DTime:
 			return d, nil
+		case *DTimeTZ:
+			return MakeDTime(d.TimeOfDay), nil
 		case *DTimestamp:
 			return MakeDTime(timeofday.FromTime(d.Time)), nil
 		case *DTimestampTZ:
@@ -3359,6 +3629,23 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			return MakeDTime(timeofday.Min.Add(d.Duration)), nil
 		}
 
+	case types.TimeTZFamily:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTimeTZ(ctx, string(*d))
+		case *DCollatedString:
+			return ParseDTimeTZ(ctx, d.Contents)
+		case *DTime:
+			// Like Postgres, use the offset currently in effect in the session
+			// time zone.
+			_, offset := ctx.GetRelativeParseTime().Zone()
+			return MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.TimeOfDay(*d), -int32(offset))), nil
+		case *DTimeTZ:
+			return d, nil
+		case *DTimestampTZ:
+			return MakeDTimeTZFromTime(d.Time.In(ctx.GetLocation())), nil
+		}
+
 	case types.TimestampFamily:
 		// TODO(knz): Timestamp from float, decimal.
 		prec := time.Microsecond
@@ -3582,9 +3869,9 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 
 // Eval implements the TypedExpr interface.
 func (expr *IndirectionExpr) Eval(ctx *EvalContext) (Datum, error) {
-	var subscriptIdx int
+	subscriptIdxs := make([]int, len(expr.Indirection))
 	for i, t := range expr.Indirection {
-		if t.Slice || i > 0 {
+		if t.Slice {
 			return nil, pgerror.AssertionFailedf("unsupported feature should have been rejected during planning")
 		}
 
@@ -3595,31 +3882,34 @@ func (expr *IndirectionExpr) Eval(ctx *EvalContext) (Datum, error) {
 		if d == DNull {
 			return d, nil
 		}
-		subscriptIdx = int(MustBeDInt(d))
+		subscriptIdxs[i] = int(MustBeDInt(d))
 	}
 
 	d, err := expr.Expr.(TypedExpr).Eval(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if d == DNull {
-		return d, nil
-	}
 
-	// Index into the DArray, using 1-indexing.
-	arr := MustBeDArray(d)
+	// Index into each dimension of the DArray in turn, using 1-indexing.
+	for _, subscriptIdx := range subscriptIdxs {
+		if d == DNull {
+			return d, nil
+		}
+		arr := MustBeDArray(d)
 
-	// VECTOR types use 0-indexing.
-	if w, ok := d.(*DOidWrapper); ok {
-		switch w.Oid {
-		case oid.T_oidvector, oid.T_int2vector:
-			subscriptIdx++
+		// VECTOR types use 0-indexing.
+		if w, ok := d.(*DOidWrapper); ok {
+			switch w.Oid {
+			case oid.T_oidvector, oid.T_int2vector:
+				subscriptIdx++
+			}
 		}
+		if subscriptIdx < 1 || subscriptIdx > arr.Len() {
+			return DNull, nil
+		}
+		d = arr.Array[subscriptIdx-1]
 	}
-	if subscriptIdx < 1 || subscriptIdx > arr.Len() {
-		return DNull, nil
-	}
-	return arr.Array[subscriptIdx-1], nil
+	return d, nil
 }
 
 // Eval implements the TypedExpr interface.
@@ -3946,6 +4236,11 @@ func (expr DefaultVal) Eval(ctx *EvalContext) (Datum, error) {
 	return nil, pgerror.AssertionFailedf("unhandled type %T", expr)
 }
 
+// Eval implements the TypedExpr interface.
+func (expr *GroupingExpr) Eval(ctx *EvalContext) (Datum, error) {
+	return nil, pgerror.AssertionFailedf("unhandled type %T", expr)
+}
+
 // Eval implements the TypedExpr interface.
 func (expr UnqualifiedStar) Eval(ctx *EvalContext) (Datum, error) {
 	return nil, pgerror.AssertionFailedf("unhandled type %T", expr)
@@ -4074,6 +4369,11 @@ func (t *DTime) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTimeTZ) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DFloat) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -4139,6 +4439,21 @@ func (t *DOid) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DEnum) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DGeometry) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DGeography) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DOidWrapper) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
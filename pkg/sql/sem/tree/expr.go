@@ -325,6 +325,7 @@ const (
 	JSONExists
 	JSONSomeExists
 	JSONAllExists
+	Overlaps
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -374,6 +375,7 @@ var comparisonOpName = [...]string{
 	JSONExists:        "?",
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
+	Overlaps:          "&&",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -734,6 +736,31 @@ func (node *CoalesceExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// GroupingExpr represents a GROUPING(...) expression. Its result is an
+// integer bit mask in which bit (n-1-i) is set when the i-th of its n
+// arguments is not part of the grouping set of the current output row.
+type GroupingExpr struct {
+	Exprs Exprs
+
+	typeAnnotation
+}
+
+// MaxGroupingArgs is the maximum number of arguments to a GROUPING
+// expression, which is limited by the width of the result bit mask.
+const MaxGroupingArgs = 31
+
+// TypedExprAt returns the expression at the specified index as a TypedExpr.
+func (node *GroupingExpr) TypedExprAt(idx int) TypedExpr {
+	return node.Exprs[idx].(TypedExpr)
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING(")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DefaultVal represents the DEFAULT expression.
 type DefaultVal struct{}
 
@@ -1505,11 +1532,13 @@ var (
 	stringCastTypes = annotateCast(types.String, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
 		types.VarBit,
 		types.AnyArray, types.AnyTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.Oid, types.INet, types.Jsonb})
-	bytesCastTypes = annotateCast(types.Bytes, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid})
+		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.TimeTZ, types.Oid, types.INet, types.Jsonb,
+		types.AnyEnum, types.Geometry, types.Geography})
+	bytesCastTypes = annotateCast(types.Bytes, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid, types.Geometry, types.Geography})
 	dateCastTypes  = annotateCast(types.Date, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int})
-	timeCastTypes  = annotateCast(types.Time, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Time,
+	timeCastTypes  = annotateCast(types.Time, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Time, types.TimeTZ,
 		types.Timestamp, types.TimestampTZ, types.Interval})
+	timeTZCastTypes    = annotateCast(types.TimeTZ, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Time, types.TimeTZ, types.TimestampTZ})
 	timestampCastTypes = annotateCast(types.Timestamp, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int})
 	intervalCastTypes  = annotateCast(types.Interval, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Int, types.Time, types.Interval, types.Float, types.Decimal})
 	oidCastTypes       = annotateCast(types.Oid, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Int, types.Oid})
@@ -1517,6 +1546,9 @@ var (
 	inetCastTypes      = annotateCast(types.INet, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.INet})
 	arrayCastTypes     = annotateCast(types.AnyArray, []*types.T{types.Unknown, types.String})
 	jsonCastTypes      = annotateCast(types.Jsonb, []*types.T{types.Unknown, types.String, types.Jsonb})
+	enumCastTypes      = annotateCast(types.AnyEnum, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.AnyEnum})
+	geometryCastTypes  = annotateCast(types.Geometry, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Geometry, types.Geography})
+	geographyCastTypes = annotateCast(types.Geography, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Geometry, types.Geography})
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1540,6 +1572,8 @@ func validCastTypes(t *types.T) []castInfo {
 		return dateCastTypes
 	case types.TimeFamily:
 		return timeCastTypes
+	case types.TimeTZFamily:
+		return timeTZCastTypes
 	case types.TimestampFamily, types.TimestampTZFamily:
 		return timestampCastTypes
 	case types.IntervalFamily:
@@ -1552,6 +1586,12 @@ func validCastTypes(t *types.T) []castInfo {
 		return inetCastTypes
 	case types.OidFamily:
 		return oidCastTypes
+	case types.EnumFamily:
+		return enumCastTypes
+	case types.GeometryFamily:
+		return geometryCastTypes
+	case types.GeographyFamily:
+		return geographyCastTypes
 	case types.ArrayFamily:
 		ret := make([]castInfo, len(arrayCastTypes))
 		copy(ret, arrayCastTypes)
@@ -1703,6 +1743,7 @@ func (node *DBool) String() string            { return AsString(node) }
 func (node *DBytes) String() string           { return AsString(node) }
 func (node *DDate) String() string            { return AsString(node) }
 func (node *DTime) String() string            { return AsString(node) }
+func (node *DTimeTZ) String() string          { return AsString(node) }
 func (node *DDecimal) String() string         { return AsString(node) }
 func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
@@ -1717,10 +1758,15 @@ func (node *DTimestampTZ) String() string     { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
 func (node *DArray) String() string           { return AsString(node) }
 func (node *DOid) String() string             { return AsString(node) }
+func (node *DEnum) String() string            { return AsString(node) }
+func (node *DGeometry) String() string        { return AsString(node) }
+func (node *DGeography) String() string       { return AsString(node) }
 func (node *DOidWrapper) String() string      { return AsString(node) }
 func (node *Exprs) String() string            { return AsString(node) }
 func (node *ArrayFlatten) String() string     { return AsString(node) }
 func (node *FuncExpr) String() string         { return AsString(node) }
+func (node *GroupingExpr) String() string     { return AsString(node) }
+func (node *GroupingSets) String() string     { return AsString(node) }
 func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
@@ -28,6 +28,10 @@ type FunctionDefinition struct {
 
 	// FunctionProperties are the properties common to all overloads.
 	FunctionProperties
+
+	// resolvedFrom is the name that a user-defined function was resolved
+	// from. See SemaContext.ResolveFunction.
+	resolvedFrom *UnresolvedName
 }
 
 // FunctionProperties defines the properties of the built-in
@@ -92,6 +96,9 @@ type FunctionProperties struct {
 	// determined without extra context. This is used for formatting builtins
 	// with the FmtParsable directive.
 	AmbiguousReturnType bool
+
+	// UserDefined is set for functions created with CREATE FUNCTION.
+	UserDefined bool
 }
 
 // FunctionClass specifies the class of the builtin function.
@@ -123,8 +130,11 @@ func NewFunctionDefinition(
 			// Builtins with a preferred overload are always ambiguous.
 			props.AmbiguousReturnType = true
 		}
-		// Produce separate telemetry for each overload.
-		def[i].counter = sqltelemetry.BuiltinCounter(name, def[i].Signature(false))
+		// Produce separate telemetry for each overload. The names of user-defined
+		// functions are not reported.
+		if !props.UserDefined {
+			def[i].counter = sqltelemetry.BuiltinCounter(name, def[i].Signature(false))
+		}
 
 		overloads[i] = &def[i]
 	}
@@ -58,6 +58,13 @@ func (node *Insert) Format(ctx *FmtCtx) {
 			ctx.WriteString(" (")
 			ctx.FormatNode(&node.OnConflict.Columns)
 			ctx.WriteString(")")
+			if node.OnConflict.ArbiterPredicate != nil {
+				ctx.WriteString(" WHERE ")
+				ctx.FormatNode(node.OnConflict.ArbiterPredicate)
+			}
+		} else if node.OnConflict.Constraint != "" {
+			ctx.WriteString(" ON CONSTRAINT ")
+			ctx.FormatNode(&node.OnConflict.Constraint)
 		}
 		if node.OnConflict.DoNothing {
 			ctx.WriteString(" DO NOTHING")
@@ -81,20 +88,28 @@ func (node *Insert) DefaultValues() bool {
 	return node.Rows.Select == nil
 }
 
-// OnConflict represents an `ON CONFLICT (columns) DO UPDATE SET exprs WHERE
-// where` clause.
+// OnConflict represents an `ON CONFLICT (columns) WHERE arbiter DO UPDATE SET
+// exprs WHERE where` clause. The conflict target can alternatively be
+// specified as `ON CONFLICT ON CONSTRAINT constraint`.
 //
 // The zero value for OnConflict is used to signal the UPSERT short form, which
 // uses the primary key for as the conflict index and the values being inserted
 // for Exprs.
 type OnConflict struct {
-	Columns   NameList
-	Exprs     UpdateExprs
-	Where     *Where
-	DoNothing bool
+	Columns NameList
+	// ArbiterPredicate is the WHERE clause of the conflict target, which
+	// allows a partial unique index to be used as the conflict index.
+	ArbiterPredicate Expr
+	// Constraint is the name of the unique constraint used as the conflict
+	// index, when specified with ON CONSTRAINT.
+	Constraint Name
+	Exprs      UpdateExprs
+	Where      *Where
+	DoNothing  bool
 }
 
 // IsUpsertAlias returns true if the UPSERT syntactic sugar was used.
 func (oc *OnConflict) IsUpsertAlias() bool {
-	return oc != nil && oc.Columns == nil && oc.Exprs == nil && oc.Where == nil && !oc.DoNothing
+	return oc != nil && oc.Columns == nil && oc.ArbiterPredicate == nil && oc.Constraint == "" &&
+		oc.Exprs == nil && oc.Where == nil && !oc.DoNothing
 }
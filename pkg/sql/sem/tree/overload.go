@@ -51,6 +51,12 @@ type Overload struct {
 	Fn            func(*EvalContext, Datums) (Datum, error)
 	Generator     GeneratorFactory
 
+	// InlineBody is set for user-defined functions whose body is a single
+	// expression without side effects, which the optimizer can substitute for
+	// calls to the function. The placeholders $1, $2, ... in the expression
+	// refer to the arguments of the call.
+	InlineBody Expr
+
 	// counter, if non-nil, should be incremented upon successful
 	// type check of expressions using this overload.
 	counter telemetry.Counter
@@ -24,7 +24,7 @@ import (
 
 var enclosingError = pgerror.Newf(pgerror.CodeInvalidTextRepresentationError, "array must be enclosed in { and }")
 var extraTextError = pgerror.Newf(pgerror.CodeInvalidTextRepresentationError, "extra text after closing right brace")
-var nestedArraysNotSupportedError = pgerror.UnimplementedWithIssueDetail(32552, "strcast", "nested arrays not supported")
+var dimensionMismatchError = pgerror.Newf(pgerror.CodeInvalidTextRepresentationError, "number of array dimensions does not match the array type")
 var malformedError = pgerror.Newf(pgerror.CodeInvalidTextRepresentationError, "malformed array")
 
 var isQuoteChar = func(ch byte) bool {
@@ -115,8 +115,24 @@ func (p *parseState) parseElement() error {
 	r := p.peek()
 	switch r {
 	case '{':
-		return nestedArraysNotSupportedError
+		if p.t.Family() != types.ArrayFamily {
+			return dimensionMismatchError
+		}
+		nested := parseState{
+			s:       p.s,
+			evalCtx: p.evalCtx,
+			result:  NewDArray(p.t.ArrayContents()),
+			t:       p.t.ArrayContents(),
+		}
+		if err := nested.parseArray(); err != nil {
+			return err
+		}
+		p.s = nested.s
+		return p.result.Append(nested.result)
 	case '"':
+		if p.t.Family() == types.ArrayFamily {
+			return dimensionMismatchError
+		}
 		p.advance()
 		next, err = p.parseQuotedString()
 		if err != nil {
@@ -134,6 +150,9 @@ func (p *parseState) parseElement() error {
 		if strings.EqualFold(next, "null") {
 			return p.result.Append(DNull)
 		}
+		if p.t.Family() == types.ArrayFamily {
+			return dimensionMismatchError
+		}
 	}
 
 	d, err := PerformCast(p.evalCtx, NewDString(next), p.t)
@@ -143,8 +162,42 @@ func (p *parseState) parseElement() error {
 	return p.result.Append(d)
 }
 
+// parseArray parses an array enclosed in { and }, consuming the closing
+// brace. Elements that are themselves enclosed in braces are parsed as nested
+// arrays.
+func (p *parseState) parseArray() error {
+	p.eatWhitespace()
+	if p.peek() != '{' {
+		return enclosingError
+	}
+	p.advance()
+	p.eatWhitespace()
+	if p.peek() != '}' {
+		if err := p.parseElement(); err != nil {
+			return err
+		}
+		p.eatWhitespace()
+		for p.peek() == ',' {
+			p.advance()
+			p.eatWhitespace()
+			if err := p.parseElement(); err != nil {
+				return err
+			}
+		}
+	}
+	p.eatWhitespace()
+	if p.eof() {
+		return enclosingError
+	}
+	if p.peek() != '}' {
+		return malformedError
+	}
+	p.advance()
+	return nil
+}
+
 // ParseDArrayFromString parses the string-form of constructing arrays, handling
-// cases such as `'{1,2,3}'::INT[]`.
+// cases such as `'{1,2,3}'::INT[]` and `'{{1,2},{3,4}}'::INT[][]`.
 func ParseDArrayFromString(evalCtx *EvalContext, s string, t *types.T) (*DArray, error) {
 	parser := parseState{
 		s:       s,
@@ -153,33 +206,9 @@ func ParseDArrayFromString(evalCtx *EvalContext, s string, t *types.T) (*DArray,
 		t:       t,
 	}
 
-	parser.eatWhitespace()
-	if parser.peek() != '{' {
-		return nil, enclosingError
-	}
-	parser.advance()
-	parser.eatWhitespace()
-	if parser.peek() != '}' {
-		if err := parser.parseElement(); err != nil {
-			return nil, err
-		}
-		parser.eatWhitespace()
-		for parser.peek() == ',' {
-			parser.advance()
-			parser.eatWhitespace()
-			if err := parser.parseElement(); err != nil {
-				return nil, err
-			}
-		}
-	}
-	parser.eatWhitespace()
-	if parser.eof() {
-		return nil, enclosingError
-	}
-	if parser.peek() != '}' {
-		return nil, malformedError
+	if err := parser.parseArray(); err != nil {
+		return nil, err
 	}
-	parser.advance()
 	parser.eatWhitespace()
 	if !parser.eof() {
 		return nil, extraTextError
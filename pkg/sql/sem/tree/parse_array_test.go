@@ -78,6 +78,10 @@ lo}`, types.String, Datums{NewDString(`hel`), NewDString(`lo`)}},
 		// occur.
 		{string([]byte{'{', 'a', 200, '}'}), types.String, Datums{NewDString("a\xc8")}},
 		{string([]byte{'{', 'a', 200, 'a', '}'}), types.String, Datums{NewDString("a\xc8a")}},
+
+		{`{{1,2},{3,4}}`, types.IntArray, Datums{intArray(1, 2), intArray(3, 4)}},
+		{` { { 1 } , {"2"} } `, types.IntArray, Datums{intArray(1), intArray(2)}},
+		{`{{}}`, types.IntArray, Datums{intArray()}},
 	}
 	for _, td := range testData {
 		t.Run(td.str, func(t *testing.T) {
@@ -99,6 +103,16 @@ lo}`, types.String, Datums{NewDString(`hel`), NewDString(`lo`)}},
 	}
 }
 
+func intArray(ints ...int) *DArray {
+	arr := NewDArray(types.Int)
+	for _, i := range ints {
+		if err := arr.Append(NewDInt(DInt(i))); err != nil {
+			panic(err)
+		}
+	}
+	return arr
+}
+
 const randomArrayIterations = 1000
 const randomArrayMaxLength = 10
 const randomStringMaxLength = 1000
@@ -166,8 +180,12 @@ func TestParseArrayError(t *testing.T) {
 		{`{,}`, types.Int, "malformed array"},
 		{`{}{}`, types.Int, "extra text after closing right brace"},
 		{`{} {}`, types.Int, "extra text after closing right brace"},
-		{`{{}}`, types.Int, "unimplemented: nested arrays not supported"},
-		{`{1, {1}}`, types.Int, "unimplemented: nested arrays not supported"},
+		{`{{}}`, types.Int, "number of array dimensions does not match the array type"},
+		{`{1, {1}}`, types.Int, "number of array dimensions does not match the array type"},
+		{`{1, 2}`, types.IntArray, "number of array dimensions does not match the array type"},
+		{`{{1}, 2}`, types.IntArray, "number of array dimensions does not match the array type"},
+		{`{{1}, NULL}`, types.IntArray, "multidimensional arrays must have array expressions with matching dimensions"},
+		{`{{1}, {1, 2}}`, types.IntArray, "multidimensional arrays must have array expressions with matching dimensions"},
 		{`{hello}`, types.Int, `could not parse "hello" as type int: strconv.ParseInt: parsing "hello": invalid syntax`},
 		{`{"hello}`, types.String, `malformed array`},
 		// It might be unnecessary to disallow this, but Postgres does.
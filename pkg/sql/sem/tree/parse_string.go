@@ -80,6 +80,8 @@ func parseStringAs(t *types.T, s string, ctx ParseTimeContext) (Datum, error) {
 		return NewDString(s), nil
 	case types.TimeFamily:
 		return ParseDTime(ctx, s)
+	case types.TimeTZFamily:
+		return ParseDTimeTZ(ctx, s)
 	case types.TimestampFamily:
 		if t.Precision() == 0 {
 			return ParseDTimestamp(ctx, s, time.Second)
@@ -92,6 +94,12 @@ func parseStringAs(t *types.T, s string, ctx ParseTimeContext) (Datum, error) {
 		return ParseDTimestampTZ(ctx, s, time.Microsecond)
 	case types.UuidFamily:
 		return ParseDUuidFromString(s)
+	case types.EnumFamily:
+		return MakeDEnumFromLogicalRepresentation(t, s)
+	case types.GeometryFamily:
+		return ParseDGeometry(s)
+	case types.GeographyFamily:
+		return ParseDGeography(s)
 	default:
 		return nil, nil
 	}
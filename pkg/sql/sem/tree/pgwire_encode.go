@@ -103,6 +103,9 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 			// double escaped.
 		case *DBytes:
 			ctx.FormatNode(dv)
+			// Nested arrays are printed without quoting, e.g. {{1,2},{3,4}}.
+		case *DArray:
+			dv.pgwireFormat(ctx)
 		default:
 			s := AsStringWithFlags(v, ctx.flags)
 			pgwireFormatStringInArray(&ctx.Buffer, s)
@@ -541,6 +541,9 @@ func (node *Select) docTable(p *PrettyCfg) []pretty.TableRow {
 	}
 	items = append(items, node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
+	for _, l := range node.Locking {
+		items = append(items, p.row("", p.Doc(l)))
+	}
 	return items
 }
 
@@ -622,7 +625,11 @@ func (node *With) docRow(p *PrettyCfg) pretty.TableRow {
 			p.bracketKeyword("AS", " (", p.Doc(cte.Stmt), ")", ""),
 		)
 	}
-	return p.row("WITH", p.commaSeparated(d...))
+	kw := "WITH"
+	if node.Recursive {
+		kw = "WITH RECURSIVE"
+	}
+	return p.row(kw, p.commaSeparated(d...))
 }
 
 func (node *Subquery) doc(p *PrettyCfg) pretty.Doc {
@@ -868,6 +875,12 @@ func (node *Insert) doc(p *PrettyCfg) pretty.Doc {
 		cond := pretty.Nil
 		if len(node.OnConflict.Columns) > 0 {
 			cond = p.bracket("(", p.Doc(&node.OnConflict.Columns), ")")
+			if node.OnConflict.ArbiterPredicate != nil {
+				cond = p.nestUnder(cond,
+					p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.OnConflict.ArbiterPredicate)))
+			}
+		} else if node.OnConflict.Constraint != "" {
+			cond = p.nestUnder(pretty.Keyword("ON CONSTRAINT"), p.Doc(&node.OnConflict.Constraint))
 		}
 		items = append(items, p.row("ON CONFLICT", cond))
 
@@ -1018,11 +1031,15 @@ func (p *PrettyCfg) exprDocWithParen(e Expr) pretty.Doc {
 }
 
 func (node *Update) doc(p *PrettyCfg) pretty.Doc {
-	items := make([]pretty.TableRow, 8)
+	items := make([]pretty.TableRow, 9)
 	items = append(items,
 		node.With.docRow(p),
 		p.row("UPDATE", p.Doc(node.Table)),
-		p.row("SET", p.Doc(&node.Exprs)),
+		p.row("SET", p.Doc(&node.Exprs)))
+	if len(node.From) > 0 {
+		items = append(items, p.row("FROM", node.From.doc(p)))
+	}
+	items = append(items,
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
@@ -1031,10 +1048,14 @@ func (node *Update) doc(p *PrettyCfg) pretty.Doc {
 }
 
 func (node *Delete) doc(p *PrettyCfg) pretty.Doc {
-	items := make([]pretty.TableRow, 6)
+	items := make([]pretty.TableRow, 7)
 	items = append(items,
 		node.With.docRow(p),
-		p.row("DELETE FROM", p.Doc(node.Table)),
+		p.row("DELETE FROM", p.Doc(node.Table)))
+	if len(node.Using) > 0 {
+		items = append(items, p.row("USING", node.Using.doc(p)))
+	}
+	items = append(items,
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
@@ -1097,12 +1118,16 @@ func (node *UpdateExpr) doc(p *PrettyCfg) pretty.Doc {
 func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	// Final layout:
 	//
-	// CREATE TABLE [IF NOT EXISTS] name ( .... ) [AS]
+	// CREATE [TEMPORARY] TABLE [IF NOT EXISTS] name ( .... ) [AS]
 	//     [SELECT ...] - for CREATE TABLE AS
 	//     [INTERLEAVE ...]
 	//     [PARTITION BY ...]
 	//
-	title := pretty.Keyword("CREATE TABLE")
+	title := pretty.Keyword("CREATE")
+	if node.Temporary {
+		title = pretty.ConcatSpace(title, pretty.Keyword("TEMPORARY"))
+	}
+	title = pretty.ConcatSpace(title, pretty.Keyword("TABLE"))
 	if node.IfNotExists {
 		title = pretty.ConcatSpace(title, pretty.Keyword("IF NOT EXISTS"))
 	}
@@ -1139,11 +1164,15 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	// Final layout:
 	//
-	// CREATE VIEW name ( ... ) AS
+	// CREATE [MATERIALIZED] VIEW name ( ... ) AS
 	//     SELECT ...
 	//
+	title := "CREATE VIEW"
+	if node.Materialized {
+		title = "CREATE MATERIALIZED VIEW"
+	}
 	d := pretty.ConcatSpace(
-		pretty.Keyword("CREATE VIEW"),
+		pretty.Keyword(title),
 		p.Doc(&node.Name),
 	)
 	if len(node.ColumnNames) > 0 {
@@ -1380,6 +1409,7 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [WHERE ...]
 	//
 	title := make([]pretty.Doc, 0, 6)
 	title = append(title, pretty.Keyword("CREATE"))
@@ -1416,6 +1446,9 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		clauses = append(clauses, p.Doc(node.PartitionBy))
 	}
+	if node.Predicate != nil {
+		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
+	}
 	return p.nestUnder(
 		pretty.Fold(pretty.ConcatSpace, title...),
 		pretty.Group(pretty.Stack(clauses...)))
@@ -1457,6 +1490,7 @@ func (node *IndexTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [WHERE ...]
 	//
 	title := pretty.Keyword("INDEX")
 	if node.Name != "" {
@@ -1480,6 +1514,9 @@ func (node *IndexTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		clauses = append(clauses, p.Doc(node.PartitionBy))
 	}
+	if node.Predicate != nil {
+		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
+	}
 
 	if len(clauses) == 0 {
 		return title
@@ -1494,6 +1531,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [WHERE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1501,6 +1539,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [WHERE ...]
 	//
 	clauses := make([]pretty.Doc, 0, 4)
 	var title pretty.Doc
@@ -1526,6 +1565,12 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		clauses = append(clauses, p.Doc(node.PartitionBy))
 	}
+	if node.Predicate != nil {
+		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
+	}
+	if node.Deferrable != NotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrable.String()))
+	}
 
 	if len(clauses) == 0 {
 		return title
@@ -1573,6 +1618,10 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	if node.Deferrable != NotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrable.String()))
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
@@ -1612,8 +1661,12 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 
 	// Compute expression (for computed columns).
 	if node.IsComputed() {
+		storage := ") STORED"
+		if node.Computed.Virtual {
+			storage = ") VIRTUAL"
+		}
 		clauses = append(clauses, pretty.ConcatSpace(pretty.Keyword("AS"),
-			p.bracket("(", p.Doc(node.Computed.Expr), ") STORED"),
+			p.bracket("(", p.Doc(node.Computed.Expr), storage),
 		))
 	}
 
@@ -1682,6 +1735,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if node.References.Deferrable != NotDeferrable {
+			fkDetails = append(fkDetails, pretty.Keyword(node.References.Deferrable.String()))
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -1761,7 +1817,7 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 
 	items = append(items, p.row("RESTORE", pretty.Nil))
 	items = append(items, node.Targets.docRow(p))
-	items = append(items, p.row("FROM", p.Doc(&node.From)))
+	items = append(items, p.row("FROM", node.From.doc(p)))
 
 	if node.AsOf.Expr != nil {
 		items = append(items, node.AsOf.docRow(p))
@@ -1897,6 +1953,22 @@ func (node *CoalesceExpr) doc(p *PrettyCfg) pretty.Doc {
 	)
 }
 
+func (node *GroupingExpr) doc(p *PrettyCfg) pretty.Doc {
+	return p.bracketKeyword(
+		"GROUPING", "(",
+		p.Doc(&node.Exprs),
+		")", "",
+	)
+}
+
+func (node *GroupingSets) doc(p *PrettyCfg) pretty.Doc {
+	return p.bracketKeyword(
+		node.Type.String(), " (",
+		p.Doc(&node.Exprs),
+		")", "",
+	)
+}
+
 func (node *AlterTable) doc(p *PrettyCfg) pretty.Doc {
 	title := pretty.Keyword("ALTER TABLE")
 	if node.IfExists {
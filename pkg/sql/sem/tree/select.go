@@ -46,6 +46,7 @@ type Select struct {
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
+	Locking LockingClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -60,6 +61,7 @@ func (node *Select) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	ctx.FormatNode(&node.Locking)
 }
 
 // ParenSelect represents a parenthesized SELECT/UNION/VALUES statement.
@@ -568,6 +570,51 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetsType is the kind of a GroupingSets item.
+type GroupingSetsType int
+
+// GroupingSetsType values.
+const (
+	// GroupingSetsExplicit is GROUPING SETS (...).
+	GroupingSetsExplicit GroupingSetsType = iota
+	// GroupingSetsRollup is ROLLUP (...).
+	GroupingSetsRollup
+	// GroupingSetsCube is CUBE (...).
+	GroupingSetsCube
+)
+
+var groupingSetsTypeName = [...]string{
+	GroupingSetsExplicit: "GROUPING SETS",
+	GroupingSetsRollup:   "ROLLUP",
+	GroupingSetsCube:     "CUBE",
+}
+
+func (t GroupingSetsType) String() string {
+	if t < 0 || t > GroupingSetsType(len(groupingSetsTypeName)-1) {
+		return fmt.Sprintf("GroupingSetsType(%d)", t)
+	}
+	return groupingSetsTypeName[t]
+}
+
+// GroupingSets represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. For ROLLUP and CUBE, each element of Exprs is a grouping
+// expression, or a Tuple of expressions that are grouped as a unit. For
+// GROUPING SETS, each element is a grouping set: an expression, a Tuple of
+// expressions (the empty Tuple denotes the empty grouping set) or a nested
+// GroupingSets item.
+type GroupingSets struct {
+	Type  GroupingSetsType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSets) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -678,6 +725,123 @@ func (node *Limit) Format(ctx *FmtCtx) {
 	}
 }
 
+// LockingClause represents a locking clause, like FOR UPDATE.
+type LockingClause []*LockingItem
+
+// Format implements the NodeFormatter interface.
+func (node *LockingClause) Format(ctx *FmtCtx) {
+	for _, n := range *node {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(n)
+	}
+}
+
+// LockingItem represents a single locking item in a locking clause.
+type LockingItem struct {
+	Strength   LockingStrength
+	Targets    TableNames
+	WaitPolicy LockingWaitPolicy
+}
+
+// Format implements the NodeFormatter interface.
+func (node *LockingItem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.Strength)
+	if len(node.Targets) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Targets)
+	}
+	ctx.FormatNode(node.WaitPolicy)
+}
+
+// LockingStrength represents the possible row-level lock modes for a SELECT
+// statement.
+type LockingStrength byte
+
+// The ordering of the variants is important, because the highest numerical
+// value takes precedence when row-level locking is specified multiple ways.
+const (
+	// ForNone represents the default - no FOR clause at all. LockingItem AST
+	// nodes are never created with this strength.
+	ForNone LockingStrength = iota
+	// ForKeyShare represents FOR KEY SHARE.
+	ForKeyShare
+	// ForShare represents FOR SHARE.
+	ForShare
+	// ForNoKeyUpdate represents FOR NO KEY UPDATE.
+	ForNoKeyUpdate
+	// ForUpdate represents FOR UPDATE.
+	ForUpdate
+)
+
+var lockingStrengthName = [...]string{
+	ForNone:        "",
+	ForKeyShare:    "FOR KEY SHARE",
+	ForShare:       "FOR SHARE",
+	ForNoKeyUpdate: "FOR NO KEY UPDATE",
+	ForUpdate:      "FOR UPDATE",
+}
+
+func (s LockingStrength) String() string {
+	return lockingStrengthName[s]
+}
+
+// Format implements the NodeFormatter interface.
+func (s LockingStrength) Format(ctx *FmtCtx) {
+	ctx.WriteString(s.String())
+}
+
+// Max returns the stronger of the two locking strengths.
+func (s LockingStrength) Max(s2 LockingStrength) LockingStrength {
+	if s2 > s {
+		return s2
+	}
+	return s
+}
+
+// LockingWaitPolicy represents the possible policies for dealing with rows
+// that are locked by other transactions when acquiring row-level locks (i.e.,
+// it represents the NOWAIT and SKIP LOCKED options).
+type LockingWaitPolicy byte
+
+// The ordering of the variants is important, because the highest numerical
+// value takes precedence when the wait policy is specified multiple ways.
+const (
+	// LockWaitBlock represents the default - wait for the lock to become
+	// available.
+	LockWaitBlock LockingWaitPolicy = iota
+	// LockWaitSkip represents SKIP LOCKED - skip rows that can't be locked.
+	LockWaitSkip
+	// LockWaitError represents NOWAIT - raise an error if a row can't be
+	// locked.
+	LockWaitError
+)
+
+var lockingWaitPolicyName = [...]string{
+	LockWaitBlock: "",
+	LockWaitSkip:  "SKIP LOCKED",
+	LockWaitError: "NOWAIT",
+}
+
+func (p LockingWaitPolicy) String() string {
+	return lockingWaitPolicyName[p]
+}
+
+// Format implements the NodeFormatter interface.
+func (p LockingWaitPolicy) Format(ctx *FmtCtx) {
+	if p != LockWaitBlock {
+		ctx.WriteByte(' ')
+		ctx.WriteString(p.String())
+	}
+}
+
+// Max returns the more restrictive of the two wait policies.
+func (p LockingWaitPolicy) Max(p2 LockingWaitPolicy) LockingWaitPolicy {
+	if p2 > p {
+		return p2
+	}
+	return p
+}
+
 // RowsFromExpr represents a ROWS FROM(...) expression.
 type RowsFromExpr struct {
 	Items Exprs
@@ -76,6 +76,30 @@ func (node *SetTransaction) Format(ctx *FmtCtx) {
 	node.Modes.Format(ctx)
 }
 
+// SetConstraints represents a SET CONSTRAINTS statement.
+type SetConstraints struct {
+	// Names are the names of the constraints to set. All the deferrable
+	// constraints are set if it is empty.
+	Names    NameList
+	Deferred bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *SetConstraints) Format(ctx *FmtCtx) {
+	ctx.WriteString("SET CONSTRAINTS ")
+	if len(node.Names) == 0 {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Names)
+	}
+	ctx.WriteByte(' ')
+	if node.Deferred {
+		ctx.WriteString("DEFERRED")
+	} else {
+		ctx.WriteString("IMMEDIATE")
+	}
+}
+
 // SetSessionCharacteristics represents a SET SESSION CHARACTERISTICS AS TRANSACTION statement.
 type SetSessionCharacteristics struct {
 	Modes TransactionModes
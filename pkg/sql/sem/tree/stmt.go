@@ -193,6 +193,12 @@ func (*AlterSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*AlterSequence) StatementTag() string { return "ALTER SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*AlterType) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterType) StatementTag() string { return "ALTER TYPE" }
+
 // StatementType implements the Statement interface.
 func (*AlterUserSetPassword) StatementType() StatementType { return RowsAffected }
 
@@ -337,7 +343,30 @@ func (*CreateRole) hiddenFromShowQueries() {}
 func (*CreateView) StatementType() StatementType { return DDL }
 
 // StatementTag returns a short string identifying the type of statement.
-func (*CreateView) StatementTag() string { return "CREATE VIEW" }
+func (n *CreateView) StatementTag() string {
+	if n.Materialized {
+		return "CREATE MATERIALIZED VIEW"
+	}
+	return "CREATE VIEW"
+}
+
+// StatementType implements the Statement interface.
+func (*CreateSchema) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateSchema) StatementTag() string { return "CREATE SCHEMA" }
+
+// StatementType implements the Statement interface.
+func (*CreateFunction) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateFunction) StatementTag() string { return "CREATE FUNCTION" }
+
+// StatementType implements the Statement interface.
+func (*CreateTrigger) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateTrigger) StatementTag() string { return "CREATE TRIGGER" }
 
 // StatementType implements the Statement interface.
 func (*CreateSequence) StatementType() StatementType { return DDL }
@@ -345,6 +374,18 @@ func (*CreateSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateSequence) StatementTag() string { return "CREATE SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*CreateDomain) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateDomain) StatementTag() string { return "CREATE DOMAIN" }
+
+// StatementType implements the Statement interface.
+func (*CreateType) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateType) StatementTag() string { return "CREATE TYPE" }
+
 // StatementType implements the Statement interface.
 func (*CreateStats) StatementType() StatementType { return DDL }
 
@@ -397,7 +438,30 @@ func (*DropTable) StatementTag() string { return "DROP TABLE" }
 func (*DropView) StatementType() StatementType { return DDL }
 
 // StatementTag returns a short string identifying the type of statement.
-func (*DropView) StatementTag() string { return "DROP VIEW" }
+func (n *DropView) StatementTag() string {
+	if n.IsMaterialized {
+		return "DROP MATERIALIZED VIEW"
+	}
+	return "DROP VIEW"
+}
+
+// StatementType implements the Statement interface.
+func (*DropSchema) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropSchema) StatementTag() string { return "DROP SCHEMA" }
+
+// StatementType implements the Statement interface.
+func (*DropFunction) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropFunction) StatementTag() string { return "DROP FUNCTION" }
+
+// StatementType implements the Statement interface.
+func (*DropTrigger) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropTrigger) StatementTag() string { return "DROP TRIGGER" }
 
 // StatementType implements the Statement interface.
 func (*DropSequence) StatementType() StatementType { return DDL }
@@ -405,6 +469,18 @@ func (*DropSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropSequence) StatementTag() string { return "DROP SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*DropDomain) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropDomain) StatementTag() string { return "DROP DOMAIN" }
+
+// StatementType implements the Statement interface.
+func (*DropType) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropType) StatementTag() string { return "DROP TYPE" }
+
 // StatementType implements the Statement interface.
 func (*DropUser) StatementType() StatementType { return RowsAffected }
 
@@ -512,6 +588,12 @@ func (n *RenameTable) StatementTag() string {
 	return "RENAME TABLE"
 }
 
+// StatementType implements the Statement interface.
+func (*RefreshMaterializedView) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RefreshMaterializedView) StatementTag() string { return "REFRESH MATERIALIZED VIEW" }
+
 // StatementType implements the Statement interface.
 func (*Relocate) StatementType() StatementType { return Rows }
 
@@ -607,6 +689,12 @@ func (*SetTransaction) StatementType() StatementType { return Ack }
 // StatementTag returns a short string identifying the type of statement.
 func (*SetTransaction) StatementTag() string { return "SET TRANSACTION" }
 
+// StatementType implements the Statement interface.
+func (*SetConstraints) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*SetConstraints) StatementTag() string { return "SET CONSTRAINTS" }
+
 // StatementType implements the Statement interface.
 func (*SetTracing) StatementType() StatementType { return Ack }
 
@@ -852,6 +940,7 @@ func (n *AlterTableDropStored) String() string      { return AsString(n) }
 func (n *AlterTableSetDefault) String() string      { return AsString(n) }
 func (n *AlterUserSetPassword) String() string      { return AsString(n) }
 func (n *AlterSequence) String() string             { return AsString(n) }
+func (n *AlterType) String() string                 { return AsString(n) }
 func (n *Backup) String() string                    { return AsString(n) }
 func (n *BeginTransaction) String() string          { return AsString(n) }
 func (n *ControlJobs) String() string               { return AsString(n) }
@@ -866,20 +955,30 @@ func (n *CopyFrom) String() string                  { return AsString(n) }
 func (n *CreateChangefeed) String() string          { return AsString(n) }
 func (n *CreateDatabase) String() string            { return AsString(n) }
 func (n *CreateIndex) String() string               { return AsString(n) }
+func (n *CreateDomain) String() string              { return AsString(n) }
+func (n *CreateFunction) String() string            { return AsString(n) }
 func (n *CreateRole) String() string                { return AsString(n) }
 func (n *CreateTable) String() string               { return AsString(n) }
+func (n *CreateSchema) String() string              { return AsString(n) }
 func (n *CreateSequence) String() string            { return AsString(n) }
 func (n *CreateStats) String() string               { return AsString(n) }
+func (n *CreateTrigger) String() string             { return AsString(n) }
+func (n *CreateType) String() string                { return AsString(n) }
 func (n *CreateUser) String() string                { return AsString(n) }
 func (n *CreateView) String() string                { return AsString(n) }
 func (n *Deallocate) String() string                { return AsString(n) }
 func (n *Delete) String() string                    { return AsString(n) }
 func (n *DropDatabase) String() string              { return AsString(n) }
+func (n *DropDomain) String() string                { return AsString(n) }
 func (n *DropIndex) String() string                 { return AsString(n) }
+func (n *DropFunction) String() string              { return AsString(n) }
 func (n *DropRole) String() string                  { return AsString(n) }
 func (n *DropTable) String() string                 { return AsString(n) }
 func (n *DropView) String() string                  { return AsString(n) }
+func (n *DropSchema) String() string                { return AsString(n) }
+func (n *DropTrigger) String() string               { return AsString(n) }
 func (n *DropSequence) String() string              { return AsString(n) }
+func (n *DropType) String() string                  { return AsString(n) }
 func (n *DropUser) String() string                  { return AsString(n) }
 func (n *Execute) String() string                   { return AsString(n) }
 func (n *Explain) String() string                   { return AsString(n) }
@@ -891,6 +990,7 @@ func (n *Import) String() string                    { return AsString(n) }
 func (n *ParenSelect) String() string               { return AsString(n) }
 func (n *Prepare) String() string                   { return AsString(n) }
 func (n *ReleaseSavepoint) String() string          { return AsString(n) }
+func (n *RefreshMaterializedView) String() string   { return AsString(n) }
 func (n *Relocate) String() string                  { return AsString(n) }
 func (n *RenameColumn) String() string              { return AsString(n) }
 func (n *RenameDatabase) String() string            { return AsString(n) }
@@ -907,6 +1007,7 @@ func (n *Scrub) String() string                     { return AsString(n) }
 func (n *Select) String() string                    { return AsString(n) }
 func (n *SelectClause) String() string              { return AsString(n) }
 func (n *SetClusterSetting) String() string         { return AsString(n) }
+func (n *SetConstraints) String() string            { return AsString(n) }
 func (n *SetZoneConfig) String() string             { return AsString(n) }
 func (n *SetSessionCharacteristics) String() string { return AsString(n) }
 func (n *SetTransaction) String() string            { return AsString(n) }
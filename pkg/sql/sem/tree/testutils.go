@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 )
@@ -61,6 +62,8 @@ func SampleDatum(t *types.T) Datum {
 		return NewDDate(pgdate.MakeCompatibleDateFromDisk(123123))
 	case types.TimeFamily:
 		return MakeDTime(timeofday.FromInt(789))
+	case types.TimeTZFamily:
+		return MakeDTimeTZ(timetz.MakeTimeTZ(timeofday.FromInt(345), 5*60*60 /* offsetSecs */))
 	case types.TimestampFamily:
 		return MakeDTimestamp(timeutil.Unix(123, 123), time.Second)
 	case types.TimestampTZFamily:
@@ -79,6 +82,12 @@ func SampleDatum(t *types.T) Datum {
 		return j
 	case types.OidFamily:
 		return NewDOid(DInt(1009))
+	case types.GeometryFamily:
+		g, _ := ParseDGeometry("POINT(1 2)")
+		return g
+	case types.GeographyFamily:
+		g, _ := ParseDGeography("POINT(1 2)")
+		return g
 	default:
 		panic(fmt.Sprintf("SampleDatum not implemented for %s", t))
 	}
@@ -59,9 +59,45 @@ type SemaContext struct {
 	// globally for the entire txn and this field would not be needed.
 	AsOfTimestamp *hlc.Timestamp
 
+	// TypeResolver is used to resolve references to user-defined types. It can
+	// be nil, in which case such references cannot be type checked.
+	TypeResolver TypeReferenceResolver
+
+	// FunctionResolver is used to resolve references to user-defined
+	// functions. It can be nil, in which case only builtin functions can be
+	// referenced.
+	FunctionResolver FunctionReferenceResolver
+
 	Properties SemaProperties
 }
 
+// FunctionReferenceResolver resolves references to user-defined functions.
+type FunctionReferenceResolver interface {
+	// ResolveFunctionByName returns the definition of the user-defined function
+	// with the given name, or an error if there is no such function.
+	ResolveFunctionByName(name *UnresolvedName) (*FunctionDefinition, error)
+}
+
+// TypeReferenceResolver resolves references to user-defined types.
+type TypeReferenceResolver interface {
+	// ResolveTypeByName returns the type with the given name parts, or an error
+	// if there is no such type.
+	ResolveTypeByName(nameParts []string) (*types.T, error)
+}
+
+// ResolveType resolves typ if it is a reference to a user-defined type (see
+// types.MakeUnresolvedTypeReference). Other types are returned unchanged.
+func ResolveType(typ *types.T, resolver TypeReferenceResolver) (*types.T, error) {
+	if !typ.IsUnresolved() {
+		return typ, nil
+	}
+	if resolver == nil {
+		return nil, pgerror.Newf(pgerror.CodeUndefinedObjectError,
+			"type %q does not exist", typ.Name())
+	}
+	return resolver.ResolveTypeByName(typ.UnresolvedName())
+}
+
 // SemaProperties is a holder for required and derived properties
 // during semantic analysis. It provides scoping semantics via its
 // Restore() method, see below.
@@ -207,6 +243,56 @@ func (sc *SemaContext) isUnresolvedPlaceholder(expr Expr) bool {
 	return sc.Placeholders.IsUnresolvedPlaceholder(expr)
 }
 
+// typeResolver provides a nil-safe method to access the TypeResolver.
+func (sc *SemaContext) typeResolver() TypeReferenceResolver {
+	if sc == nil {
+		return nil
+	}
+	return sc.TypeResolver
+}
+
+// ResolveFunction resolves the function reference fn like
+// ResolvableFunctionReference.Resolve does, using the search path of the
+// context. Names that do not refer to builtin functions are looked up with the
+// FunctionResolver, if any. Builtin functions cannot be shadowed by
+// user-defined functions.
+//
+// References to user-defined functions are always resolved again, since a
+// syntax tree can be reused, e.g. by a prepared statement, after the function
+// was replaced or dropped.
+func (sc *SemaContext) ResolveFunction(fn *ResolvableFunctionReference) (*FunctionDefinition, error) {
+	var searchPath sessiondata.SearchPath
+	if sc != nil {
+		searchPath = sc.SearchPath
+		if def, ok := fn.FunctionReference.(*FunctionDefinition); ok &&
+			def.resolvedFrom != nil && sc.FunctionResolver != nil {
+			fn.FunctionReference = def.resolvedFrom
+		}
+	}
+	def, err := fn.Resolve(searchPath)
+	if err == nil || sc == nil || sc.FunctionResolver == nil {
+		return def, err
+	}
+	name, ok := fn.FunctionReference.(*UnresolvedName)
+	if !ok {
+		return nil, err
+	}
+	if pgErr, ok := pgerror.GetPGCause(err); !ok || pgErr.Code != pgerror.CodeUndefinedFunctionError {
+		return nil, err
+	}
+	udf, udfErr := sc.FunctionResolver.ResolveFunctionByName(name)
+	if udfErr != nil {
+		if pgErr, ok := pgerror.GetPGCause(udfErr); ok && pgErr.Code == pgerror.CodeUndefinedFunctionError {
+			// Report the original error, which may suggest a builtin function.
+			return nil, err
+		}
+		return nil, udfErr
+	}
+	udf.resolvedFrom = name
+	fn.FunctionReference = udf
+	return udf, nil
+}
+
 // GetLocation returns the session timezone.
 func (sc *SemaContext) GetLocation() *time.Location {
 	if sc == nil || sc.Location == nil || *sc.Location == nil {
@@ -437,6 +523,14 @@ func isEmptyArray(expr Expr) bool {
 
 // TypeCheck implements the Expr interface.
 func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ *types.T) (TypedExpr, error) {
+	if expr.Type.IsUnresolved() {
+		typ, err := ResolveType(expr.Type, ctx.typeResolver())
+		if err != nil {
+			return nil, err
+		}
+		expr.Type = typ
+	}
+
 	// The desired type provided to a CastExpr is ignored. Instead,
 	// types.Any is passed to the child of the cast. There are two
 	// exceptions, described below.
@@ -450,11 +544,14 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ *types.T) (TypedExpr, error)
 			desired = expr.Type
 
 			// If the type doesn't have any possible parameters (like length,
-			// precision), the CastExpr becomes a no-op and can be elided.
+			// precision), the CastExpr becomes a no-op and can be elided, unless
+			// the constraints of a domain must be checked.
 			switch expr.Type.Family() {
-			case types.BoolFamily, types.DateFamily, types.TimeFamily, types.TimestampFamily, types.TimestampTZFamily,
-				types.IntervalFamily, types.BytesFamily:
-				return expr.Expr.TypeCheck(ctx, expr.Type)
+			case types.BoolFamily, types.DateFamily, types.TimeFamily, types.TimeTZFamily, types.TimestampFamily,
+				types.TimestampTZFamily, types.IntervalFamily, types.BytesFamily:
+				if expr.Type.DomainID() == 0 {
+					return expr.Expr.TypeCheck(ctx, expr.Type)
+				}
 			}
 		}
 	case ctx.isUnresolvedPlaceholder(expr.Expr):
@@ -492,31 +589,34 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ *types.T) (TypedExpr, error)
 
 // TypeCheck implements the Expr interface.
 func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	for i, t := range expr.Indirection {
+	// Each subscript selects an element of the next dimension of the array.
+	desiredArray := desired
+	for _, t := range expr.Indirection {
 		if t.Slice {
 			return nil, pgerror.UnimplementedWithIssuef(32551, "ARRAY slicing in %s", expr)
 		}
-		if i > 0 {
-			return nil, pgerror.UnimplementedWithIssueDetailf(32552, "ind", "multidimensional indexing: %s", expr)
-		}
 
 		beginExpr, err := typeCheckAndRequire(ctx, t.Begin, types.Int, "ARRAY subscript")
 		if err != nil {
 			return nil, err
 		}
 		t.Begin = beginExpr
+		desiredArray = types.MakeArray(desiredArray)
 	}
 
-	subExpr, err := expr.Expr.TypeCheck(ctx, types.MakeArray(desired))
+	subExpr, err := expr.Expr.TypeCheck(ctx, desiredArray)
 	if err != nil {
 		return nil, err
 	}
 	typ := subExpr.ResolvedType()
-	if typ.Family() != types.ArrayFamily {
-		return nil, pgerror.Newf(pgerror.CodeDatatypeMismatchError, "cannot subscript type %s because it is not an array", typ)
+	for range expr.Indirection {
+		if typ.Family() != types.ArrayFamily {
+			return nil, pgerror.Newf(pgerror.CodeDatatypeMismatchError, "cannot subscript type %s because it is not an array", typ)
+		}
+		typ = typ.ArrayContents()
 	}
 	expr.Expr = subExpr
-	expr.typ = typ.ArrayContents()
+	expr.typ = typ
 
 	telemetry.Inc(sqltelemetry.ArraySubscriptCounter)
 	return expr, nil
@@ -524,6 +624,13 @@ func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired *types.T) (Type
 
 // TypeCheck implements the Expr interface.
 func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
+	if expr.Type.IsUnresolved() {
+		typ, err := ResolveType(expr.Type, ctx.typeResolver())
+		if err != nil {
+			return nil, err
+		}
+		expr.Type = typ
+	}
 	subExpr, err := typeCheckAndRequire(ctx, expr.Expr, expr.Type,
 		fmt.Sprintf("type annotation for %v as %s, found", expr.Expr, expr.Type))
 	if err != nil {
@@ -644,6 +751,40 @@ func (expr *CoalesceExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedEx
 	return expr, nil
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
+	if ctx != nil {
+		if ctx.Properties.Derived.inFuncExpr &&
+			ctx.Properties.required.rejectFlags&RejectNestedAggregates != 0 {
+			return nil, pgerror.Newf(pgerror.CodeGroupingError,
+				"aggregate function calls cannot contain grouping operations")
+		}
+		if ctx.Properties.required.rejectFlags&RejectAggregates != 0 {
+			return nil, pgerror.Newf(pgerror.CodeGroupingError,
+				"grouping operations are not allowed in %s", ctx.Properties.required.context)
+		}
+	}
+	if len(expr.Exprs) > MaxGroupingArgs {
+		return nil, pgerror.Newf(pgerror.CodeTooManyArgumentsError,
+			"GROUPING must have fewer than %d arguments", MaxGroupingArgs+1)
+	}
+	for i := range expr.Exprs {
+		typedExpr, err := expr.Exprs[i].TypeCheck(ctx, types.Any)
+		if err != nil {
+			return nil, err
+		}
+		expr.Exprs[i] = typedExpr
+	}
+	expr.typ = types.Int
+	return expr, nil
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSets) TypeCheck(_ *SemaContext, desired *types.T) (TypedExpr, error) {
+	return nil, pgerror.Newf(pgerror.CodeSyntaxError,
+		"%s is only allowed in GROUP BY", expr.Type)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *ComparisonExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
 	var leftTyped, rightTyped TypedExpr
@@ -806,11 +947,7 @@ func CheckIsWindowOrAgg(def *FunctionDefinition) error {
 
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	var searchPath sessiondata.SearchPath
-	if ctx != nil {
-		searchPath = ctx.SearchPath
-	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := ctx.ResolveFunction(&expr.Func)
 	if err != nil {
 		return nil, err
 	}
@@ -1442,6 +1579,10 @@ func (d *DDate) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { retur
 // identity function for Datum.
 func (d *DTime) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTimeTZ) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTimestamp) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
@@ -1489,6 +1630,18 @@ func (d *DArray) TypeCheck(_ *SemaContext, desired *types.T) (TypedExpr, error)
 // identity function for Datum.
 func (d *DOid) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DEnum) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DGeometry) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DGeography) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DOidWrapper) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
@@ -1807,9 +1960,11 @@ func typeCheckComparisonOp(
 	// or if it found an ambiguity.
 	collationMismatch :=
 		leftReturn.Family() == types.CollatedStringFamily && !leftReturn.Equivalent(rightReturn)
-	if len(fns) != 1 || collationMismatch {
+	enumMismatch :=
+		leftReturn.Family() == types.EnumFamily && !leftReturn.Equivalent(rightReturn)
+	if len(fns) != 1 || collationMismatch || enumMismatch {
 		sig := fmt.Sprintf(compSignatureFmt, leftReturn, op, rightReturn)
-		if len(fns) == 0 || collationMismatch {
+		if len(fns) == 0 || collationMismatch || enumMismatch {
 			return nil, nil, nil, false,
 				pgerror.Newf(pgerror.CodeInvalidParameterValueError, unsupportedCompErrFmt, sig)
 		}
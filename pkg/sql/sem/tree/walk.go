@@ -187,6 +187,36 @@ func (expr *CoalesceExpr) Walk(v Visitor) Expr {
 	return ret
 }
 
+// copyNode makes a copy of this Expr without recursing in any child Exprs.
+func (expr *GroupingExpr) copyNode() *GroupingExpr {
+	exprCopy := *expr
+	return &exprCopy
+}
+
+// Walk implements the Expr interface.
+func (expr *GroupingExpr) Walk(v Visitor) Expr {
+	ret := expr
+	exprs, changed := walkExprSlice(v, expr.Exprs)
+	if changed {
+		if ret == expr {
+			ret = expr.copyNode()
+		}
+		ret.Exprs = exprs
+	}
+	return ret
+}
+
+// Walk implements the Expr interface.
+func (expr *GroupingSets) Walk(v Visitor) Expr {
+	exprs, changed := walkExprSlice(v, expr.Exprs)
+	if changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr *ComparisonExpr) Walk(v Visitor) Expr {
 	left, changedL := WalkExpr(v, expr.Left)
@@ -620,6 +650,9 @@ func (expr *DDate) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DTime) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DTimeTZ) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DFloat) Walk(_ Visitor) Expr { return expr }
 
@@ -665,6 +698,15 @@ func (expr *DArray) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DOid) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DEnum) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DGeometry) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DGeography) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DOidWrapper) Walk(_ Visitor) Expr { return expr }
 
@@ -723,6 +765,25 @@ func walkReturningClause(v Visitor, clause ReturningClause) (ReturningClause, bo
 	}
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *RefreshMaterializedView) copyNode() *RefreshMaterializedView {
+	stmtCopy := *stmt
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *RefreshMaterializedView) walkStmt(v Visitor) Statement {
+	ret := stmt
+	if stmt.AsOf.Expr != nil {
+		e, changed := WalkExpr(v, stmt.AsOf.Expr)
+		if changed {
+			ret = stmt.copyNode()
+			ret.AsOf.Expr = e
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *Backup) copyNode() *Backup {
 	stmtCopy := *stmt
@@ -1344,6 +1405,7 @@ var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Insert{}
 var _ walkableStmt = &Import{}
 var _ walkableStmt = &ParenSelect{}
+var _ walkableStmt = &RefreshMaterializedView{}
 var _ walkableStmt = &Restore{}
 var _ walkableStmt = &Select{}
 var _ walkableStmt = &SelectClause{}
@@ -1410,6 +1472,18 @@ func SimpleVisit(expr Expr, preFn SimpleVisitFn) (Expr, error) {
 	return newExpr, nil
 }
 
+// SimpleStmtVisit is like SimpleVisit, but visits the expressions of a
+// statement. See the note on walkStmt about which parts of a statement are
+// traversed.
+func SimpleStmtVisit(stmt Statement, preFn SimpleVisitFn) (Statement, error) {
+	v := simpleVisitor{fn: preFn}
+	newStmt, _ := walkStmt(&v, stmt)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return newStmt, nil
+}
+
 type debugVisitor struct {
 	buf   bytes.Buffer
 	level int
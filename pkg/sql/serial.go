@@ -104,6 +104,13 @@ func (p *planner) processSerialInColumnDef(
 	// The constraint on the name is that an object of this name must not exist already.
 	seqName := tree.NewUnqualifiedTableName(
 		tree.Name(tableName.Table() + "_" + string(d.Name) + "_seq"))
+	// The sequence of a table in a user-defined schema lives in that schema.
+	inUserSchema := tableName.Schema() != tree.PublicSchema && !isTemporarySchemaRef(tableName.Schema())
+	if inUserSchema {
+		seqName.TableNamePrefix = tableName.TableNamePrefix
+		seqName.ExplicitCatalog = true
+		seqName.ExplicitSchema = true
+	}
 
 	// The first step in the search is to prepare the seqName to fill in
 	// the catalog/schema parent. This is what ResolveUncachedDatabase does.
@@ -111,7 +118,7 @@ func (p *planner) processSerialInColumnDef(
 	// Here and below we skip the cache because name resolution using
 	// the cache does not work (well) if the txn retries and the
 	// descriptor was written already in an early txn attempt.
-	dbDesc, err := p.ResolveUncachedDatabase(ctx, seqName)
+	dbDesc, err := p.resolveUncachedDatabaseForRelation(ctx, seqName, false /* allowTemporary */)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -130,9 +137,13 @@ func (p *planner) processSerialInColumnDef(
 		}
 	}
 
+	seqRef := seqName.Table()
+	if inUserSchema {
+		seqRef = tree.AsString(seqName)
+	}
 	defaultExpr := &tree.FuncExpr{
 		Func:  tree.WrapFunction("nextval"),
-		Exprs: tree.Exprs{tree.NewStrVal(seqName.Table())},
+		Exprs: tree.Exprs{tree.NewStrVal(seqRef)},
 	}
 
 	seqType := ""
@@ -24,48 +24,86 @@ const DefaultDatabaseName = "defaultdb"
 // PgCatalogName is the name of the pg_catalog system schema.
 const PgCatalogName = "pg_catalog"
 
+// PgTempSchemaName is the alias for temporary schemas across sessions.
+const PgTempSchemaName = "pg_temp"
+
 // SearchPath represents a list of namespaces to search builtins in.
 // The names must be normalized (as per Name.Normalize) already.
 type SearchPath struct {
-	paths             []string
-	containsPgCatalog bool
+	paths                []string
+	containsPgCatalog    bool
+	containsPgTempSchema bool
+	tempSchemaName       string
 }
 
 // MakeSearchPath returns a new immutable SearchPath struct. The paths slice
 // must not be modified after hand-off to MakeSearchPath.
 func MakeSearchPath(paths []string) SearchPath {
 	containsPgCatalog := false
+	containsPgTempSchema := false
 	for _, e := range paths {
-		if e == PgCatalogName {
+		switch e {
+		case PgCatalogName:
 			containsPgCatalog = true
-			break
+		case PgTempSchemaName:
+			containsPgTempSchema = true
 		}
 	}
 	return SearchPath{
-		paths:             paths,
-		containsPgCatalog: containsPgCatalog,
+		paths:                paths,
+		containsPgCatalog:    containsPgCatalog,
+		containsPgTempSchema: containsPgTempSchema,
+	}
+}
+
+// WithTemporarySchemaName returns a new immutable SearchPath struct with
+// the tempSchemaName supplied and the same paths as before.
+// This should be called every time a session creates a temporary schema
+// for the first time.
+func (s SearchPath) WithTemporarySchemaName(tempSchemaName string) SearchPath {
+	return SearchPath{
+		paths:                s.paths,
+		containsPgCatalog:    s.containsPgCatalog,
+		containsPgTempSchema: s.containsPgTempSchema,
+		tempSchemaName:       tempSchemaName,
 	}
 }
 
+// GetTemporarySchemaName returns the temporary schema specific to the current
+// session, or the empty string if the session has not created one yet.
+func (s SearchPath) GetTemporarySchemaName() string {
+	return s.tempSchemaName
+}
+
 // Iter returns an iterator through the search path. We must include the
-// implicit pg_catalog at the beginning of the search path, unless it has been
-// explicitly set later by the user.
+// implicit pg_catalog and temporary schema at the beginning of the search
+// path, unless they have been explicitly set later by the user.
 // "The system catalog schema, pg_catalog, is always searched, whether it is
 // mentioned in the path or not. If it is mentioned in the path then it will be
 // searched in the specified order. If pg_catalog is not in the path then it
 // will be searched before searching any of the path items."
+// "Likewise, the current session's temporary-table schema, pg_temp_nnn, is
+// always searched if it exists. It can be explicitly listed in the path by
+// using the alias pg_temp. If it is not listed in the path then it is
+// searched first (even before pg_catalog)."
 // - https://www.postgresql.org/docs/9.1/static/runtime-config-client.html
 func (s SearchPath) Iter() SearchPathIter {
-	if s.containsPgCatalog {
-		return SearchPathIter{paths: s.paths, i: 0}
+	implicitPgTempSchema := !s.containsPgTempSchema && s.tempSchemaName != ""
+	return SearchPathIter{
+		paths:                s.paths,
+		implicitPgCatalog:    !s.containsPgCatalog,
+		implicitPgTempSchema: implicitPgTempSchema,
+		tempSchemaName:       s.tempSchemaName,
 	}
-	return SearchPathIter{paths: s.paths, i: -1}
 }
 
 // IterWithoutImplicitPGCatalog is the same as Iter, but does not include the
-// implicit pg_catalog.
+// implicit pg_catalog or the implicit temporary schema.
 func (s SearchPath) IterWithoutImplicitPGCatalog() SearchPathIter {
-	return SearchPathIter{paths: s.paths, i: 0}
+	return SearchPathIter{
+		paths:          s.paths,
+		tempSchemaName: s.tempSchemaName,
+	}
 }
 
 // GetPathArray returns the underlying path array of this SearchPath. The
@@ -79,6 +117,12 @@ func (s SearchPath) Equals(other *SearchPath) bool {
 	if s.containsPgCatalog != other.containsPgCatalog {
 		return false
 	}
+	if s.containsPgTempSchema != other.containsPgTempSchema {
+		return false
+	}
+	if s.tempSchemaName != other.tempSchemaName {
+		return false
+	}
 	if len(s.paths) != len(other.paths) {
 		return false
 	}
@@ -100,21 +144,36 @@ func (s SearchPath) String() string {
 // SearchPathIter enables iteration over the search paths without triggering an
 // allocation. Use one of the SearchPath.Iter methods to get an instance of the
 // iterator, and then repeatedly call the Next method in order to iterate over
-// each search path.
+// each search path. The pg_temp alias is replaced by the session's temporary
+// schema, and skipped if the session has none.
 type SearchPathIter struct {
-	paths []string
-	i     int
+	paths                []string
+	implicitPgCatalog    bool
+	implicitPgTempSchema bool
+	tempSchemaName       string
+	i                    int
 }
 
 // Next returns the next search path, or false if there are no remaining paths.
 func (iter *SearchPathIter) Next() (path string, ok bool) {
-	if iter.i == -1 {
-		iter.i++
+	if iter.implicitPgTempSchema {
+		iter.implicitPgTempSchema = false
+		return iter.tempSchemaName, true
+	}
+	if iter.implicitPgCatalog {
+		iter.implicitPgCatalog = false
 		return PgCatalogName, true
 	}
-	if iter.i < len(iter.paths) {
+	for iter.i < len(iter.paths) {
 		iter.i++
-		return iter.paths[iter.i-1], true
+		path := iter.paths[iter.i-1]
+		if path == PgTempSchemaName {
+			if iter.tempSchemaName == "" {
+				continue
+			}
+			return iter.tempSchemaName, true
+		}
+		return path, true
 	}
 	return "", false
 }
@@ -59,6 +59,55 @@ func TestImpliedSearchPath(t *testing.T) {
 	}
 }
 
+func TestImpliedSearchPathWithTemporarySchema(t *testing.T) {
+	tempSchema := "pg_temp_1"
+	testCases := []struct {
+		explicitSearchPath                         []string
+		expectedSearchPath                         []string
+		expectedSearchPathWithoutImplicitPgCatalog []string
+	}{
+		{[]string{}, []string{tempSchema, `pg_catalog`}, []string{}},
+		{[]string{`pg_catalog`}, []string{tempSchema, `pg_catalog`}, []string{`pg_catalog`}},
+		{[]string{`foobar`, `pg_temp`}, []string{`pg_catalog`, `foobar`, tempSchema}, []string{`foobar`, tempSchema}},
+		{[]string{`pg_temp`, `foobar`, `pg_catalog`}, []string{tempSchema, `foobar`, `pg_catalog`}, []string{tempSchema, `foobar`, `pg_catalog`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.explicitSearchPath, ","), func(t *testing.T) {
+			searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName(tempSchema)
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.Iter()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPath, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPath, actualSearchPath)
+			}
+		})
+
+		t.Run(strings.Join(tc.explicitSearchPath, ",")+"/no-pg-catalog", func(t *testing.T) {
+			searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName(tempSchema)
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.IterWithoutImplicitPGCatalog()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath)
+			}
+		})
+	}
+
+	// Without a temporary schema, the pg_temp alias is skipped.
+	searchPath := MakeSearchPath([]string{`pg_temp`, `foobar`})
+	actualSearchPath := make([]string, 0)
+	iter := searchPath.Iter()
+	for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+		actualSearchPath = append(actualSearchPath, p)
+	}
+	assert.Equal(t, []string{`pg_catalog`, `foobar`}, actualSearchPath)
+}
+
 func TestSearchPathEquals(t *testing.T) {
 	a1 := MakeSearchPath([]string{"x", "y", "z"})
 	a2 := MakeSearchPath([]string{"x", "y", "z"})
@@ -78,4 +127,8 @@ func TestSearchPathEquals(t *testing.T) {
 
 	d := MakeSearchPath([]string{"x"})
 	assert.False(t, a1.Equals(&d))
+
+	e := a1.WithTemporarySchemaName("pg_temp_1")
+	assert.False(t, a1.Equals(&e))
+	assert.True(t, e.Equals(&e))
 }
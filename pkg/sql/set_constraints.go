@@ -0,0 +1,108 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// SetConstraints sets the checking mode of the deferrable constraints for the
+// rest of the transaction, either of all of them or of the named ones. Setting
+// constraints to IMMEDIATE runs the checks which were deferred so far.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	if p.EvalContext().TxnImplicit || p.extendedEvalCtx.Constraints == nil {
+		return nil, pgerror.Newf(pgerror.CodeNoActiveSQLTransactionError,
+			"SET CONSTRAINTS can only be used in transaction blocks")
+	}
+	if len(n.Names) == 0 {
+		if err := p.extendedEvalCtx.Constraints.setMode(ctx, p.txn, n.Deferred); err != nil {
+			return nil, err
+		}
+		return newZeroNode(nil /* columns */), nil
+	}
+	ids, err := p.resolveDeferrableConstraints(ctx, n.Names)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.extendedEvalCtx.Constraints.setNamedMode(ctx, p.txn, ids, n.Deferred); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// resolveDeferrableConstraints returns the constraints of the tables of the
+// current database which have the given names. As in Postgres, a name can
+// match constraints of several tables, and it is an error for a name to match
+// no constraint or a constraint which is not deferrable.
+func (p *planner) resolveDeferrableConstraints(
+	ctx context.Context, names tree.NameList,
+) ([]tree.ConstraintID, error) {
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(names))
+	for _, name := range names {
+		found[string(name)] = false
+	}
+	var ids []tree.ConstraintID
+	err = forEachTableDesc(ctx, p, dbDesc, hideVirtual,
+		func(_ *sqlbase.DatabaseDescriptor, _ string, table *sqlbase.TableDescriptor) error {
+			for _, c := range table.Checks {
+				if _, ok := found[c.Name]; ok {
+					return errConstraintNotDeferrable(c.Name)
+				}
+			}
+			for _, idx := range table.AllNonDropIndexes() {
+				if _, ok := found[idx.Name]; ok {
+					if idx.Unique {
+						return errConstraintNotDeferrable(idx.Name)
+					}
+					if idx.UniqueDeferrability != sqlbase.ConstraintDeferrability_NotDeferrable {
+						found[idx.Name] = true
+						ids = append(ids, tree.ConstraintID{TableID: uint32(table.ID), Name: idx.Name})
+					}
+				}
+				if !idx.ForeignKey.IsSet() {
+					continue
+				}
+				fk := &idx.ForeignKey
+				if _, ok := found[fk.Name]; ok {
+					if fk.Deferrability == sqlbase.ConstraintDeferrability_NotDeferrable {
+						return errConstraintNotDeferrable(fk.Name)
+					}
+					found[fk.Name] = true
+					ids = append(ids, tree.ConstraintID{TableID: uint32(table.ID), Name: fk.Name})
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if !found[string(name)] {
+			return nil, pgerror.Newf(pgerror.CodeUndefinedObjectError,
+				"constraint %q does not exist", string(name))
+		}
+	}
+	return ids, nil
+}
+
+func errConstraintNotDeferrable(name string) error {
+	return pgerror.Newf(pgerror.CodeWrongObjectTypeError, "constraint %q is not deferrable", name)
+}
@@ -30,13 +30,23 @@ func ShowCreateView(
 	ctx context.Context, tn *tree.Name, desc *sqlbase.TableDescriptor,
 ) (string, error) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
-	f.WriteString("CREATE VIEW ")
+	if desc.MaterializedView() {
+		f.WriteString("CREATE MATERIALIZED VIEW ")
+	} else {
+		f.WriteString("CREATE VIEW ")
+	}
 	f.FormatNode(tn)
 	f.WriteString(" (")
+	first := true
 	for i := range desc.Columns {
-		if i > 0 {
+		// Skip the hidden rowid column of a materialized view.
+		if desc.Columns[i].Hidden {
+			continue
+		}
+		if !first {
 			f.WriteString(", ")
 		}
+		first = false
 		f.FormatNameP(&desc.Columns[i].Name)
 	}
 	f.WriteString(") AS ")
@@ -101,9 +111,28 @@ func printForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.Deferrability != sqlbase.ConstraintDeferrability_NotDeferrable {
+		buf.WriteByte(' ')
+		buf.WriteString(sqlbase.TreeConstraintDeferrabilityValue[fk.Deferrability].String())
+	}
 	return nil
 }
 
+// showCreateUniqueConstraint writes the UNIQUE constraint of the given index
+// to f, without its partitioning, predicate and deferrability.
+func showCreateUniqueConstraint(f *tree.FmtCtx, idx *sqlbase.IndexDescriptor) {
+	f.WriteString("CONSTRAINT ")
+	f.FormatNameP(&idx.Name)
+	f.WriteString(" UNIQUE (")
+	idx.ColNamesFormat(f)
+	f.WriteByte(')')
+	if len(idx.StoreColumnNames) > 0 {
+		f.WriteString(" STORING (")
+		formatQuoteNames(&f.Buffer, idx.StoreColumnNames...)
+		f.WriteByte(')')
+	}
+}
+
 // ShowCreateSequence returns a valid SQL representation of the
 // CREATE SEQUENCE statement used to create the given sequence.
 func ShowCreateSequence(
@@ -142,7 +171,11 @@ func ShowCreateTable(
 	a := &sqlbase.DatumAlloc{}
 
 	f := tree.NewFmtCtx(tree.FmtSimple)
-	f.WriteString("CREATE TABLE ")
+	f.WriteString("CREATE ")
+	if desc.Temporary {
+		f.WriteString("TEMPORARY ")
+	}
+	f.WriteString("TABLE ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	primaryKeyIsOnVisibleColumn := false
@@ -181,7 +214,13 @@ func ShowCreateTable(
 		if idx.ID != desc.PrimaryIndex.ID {
 			// Showing the primary index is handled above.
 			f.WriteString(",\n\t")
-			f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			if idx.UniqueDeferrability != sqlbase.ConstraintDeferrability_NotDeferrable {
+				// A deferrable UNIQUE constraint can only be declared as a
+				// constraint, and not as an index.
+				showCreateUniqueConstraint(f, idx)
+			} else {
+				f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			}
 			// Showing the INTERLEAVE and PARTITION BY for the primary index are
 			// handled last.
 			if err := showCreateInterleave(ctx, idx, &f.Buffer, dbPrefix, lCtx); err != nil {
@@ -192,6 +231,13 @@ func ShowCreateTable(
 			); err != nil {
 				return "", err
 			}
+			if idx.IsPartial() {
+				f.WriteString(" WHERE ")
+				f.WriteString(idx.Predicate)
+			}
+			if idx.UniqueDeferrability != sqlbase.ConstraintDeferrability_NotDeferrable {
+				f.FormatNode(sqlbase.TreeConstraintDeferrabilityValue[idx.UniqueDeferrability])
+			}
 		}
 	}
 
@@ -232,9 +278,47 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	// Triggers may refer to other tables, so like foreign keys they are
+	// omitted when the caller recreates them separately.
+	if !ignoreFKs {
+		for _, trig := range showCreateTriggers(tn, desc) {
+			f.WriteString(";\n")
+			f.WriteString(trig)
+		}
+	}
+
 	return f.CloseAndGetString(), nil
 }
 
+// showCreateTriggers returns the CREATE TRIGGER statements which recreate the
+// triggers of the given table.
+func showCreateTriggers(tn *tree.Name, desc *sqlbase.TableDescriptor) []string {
+	stmts := make([]string, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trig := &desc.Triggers[i]
+		n := tree.CreateTrigger{
+			Name:             tree.Name(trig.Name),
+			Table:            tree.MakeUnqualifiedTableName(*tn),
+			ForEachStatement: trig.ForEachStatement,
+			Body:             trig.Body,
+		}
+		if trig.ActionTime == sqlbase.TableDescriptor_Trigger_AFTER {
+			n.ActionTime = tree.TriggerAfter
+		}
+		if trig.OnInsert {
+			n.Events |= tree.TriggerInsert
+		}
+		if trig.OnUpdate {
+			n.Events |= tree.TriggerUpdate
+		}
+		if trig.OnDelete {
+			n.Events |= tree.TriggerDelete
+		}
+		stmts[i] = tree.AsStringWithFlags(&n, tree.FmtSimple)
+	}
+	return stmts
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
@@ -231,6 +231,9 @@ func ensureColumnOrderable(c sqlbase.ResultColumn) error {
 	if c.Typ.Family() == types.JsonFamily {
 		return pgerror.UnimplementedWithIssue(32706, "can't order by column type jsonb")
 	}
+	if f := c.Typ.Family(); f == types.GeometryFamily || f == types.GeographyFamily {
+		return pgerror.Newf(pgerror.CodeFeatureNotSupportedError, "can't order by column type %s", c.Typ)
+	}
 	return nil
 }
 
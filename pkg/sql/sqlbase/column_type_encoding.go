@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
@@ -104,6 +106,11 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 			return encoding.EncodeVarintAscending(b, int64(*t)), nil
 		}
 		return encoding.EncodeVarintDescending(b, int64(*t)), nil
+	case *tree.DTimeTZ:
+		if dir == encoding.Ascending {
+			return encoding.EncodeTimeTZAscending(b, t.TimeTZ), nil
+		}
+		return encoding.EncodeTimeTZDescending(b, t.TimeTZ), nil
 	case *tree.DTimestamp:
 		if dir == encoding.Ascending {
 			return encoding.EncodeTimeAscending(b, t.Time), nil
@@ -163,6 +170,11 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 			return encoding.EncodeVarintAscending(b, int64(t.DInt)), nil
 		}
 		return encoding.EncodeVarintDescending(b, int64(t.DInt)), nil
+	case *tree.DEnum:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, t.PhysicalRep), nil
+		}
+		return encoding.EncodeBytesDescending(b, t.PhysicalRep), nil
 	}
 	return nil, errors.Errorf("unable to encode table key: %T", val)
 }
@@ -243,7 +255,7 @@ func DecodeTableKey(
 			return nil, nil, err
 		}
 		return tree.NewDCollatedString(r, valType.Locale(), &a.env), rkey, err
-	case types.JsonFamily:
+	case types.JsonFamily, types.GeometryFamily, types.GeographyFamily:
 		return tree.DNull, []byte{}, nil
 	case types.BytesFamily:
 		var r []byte
@@ -269,6 +281,14 @@ func DecodeTableKey(
 			rkey, t, err = encoding.DecodeVarintDescending(key)
 		}
 		return a.NewDTime(tree.DTime(t)), rkey, err
+	case types.TimeTZFamily:
+		var t timetz.TimeTZ
+		if dir == encoding.Ascending {
+			rkey, t, err = encoding.DecodeTimeTZAscending(key)
+		} else {
+			rkey, t, err = encoding.DecodeTimeTZDescending(key)
+		}
+		return a.NewDTimeTZ(tree.DTimeTZ{TimeTZ: t}), rkey, err
 	case types.TimestampFamily:
 		var t time.Time
 		if dir == encoding.Ascending {
@@ -326,6 +346,18 @@ func DecodeTableKey(
 			rkey, i, err = encoding.DecodeVarintDescending(key)
 		}
 		return a.NewDOid(tree.MakeDOid(tree.DInt(i))), rkey, err
+	case types.EnumFamily:
+		var r []byte
+		if dir == encoding.Ascending {
+			rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+		} else {
+			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := tree.MakeDEnumFromPhysicalRepresentation(valType, r)
+		return d, rkey, err
 	default:
 		return nil, nil, errors.Errorf("unable to decode table key: %s", valType)
 	}
@@ -367,6 +399,8 @@ func EncodeTableValue(
 		return encoding.EncodeIntValue(appendTo, uint32(colID), t.UnixEpochDaysWithOrig()), nil
 	case *tree.DTime:
 		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(*t)), nil
+	case *tree.DTimeTZ:
+		return encoding.EncodeTimeTZValue(appendTo, uint32(colID), t.TimeTZ), nil
 	case *tree.DTimestamp:
 		return encoding.EncodeTimeValue(appendTo, uint32(colID), t.Time), nil
 	case *tree.DTimestampTZ:
@@ -383,6 +417,10 @@ func EncodeTableValue(
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DGeometry:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.EWKB()), nil
+	case *tree.DGeography:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.EWKB()), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -395,6 +433,8 @@ func EncodeTableValue(
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
 	case *tree.DOid:
 		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(t.DInt)), nil
+	case *tree.DEnum:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.PhysicalRep), nil
 	default:
 		return nil, errors.Errorf("unable to encode table value: %T", t)
 	}
@@ -483,6 +523,12 @@ func decodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []b
 			return nil, b, err
 		}
 		return a.NewDTime(tree.DTime(data)), b, nil
+	case types.TimeTZFamily:
+		b, data, err := encoding.DecodeUntaggedTimeTZValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		return a.NewDTimeTZ(tree.DTimeTZ{TimeTZ: data}), b, nil
 	case types.TimestampFamily:
 		b, data, err := encoding.DecodeUntaggedTimeValue(buf)
 		if err != nil {
@@ -514,9 +560,36 @@ func decodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []b
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.GeometryFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		g, err := geo.ParseGeometryFromEWKB(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDGeometry(g), b, nil
+	case types.GeographyFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		g, err := geo.ParseGeographyFromEWKB(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDGeography(g), b, nil
 	case types.OidFamily:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(tree.MakeDOid(tree.DInt(data))), b, err
+	case types.EnumFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.MakeDEnumFromPhysicalRepresentation(t, data)
+		return d, b, err
 	case types.ArrayFamily:
 		return decodeArray(a, t.ArrayContents(), buf)
 	case types.TupleFamily:
@@ -613,6 +686,11 @@ func MarshalColumnValue(col *ColumnDescriptor, val tree.Datum) (roachpb.Value, e
 			r.SetInt(int64(*v))
 			return r, nil
 		}
+	case types.TimeTZFamily:
+		if v, ok := val.(*tree.DTimeTZ); ok {
+			r.SetTimeTZ(v.TimeTZ)
+			return r, nil
+		}
 	case types.TimestampFamily:
 		if v, ok := val.(*tree.DTimestamp); ok {
 			r.SetTime(v.Time)
@@ -648,6 +726,16 @@ func MarshalColumnValue(col *ColumnDescriptor, val tree.Datum) (roachpb.Value, e
 			r.SetBytes(data)
 			return r, nil
 		}
+	case types.GeometryFamily:
+		if v, ok := val.(*tree.DGeometry); ok {
+			r.SetBytes(v.EWKB())
+			return r, nil
+		}
+	case types.GeographyFamily:
+		if v, ok := val.(*tree.DGeography); ok {
+			r.SetBytes(v.EWKB())
+			return r, nil
+		}
 	case types.ArrayFamily:
 		if v, ok := val.(*tree.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type.ArrayContents()); err != nil {
@@ -678,6 +766,11 @@ func MarshalColumnValue(col *ColumnDescriptor, val tree.Datum) (roachpb.Value, e
 			r.SetInt(int64(v.DInt))
 			return r, nil
 		}
+	case types.EnumFamily:
+		if v, ok := val.(*tree.DEnum); ok {
+			r.SetBytes(v.PhysicalRep)
+			return r, nil
+		}
 	default:
 		return r, pgerror.AssertionFailedf("unsupported column type: %s", col.Type.Family())
 	}
@@ -754,6 +847,12 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return a.NewDTime(tree.DTime(v)), nil
+	case types.TimeTZFamily:
+		v, err := value.GetTimeTZ()
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDTimeTZ(tree.DTimeTZ{TimeTZ: v}), nil
 	case types.TimestampFamily:
 		v, err := value.GetTime()
 		if err != nil {
@@ -805,6 +904,12 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return a.NewDOid(tree.MakeDOid(tree.DInt(v))), nil
+	case types.EnumFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.MakeDEnumFromPhysicalRepresentation(typ, v)
 	case types.ArrayFamily:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -822,6 +927,26 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return tree.NewDJSON(jsonDatum), nil
+	case types.GeometryFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		g, err := geo.ParseGeometryFromEWKB(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDGeometry(g), nil
+	case types.GeographyFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		g, err := geo.ParseGeographyFromEWKB(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDGeography(g), nil
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.Family())
 	}
@@ -865,23 +990,50 @@ func decodeTuple(a *DatumAlloc, tupTyp *types.T, b []byte) (tree.Datum, []byte,
 	return a.NewDTuple(result), b, nil
 }
 
-// encodeArray produces the value encoding for an array.
+// encodeArray produces the value encoding for an array. Multidimensional
+// arrays are encoded as the elements of their innermost dimension, in
+// row-major order, and the length of each dimension is stored in the header.
 func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 	if err := d.Validate(); err != nil {
 		return scratch, err
 	}
 	scratch = scratch[0:0]
-	elementType, err := datumTypeToArrayElementEncodingType(d.ParamTyp)
+	paramTyp := d.ParamTyp
+	numDimensions := 1
+	for paramTyp.Family() == types.ArrayFamily {
+		paramTyp = paramTyp.ArrayContents()
+		numDimensions++
+	}
+	if numDimensions > maxArrayDimensions {
+		return nil, errors.Errorf("arrays can have at most %d dimensions", maxArrayDimensions)
+	}
+	elementType, err := datumTypeToArrayElementEncodingType(paramTyp)
 
 	if err != nil {
 		return nil, err
 	}
+	elements := d.Array
+	hasNulls := d.HasNulls
+	var dimensions []uint64
+	if numDimensions > 1 {
+		dimensions = make([]uint64, numDimensions)
+		for i, dim := range d.Dimensions() {
+			dimensions[i] = uint64(dim)
+		}
+		elements = appendArrayElements(nil /* elements */, d)
+		hasNulls = false
+		for _, e := range elements {
+			if e == tree.DNull {
+				hasNulls = true
+			}
+		}
+	}
 	header := arrayHeader{
-		hasNulls: d.HasNulls,
-		// TODO(justin): support multiple dimensions.
-		numDimensions: 1,
+		hasNulls:      hasNulls,
+		numDimensions: numDimensions,
+		dimensions:    dimensions,
 		elementType:   elementType,
-		length:        uint64(d.Len()),
+		length:        uint64(len(elements)),
 		// We don't encode the NULL bitmap in this function because we do it in lockstep with the
 		// main data.
 	}
@@ -890,14 +1042,14 @@ func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 		return nil, err
 	}
 	nullBitmapStart := len(scratch)
-	if d.HasNulls {
-		for i := 0; i < numBytesInBitArray(d.Len()); i++ {
+	if hasNulls {
+		for i := 0; i < numBytesInBitArray(len(elements)); i++ {
 			scratch = append(scratch, 0)
 		}
 	}
-	for i, e := range d.Array {
+	for i, e := range elements {
 		var err error
-		if d.HasNulls && e == tree.DNull {
+		if hasNulls && e == tree.DNull {
 			setBit(scratch[nullBitmapStart:], i)
 		} else {
 			scratch, err = encodeArrayElement(scratch, e)
@@ -909,6 +1061,19 @@ func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 	return scratch, nil
 }
 
+// appendArrayElements appends the elements of the innermost dimension of the
+// given array to elements, in row-major order.
+func appendArrayElements(elements tree.Datums, d *tree.DArray) tree.Datums {
+	for _, e := range d.Array {
+		if inner, ok := tree.AsDArray(e); ok {
+			elements = appendArrayElements(elements, inner)
+		} else {
+			elements = append(elements, e)
+		}
+	}
+	return elements
+}
+
 // decodeArray decodes the value encoding for an array.
 func decodeArray(a *DatumAlloc, elementType *types.T, b []byte) (tree.Datum, []byte, error) {
 	b, _, _, err := encoding.DecodeNonsortingUvarint(b)
@@ -927,9 +1092,28 @@ func decodeArrayNoMarshalColumnValue(
 	if err != nil {
 		return nil, b, err
 	}
+	paramTyp := elementType
+	numDimensions := 1
+	for paramTyp.Family() == types.ArrayFamily {
+		paramTyp = paramTyp.ArrayContents()
+		numDimensions++
+	}
+	if header.numDimensions != numDimensions {
+		return nil, b, errors.Errorf("array with %d dimensions cannot be decoded as %s[]",
+			header.numDimensions, elementType)
+	}
+	if numDimensions > 1 {
+		length := uint64(1)
+		for _, dim := range header.dimensions {
+			length *= dim
+		}
+		if length != header.length {
+			return nil, b, errors.Errorf("array dimensions do not match its length %d", header.length)
+		}
+	}
 	result := tree.DArray{
 		Array:    make(tree.Datums, header.length),
-		ParamTyp: elementType,
+		ParamTyp: paramTyp,
 	}
 	var val tree.Datum
 	for i := uint64(0); i < header.length; i++ {
@@ -938,16 +1122,51 @@ func decodeArrayNoMarshalColumnValue(
 			result.HasNulls = true
 		} else {
 			result.HasNonNulls = true
-			val, b, err = decodeUntaggedDatum(a, elementType, b)
+			val, b, err = decodeUntaggedDatum(a, paramTyp, b)
 			if err != nil {
 				return nil, b, err
 			}
 			result.Array[i] = val
 		}
 	}
+	if numDimensions > 1 {
+		return makeNestedArray(elementType, header.dimensions, result.Array), b, nil
+	}
 	return &result, b, nil
 }
 
+// makeNestedArray builds a multidimensional array with the given element type
+// from the elements of its innermost dimension, in row-major order.
+func makeNestedArray(elementType *types.T, dimensions []uint64, elements tree.Datums) *tree.DArray {
+	result := &tree.DArray{
+		Array:    make(tree.Datums, dimensions[0]),
+		ParamTyp: elementType,
+	}
+	if len(dimensions) == 1 {
+		copy(result.Array, elements)
+		for _, e := range result.Array {
+			if e == tree.DNull {
+				result.HasNulls = true
+			} else {
+				result.HasNonNulls = true
+			}
+		}
+		return result
+	}
+	stride := uint64(len(elements))
+	if dimensions[0] > 0 {
+		stride /= dimensions[0]
+	}
+	for i := range result.Array {
+		start := uint64(i) * stride
+		result.Array[i] = makeNestedArray(
+			elementType.ArrayContents(), dimensions[1:], elements[start:start+stride],
+		)
+		result.HasNonNulls = true
+	}
+	return result
+}
+
 // arrayHeader is a parameter passing struct between
 // encodeArray/decodeArray and encodeArrayHeader/decodeArrayHeader.
 //
@@ -958,6 +1177,9 @@ type arrayHeader struct {
 	hasNulls bool
 	// numDimensions is the number of dimensions in the array.
 	numDimensions int
+	// dimensions is the length of each dimension of a multidimensional array.
+	// It is only encoded when the array has more than one dimension.
+	dimensions []uint64
 	// elementType is the encoding type of the array elements.
 	elementType encoding.Type
 	// length is the total number of elements encoded.
@@ -996,6 +1218,10 @@ func makeBitVec(src []byte, length int) (b, bitVec []byte) {
 
 const hasNullFlag = 1 << 4
 
+// maxArrayDimensions is the maximum number of dimensions of an array that can
+// be stored in the low 4 bits of the array header byte.
+const maxArrayDimensions = 15
+
 // encodeArrayHeader is used by encodeArray to encode the header
 // at the beginning of the value encoding.
 func encodeArrayHeader(h arrayHeader, buf []byte) ([]byte, error) {
@@ -1010,6 +1236,11 @@ func encodeArrayHeader(h arrayHeader, buf []byte) ([]byte, error) {
 	buf = append(buf, byte(headerByte))
 	buf = encoding.EncodeValueTag(buf, encoding.NoColumnID, h.elementType)
 	buf = encoding.EncodeNonsortingUvarint(buf, h.length)
+	if h.numDimensions > 1 {
+		for _, dim := range h.dimensions {
+			buf = encoding.EncodeNonsortingUvarint(buf, dim)
+		}
+	}
 	return buf, nil
 }
 
@@ -1020,6 +1251,7 @@ func decodeArrayHeader(b []byte) (arrayHeader, []byte, error) {
 		return arrayHeader{}, b, errors.Errorf("buffer too small")
 	}
 	hasNulls := b[0]&hasNullFlag != 0
+	numDimensions := int(b[0] & (hasNullFlag - 1))
 	b = b[1:]
 	_, dataOffset, _, encType, err := encoding.DecodeValueTag(b)
 	if err != nil {
@@ -1030,14 +1262,29 @@ func decodeArrayHeader(b []byte) (arrayHeader, []byte, error) {
 	if err != nil {
 		return arrayHeader{}, b, err
 	}
+	// Arrays were always encoded with a single dimension before
+	// multidimensional arrays were supported.
+	if numDimensions < 1 {
+		numDimensions = 1
+	}
+	var dimensions []uint64
+	if numDimensions > 1 {
+		dimensions = make([]uint64, numDimensions)
+		for i := range dimensions {
+			b, _, dimensions[i], err = encoding.DecodeNonsortingUvarint(b)
+			if err != nil {
+				return arrayHeader{}, b, err
+			}
+		}
+	}
 	nullBitmap := []byte(nil)
 	if hasNulls {
 		b, nullBitmap = makeBitVec(b, int(length))
 	}
 	return arrayHeader{
-		hasNulls: hasNulls,
-		// TODO(justin): support multiple dimensions.
-		numDimensions: 1,
+		hasNulls:      hasNulls,
+		numDimensions: numDimensions,
+		dimensions:    dimensions,
 		elementType:   encType,
 		length:        length,
 		nullBitmap:    nullBitmap,
@@ -1066,6 +1313,8 @@ func datumTypeToArrayElementEncodingType(t *types.T) (encoding.Type, error) {
 	// persisted with incorrect elementType values.
 	case types.DateFamily, types.TimeFamily:
 		return encoding.Int, nil
+	case types.TimeTZFamily:
+		return encoding.TimeTZ, nil
 	case types.IntervalFamily:
 		return encoding.Duration, nil
 	case types.BoolFamily:
@@ -1121,6 +1370,8 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 		return encoding.EncodeUntaggedIntValue(b, t.UnixEpochDaysWithOrig()), nil
 	case *tree.DTime:
 		return encoding.EncodeUntaggedIntValue(b, int64(*t)), nil
+	case *tree.DTimeTZ:
+		return encoding.EncodeUntaggedTimeTZValue(b, t.TimeTZ), nil
 	case *tree.DTimestamp:
 		return encoding.EncodeUntaggedTimeValue(b, t.Time), nil
 	case *tree.DTimestampTZ:
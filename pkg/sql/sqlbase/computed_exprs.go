@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
 // RowIndexedVarContainer is used to evaluate expressions over various rows.
@@ -80,6 +81,13 @@ func CannotWriteToComputedColError(colName string) error {
 		"cannot write directly to computed column %q", tree.ErrNameString(colName))
 }
 
+// NewVirtualPrimaryKeyColumnError constructs the error reported when a
+// virtual computed column is part of a primary key, which must be stored.
+func NewVirtualPrimaryKeyColumnError(colName string) error {
+	return pgerror.Newf(pgerror.CodeInvalidTableDefinitionError,
+		"primary key column %q cannot be a virtual computed column", tree.ErrNameString(colName))
+}
+
 // ProcessComputedColumns adds columns which are computed to the set of columns
 // being updated and returns the computation exprs for those columns.
 //
@@ -211,3 +219,111 @@ func MakeComputedExprs(
 	}
 	return computedExprs, nil
 }
+
+// VirtualColumns computes the values of the virtual computed columns of the
+// rows read from the primary index of a table, which does not store them.
+type VirtualColumns struct {
+	// cols contains the positions in the rows of the computed columns.
+	cols util.FastIntSet
+	// deps contains the positions in the rows of the columns referenced by
+	// the expressions of the computed columns.
+	deps util.FastIntSet
+	// rowIdx and exprs contain the position and the expression of each
+	// computed column.
+	rowIdx  []int
+	exprs   []tree.TypedExpr
+	iv      RowIndexedVarContainer
+	evalCtx *tree.EvalContext
+}
+
+// MakeVirtualColumns prepares the computation of the virtual computed columns
+// among the columns of the rows whose positions are in needed. The columns
+// of the rows are given by cols and colIdxMap.
+func MakeVirtualColumns(
+	tableDesc *ImmutableTableDescriptor,
+	cols []ColumnDescriptor,
+	colIdxMap map[ColumnID]int,
+	needed util.FastIntSet,
+	evalCtx *tree.EvalContext,
+) (VirtualColumns, error) {
+	v := VirtualColumns{evalCtx: evalCtx}
+	var virtualCols []ColumnDescriptor
+	for i := range cols {
+		if cols[i].Virtual && needed.Contains(i) {
+			v.cols.Add(i)
+			v.rowIdx = append(v.rowIdx, i)
+			virtualCols = append(virtualCols, cols[i])
+		}
+	}
+	if len(virtualCols) == 0 {
+		return v, nil
+	}
+	if evalCtx == nil {
+		return VirtualColumns{}, pgerror.AssertionFailedf(
+			"virtual computed column %q cannot be computed without an evaluation context",
+			virtualCols[0].Name)
+	}
+
+	tn := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.Name))
+	exprs, err := MakeComputedExprs(
+		virtualCols, tableDesc, &tn, &transform.ExprTransformContext{}, evalCtx, false, /* addingCols */
+	)
+	if err != nil {
+		return VirtualColumns{}, err
+	}
+	v.exprs = exprs
+	v.iv = RowIndexedVarContainer{Cols: tableDesc.Columns, Mapping: colIdxMap}
+
+	// The expressions reference the columns of the table by their position
+	// among the public columns.
+	visitFn := func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if iv, ok := expr.(*tree.IndexedVar); ok {
+			col := &tableDesc.Columns[iv.Idx]
+			rowIdx, ok := colIdxMap[col.ID]
+			if !ok {
+				return false, nil, pgerror.AssertionFailedf(
+					"column %q used by a virtual computed column is not fetched", col.Name)
+			}
+			v.deps.Add(rowIdx)
+		}
+		return true, expr, nil
+	}
+	for _, expr := range exprs {
+		if _, err := tree.SimpleVisit(expr, visitFn); err != nil {
+			return VirtualColumns{}, err
+		}
+	}
+	return v, nil
+}
+
+// Empty returns true if there are no virtual computed columns to compute.
+func (v *VirtualColumns) Empty() bool {
+	return len(v.exprs) == 0
+}
+
+// Columns returns the positions in the rows of the computed columns.
+func (v *VirtualColumns) Columns() util.FastIntSet {
+	return v.cols
+}
+
+// Dependencies returns the positions in the rows of the columns which must be
+// decoded to compute the virtual computed columns.
+func (v *VirtualColumns) Dependencies() util.FastIntSet {
+	return v.deps
+}
+
+// Compute sets the values of the virtual computed columns in row. The values
+// of their dependencies must be decoded in values.
+func (v *VirtualColumns) Compute(values tree.Datums, row EncDatumRow) error {
+	v.iv.CurSourceRow = values
+	v.evalCtx.PushIVarContainer(&v.iv)
+	defer v.evalCtx.PopIVarContainer()
+	for i, expr := range v.exprs {
+		d, err := expr.Eval(v.evalCtx)
+		if err != nil {
+			return err
+		}
+		row[v.rowIdx[i]] = EncDatum{Datum: d}
+	}
+	return nil
+}
@@ -77,15 +77,18 @@ func MakeDefaultExprs(
 }
 
 // ProcessDefaultColumns adds columns with DEFAULT to cols if not present
-// and returns the defaultExprs for cols.
+// and returns the defaultExprs for cols. Row triggers can modify any column of
+// the inserted rows, so if the table has any, all the columns which are not
+// computed are added.
 func ProcessDefaultColumns(
 	cols []ColumnDescriptor,
 	tableDesc *ImmutableTableDescriptor,
 	txCtx *transform.ExprTransformContext,
 	evalCtx *tree.EvalContext,
 ) ([]ColumnDescriptor, []tree.TypedExpr, error) {
+	hasTriggers := len(tableDesc.Triggers) > 0
 	cols = processColumnSet(cols, tableDesc, func(col *ColumnDescriptor) bool {
-		return col.DefaultExpr != nil
+		return col.DefaultExpr != nil || (hasTriggers && !col.IsComputed())
 	})
 	defaultExprs, err := MakeDefaultExprs(cols, txCtx, evalCtx)
 	return cols, defaultExprs, err
@@ -105,11 +105,29 @@ func NewUndefinedRelationError(name tree.NodeFormatter) error {
 		"relation %q does not exist", tree.ErrString(name))
 }
 
+// NewUndefinedTypeError creates an error that represents a missing type.
+func NewUndefinedTypeError(name tree.NodeFormatter) error {
+	return pgerror.Newf(pgerror.CodeUndefinedObjectError,
+		"type %q does not exist", tree.ErrString(name))
+}
+
+// NewUndefinedSchemaError creates an error that represents a missing schema.
+func NewUndefinedSchemaError(name string) error {
+	return pgerror.Newf(pgerror.CodeInvalidSchemaNameError, "schema %q does not exist", name)
+}
+
 // NewUndefinedColumnError creates an error that represents a missing database column.
 func NewUndefinedColumnError(name string) error {
 	return pgerror.Newf(pgerror.CodeUndefinedColumnError, "column %q does not exist", name)
 }
 
+// NewUndefinedConstraintError creates an error that represents a missing
+// constraint of a table.
+func NewUndefinedConstraintError(name, tableName string) error {
+	return pgerror.Newf(pgerror.CodeUndefinedObjectError,
+		"constraint %q for table %q does not exist", name, tableName)
+}
+
 // NewDatabaseAlreadyExistsError creates an error for a preexisting database.
 func NewDatabaseAlreadyExistsError(name string) error {
 	return pgerror.Newf(pgerror.CodeDuplicateDatabaseError, "database %q already exists", name)
@@ -120,6 +138,16 @@ func NewRelationAlreadyExistsError(name string) error {
 	return pgerror.Newf(pgerror.CodeDuplicateRelationError, "relation %q already exists", name)
 }
 
+// NewTypeAlreadyExistsError creates an error for a preexisting type.
+func NewTypeAlreadyExistsError(name string) error {
+	return pgerror.Newf(pgerror.CodeDuplicateObjectError, "type %q already exists", name)
+}
+
+// NewSchemaAlreadyExistsError creates an error for a preexisting schema.
+func NewSchemaAlreadyExistsError(name string) error {
+	return pgerror.Newf(pgerror.CodeDuplicateSchemaError, "schema %q already exists", name)
+}
+
 // NewWrongObjectTypeError creates a wrong object type error.
 func NewWrongObjectTypeError(name *tree.TableName, desiredObjType string) error {
 	return pgerror.Newf(pgerror.CodeWrongObjectTypeError, "%q is not a %s",
@@ -13,9 +13,11 @@
 package sqlbase
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -696,6 +698,20 @@ func EncodeInvertedIndexKeys(
 // concatenates it with `inKey`and returns a list of buffers per
 // path. The encoded values is guaranteed to be lexicographically
 // sortable, but not guaranteed to be round-trippable during decoding.
+//
+// Arrays are encoded as one key per distinct non-NULL element, using the
+// regular key encoding of the element. An array with no such elements is
+// encoded as a single key that sorts after NULL and before all elements, so
+// that it can be found by containment queries. The spans of constrained
+// array index scans are expressed in terms of single-element arrays, or of
+// the empty array for that key.
+//
+// Spatial values are encoded as the single cell of the spatial index under
+// which they are stored. Empty shapes, which cannot satisfy any spatial
+// predicate, are stored under the invalid cell ID 0, which no scan of the
+// index ever reads; this keeps one index entry per row. The spans of constrained spatial index
+// scans are expressed directly in terms of cell IDs, which are passed in as
+// DInts.
 func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
 	if val == tree.DNull {
 		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
@@ -703,8 +719,53 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 	switch t := tree.UnwrapDatum(nil, val).(type) {
 	case *tree.DJSON:
 		return json.EncodeInvertedIndexKeys(inKey, (t.JSON))
+	case *tree.DArray:
+		return encodeArrayInvertedIndexKeys(t, inKey)
+	case *tree.DGeometry:
+		return encodeSpatialIndexKey(inKey, t.IndexCell)
+	case *tree.DGeography:
+		return encodeSpatialIndexKey(inKey, t.IndexCell)
+	case *tree.DInt:
+		return [][]byte{encoding.EncodeUvarintAscending(inKey, uint64(*t))}, nil
+	}
+	return nil, pgerror.AssertionFailedf("trying to apply inverted index to unsupported type %s", val.ResolvedType())
+}
+
+func encodeArrayInvertedIndexKeys(val *tree.DArray, inKey []byte) ([][]byte, error) {
+	keys := make([][]byte, 0, len(val.Array))
+	for _, d := range val.Array {
+		if d == tree.DNull {
+			continue
+		}
+		// Make a copy of the prefix for each key, since the keys share it.
+		outKey := make([]byte, len(inKey), len(inKey)+16)
+		copy(outKey, inKey)
+		outKey, err := EncodeTableKey(outKey, d, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, outKey)
+	}
+	if len(keys) == 0 {
+		return [][]byte{encoding.EncodeNotNullAscending(inKey)}, nil
+	}
+	// Equal elements produce the same key, which only needs to be written once.
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	unique := keys[:1]
+	for _, k := range keys[1:] {
+		if !bytes.Equal(k, unique[len(unique)-1]) {
+			unique = append(unique, k)
+		}
+	}
+	return unique, nil
+}
+
+func encodeSpatialIndexKey(inKey []byte, indexCell func() (geo.CellID, bool)) ([][]byte, error) {
+	cell, ok := indexCell()
+	if !ok {
+		cell = 0
 	}
-	return nil, pgerror.AssertionFailedf("trying to apply inverted index to non JSON type")
+	return [][]byte{encoding.EncodeUvarintAscending(inKey, uint64(cell))}, nil
 }
 
 // EncodeSecondaryIndex encodes key/values for a secondary
@@ -59,6 +59,12 @@ func WrapDescriptor(descriptor DescriptorProto) *Descriptor {
 		desc.Union = &Descriptor_Table{Table: t}
 	case *DatabaseDescriptor:
 		desc.Union = &Descriptor_Database{Database: t}
+	case *TypeDescriptor:
+		desc.Union = &Descriptor_Type{Type: t}
+	case *SchemaDescriptor:
+		desc.Union = &Descriptor_Schema{Schema: t}
+	case *FunctionDescriptor:
+		desc.Union = &Descriptor_Function{Function: t}
 	default:
 		panic(fmt.Sprintf("unknown descriptor type: %s", descriptor.TypeName()))
 	}
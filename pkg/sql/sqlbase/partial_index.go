@@ -0,0 +1,230 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sqlbase
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// IsPartial returns true if the index is a partial index, i.e. it only
+// contains entries for the rows that satisfy its predicate.
+func (desc *IndexDescriptor) IsPartial() bool {
+	return desc.Predicate != ""
+}
+
+// PredicateColumnIDs returns the sorted IDs of the columns referenced by the
+// predicate of a partial index.
+func (desc *IndexDescriptor) PredicateColumnIDs(tableDesc *TableDescriptor) ([]ColumnID, error) {
+	if !desc.IsPartial() {
+		return nil, nil
+	}
+	parsed, err := parser.ParseExpr(desc.Predicate)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgerror.CodeSyntaxError,
+			"could not parse predicate of index %q", desc.Name)
+	}
+	colIDsUsed := make(map[ColumnID]struct{})
+	visitFn := func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if vBase, ok := expr.(tree.VarName); ok {
+			v, err := vBase.NormalizeVarName()
+			if err != nil {
+				return false, nil, err
+			}
+			if c, ok := v.(*tree.ColumnItem); ok {
+				col, _, err := tableDesc.FindColumnByName(c.ColumnName)
+				if err != nil {
+					return false, nil, err
+				}
+				colIDsUsed[col.ID] = struct{}{}
+			}
+			return false, v, nil
+		}
+		return true, expr, nil
+	}
+	if _, err := tree.SimpleVisit(parsed, visitFn); err != nil {
+		return nil, err
+	}
+	colIDs := make([]ColumnID, 0, len(colIDsUsed))
+	for colID := range colIDsUsed {
+		colIDs = append(colIDs, colID)
+	}
+	sort.Sort(ColumnIDs(colIDs))
+	return colIDs, nil
+}
+
+// PredicateUsesColumn returns whether the predicate of a partial index
+// references the specified column.
+func (desc *IndexDescriptor) PredicateUsesColumn(
+	tableDesc *TableDescriptor, colID ColumnID,
+) (bool, error) {
+	colIDs, err := desc.PredicateColumnIDs(tableDesc)
+	if err != nil {
+		return false, err
+	}
+	i := sort.Search(len(colIDs), func(i int) bool {
+		return colIDs[i] >= colID
+	})
+	return i < len(colIDs) && colIDs[i] == colID, nil
+}
+
+// ArbiterPredicateImplies returns whether the arbiter predicate of an
+// INSERT ... ON CONFLICT (columns) WHERE arbiter clause implies the given
+// partial index predicate, so that the partial index can be used to detect
+// conflicts. Only simple implications are detected: each conjunct of the index
+// predicate must appear among the conjuncts of the arbiter predicate, ignoring
+// the qualification of column names.
+func ArbiterPredicateImplies(arbiter tree.Expr, predicate string) (bool, error) {
+	pred, err := parser.ParseExpr(predicate)
+	if err != nil {
+		return false, err
+	}
+	arbiterConjuncts := make(map[string]struct{})
+	if err := forEachConjunct(arbiter, func(s string) {
+		arbiterConjuncts[s] = struct{}{}
+	}); err != nil {
+		return false, err
+	}
+	implied := true
+	if err := forEachConjunct(pred, func(s string) {
+		if _, ok := arbiterConjuncts[s]; !ok {
+			implied = false
+		}
+	}); err != nil {
+		return false, err
+	}
+	return implied, nil
+}
+
+// forEachConjunct calls fn with the serialized form of each conjunct of expr,
+// with all column names unqualified.
+func forEachConjunct(expr tree.Expr, fn func(string)) error {
+	switch t := expr.(type) {
+	case *tree.AndExpr:
+		if err := forEachConjunct(t.Left, fn); err != nil {
+			return err
+		}
+		return forEachConjunct(t.Right, fn)
+	case *tree.ParenExpr:
+		return forEachConjunct(t.Expr, fn)
+	}
+	expr, err := tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if vBase, ok := expr.(tree.VarName); ok {
+			v, err := vBase.NormalizeVarName()
+			if err != nil {
+				return false, nil, err
+			}
+			if c, ok := v.(*tree.ColumnItem); ok {
+				return false, &tree.ColumnItem{ColumnName: c.ColumnName}, nil
+			}
+			return false, v, nil
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		return err
+	}
+	fn(tree.Serialize(expr))
+	return nil
+}
+
+// PartialIndexPredicates evaluates the predicates of the partial indexes among
+// a set of indexes against rows of a table.
+type PartialIndexPredicates struct {
+	// exprs contains one expression per index, or is nil if none of the
+	// indexes are partial. The expression of an index that is not partial is
+	// nil.
+	exprs   []tree.TypedExpr
+	iv      RowIndexedVarContainer
+	evalCtx *tree.EvalContext
+}
+
+// MakePartialIndexPredicates parses and type checks the predicates of the
+// partial indexes in the given slice of indexes of a table.
+func MakePartialIndexPredicates(
+	tableDesc *ImmutableTableDescriptor, indexes []IndexDescriptor, evalCtx *tree.EvalContext,
+) (PartialIndexPredicates, error) {
+	p := PartialIndexPredicates{evalCtx: evalCtx}
+	var sources MultiSourceInfo
+	var ivarHelper tree.IndexedVarHelper
+	var semaCtx tree.SemaContext
+	for i := range indexes {
+		idx := &indexes[i]
+		if !idx.IsPartial() {
+			continue
+		}
+		if p.exprs == nil {
+			// The predicate may reference any column of the table that is
+			// not being added, including columns in the process of being
+			// dropped along with the index.
+			cols := tableDesc.DeletableColumns()
+			p.exprs = make([]tree.TypedExpr, len(indexes))
+			p.iv.Cols = cols
+			iv := &descContainer{cols}
+			ivarHelper = tree.MakeIndexedVarHelper(iv, len(cols))
+			tn := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.Name))
+			sources = MakeMultiSourceInfo(NewSourceInfoForSingleTable(
+				tn, ResultColumnsFromColDescs(cols),
+			))
+			semaCtx = tree.MakeSemaContext()
+			semaCtx.IVarContainer = iv
+		}
+		expr, err := parser.ParseExpr(idx.Predicate)
+		if err != nil {
+			return PartialIndexPredicates{}, err
+		}
+		searchPath := DefaultSearchPath
+		if evalCtx.SessionData != nil {
+			searchPath = evalCtx.SessionData.SearchPath
+		}
+		expr, _, _, err = ResolveNames(expr, sources, ivarHelper, searchPath)
+		if err != nil {
+			return PartialIndexPredicates{}, err
+		}
+		typedExpr, err := tree.TypeCheck(expr, &semaCtx, types.Bool)
+		if err != nil {
+			return PartialIndexPredicates{}, err
+		}
+		p.exprs[i] = typedExpr
+	}
+	return p, nil
+}
+
+// Empty returns true if none of the indexes are partial.
+func (p *PartialIndexPredicates) Empty() bool {
+	return p.exprs == nil
+}
+
+// Holds returns whether the row, laid out according to colMap, satisfies the
+// predicate of the i-th index. It always returns true for indexes that are not
+// partial.
+func (p *PartialIndexPredicates) Holds(
+	i int, colMap map[ColumnID]int, values tree.Datums,
+) (bool, error) {
+	if p.exprs == nil || p.exprs[i] == nil {
+		return true, nil
+	}
+	p.iv.Mapping = colMap
+	p.iv.CurSourceRow = values
+	p.evalCtx.PushIVarContainer(&p.iv)
+	d, err := p.exprs[i].Eval(p.evalCtx)
+	p.evalCtx.PopIVarContainer()
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
@@ -142,6 +142,11 @@ func (v *NameResolutionVisitor) VisitPre(expr tree.Expr) (recurse bool, newNode
 	case *tree.FuncExpr:
 		fd, err := t.Func.Resolve(v.searchPath)
 		if err != nil {
+			if pgErr, ok := pgerror.GetPGCause(err); ok && pgErr.Code == pgerror.CodeUndefinedFunctionError {
+				// The name may refer to a user-defined function, which is resolved
+				// during type checking.
+				break
+			}
 			v.err = err
 			return false, expr
 		}
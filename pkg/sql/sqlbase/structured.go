@@ -13,6 +13,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"sort"
@@ -296,6 +297,57 @@ func GetTableDescFromID(ctx context.Context, txn *client.Txn, id ID) (*TableDesc
 	return table, nil
 }
 
+// GetTypeDescFromID retrieves the type descriptor for the type ID passed in
+// using an existing txn. Returns ErrDescriptorNotFound if the descriptor
+// doesn't exist or if it exists and is not a type.
+func GetTypeDescFromID(ctx context.Context, txn *client.Txn, id ID) (*TypeDescriptor, error) {
+	desc := &Descriptor{}
+	descKey := MakeDescMetadataKey(id)
+
+	if err := txn.GetProto(ctx, descKey, desc); err != nil {
+		return nil, err
+	}
+	typ := desc.GetType()
+	if typ == nil {
+		return nil, ErrDescriptorNotFound
+	}
+	return typ, nil
+}
+
+// GetSchemaDescFromID retrieves the schema descriptor for the schema ID
+// passed in using an existing txn. Returns ErrDescriptorNotFound if the
+// descriptor doesn't exist or if it exists and is not a schema.
+func GetSchemaDescFromID(ctx context.Context, txn *client.Txn, id ID) (*SchemaDescriptor, error) {
+	desc := &Descriptor{}
+	descKey := MakeDescMetadataKey(id)
+
+	if err := txn.GetProto(ctx, descKey, desc); err != nil {
+		return nil, err
+	}
+	schema := desc.GetSchema()
+	if schema == nil {
+		return nil, ErrDescriptorNotFound
+	}
+	return schema, nil
+}
+
+// GetFunctionDescFromID retrieves the function descriptor for the function
+// ID passed in using an existing txn. Returns ErrDescriptorNotFound if the
+// descriptor doesn't exist or if it exists and is not a function.
+func GetFunctionDescFromID(ctx context.Context, txn *client.Txn, id ID) (*FunctionDescriptor, error) {
+	desc := &Descriptor{}
+	descKey := MakeDescMetadataKey(id)
+
+	if err := txn.GetProto(ctx, descKey, desc); err != nil {
+		return nil, err
+	}
+	fn := desc.GetFunction()
+	if fn == nil {
+		return nil, ErrDescriptorNotFound
+	}
+	return fn, nil
+}
+
 // GetMutableTableDescFromID retrieves the table descriptor for the table
 // ID passed in using an existing txn. Returns an error if the
 // descriptor doesn't exist or if it exists and is not a table.
@@ -507,6 +559,23 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns true if the TableDescriptor describes a
+// materialized view, which is a view whose results are stored like the
+// contents of a table.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsView() && desc.IsMaterializedView
+}
+
+// NamespaceParentID returns the ID under which the name of the table is
+// stored in system.namespace: the ID of its schema if it doesn't belong to the
+// public schema of its database, otherwise the ID of the database.
+func (desc *TableDescriptor) NamespaceParentID() ID {
+	if desc.UnexposedParentSchemaID != InvalidID {
+		return desc.UnexposedParentSchemaID
+	}
+	return desc.ParentID
+}
+
 // IsSequence returns true if the TableDescriptor actually describes a
 // Sequence resource rather than a Table.
 func (desc *TableDescriptor) IsSequence() bool {
@@ -531,10 +600,11 @@ func IsVirtualTable(id ID) bool {
 // physical Table that needs to be stored in the kv layer, as opposed to a
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
-// Sequences count as physical tables because their values are stored in
-// the KV layer.
+// Sequences and materialized views count as physical tables because their
+// values are stored in the KV layer.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || desc.MaterializedView() ||
+		(desc.IsTable() && !desc.IsVirtualTable())
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -859,7 +929,9 @@ func DatumTypeHasCompositeKeyEncoding(typ *types.T) bool {
 func MustBeValueEncoded(semanticType types.Family) bool {
 	return semanticType == types.ArrayFamily ||
 		semanticType == types.JsonFamily ||
-		semanticType == types.TupleFamily
+		semanticType == types.TupleFamily ||
+		semanticType == types.GeometryFamily ||
+		semanticType == types.GeographyFamily
 }
 
 // HasOldStoredColumns returns whether the index has stored columns in the old
@@ -1018,6 +1090,10 @@ func (desc *MutableTableDescriptor) allocateColumnFamilyIDs(columnNames map[stri
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
+		if col.Virtual {
+			// Virtual computed columns are not stored in any family.
+			return
+		}
 		if _, ok := primaryIndexColIDs[col.ID]; ok {
 			// Primary index columns are required to be assigned to family 0.
 			desc.Families[0].ColumnNames = append(desc.Families[0].ColumnNames, col.Name)
@@ -1302,6 +1378,11 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 		return pgerror.AssertionFailedf("invalid parent ID %d", log.Safe(desc.ParentID))
 	}
 
+	// A temporary table always belongs to a temporary schema.
+	if desc.Temporary && desc.UnexposedParentSchemaID == InvalidID {
+		return pgerror.AssertionFailedf("temporary table %q has no parent schema", desc.Name)
+	}
+
 	// We maintain forward compatibility, so if you see this error message with a
 	// version older that what this client supports, then there's a
 	// MaybeFillInDescriptor missing from some codepath.
@@ -1368,6 +1449,32 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 				}
 			}
 		}
+		if !st.Version.IsActive(cluster.VersionSpatialTypes) {
+			for i := range desc.Columns {
+				switch desc.Columns[i].Type.Family() {
+				case types.GeometryFamily, types.GeographyFamily:
+					return fmt.Errorf("cluster version does not support %s (required: %s)",
+						desc.Columns[i].Type.SQLString(), cluster.VersionByKey(cluster.VersionSpatialTypes))
+				}
+			}
+		}
+		if !st.Version.IsActive(cluster.VersionTimeTZType) {
+			for i := range desc.Columns {
+				if desc.Columns[i].Type.Family() == types.TimeTZFamily {
+					return fmt.Errorf("cluster version does not support TIMETZ (required: %s)",
+						cluster.VersionByKey(cluster.VersionTimeTZType))
+				}
+			}
+		}
+		if !st.Version.IsActive(cluster.VersionMultiDimensionalArrays) {
+			for i := range desc.Columns {
+				typ := &desc.Columns[i].Type
+				if typ.Family() == types.ArrayFamily && typ.ArrayContents().Family() == types.ArrayFamily {
+					return fmt.Errorf("cluster version does not support multi-dimensional arrays (required: %s)",
+						cluster.VersionByKey(cluster.VersionMultiDimensionalArrays))
+				}
+			}
+		}
 	}
 
 	for _, m := range desc.Mutations {
@@ -1479,6 +1586,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 		}
 
 		for _, colID := range family.ColumnIDs {
+			if col, err := desc.FindColumnByID(colID); err == nil && col.Virtual {
+				return nil, fmt.Errorf("family %q contains virtual computed column %q", family.Name, col.Name)
+			}
 			if famID, ok := colIDToFamilyID[colID]; ok {
 				return nil, fmt.Errorf("column %d is in both family %d and %d", colID, famID, family.ID)
 			}
@@ -1487,6 +1597,10 @@ func (desc *TableDescriptor) validateColumnFamilies(
 	}
 	for colID := range columnIDs {
 		if _, ok := colIDToFamilyID[colID]; !ok {
+			if col, err := desc.FindColumnByID(colID); err == nil && col.Virtual {
+				// Virtual computed columns are not stored.
+				continue
+			}
 			return nil, fmt.Errorf("column %d is not in any column family", colID)
 		}
 	}
@@ -1808,7 +1922,15 @@ func columnTypeIsIndexable(t *types.T) bool {
 // columnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
 // using an inverted index.
 func columnTypeIsInvertedIndexable(t *types.T) bool {
-	return t.Family() == types.JsonFamily
+	switch t.Family() {
+	case types.JsonFamily, types.GeometryFamily, types.GeographyFamily:
+		return true
+	case types.ArrayFamily:
+		// The elements of the array are key-encoded into the index, so they must
+		// be indexable. This excludes nested arrays.
+		return columnTypeIsIndexable(t.ArrayContents())
+	}
+	return false
 }
 
 func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
@@ -2520,8 +2642,12 @@ func (desc *ImmutableTableDescriptor) MakeFirstMutationPublic() (*MutableTableDe
 }
 
 // ColumnNeedsBackfill returns true if adding the given column requires a
-// backfill (dropping a column always requires a backfill).
+// backfill (dropping a column always requires a backfill). Virtual computed
+// columns are not stored, so adding them does not require one.
 func ColumnNeedsBackfill(desc *ColumnDescriptor) bool {
+	if desc.Virtual {
+		return false
+	}
 	return desc.DefaultExpr != nil || !desc.Nullable || desc.IsComputed()
 }
 
@@ -2650,6 +2776,267 @@ func (desc *DatabaseDescriptor) Validate() error {
 	return desc.Privileges.Validate(desc.GetID())
 }
 
+// FunctionIDs returns the IDs of the overloads of the user-defined function
+// with the given name.
+func (desc *DatabaseDescriptor) FunctionIDs(name string) []ID {
+	var ids []ID
+	for _, fn := range desc.Functions {
+		if fn.Name == name {
+			ids = append(ids, fn.ID)
+		}
+	}
+	return ids
+}
+
+// AddFunction records that the database contains the user-defined function
+// with the given name and ID.
+func (desc *DatabaseDescriptor) AddFunction(name string, id ID) {
+	desc.Functions = append(desc.Functions, DatabaseDescriptor_FunctionReference{Name: name, ID: id})
+}
+
+// RemoveFunction removes the user-defined function with the given ID from the
+// database.
+func (desc *DatabaseDescriptor) RemoveFunction(id ID) {
+	for i := range desc.Functions {
+		if desc.Functions[i].ID == id {
+			desc.Functions = append(desc.Functions[:i], desc.Functions[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetID implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *TypeDescriptor) TypeName() string {
+	return "type"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+func (desc *TypeDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the type descriptor is well formed. Checks include
+// validating the type name, and verifying that the members of the type are
+// unique and ordered by their physical representations.
+func (desc *TypeDescriptor) Validate() error {
+	if err := validateName(desc.Name, "type"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid type ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d", desc.ParentID)
+	}
+	if desc.Domain != nil {
+		if len(desc.EnumMembers) > 0 {
+			return fmt.Errorf("domain %q has enum members", desc.Name)
+		}
+		if desc.Domain.BaseType.UserDefined() {
+			return fmt.Errorf("domain %q has a user-defined base type", desc.Name)
+		}
+		names := make(map[string]struct{}, len(desc.Domain.Checks))
+		for i := range desc.Domain.Checks {
+			name := desc.Domain.Checks[i].Name
+			if _, ok := names[name]; ok {
+				return fmt.Errorf("duplicate constraint name %q", name)
+			}
+			names[name] = struct{}{}
+		}
+	}
+	labels := make(map[string]struct{}, len(desc.EnumMembers))
+	for i := range desc.EnumMembers {
+		m := &desc.EnumMembers[i]
+		if _, ok := labels[m.LogicalRepresentation]; ok {
+			return fmt.Errorf("duplicate enum member %q", m.LogicalRepresentation)
+		}
+		labels[m.LogicalRepresentation] = struct{}{}
+		if i > 0 && bytes.Compare(desc.EnumMembers[i-1].PhysicalRepresentation, m.PhysicalRepresentation) >= 0 {
+			return fmt.Errorf("enum members %q and %q are not ordered by their physical representations",
+				desc.EnumMembers[i-1].LogicalRepresentation, m.LogicalRepresentation)
+		}
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// MakeTypesT creates the types.T that describes the type. The returned type
+// carries all the members of an enum type, so that values of the type can be
+// interpreted without access to the descriptor. The type of a domain is its
+// base type, marked with the ID of the domain.
+func (desc *TypeDescriptor) MakeTypesT() *types.T {
+	if desc.Domain != nil {
+		return types.MakeDomain(&desc.Domain.BaseType, desc.Name, uint32(desc.ID))
+	}
+	members := &types.EnumMetadata{
+		PhysicalRepresentations: make([][]byte, len(desc.EnumMembers)),
+		LogicalRepresentations:  make([]string, len(desc.EnumMembers)),
+		IsMemberReadOnly:        make([]bool, len(desc.EnumMembers)),
+	}
+	for i := range desc.EnumMembers {
+		m := &desc.EnumMembers[i]
+		members.PhysicalRepresentations[i] = m.PhysicalRepresentation
+		members.LogicalRepresentations[i] = m.LogicalRepresentation
+		members.IsMemberReadOnly[i] = m.Capability == TypeDescriptor_EnumMember_READ_ONLY
+	}
+	return types.MakeEnum(types.TypeIDToOID(uint32(desc.ID)), desc.Name, members)
+}
+
+// HasReadOnlyMembers returns true if some members of the type are still being
+// added to it.
+func (desc *TypeDescriptor) HasReadOnlyMembers() bool {
+	for i := range desc.EnumMembers {
+		if desc.EnumMembers[i].Capability == TypeDescriptor_EnumMember_READ_ONLY {
+			return true
+		}
+	}
+	return false
+}
+
+// AddReferencingDescriptorID records that the descriptor with the given ID
+// references the type.
+func (desc *TypeDescriptor) AddReferencingDescriptorID(id ID) {
+	for _, ref := range desc.ReferencingDescriptorIDs {
+		if ref == id {
+			return
+		}
+	}
+	desc.ReferencingDescriptorIDs = append(desc.ReferencingDescriptorIDs, id)
+}
+
+// RemoveReferencingDescriptorID records that the descriptor with the given ID
+// no longer references the type.
+func (desc *TypeDescriptor) RemoveReferencingDescriptorID(id ID) {
+	for i, ref := range desc.ReferencingDescriptorIDs {
+		if ref == id {
+			desc.ReferencingDescriptorIDs = append(
+				desc.ReferencingDescriptorIDs[:i], desc.ReferencingDescriptorIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetID implements the DescriptorProto interface.
+func (desc *SchemaDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *SchemaDescriptor) TypeName() string {
+	return "schema"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *SchemaDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+func (desc *SchemaDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the schema descriptor is well formed. Checks
+// include validating the schema name, and verifying that there is at least
+// one read and write user.
+func (desc *SchemaDescriptor) Validate() error {
+	if err := validateName(desc.Name, "schema"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid schema ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d", desc.ParentID)
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// SetID implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the function descriptor is well formed. Checks
+// include validating the function name, and verifying that the names of the
+// parameters are unique.
+func (desc *FunctionDescriptor) Validate() error {
+	if err := validateName(desc.Name, "function"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid function ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d", desc.ParentID)
+	}
+	names := make(map[string]struct{}, len(desc.Parameters))
+	for i := range desc.Parameters {
+		name := desc.Parameters[i].Name
+		if name == "" {
+			continue
+		}
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("parameter name %q used more than once", name)
+		}
+		names[name] = struct{}{}
+	}
+	if desc.Body == "" {
+		return fmt.Errorf("function %q has no body", desc.Name)
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// ParameterTypes returns the types of the parameters of the function.
+func (desc *FunctionDescriptor) ParameterTypes() []*types.T {
+	typs := make([]*types.T, len(desc.Parameters))
+	for i := range desc.Parameters {
+		typs[i] = &desc.Parameters[i].Type
+	}
+	return typs
+}
+
+// Signature returns the name of the function followed by the types of its
+// parameters, e.g. "f(INT8, STRING)". Overloads of a function are identified
+// by their signature.
+func (desc *FunctionDescriptor) Signature() string {
+	var buf bytes.Buffer
+	buf.WriteString(desc.Name)
+	buf.WriteByte('(')
+	for i := range desc.Parameters {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(desc.Parameters[i].Type.SQLString())
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
 // GetID returns the ID of the descriptor.
 func (desc *Descriptor) GetID() ID {
 	switch t := desc.Union.(type) {
@@ -2657,6 +3044,12 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Type:
+		return t.Type.ID
+	case *Descriptor_Schema:
+		return t.Schema.ID
+	case *Descriptor_Function:
+		return t.Function.ID
 	default:
 		return 0
 	}
@@ -2669,6 +3062,12 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Type:
+		return t.Type.Name
+	case *Descriptor_Schema:
+		return t.Schema.Name
+	case *Descriptor_Function:
+		return t.Function.Name
 	default:
 		return ""
 	}
@@ -2753,7 +3152,11 @@ func (desc *ColumnDescriptor) SQLString() string {
 	if desc.IsComputed() {
 		f.WriteString(" AS (")
 		f.WriteString(*desc.ComputeExpr)
-		f.WriteString(") STORED")
+		if desc.Virtual {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString()
 }
@@ -2854,6 +3257,23 @@ func (x ForeignKeyReference_Match) String() string {
 	}
 }
 
+// ConstraintDeferrabilityValue allows the conversion from a
+// tree.ConstraintDeferrability to a ConstraintDeferrability.
+var ConstraintDeferrabilityValue = [...]ConstraintDeferrability{
+	tree.NotDeferrable:                ConstraintDeferrability_NotDeferrable,
+	tree.DeferrableInitiallyImmediate: ConstraintDeferrability_InitiallyImmediate,
+	tree.DeferrableInitiallyDeferred:  ConstraintDeferrability_InitiallyDeferred,
+}
+
+// TreeConstraintDeferrabilityValue allows the conversion from a
+// ConstraintDeferrability to a tree.ConstraintDeferrability.
+// This should match ConstraintDeferrabilityValue.
+var TreeConstraintDeferrabilityValue = [...]tree.ConstraintDeferrability{
+	ConstraintDeferrability_NotDeferrable:      tree.NotDeferrable,
+	ConstraintDeferrability_InitiallyImmediate: tree.DeferrableInitiallyImmediate,
+	ConstraintDeferrability_InitiallyDeferred:  tree.DeferrableInitiallyDeferred,
+}
+
 // ForeignKeyReferenceActionValue allows the conversion between a
 // tree.ReferenceAction and a ForeignKeyReference_Action.
 var ForeignKeyReferenceActionValue = [...]ForeignKeyReference_Action{
@@ -2904,24 +3324,22 @@ func (desc *ColumnDescriptor) DatumType() *types.T {
 
 // ColTypePrecision is part of the cat.Column interface.
 func (desc *ColumnDescriptor) ColTypePrecision() int {
-	if desc.Type.Family() == types.ArrayFamily {
-		if desc.Type.ArrayContents().Family() == types.ArrayFamily {
-			panic(pgerror.AssertionFailedf("column type should never be a nested array"))
-		}
-		return int(desc.Type.ArrayContents().Precision())
+	// The precision of an array column is the precision of its element type.
+	typ := &desc.Type
+	for typ.Family() == types.ArrayFamily {
+		typ = typ.ArrayContents()
 	}
-	return int(desc.Type.Precision())
+	return int(typ.Precision())
 }
 
 // ColTypeWidth is part of the cat.Column interface.
 func (desc *ColumnDescriptor) ColTypeWidth() int {
-	if desc.Type.Family() == types.ArrayFamily {
-		if desc.Type.ArrayContents().Family() == types.ArrayFamily {
-			panic(pgerror.AssertionFailedf("column type should never be a nested array"))
-		}
-		return int(desc.Type.ArrayContents().Width())
+	// The width of an array column is the width of its element type.
+	typ := &desc.Type
+	for typ.Family() == types.ArrayFamily {
+		typ = typ.ArrayContents()
 	}
-	return int(desc.Type.Width())
+	return int(typ.Width())
 }
 
 // ColTypeStr is part of the cat.Column interface.
@@ -3143,3 +3561,25 @@ func (tk TableKey) Key() roachpb.Key {
 func (tk TableKey) Name() string {
 	return tk.name
 }
+
+// SchemaKey implements DescriptorKey interface.
+type SchemaKey struct {
+	parentID ID
+	name     string
+}
+
+// NewSchemaKey returns a new SchemaKey for a schema of the database with the
+// given ID.
+func NewSchemaKey(parentID ID, name string) SchemaKey {
+	return SchemaKey{parentID, name}
+}
+
+// Key implements DescriptorKey interface.
+func (sk SchemaKey) Key() roachpb.Key {
+	return MakeNameMetadataKey(sk.parentID, sk.name)
+}
+
+// Name implements DescriptorKey interface.
+func (sk SchemaKey) Name() string {
+	return sk.name
+}
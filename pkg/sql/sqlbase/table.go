@@ -89,10 +89,6 @@ func ValidateColumnDefType(t *types.T) error {
 		}
 
 	case types.ArrayFamily:
-		if t.ArrayContents().Family() == types.ArrayFamily {
-			// Nested arrays are not supported as a column type.
-			return errors.Errorf("nested array unsupported as column type: %s", t.String())
-		}
 		if err := types.CheckArrayElementType(t.ArrayContents()); err != nil {
 			return err
 		}
@@ -100,9 +96,22 @@ func ValidateColumnDefType(t *types.T) error {
 
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
-		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily:
+		types.TimeTZFamily, types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.GeometryFamily,
+		types.GeographyFamily:
 		// These types are OK.
 
+	case types.EnumFamily:
+		// References to user-defined types are resolved by the planner before
+		// the column descriptor is created.
+		if t.IsUnresolved() {
+			return pgerror.Newf(pgerror.CodeUndefinedObjectError,
+				"type %q does not exist", t.Name())
+		}
+		if t.EnumData() == nil {
+			return pgerror.Newf(pgerror.CodeInvalidTableDefinitionError,
+				"value type %s cannot be used for table columns", t.String())
+		}
+
 	default:
 		return pgerror.Newf(pgerror.CodeInvalidTableDefinitionError,
 			"value type %s cannot be used for table columns", t.String())
@@ -146,6 +155,15 @@ func MakeColumnDefDescs(
 		// Should never happen since `HoistConstraints` moves these to table level
 		return nil, nil, nil, errors.New("unexpected column REFERENCED constraint")
 	}
+	if d.IsComputed() && d.Computed.Virtual {
+		if d.PrimaryKey {
+			return nil, nil, nil, NewVirtualPrimaryKeyColumnError(string(d.Name))
+		}
+		if d.HasColumnFamily() {
+			return nil, nil, nil, pgerror.Newf(pgerror.CodeInvalidTableDefinitionError,
+				"virtual computed column %q cannot be assigned to a column family", d.Name)
+		}
+	}
 
 	col := &ColumnDescriptor{
 		Name:     string(d.Name),
@@ -157,7 +175,10 @@ func MakeColumnDefDescs(
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	col.Type = *d.Type
+	// A column declared with a domain has the base type of the domain; the
+	// constraints of the domain are enforced by the column constraints.
+	col.Type = *d.Type.BaseType()
+	col.DomainID = ID(d.Type.DomainID())
 
 	var typedExpr tree.TypedExpr
 	if d.HasDefaultExpr() {
@@ -165,7 +186,7 @@ func MakeColumnDefDescs(
 		// and does not contain invalid functions.
 		var err error
 		if typedExpr, err = SanitizeVarFreeExpr(
-			d.DefaultExpr.Expr, d.Type, "DEFAULT", semaCtx, true, /* allowImpure */
+			d.DefaultExpr.Expr, &col.Type, "DEFAULT", semaCtx, true, /* allowImpure */
 		); err != nil {
 			return nil, nil, nil, err
 		}
@@ -180,6 +201,7 @@ func MakeColumnDefDescs(
 	if d.IsComputed() {
 		s := tree.Serialize(d.Computed.Expr)
 		col.ComputeExpr = &s
+		col.Virtual = d.Computed.Virtual
 	}
 
 	var idx *IndexDescriptor
@@ -339,7 +361,7 @@ func (desc *TableDescriptor) collectConstraintInfo(
 			detail.Columns = index.ColumnNames
 			detail.Index = index
 			info[index.Name] = detail
-		} else if index.Unique {
+		} else if index.Unique || index.UniqueDeferrability != ConstraintDeferrability_NotDeferrable {
 			if _, ok := info[index.Name]; ok {
 				return nil, pgerror.Newf(pgerror.CodeDuplicateObjectError,
 					"duplicate constraint name: %q", index.Name)
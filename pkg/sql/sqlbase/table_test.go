@@ -346,6 +346,32 @@ func TestArrayEncoding(t *testing.T) {
 				HasNulls: true,
 			},
 			[]byte{17, 3, 9, 6, 1, 2, 4, 6, 8, 10, 12},
+		}, {
+			"two-dimensional int array",
+			tree.DArray{
+				ParamTyp: types.IntArray,
+				Array: tree.Datums{
+					&tree.DArray{
+						ParamTyp: types.Int,
+						Array:    tree.Datums{tree.NewDInt(1), tree.NewDInt(2)},
+					},
+					&tree.DArray{
+						ParamTyp: types.Int,
+						Array:    tree.Datums{tree.NewDInt(3), tree.DNull},
+						HasNulls: true,
+					},
+				},
+			},
+			[]byte{18, 3, 4, 2, 2, 8, 2, 4, 6},
+		}, {
+			"two-dimensional array containing an empty array",
+			tree.DArray{
+				ParamTyp: types.IntArray,
+				Array: tree.Datums{
+					&tree.DArray{ParamTyp: types.Int, Array: tree.Datums{}},
+				},
+			},
+			[]byte{2, 3, 0, 1, 0},
 		},
 	}
 
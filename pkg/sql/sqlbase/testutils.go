@@ -22,6 +22,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -131,6 +133,8 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 		return tree.NewDDate(d)
 	case types.TimeFamily:
 		return tree.MakeDTime(timeofday.Random(rng))
+	case types.TimeTZFamily:
+		return tree.MakeDTimeTZ(timetz.Random(rng))
 	case types.TimestampFamily:
 		return &tree.DTimestamp{Time: timeutil.Unix(rng.Int63n(1000000), rng.Int63n(1000000))}
 	case types.IntervalFamily:
@@ -152,6 +156,14 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.GeometryFamily:
+		return tree.NewDGeometry(geo.Geometry{Shape: geo.Point{
+			Coord: geo.Coord{X: rng.NormFloat64() * 1000, Y: rng.NormFloat64() * 1000},
+		}})
+	case types.GeographyFamily:
+		return tree.NewDGeography(geo.Geography{SRID: geo.DefaultGeographySRID, Shape: geo.Point{
+			Coord: geo.Coord{X: rng.Float64()*360 - 180, Y: rng.Float64()*180 - 90},
+		}})
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -275,6 +287,10 @@ var (
 			tree.MakeDTime(timeofday.Min),
 			tree.MakeDTime(timeofday.Max),
 		},
+		types.TimeTZFamily: {
+			tree.MakeDTimeTZ(timetz.MinTimeTZ),
+			tree.MakeDTimeTZ(timetz.MaxTimeTZ),
+		},
 		types.TimestampFamily: func() []tree.Datum {
 			res := make([]tree.Datum, len(randTimestampSpecials))
 			for i, t := range randTimestampSpecials {
@@ -420,7 +436,7 @@ func randType(rng *rand.Rand, typs []*types.T) *types.T {
 }
 
 // RandColumnType returns a random type that is a legal column type (e.g. no
-// nested arrays or tuples).
+// tuples).
 func RandColumnType(rng *rand.Rand) *types.T {
 	for {
 		typ := RandType(rng)
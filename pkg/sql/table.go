@@ -144,13 +144,6 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		log.Infof(ctx, "reading mutable descriptor on table '%s'", tn)
 	}
 
-	if tn.SchemaName != tree.PublicSchemaName {
-		if flags.required {
-			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(tn))
-		}
-		return nil, nil
-	}
-
 	refuseFurtherLookup, dbID, err := tc.getUncommittedDatabaseID(tn.Catalog(), flags.required)
 	if refuseFurtherLookup || err != nil {
 		return nil, err
@@ -167,7 +160,20 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		}
 	}
 
-	if refuseFurtherLookup, table, err := tc.getUncommittedTable(dbID, tn, flags.required); refuseFurtherLookup || err != nil {
+	// Objects in user-defined and temporary schemas are keyed by the schema
+	// ID instead of the database ID.
+	parentID, err := resolveSchemaID(ctx, txn, dbID, tn.Schema())
+	if err != nil {
+		return nil, err
+	}
+	if parentID == sqlbase.InvalidID {
+		if flags.required {
+			return nil, sqlbase.NewUndefinedRelationError(tn)
+		}
+		return nil, nil
+	}
+
+	if refuseFurtherLookup, table, err := tc.getUncommittedTable(parentID, tn, flags.required); refuseFurtherLookup || err != nil {
 		return nil, err
 	} else if mut := table.MutableTableDescriptor; mut != nil {
 		log.VEventf(ctx, 2, "found uncommitted table %d", mut.ID)
@@ -200,13 +206,6 @@ func (tc *TableCollection) getTableVersion(
 		log.Infof(ctx, "planner acquiring lease on table '%s'", tn)
 	}
 
-	if tn.SchemaName != tree.PublicSchemaName {
-		if flags.required {
-			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(tn))
-		}
-		return nil, nil
-	}
-
 	refuseFurtherLookup, dbID, err := tc.getUncommittedDatabaseID(tn.Catalog(), flags.required)
 	if refuseFurtherLookup || err != nil {
 		return nil, err
@@ -223,6 +222,19 @@ func (tc *TableCollection) getTableVersion(
 		}
 	}
 
+	// Objects in user-defined and temporary schemas are keyed by the schema
+	// ID instead of the database ID.
+	parentID, err := resolveSchemaID(ctx, txn, dbID, tn.Schema())
+	if err != nil {
+		return nil, err
+	}
+	if parentID == sqlbase.InvalidID {
+		if flags.required {
+			return nil, sqlbase.NewUndefinedRelationError(tn)
+		}
+		return nil, nil
+	}
+
 	// TODO(vivek): Ideally we'd avoid caching for only the
 	// system.descriptor and system.lease tables, because they are
 	// used for acquiring leases, creating a chicken&egg problem.
@@ -233,7 +245,7 @@ func (tc *TableCollection) getTableVersion(
 	avoidCache := flags.avoidCached || testDisableTableLeases ||
 		(tn.Catalog() == sqlbase.SystemDB.Name && tn.TableName.String() != sqlbase.RoleMembersTable.Name)
 
-	if refuseFurtherLookup, table, err := tc.getUncommittedTable(dbID, tn, flags.required); refuseFurtherLookup || err != nil {
+	if refuseFurtherLookup, table, err := tc.getUncommittedTable(parentID, tn, flags.required); refuseFurtherLookup || err != nil {
 		return nil, err
 	} else if immut := table.ImmutableTableDescriptor; immut != nil {
 		// If not forcing to resolve using KV, tables being added aren't visible.
@@ -268,14 +280,14 @@ func (tc *TableCollection) getTableVersion(
 	// transaction.
 	for _, table := range tc.leasedTables {
 		if table.Name == string(tn.TableName) &&
-			table.ParentID == dbID {
+			table.NamespaceParentID() == parentID {
 			log.VEventf(ctx, 2, "found table in table collection for table '%s'", tn)
 			return table, nil
 		}
 	}
 
 	origTimestamp := txn.OrigTimestamp()
-	table, expiration, err := tc.leaseMgr.AcquireByName(ctx, origTimestamp, dbID, tn.Table())
+	table, expiration, err := tc.leaseMgr.AcquireByName(ctx, origTimestamp, parentID, tn.Table())
 	if err != nil {
 		// Read the descriptor from the store in the face of some specific errors
 		// because of a known limitation of AcquireByName. See the known
@@ -514,14 +526,15 @@ func (tc *TableCollection) getUncommittedDatabaseID(
 
 // getUncommittedTable returns a table for the requested tablename
 // if the requested tablename is for a table modified within the transaction
-// affiliated with the LeaseCollection.
+// affiliated with the LeaseCollection. parentID is the namespace parent of
+// the table (see TableDescriptor.NamespaceParentID).
 //
 // The first return value "refuseFurtherLookup" is true when there is
 // a known deletion of that table, so it would be invalid to miss the
 // cache and go to KV (where the descriptor prior to the DROP may
 // still exist).
 func (tc *TableCollection) getUncommittedTable(
-	dbID sqlbase.ID, tn *tree.TableName, required bool,
+	parentID sqlbase.ID, tn *tree.TableName, required bool,
 ) (refuseFurtherLookup bool, table uncommittedTable, err error) {
 	// Walk latest to earliest so that a DROP TABLE followed by a CREATE TABLE
 	// with the same name will result in the CREATE TABLE being seen.
@@ -535,7 +548,7 @@ func (tc *TableCollection) getUncommittedTable(
 		// effect of it.
 		for _, drain := range mutTbl.DrainingNames {
 			if drain.Name == string(tn.TableName) &&
-				drain.ParentID == dbID {
+				drain.ParentID == parentID {
 				// Table name has gone away.
 				if required {
 					// If it's required here, say it doesn't exist.
@@ -549,7 +562,7 @@ func (tc *TableCollection) getUncommittedTable(
 
 		// Do we know about a table with this name?
 		if mutTbl.Name == string(tn.TableName) &&
-			mutTbl.ParentID == dbID {
+			mutTbl.NamespaceParentID() == parentID {
 			// Right state?
 			if err = filterTableState(mutTbl.TableDesc()); err != nil && err != errTableAdding {
 				if !required {
@@ -126,11 +126,19 @@ type tableWriterBase struct {
 	b *client.Batch
 	// batchSize is the current batch size (when known).
 	batchSize int
+	// triggers are the triggers fired by the statement, if any.
+	triggers tableTriggers
+	// constraints queues the checks of the deferrable constraints. It is nil
+	// outside of SQL sessions.
+	constraints tree.DeferrableConstraints
 }
 
-func (tb *tableWriterBase) init(txn *client.Txn) {
+func (tb *tableWriterBase) init(txn *client.Txn, evalCtx *tree.EvalContext) {
 	tb.txn = txn
 	tb.b = txn.NewBatch()
+	if evalCtx != nil {
+		tb.constraints = evalCtx.Constraints
+	}
 }
 
 // flushAndStartNewBatch shares the common flushAndStartNewBatch()
@@ -143,7 +151,7 @@ func (tb *tableWriterBase) flushAndStartNewBatch(
 	}
 	tb.b = tb.txn.NewBatch()
 	tb.batchSize = 0
-	return nil
+	return tb.triggers.fireAfter(ctx)
 }
 
 // curBatchSize shares the common curBatchSize() code between extendedTableWriters().
@@ -153,10 +161,12 @@ func (tb *tableWriterBase) curBatchSize() int { return tb.batchSize }
 func (tb *tableWriterBase) finalize(
 	ctx context.Context, tableDesc *sqlbase.ImmutableTableDescriptor,
 ) (err error) {
-	if tb.autoCommit == autoCommitEnabled {
+	if tb.autoCommit == autoCommitEnabled && !tb.triggers.hasPendingAfter() &&
+		(tb.constraints == nil || !tb.constraints.HasChecks()) {
 		// An auto-txn can commit the transaction with the batch. This is an
 		// optimization to avoid an extra round-trip to the transaction
-		// coordinator.
+		// coordinator. It is not possible when AFTER triggers or constraint
+		// checks must still run in the transaction.
 		err = tb.txn.CommitInBatch(ctx, tb.b)
 	} else {
 		err = tb.txn.Run(ctx, tb.b)
@@ -165,7 +175,12 @@ func (tb *tableWriterBase) finalize(
 	if err != nil {
 		return row.ConvertBatchError(ctx, tableDesc, tb.b)
 	}
-	return nil
+	if tb.constraints != nil {
+		if err := tb.constraints.RunStatementChecks(ctx, tb.txn); err != nil {
+			return err
+		}
+	}
+	return tb.triggers.fireAfterStatement(ctx)
 }
 
 func (tb *tableWriterBase) enableAutoCommit() {
@@ -183,6 +198,10 @@ type batchedTableWriter interface {
 
 	// batchedValues accesses one row in the current batch.
 	batchedValues(rowIdx int) tree.Datums
+
+	// upsertTriggers returns the triggers fired by the upsert, which are
+	// initialized by the upsertNode.
+	upsertTriggers() *tableTriggers
 }
 
 var _ batchedTableWriter = (*tableUpserter)(nil)
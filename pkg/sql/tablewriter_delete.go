@@ -34,6 +34,10 @@ type tableDeleter struct {
 
 	rd    row.Deleter
 	alloc *sqlbase.DatumAlloc
+
+	// evalCtx is used to compute the virtual computed columns of the rows
+	// scanned by deleteAllRowsScan and deleteIndexScan.
+	evalCtx *tree.EvalContext
 }
 
 // desc is part of the tableWriter interface.
@@ -43,8 +47,9 @@ func (*tableDeleter) desc() string { return "deleter" }
 func (td *tableDeleter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
 
 // init is part of the tableWriter interface.
-func (td *tableDeleter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	td.tableWriterBase.init(txn)
+func (td *tableDeleter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	td.tableWriterBase.init(txn, evalCtx)
+	td.evalCtx = evalCtx
 	return nil
 }
 
@@ -63,7 +68,25 @@ func (td *tableDeleter) atBatchEnd(_ context.Context, _ bool) error { return nil
 
 func (td *tableDeleter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
 	td.batchSize++
-	return td.rd.DeleteRow(ctx, td.b, values, row.CheckFKs, traceKV)
+	if err := td.rd.DeleteRow(ctx, td.b, values, row.CheckFKs, traceKV); err != nil {
+		return err
+	}
+	if td.triggers.hasAfter(tree.TriggerDelete) {
+		oldRow := td.triggers.makeRow(td.rd.FetchColIDtoRowIndex, values)
+		td.triggers.queueAfter(tree.TriggerDelete, oldRow, nil /* newRow */)
+	}
+	return nil
+}
+
+// fireBeforeTriggers fires the BEFORE DELETE triggers of the table for a row
+// about to be deleted. It returns true if the row must not be deleted.
+func (td *tableDeleter) fireBeforeTriggers(ctx context.Context, values tree.Datums) (bool, error) {
+	if !td.triggers.hasBefore(tree.TriggerDelete) {
+		return false, nil
+	}
+	oldRow := td.triggers.makeRow(td.rd.FetchColIDtoRowIndex, values)
+	skip, _, err := td.triggers.fireBefore(ctx, tree.TriggerDelete, oldRow, nil /* newRow */)
+	return skip, err
 }
 
 // fastPathDeleteAvailable returns true if the fastDelete optimization can be used.
@@ -164,6 +187,7 @@ func (td *tableDeleter) deleteAllRowsScan(
 		ValNeededForCol: valNeededForCol,
 	}
 	if err := rf.Init(
+		td.evalCtx,
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, td.alloc, tableArgs,
 	); err != nil {
 		return resume, err
@@ -276,6 +300,7 @@ func (td *tableDeleter) deleteIndexScan(
 		ValNeededForCol: valNeededForCol,
 	}
 	if err := rf.Init(
+		td.evalCtx,
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, td.alloc, tableArgs,
 	); err != nil {
 		return resume, err
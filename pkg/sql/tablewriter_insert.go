@@ -32,15 +32,27 @@ type tableInserter struct {
 func (*tableInserter) desc() string { return "inserter" }
 
 // init is part of the tableWriter interface.
-func (ti *tableInserter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	ti.tableWriterBase.init(txn)
+func (ti *tableInserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	ti.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
 // row is part of the tableWriter interface.
 func (ti *tableInserter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
 	ti.batchSize++
-	return ti.ri.InsertRow(ctx, ti.b, values, false /* overwrite */, row.CheckFKs, traceKV)
+	if err := ti.ri.InsertRow(ctx, ti.b, values, false /* overwrite */, row.CheckFKs, traceKV); err != nil {
+		return err
+	}
+	ti.triggers.queueAfterInsert(&ti.ri, values)
+	return nil
+}
+
+// fireBeforeTriggers fires the BEFORE INSERT triggers of the table for a row
+// about to be inserted, and applies their modifications to values. It returns
+// true if the row must not be inserted.
+func (ti *tableInserter) fireBeforeTriggers(ctx context.Context, values tree.Datums) (bool, error) {
+	skip, _, err := ti.triggers.fireBeforeInsert(ctx, &ti.ri, values)
+	return skip, err
 }
 
 // atBatchEnd is part of the extendedTableWriter interface.
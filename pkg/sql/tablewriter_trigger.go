@@ -0,0 +1,526 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// maxTriggerDepth is the maximum number of triggers which can fire
+// recursively, when the body of a trigger modifies a table with triggers.
+const maxTriggerDepth = 32
+
+// triggerDepthKey is the context key under which the number of enclosing
+// trigger executions is stored.
+type triggerDepthKey struct{}
+
+// preparedTrigger is a trigger of a table, prepared for execution.
+type preparedTrigger struct {
+	name   string
+	events tree.TriggerEvents
+	// query is the body of the trigger, in which the references to the
+	// columns of the NEW and OLD rows are replaced by placeholders.
+	query string
+	// refs describes the values of the placeholders of the query, in order.
+	refs []triggerRowRef
+	// returnsRow is set for row-level BEFORE triggers whose body is a SELECT
+	// statement. Its result replaces the columns of the NEW row that it names.
+	returnsRow bool
+}
+
+// triggerRowRef is a reference to a column of the NEW or OLD row in the body
+// of a trigger.
+type triggerRowRef struct {
+	old bool
+	// colIdx is the index of the column in the public columns of the table.
+	colIdx int
+}
+
+// triggerEvents returns the events on which the given trigger fires.
+func triggerEvents(trig *sqlbase.TableDescriptor_Trigger) tree.TriggerEvents {
+	var events tree.TriggerEvents
+	if trig.OnInsert {
+		events |= tree.TriggerInsert
+	}
+	if trig.OnUpdate {
+		events |= tree.TriggerUpdate
+	}
+	if trig.OnDelete {
+		events |= tree.TriggerDelete
+	}
+	return events
+}
+
+// parseTriggerBody parses the body of the given trigger of the given table, and
+// replaces the references to the columns of the NEW and OLD rows with
+// placeholders annotated with the types of the columns. The placeholders are
+// numbered in order of first reference; the returned refs describe them.
+func parseTriggerBody(
+	desc *sqlbase.TableDescriptor, trig *sqlbase.TableDescriptor_Trigger,
+) (tree.Statement, []triggerRowRef, error) {
+	stmts, err := parser.Parse(trig.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var stmt tree.Statement
+	if len(stmts) == 1 {
+		stmt = stmts[0].AST
+	}
+	switch stmt.(type) {
+	case *tree.Select, *tree.Insert, *tree.Update, *tree.Delete:
+	default:
+		return nil, nil, pgerror.UnimplementedWithIssueDetail(28296, "body",
+			"only a single SELECT, INSERT, UPDATE or DELETE statement is supported as the body of a trigger")
+	}
+
+	var refs []triggerRowRef
+	refIdx := make(map[triggerRowRef]int)
+	stmt, err = tree.SimpleStmtVisit(stmt, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch t := expr.(type) {
+		case *tree.Placeholder:
+			return false, expr, pgerror.Newf(pgerror.CodeUndefinedParameterError,
+				"there is no parameter %s", t)
+		case *tree.UnresolvedName:
+			if t.NumParts != 2 || (t.Parts[1] != "new" && t.Parts[1] != "old") {
+				break
+			}
+			if t.Star {
+				return false, expr, pgerror.UnimplementedWithIssueDetailf(28296, "row star",
+					"%s is not supported in the body of a trigger", tree.ErrString(t))
+			}
+			ref := triggerRowRef{old: t.Parts[1] == "old", colIdx: -1}
+			for i := range desc.Columns {
+				if desc.Columns[i].Name == t.Parts[0] {
+					ref.colIdx = i
+					break
+				}
+			}
+			if ref.colIdx == -1 {
+				return false, expr, pgerror.Newf(pgerror.CodeUndefinedColumnError,
+					"record %q has no field %q", t.Parts[1], t.Parts[0])
+			}
+			idx, ok := refIdx[ref]
+			if !ok {
+				idx = len(refs)
+				refIdx[ref] = idx
+				refs = append(refs, ref)
+			}
+			return false, &tree.AnnotateTypeExpr{
+				Expr:       &tree.Placeholder{Idx: tree.PlaceholderIdx(idx)},
+				Type:       &desc.Columns[ref.colIdx].Type,
+				SyntaxMode: tree.AnnotateShort,
+			}, nil
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return stmt, refs, nil
+}
+
+// makeTrigger prepares the given trigger of the given table for execution.
+func makeTrigger(
+	desc *sqlbase.TableDescriptor, trig *sqlbase.TableDescriptor_Trigger,
+) (preparedTrigger, error) {
+	stmt, refs, err := parseTriggerBody(desc, trig)
+	if err != nil {
+		return preparedTrigger{}, pgerror.Wrapf(err, pgerror.CodeInvalidObjectDefinitionError,
+			"trigger %q", trig.Name)
+	}
+	if trig.ForEachStatement && len(refs) > 0 {
+		return preparedTrigger{}, pgerror.Newf(pgerror.CodeInvalidObjectDefinitionError,
+			"statement-level trigger %q cannot refer to the NEW and OLD rows", trig.Name)
+	}
+	_, isSelect := stmt.(*tree.Select)
+	return preparedTrigger{
+		name:   trig.Name,
+		events: triggerEvents(trig),
+		query:  tree.AsStringWithFlags(stmt, tree.FmtParsable),
+		refs:   refs,
+		returnsRow: isSelect && !trig.ForEachStatement &&
+			trig.ActionTime == sqlbase.TableDescriptor_Trigger_BEFORE,
+	}, nil
+}
+
+// pendingTriggerRow is a row modified by a statement, for which AFTER
+// triggers fire once the batch in which it was written has been run.
+type pendingTriggerRow struct {
+	event  tree.TriggerEvents
+	oldRow tree.Datums
+	newRow tree.Datums
+}
+
+// tableTriggers holds the triggers of the table modified by a tableWriter.
+// The rows passed to the triggers hold the values of the public
+// columns of the table, in order; they are nil when the row does not apply to
+// the event (OLD for INSERT, NEW for DELETE), in which case their columns are
+// NULL in the body of the triggers.
+type tableTriggers struct {
+	txn     *client.Txn
+	evalCtx *tree.EvalContext
+	ie      *SessionBoundInternalExecutor
+	desc    *sqlbase.ImmutableTableDescriptor
+
+	// before and after hold the row-level triggers.
+	before       []preparedTrigger
+	after        []preparedTrigger
+	beforeEvents tree.TriggerEvents
+	afterEvents  tree.TriggerEvents
+
+	// afterStmt holds the statement-level AFTER triggers which fire on the
+	// events of the statement, once all its rows have been written.
+	afterStmt []preparedTrigger
+
+	// hasComputedCols is set if the table has computed columns, which BEFORE
+	// triggers cannot modify the inputs of.
+	hasComputedCols bool
+
+	// pending holds the rows for which AFTER triggers must fire after the
+	// current batch has been run.
+	pending []pendingTriggerRow
+}
+
+// init prepares the triggers of the given table, if there are any, and fires
+// the statement-level BEFORE triggers of the given events, which are those of
+// the statement. It is called by the nodes which execute INSERT, UPSERT,
+// UPDATE and DELETE statements; other users of tableWriters, like schema
+// changes, do not fire triggers.
+func (tt *tableTriggers) init(
+	ctx context.Context,
+	txn *client.Txn,
+	evalCtx *tree.EvalContext,
+	desc *sqlbase.ImmutableTableDescriptor,
+	events tree.TriggerEvents,
+) error {
+	if len(desc.Triggers) == 0 {
+		return nil
+	}
+	var ie *SessionBoundInternalExecutor
+	if evalCtx != nil {
+		ie, _ = evalCtx.InternalExecutor.(*SessionBoundInternalExecutor)
+	}
+	if ie == nil {
+		return pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+			"cannot modify table %q, which has triggers, in this context", desc.Name)
+	}
+	*tt = tableTriggers{txn: txn, evalCtx: evalCtx, ie: ie, desc: desc}
+	for i := range desc.Columns {
+		tt.hasComputedCols = tt.hasComputedCols || desc.Columns[i].IsComputed()
+	}
+	var beforeStmt []preparedTrigger
+	for i := range desc.Triggers {
+		trig := &desc.Triggers[i]
+		t, err := makeTrigger(desc.TableDesc(), trig)
+		if err != nil {
+			return err
+		}
+		switch {
+		case trig.ForEachStatement && t.events&events == 0:
+		case trig.ForEachStatement && trig.ActionTime == sqlbase.TableDescriptor_Trigger_BEFORE:
+			beforeStmt = append(beforeStmt, t)
+		case trig.ForEachStatement:
+			tt.afterStmt = append(tt.afterStmt, t)
+		case trig.ActionTime == sqlbase.TableDescriptor_Trigger_BEFORE:
+			tt.before = append(tt.before, t)
+			tt.beforeEvents |= t.events
+		default:
+			tt.after = append(tt.after, t)
+			tt.afterEvents |= t.events
+		}
+	}
+	for i := range beforeStmt {
+		if _, _, err := tt.run(ctx, &beforeStmt[i], nil /* oldRow */, nil /* newRow */); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasPendingAfter returns true if AFTER triggers must still fire once the
+// current batch has been run.
+func (tt *tableTriggers) hasPendingAfter() bool {
+	return len(tt.pending) > 0 || len(tt.afterStmt) > 0
+}
+
+// hasBefore returns true if BEFORE triggers fire on the given event.
+func (tt *tableTriggers) hasBefore(event tree.TriggerEvents) bool {
+	return tt.beforeEvents&event != 0
+}
+
+// hasAfter returns true if AFTER triggers fire on the given event.
+func (tt *tableTriggers) hasAfter(event tree.TriggerEvents) bool {
+	return tt.afterEvents&event != 0
+}
+
+// makeRow returns the values of the public columns of the table, taken from
+// values as mapped by colIDtoRowIndex. The columns which are not mapped are
+// NULL.
+func (tt *tableTriggers) makeRow(
+	colIDtoRowIndex map[sqlbase.ColumnID]int, values tree.Datums,
+) tree.Datums {
+	row := make(tree.Datums, len(tt.desc.Columns))
+	for i := range tt.desc.Columns {
+		if idx, ok := colIDtoRowIndex[tt.desc.Columns[i].ID]; ok {
+			row[i] = values[idx]
+		} else {
+			row[i] = tree.DNull
+		}
+	}
+	return row
+}
+
+// run executes the body of the given trigger for the given rows.
+func (tt *tableTriggers) run(
+	ctx context.Context, t *preparedTrigger, oldRow, newRow tree.Datums,
+) ([]tree.Datums, sqlbase.ResultColumns, error) {
+	depth, _ := ctx.Value(triggerDepthKey{}).(int)
+	if depth >= maxTriggerDepth {
+		return nil, nil, pgerror.Newf(pgerror.CodeProgramLimitExceededError,
+			"trigger %q exceeded the maximum depth of %d nested triggers", t.name, maxTriggerDepth)
+	}
+	ctx = context.WithValue(ctx, triggerDepthKey{}, depth+1)
+
+	args := make([]interface{}, len(t.refs))
+	for i, ref := range t.refs {
+		row := newRow
+		if ref.old {
+			row = oldRow
+		}
+		if row == nil {
+			args[i] = tree.DNull
+		} else {
+			args[i] = row[ref.colIdx]
+		}
+	}
+	rows, cols, err := tt.ie.QueryWithCols(ctx, "trigger-"+t.name, tt.txn, t.query, args...)
+	if err != nil {
+		return nil, nil, pgerror.Wrapf(err, pgerror.CodeTriggeredActionExceptionError,
+			"trigger %q", t.name)
+	}
+	return rows, cols, nil
+}
+
+// fireBefore fires the BEFORE triggers of the given event for a row. The
+// triggers whose body is a SELECT statement replace the columns of newRow
+// named by its result; the indexes of the columns which they modify are
+// returned. If such a trigger returns no row, skip is set and the row must not
+// be written.
+func (tt *tableTriggers) fireBefore(
+	ctx context.Context, event tree.TriggerEvents, oldRow, newRow tree.Datums,
+) (skip bool, modified util.FastIntSet, _ error) {
+	for i := range tt.before {
+		t := &tt.before[i]
+		if t.events&event == 0 {
+			continue
+		}
+		rows, cols, err := tt.run(ctx, t, oldRow, newRow)
+		if err != nil {
+			return false, modified, err
+		}
+		if !t.returnsRow {
+			continue
+		}
+		switch len(rows) {
+		case 0:
+			return true, modified, nil
+		case 1:
+		default:
+			return false, modified, pgerror.Newf(pgerror.CodeCardinalityViolationError,
+				"trigger %q returned more than one row", t.name)
+		}
+		if newRow == nil {
+			// The result of a trigger on DELETE only determines whether the row
+			// is deleted.
+			continue
+		}
+		for j := range cols {
+			colIdx, err := triggerResultColumn(tt.desc.TableDesc(), t.name, &cols[j])
+			if err != nil {
+				return false, modified, err
+			}
+			d := rows[0][j]
+			if d.Compare(tt.evalCtx, newRow[colIdx]) == 0 {
+				continue
+			}
+			if tt.hasComputedCols {
+				return false, modified, pgerror.Newf(pgerror.CodeFeatureNotSupportedError,
+					"trigger %q cannot modify rows of table %q, which has computed columns",
+					t.name, tt.desc.Name)
+			}
+			newRow[colIdx] = d
+			modified.Add(colIdx)
+		}
+	}
+	return false, modified, nil
+}
+
+// triggerResultColumn returns the index of the column of the given table which
+// the given result column of a BEFORE trigger replaces.
+func triggerResultColumn(
+	desc *sqlbase.TableDescriptor, name string, res *sqlbase.ResultColumn,
+) (int, error) {
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if col.Name != res.Name {
+			continue
+		}
+		if col.IsComputed() {
+			return -1, pgerror.Newf(pgerror.CodeInvalidColumnReferenceError,
+				"trigger %q cannot modify computed column %q", name, col.Name)
+		}
+		if res.Typ.Family() != types.UnknownFamily && !res.Typ.Equivalent(&col.Type) {
+			return -1, pgerror.Newf(pgerror.CodeDatatypeMismatchError,
+				"trigger %q returned type %s for column %q of type %s",
+				name, res.Typ.SQLString(), col.Name, col.Type.SQLString())
+		}
+		return i, nil
+	}
+	return -1, pgerror.Newf(pgerror.CodeUndefinedColumnError,
+		"trigger %q returned column %q, which does not exist in table %q",
+		name, res.Name, desc.Name)
+}
+
+// applyModified copies the columns of row modified by BEFORE triggers into
+// values, as mapped by colIDtoRowIndex, and verifies that their new values
+// satisfy the constraints of the columns.
+func (tt *tableTriggers) applyModified(
+	row tree.Datums,
+	modified util.FastIntSet,
+	colIDtoRowIndex map[sqlbase.ColumnID]int,
+	values tree.Datums,
+) error {
+	for colIdx, ok := modified.Next(0); ok; colIdx, ok = modified.Next(colIdx + 1) {
+		col := &tt.desc.Columns[colIdx]
+		idx, written := colIDtoRowIndex[col.ID]
+		if !written {
+			return pgerror.Newf(pgerror.CodeTriggeredDataChangeViolationError,
+				"BEFORE trigger cannot modify column %q, which is not written by the statement",
+				col.Name)
+		}
+		if !col.Nullable && row[colIdx] == tree.DNull {
+			return sqlbase.NewNonNullViolationError(col.Name)
+		}
+		d, err := sqlbase.LimitValueWidth(&col.Type, row[colIdx], &col.Name)
+		if err != nil {
+			return err
+		}
+		values[idx] = d
+	}
+	return nil
+}
+
+// fireBeforeInsert fires the BEFORE INSERT triggers for a row about to be
+// inserted by ri, and applies their modifications to values. It returns true
+// if the row must not be inserted, and whether a trigger modified it.
+func (tt *tableTriggers) fireBeforeInsert(
+	ctx context.Context, ri *row.Inserter, values tree.Datums,
+) (skip, modified bool, _ error) {
+	if !tt.hasBefore(tree.TriggerInsert) {
+		return false, false, nil
+	}
+	newRow := tt.makeRow(ri.InsertColIDtoRowIndex, values)
+	skip, cols, err := tt.fireBefore(ctx, tree.TriggerInsert, nil /* oldRow */, newRow)
+	if skip || err != nil {
+		return skip, false, err
+	}
+	return false, !cols.Empty(), tt.applyModified(newRow, cols, ri.InsertColIDtoRowIndex, values)
+}
+
+// queueAfterInsert records a row inserted by ri for the AFTER INSERT triggers.
+func (tt *tableTriggers) queueAfterInsert(ri *row.Inserter, values tree.Datums) {
+	if tt.hasAfter(tree.TriggerInsert) {
+		newRow := tt.makeRow(ri.InsertColIDtoRowIndex, values)
+		tt.queueAfter(tree.TriggerInsert, nil /* oldRow */, newRow)
+	}
+}
+
+// fireBeforeUpdate fires the BEFORE UPDATE triggers for a row about to be
+// updated by ru, and applies their modifications to updateValues. It returns
+// true if the row must not be updated, and whether a trigger modified it.
+func (tt *tableTriggers) fireBeforeUpdate(
+	ctx context.Context, ru *row.Updater, oldValues, updateValues tree.Datums,
+) (skip, modified bool, _ error) {
+	if !tt.hasBefore(tree.TriggerUpdate) {
+		return false, false, nil
+	}
+	oldRow := tt.makeRow(ru.FetchColIDtoRowIndex, oldValues)
+	newRow := tt.makeRow(ru.FetchColIDtoRowIndex, oldValues)
+	for i := range tt.desc.Columns {
+		if idx, ok := ru.UpdateColIDtoRowIndex[tt.desc.Columns[i].ID]; ok {
+			newRow[i] = updateValues[idx]
+		}
+	}
+	skip, cols, err := tt.fireBefore(ctx, tree.TriggerUpdate, oldRow, newRow)
+	if skip || err != nil {
+		return skip, false, err
+	}
+	return false, !cols.Empty(), tt.applyModified(newRow, cols, ru.UpdateColIDtoRowIndex, updateValues)
+}
+
+// queueAfterUpdate records a row updated by ru for the AFTER UPDATE triggers.
+// Both oldValues and newValues hold the fetched columns of ru.
+func (tt *tableTriggers) queueAfterUpdate(ru *row.Updater, oldValues, newValues tree.Datums) {
+	if tt.hasAfter(tree.TriggerUpdate) {
+		oldRow := tt.makeRow(ru.FetchColIDtoRowIndex, oldValues)
+		newRow := tt.makeRow(ru.FetchColIDtoRowIndex, newValues)
+		tt.queueAfter(tree.TriggerUpdate, oldRow, newRow)
+	}
+}
+
+// queueAfter records a modified row for the AFTER triggers of the given event.
+func (tt *tableTriggers) queueAfter(event tree.TriggerEvents, oldRow, newRow tree.Datums) {
+	tt.pending = append(tt.pending, pendingTriggerRow{event: event, oldRow: oldRow, newRow: newRow})
+}
+
+// fireAfter fires the AFTER triggers for the rows written by the batch which
+// has just been run, in the order in which they were written.
+func (tt *tableTriggers) fireAfter(ctx context.Context) error {
+	for _, r := range tt.pending {
+		for i := range tt.after {
+			t := &tt.after[i]
+			if t.events&r.event == 0 {
+				continue
+			}
+			if _, _, err := tt.run(ctx, t, r.oldRow, r.newRow); err != nil {
+				return err
+			}
+		}
+	}
+	tt.pending = tt.pending[:0]
+	return nil
+}
+
+// fireAfterStatement fires the statement-level AFTER triggers, once the last
+// batch of the statement has been run. They fire even if the statement did not
+// modify any row.
+func (tt *tableTriggers) fireAfterStatement(ctx context.Context) error {
+	if err := tt.fireAfter(ctx); err != nil {
+		return err
+	}
+	for i := range tt.afterStmt {
+		if _, _, err := tt.run(ctx, &tt.afterStmt[i], nil /* oldRow */, nil /* newRow */); err != nil {
+			return err
+		}
+	}
+	return nil
+}
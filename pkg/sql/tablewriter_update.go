@@ -32,8 +32,8 @@ type tableUpdater struct {
 func (*tableUpdater) desc() string { return "updater" }
 
 // init is part of the tableWriter interface.
-func (tu *tableUpdater) init(txn *client.Txn, _ *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+func (tu *tableUpdater) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	tu.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -50,7 +50,22 @@ func (tu *tableUpdater) rowForUpdate(
 	ctx context.Context, oldValues, updateValues tree.Datums, traceKV bool,
 ) (tree.Datums, error) {
 	tu.batchSize++
-	return tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, row.CheckFKs, traceKV)
+	newValues, err := tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, row.CheckFKs, traceKV)
+	if err != nil {
+		return nil, err
+	}
+	tu.triggers.queueAfterUpdate(&tu.ru, oldValues, newValues)
+	return newValues, nil
+}
+
+// fireBeforeTriggers fires the BEFORE UPDATE triggers of the table for a row
+// about to be updated, and applies their modifications to updateValues. It
+// returns true if the row must not be updated.
+func (tu *tableUpdater) fireBeforeTriggers(
+	ctx context.Context, oldValues, updateValues tree.Datums,
+) (bool, error) {
+	skip, _, err := tu.triggers.fireBeforeUpdate(ctx, &tu.ru, oldValues, updateValues)
+	return skip, err
 }
 
 // atBatchEnd is part of the extendedTableWriter interface.
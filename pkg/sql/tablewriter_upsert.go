@@ -62,10 +62,15 @@ type tableUpserterBase struct {
 
 	// For allocation avoidance.
 	indexKeyPrefix []byte
+
+	// checkHelper verifies the check constraints of the inserted rows of a
+	// table with triggers, once the BEFORE INSERT triggers have run. It is nil
+	// for other tables, whose constraints are verified by the upsertNode.
+	checkHelper *sqlbase.CheckHelper
 }
 
 func (tu *tableUpserterBase) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 	tableDesc := tu.tableDesc()
 
 	tu.insertRows.Init(
@@ -116,6 +121,29 @@ func (tu *tableUpserterBase) tableDesc() *sqlbase.ImmutableTableDescriptor {
 	return tu.ri.Helper.TableDesc
 }
 
+// upsertTriggers is part of the batchedTableWriter interface.
+func (tu *tableUpserterBase) upsertTriggers() *tableTriggers {
+	return &tu.triggers
+}
+
+// fireBeforeInsertTriggers fires the BEFORE INSERT triggers of the table for a
+// row about to be inserted by the upsert, and applies their modifications to
+// values. The check constraints are then verified if checkHelper is set. It
+// returns true if the row must not be inserted, and whether a trigger modified
+// it.
+func (tu *tableUpserterBase) fireBeforeInsertTriggers(
+	ctx context.Context, values tree.Datums,
+) (skip, modified bool, _ error) {
+	skip, modified, err := tu.triggers.fireBeforeInsert(ctx, &tu.ri, values)
+	if skip || err != nil || tu.checkHelper == nil {
+		return skip, modified, err
+	}
+	if err := tu.checkHelper.LoadEvalRow(tu.ri.InsertColIDtoRowIndex, values, false); err != nil {
+		return false, false, err
+	}
+	return false, modified, tu.checkHelper.CheckEval(tu.triggers.evalCtx)
+}
+
 // row is part of the tableWriter interface.
 func (tu *tableUpserterBase) row(ctx context.Context, row tree.Datums, traceKV bool) error {
 	tu.batchSize++
@@ -243,6 +271,10 @@ type tableUpserter struct {
 	conflictIndex sqlbase.IndexDescriptor
 	anyComputed   bool
 
+	// conflictIndexPredicate evaluates the predicate of conflictIndex, if it
+	// is a partial index. Only the rows that satisfy it can conflict.
+	conflictIndexPredicate sqlbase.PartialIndexPredicates
+
 	evalCtx *tree.EvalContext
 
 	// These are set for ON CONFLICT DO UPDATE, but not for DO NOTHING
@@ -271,7 +303,7 @@ func (*tableUpserter) desc() string { return "upserter" }
 
 // init is part of the tableWriter interface.
 func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 
 	tu.evalCtx = evalCtx
 
@@ -282,6 +314,13 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 
 	tableDesc := tu.tableDesc()
 
+	tu.conflictIndexPredicate, err = sqlbase.MakePartialIndexPredicates(
+		tableDesc, []sqlbase.IndexDescriptor{tu.conflictIndex}, evalCtx,
+	)
+	if err != nil {
+		return err
+	}
+
 	requestedCols := tableDesc.Columns
 
 	if len(tu.updateCols) == 0 {
@@ -329,6 +368,7 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 	}
 
 	if err := tu.fetcher.Init(
+		tu.evalCtx,
 		false /* reverse */, false /*returnRangeInfo*/, false /* isCheck */, tu.alloc, tableArgs,
 	); err != nil {
 		return err
@@ -399,7 +439,21 @@ func (tu *tableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error {
 
 		// Do we have a conflict?
 		if conflictingRowIdx == -1 {
-			// We don't have a conflict. This is a new row in KV. Create it.
+			// We don't have a conflict. Fire the BEFORE INSERT triggers, if
+			// any. They can modify the row, or skip it.
+			skip, modified, err := tu.fireBeforeInsertTriggers(ctx, insertRow)
+			if err != nil {
+				return err
+			}
+			if skip {
+				continue
+			}
+			if modified {
+				// The triggers may have modified the PK of the row.
+				conflictingRowPK = nil
+			}
+
+			// This is a new row in KV. Create it.
 			resultRow, err = tu.insertNonConflictingRow(
 				ctx, tu.b, insertRow, conflictingRowPK, pkToRowIdx, tableDesc, traceKV,
 			)
@@ -454,7 +508,8 @@ func (tu *tableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error {
 			}
 
 			// We know there was a row already, and we know we need to update it. Do it.
-			resultRow, err = tu.updateConflictingRow(
+			var skipped bool
+			resultRow, skipped, err = tu.updateConflictingRow(
 				ctx, tu.b, insertRow,
 				conflictingRowPK, conflictingRowIdx, existingRow,
 				pkToRowIdx, tableDesc, traceKV,
@@ -462,6 +517,10 @@ func (tu *tableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error {
 			if err != nil {
 				return err
 			}
+			if skipped {
+				// A BEFORE UPDATE trigger skipped the row.
+				continue
+			}
 
 			// We have processed a row, remember this for the rows affected
 			// count in case we're not populating rowsUpserted below.
@@ -502,6 +561,8 @@ func (tu *tableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error {
 // - resultRow is the row that was updated, shaped in the order of the table
 //   descriptor. This may be different than the shape of insertRow if there are
 //   nullable columns. This is only returned if collectRows is true.
+// - skipped is set if a BEFORE UPDATE trigger skipped the row, which is then
+//   not updated.
 // Input/Outputs:
 // - pkToRowIdx is extended with the index of the new entry in existingRows.
 func (tu *tableUpserter) updateConflictingRow(
@@ -514,13 +575,20 @@ func (tu *tableUpserter) updateConflictingRow(
 	pkToRowIdx map[string]int,
 	tableDesc *sqlbase.ImmutableTableDescriptor,
 	traceKV bool,
-) (resultRow tree.Datums, err error) {
+) (resultRow tree.Datums, skipped bool, err error) {
 	// First compute all the updates via SET (or the pseudo-SET generated
 	// for UPSERT statements).
 
 	updateValues, err := tu.evaler.eval(insertRow, conflictingRowValues, tu.updateValues)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	// Fire the BEFORE UPDATE triggers, if any. They can modify the updated
+	// values, or skip the row.
+	skip, _, err := tu.triggers.fireBeforeUpdate(ctx, &tu.ru, conflictingRowValues, updateValues)
+	if skip || err != nil {
+		return nil, skip, err
 	}
 
 	checkHelper := tu.fkTables[tableDesc.ID].CheckHelper
@@ -557,7 +625,7 @@ func (tu *tableUpserter) updateConflictingRow(
 		// of updateValues.
 		updateValues, err = tu.evaler.evalComputedCols(newValues, updateValues)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		// Ensure that all the values produced by SET comply with the schema constraints.
@@ -567,7 +635,7 @@ func (tu *tableUpserter) updateConflictingRow(
 		// However the SET expression can be arbitrary and can introduce errors
 		// downstream, so we need to (re)validate here.
 		if err := enforceLocalColumnConstraints(updateValues, tu.ru.UpdateCols); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		if checkHelper != nil {
@@ -581,10 +649,10 @@ func (tu *tableUpserter) updateConflictingRow(
 
 			// Check CHECK constraints.
 			if err := checkHelper.LoadEvalRow(tu.ru.FetchColIDtoRowIndex, newValues, false); err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			if err := checkHelper.CheckEval(tu.evalCtx); err != nil {
-				return nil, err
+				return nil, false, err
 			}
 		}
 	}
@@ -596,8 +664,9 @@ func (tu *tableUpserter) updateConflictingRow(
 		ctx, b, conflictingRowValues, updateValues, row.CheckFKs, traceKV,
 	)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	tu.triggers.queueAfterUpdate(&tu.ru, conflictingRowValues, updatedRow)
 
 	// Keep the slice for reuse.
 	tu.updateValues = updateValues[:0]
@@ -609,7 +678,7 @@ func (tu *tableUpserter) updateConflictingRow(
 	updatedConflictingRowPK, _, err := sqlbase.EncodeIndexKey(
 		tableDesc.TableDesc(), &tableDesc.PrimaryIndex, tu.evaler.ccIvarContainer.Mapping, updatedRow, tu.indexKeyPrefix)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// It's possible that the PK for the updated values is different
@@ -645,7 +714,7 @@ func (tu *tableUpserter) updateConflictingRow(
 		if err := tu.appendKnownConflictingRow(
 			ctx, updatedRow, updatedConflictingRowPK, pkToRowIdx,
 		); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
@@ -656,11 +725,11 @@ func (tu *tableUpserter) updateConflictingRow(
 
 	// We only need a result row if we're collecting rows.
 	if !tu.collectRows {
-		return nil, nil
+		return nil, false, nil
 	}
 
 	// We now need a row that has the shape of the result row.
-	return tu.makeResultFromRow(updatedRow, tu.evaler.ccIvarContainer.Mapping), nil
+	return tu.makeResultFromRow(updatedRow, tu.evaler.ccIvarContainer.Mapping), false, nil
 }
 
 // insertNonConflictingRow inserts the source row insertRow
@@ -690,6 +759,7 @@ func (tu *tableUpserter) insertNonConflictingRow(
 		ctx, b, insertRow, false /* ignoreConflicts */, row.CheckFKs, traceKV); err != nil {
 		return nil, err
 	}
+	tu.triggers.queueAfterInsert(&tu.ri, insertRow)
 
 	// We may not know the conflictingRowPK yet for the new row, for
 	// example when the conflicting index was a secondary index.
@@ -777,7 +847,9 @@ func (tu *tableUpserter) getConflictingRowPK(
 //   row is already present in KV or not with a lookup.
 //
 // - if the conflicting index is secondary, that index is used to look
-//   up the primary key. If the row is absent, no key is generated.
+//   up the primary key. If the row is absent, no key is generated. If
+//   the index is partial, the rows that do not satisfy its predicate
+//   are not looked up.
 //
 // The keys returned are guaranteed to be unique.
 //
@@ -824,8 +896,20 @@ func (tu *tableUpserter) upsertRowPKSpans(
 	// case, some spots in the slice will be nil (indicating no conflict) and the
 	// others will be conflicting rows.
 	b := tu.txn.NewBatch()
+	// rowIdxs contains the index in tu.insertRows of the row looked up by
+	// each request in the batch.
+	rowIdxs := make([]int, 0, tu.insertRows.Len())
 	for i := 0; i < tu.insertRows.Len(); i++ {
 		insertRow := tu.insertRows.At(i)
+		holds, err := tu.conflictIndexPredicate.Holds(0, tu.ri.InsertColIDtoRowIndex, insertRow)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !holds {
+			// The row would not be in the partial index, so it cannot
+			// conflict.
+			continue
+		}
 		entries, err := sqlbase.EncodeSecondaryIndex(
 			tableDesc.TableDesc(), &tu.conflictIndex, tu.ri.InsertColIDtoRowIndex, insertRow)
 		if err != nil {
@@ -837,6 +921,7 @@ func (tu *tableUpserter) upsertRowPKSpans(
 				log.VEventf(ctx, 2, "Get %s", entry.Key)
 			}
 			b.Get(entry.Key)
+			rowIdxs = append(rowIdxs, i)
 		}
 	}
 
@@ -844,7 +929,8 @@ func (tu *tableUpserter) upsertRowPKSpans(
 		return nil, nil, err
 	}
 	conflictingPKs := make(map[int]roachpb.Key)
-	for i, result := range b.Results {
+	for j, result := range b.Results {
+		i := rowIdxs[j]
 		if len(result.Rows) == 1 {
 			if result.Rows[0].Value != nil {
 				upsertRowPK, err := sqlbase.ExtractIndexKey(tu.alloc, tableDesc.TableDesc(), result.Rows[0])
@@ -38,8 +38,8 @@ type fastTableUpserter struct {
 func (*fastTableUpserter) desc() string { return "fast upserter" }
 
 // init is part of the tableWriter interface.
-func (tu *fastTableUpserter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+func (tu *fastTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	tu.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -101,7 +101,6 @@ func (*optTableUpserter) desc() string { return "opt upserter" }
 // row is part of the tableWriter interface.
 func (tu *optTableUpserter) row(ctx context.Context, row tree.Datums, traceKV bool) error {
 	tu.batchSize++
-	tu.resultCount++
 
 	// Consult the canary column to determine whether to insert or update. For
 	// more details on how canary columns work, see the block comment on
@@ -110,16 +109,24 @@ func (tu *optTableUpserter) row(ctx context.Context, row tree.Datums, traceKV bo
 	if tu.canaryOrdinal == -1 {
 		// No canary column means that existing row should be overwritten (i.e.
 		// the insert and update columns are the same, so no need to choose).
+		tu.resultCount++
 		return tu.insertNonConflictingRow(ctx, tu.b, row[:insertEnd], true /* overwrite */, traceKV)
 	}
 	if row[tu.canaryOrdinal] == tree.DNull {
-		// No conflict, so insert a new row.
-		return tu.insertNonConflictingRow(ctx, tu.b, row[:insertEnd], false /* overwrite */, traceKV)
+		// No conflict, so insert a new row. The BEFORE INSERT triggers, if any,
+		// can modify the row, or skip it.
+		insertRow := row[:insertEnd]
+		if skip, _, err := tu.fireBeforeInsertTriggers(ctx, insertRow); skip || err != nil {
+			return err
+		}
+		tu.resultCount++
+		return tu.insertNonConflictingRow(ctx, tu.b, insertRow, false /* overwrite */, traceKV)
 	}
 
 	// If no columns need to be updated, then possibly collect the unchanged row.
 	fetchEnd := insertEnd + len(tu.fetchCols)
 	if len(tu.updateCols) == 0 {
+		tu.resultCount++
 		if !tu.collectRows {
 			return nil
 		}
@@ -127,18 +134,46 @@ func (tu *optTableUpserter) row(ctx context.Context, row tree.Datums, traceKV bo
 		return err
 	}
 
-	// Update the row.
+	// Update the row. The BEFORE UPDATE triggers, if any, can modify the
+	// updated values, or skip the row.
 	updateEnd := fetchEnd + len(tu.updateCols)
+	fetchRow, updateValues := row[insertEnd:fetchEnd], row[fetchEnd:updateEnd]
+	if skip, err := tu.fireBeforeUpdateTriggers(ctx, fetchRow, updateValues); skip || err != nil {
+		return err
+	}
+	tu.resultCount++
 	return tu.updateConflictingRow(
 		ctx,
 		tu.b,
-		row[insertEnd:fetchEnd],
-		row[fetchEnd:updateEnd],
+		fetchRow,
+		updateValues,
 		tu.tableDesc(),
 		traceKV,
 	)
 }
 
+// fireBeforeUpdateTriggers fires the BEFORE UPDATE triggers of the table for a
+// conflicting row about to be updated, and applies their modifications to
+// updateValues. The check constraints are then verified on the updated row if
+// checkHelper is set. It returns true if the row must not be updated.
+func (tu *optTableUpserter) fireBeforeUpdateTriggers(
+	ctx context.Context, fetchRow, updateValues tree.Datums,
+) (bool, error) {
+	skip, _, err := tu.triggers.fireBeforeUpdate(ctx, &tu.ru, fetchRow, updateValues)
+	if skip || err != nil || tu.checkHelper == nil {
+		return skip, err
+	}
+	newValues := make(tree.Datums, len(fetchRow))
+	copy(newValues, fetchRow)
+	for i := range tu.ru.UpdateCols {
+		newValues[tu.ru.FetchColIDtoRowIndex[tu.ru.UpdateCols[i].ID]] = updateValues[i]
+	}
+	if err := tu.checkHelper.LoadEvalRow(tu.ru.FetchColIDtoRowIndex, newValues, false); err != nil {
+		return false, err
+	}
+	return false, tu.checkHelper.CheckEval(tu.triggers.evalCtx)
+}
+
 // atBatchEnd is part of the extendedTableWriter interface.
 func (tu *optTableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error {
 	// Nothing to do, because the row method does everything.
@@ -156,6 +191,7 @@ func (tu *optTableUpserter) insertNonConflictingRow(
 		ctx, b, insertRow, overwrite, row.CheckFKs, traceKV); err != nil {
 		return err
 	}
+	tu.triggers.queueAfterInsert(&tu.ri, insertRow)
 
 	if !tu.collectRows {
 		return nil
@@ -200,10 +236,11 @@ func (tu *optTableUpserter) updateConflictingRow(
 	// Queue the update in KV. This also returns an "update row"
 	// containing the updated values for every column in the
 	// table. This is useful for RETURNING, which we collect below.
-	_, err := tu.ru.UpdateRow(ctx, b, fetchRow, updateValues, row.CheckFKs, traceKV)
+	newValues, err := tu.ru.UpdateRow(ctx, b, fetchRow, updateValues, row.CheckFKs, traceKV)
 	if err != nil {
 		return err
 	}
+	tu.triggers.queueAfterUpdate(&tu.ru, fetchRow, newValues)
 
 	// We only need a result row if we're collecting rows.
 	if !tu.collectRows {
@@ -43,7 +43,7 @@ func (*strictTableUpserter) desc() string { return "strict upserter" }
 
 // init is part of the tableWriter interface.
 func (tu *strictTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 
 	err := tu.tableUpserterBase.init(txn, evalCtx)
 	if err != nil {
@@ -67,9 +67,21 @@ func (tu *strictTableUpserter) atBatchEnd(ctx context.Context, traceKV bool) err
 			continue
 		}
 
-		if err := tu.ri.InsertRow(ctx, tu.b, insertRow, true, row.CheckFKs, traceKV); err != nil {
+		// Fire the BEFORE INSERT triggers, if any. They can modify the row, or
+		// skip it. A row modified by a trigger was not checked for conflicts,
+		// so it must not overwrite an existing row.
+		skip, modified, err := tu.fireBeforeInsertTriggers(ctx, insertRow)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		if err := tu.ri.InsertRow(ctx, tu.b, insertRow, !modified, row.CheckFKs, traceKV); err != nil {
 			return err
 		}
+		tu.triggers.queueAfterInsert(&tu.ri, insertRow)
 
 		tu.resultCount++
 
@@ -99,12 +111,15 @@ func (tu *strictTableUpserter) atBatchEnd(ctx context.Context, traceKV bool) err
 	return nil
 }
 
-// Get all unique indexes and store them in tu.ConflictIndexes.
+// Get all unique indexes and store them in tu.ConflictIndexes. Partial indexes
+// are skipped, since an entry for a conflicting row may legitimately be absent
+// from them; a conflict in a partial unique index is reported as an error by
+// the insert instead.
 func (tu *strictTableUpserter) getUniqueIndexes() (err error) {
 	tableDesc := tu.tableDesc()
 	indexes := tableDesc.Indexes
 	for _, index := range indexes {
-		if index.Unique {
+		if index.Unique && !index.IsPartial() {
 			tu.conflictIndexes = append(tu.conflictIndexes, index)
 		}
 	}
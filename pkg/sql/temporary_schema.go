@@ -0,0 +1,393 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+)
+
+// Temporary tables live in a per-session temporary schema, named
+// pg_temp_<session ID>. A temporary schema has no descriptor: it is only a
+// namespace entry (database ID, schema name) -> schema ID, created lazily the
+// first time the session creates a temporary table in that database. The
+// temporary tables themselves use the schema ID as their namespace parent
+// (see TableDescriptor.NamespaceParentID).
+//
+// Temporary schemas are dropped, along with the objects in them, when the
+// owning session ends. Sessions that do not end gracefully (e.g. because
+// their node crashed) leave their temporary schemas behind; these are
+// removed by the TemporaryObjectCleaner.
+
+// TempObjectCleanupInterval is the interval at which the
+// TemporaryObjectCleaner looks for orphaned temporary objects.
+var TempObjectCleanupInterval = settings.RegisterValidatedDurationSetting(
+	"sql.temp_object_cleaner.cleanup_interval",
+	"how often to clean up orphaned temporary objects",
+	30*time.Minute,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return pgerror.Newf(pgerror.CodeInvalidParameterValueError,
+				"cleanup interval must be positive: %s", v)
+		}
+		return nil
+	},
+)
+
+// temporarySchemaName returns the name of the temporary schema owned by the
+// session with the given ID.
+func temporarySchemaName(sessionID ClusterWideID) string {
+	return fmt.Sprintf("%s_%d_%d", sessiondata.PgTempSchemaName, sessionID.Hi, sessionID.Lo)
+}
+
+// isTemporarySchemaName returns true if scName is the name of a temporary
+// schema, owned by any session. It returns false for the pg_temp alias.
+func isTemporarySchemaName(scName string) bool {
+	return strings.HasPrefix(scName, sessiondata.PgTempSchemaName+"_")
+}
+
+// isTemporarySchemaRef returns true if scName refers to a temporary schema,
+// either through the pg_temp alias or by its full name.
+func isTemporarySchemaRef(scName string) bool {
+	return scName == sessiondata.PgTempSchemaName || isTemporarySchemaName(scName)
+}
+
+// temporarySchemaSessionID returns the ID of the session owning the
+// temporary schema with the given name. ok is false if scName is not the name
+// of a temporary schema.
+func temporarySchemaSessionID(scName string) (_ ClusterWideID, ok bool) {
+	if !isTemporarySchemaName(scName) {
+		return ClusterWideID{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(scName, sessiondata.PgTempSchemaName+"_"), "_")
+	if len(parts) != 2 {
+		return ClusterWideID{}, false
+	}
+	hi, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, false
+	}
+	lo, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, false
+	}
+	return ClusterWideID{Uint128: uint128.FromInts(hi, lo)}, true
+}
+
+// getNamespaceEntries returns the names and IDs of all the namespace entries
+// with the given parent ID.
+func getNamespaceEntries(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID,
+) (map[string]sqlbase.ID, error) {
+	prefix := sqlbase.MakeNameMetadataKey(parentID, "")
+	kvs, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]sqlbase.ID, len(kvs))
+	for _, kv := range kvs {
+		_, name, err := encoding.DecodeUnsafeStringAscending(bytes.TrimPrefix(kv.Key, prefix), nil)
+		if err != nil {
+			return nil, err
+		}
+		entries[name] = sqlbase.ID(kv.ValueInt())
+	}
+	return entries, nil
+}
+
+// getTemporarySchemaNames returns the names of the temporary schemas in the
+// database with ID dbID, of all sessions, keyed by schema ID.
+func getTemporarySchemaNames(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID,
+) (map[sqlbase.ID]string, error) {
+	entries, err := getNamespaceEntries(ctx, txn, dbID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[sqlbase.ID]string)
+	for name, id := range entries {
+		if isTemporarySchemaName(name) {
+			names[id] = name
+		}
+	}
+	return names, nil
+}
+
+// getOrCreateTemporarySchemaID returns the ID of the session's temporary
+// schema in the database with ID dbID, creating the schema if it does not
+// exist yet.
+func (p *planner) getOrCreateTemporarySchemaID(
+	ctx context.Context, dbID sqlbase.ID,
+) (sqlbase.ID, error) {
+	// Nodes running older versions do not clean up temporary schemas.
+	if !p.ExecCfg().Settings.Version.IsActive(cluster.VersionTemporaryTables) {
+		return sqlbase.InvalidID, pgerror.Newf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			`temporary tables require all nodes to be upgraded to %s`,
+			cluster.VersionByKey(cluster.VersionTemporaryTables),
+		)
+	}
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	if tempSchemaName == "" {
+		if p.sessionDataMutator == nil {
+			return sqlbase.InvalidID, pgerror.New(pgerror.CodeFeatureNotSupportedError,
+				"cannot create temporary tables in this context")
+		}
+		tempSchemaName = temporarySchemaName(p.ExtendedEvalContext().SessionID)
+	}
+
+	key := sqlbase.NewSchemaKey(dbID, tempSchemaName)
+	schemaID, err := getDescriptorID(ctx, p.txn, key)
+	if err != nil {
+		return sqlbase.InvalidID, err
+	}
+	if schemaID == sqlbase.InvalidID {
+		schemaID, err = GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+		if err != nil {
+			return sqlbase.InvalidID, err
+		}
+		log.VEventf(ctx, 2, "CPut %s -> %d", key.Key(), schemaID)
+		if err := p.txn.CPut(ctx, key.Key(), schemaID, nil); err != nil {
+			return sqlbase.InvalidID, err
+		}
+	}
+
+	// From now on, the temporary schema is searched first by name resolution.
+	if p.SessionData().SearchPath.GetTemporarySchemaName() == "" {
+		p.sessionDataMutator.SetTemporarySchemaName(tempSchemaName)
+	}
+	return schemaID, nil
+}
+
+// cleanupSessionTempObjects drops the temporary schema with the given name
+// and all the objects in it, in every database. The internal executor must be
+// bound to a session whose temporary schema is tempSchemaName, since name
+// resolution refuses to access other sessions' temporary schemas.
+func cleanupSessionTempObjects(
+	ctx context.Context, db *client.DB, ie sqlutil.InternalExecutor, tempSchemaName string,
+) error {
+	var dbIDs map[string]sqlbase.ID
+	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		dbIDs, err = getNamespaceEntries(ctx, txn, keys.RootNamespaceID)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	dbNames := make([]string, 0, len(dbIDs))
+	for dbName := range dbIDs {
+		dbNames = append(dbNames, dbName)
+	}
+	sort.Strings(dbNames)
+	for _, dbName := range dbNames {
+		if err := cleanupTempSchemaInDatabase(
+			ctx, db, ie, dbName, dbIDs[dbName], tempSchemaName,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupTempSchemaInDatabase drops the temporary schema with the given name
+// in a single database, along with the objects in it.
+func cleanupTempSchemaInDatabase(
+	ctx context.Context,
+	db *client.DB,
+	ie sqlutil.InternalExecutor,
+	dbName string,
+	dbID sqlbase.ID,
+	tempSchemaName string,
+) error {
+	schemaKey := sqlbase.NewSchemaKey(dbID, tempSchemaName)
+	var tableNames []string
+	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		tableNames = nil
+		schemaID, err := getDescriptorID(ctx, txn, schemaKey)
+		if err != nil || schemaID == sqlbase.InvalidID {
+			return err
+		}
+		entries, err := getNamespaceEntries(ctx, txn, schemaID)
+		if err != nil {
+			return err
+		}
+		for name := range entries {
+			tn := tree.MakeTableNameWithSchema(
+				tree.Name(dbName), tree.Name(tempSchemaName), tree.Name(name))
+			tableNames = append(tableNames, tn.String())
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(tableNames) > 0 {
+		sort.Strings(tableNames)
+		if _, err := ie.Exec(ctx, "delete-temp-tables", nil, /* txn */
+			fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", strings.Join(tableNames, ", ")),
+		); err != nil {
+			return err
+		}
+	}
+
+	log.VEventf(ctx, 2, "Del %s", schemaKey.Key())
+	return db.Del(ctx, schemaKey.Key())
+}
+
+// TemporaryObjectCleaner periodically drops the temporary schemas, and the
+// objects in them, of sessions that no longer exist. Sessions drop their
+// temporary schemas when they end, so this only has work to do when a
+// session could not clean up after itself, e.g. because its node crashed.
+type TemporaryObjectCleaner struct {
+	settings  *cluster.Settings
+	db        *client.DB
+	ie        sqlutil.InternalExecutor
+	ieFactory sqlutil.SessionBoundInternalExecutorFactory
+}
+
+// NewTemporaryObjectCleaner initializes the TemporaryObjectCleaner with the
+// required arguments, but does not start it.
+func NewTemporaryObjectCleaner(
+	settings *cluster.Settings,
+	db *client.DB,
+	ie sqlutil.InternalExecutor,
+	ieFactory sqlutil.SessionBoundInternalExecutorFactory,
+) *TemporaryObjectCleaner {
+	return &TemporaryObjectCleaner{
+		settings:  settings,
+		db:        db,
+		ie:        ie,
+		ieFactory: ieFactory,
+	}
+}
+
+// Start initializes the background thread which periodically cleans up
+// orphaned temporary objects.
+func (c *TemporaryObjectCleaner) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(TempObjectCleanupInterval.Get(&c.settings.SV))
+			select {
+			case <-timer.C:
+				timer.Read = true
+				if err := c.doTemporaryObjectCleanup(ctx); err != nil {
+					log.Warningf(ctx, "failed to clean up orphaned temporary objects: %s", err)
+				}
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// doTemporaryObjectCleanup performs one round of cleanup of the temporary
+// schemas whose owning session no longer exists.
+func (c *TemporaryObjectCleaner) doTemporaryObjectCleanup(ctx context.Context) error {
+	// Collect the temporary schemas before listing the sessions, so that every
+	// schema found belongs to a session that was already running when the
+	// sessions are listed.
+	tempSchemaNames := make(map[string]struct{})
+	if err := c.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		dbIDs, err := getNamespaceEntries(ctx, txn, keys.RootNamespaceID)
+		if err != nil {
+			return err
+		}
+		for _, dbID := range dbIDs {
+			entries, err := getNamespaceEntries(ctx, txn, dbID)
+			if err != nil {
+				return err
+			}
+			for name := range entries {
+				if _, ok := temporarySchemaSessionID(name); ok {
+					tempSchemaNames[name] = struct{}{}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(tempSchemaNames) == 0 {
+		return nil
+	}
+
+	rows, err := c.ie.Query(
+		ctx, "list-sessions", nil, /* txn */
+		`SELECT node_id, session_id FROM crdb_internal.cluster_sessions`,
+	)
+	if err != nil {
+		return err
+	}
+	activeSessions := make(map[uint128.Uint128]struct{})
+	// Nodes that could not be reached may still be running the sessions that
+	// own some of the temporary schemas; leave those alone.
+	unreachableNodes := make(map[int32]struct{})
+	for _, row := range rows {
+		nodeID := int32(tree.MustBeDInt(row[0]))
+		if row[1] == tree.DNull {
+			unreachableNodes[nodeID] = struct{}{}
+			continue
+		}
+		sessionID, err := StringToClusterWideID(string(tree.MustBeDString(row[1])))
+		if err != nil {
+			continue
+		}
+		activeSessions[sessionID.Uint128] = struct{}{}
+	}
+
+	for name := range tempSchemaNames {
+		sessionID, _ := temporarySchemaSessionID(name)
+		if _, ok := activeSessions[sessionID.Uint128]; ok {
+			continue
+		}
+		if _, ok := unreachableNodes[sessionID.GetNodeID()]; ok {
+			continue
+		}
+		log.Infof(ctx, "cleaning up orphaned temporary schema %s", name)
+		sd := &sessiondata.SessionData{
+			User:          security.RootUser,
+			SearchPath:    sessiondata.MakeSearchPath(nil).WithTemporarySchemaName(name),
+			SequenceState: sessiondata.NewSequenceState(),
+			DataConversion: sessiondata.DataConversionConfig{
+				Location: time.UTC,
+			},
+		}
+		if err := cleanupSessionTempObjects(ctx, c.db, c.ieFactory(ctx, sd), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
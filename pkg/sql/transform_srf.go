@@ -108,6 +108,11 @@ func (v *srfExtractionVisitor) VisitPost(expr tree.Expr) tree.Expr {
 func (v *srfExtractionVisitor) lookupSRF(t *tree.FuncExpr) (*tree.FunctionDefinition, error) {
 	fd, err := t.Func.Resolve(v.searchPath)
 	if err != nil {
+		if pgErr, ok := pgerror.GetPGCause(err); ok && pgErr.Code == pgerror.CodeUndefinedFunctionError {
+			// The name may refer to a user-defined function, which is not a
+			// generator.
+			return nil, nil
+		}
 		return nil, err
 	}
 	if fd.Class != tree.GeneratorClass {
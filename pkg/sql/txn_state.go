@@ -110,6 +110,17 @@ type txnState struct {
 	// activeSavepointName stores the name of the active savepoint,
 	// or is empty if no savepoint is active.
 	activeSavepointName tree.Name
+
+	// savepoints is the stack of savepoints established through SAVEPOINT
+	// statements, not including the restart savepoint tracked by
+	// activeSavepointName. See conn_executor_savepoints.go.
+	savepoints savepointStack
+
+	// cleanupDeferred is set when the SQL txn moved to the Aborted state while
+	// there were active savepoints. In that case the KV txn is not rolled back
+	// right away, so that a ROLLBACK TO SAVEPOINT can resume it. If that doesn't
+	// happen, the KV txn is rolled back when the SQL txn finishes.
+	cleanupDeferred bool
 }
 
 // txnType represents the type of a SQL transaction.
@@ -223,6 +234,8 @@ func (ts *txnState) resetForNewSQLTxn(
 
 	// Discard the old schemaChangers, if any.
 	ts.schemaChangers = schemaChangerCollection{}
+	ts.savepoints = nil
+	ts.cleanupDeferred = false
 }
 
 // finishSQLTxn finalizes a transaction's results and closes the root span for
@@ -237,6 +250,7 @@ func (ts *txnState) finishSQLTxn() {
 	if ts.sp == nil {
 		panic("No span in context? Was resetForNewSQLTxn() called previously?")
 	}
+	ts.rollbackIfCleanupDeferred()
 
 	if ts.recordingThreshold > 0 {
 		if r := tracing.GetRecording(ts.sp); r != nil {
@@ -261,6 +275,30 @@ func (ts *txnState) finishSQLTxn() {
 	ts.recordingThreshold = 0
 }
 
+// cleanupOnError rolls back the KV txn after an error moved the SQL txn to the
+// Aborted state. If there are active savepoints, the rollback is deferred (see
+// cleanupDeferred).
+func (ts *txnState) cleanupOnError(err error) {
+	if len(ts.savepoints) > 0 {
+		ts.cleanupDeferred = true
+		return
+	}
+	ts.mu.txn.CleanupOnError(ts.Ctx, err)
+}
+
+// rollbackIfCleanupDeferred rolls back the KV txn if cleanupOnError deferred
+// doing so.
+func (ts *txnState) rollbackIfCleanupDeferred() {
+	if !ts.cleanupDeferred {
+		return
+	}
+	ts.cleanupDeferred = false
+	ts.savepoints = nil
+	if err := ts.mu.txn.Rollback(ts.Ctx); err != nil {
+		log.Warningf(ts.Ctx, "txn rollback failed: %s", err)
+	}
+}
+
 // finishExternalTxn is a stripped-down version of finishSQLTxn used by
 // connExecutors that run within a higher-level transaction (through the
 // InternalExecutor). These guys don't want to mess with the transaction per-se,
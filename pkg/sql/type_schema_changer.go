@@ -0,0 +1,165 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+)
+
+// typeSchemaChanger finishes the addition of members to an enum type.
+//
+// Members are added to a type in the READ_ONLY state, and the new version of
+// the type is copied into the columns of the tables that use it. Once every
+// node has picked up the new versions of these tables, all the nodes are able
+// to interpret the new members, and the typeSchemaChanger makes them
+// writable.
+type typeSchemaChanger struct {
+	typeID  sqlbase.ID
+	execCfg *ExecutorConfig
+	// execAfter is the time after which the SchemaChangeManager should run the
+	// schema changer.
+	execAfter time.Time
+}
+
+// exec promotes the READ_ONLY members of the type to the ALL capability. It
+// is a no-op if the type has no READ_ONLY members or no longer exists.
+func (sc *typeSchemaChanger) exec(ctx context.Context) error {
+	var typeDesc *sqlbase.TypeDescriptor
+	if err := sc.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		typeDesc, err = sqlbase.GetTypeDescFromID(ctx, txn, sc.typeID)
+		return err
+	}); err != nil {
+		return err
+	}
+	if !typeDesc.HasReadOnlyMembers() {
+		return nil
+	}
+
+	// Only the members that were seen as READ_ONLY before waiting for the
+	// referencing tables can be promoted; members added concurrently must wait
+	// for their own round.
+	readOnly := make(map[string]struct{})
+	for i := range typeDesc.EnumMembers {
+		m := &typeDesc.EnumMembers[i]
+		if m.Capability == sqlbase.TypeDescriptor_EnumMember_READ_ONLY {
+			readOnly[m.LogicalRepresentation] = struct{}{}
+		}
+	}
+
+	retryOpts := retry.Options{
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     200 * time.Millisecond,
+		Multiplier:     2,
+	}
+	for _, id := range typeDesc.ReferencingDescriptorIDs {
+		if _, err := sc.execCfg.LeaseManager.WaitForOneVersion(ctx, id, retryOpts); err != nil {
+			// The table may have been dropped in the meantime, in which case there
+			// is nothing to wait for.
+			if exists, existsErr := sc.tableExists(ctx, id); existsErr == nil && !exists {
+				continue
+			}
+			return err
+		}
+	}
+
+	return sc.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		if err := txn.SetSystemConfigTrigger(); err != nil {
+			return err
+		}
+		typeDesc, err := sqlbase.GetTypeDescFromID(ctx, txn, sc.typeID)
+		if err != nil {
+			return err
+		}
+		for i := range typeDesc.EnumMembers {
+			m := &typeDesc.EnumMembers[i]
+			if _, ok := readOnly[m.LogicalRepresentation]; ok {
+				m.Capability = sqlbase.TypeDescriptor_EnumMember_ALL
+			}
+		}
+		typeDesc.Version++
+		if err := typeDesc.Validate(); err != nil {
+			return err
+		}
+
+		b := txn.NewBatch()
+		b.Put(sqlbase.MakeDescMetadataKey(typeDesc.ID), sqlbase.WrapDescriptor(typeDesc))
+		typ := typeDesc.MakeTypesT()
+		for _, id := range typeDesc.ReferencingDescriptorIDs {
+			tableDesc, err := sqlbase.GetMutableTableDescFromID(ctx, txn, id)
+			if err == sqlbase.ErrDescriptorNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !setReferencingColumnTypes(tableDesc, typ) {
+				continue
+			}
+			if err := tableDesc.MaybeIncrementVersion(ctx, txn); err != nil {
+				return err
+			}
+			if err := tableDesc.ValidateTable(sc.execCfg.Settings); err != nil {
+				return err
+			}
+			b.Put(sqlbase.MakeDescMetadataKey(tableDesc.ID), sqlbase.WrapDescriptor(tableDesc))
+		}
+		if log.V(2) {
+			log.Infof(ctx, "promoted %d members of type %d", len(readOnly), sc.typeID)
+		}
+		return txn.Run(ctx, b)
+	})
+}
+
+// tableExists returns whether a table with the given ID exists.
+func (sc *typeSchemaChanger) tableExists(ctx context.Context, id sqlbase.ID) (bool, error) {
+	exists := true
+	err := sc.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		_, err := sqlbase.GetTableDescFromID(ctx, txn, id)
+		if err == sqlbase.ErrDescriptorNotFound {
+			exists = false
+			return nil
+		}
+		return err
+	})
+	return exists, err
+}
+
+// setReferencingColumnTypes replaces the type of the columns of the table
+// that use the given user-defined type, including the columns being added, by
+// typ. It returns whether any column was updated.
+func setReferencingColumnTypes(desc *sqlbase.MutableTableDescriptor, typ *types.T) bool {
+	updated := false
+	update := func(col *sqlbase.ColumnDescriptor) {
+		if col.Type.Oid() == typ.Oid() {
+			col.Type = *typ
+			updated = true
+		}
+	}
+	for i := range desc.Columns {
+		update(&desc.Columns[i])
+	}
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil {
+			update(col)
+		}
+	}
+	return updated
+}
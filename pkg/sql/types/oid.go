@@ -52,6 +52,17 @@ var (
 		Family: ArrayFamily, Oid: oid.T_oidvector, ArrayContents: Oid, Locale: &emptyLocale}}
 )
 
+// OIDs of the spatial types. Postgres has no fixed OIDs for them, since they
+// are defined by the PostGIS extension, so they are chosen to not collide
+// with the builtin Postgres types nor with user-defined types (see
+// oidOffset).
+const (
+	GeometryOid       oid.Oid = 90000
+	GeographyOid      oid.Oid = 90001
+	GeometryArrayOid  oid.Oid = 90002
+	GeographyArrayOid oid.Oid = 90003
+)
+
 // OidToType maps Postgres object IDs to CockroachDB types.  We export the map
 // instead of a method so that other packages can iterate over the map directly.
 // Note that additional elements for the array Oid types are added in init().
@@ -84,12 +95,15 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_regtype:      RegType,
 	oid.T_text:         String,
 	oid.T_time:         Time,
+	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
 	oid.T_unknown:      Unknown,
 	oid.T_uuid:         Uuid,
 	oid.T_varbit:       VarBit,
 	oid.T_varchar:      VarChar,
+	GeometryOid:        Geometry,
+	GeographyOid:       Geography,
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -122,11 +136,14 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_regtype:      oid.T__regtype,
 	oid.T_text:         oid.T__text,
 	oid.T_time:         oid.T__time,
+	oid.T_timetz:       oid.T__timetz,
 	oid.T_timestamp:    oid.T__timestamp,
 	oid.T_timestamptz:  oid.T__timestamptz,
 	oid.T_uuid:         oid.T__uuid,
 	oid.T_varbit:       oid.T__varbit,
 	oid.T_varchar:      oid.T__varchar,
+	GeometryOid:        GeometryArrayOid,
+	GeographyOid:       GeographyArrayOid,
 }
 
 // familyToOid maps each type family to a default OID value that is used when
@@ -150,10 +167,14 @@ var familyToOid = map[Family]oid.Oid{
 	ArrayFamily:          oid.T_anyarray,
 	INetFamily:           oid.T_inet,
 	TimeFamily:           oid.T_time,
+	TimeTZFamily:         oid.T_timetz,
 	JsonFamily:           oid.T_jsonb,
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
 	AnyFamily:            oid.T_anyelement,
+	EnumFamily:           oid.T_anyenum,
+	GeometryFamily:       GeometryOid,
+	GeographyFamily:      GeographyOid,
 }
 
 // ArrayOids is a set of all oids which correspond to an array type.
@@ -166,6 +187,27 @@ func init() {
 	}
 }
 
+// oidOffset is the offset at which the OIDs of user-defined types start. It is
+// larger than the OIDs of all the builtin Postgres types, so the two never
+// collide.
+const oidOffset = 100000
+
+// TypeIDToOID converts the ID of a type descriptor into the OID of the
+// user-defined type that it describes.
+func TypeIDToOID(id uint32) oid.Oid {
+	return oid.Oid(id + oidOffset)
+}
+
+// UserDefinedTypeOIDToID converts the OID of a user-defined type into the ID of
+// the type descriptor that describes it. It returns false if the OID does not
+// belong to a user-defined type.
+func UserDefinedTypeOIDToID(o oid.Oid) (uint32, bool) {
+	if o < oidOffset {
+		return 0, false
+	}
+	return uint32(o) - oidOffset, true
+}
+
 // calcArrayOid returns the OID of the array type having elements of the given
 // type.
 func calcArrayOid(elemTyp *T) oid.Oid {
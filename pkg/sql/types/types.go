@@ -153,6 +153,31 @@ import (
 // When these types are themselves made into arrays, the Oids become T__int2vector and
 // T__oidvector, respectively.
 //
+// User-defined types
+// ------------------
+//
+// User-defined types are created through CREATE TYPE and are backed by a type
+// descriptor. Their values can be interpreted without access to that
+// descriptor, because the type carries everything that is needed in its
+// UDTMetadata field.
+//
+// | Field           | Description                                             |
+// |-----------------|---------------------------------------------------------|
+// | Family          | EnumFamily                                              |
+// | Oid             | OID derived from the descriptor ID (see TypeIDToOID)    |
+// | UDTMetadata     | Name of the type and its members, ordered by their      |
+// |                 | physical representations                                |
+//
+// Domains are created through CREATE DOMAIN. A domain is an existing base type
+// with constraints on its values, so it has the family, width and OID of its
+// base type; its UDTMetadata field holds the name of the domain and the ID of
+// its descriptor (see MakeDomain). The constraints of the domain are not part
+// of the type, so checking them requires access to the descriptor.
+//
+// The parser does not have access to type descriptors, so it produces
+// references to user-defined types that are resolved later on (see
+// MakeUnresolvedTypeReference).
+//
 type T struct {
 	// InternalType should never be directly referenced outside this package. The
 	// only reason it is exported is because gogoproto panics when printing the
@@ -249,6 +274,15 @@ var (
 	Time = &T{InternalType: InternalType{
 		Family: TimeFamily, Oid: oid.T_time, Locale: &emptyLocale}}
 
+	// TimeTZ is the type of a value specifying hour, minute, second (with no
+	// date component), along with the offset of the time zone in which it was
+	// specified. By default, it has microsecond precision. For example:
+	//
+	//   HH:MM:SS.ssssss+-ZZ:ZZ
+	//
+	TimeTZ = &T{InternalType: InternalType{
+		Family: TimeTZFamily, Oid: oid.T_timetz, Locale: &emptyLocale}}
+
 	// Timestamp is the type of a value specifying year, month, day, hour, minute,
 	// and second, but with no associated timezone. By default, it has microsecond
 	// precision. For example:
@@ -290,6 +324,23 @@ var (
 	INet = &T{InternalType: InternalType{
 		Family: INetFamily, Oid: oid.T_inet, Locale: &emptyLocale}}
 
+	// Geometry is the type of a planar spatial object, such as a point, line
+	// string or polygon. For example:
+	//
+	//   POINT(1 2)
+	//   SRID=4326;LINESTRING(0 0,1 1)
+	//
+	Geometry = &T{InternalType: InternalType{
+		Family: GeometryFamily, Oid: GeometryOid, Locale: &emptyLocale}}
+
+	// Geography is the type of a spatial object on the surface of the earth,
+	// with longitude/latitude coordinates. For example:
+	//
+	//   POINT(-73.98 40.75)
+	//
+	Geography = &T{InternalType: InternalType{
+		Family: GeographyFamily, Oid: GeographyOid, Locale: &emptyLocale}}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -311,8 +362,11 @@ var (
 		Uuid,
 		INet,
 		Time,
+		TimeTZ,
 		Jsonb,
 		VarBit,
+		Geometry,
+		Geography,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -335,6 +389,12 @@ var (
 	AnyTuple = &T{InternalType: InternalType{
 		Family: TupleFamily, TupleContents: []T{*Any}, Oid: oid.T_record, Locale: &emptyLocale}}
 
+	// AnyEnum is a special type used only during static analysis as a wildcard
+	// type that matches any enum type. Execution-time values should never have
+	// this type.
+	AnyEnum = &T{InternalType: InternalType{
+		Family: EnumFamily, Oid: oid.T_anyenum, Locale: &emptyLocale}}
+
 	// AnyCollatedString is a special type used only during static analysis as a
 	// wildcard type that matches a collated string with any locale. Execution-
 	// time values should never have this type.
@@ -466,7 +526,7 @@ func MakeScalar(family Family, o oid.Oid, precision, width int32, locale string)
 		panic(pgerror.AssertionFailedf("negative precision is not allowed"))
 	}
 	switch family {
-	case DecimalFamily, TimeFamily, TimestampFamily, TimestampTZFamily:
+	case DecimalFamily, TimeFamily, TimeTZFamily, TimestampFamily, TimestampTZFamily:
 	default:
 		if precision != 0 {
 			panic(pgerror.AssertionFailedf("type %s cannot have precision", family))
@@ -642,6 +702,19 @@ func MakeTime(precision int32) *T {
 		Family: TimeFamily, Oid: oid.T_time, Precision: precision, Locale: &emptyLocale}}
 }
 
+// MakeTimeTZ constructs a new instance of a TIMETZ type (oid = T_timetz) that
+// has at most the given number of fractional second digits.
+func MakeTimeTZ(precision int32) *T {
+	if precision == 0 {
+		return TimeTZ
+	}
+	if precision != 6 {
+		panic(pgerror.AssertionFailedf("precision %d is not currently supported", precision))
+	}
+	return &T{InternalType: InternalType{
+		Family: TimeTZFamily, Oid: oid.T_timetz, Precision: precision, Locale: &emptyLocale}}
+}
+
 // MakeTimestamp constructs a new instance of a TIMESTAMP type that has at most
 // the given number of fractional second digits.
 func MakeTimestamp(precision int32) *T {
@@ -696,6 +769,48 @@ func MakeLabeledTuple(contents []T, labels []string) *T {
 	}}
 }
 
+// MakeEnum constructs a new instance of an EnumFamily type with the given OID,
+// name and members. The members must be ordered by their physical
+// representations.
+func MakeEnum(typeOID oid.Oid, name string, members *EnumMetadata) *T {
+	return &T{InternalType: InternalType{
+		Family: EnumFamily,
+		Oid:    typeOID,
+		Locale: &emptyLocale,
+		UDTMetadata: &UserDefinedTypeMetadata{
+			Name:     name,
+			EnumData: members,
+		},
+	}}
+}
+
+// MakeDomain constructs a new instance of a domain with the given base type,
+// name and descriptor ID. The domain has the same family, width and OID as its
+// base type, so that its values are values of the base type.
+func MakeDomain(base *T, name string, domainID uint32) *T {
+	typ := *base
+	typ.InternalType.UDTMetadata = &UserDefinedTypeMetadata{
+		Name:     name,
+		DomainID: domainID,
+	}
+	return &typ
+}
+
+// MakeUnresolvedTypeReference constructs a reference to a user-defined type
+// with the given name parts (e.g. ["db", "public", "typ"]). The reference
+// must be resolved to a type descriptor before it can be used; see
+// IsUnresolved.
+func MakeUnresolvedTypeReference(nameParts []string) *T {
+	return &T{InternalType: InternalType{
+		Family: EnumFamily,
+		Locale: &emptyLocale,
+		UDTMetadata: &UserDefinedTypeMetadata{
+			Name:           strings.Join(nameParts, "."),
+			UnresolvedName: nameParts,
+		},
+	}}
+}
+
 // Family specifies a group of types that are compatible with one another. Types
 // in the same family can be compared, assigned, etc., but may differ from one
 // another in width, precision, locale, and other attributes. For example, it is
@@ -797,6 +912,56 @@ func (t *T) TupleLabels() []string {
 	return t.InternalType.TupleLabels
 }
 
+// UserDefined returns true if this is a user-defined type, or an unresolved
+// reference to one.
+func (t *T) UserDefined() bool {
+	return t.InternalType.UDTMetadata != nil
+}
+
+// IsUnresolved returns true if this is a reference to a user-defined type that
+// has not been resolved yet. See MakeUnresolvedTypeReference.
+func (t *T) IsUnresolved() bool {
+	return t.UserDefined() && len(t.InternalType.UDTMetadata.UnresolvedName) > 0
+}
+
+// UnresolvedName returns the name parts of an unresolved reference to a
+// user-defined type. It is nil for other types.
+func (t *T) UnresolvedName() []string {
+	if !t.UserDefined() {
+		return nil
+	}
+	return t.InternalType.UDTMetadata.UnresolvedName
+}
+
+// EnumData returns the members of an enum type. It is nil for other types,
+// including the AnyEnum wildcard type.
+func (t *T) EnumData() *EnumMetadata {
+	if !t.UserDefined() {
+		return nil
+	}
+	return t.InternalType.UDTMetadata.EnumData
+}
+
+// DomainID returns the ID of the descriptor of a domain. It is zero for other
+// types.
+func (t *T) DomainID() uint32 {
+	if !t.UserDefined() {
+		return 0
+	}
+	return t.InternalType.UDTMetadata.DomainID
+}
+
+// BaseType returns the base type of a domain, which has no constraints. Other
+// types are returned unchanged.
+func (t *T) BaseType() *T {
+	if t.DomainID() == 0 {
+		return t
+	}
+	typ := *t
+	typ.InternalType.UDTMetadata = nil
+	return &typ
+}
+
 // Name returns a single word description of the type that describes it
 // succinctly, but without all the details, such as width, locale, etc. The name
 // is sometimes the same as the name returned by SQLStandardName, but is more
@@ -828,6 +993,11 @@ func (t *T) Name() string {
 		return "date"
 	case DecimalFamily:
 		return "decimal"
+	case EnumFamily:
+		if t.Oid() == oid.T_anyenum {
+			return "anyenum"
+		}
+		return t.InternalType.UDTMetadata.Name
 	case FloatFamily:
 		switch t.Width() {
 		case 64:
@@ -837,6 +1007,10 @@ func (t *T) Name() string {
 		default:
 			panic(pgerror.AssertionFailedf("programming error: unknown float width: %d", t.Width()))
 		}
+	case GeographyFamily:
+		return "geography"
+	case GeometryFamily:
+		return "geometry"
 	case INetFamily:
 		return "inet"
 	case IntFamily:
@@ -873,6 +1047,8 @@ func (t *T) Name() string {
 		panic(pgerror.AssertionFailedf("unexpected OID: %d", t.Oid()))
 	case TimeFamily:
 		return "time"
+	case TimeTZFamily:
+		return "timetz"
 	case TimestampFamily:
 		return "timestamp"
 	case TimestampTZFamily:
@@ -901,6 +1077,19 @@ func (t *T) Name() string {
 //   int4[]       _int4
 //
 func (t *T) PGName() string {
+	if t.UserDefined() {
+		return t.InternalType.UDTMetadata.Name
+	}
+	switch t.Family() {
+	case GeometryFamily, GeographyFamily:
+		// The spatial types are not builtin Postgres types.
+		return t.Name()
+	case ArrayFamily:
+		switch t.ArrayContents().Family() {
+		case GeometryFamily, GeographyFamily:
+			return "_" + t.ArrayContents().Name()
+		}
+	}
 	name, ok := oid.TypeName[t.Oid()]
 	if ok {
 		return strings.ToLower(name)
@@ -945,6 +1134,8 @@ func (t *T) SQLStandardName() string {
 		return "date"
 	case DecimalFamily:
 		return "numeric"
+	case EnumFamily:
+		return t.Name()
 	case FloatFamily:
 		switch t.Width() {
 		case 32:
@@ -954,6 +1145,10 @@ func (t *T) SQLStandardName() string {
 		default:
 			panic(pgerror.AssertionFailedf("programming error: unknown float width: %d", t.Width()))
 		}
+	case GeographyFamily:
+		return "geography"
+	case GeometryFamily:
+		return "geometry"
 	case INetFamily:
 		return "inet"
 	case IntFamily:
@@ -1007,6 +1202,8 @@ func (t *T) SQLStandardName() string {
 		panic(pgerror.AssertionFailedf("unexpected OID: %d", t.Oid()))
 	case TimeFamily:
 		return "time without time zone"
+	case TimeTZFamily:
+		return "time with time zone"
 	case TimestampFamily:
 		return "timestamp without time zone"
 	case TimestampTZFamily:
@@ -1028,10 +1225,14 @@ func (t *T) SQLStandardName() string {
 // This is different from SQLString() in that it must report SQL standard names
 // that are compatible with PostgreSQL client expectations.
 func (t *T) InformationSchemaName() string {
-	// This is the same as SQLStandardName, except for the case of arrays.
+	// This is the same as SQLStandardName, except for the case of arrays and
+	// user-defined types.
 	if t.Family() == ArrayFamily {
 		return "ARRAY"
 	}
+	if t.UserDefined() && t.DomainID() == 0 {
+		return "USER-DEFINED"
+	}
 	return t.SQLStandardName()
 }
 
@@ -1039,6 +1240,13 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.DomainID() != 0 {
+		// Domains are referred to by their name, which must be quoted as an
+		// identifier.
+		var buf bytes.Buffer
+		lex.EncodeRestrictedSQLIdent(&buf, t.InternalType.UDTMetadata.Name, lex.EncNoFlags)
+		return buf.String()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -1084,13 +1292,30 @@ func (t *T) SQLString() string {
 	case JsonFamily:
 		// Only binary JSON is currently supported.
 		return "JSONB"
+	case EnumFamily:
+		if !t.UserDefined() {
+			break
+		}
+		// The name of user-defined types must be quoted as an identifier.
+		var buf bytes.Buffer
+		if t.IsUnresolved() {
+			for i, part := range t.UnresolvedName() {
+				if i > 0 {
+					buf.WriteByte('.')
+				}
+				lex.EncodeRestrictedSQLIdent(&buf, part, lex.EncNoFlags)
+			}
+		} else {
+			lex.EncodeRestrictedSQLIdent(&buf, t.Name(), lex.EncNoFlags)
+		}
+		return buf.String()
 	case TimestampFamily, TimestampTZFamily:
 		if t.Precision() != -1 {
 			return fmt.Sprintf("%s(%d)", strings.ToUpper(t.Name()), t.Precision())
 		}
 		// This is the timestamp with the default precision value
 		return strings.ToUpper(t.Name())
-	case TimeFamily:
+	case TimeFamily, TimeTZFamily:
 		if t.Precision() > 0 {
 			return fmt.Sprintf("%s(%d)", strings.ToUpper(t.Name()), t.Precision())
 		}
@@ -1158,6 +1383,16 @@ func (t *T) Equivalent(other *T) bool {
 		if !t.ArrayContents().Equivalent(other.ArrayContents()) {
 			return false
 		}
+
+	case EnumFamily:
+		// The AnyEnum wildcard type is equivalent to any enum type. Otherwise,
+		// enum types are only equivalent to themselves.
+		if t.Oid() == oid.T_anyenum || other.Oid() == oid.T_anyenum {
+			return true
+		}
+		if t.Oid() != other.Oid() {
+			return false
+		}
 	}
 
 	return true
@@ -1245,6 +1480,17 @@ func (t *InternalType) Identical(other *InternalType) bool {
 			return false
 		}
 	}
+	// A domain has the OID of its base type, but it is not identical to it.
+	var domainID, otherDomainID uint32
+	if t.UDTMetadata != nil {
+		domainID = t.UDTMetadata.DomainID
+	}
+	if other.UDTMetadata != nil {
+		otherDomainID = other.UDTMetadata.DomainID
+	}
+	if domainID != otherDomainID {
+		return false
+	}
 	return t.Oid == other.Oid
 }
 
@@ -1384,11 +1630,6 @@ func (t *T) upgradeType() error {
 			t.InternalType.Oid = calcArrayOid(t.ArrayContents())
 		}
 
-		// Marshaling/unmarshaling nested arrays is not yet supported.
-		if t.ArrayContents().Family() == ArrayFamily {
-			return pgerror.AssertionFailedf("nested array should never be unmarshaled")
-		}
-
 		// Zero out fields that may have been used to store information about
 		// the array element type, or which are no longer in use.
 		t.InternalType.Width = 0
@@ -1502,9 +1743,10 @@ func (t *T) downgradeType() error {
 		}
 
 	case ArrayFamily:
-		// Marshaling/unmarshaling nested arrays is not yet supported.
+		// Nested arrays cannot be represented in the array format used before
+		// 19.2, so they are only described by the ArrayContents field.
 		if t.ArrayContents().Family() == ArrayFamily {
-			return pgerror.AssertionFailedf("nested array should never be marshaled")
+			break
 		}
 
 		// Downgrade to array representation used before 19.2, in which the array
@@ -1607,6 +1849,8 @@ func (t *T) IsAmbiguous() bool {
 		return false
 	case ArrayFamily:
 		return t.ArrayContents().IsAmbiguous()
+	case EnumFamily:
+		return t.Oid() == oid.T_anyenum
 	}
 	return false
 }
@@ -1629,6 +1873,10 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 	switch t.Family() {
 	case JsonFamily:
 		return false, 23468
+	case EnumFamily:
+		return false, 27793
+	case GeometryFamily, GeographyFamily:
+		return false, 19313
 	default:
 		return true, 0
 	}
@@ -1651,6 +1899,8 @@ func IsDateTimeType(t *T) bool {
 		return true
 	case TimeFamily:
 		return true
+	case TimeTZFamily:
+		return true
 	case TimestampFamily:
 		return true
 	case TimestampTZFamily:
@@ -1741,6 +1991,10 @@ func init() {
 			typNameLiterals[name] = t
 		}
 	}
+	// The spatial types do not have builtin postgres OIDs, so they are not
+	// named by oid.TypeName.
+	typNameLiterals["geometry"] = Geometry
+	typNameLiterals["geography"] = Geography
 }
 
 // TypeForNonKeywordTypeName returns the column type for the string name of a
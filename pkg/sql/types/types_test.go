@@ -220,6 +220,14 @@ func TestTypes(t *testing.T) {
 			Family: TimeFamily, Oid: oid.T_time, Precision: 6, Locale: &emptyLocale}}},
 		{MakeTime(6), MakeScalar(TimeFamily, oid.T_time, 6, 0, emptyLocale)},
 
+		// TIMETZ
+		{MakeTimeTZ(0), TimeTZ},
+		{MakeTimeTZ(0), &T{InternalType: InternalType{
+			Family: TimeTZFamily, Oid: oid.T_timetz, Locale: &emptyLocale}}},
+		{MakeTimeTZ(6), &T{InternalType: InternalType{
+			Family: TimeTZFamily, Oid: oid.T_timetz, Precision: 6, Locale: &emptyLocale}}},
+		{MakeTimeTZ(6), MakeScalar(TimeTZFamily, oid.T_timetz, 6, 0, emptyLocale)},
+
 		// TIMESTAMP
 		{MakeTimestamp(0), &T{InternalType: InternalType{
 			Family: TimestampFamily, Precision: 0, Oid: oid.T_timestamp, Locale: &emptyLocale}}},
@@ -266,13 +274,7 @@ func TestTypes(t *testing.T) {
 			t.Errorf("expected <%v>, got <%v>", tc.expected.DebugString(), tc.actual.DebugString())
 		}
 
-		// Roundtrip type by marshaling, then unmarshaling. Only do this for non-
-		// nested array types (since we don't yet support marshaling/ummarshaling
-		// nested arrays).
-		if tc.actual.Family() == ArrayFamily && tc.actual.ArrayContents().Family() == ArrayFamily {
-			continue
-		}
-
+		// Roundtrip type by marshaling, then unmarshaling.
 		data, err := protoutil.Marshal(tc.actual)
 		if err != nil {
 			t.Errorf("error during marshal of type <%v>: %v", tc.actual.DebugString(), err)
@@ -60,6 +60,10 @@ func (p *planner) Update(
 		return nil, pgerror.DangerousStatementf("UPDATE without WHERE clause")
 	}
 
+	if len(n.From) > 0 {
+		return nil, pgerror.UnimplementedWithIssue(7841, "UPDATE ... FROM requires the optimizer")
+	}
+
 	// CTE analysis.
 	resetter, err := p.initWith(ctx, n.With)
 	if err != nil {
@@ -123,6 +127,9 @@ func (p *planner) Update(
 	if err != nil {
 		return nil, err
 	}
+	if len(desc.Triggers) > 0 {
+		names, setExprs = addImplicitUpdateExprs(desc, nil /* tn */, names, setExprs)
+	}
 
 	// Extract the column descriptors for the column names listed
 	// in the LHS operands of SET expressions. This also checks
@@ -167,9 +174,9 @@ func (p *planner) Update(
 	rowsNeeded := resultsNeeded(n.Returning)
 
 	var requestedCols []sqlbase.ColumnDescriptor
-	if rowsNeeded {
+	if rowsNeeded || len(desc.Triggers) > 0 {
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
-		// exprs.
+		// exprs. Row triggers have access to all the columns of the updated rows.
 		requestedCols = desc.Columns
 	} else if len(desc.ActiveChecks()) > 0 {
 		// Request any columns we'll need when validating check constraints. We
@@ -500,7 +507,12 @@ func (u *updateNode) startExec(params runParams) error {
 			params.EvalContext().Mon.MakeBoundAccount(),
 			sqlbase.ColTypeInfoFromResCols(u.columns), 0)
 	}
-	return u.run.tu.init(params.p.txn, params.EvalContext())
+	if err := u.run.tu.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	return u.run.tu.triggers.init(
+		params.ctx, params.p.txn, params.EvalContext(), u.run.tu.tableDesc(), tree.TriggerUpdate,
+	)
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -548,8 +560,6 @@ func (u *updateNode) BatchedNext(params runParams) (bool, error) {
 			return false, err
 		}
 
-		u.run.rowCount++
-
 		// Are we done yet with the current batch?
 		if u.run.tu.curBatchSize() >= maxUpdateBatchSize {
 			break
@@ -653,6 +663,12 @@ func (u *updateNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		params.EvalContext().PopIVarContainer()
 	}
 
+	// Fire the BEFORE triggers, if any. They can modify the row, or skip it.
+	if skip, err := u.run.tu.fireBeforeTriggers(
+		params.ctx, oldValues, u.run.updateValues); skip || err != nil {
+		return err
+	}
+
 	// Verify the schema constraints. For consistency with INSERT/UPSERT
 	// and compatibility with PostgreSQL, we must do this before
 	// processing the CHECK constraints.
@@ -691,6 +707,7 @@ func (u *updateNode) processSourceRow(params runParams, sourceVals tree.Datums)
 	if err != nil {
 		return err
 	}
+	u.run.rowCount++
 
 	// If result rows need to be accumulated, do it.
 	if u.run.rows != nil {
@@ -888,6 +905,41 @@ func (p *planner) namesForExprs(
 	return names, setExprs, nil
 }
 
+// addImplicitUpdateExprs extends the given assignments of an UPDATE, or of
+// the DO UPDATE clause of an upsert, of a table with row triggers. BEFORE
+// triggers can modify any column of the updated rows, so the columns which are
+// not assigned by the statement are assigned their current value. The columns
+// are qualified by tn if it is not nil, to distinguish them from the excluded
+// values of an upsert.
+func addImplicitUpdateExprs(
+	desc *sqlbase.ImmutableTableDescriptor,
+	tn *tree.TableName,
+	names tree.NameList,
+	setExprs tree.UpdateExprs,
+) (tree.NameList, tree.UpdateExprs) {
+	assigned := make(map[tree.Name]struct{}, len(names))
+	for _, name := range names {
+		assigned[name] = struct{}{}
+	}
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		name := tree.Name(col.Name)
+		if _, ok := assigned[name]; ok || col.IsComputed() || desc.PrimaryIndex.ContainsColumnID(col.ID) {
+			continue
+		}
+		expr := &tree.ColumnItem{ColumnName: name}
+		if tn != nil {
+			expr = tree.NewColumnItem(tn, name)
+		}
+		names = append(names, name)
+		setExprs = append(setExprs, &tree.UpdateExpr{
+			Names: tree.NameList{name},
+			Expr:  expr,
+		})
+	}
+	return names, setExprs
+}
+
 func fillDefault(expr tree.Expr, index int, defaultExprs []tree.TypedExpr) tree.Expr {
 	switch expr.(type) {
 	case tree.DefaultVal:
@@ -68,17 +68,33 @@ func (p *planner) newUpsertNode(
 		return nil, err
 	}
 
+	// The check constraints of tables with triggers are verified by the
+	// upserter, once the BEFORE triggers have modified the rows.
+	checkHelper := fkTables[desc.ID].CheckHelper
+	var triggerCheckHelper *sqlbase.CheckHelper
+	if len(desc.Triggers) > 0 {
+		checkHelper, triggerCheckHelper = nil, checkHelper
+	}
+
+	// The statement-level triggers on UPDATE do not fire for ON CONFLICT DO
+	// NOTHING, which never updates rows.
+	triggerEvents := tree.TriggerInsert | tree.TriggerUpdate
+	if n.OnConflict.DoNothing {
+		triggerEvents = tree.TriggerInsert
+	}
+
 	// Instantiate the upsert node.
 	un := upsertNodePool.Get().(*upsertNode)
 	*un = upsertNode{
 		source:  sourceRows,
 		columns: resultCols,
 		run: upsertRun{
-			checkHelper:  fkTables[desc.ID].CheckHelper,
-			insertCols:   ri.InsertCols,
-			defaultExprs: defaultExprs,
-			computedCols: computedCols,
-			computeExprs: computeExprs,
+			checkHelper:   checkHelper,
+			triggerEvents: triggerEvents,
+			insertCols:    ri.InsertCols,
+			defaultExprs:  defaultExprs,
+			computedCols:  computedCols,
+			computeExprs:  computeExprs,
 			iVarContainerForComputedCols: sqlbase.RowIndexedVarContainer{
 				Cols:    desc.Columns,
 				Mapping: ri.InsertColIDtoRowIndex,
@@ -101,6 +117,7 @@ func (p *planner) newUpsertNode(
 					ri:          ri,
 					collectRows: needRows,
 					alloc:       &p.alloc,
+					checkHelper: triggerCheckHelper,
 				},
 			}
 		} else {
@@ -110,6 +127,7 @@ func (p *planner) newUpsertNode(
 					ri:          ri,
 					collectRows: needRows,
 					alloc:       &p.alloc,
+					checkHelper: triggerCheckHelper,
 				},
 			}
 		}
@@ -132,6 +150,9 @@ func (p *planner) newUpsertNode(
 		// need to use its result as new input for newUpsertHelper()
 		// below.
 		updateExprs = newUpdateExprs
+		if len(desc.Triggers) > 0 {
+			names, updateExprs = addImplicitUpdateExprs(desc, tn, names, updateExprs)
+		}
 
 		// We use ensureColumns = false in processColumns, because
 		// updateCols may be legitimately empty (when there is no DO
@@ -197,7 +218,9 @@ func (p *planner) newUpsertNode(
 			len(ri.InsertCols) == len(desc.Columns) &&
 			// We cannot use the fast path if we also have a RETURNING clause, because
 			// RETURNING wants to see only the updated rows.
-			!needRows
+			!needRows &&
+			// Triggers need to know whether each row is inserted or updated.
+			len(desc.Triggers) == 0
 
 		if enableFastPath {
 			// We then use the super-simple, super-fast writer. There's not
@@ -214,6 +237,7 @@ func (p *planner) newUpsertNode(
 					ri:          ri,
 					alloc:       &p.alloc,
 					collectRows: needRows,
+					checkHelper: triggerCheckHelper,
 				},
 				anyComputed:   len(computeExprs) >= 0,
 				fkTables:      fkTables,
@@ -236,6 +260,10 @@ type upsertRun struct {
 	tw          batchedTableWriter
 	checkHelper *sqlbase.CheckHelper
 
+	// triggerEvents are the events of the statement, on which its
+	// statement-level triggers fire.
+	triggerEvents tree.TriggerEvents
+
 	// insertCols are the columns being inserted/upserted into.
 	insertCols []sqlbase.ColumnDescriptor
 
@@ -269,7 +297,12 @@ func (n *upsertNode) startExec(params runParams) error {
 	// cache traceKV during execution, to avoid re-evaluating it for every row.
 	n.run.traceKV = params.p.ExtendedEvalContext().Tracing.KVTracingEnabled()
 
-	return n.run.tw.init(params.p.txn, params.EvalContext())
+	if err := n.run.tw.init(params.p.txn, params.EvalContext()); err != nil {
+		return err
+	}
+	return n.run.tw.upsertTriggers().init(
+		params.ctx, params.p.txn, params.EvalContext(), n.run.tw.tableDesc(), n.run.triggerEvents,
+	)
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -799,35 +832,83 @@ func upsertExprsAndIndex(
 		return true, updateExprs, conflictIndex, nil
 	}
 
-	if onConflict.DoNothing && len(onConflict.Columns) == 0 {
+	if onConflict.DoNothing && len(onConflict.Columns) == 0 && onConflict.Constraint == "" {
 		return false, onConflict.Exprs, nil, nil
 	}
 
 	// General case: INSERT with an ON CONFLICT clause.
 
-	indexMatch := func(index sqlbase.IndexDescriptor) bool {
+	if onConflict.Constraint != "" {
+		conflictIdx, err := findConflictIndexByConstraint(tableDesc, onConflict.Constraint)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		return false, onConflict.Exprs, conflictIdx, nil
+	}
+
+	// A partial unique index can only be used if the arbiter predicate implies
+	// its predicate. Unique indexes that are not partial are preferred.
+	var partialMatch *sqlbase.IndexDescriptor
+	indexMatch := func(index *sqlbase.IndexDescriptor) (bool, error) {
 		if !index.Unique {
-			return false
+			return false, nil
 		}
 		if len(index.ColumnNames) != len(onConflict.Columns) {
-			return false
+			return false, nil
 		}
 		for i, colName := range index.ColumnNames {
 			if colName != string(onConflict.Columns[i]) {
-				return false
+				return false, nil
+			}
+		}
+		if index.IsPartial() {
+			if partialMatch == nil && onConflict.ArbiterPredicate != nil {
+				implied, err := sqlbase.ArbiterPredicateImplies(onConflict.ArbiterPredicate, index.Predicate)
+				if err != nil {
+					return false, err
+				}
+				if implied {
+					partialMatch = index
+				}
 			}
+			return false, nil
 		}
-		return true
+		return true, nil
 	}
 
-	if indexMatch(tableDesc.PrimaryIndex) {
+	if ok, err := indexMatch(&tableDesc.PrimaryIndex); err != nil {
+		return false, nil, nil, err
+	} else if ok {
 		return false, onConflict.Exprs, &tableDesc.PrimaryIndex, nil
 	}
 	for i := range tableDesc.Indexes {
-		if indexMatch(tableDesc.Indexes[i]) {
+		if ok, err := indexMatch(&tableDesc.Indexes[i]); err != nil {
+			return false, nil, nil, err
+		} else if ok {
 			return false, onConflict.Exprs, &tableDesc.Indexes[i], nil
 		}
 	}
+	if partialMatch != nil {
+		return false, onConflict.Exprs, partialMatch, nil
+	}
 	return false, nil, nil, pgerror.Newf(pgerror.CodeInvalidColumnReferenceError,
 		"there is no unique or exclusion constraint matching the ON CONFLICT specification")
 }
+
+// findConflictIndexByConstraint returns the index of the unique constraint
+// named in an ON CONFLICT ON CONSTRAINT clause. Only the primary key and the
+// unique indexes that are not partial are constraints.
+func findConflictIndexByConstraint(
+	tableDesc *sqlbase.ImmutableTableDescriptor, name tree.Name,
+) (*sqlbase.IndexDescriptor, error) {
+	if tableDesc.PrimaryIndex.Name == string(name) {
+		return &tableDesc.PrimaryIndex, nil
+	}
+	for i := range tableDesc.Indexes {
+		index := &tableDesc.Indexes[i]
+		if index.Name == string(name) && index.Unique && !index.IsPartial() {
+			return index, nil
+		}
+	}
+	return nil, sqlbase.NewUndefinedConstraintError(string(name), tableDesc.Name)
+}
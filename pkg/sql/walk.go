@@ -204,6 +204,7 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 			if n.hardLimit > 0 && isFilterTrue(n.filter) {
 				v.observer.attr(name, "limit", fmt.Sprintf("%d", n.hardLimit))
 			}
+			v.lockingAttrs(name, n)
 		}
 		if v.observer.expr != nil {
 			v.expr(name, "filter", -1, n.filter)
@@ -227,6 +228,7 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		if v.observer.attr != nil {
 			v.observer.attr(name, "table", fmt.Sprintf("%s@%s", n.table.desc.Name, n.table.index.Name))
 			v.expr(name, "filter", -1, n.table.filter)
+			v.lockingAttrs(name, n.table)
 		}
 		v.visitConcrete(n.index)
 
@@ -234,6 +236,7 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		if v.observer.attr != nil {
 			v.observer.attr(name, "table", fmt.Sprintf("%s@%s", n.table.desc.Name, n.table.index.Name))
 			v.observer.attr(name, "type", joinTypeStr(n.joinType))
+			v.lockingAttrs(name, n.table)
 		}
 		if v.observer.expr != nil && n.onCond != nil && n.onCond != tree.DBoolTrue {
 			v.expr(name, "pred", -1, n.onCond)
@@ -393,6 +396,23 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 			if len(n.groupColOrdering) > 0 {
 				v.observer.attr(name, "ordered", formatOrdering(n.groupColOrdering, inputCols))
 			}
+			if len(n.groupingSets) > 0 {
+				var buf bytes.Buffer
+				for i, set := range n.groupingSets {
+					if i > 0 {
+						buf.WriteString(", ")
+					}
+					buf.WriteByte('(')
+					for j, c := range set {
+						if j > 0 {
+							buf.WriteString(", ")
+						}
+						buf.WriteString(inputCols[c].Name)
+					}
+					buf.WriteByte(')')
+				}
+				v.observer.attr(name, "grouping sets", buf.String())
+			}
 			if n.isScalar {
 				v.observer.attr(name, "scalar", "")
 			}
@@ -621,7 +641,43 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		n.plan = v.visit(n.plan)
 
 	case *bufferNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
 		n.plan = v.visit(n.plan)
+
+	case *scanBufferNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
+
+	case *recursiveCTENode:
+		if v.observer.attr != nil && n.label != "" {
+			v.observer.attr(name, "label", n.label)
+		}
+		n.initial = v.visit(n.initial)
+	}
+}
+
+// lockingAttrs reports the row-level locking mode of the given scan, if any.
+func (v *planVisitor) lockingAttrs(nodeName string, n *scanNode) {
+	switch n.lockingStrength {
+	case sqlbase.ScanLockingStrength_FOR_NONE:
+		return
+	case sqlbase.ScanLockingStrength_FOR_KEY_SHARE:
+		v.observer.attr(nodeName, "locking strength", "for key share")
+	case sqlbase.ScanLockingStrength_FOR_SHARE:
+		v.observer.attr(nodeName, "locking strength", "for share")
+	case sqlbase.ScanLockingStrength_FOR_NO_KEY_UPDATE:
+		v.observer.attr(nodeName, "locking strength", "for no key update")
+	case sqlbase.ScanLockingStrength_FOR_UPDATE:
+		v.observer.attr(nodeName, "locking strength", "for update")
+	}
+	switch n.lockingWaitPolicy {
+	case sqlbase.ScanLockingWaitPolicy_SKIP:
+		v.observer.attr(nodeName, "locking wait policy", "skip locked")
+	case sqlbase.ScanLockingWaitPolicy_ERROR:
+		v.observer.attr(nodeName, "locking wait policy", "nowait")
 	}
 }
 
@@ -711,6 +767,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterIndexNode{}):           "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):        "alter sequence",
 	reflect.TypeOf(&alterTableNode{}):           "alter table",
+	reflect.TypeOf(&alterTypeNode{}):            "alter type",
 	reflect.TypeOf(&alterUserSetPasswordNode{}): "alter user",
 	reflect.TypeOf(&applyJoinNode{}):            "apply-join",
 	reflect.TypeOf(&bufferNode{}):               "buffer node",
@@ -721,10 +778,15 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&cancelSessionsNode{}):       "cancel sessions",
 	reflect.TypeOf(&controlJobsNode{}):          "control jobs",
 	reflect.TypeOf(&createDatabaseNode{}):       "create database",
+	reflect.TypeOf(&createFunctionNode{}):       "create function",
+	reflect.TypeOf(&createTriggerNode{}):        "create trigger",
 	reflect.TypeOf(&createIndexNode{}):          "create index",
+	reflect.TypeOf(&createSchemaNode{}):         "create schema",
 	reflect.TypeOf(&createSequenceNode{}):       "create sequence",
 	reflect.TypeOf(&createStatsNode{}):          "create statistics",
 	reflect.TypeOf(&createTableNode{}):          "create table",
+	reflect.TypeOf(&createDomainNode{}):         "create domain",
+	reflect.TypeOf(&createTypeNode{}):           "create type",
 	reflect.TypeOf(&CreateUserNode{}):           "create user/role",
 	reflect.TypeOf(&createViewNode{}):           "create view",
 	reflect.TypeOf(&delayedNode{}):              "virtual table",
@@ -732,9 +794,13 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&deleteRangeNode{}):          "delete range",
 	reflect.TypeOf(&distinctNode{}):             "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):         "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):         "drop function",
+	reflect.TypeOf(&dropTriggerNode{}):          "drop trigger",
 	reflect.TypeOf(&dropIndexNode{}):            "drop index",
+	reflect.TypeOf(&dropSchemaNode{}):           "drop schema",
 	reflect.TypeOf(&dropSequenceNode{}):         "drop sequence",
 	reflect.TypeOf(&dropTableNode{}):            "drop table",
+	reflect.TypeOf(&dropTypeNode{}):             "drop type",
 	reflect.TypeOf(&DropUserNode{}):             "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):             "drop view",
 	reflect.TypeOf(&errorIfRowsNode{}):          "errorIfRows",
@@ -751,6 +817,8 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&max1RowNode{}):              "max1row",
 	reflect.TypeOf(&ordinalityNode{}):           "ordinality",
 	reflect.TypeOf(&projectSetNode{}):           "project set",
+	reflect.TypeOf(&recursiveCTENode{}):         "recursive cte node",
+	reflect.TypeOf(&refreshMatViewNode{}):       "refresh materialized view",
 	reflect.TypeOf(&relocateNode{}):             "relocate",
 	reflect.TypeOf(&renameColumnNode{}):         "rename column",
 	reflect.TypeOf(&renameDatabaseNode{}):       "rename database",
@@ -105,6 +105,9 @@ func containsMutations(plan planNode) (bool, error) {
 // is finished resolving names, which pops the environment frame.
 func (p *planner) initWith(ctx context.Context, with *tree.With) (func(p *planner) error, error) {
 	if with != nil {
+		if with.Recursive {
+			return nil, pgerror.UnimplementedWithIssue(21085, "WITH RECURSIVE requires the optimizer")
+		}
 		frame := make(cteNameEnvironmentFrame)
 		p.curPlan.cteNameEnvironment = p.curPlan.cteNameEnvironment.push(frame)
 		for _, cte := range with.CTEList {
@@ -0,0 +1,51 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterCommand(roachpb.Lock, DefaultDeclareKeys, Lock)
+}
+
+// Lock acquires an exclusive lock on a key for the transaction by writing an
+// intent with the key's latest value. Keys that don't exist are not locked.
+func Lock(
+	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
+) (result.Result, error) {
+	args := cArgs.Args.(*roachpb.LockRequest)
+	h := cArgs.Header
+
+	if h.Txn == nil {
+		return result.Result{}, errors.Errorf("cannot lock %s outside of a transaction", args.Key)
+	}
+
+	// Read the latest value of the key, including values written above the
+	// transaction's timestamp, so that the intent does not roll the key back to
+	// an older value. A conflicting intent results in a WriteIntentError.
+	val, _, err := engine.MVCCGet(ctx, batch, args.Key, hlc.MaxTimestamp, engine.MVCCGetOptions{
+		Txn: h.Txn,
+	})
+	if err != nil || val == nil {
+		return result.Result{}, err
+	}
+	return result.Result{}, engine.MVCCPut(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, *val, h.Txn)
+}
@@ -45,9 +45,10 @@ func ResolveIntentRange(
 	}
 
 	intent := roachpb.Intent{
-		Span:   args.Span(),
-		Txn:    args.IntentTxn,
-		Status: args.Status,
+		Span:           args.Span(),
+		Txn:            args.IntentTxn,
+		Status:         args.Status,
+		IgnoredSeqNums: args.IgnoredSeqNums,
 	}
 
 	iterAndBuf := engine.GetIterAndBuf(batch, engine.IterOptions{UpperBound: args.EndKey})
@@ -31,6 +31,16 @@ type TxnEpoch int32
 // at equal or lower sequence numbers.
 type TxnSeq int32
 
+// TxnSeqIsIgnored returns whether the sequence number falls into one of the
+// provided ranges of ignored sequence numbers. The ranges are expected to be
+// sorted and non-overlapping.
+func TxnSeqIsIgnored(seq TxnSeq, ignored []IgnoredSeqNumRange) bool {
+	i := sort.Search(len(ignored), func(i int) bool {
+		return ignored[i].End >= seq
+	})
+	return i < len(ignored) && ignored[i].Start <= seq
+}
+
 // TxnPriority defines the priority that a transaction operates at. Transactions
 // with high priorities are preferred over transaction with low priorities when
 // resolving conflicts between themselves. For example, transaction priorities
@@ -168,13 +178,21 @@ func (meta *MVCCMetadata) AddToIntentHistory(seq TxnSeq, val []byte) {
 }
 
 // GetPrevIntentSeq goes through the intent history and finds the previous
-// intent's sequence number given the current sequence.
-func (meta *MVCCMetadata) GetPrevIntentSeq(seq TxnSeq) (TxnSeq, bool) {
+// intent's sequence number given the current sequence. Intents written at
+// ignored sequence numbers are skipped.
+func (meta *MVCCMetadata) GetPrevIntentSeq(
+	seq TxnSeq, ignored []IgnoredSeqNumRange,
+) (TxnSeq, bool) {
 	index := sort.Search(len(meta.IntentHistory), func(i int) bool {
 		return meta.IntentHistory[i].Sequence >= seq
 	})
-	if index > 0 && index < len(meta.IntentHistory) {
-		return meta.IntentHistory[index-1].Sequence, true
+	if index >= len(meta.IntentHistory) {
+		return 0, false
+	}
+	for i := index - 1; i >= 0; i-- {
+		if prevSeq := meta.IntentHistory[i].Sequence; !TxnSeqIsIgnored(prevSeq, ignored) {
+			return prevSeq, true
+		}
 	}
 	return 0, false
 }
@@ -947,6 +947,50 @@ func mvccGetInternal(
 	return value, ignoredIntent, allowedSafety, nil
 }
 
+// mvccMaybeRewriteIntentHistory rewrites the intent described by meta if the
+// sequence number it was written at has been rolled back. The latest value in
+// the intent history written at a sequence number that was not rolled back is
+// written back as the intent's value, and meta is updated accordingly (but not
+// persisted). If there is no such value, removeIntent is returned and the
+// caller must remove the intent.
+func mvccMaybeRewriteIntentHistory(
+	engine ReadWriter,
+	ignoredSeqNums []enginepb.IgnoredSeqNumRange,
+	meta *enginepb.MVCCMetadata,
+	key roachpb.Key,
+) (removeIntent bool, rewritten bool, _ error) {
+	if !enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, ignoredSeqNums) {
+		return false, false, nil
+	}
+	i := latestUnignoredIntentHistoryIndex(meta, ignoredSeqNums)
+	if i < 0 {
+		return true, false, nil
+	}
+	restoredVal := meta.IntentHistory[i].Value
+	txnMeta := *meta.Txn
+	txnMeta.Sequence = meta.IntentHistory[i].Sequence
+	meta.Txn = &txnMeta
+	meta.IntentHistory = meta.IntentHistory[:i]
+	meta.Deleted = len(restoredVal) == 0
+	meta.ValBytes = int64(len(restoredVal))
+	versionKey := MVCCKey{Key: key, Timestamp: hlc.Timestamp(meta.Timestamp)}
+	return false, true, engine.Put(versionKey, restoredVal)
+}
+
+// latestUnignoredIntentHistoryIndex returns the index of the latest entry in
+// the intent history of meta that was not written at an ignored sequence
+// number, or -1 if there is no such entry.
+func latestUnignoredIntentHistoryIndex(
+	meta *enginepb.MVCCMetadata, ignoredSeqNums []enginepb.IgnoredSeqNumRange,
+) int {
+	for i := len(meta.IntentHistory) - 1; i >= 0; i-- {
+		if !enginepb.TxnSeqIsIgnored(meta.IntentHistory[i].Sequence, ignoredSeqNums) {
+			return i
+		}
+	}
+	return -1
+}
+
 // putBuffer holds pointer data needed by mvccPutInternal. Bundling
 // this data into a single structure reduces memory
 // allocations. Managing this temporary buffer using a sync.Pool
@@ -1203,7 +1247,7 @@ func replayTransactionalWrite(
 
 	// If the valueFn is specified, we must apply it to the would-be value at the key.
 	if valueFn != nil {
-		prevSeq, prevValueWritten := meta.GetPrevIntentSeq(txn.Sequence)
+		prevSeq, prevValueWritten := meta.GetPrevIntentSeq(txn.Sequence, txn.IgnoredSeqNums)
 		if prevValueWritten {
 			// If the previous value was found in the IntentHistory,
 			// simply apply the value function to the historic value
@@ -1394,10 +1438,27 @@ func mvccPutInternal(
 			defer getBuf.release()
 			getBuf.meta = buf.meta // initialize get metadata from what we've already read
 
-			existingVal, _, _, err := mvccGetInternal(
-				ctx, iter, metaKey, readTimestamp, true /* consistent */, safeValue, txn, getBuf)
-			if err != nil {
-				return err
+			// If the intent was written at a sequence number that has since been
+			// rolled back, the existing value is the latest value in the intent
+			// history that was not rolled back or, if there is none, the latest
+			// committed value.
+			curIntentIgnored := txn.Epoch == meta.Txn.Epoch &&
+				enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, txn.IgnoredSeqNums)
+			var existingVal *roachpb.Value
+			if !curIntentIgnored {
+				existingVal, _, _, err = mvccGetInternal(
+					ctx, iter, metaKey, readTimestamp, true /* consistent */, safeValue, txn, getBuf)
+				if err != nil {
+					return err
+				}
+			} else if i := latestUnignoredIntentHistoryIndex(meta, txn.IgnoredSeqNums); i >= 0 {
+				existingVal = &roachpb.Value{RawBytes: meta.IntentHistory[i].Value}
+			} else {
+				existingVal, _, _, err = mvccGetInternal(
+					ctx, iter, metaKey, hlc.MaxTimestamp, false /* consistent */, safeValue, nil /* txn */, getBuf)
+				if err != nil {
+					return err
+				}
 			}
 			// It's possible that the existing value is nil if the intent on the key
 			// has a lower epoch. We don't have to deal with this as a special case
@@ -1459,16 +1520,21 @@ func mvccPutInternal(
 			//
 			// If the epoch of the transaction doesn't match the epoch of the
 			// intent, blow away the intent history.
+			//
+			// If the previous intent was written at an ignored sequence number,
+			// there is no need to record it: it will never be read again.
 			if txn.Epoch == meta.Txn.Epoch {
-				// This case shouldn't pop up, but it is worth asserting
-				// that it doesn't. We shouldn't write invalid intents
-				// to the history
-				if existingVal == nil {
-					return errors.Errorf(
-						"previous intent of the transaction with the same epoch not found for %s (%+v)",
-						metaKey, txn)
+				if !curIntentIgnored {
+					// This case shouldn't pop up, but it is worth asserting
+					// that it doesn't. We shouldn't write invalid intents
+					// to the history
+					if existingVal == nil {
+						return errors.Errorf(
+							"previous intent of the transaction with the same epoch not found for %s (%+v)",
+							metaKey, txn)
+					}
+					buf.newMeta.AddToIntentHistory(prevIntentSequence, prevIntentValBytes)
 				}
-				buf.newMeta.AddToIntentHistory(prevIntentSequence, prevIntentValBytes)
 			} else {
 				buf.newMeta.IntentHistory = nil
 			}
@@ -2327,10 +2393,44 @@ func mvccResolveWriteIntent(
 	inProgress := !intent.Status.IsFinalized() && meta.Txn.Epoch >= intent.Txn.Epoch
 	pushed := inProgress && hlc.Timestamp(meta.Timestamp).Less(intent.Txn.Timestamp)
 
+	// If the transaction rolled back the sequence number at which the intent
+	// was written, restore the latest value from the intent history that was
+	// not rolled back before doing anything else. If there is no such value,
+	// the intent is removed altogether.
+	var rolledBack bool
+	if epochsMatch && intent.Status != roachpb.ABORTED && len(intent.IgnoredSeqNums) > 0 {
+		origMeta := *meta
+		var removeIntent bool
+		removeIntent, rolledBack, err = mvccMaybeRewriteIntentHistory(
+			engine, intent.IgnoredSeqNums, meta, intent.Key)
+		if err != nil {
+			return false, err
+		}
+		if removeIntent {
+			// The intent is removed below, as if the transaction was aborted.
+			commit, pushed, inProgress = false, false, false
+		}
+		if rolledBack {
+			// Persist the rewritten intent and account for it as if the
+			// transaction had overwritten its own intent at the same
+			// timestamp. The resolution below then starts from the rewritten
+			// intent.
+			metaKeySize, metaValSize, err := buf.putMeta(engine, metaKey, meta)
+			if err != nil {
+				return false, err
+			}
+			if ms != nil {
+				ms.Add(updateStatsOnPut(intent.Key, 0 /* prevValSize */, origMetaKeySize, origMetaValSize,
+					metaKeySize, metaValSize, &origMeta, meta))
+			}
+			origMetaKeySize, origMetaValSize = metaKeySize, metaValSize
+		}
+	}
+
 	// There's nothing to do if meta's epoch is greater than or equal txn's
 	// epoch and the state is still in progress but the intent was not pushed
-	// to a larger timestamp.
-	if inProgress && !pushed {
+	// to a larger timestamp (or rewritten above).
+	if inProgress && !pushed && !rolledBack {
 		return false, nil
 	}
 
@@ -2338,14 +2438,16 @@ func mvccResolveWriteIntent(
 	// the proposed epoch matches the existing epoch: update the meta.Txn. For commit, it's set to
 	// nil; otherwise, we update its value. We may have to update the actual version value (remove old
 	// and create new with proper timestamp-encoded key) if timestamp changed.
-	if commit || pushed {
+	if commit || pushed || rolledBack {
 		buf.newMeta = *meta
 		// Set the timestamp for upcoming write (or at least the stats update).
-		buf.newMeta.Timestamp = hlc.LegacyTimestamp(intent.Txn.Timestamp)
+		if commit || pushed {
+			buf.newMeta.Timestamp = hlc.LegacyTimestamp(intent.Txn.Timestamp)
+		}
 
 		// Update or remove the metadata key.
 		var metaKeySize, metaValSize int64
-		if pushed {
+		if !commit {
 			// Keep existing intent if we're pushing timestamp. We keep the
 			// existing metadata instead of using the supplied intent meta
 			// to avoid overwriting a newer epoch (see comments above). The
@@ -2403,13 +2505,13 @@ func mvccResolveWriteIntent(
 
 		// Log the logical MVCC operation.
 		logicalOp := MVCCCommitIntentOpType
-		if pushed {
+		if !commit {
 			logicalOp = MVCCUpdateIntentOpType
 		}
 		engine.LogLogicalOp(logicalOp, MVCCLogicalOpDetails{
 			Txn:       intent.Txn,
 			Key:       intent.Key,
-			Timestamp: intent.Txn.Timestamp,
+			Timestamp: hlc.Timestamp(buf.newMeta.Timestamp),
 		})
 
 		return true, nil
@@ -3516,6 +3516,136 @@ func TestMVCCReadWithOldEpoch(t *testing.T) {
 	}
 }
 
+// TestMVCCIgnoredSeqNums verifies that values written by a transaction at
+// ignored sequence numbers are invisible to the transaction's own reads and
+// are discarded when its intents are resolved.
+func TestMVCCIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	// The stats are maintained throughout and checked against the recomputed
+	// ones after every step.
+	var ms enginepb.MVCCStats
+	assertStats := func(debug string) {
+		t.Helper()
+		it := engine.NewIterator(IterOptions{UpperBound: roachpb.KeyMax})
+		defer it.Close()
+		expMS, err := ComputeStatsGo(it, MVCCKey{}, MVCCKey{Key: roachpb.KeyMax}, ms.LastUpdateNanos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEq(t, engine, debug, &ms, &expMS)
+	}
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	keyA, keyB := roachpb.Key("a"), roachpb.Key("b")
+	keyC, keyD := roachpb.Key("c"), roachpb.Key("d")
+	if err := MVCCPut(ctx, engine, &ms, keyA, ts1, value1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	txn := &roachpb.Transaction{
+		TxnMeta: enginepb.TxnMeta{
+			ID:        uuid.MakeV4(),
+			Timestamp: ts2,
+		},
+		OrigTimestamp: ts2,
+		Status:        roachpb.PENDING,
+	}
+	for _, w := range []struct {
+		key roachpb.Key
+		seq enginepb.TxnSeq
+		val *roachpb.Value // nil for a deletion
+	}{
+		{keyA, 1, &value2},
+		{keyA, 2, &value3},
+		{keyB, 3, &value4},
+		// Rolling back the deletion of keyC restores a value, and rolling
+		// back the write to keyD restores a deletion.
+		{keyC, 5, &value1},
+		{keyC, 6, nil},
+		{keyD, 7, nil},
+		{keyD, 8, &value2},
+	} {
+		txn.Sequence = w.seq
+		var err error
+		if w.val == nil {
+			err = MVCCDelete(ctx, engine, &ms, w.key, ts2, txn)
+		} else {
+			err = MVCCPut(ctx, engine, &ms, w.key, ts2, *w.val, txn)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertStats("after writes")
+
+	// Roll back the writes at sequence numbers 2, 3, 6 and 8.
+	txn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{Start: 2, End: 3})
+	txn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{Start: 6, End: 6})
+	txn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{Start: 8, End: 8})
+
+	expect := func(getTxn *roachpb.Transaction, key roachpb.Key, exp *roachpb.Value) {
+		t.Helper()
+		val, _, err := MVCCGet(ctx, engine, key, ts2, MVCCGetOptions{Txn: getTxn})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp == nil {
+			if val != nil {
+				t.Fatalf("%s: expected no value, found %q", key, val.RawBytes)
+			}
+		} else if val == nil || !bytes.Equal(val.RawBytes, exp.RawBytes) {
+			t.Fatalf("%s: expected %q, found %v", key, exp.RawBytes, val)
+		}
+	}
+	expect(txn, keyA, &value2)
+	expect(txn, keyB, nil)
+	expect(txn, keyC, &value1)
+	expect(txn, keyD, nil)
+
+	// Overwriting a key whose intent was rolled back does not resurrect the
+	// rolled back value.
+	txn.Sequence = 9
+	if err := MVCCPut(ctx, engine, &ms, keyB, ts2, value1, txn); err != nil {
+		t.Fatal(err)
+	}
+	txn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{Start: 9, End: 9})
+	expect(txn, keyB, nil)
+	assertStats("after overwrite")
+
+	resolve := func(status roachpb.TransactionStatus) {
+		t.Helper()
+		txn.Status = status
+		for _, key := range []roachpb.Key{keyA, keyB, keyC, keyD} {
+			intent := roachpb.MakeIntent(txn, roachpb.Span{Key: key})
+			if err := MVCCResolveWriteIntent(ctx, engine, &ms, intent); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Resolving the intents of the pending transaction, as a pusher does,
+	// rewrites the intents whose values were rolled back.
+	resolve(roachpb.PENDING)
+	assertStats("after resolving pending intents")
+	expect(txn, keyA, &value2)
+	expect(txn, keyC, &value1)
+
+	// Committing the transaction restores the latest values that were not
+	// rolled back and removes the intents that have none.
+	resolve(roachpb.COMMITTED)
+	assertStats("after commit")
+	expect(nil, keyA, &value2)
+	expect(nil, keyB, nil)
+	expect(nil, keyC, &value1)
+	expect(nil, keyD, nil)
+}
+
 // TestMVCCWriteWithSequence verifies that writes at sequence numbers equal to
 // or below the sequence of an active intent verify that they agree with the
 // intent's sequence history. If so, they become no-ops because writes are meant
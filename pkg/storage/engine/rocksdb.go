@@ -2617,10 +2617,23 @@ func goToCTxn(txn *roachpb.Transaction) C.DBTxn {
 		r.epoch = C.uint32_t(txn.Epoch)
 		r.sequence = C.int32_t(txn.Sequence)
 		r.max_timestamp = goToCTimestamp(txn.MaxTimestamp)
+		r.ignored_seqnums = goToCIgnoredSeqNums(txn.IgnoredSeqNums)
 	}
 	return r
 }
 
+func goToCIgnoredSeqNums(b []enginepb.IgnoredSeqNumRange) C.DBIgnoredSeqNums {
+	if len(b) == 0 {
+		return C.DBIgnoredSeqNums{ranges: nil, len: 0}
+	}
+	// enginepb.IgnoredSeqNumRange has the same memory layout as
+	// C.DBIgnoredSeqNumRange.
+	return C.DBIgnoredSeqNums{
+		ranges: (*C.DBIgnoredSeqNumRange)(unsafe.Pointer(&b[0])),
+		len:    C.int(len(b)),
+	}
+}
+
 func goToCIterOptions(opts IterOptions) C.DBIterOptions {
 	return C.DBIterOptions{
 		prefix:             C.bool(opts.Prefix),
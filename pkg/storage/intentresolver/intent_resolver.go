@@ -243,9 +243,11 @@ func (ir *IntentResolver) ProcessWriteIntentError(
 	}
 
 	// Possibly queue this processing if the write intent error is for a
-	// single intent affecting a unitary key.
+	// single intent affecting a unitary key. Requests which don't want to
+	// wait on conflicting intents never queue.
 	var cleanup func(*roachpb.WriteIntentError, *enginepb.TxnMeta)
-	if len(wiErr.Intents) == 1 && len(wiErr.Intents[0].Span.EndKey) == 0 {
+	if len(wiErr.Intents) == 1 && len(wiErr.Intents[0].Span.EndKey) == 0 &&
+		h.WaitPolicy == roachpb.WaitPolicy_BLOCK {
 		var done bool
 		// Note that the write intent error may be mutated here in the event
 		// that this pusher is queued to wait for a different transaction
@@ -369,6 +371,7 @@ func updateIntentTxnStatus(
 		}
 		intent.Txn = pushee.TxnMeta
 		intent.Status = pushee.Status
+		intent.IgnoredSeqNums = pushee.IgnoredSeqNums
 		results = append(results, intent)
 	}
 	return results
@@ -709,6 +712,7 @@ func (ir *IntentResolver) CleanupTxnIntentsOnGCAsync(
 				for i := range intents {
 					intents[i].Txn = txn.TxnMeta
 					intents[i].Status = txn.Status
+					intents[i].IgnoredSeqNums = txn.IgnoredSeqNums
 				}
 			}
 			var onCleanupComplete func(error)
@@ -890,19 +894,21 @@ func (ir *IntentResolver) ResolveIntents(
 				resolveReq{
 					rangeID: ir.lookupRangeID(ctx, intent.Key),
 					req: &roachpb.ResolveIntentRequest{
-						RequestHeader: roachpb.RequestHeaderFromSpan(intent.Span),
-						IntentTxn:     intent.Txn,
-						Status:        intent.Status,
-						Poison:        opts.Poison,
+						RequestHeader:  roachpb.RequestHeaderFromSpan(intent.Span),
+						IntentTxn:      intent.Txn,
+						Status:         intent.Status,
+						Poison:         opts.Poison,
+						IgnoredSeqNums: intent.IgnoredSeqNums,
 					},
 				})
 		} else {
 			resolveRangeReqs = append(resolveRangeReqs, &roachpb.ResolveIntentRangeRequest{
-				RequestHeader: roachpb.RequestHeaderFromSpan(intent.Span),
-				IntentTxn:     intent.Txn,
-				Status:        intent.Status,
-				Poison:        opts.Poison,
-				MinTimestamp:  opts.MinTimestamp,
+				RequestHeader:  roachpb.RequestHeaderFromSpan(intent.Span),
+				IntentTxn:      intent.Txn,
+				Status:         intent.Status,
+				Poison:         opts.Poison,
+				MinTimestamp:   opts.MinTimestamp,
+				IgnoredSeqNums: intent.IgnoredSeqNums,
 			})
 		}
 	}
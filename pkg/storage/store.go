@@ -3029,13 +3029,20 @@ func (s *Store) Send(
 			// this is the code path with the requesting client waiting.
 			if pErr.Index != nil {
 				var pushType roachpb.PushTxnType
-				if ba.IsWrite() {
+				switch {
+				case ba.WaitPolicy == roachpb.WaitPolicy_ERROR:
+					// The request does not want to wait on conflicting intents.
+					// Only clean up intents whose transactions are already
+					// finalized or abandoned; return the conflict otherwise.
+					pushType = roachpb.PUSH_TOUCH
+				case ba.IsWrite():
 					pushType = roachpb.PUSH_ABORT
-				} else {
+				default:
 					pushType = roachpb.PUSH_TIMESTAMP
 				}
 
 				index := pErr.Index
+				wiPErr := pErr
 				args := ba.Requests[index.Index].GetInner()
 				// Make a copy of the header for the upcoming push; we will update
 				// the timestamp.
@@ -3070,6 +3077,12 @@ func (s *Store) Send(
 					s.intentResolver.ProcessWriteIntentError(ctx, pErr, args, h, pushType); pErr != nil {
 					// Do not propagate ambiguous results; assume success and retry original op.
 					if _, ok := pErr.GetDetail().(*roachpb.AmbiguousResultError); !ok {
+						if _, ok := pErr.GetDetail().(*roachpb.TransactionPushError); ok &&
+							pushType == roachpb.PUSH_TOUCH {
+							// The conflicting transaction is still active; surface the
+							// original conflict to the client instead of the failed push.
+							pErr = wiPErr
+						}
 						// Preserve the error index.
 						pErr.Index = index
 						return nil, pErr
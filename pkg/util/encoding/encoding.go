@@ -30,6 +30,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
@@ -79,6 +81,8 @@ const (
 	bitArrayDataTerminator     = 0x00
 	bitArrayDataDescTerminator = 0xff
 
+	timeTZMarker = bitArrayDescMarker + 1
+
 	// IntMin is chosen such that the range of int tags does not overlap the
 	// ascii character set that is frequently used in testing.
 	IntMin      = 0x80 // 128
@@ -108,6 +112,9 @@ const (
 	// EncodedDurationMaxLen is the largest number of bytes used when encoding a
 	// Duration.
 	EncodedDurationMaxLen = 1 + 3*binary.MaxVarintLen64 // 3 varints are encoded.
+	// EncodedTimeTZMaxLen is the largest number of bytes used when encoding a
+	// TimeTZ.
+	EncodedTimeTZMaxLen = 1 + 2*binary.MaxVarintLen64 // 2 varints are encoded.
 	// BytesDescMarker is exported for testing.
 	BytesDescMarker = bytesDescMarker
 )
@@ -953,6 +960,72 @@ func decodeTime(b []byte) (r []byte, sec int64, nsec int64, err error) {
 	return b, sec, nsec, nil
 }
 
+// EncodeTimeTZAscending encodes a timetz.TimeTZ value and appends it to the
+// supplied buffer and returns the final buffer. The encoding is guaranteed to
+// be ordered such that if t1.Compare(t2) < 0 then after EncodeTimeTZ(b1, t1)
+// and EncodeTimeTZ(b2, t2), Compare(b1, b2) < 0. Unlike times, the time zone
+// offset is included in the encoding.
+func EncodeTimeTZAscending(b []byte, t timetz.TimeTZ) []byte {
+	return encodeTimeTZ(b, t.UTCMicros(), int64(t.OffsetSecs))
+}
+
+// EncodeTimeTZDescending is the descending version of EncodeTimeTZAscending.
+func EncodeTimeTZDescending(b []byte, t timetz.TimeTZ) []byte {
+	return encodeTimeTZ(b, ^t.UTCMicros(), ^int64(t.OffsetSecs))
+}
+
+func encodeTimeTZ(b []byte, utcMicros, offsetSecs int64) []byte {
+	b = append(b, timeTZMarker)
+	b = EncodeVarintAscending(b, utcMicros)
+	b = EncodeVarintAscending(b, offsetSecs)
+	return b
+}
+
+// DecodeTimeTZAscending decodes a timetz.TimeTZ value which was encoded using
+// EncodeTimeTZAscending. The remainder of the input buffer and the decoded
+// timetz.TimeTZ are returned.
+func DecodeTimeTZAscending(b []byte) ([]byte, timetz.TimeTZ, error) {
+	b, utcMicros, offsetSecs, err := decodeTimeTZ(b)
+	if err != nil {
+		return b, timetz.TimeTZ{}, err
+	}
+	return b, makeTimeTZ(utcMicros, offsetSecs), nil
+}
+
+// DecodeTimeTZDescending is the descending version of DecodeTimeTZAscending.
+func DecodeTimeTZDescending(b []byte) ([]byte, timetz.TimeTZ, error) {
+	b, utcMicros, offsetSecs, err := decodeTimeTZ(b)
+	if err != nil {
+		return b, timetz.TimeTZ{}, err
+	}
+	return b, makeTimeTZ(^utcMicros, ^offsetSecs), nil
+}
+
+func decodeTimeTZ(b []byte) (r []byte, utcMicros int64, offsetSecs int64, err error) {
+	if PeekType(b) != TimeTZ {
+		return nil, 0, 0, errors.Errorf("did not find marker")
+	}
+	b = b[1:]
+	b, utcMicros, err = DecodeVarintAscending(b)
+	if err != nil {
+		return b, 0, 0, err
+	}
+	b, offsetSecs, err = DecodeVarintAscending(b)
+	if err != nil {
+		return b, 0, 0, err
+	}
+	return b, utcMicros, offsetSecs, nil
+}
+
+// makeTimeTZ reconstructs a timetz.TimeTZ from its UTC time of day and
+// offset. The time of day is not wrapped, so that 24:00 round trips.
+func makeTimeTZ(utcMicros, offsetSecs int64) timetz.TimeTZ {
+	return timetz.MakeTimeTZ(
+		timeofday.TimeOfDay(utcMicros-offsetSecs*int64(time.Second/time.Microsecond)),
+		int32(offsetSecs),
+	)
+}
+
 // EncodeDurationAscending encodes a duration.Duration value, appends it to the
 // supplied buffer, and returns the final buffer. The encoding is guaranteed to
 // be ordered such that if t1.Compare(t2) < 0 (or = 0 or > 0) then bytes.Compare
@@ -1207,6 +1280,7 @@ const (
 	Tuple        Type = 16
 	BitArray     Type = 17
 	BitArrayDesc Type = 18 // BitArray encoded descendingly
+	TimeTZ       Type = 19
 )
 
 // typMap maps an encoded type byte to a decoded Type. It's got 256 slots, one
@@ -1249,6 +1323,8 @@ func slowPeekType(b []byte) Type {
 			return BitArrayDesc
 		case m == timeMarker:
 			return Time
+		case m == timeTZMarker:
+			return TimeTZ
 		case m == byte(Array):
 			return Array
 		case m == byte(True):
@@ -1330,7 +1406,7 @@ func PeekLength(b []byte) (int, error) {
 		return getJSONInvertedIndexKeyLength(b)
 	case bytesDescMarker:
 		return getBytesLength(b, descendingEscapes)
-	case timeMarker:
+	case timeMarker, timeTZMarker:
 		return GetMultiVarintLen(b, 2)
 	case durationBigNegMarker, durationMarker, durationBigPosMarker:
 		return GetMultiVarintLen(b, 3)
@@ -1536,6 +1612,17 @@ func prettyPrintFirstValue(dir Direction, b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, t.UTC().Format(time.RFC3339Nano), nil
+	case TimeTZ:
+		var t timetz.TimeTZ
+		if dir == Descending {
+			b, t, err = DecodeTimeTZDescending(b)
+		} else {
+			b, t, err = DecodeTimeTZAscending(b)
+		}
+		if err != nil {
+			return b, "", err
+		}
+		return b, t.String(), nil
 	case Duration:
 		var d duration.Duration
 		if dir == Descending {
@@ -1826,6 +1913,20 @@ func EncodeUntaggedTimeValue(appendTo []byte, t time.Time) []byte {
 	return EncodeNonsortingStdlibVarint(appendTo, int64(t.Nanosecond()))
 }
 
+// EncodeTimeTZValue encodes a timetz.TimeTZ value with its value tag, appends
+// it to the supplied buffer, and returns the final buffer.
+func EncodeTimeTZValue(appendTo []byte, colID uint32, t timetz.TimeTZ) []byte {
+	appendTo = EncodeValueTag(appendTo, colID, TimeTZ)
+	return EncodeUntaggedTimeTZValue(appendTo, t)
+}
+
+// EncodeUntaggedTimeTZValue encodes a timetz.TimeTZ value, appends it to the
+// supplied buffer, and returns the final buffer.
+func EncodeUntaggedTimeTZValue(appendTo []byte, t timetz.TimeTZ) []byte {
+	appendTo = EncodeNonsortingStdlibVarint(appendTo, int64(t.TimeOfDay))
+	return EncodeNonsortingStdlibVarint(appendTo, int64(t.OffsetSecs))
+}
+
 // EncodeDecimalValue encodes an apd.Decimal value with its value tag, appends
 // it to the supplied buffer, and returns the final buffer.
 func EncodeDecimalValue(appendTo []byte, colID uint32, d *apd.Decimal) []byte {
@@ -2059,6 +2160,30 @@ func DecodeUntaggedTimeValue(b []byte) (remaining []byte, t time.Time, err error
 	return b, timeutil.Unix(sec, nsec), nil
 }
 
+// DecodeTimeTZValue decodes a value encoded by EncodeTimeTZValue.
+func DecodeTimeTZValue(b []byte) (remaining []byte, t timetz.TimeTZ, err error) {
+	b, err = decodeValueTypeAssert(b, TimeTZ)
+	if err != nil {
+		return b, timetz.TimeTZ{}, err
+	}
+	return DecodeUntaggedTimeTZValue(b)
+}
+
+// DecodeUntaggedTimeTZValue decodes a value encoded by
+// EncodeUntaggedTimeTZValue.
+func DecodeUntaggedTimeTZValue(b []byte) (remaining []byte, t timetz.TimeTZ, err error) {
+	var micros, offsetSecs int64
+	b, _, micros, err = DecodeNonsortingStdlibVarint(b)
+	if err != nil {
+		return b, timetz.TimeTZ{}, err
+	}
+	b, _, offsetSecs, err = DecodeNonsortingStdlibVarint(b)
+	if err != nil {
+		return b, timetz.TimeTZ{}, err
+	}
+	return b, timetz.MakeTimeTZ(timeofday.TimeOfDay(micros), int32(offsetSecs)), nil
+}
+
 // DecodeDecimalValue decodes a value encoded by EncodeDecimalValue.
 func DecodeDecimalValue(b []byte) (remaining []byte, d apd.Decimal, err error) {
 	b, err = decodeValueTypeAssert(b, Decimal)
@@ -2264,7 +2389,7 @@ func PeekValueLengthWithOffsetsAndType(b []byte, dataOffset int, typ Type) (leng
 	case Decimal:
 		_, n, i, err := DecodeNonsortingStdlibUvarint(b)
 		return dataOffset + n + int(i), err
-	case Time:
+	case Time, TimeTZ:
 		n, err := getMultiNonsortingVarintLen(b, 2)
 		return dataOffset + n, err
 	case Duration:
@@ -2355,6 +2480,13 @@ func PrettyPrintValueEncoded(b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, t.UTC().Format(time.RFC3339Nano), nil
+	case TimeTZ:
+		var t timetz.TimeTZ
+		b, t, err = DecodeTimeTZValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, t.String(), nil
 	case Duration:
 		var d duration.Duration
 		b, d, err = DecodeDurationValue(b)
@@ -0,0 +1,178 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package timetz
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+)
+
+const (
+	// MaxOffsetSecs is the largest time zone offset, in seconds, that a TimeTZ
+	// may have. Like Postgres, offsets of up to 15:59:59 on either side of UTC
+	// are allowed.
+	MaxOffsetSecs = int32(15*60*60 + 59*60 + 59)
+	// MinOffsetSecs is the smallest time zone offset that a TimeTZ may have.
+	MinOffsetSecs = -MaxOffsetSecs
+
+	microsecondsPerSecond = 1e6
+)
+
+var (
+	// MinTimeTZ is the lowest TimeTZ value: midnight at the time zone furthest
+	// east of UTC.
+	MinTimeTZ = MakeTimeTZ(timeofday.Min, MinOffsetSecs)
+	// MaxTimeTZ is the highest TimeTZ value: 24:00 at the time zone furthest
+	// west of UTC.
+	MaxTimeTZ = MakeTimeTZ(timeofday.Time2400, MaxOffsetSecs)
+)
+
+// TimeTZ is a time of day along with the offset from UTC of the time zone in
+// which it was specified, mirroring the Postgres TIMETZ type.
+//
+// As in Postgres, OffsetSecs is the number of seconds *west* of UTC, which is
+// the negation of the offset returned by time.Time.Zone. For example,
+// 12:00:00-08 has an OffsetSecs of 28800, and the same instant in UTC is
+// TimeOfDay + OffsetSecs, that is 20:00:00.
+type TimeTZ struct {
+	timeofday.TimeOfDay
+	OffsetSecs int32
+}
+
+// MakeTimeTZ creates a TimeTZ from a time of day and an offset in seconds west
+// of UTC.
+func MakeTimeTZ(t timeofday.TimeOfDay, offsetSecs int32) TimeTZ {
+	return TimeTZ{TimeOfDay: t, OffsetSecs: offsetSecs}
+}
+
+// MakeTimeTZFromTime creates a TimeTZ from the clock time and time zone offset
+// of a time.Time, ignoring its date.
+func MakeTimeTZFromTime(t time.Time) TimeTZ {
+	_, offset := t.Zone()
+	return MakeTimeTZ(timeofday.FromTime(t), -int32(offset))
+}
+
+// Random generates a random TimeTZ.
+func Random(rng *rand.Rand) TimeTZ {
+	offset := rng.Int31n(2*MaxOffsetSecs+1) + MinOffsetSecs
+	return MakeTimeTZ(timeofday.Random(rng), offset)
+}
+
+// timeTZStartsWithTimeRegex matches inputs that start with a time of day
+// rather than a date or a special value like "now".
+var timeTZStartsWithTimeRegex = regexp.MustCompile(`^\d+:`)
+
+// timeTZ2400Regex matches inputs that start with the special 24:00 time, which
+// the date parser would otherwise turn into midnight of the next day.
+var timeTZ2400Regex = regexp.MustCompile(`^24:00(:00(\.0+)?)?`)
+
+// ParseTimeTZ parses a time of day with an optional time zone. Inputs that do
+// not specify a time zone, or that specify one by name, use the offset in
+// effect in that time zone on the date of now (which also supplies the default
+// time zone). The input may include a date, which is then used to resolve
+// named time zones instead.
+func ParseTimeTZ(now time.Time, s string) (TimeTZ, error) {
+	s = strings.TrimSpace(s)
+	is2400 := false
+	if loc := timeTZ2400Regex.FindStringIndex(s); loc != nil {
+		is2400 = true
+		s = "00:00:00" + s[loc[1]:]
+	}
+	// The offset of a named time zone depends on the date, so parse the input
+	// on the current date unless it has a date of its own.
+	if timeTZStartsWithTimeRegex.MatchString(s) {
+		s = now.Format("2006-01-02 ") + s
+	}
+	t, err := pgdate.ParseTime(now, 0 /* mode */, s)
+	if err != nil {
+		return TimeTZ{}, err
+	}
+	ret := MakeTimeTZFromTime(t)
+	if is2400 {
+		ret.TimeOfDay = timeofday.Time2400
+	}
+	if ret.OffsetSecs < MinOffsetSecs || ret.OffsetSecs > MaxOffsetSecs {
+		return TimeTZ{}, fmt.Errorf("time zone displacement out of range: %q", s)
+	}
+	return ret, nil
+}
+
+// UTCMicros returns the time of day in UTC, in microseconds. Unlike a
+// TimeOfDay, the result is not wrapped to a single day, so it can be negative
+// or larger than a day.
+func (t TimeTZ) UTCMicros() int64 {
+	return int64(t.TimeOfDay) + int64(t.OffsetSecs)*microsecondsPerSecond
+}
+
+// ToTime converts a TimeTZ to a time.Time in a fixed time zone with the
+// offset of t, using the Unix epoch (in UTC) as the date.
+func (t TimeTZ) ToTime() time.Time {
+	loc := time.FixedZone("", -int(t.OffsetSecs))
+	return time.Unix(0, t.UTCMicros()*1000).In(loc)
+}
+
+// WithOffset returns the TimeTZ for the same instant as t in the time zone
+// with the given offset in seconds west of UTC.
+func (t TimeTZ) WithOffset(offsetSecs int32) TimeTZ {
+	return MakeTimeTZ(
+		timeofday.FromInt(t.UTCMicros()-int64(offsetSecs)*microsecondsPerSecond),
+		offsetSecs,
+	)
+}
+
+// Compare returns -1, 0 or 1 depending on whether t is before, equal to or
+// after other. Values are ordered by their UTC time first and then by offset,
+// so that two values are only equal if both their times and offsets are.
+func (t TimeTZ) Compare(other TimeTZ) int {
+	tMicros, otherMicros := t.UTCMicros(), other.UTCMicros()
+	switch {
+	case tMicros < otherMicros:
+		return -1
+	case tMicros > otherMicros:
+		return 1
+	case t.OffsetSecs < other.OffsetSecs:
+		return -1
+	case t.OffsetSecs > other.OffsetSecs:
+		return 1
+	}
+	return 0
+}
+
+// String formats the TimeTZ like Postgres does, e.g. 12:00:00-08 or
+// 01:02:03.456+05:30.
+func (t TimeTZ) String() string {
+	var buf strings.Builder
+	buf.WriteString(t.TimeOfDay.String())
+	offset := -t.OffsetSecs
+	if offset < 0 {
+		buf.WriteByte('-')
+		offset = -offset
+	} else {
+		buf.WriteByte('+')
+	}
+	hours, mins, secs := offset/3600, (offset/60)%60, offset%60
+	fmt.Fprintf(&buf, "%02d", hours)
+	if mins != 0 || secs != 0 {
+		fmt.Fprintf(&buf, ":%02d", mins)
+	}
+	if secs != 0 {
+		fmt.Fprintf(&buf, ":%02d", secs)
+	}
+	return buf.String()
+}
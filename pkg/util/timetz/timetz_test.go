@@ -0,0 +1,118 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License included
+// in the file licenses/BSL.txt and at www.mariadb.com/bsl11.
+//
+// Change Date: 2022-10-01
+//
+// On the date above, in accordance with the Business Source License, use
+// of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt and at
+// https://www.apache.org/licenses/LICENSE-2.0
+
+package timetz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+)
+
+func TestParseTimeTZ(t *testing.T) {
+	// A summer date, so that named time zones use their daylight saving offset.
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	nyc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		now      time.Time
+		input    string
+		expected TimeTZ
+		str      string
+	}{
+		{now, "12:00:00+00", MakeTimeTZ(timeofday.New(12, 0, 0, 0), 0), "12:00:00+00"},
+		{now, "12:00:00-08", MakeTimeTZ(timeofday.New(12, 0, 0, 0), 8*60*60), "12:00:00-08"},
+		{now, "01:02:03.456+05:30", MakeTimeTZ(timeofday.New(1, 2, 3, 456000), -(5*60*60 + 30*60)), "01:02:03.456+05:30"},
+		{now, "11:00", MakeTimeTZ(timeofday.New(11, 0, 0, 0), 0), "11:00:00+00"},
+		{now.In(nyc), "11:00", MakeTimeTZ(timeofday.New(11, 0, 0, 0), 4*60*60), "11:00:00-04"},
+		{now, "11:00 America/New_York", MakeTimeTZ(timeofday.New(11, 0, 0, 0), 4*60*60), "11:00:00-04"},
+		{now, "2019-01-01 11:00 America/New_York", MakeTimeTZ(timeofday.New(11, 0, 0, 0), 5*60*60), "11:00:00-05"},
+		{now, "24:00+03", MakeTimeTZ(timeofday.Time2400, -3*60*60), "24:00:00+03"},
+		{now, "24:00:00.000-01", MakeTimeTZ(timeofday.Time2400, 60*60), "24:00:00-01"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := ParseTimeTZ(tc.now, tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, actual)
+			}
+			if s := actual.String(); s != tc.str {
+				t.Errorf("expected %s, got %s", tc.str, s)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "abc", "2019-01-01", "25:00+00", "12:00+16"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseTimeTZ(now, input); err == nil {
+				t.Errorf("expected error parsing %q", input)
+			}
+		})
+	}
+}
+
+func TestTimeTZCompare(t *testing.T) {
+	// Each value is strictly greater than the previous one.
+	ordered := []TimeTZ{
+		MinTimeTZ,
+		MakeTimeTZ(timeofday.New(1, 0, 0, 0), 0),
+		// Same UTC time as the previous value, but further west.
+		MakeTimeTZ(timeofday.New(0, 0, 0, 0), 60*60),
+		MakeTimeTZ(timeofday.New(23, 0, 0, 0), -60*60),
+		MakeTimeTZ(timeofday.New(22, 0, 0, 0), 0),
+		MakeTimeTZ(timeofday.New(23, 0, 0, 0), 60*60),
+		MaxTimeTZ,
+	}
+	for i := range ordered {
+		if c := ordered[i].Compare(ordered[i]); c != 0 {
+			t.Errorf("expected %s = %s, got %d", ordered[i], ordered[i], c)
+		}
+		if i == 0 {
+			continue
+		}
+		if c := ordered[i-1].Compare(ordered[i]); c != -1 {
+			t.Errorf("expected %s < %s, got %d", ordered[i-1], ordered[i], c)
+		}
+		if c := ordered[i].Compare(ordered[i-1]); c != 1 {
+			t.Errorf("expected %s > %s, got %d", ordered[i], ordered[i-1], c)
+		}
+	}
+}
+
+func TestTimeTZWithOffset(t *testing.T) {
+	testCases := []struct {
+		t        TimeTZ
+		offset   int32
+		expected string
+	}{
+		{MakeTimeTZ(timeofday.New(12, 0, 0, 0), 0), 8 * 60 * 60, "04:00:00-08"},
+		{MakeTimeTZ(timeofday.New(1, 0, 0, 0), 0), 3 * 60 * 60, "22:00:00-03"},
+		{MakeTimeTZ(timeofday.New(20, 0, 0, 0), 60*60), -5 * 60 * 60, "02:00:00+05"},
+	}
+	for _, tc := range testCases {
+		actual := tc.t.WithOffset(tc.offset)
+		if s := actual.String(); s != tc.expected {
+			t.Errorf("%s with offset %d: expected %s, got %s", tc.t, tc.offset, tc.expected, s)
+		}
+		// The results are wrapped to a single day, so they may be a day apart.
+		if d := tc.t.UTCMicros() - actual.UTCMicros(); d%(24*60*60*1e6) != 0 {
+			t.Errorf("%s and %s should be the same time of day in UTC", tc.t, actual)
+		}
+	}
+}
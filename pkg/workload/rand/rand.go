@@ -311,7 +311,7 @@ func DatumToGoSQL(d tree.Datum) (interface{}, error) {
 		return string(*d), nil
 	case *tree.DBytes:
 		return string(*d), nil
-	case *tree.DDate, *tree.DTime:
+	case *tree.DDate, *tree.DTime, *tree.DTimeTZ:
 		return tree.AsStringWithFlags(d, tree.FmtBareStrings), nil
 	case *tree.DTimestamp:
 		return d.Time, nil
@@ -346,6 +346,8 @@ func DatumToGoSQL(d tree.Datum) (interface{}, error) {
 		return d.UUID, nil
 	case *tree.DIPAddr:
 		return d.IPAddr.String(), nil
+	case *tree.DGeometry, *tree.DGeography:
+		return tree.AsStringWithFlags(d, tree.FmtBareStrings), nil
 	}
 	return nil, errors.Errorf("unhandled datum type: %s", reflect.TypeOf(d))
 }